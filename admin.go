@@ -0,0 +1,248 @@
+package disgm
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/store"
+)
+
+const (
+	defaultImpersonationTTL = 15 * time.Minute // Lifetime of impersonation tokens that set none.
+	maxImpersonationTTL     = time.Hour        // Longest lifetime of an impersonation token.
+)
+
+// AdminGuild is a guild of the bot, as listed for admin tokens.
+type AdminGuild struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	MemberCount int    `json:"member_count"`
+	HasToken    bool   `json:"has_token"`    // Whether the token store has a token for the guild
+	Clients     int    `json:"clients"`      // Connected WebSocket clients
+	SlowClients int    `json:"slow_clients"` // Connected WebSocket clients whose writes are slow
+	EventsOut   int    `json:"events_out"`   // Events sent to the connected clients
+	MessagesIn  int    `json:"messages_in"`  // Messages received from the connected clients
+}
+
+// ImpersonationParams are the parameters of an impersonation token.
+type ImpersonationParams struct {
+	ActingUser string       `json:"acting_user"`           // Support agent using the token, recorded in audit-log reasons and the API audit log
+	Scope      *store.Scope `json:"scope,omitempty"`       // Channels the token is restricted to, unrestricted if unset
+	TTLSeconds int          `json:"ttl_seconds,omitempty"` // Lifetime of the token, up to 3600, defaults to 900
+}
+
+// Impersonation is a short-lived token for a guild, minted by an admin token for a support session.
+type Impersonation struct {
+	Token      string       `json:"token"`
+	GuildID    string       `json:"guild_id"`
+	ActingUser string       `json:"acting_user"`
+	Scope      *store.Scope `json:"scope,omitempty"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+}
+
+// The admin tokens and the token store, set from the options.
+var admin = struct {
+	sync.Mutex
+	tokens []string
+	store  store.TokenStore
+}{}
+
+// The impersonation tokens, keyed by token. Expired tokens are dropped when tokens are looked up.
+var impersonations = struct {
+	sync.Mutex
+	byToken map[string]*Impersonation
+}{byToken: make(map[string]*Impersonation)}
+
+// impersonation returns the impersonation token with the given value if it has not expired.
+func impersonation(token string) (*Impersonation, bool) {
+	impersonations.Lock()
+	defer impersonations.Unlock()
+
+	now := time.Now()
+	for t, imp := range impersonations.byToken {
+		if now.After(imp.ExpiresAt) {
+			delete(impersonations.byToken, t)
+		}
+	}
+	imp, ok := impersonations.byToken[token]
+	return imp, ok
+}
+
+// isAdminToken reports whether a token is one of the admin tokens of the options.
+func isAdminToken(token string) bool {
+	admin.Lock()
+	defer admin.Unlock()
+	return slices.Contains(admin.tokens, token)
+}
+
+// adminPath reports whether a request is for the admin routes, which only admin tokens may use.
+// Routes are matched case-insensitively, so the prefix is too.
+func adminPath(c *fiber.Ctx) bool {
+	const prefix = "/api/admin/"
+	path := c.Path()
+	return len(path) >= len(prefix) && strings.EqualFold(path[:len(prefix)], prefix)
+}
+
+// isAdmin reports whether a request was authenticated with an admin token. Admin handlers check
+// it themselves, so they do not rely on the token middleware recognizing their path.
+func isAdmin(c *fiber.Ctx) bool {
+	admin, _ := c.Locals("Admin").(bool)
+	return admin
+}
+
+// adminTokenRequired responds with HTTP status 403 (Forbidden) for requests without an admin token.
+func adminTokenRequired(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).SendString("Forbidden: this endpoint requires an admin token")
+}
+
+// GetAdminGuilds lists all guilds the bot is in with their WebSocket connection stats.
+//
+// Requires an admin token, see the AdminTokens option.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the guilds as a JSON array, ordered by ID.
+//   - On failure, it returns an HTTP status 403 (Forbidden) without an admin token,
+//     or HTTP status 500 (Internal Server Error) if the token store cannot be read.
+// @Summary		Get Admin Guilds
+// @Description	List all guilds of the bot with member counts and WebSocket connection stats.
+// @Tags			Admin
+// @Success		200	{array}		AdminGuild
+// @Failure		403	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/admin/guilds [get]
+func GetAdminGuilds(c *fiber.Ctx, s *discordgo.Session) error {
+	if !isAdmin(c) {
+		return adminTokenRequired(c)
+	}
+
+	admin.Lock()
+	tokens := admin.store
+	admin.Unlock()
+
+	var stored map[string]string
+	if tokens != nil {
+		var err error
+		if stored, err = tokens.Load(); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to load tokens: " + err.Error())
+		}
+	}
+
+	s.State.RLock()
+	guilds := make([]AdminGuild, 0, len(s.State.Guilds))
+	byID := make(map[string]*AdminGuild, len(s.State.Guilds))
+	for _, g := range s.State.Guilds {
+		guilds = append(guilds, AdminGuild{ID: g.ID, Name: g.Name, MemberCount: g.MemberCount, HasToken: stored[g.ID] != ""})
+	}
+	s.State.RUnlock()
+
+	slices.SortFunc(guilds, func(a, b AdminGuild) int {
+		switch {
+		case snowflakeLess(a.ID, b.ID):
+			return -1
+		case snowflakeLess(b.ID, a.ID):
+			return 1
+		}
+		return 0
+	})
+	for i := range guilds {
+		byID[guilds[i].ID] = &guilds[i]
+	}
+
+	clientsMu.Lock()
+	for conn, guildID := range clients {
+		g, ok := byID[guildID]
+		if !ok {
+			continue
+		}
+		info := clientInfo[conn]
+		g.Clients++
+		g.EventsOut += info.EventsOut
+		g.MessagesIn += info.MessagesIn
+		if info.Slow {
+			g.SlowClients++
+		}
+	}
+	clientsMu.Unlock()
+
+	return c.JSON(guilds)
+}
+
+// ImpersonateGuild mints a short-lived token for a guild, for support sessions.
+//
+// The token acts like a guild token, optionally restricted to channels, and expires after its
+// TTL; WebSocket connections opened with it are closed once it expires. The support agent is
+// recorded in the audit-log reasons of the changes made with it. Requires an admin token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - id: The ID of the guild.
+//
+// Request Body:
+//   - An ImpersonationParams object; acting_user is required.
+//
+// Returns:
+//   - On success, it returns the impersonation token as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) without an admin token,
+//     HTTP status 404 (Not Found) if the bot is not in the guild,
+//     or HTTP status 500 (Internal Server Error) if no token can be generated.
+// @Summary		Impersonate Guild
+// @Description	Mint a short-lived, optionally scoped token for a guild for a support session.
+// @Tags			Admin
+// @Param			id		path		string				true	"Guild ID"
+// @Param			body	body		ImpersonationParams	true	"Impersonation parameters"
+// @Success		201		{object}	Impersonation
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/admin/guilds/{id}/impersonate [post]
+func ImpersonateGuild(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Params("id")
+
+	if !isAdmin(c) {
+		return adminTokenRequired(c)
+	}
+
+	var params ImpersonationParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if strings.TrimSpace(params.ActingUser) == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: acting_user is required")
+	}
+	ttl := defaultImpersonationTTL
+	if params.TTLSeconds != 0 {
+		ttl = time.Duration(params.TTLSeconds) * time.Second
+		if ttl < 0 || ttl > maxImpersonationTTL {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: ttl_seconds must be between 1 and 3600")
+		}
+	}
+
+	if _, err := s.State.Guild(guildID); err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Guild not found")
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to generate token: " + err.Error())
+	}
+
+	imp := &Impersonation{Token: token, GuildID: guildID, ActingUser: params.ActingUser, Scope: params.Scope, ExpiresAt: time.Now().Add(ttl)}
+	impersonations.Lock()
+	impersonations.byToken[token] = imp
+	impersonations.Unlock()
+
+	return c.Status(fiber.StatusCreated).JSON(imp)
+}
@@ -0,0 +1,218 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxAPIAuditEntries is the number of API audit log entries kept per guild.
+const maxAPIAuditEntries = 10000
+
+// APIAuditEntry is a change made through the API, as recorded in the API audit log.
+type APIAuditEntry struct {
+	ID         string    `json:"id"`                    // Sortable ID of the entry within its guild
+	Time       time.Time `json:"time"`                  // When the request was answered
+	Method     string    `json:"method"`                // HTTP method of the request
+	Route      string    `json:"route"`                 // Route of the request, e.g. /api/guild/roles/:roleid
+	Path       string    `json:"path"`                  // Path of the request
+	Status     int       `json:"status"`                // HTTP status of the response
+	Credential string    `json:"credential"`            // Credential of the request: guild, token:<id> for issued tokens, or impersonation:<hash>
+	ActingUser string    `json:"acting_user,omitempty"` // Dashboard user the token acts for, if any
+	Reason     string    `json:"reason,omitempty"`      // Audit-log reason sent with the request, if any
+}
+
+// The API audit log: every request changing a guild is recorded in the key-value store, keyed by
+// "<guild>/<id>" so keys sort by time. Guilds keep at most maxAPIAuditEntries entries.
+var apiAudit = struct {
+	sync.Mutex
+	storage *Storage
+	counts  map[string]int
+	seq     uint64
+}{counts: make(map[string]int)}
+
+// loadAPIAudit counts the entries already in the storage, so pruning continues after a restart.
+func loadAPIAudit(storage *Storage) {
+	apiAudit.Lock()
+	defer apiAudit.Unlock()
+
+	apiAudit.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load API audit log: %v", err)
+		return
+	}
+	for _, key := range keys {
+		guildID, _, _ := strings.Cut(key, "/")
+		apiAudit.counts[guildID]++
+	}
+}
+
+// APIAuditMiddleware records the requests that change a guild in the API audit log, with the
+// credential and acting user that made them. Reads are not recorded.
+func APIAuditMiddleware(c *fiber.Ctx) error {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return c.Next()
+	}
+	guildID, ok := c.Locals("ID").(string)
+	if !ok {
+		return c.Next()
+	}
+
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+	}
+	entry := APIAuditEntry{
+		Time:   time.Now().UTC(),
+		Method: c.Method(),
+		Route:  c.Route().Path,
+		Path:   c.Path(),
+		Status: status,
+		Reason: c.Get(AuditReasonHeader),
+	}
+	entry.Credential, _ = c.Locals("Credential").(string)
+	entry.ActingUser, _ = c.Locals("ActingUser").(string)
+	recordAPIAudit(guildID, entry)
+
+	return err
+}
+
+// recordAPIAudit appends an entry to the API audit log of a guild.
+func recordAPIAudit(guildID string, entry APIAuditEntry) {
+	apiAudit.Lock()
+	defer apiAudit.Unlock()
+
+	if apiAudit.storage == nil {
+		return
+	}
+
+	apiAudit.seq++
+	entry.ID = fmt.Sprintf("%020d-%06d", entry.Time.UnixNano(), apiAudit.seq%1000000)
+
+	value, err := json.Marshal(entry)
+	if err == nil {
+		err = apiAudit.storage.Set(guildID+"/"+entry.ID, value)
+	}
+	if err != nil {
+		log.Printf("Failed to record API audit entry: %v", err)
+		return
+	}
+
+	// Prunes in batches, so the keys are not listed on every request.
+	apiAudit.counts[guildID]++
+	if apiAudit.counts[guildID] > maxAPIAuditEntries+maxAPIAuditEntries/10 {
+		pruneAPIAudit(guildID)
+	}
+}
+
+// pruneAPIAudit drops the oldest entries of a guild beyond maxAPIAuditEntries.
+// The caller must hold the apiAudit lock.
+func pruneAPIAudit(guildID string) {
+	err := apiAudit.storage.Update(func(tx *StorageTx) error {
+		keys, err := tx.List(guildID + "/")
+		if err != nil {
+			return err
+		}
+
+		drop := max(0, len(keys)-maxAPIAuditEntries)
+		for _, key := range keys[:drop] {
+			if err := tx.Delete(key); err != nil {
+				return err
+			}
+		}
+		apiAudit.counts[guildID] = len(keys) - drop
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to prune API audit log: %v", err)
+	}
+}
+
+// GetAPIAudit retrieves the latest changes made to the guild through the API, newest first.
+//
+// Each entry names the credential and the acting user of the request, so guild owners can tell
+// which dashboard user performed an action even when it failed or Discord keeps no audit-log
+// entry for it. Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - acting_user: Optional ID of the acting user whose changes are listed.
+//   - limit: Optional number of entries, 1 to 1000, defaults to 100; both can be changed with the Limits option.
+//
+// Returns:
+//   - On success, it returns the entries as a JSON array.
+//   - On failure, it returns an HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 500 (Internal Server Error) if the log cannot be read.
+// @Summary		Get API Audit Log
+// @Description	Retrieve the latest changes made to the guild through the API with their credential and acting user, newest first.
+// @Tags			Guild
+// @Param			acting_user	query		string	false	"Acting user ID"
+// @Param			limit		query		int		false	"Number of entries"	minimum(1)	maximum(1000)	default(100)
+// @Success		200			{array}		APIAuditEntry
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/audit [get]
+func GetAPIAudit(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	limit := queryLimit(c, LimitAPIAudit)
+	user := c.Query("acting_user")
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	apiAudit.Lock()
+	storage := apiAudit.storage
+	apiAudit.Unlock()
+
+	entries := []APIAuditEntry{}
+	if storage == nil {
+		return c.JSON(entries)
+	}
+
+	err := storage.View(func(tx *StorageTx) error {
+		keys, err := tx.List(guildID + "/")
+		if err != nil {
+			return err
+		}
+		slices.Reverse(keys)
+
+		for _, key := range keys {
+			if len(entries) == limit {
+				break
+			}
+			value, ok, err := tx.Get(key)
+			if err != nil {
+				return err
+			}
+			var entry APIAuditEntry
+			if ok && json.Unmarshal(value, &entry) == nil && (user == "" || entry.ActingUser == user) {
+				entries = append(entries, entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read API audit log: " + err.Error())
+	}
+
+	return c.JSON(entries)
+}
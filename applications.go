@@ -1,6 +1,8 @@
 package disgm
 
 import (
+	"encoding/json"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rif223/disgm/models"
@@ -121,6 +123,60 @@ func CreateGuildApplicationCommand(c *fiber.Ctx, s *discordgo.Session) error {
 	return c.JSON(cmd)
 }
 
+// UpdateGuildApplicationCommand updates an application command of a specific guild.
+//
+// This function edits an existing guild command in place, so its ID and the permissions set
+// for it in the guild are kept, unlike deleting and recreating it. Only the fields set in the
+// request body are changed; options, if set, replace the current ones.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - cmdid: The ID of the application command to update.
+//
+// Request Context:
+//   - ID: The guild ID is stored in the Fiber context under the key "ID".
+//
+// Request Body:
+//   - The request body should contain the changed application command fields in JSON format.
+//
+// Returns:
+//   - On success, it returns the updated application command as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body is invalid,
+//     or an HTTP status 500 (Internal Server Error) if the command cannot be updated.
+// @Summary		Update Guild Application Command
+// @Description	Update a guild application command by ID, keeping its permissions.
+// @Tags			Commands
+// @Param			cmdid	path		string						true	"Command ID"
+// @Param			body	body		models.ApplicationCommand	true	"Changed command fields"
+// @Success		200		{object}	models.ApplicationCommand
+// @Failure		400		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/commands/{cmdid} [patch]
+func UpdateGuildApplicationCommand(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	user, _ := s.User("@me") // Retrieves the bot's application user
+	cmdID := c.Params("cmdid")
+
+	// The fields are sent as they are: ApplicationCommandEdit would send an empty name and null
+	// options for fields left out of the body.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &fields); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	var cmd models.ApplicationCommand
+	endpoint := discordgo.EndpointApplicationGuildCommand(user.ID, guildID, cmdID)
+	err := commandRequest(s, "PATCH", endpoint, discordgo.EndpointApplicationGuildCommands(user.ID, guildID), fields, &cmd)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update cmd: " + err.Error())
+	}
+
+	return c.JSON(cmd)
+}
+
 // DeleteGuildApplicationCommand deletes a specific application command from a guild.
 //
 // This function removes an existing application command from a guild, using the guild ID
@@ -158,3 +214,168 @@ func DeleteGuildApplicationCommand(c *fiber.Ctx, s *discordgo.Session) error {
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// commandRequest sends a request to an application commands endpoint and decodes the response
+// into v, if set. discordgo drops the integration types and contexts of commands, which
+// user-installed apps depend on, so the global commands call the Discord API directly.
+func commandRequest(s *discordgo.Session, method, endpoint, bucket string, body interface{}, v interface{}) error {
+	resp, err := s.RequestWithBucketID(method, endpoint, body, bucket)
+	if err != nil || v == nil {
+		return err
+	}
+	return json.Unmarshal(resp, v)
+}
+
+// GetGlobalApplicationCommands retrieves all global application commands of the bot.
+//
+// Global commands are available in every guild the app is installed in and, depending on their
+// integration types and contexts, in the DMs of the users who installed the app.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns a JSON list of application commands.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) with an error message.
+// @Summary		Get Global Application Commands
+// @Description	Retrieve all global application commands, including their integration types and contexts.
+// @Tags			Commands
+// @Success		200	{array}		ApplicationCommandArray
+// @Failure		500	{object}	error
+// @Router			/api/commands [get]
+func GetGlobalApplicationCommands(c *fiber.Ctx, s *discordgo.Session) error {
+	user, _ := s.User("@me") // Retrieves the bot's application user
+
+	endpoint := discordgo.EndpointApplicationGlobalCommands(user.ID)
+	cmds := ApplicationCommandArray{}
+	if err := commandRequest(s, "GET", endpoint, endpoint, nil, &cmds); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve cmds: " + err.Error())
+	}
+
+	return c.JSON(cmds)
+}
+
+// GetGlobalApplicationCommand retrieves a specific global application command of the bot.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - cmdid: The ID of the application command to retrieve.
+//
+// Returns:
+//   - On success, it returns the application command details as JSON.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) with an error message.
+// @Summary		Get Global Application Command
+// @Description	Retrieve a specific global application command by ID.
+// @Tags			Commands
+// @Param			cmdid	path		string	true	"Command ID"
+// @Success		200		{object}	models.ApplicationCommand
+// @Failure		500		{object}	error
+// @Router			/api/commands/{cmdid} [get]
+func GetGlobalApplicationCommand(c *fiber.Ctx, s *discordgo.Session) error {
+	user, _ := s.User("@me") // Retrieves the bot's application user
+
+	var cmd models.ApplicationCommand
+	endpoint := discordgo.EndpointApplicationGlobalCommand(user.ID, c.Params("cmdid"))
+	err := commandRequest(s, "GET", endpoint, discordgo.EndpointApplicationGlobalCommands(user.ID), nil, &cmd)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve cmd: " + err.Error())
+	}
+
+	return c.JSON(cmd)
+}
+
+// CreateGlobalApplicationCommand registers a new global application command of the bot.
+//
+// Unlike guild commands, global commands can set integration_types, to be available when the
+// app is installed to a user (1) rather than a guild (0), and contexts, to be usable in DMs
+// with the bot (1) and private channels (2) besides guilds (0). Creating a command with an
+// existing name replaces it. Requires an unrestricted token, as the command is visible in every
+// guild of the bot.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - The request body should contain the application command data in JSON format.
+//
+// Returns:
+//   - On success, it returns the newly created application command as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body is invalid,
+//     HTTP status 403 (Forbidden) for restricted tokens,
+//     or an HTTP status 500 (Internal Server Error) if command creation fails.
+// @Summary		Create Global Application Command
+// @Description	Create a new global application command, e.g. for user-installed apps.
+// @Tags			Commands
+// @Param			body	body		models.ApplicationCommand	true	"Application command"
+// @Success		201		{object}	models.ApplicationCommand
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/commands [post]
+func CreateGlobalApplicationCommand(c *fiber.Ctx, s *discordgo.Session) error {
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var ac models.ApplicationCommand
+	if err := c.BodyParser(&ac); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if ac.Name == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name is required")
+	}
+
+	user, _ := s.User("@me") // Retrieves the bot's application user
+
+	var cmd models.ApplicationCommand
+	endpoint := discordgo.EndpointApplicationGlobalCommands(user.ID)
+	if err := commandRequest(s, "POST", endpoint, endpoint, ac, &cmd); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create cmd: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(cmd)
+}
+
+// DeleteGlobalApplicationCommand deletes a specific global application command of the bot.
+//
+// Requires an unrestricted token, as the command is removed from every guild of the bot.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - cmdid: The ID of the application command to delete.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 500 (Internal Server Error) with an error message.
+// @Summary		Delete Global Application Command
+// @Description	Delete a global application command by ID.
+// @Tags			Commands
+// @Param			cmdid	path	string	true	"Command ID"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/commands/{cmdid} [delete]
+func DeleteGlobalApplicationCommand(c *fiber.Ctx, s *discordgo.Session) error {
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	user, _ := s.User("@me") // Retrieves the bot's application user
+
+	endpoint := discordgo.EndpointApplicationGlobalCommand(user.ID, c.Params("cmdid"))
+	err := commandRequest(s, "DELETE", endpoint, discordgo.EndpointApplicationGlobalCommands(user.ID), nil, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete cmd: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
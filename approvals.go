@@ -0,0 +1,275 @@
+package disgm
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Actions that can be placed under the two-person approval policy.
+const (
+	ActionBulkBan       = "bulk_ban"
+	ActionChannelDelete = "channel_delete"
+	ActionRoleDelete    = "role_delete"
+)
+
+// Approval states reported in approval records and WebSocket events.
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+	ApprovalFailed   = "failed"
+)
+
+// Approval is a destructive action waiting for a second token to approve or reject it.
+type Approval struct {
+	ID          string    `json:"id"`                     // Unique ID of the approval
+	Action      string    `json:"action"`                 // Action that will be executed, e.g. "channel_delete"
+	Target      []string  `json:"target"`                 // IDs of the channels, roles or users affected
+	RequestedBy string    `json:"requested_by,omitempty"` // Acting user that requested the action, for display only
+	ResolvedBy  string    `json:"resolved_by,omitempty"`  // Acting user that approved or rejected the action, for display only
+	Status      string    `json:"status"`                 // Current state of the approval
+	Error       string    `json:"error,omitempty"`        // Discord error if the approved action failed
+	CreatedAt   time.Time `json:"created_at"`             // When the approval was requested
+	ExpiresAt   time.Time `json:"expires_at"`             // When the approval can no longer be approved
+
+	guildID   string                                         // Guild the action belongs to
+	requester []string                                       // Lineage of the requesting token, see credentialLineage
+	execute   func(options ...discordgo.RequestOption) error // Performs the action once approved
+}
+
+// approvalStore keeps pending approvals in memory.
+type approvalStore struct {
+	mu      sync.Mutex
+	actions []string
+	ttl     time.Duration
+	records map[string]*Approval
+}
+
+// A store of pending approvals, configured by New from the options.
+var approvals = &approvalStore{records: make(map[string]*Approval)}
+
+// required reports whether an action is placed under the approval policy.
+func (a *approvalStore) required(action string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return slices.Contains(a.actions, action)
+}
+
+// pending returns the pending approval with the given ID in a guild, dropping expired records.
+func (a *approvalStore) pending(guildID, id string) (*Approval, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expire()
+
+	approval, ok := a.records[id]
+	if !ok || approval.guildID != guildID {
+		return nil, false
+	}
+	return approval, true
+}
+
+// expire removes approvals whose TTL has passed. The caller must hold the lock.
+func (a *approvalStore) expire() {
+	now := time.Now()
+	for id, approval := range a.records {
+		if now.After(approval.ExpiresAt) {
+			delete(a.records, id)
+		}
+	}
+}
+
+// sameParty reports whether two token lineages may belong to the same person: if either token was
+// issued from the other, directly or through other tokens, its holder controls both. Credentials
+// are the guild token, issued tokens and impersonation tokens; the acting user is left out, as it
+// does not tell the holders of a token apart.
+func sameParty(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 || a[len(a)-1] == "" || b[len(b)-1] == "" {
+		return true
+	}
+	return slices.Contains(a, b[len(b)-1]) || slices.Contains(b, a[len(a)-1])
+}
+
+// requestApproval records a destructive action as pending instead of executing it.
+//
+// The approval is announced to the guild's WebSocket clients with an APPROVAL_CREATE event
+// and must be approved with an unrelated credential before its TTL expires, see sameParty, e.g.
+// with tokens issued for two people with POST /api/guild/tokens.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context of the request that triggered the action.
+//   - action: string – The name of the action, e.g. ActionChannelDelete.
+//   - target: []string – The IDs affected by the action.
+//   - execute: func – Performs the action with the request options of the approving request.
+//
+// Returns:
+//   - It returns HTTP status 202 (Accepted) with the approval record as JSON.
+func requestApproval(c *fiber.Ctx, action string, target []string, execute func(options ...discordgo.RequestOption) error) error {
+	guildID := c.Locals("ID").(string)
+
+	id, err := randomToken(8)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create approval: " + err.Error())
+	}
+
+	user, _ := c.Locals("ActingUser").(string)
+	now := time.Now()
+
+	approvals.mu.Lock()
+	approval := &Approval{
+		ID:          id,
+		Action:      action,
+		Target:      target,
+		RequestedBy: user,
+		Status:      ApprovalPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(approvals.ttl),
+		guildID:     guildID,
+		requester:   credentialLineage(c),
+		execute:     execute,
+	}
+	approvals.records[id] = approval
+	approvals.mu.Unlock()
+
+	EventCall(guildID, "APPROVAL_CREATE", approval)
+
+	return c.Status(fiber.StatusAccepted).JSON(approval)
+}
+
+// GetPendingApprovals retrieves all pending approvals of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Context:
+//   - ID: The guild ID is stored in the Fiber context under the key "ID".
+//
+// Returns:
+//   - It returns the pending approvals as a JSON array.
+// @Summary		Get Pending Approvals
+// @Description	Retrieve all destructive actions waiting for a second approval.
+// @Tags			Approvals
+// @Success		200	{array}	Approval
+// @Router			/api/guild/approvals [get]
+func GetPendingApprovals(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	approvals.mu.Lock()
+	approvals.expire()
+	list := []*Approval{}
+	for _, approval := range approvals.records {
+		if approval.guildID == guildID {
+			list = append(list, approval)
+		}
+	}
+	approvals.mu.Unlock()
+
+	slices.SortFunc(list, func(a, b *Approval) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return c.JSON(list)
+}
+
+// ApproveAction approves and executes a pending destructive action.
+//
+// The approving token must be a different credential than the token that requested the action:
+// the guild token, a token issued for the guild or an impersonation token. Neither may have been
+// issued from the other, directly or through other tokens, as their holder controls both; actions
+// requested with the guild token are approved with an impersonation token. The acting user of a
+// token is not considered, as anyone holding the token could claim another one. The outcome is
+// announced to the guild's WebSocket clients with an APPROVAL_UPDATE event.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - approvalid: The ID of the pending approval.
+//
+// Returns:
+//   - On success, it returns the approved record as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the approval does not exist or expired,
+//     HTTP status 403 (Forbidden) if the requester or a token issued from it tries to approve the action,
+//     or HTTP status 500 (Internal Server Error) if the action fails.
+// @Summary		Approve Action
+// @Description	Approve and execute a pending destructive action.
+// @Tags			Approvals
+// @Param			approvalid	path		string	true	"Approval ID"
+// @Success		200			{object}	Approval
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/approvals/{approvalid}/approve [post]
+func ApproveAction(c *fiber.Ctx, s *discordgo.Session) error {
+	return resolveApproval(c, true)
+}
+
+// RejectAction rejects a pending destructive action without executing it.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - approvalid: The ID of the pending approval.
+//
+// Returns:
+//   - On success, it returns the rejected record as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the approval does not exist or expired.
+// @Summary		Reject Action
+// @Description	Reject a pending destructive action.
+// @Tags			Approvals
+// @Param			approvalid	path		string	true	"Approval ID"
+// @Success		200			{object}	Approval
+// @Failure		404			{object}	error
+// @Router			/api/guild/approvals/{approvalid}/reject [post]
+func RejectAction(c *fiber.Ctx, s *discordgo.Session) error {
+	return resolveApproval(c, false)
+}
+
+// resolveApproval approves or rejects a pending approval and notifies the guild's clients.
+func resolveApproval(c *fiber.Ctx, approve bool) error {
+	guildID := c.Locals("ID").(string)
+
+	approval, ok := approvals.pending(guildID, c.Params("approvalid"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Approval not found or expired")
+	}
+
+	if approve && sameParty(approval.requester, credentialLineage(c)) {
+		return c.Status(fiber.StatusForbidden).SendString("Forbidden: an action must be approved by a token not issued from the requesting one")
+	}
+
+	// Removes the record first, so the action cannot be executed twice.
+	approvals.mu.Lock()
+	if _, ok := approvals.records[approval.ID]; !ok {
+		approvals.mu.Unlock()
+		return c.Status(fiber.StatusNotFound).SendString("Approval not found or expired")
+	}
+	delete(approvals.records, approval.ID)
+	approvals.mu.Unlock()
+
+	approval.ResolvedBy, _ = c.Locals("ActingUser").(string)
+	approval.Status = ApprovalRejected
+
+	var err error
+	if approve {
+		approval.Status = ApprovalApproved
+		if err = approval.execute(auditOptions(c)...); err != nil {
+			approval.Status = ApprovalFailed
+			approval.Error = err.Error()
+		}
+	}
+
+	EventCall(guildID, "APPROVAL_UPDATE", approval)
+
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to execute approved action: " + err.Error())
+	}
+	return c.JSON(approval)
+}
@@ -0,0 +1,89 @@
+package disgm_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rif223/disgm"
+	"github.com/rif223/disgm/disgmtest"
+)
+
+// issueToken issues a token for the guild with the given token and returns it.
+func (ts *testServer) issueToken(t *testing.T, token, name string) string {
+	t.Helper()
+
+	status, body := ts.do(t, http.MethodPost, "/api/guild/tokens", token, `{"name":"`+name+`"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("issuing token %s: status %d: %s", name, status, body)
+	}
+	var issued disgm.GuildToken
+	if err := json.Unmarshal([]byte(body), &issued); err != nil {
+		t.Fatal(err)
+	}
+	return issued.Token
+}
+
+// requestDelete requests the deletion of a channel under the approval policy and returns the approval ID.
+func (ts *testServer) requestDelete(t *testing.T, token, channelID string) string {
+	t.Helper()
+
+	status, body := ts.do(t, http.MethodDelete, "/api/guild/channels/"+channelID, token, "")
+	if status != http.StatusAccepted {
+		t.Fatalf("deleting channel: status %d, want 202: %s", status, body)
+	}
+	var approval disgm.Approval
+	if err := json.Unmarshal([]byte(body), &approval); err != nil {
+		t.Fatal(err)
+	}
+	return approval.ID
+}
+
+// TestApprovalTwoPersonRule checks that an action cannot be approved with a token issued from the
+// requesting token, or with the token the requesting one was issued from.
+func TestApprovalTwoPersonRule(t *testing.T) {
+	var first, second *discordgo.Channel
+	ts := startServer(t, func(b *disgmtest.Backend, guild *discordgo.Guild, opt *disgm.Options) {
+		first = b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "first", Type: discordgo.ChannelTypeGuildText})
+		second = b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "second", Type: discordgo.ChannelTypeGuildText})
+		opt.ApprovalActions = []string{disgm.ActionChannelDelete}
+	})
+
+	alice := ts.issueToken(t, disgmtest.BotToken, "alice")
+	bob := ts.issueToken(t, disgmtest.BotToken, "bob")
+	aliceAgain := ts.issueToken(t, alice, "alice again")
+	aliceThird := ts.issueToken(t, aliceAgain, "alice third")
+
+	approve := func(token, id string) int {
+		status, _ := ts.do(t, http.MethodPost, "/api/guild/approvals/"+id+"/approve", token, "")
+		return status
+	}
+
+	id := ts.requestDelete(t, alice, first.ID)
+	denied := []struct {
+		name  string
+		token string
+	}{
+		{"requester", alice},
+		{"token issued by the requester", aliceAgain},
+		{"token issued through another token of the requester", aliceThird},
+		{"token the requester was issued from", disgmtest.BotToken},
+	}
+	for _, tt := range denied {
+		if status := approve(tt.token, id); status != http.StatusForbidden {
+			t.Errorf("approving with the %s: status %d, want 403", tt.name, status)
+		}
+	}
+	if status := approve(bob, id); status != http.StatusOK {
+		t.Errorf("approving with an unrelated token: status %d, want 200", status)
+	}
+
+	// Every issued token descends from the guild token, so none can approve its actions.
+	id = ts.requestDelete(t, disgmtest.BotToken, second.ID)
+	for _, token := range []string{bob, aliceAgain} {
+		if status := approve(token, id); status != http.StatusForbidden {
+			t.Errorf("approving an action of the guild token with an issued token: status %d, want 403", status)
+		}
+	}
+}
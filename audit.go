@@ -0,0 +1,50 @@
+package disgm
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditReasonHeader is the request header clients may use to supply a Discord audit-log reason.
+const AuditReasonHeader = "X-Audit-Log-Reason"
+
+// auditReason builds the Discord audit-log reason for a request.
+//
+// The reason falls back to the X-Audit-Log-Reason header and is attributed to the acting user
+// of the token, e.g. "spam (via disgm by user 123)", so guild owners can tell which dashboard
+// user performed an action. If the token or its guild has an audit-reason template, the reason
+// is rendered with it instead, e.g. "123 via ACME dashboard: spam".
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context holding the acting user under the key "ActingUser".
+//   - reason: string – The reason supplied in the request body, if any.
+//
+// Returns:
+//   - string: The attributed reason, or an empty string if there is nothing to record.
+func auditReason(c *fiber.Ctx, reason string) string {
+	if reason == "" {
+		reason = c.Get(AuditReasonHeader)
+	}
+
+	user, _ := c.Locals("ActingUser").(string)
+	if template := auditReasonTemplate(c); template != "" {
+		return renderAuditReason(template, c, user, reason)
+	}
+	if user == "" {
+		return reason
+	}
+
+	attribution := "via disgm by user " + user
+	if reason == "" {
+		return attribution
+	}
+	return reason + " (" + attribution + ")"
+}
+
+// auditOptions returns the request options carrying the audit-log reason of a request.
+func auditOptions(c *fiber.Ctx) []discordgo.RequestOption {
+	if reason := auditReason(c, ""); reason != "" {
+		return []discordgo.RequestOption{discordgo.WithAuditLogReason(reason)}
+	}
+	return nil
+}
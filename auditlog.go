@@ -0,0 +1,117 @@
+package disgm
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// auditLogPager pages through the audit log of a guild, newest first.
+var auditLogPager = pager{resource: LimitAuditLog, descending: true, reversible: true}
+
+// AuditLogEntry is an entry of the audit log of a guild.
+type AuditLogEntry struct {
+	ID         string           `json:"id"`                                     // ID of the entry
+	TargetID   *string          `json:"target_id"`                              // ID of the affected entity, e.g. a user, role or channel
+	UserID     *string          `json:"user_id"`                                // ID of the user or app that made the change
+	ActionType int              `json:"action_type"`                            // Type of the action, see the Discord audit log events
+	Changes    []AuditLogChange `json:"changes,omitempty"`                      // Changes made to the target
+	Options    json.RawMessage  `json:"options,omitempty" swaggertype:"object"` // Additional info for some action types
+	Reason     string           `json:"reason,omitempty"`                       // Reason for the change, 1 to 512 characters
+}
+
+// AuditLogChange is a change of an audit log entry.
+type AuditLogChange struct {
+	Key      string          `json:"key"`                                      // Name of the changed field
+	NewValue json.RawMessage `json:"new_value,omitempty" swaggertype:"object"` // New value of the field, if any
+	OldValue json.RawMessage `json:"old_value,omitempty" swaggertype:"object"` // Old value of the field, if any
+}
+
+// GetGuildAuditLog retrieves a page of the audit log of the guild, newest first.
+//
+// The entries can be filtered by the user who made the change and by action type. The users
+// referenced by the entries are added to the user cache. Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - user_id: Optional ID of the user whose changes are listed.
+//   - action_type: Optional type of the listed actions.
+//   - limit: Optional number of entries, 1 to 100, defaults to 50; both can be changed with the Limits option.
+//   - cursor: Optional cursor of the page, from the paging of a previous page.
+//
+// Returns:
+//   - On success, it returns the page of audit log entries as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if a parameter is invalid,
+//     HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 500 (Internal Server Error) if the audit log cannot be retrieved.
+// @Summary		Get Guild Audit Log
+// @Description	Retrieve a page of the audit log of the guild, newest first.
+// @Tags			Guild
+// @Param			user_id		query		string	false	"User ID"
+// @Param			action_type	query		int		false	"Action type"
+// @Param			limit		query		int		false	"Entries per page"	minimum(1)	maximum(100)	default(50)
+// @Param			cursor		query		string	false	"Page cursor"
+// @Success		200			{object}	Page{data=[]AuditLogEntry}
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/audit-logs [get]
+func GetGuildAuditLog(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	req, err := auditLogPager.request(c)
+	if err != nil {
+		return invalidPage(c, err)
+	}
+
+	query := url.Values{"limit": {strconv.Itoa(req.limit)}}
+	if req.before != "" {
+		query.Set("before", req.before)
+	}
+	if req.after != "" {
+		query.Set("after", req.after)
+	}
+	if v := c.Query("user_id"); v != "" {
+		query.Set("user_id", v)
+	}
+	if v := c.Query("action_type"); v != "" {
+		if _, err := strconv.Atoi(v); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid action_type: " + err.Error())
+		}
+		query.Set("action_type", v)
+	}
+
+	endpoint := discordgo.EndpointGuildAuditLogs(guildID)
+	body, err := s.RequestWithBucketID("GET", endpoint+"?"+query.Encode(), nil, endpoint)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve audit log: " + err.Error())
+	}
+	var log struct {
+		Entries []AuditLogEntry   `json:"audit_log_entries"`
+		Users   []*discordgo.User `json:"users"`
+	}
+	if err := json.Unmarshal(body, &log); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve audit log: " + err.Error())
+	}
+
+	cacheUsers(log.Users...)
+
+	if log.Entries == nil {
+		log.Entries = []AuditLogEntry{}
+	}
+	ids := make([]string, len(log.Entries))
+	for i, e := range log.Entries {
+		ids[i] = e.ID
+	}
+	return auditLogPager.send(c, req, log.Entries, ids, nil)
+}
@@ -0,0 +1,202 @@
+package disgm
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+// AutoModRule is an AutoMod rule of a guild.
+type AutoModRule = models.AutoModRule
+
+// GetAutoModRules retrieves the AutoMod rules of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the rules as a JSON array.
+//   - On failure, it returns an HTTP status 500 and an error message if the rules cannot be retrieved.
+// @Summary		Get AutoMod Rules
+// @Description	Retrieve the AutoMod rules of the guild.
+// @Tags			AutoMod
+// @Success		200	{array}		models.AutoModRule
+// @Failure		500	{object}	error
+// @Router			/api/guild/automod/rules [get]
+func GetAutoModRules(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	rules, err := s.AutoModerationRules(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve AutoMod rules: " + err.Error())
+	}
+
+	return c.JSON(rules)
+}
+
+// GetAutoModRule retrieves an AutoMod rule of the guild by its ID.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - ruleid: The ID of the rule to retrieve.
+//
+// Returns:
+//   - On success, it returns the rule as JSON.
+//   - On failure, it returns an HTTP status 500 and an error message if the rule cannot be retrieved.
+// @Summary		Get AutoMod Rule
+// @Description	Retrieve an AutoMod rule of the guild by ID.
+// @Tags			AutoMod
+// @Param			ruleid	path		string	true	"Rule ID"
+// @Success		200		{object}	models.AutoModRule
+// @Failure		500		{object}	error
+// @Router			/api/guild/automod/rules/{ruleid} [get]
+func GetAutoModRule(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	ruleID := c.Params("ruleid")
+
+	rule, err := s.AutoModerationRule(guildID, ruleID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve AutoMod rule: " + err.Error())
+	}
+
+	return c.JSON(rule)
+}
+
+// CreateAutoModRule creates an AutoMod rule in the guild.
+//
+// This function parses the rule from the request body: its name, the event it checks, the
+// trigger with its metadata, e.g. keywords or mention limits, the actions taken and the exempt
+// roles and channels. AutoMod applies to the whole guild, so it requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the created rule as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 500 if the rule cannot be created.
+// @Summary		Create AutoMod Rule
+// @Description	Create a keyword, spam or mention AutoMod rule in the guild.
+// @Tags			AutoMod
+// @Param			body	body		models.AutoModRule	true	"Rule"
+// @Success		201		{object}	models.AutoModRule
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/automod/rules [post]
+func CreateAutoModRule(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var rule discordgo.AutoModerationRule
+	if err := c.BodyParser(&rule); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if rule.Name == "" || rule.EventType == 0 || rule.TriggerType == 0 || len(rule.Actions) == 0 {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name, event_type, trigger_type and actions are required")
+	}
+
+	created, err := s.AutoModerationRuleCreate(guildID, &rule, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create AutoMod rule: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// UpdateAutoModRule edits an AutoMod rule of the guild.
+//
+// Only the fields present in the request body are changed. The trigger type of a rule cannot be
+// changed after creation.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - ruleid: The ID of the rule to edit.
+//
+// Returns:
+//   - On success, it returns the updated rule as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 500 if the rule cannot be updated.
+// @Summary		Update AutoMod Rule
+// @Description	Edit the fields of an AutoMod rule.
+// @Tags			AutoMod
+// @Param			ruleid	path		string				true	"Rule ID"
+// @Param			body	body		models.AutoModRule	true	"Changed fields"
+// @Success		200		{object}	models.AutoModRule
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/automod/rules/{ruleid} [patch]
+func UpdateAutoModRule(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	ruleID := c.Params("ruleid")
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var rule discordgo.AutoModerationRule
+	if err := c.BodyParser(&rule); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if rule.TriggerType != 0 {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: the trigger type of a rule cannot be changed")
+	}
+
+	updated, err := s.AutoModerationRuleEdit(guildID, ruleID, &rule, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update AutoMod rule: " + err.Error())
+	}
+
+	return c.JSON(updated)
+}
+
+// DeleteAutoModRule deletes an AutoMod rule of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - ruleid: The ID of the rule to delete.
+//
+// Returns:
+//   - On success, it returns an HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 500 and an error message if the rule cannot be deleted.
+// @Summary		Delete AutoMod Rule
+// @Description	Delete an AutoMod rule from the guild.
+// @Tags			AutoMod
+// @Param			ruleid	path	string	true	"Rule ID"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/automod/rules/{ruleid} [delete]
+func DeleteAutoModRule(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	ruleID := c.Params("ruleid")
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	err := s.AutoModerationRuleDelete(guildID, ruleID, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete AutoMod rule: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
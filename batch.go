@@ -0,0 +1,200 @@
+package disgm
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+)
+
+const (
+	minBatchInterval = 10 * time.Millisecond // Shortest flush interval of a batching client.
+	maxBatchInterval = 5 * time.Second       // Longest flush interval of a batching client.
+	defaultBatchSize = 100                   // Events per batch if the client sets no maximum.
+	maxBatchSize     = 1000                  // Largest batch a client may ask for.
+)
+
+// coalescedEvents lists the events that are coalesced per user for clients that ask for it.
+// They are sent often and only their latest state matters.
+var coalescedEvents = []string{"PRESENCE_UPDATE", "TYPING_START"}
+
+// BatchOptions is the payload of the batch op, which makes a client receive its events in
+// batches rather than one frame per event.
+//
+// A batch is sent every interval_ms milliseconds, or as soon as it holds max_events events. JSON
+// clients receive a batch as an array of events in one text frame, proto clients as an EventBatch
+// message. With coalesce, a PRESENCE_UPDATE or TYPING_START event replaces the pending one of the
+// same user, so a batch holds only the latest of them. Interactions are never batched. An
+// interval of 0 turns batching off and sends the pending events.
+//
+// Example:
+//
+//	{"op": "batch", "data": {"interval_ms": 250, "max_events": 50, "coalesce": true}}
+type BatchOptions struct {
+	IntervalMs int  `json:"interval_ms"` // Time events are collected before they are sent, 10 to 5000
+	MaxEvents  int  `json:"max_events"`  // Events that fill a batch, up to 1000, defaults to 100
+	Coalesce   bool `json:"coalesce"`    // Whether presence and typing updates are coalesced per user
+}
+
+// validate checks the options and sets the default batch size.
+func (o *BatchOptions) validate() error {
+	interval := time.Duration(o.IntervalMs) * time.Millisecond
+	if o.IntervalMs != 0 && (interval < minBatchInterval || interval > maxBatchInterval) {
+		return errors.New("interval_ms must be between 10 and 5000, or 0 to turn batching off")
+	}
+	if o.MaxEvents < 0 || o.MaxEvents > maxBatchSize {
+		return errors.New("max_events must be at most 1000")
+	}
+	if o.MaxEvents == 0 {
+		o.MaxEvents = defaultBatchSize
+	}
+	return nil
+}
+
+// batchedEvent is an event waiting in the batch of a client.
+type batchedEvent struct {
+	name string
+	data json.RawMessage
+	user string // User the event is coalesced by, if any
+}
+
+// eventBatch holds the events of a batching client until they are sent.
+type eventBatch struct {
+	options BatchOptions
+	events  []batchedEvent
+	timer   *time.Timer // Sends the batch at the end of the interval, nil while the batch is empty
+}
+
+// coalesceUser returns the user a coalesced event is about, or an empty string for other events.
+func coalesceUser(name string, data interface{}) string {
+	m, ok := data.(map[string]interface{})
+	if !ok || !slices.Contains(coalescedEvents, name) {
+		return ""
+	}
+	if id, ok := m["user_id"].(string); ok {
+		return id
+	}
+	if user, ok := m["user"].(map[string]interface{}); ok {
+		id, _ := user["id"].(string)
+		return id
+	}
+	return ""
+}
+
+// add queues an event in the batch of a client. Once the batch is full, its events are taken out
+// and returned, to be sent by the caller with writeBatch after it released clientsMu.
+// The caller must hold clientsMu.
+func (b *eventBatch) add(conn *websocket.Conn, name string, data json.RawMessage, user string) []batchedEvent {
+	if b.options.Coalesce && user != "" {
+		b.events = slices.DeleteFunc(b.events, func(e batchedEvent) bool {
+			return e.user == user && e.name == name
+		})
+	}
+	b.events = append(b.events, batchedEvent{name: name, data: data, user: user})
+
+	if len(b.events) >= b.options.MaxEvents {
+		return b.take()
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(time.Duration(b.options.IntervalMs)*time.Millisecond, func() {
+			clientsMu.Lock()
+			// The client may have disconnected or changed its batch options in the meantime.
+			info, ok := clientInfo[conn]
+			var events []batchedEvent
+			if ok && info.batch == b {
+				events = b.take()
+			}
+			clientsMu.Unlock()
+
+			if events != nil {
+				writeBatch(conn, info, events)
+			}
+		})
+	}
+	return nil
+}
+
+// take removes the pending events from the batch and returns them, or nil if there are none.
+// The caller must hold clientsMu.
+func (b *eventBatch) take() []batchedEvent {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.events) == 0 {
+		return nil
+	}
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// writeBatch sends the events of a batch as one frame in the encoding of the client.
+// The caller must not hold clientsMu.
+func writeBatch(conn *websocket.Conn, info *WSClient, events []batchedEvent) error {
+	buf := envelopeBuffers.Get().(*[]byte)
+	defer envelopeBuffers.Put(buf)
+
+	if info.Encoding == EncodingProto {
+		*buf = appendProtoBatch((*buf)[:0], events)
+		return writeFrame(conn, info, websocket.BinaryMessage, *buf)
+	}
+
+	*buf = append((*buf)[:0], '[')
+	for i, e := range events {
+		if i > 0 {
+			*buf = append(*buf, ',')
+		}
+		*buf = appendEventJSON(*buf, e.name, e.data, false)
+	}
+	*buf = append(*buf, ']')
+	return writeFrame(conn, info, websocket.TextMessage, *buf)
+}
+
+// appendProtoBatch appends an EventBatch message with the events of a batch.
+func appendProtoBatch(b []byte, events []batchedEvent) []byte {
+	var event []byte
+	for _, e := range events {
+		event = appendProtoEvent(event[:0], e.name, e.data, false)
+		b = appendLengthDelimited(b, 1, event)
+	}
+	return b
+}
+
+// handleBatch sets the batch options of a client and confirms them with a BATCH_UPDATED event,
+// or reports invalid options with BATCH_ERROR. The pending events are sent first, so the new
+// options only apply to the following events.
+func handleBatch(conn *websocket.Conn, raw json.RawMessage) {
+	var options BatchOptions
+	err := json.Unmarshal(raw, &options)
+	if err == nil {
+		err = options.validate()
+	}
+	if err != nil {
+		writeEvent(conn, "BATCH_ERROR", map[string]string{"error": "invalid batch options: " + err.Error()})
+		return
+	}
+
+	clientsMu.Lock()
+	info, ok := clientInfo[conn]
+	var pending []batchedEvent
+	if ok {
+		if info.batch != nil {
+			pending = info.batch.take()
+		}
+		info.batch, info.Batch = nil, nil
+		if options.IntervalMs > 0 {
+			info.batch = &eventBatch{options: options}
+			info.Batch = &info.batch.options
+		}
+	}
+	clientsMu.Unlock()
+
+	if pending != nil {
+		writeBatch(conn, info, pending)
+	}
+
+	writeEvent(conn, "BATCH_UPDATED", options)
+}
@@ -0,0 +1,248 @@
+package disgm
+
+import (
+	"container/list"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Names of the caches kept in the shared cache.
+const (
+	CacheUsers    = "users"    // User objects of bans, reactions and resolved user IDs
+	CacheWidgets  = "widgets"  // Widget images of the guilds, by style
+	CacheMessages = "messages" // Recent messages of the channels, prefetched for the READY snapshot
+)
+
+// Caches lists the caches kept in the shared cache.
+var Caches = []string{CacheUsers, CacheWidgets, CacheMessages}
+
+// CacheStats reports the usage of a cache since disgm started.
+type CacheStats struct {
+	Name       string `json:"name"`                  // Name of the cache
+	Entries    int    `json:"entries"`               // Number of cached entries
+	Bytes      int64  `json:"bytes"`                 // Estimated memory used by the entries
+	MaxEntries int    `json:"max_entries,omitempty"` // Maximum number of entries of the cache, if limited
+	Hits       uint64 `json:"hits"`                  // Lookups served from the cache
+	Misses     uint64 `json:"misses"`                // Lookups of missing or expired entries
+	Evictions  uint64 `json:"evictions"`             // Entries evicted for the memory or entry limit
+}
+
+// cacheEntry is an entry of the shared cache.
+type cacheEntry struct {
+	cache   string
+	key     string
+	value   interface{}
+	size    int64
+	expires time.Time
+}
+
+// cacheEntryOverhead is the estimated memory of an entry besides its value: the list element,
+// the map slot and the entry itself.
+const cacheEntryOverhead = 160
+
+// The shared cache: one LRU list for all caches, bounded by the estimated memory of its entries,
+// so a busy cache takes memory from idle ones instead of each having its own limit. Caches may
+// also limit their number of entries.
+var caches = struct {
+	sync.Mutex
+	maxBytes   int64
+	bytes      int64
+	order      *list.List               // Entries, most recently used first
+	entries    map[string]*list.Element // Entries keyed by cacheKey
+	maxEntries map[string]int
+	stats      map[string]*CacheStats
+}{
+	maxBytes:   64 << 20,
+	order:      list.New(),
+	entries:    make(map[string]*list.Element),
+	maxEntries: make(map[string]int),
+	stats:      make(map[string]*CacheStats),
+}
+
+// cacheKey returns the key of an entry in the shared cache.
+func cacheKey(cache, key string) string {
+	return cache + "\x00" + key
+}
+
+// cacheStats returns the stats of a cache, creating them if needed. The caller must hold caches.
+func cacheStats(cache string) *CacheStats {
+	stats, ok := caches.stats[cache]
+	if !ok {
+		stats = &CacheStats{Name: cache}
+		caches.stats[cache] = stats
+	}
+	return stats
+}
+
+// cacheGet returns an entry of a cache if it has not expired, marking it as recently used.
+func cacheGet(cache, key string) (interface{}, bool) {
+	caches.Lock()
+	defer caches.Unlock()
+
+	stats := cacheStats(cache)
+	el, ok := caches.entries[cacheKey(cache, key)]
+	if !ok {
+		stats.Misses++
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if time.Now().After(e.expires) {
+		cacheRemove(el)
+		stats.Misses++
+		return nil, false
+	}
+	caches.order.MoveToFront(el)
+	stats.Hits++
+	return e.value, true
+}
+
+// cacheSet adds or replaces an entry of a cache with its estimated size in bytes, then evicts the
+// least recently used entries beyond the entry limit of the cache and the memory limit.
+func cacheSet(cache, key string, value interface{}, size int64, ttl time.Duration) {
+	caches.Lock()
+	defer caches.Unlock()
+
+	if max, ok := caches.maxEntries[cache]; ok && max <= 0 {
+		return
+	}
+
+	k := cacheKey(cache, key)
+	if el, ok := caches.entries[k]; ok {
+		cacheRemove(el)
+	}
+	e := &cacheEntry{cache: cache, key: key, value: value, size: size + cacheEntryOverhead, expires: time.Now().Add(ttl)}
+	caches.entries[k] = caches.order.PushFront(e)
+	caches.bytes += e.size
+	stats := cacheStats(cache)
+	stats.Entries++
+	stats.Bytes += e.size
+
+	if max, ok := caches.maxEntries[cache]; ok && stats.Entries > max {
+		for el := caches.order.Back(); el != nil && stats.Entries > max; {
+			prev := el.Prev()
+			if el.Value.(*cacheEntry).cache == cache {
+				cacheRemove(el)
+				stats.Evictions++
+			}
+			el = prev
+		}
+	}
+	for caches.bytes > caches.maxBytes && caches.order.Len() > 1 {
+		el := caches.order.Back()
+		cacheRemove(el)
+		cacheStats(el.Value.(*cacheEntry).cache).Evictions++
+	}
+}
+
+// cacheRemove removes an entry from the shared cache. The caller must hold caches.
+func cacheRemove(el *list.Element) {
+	e := el.Value.(*cacheEntry)
+	caches.order.Remove(el)
+	delete(caches.entries, cacheKey(e.cache, e.key))
+	caches.bytes -= e.size
+	stats := cacheStats(e.cache)
+	stats.Entries--
+	stats.Bytes -= e.size
+}
+
+// cacheDelete removes an entry of a cache, if it is cached.
+func cacheDelete(cache, key string) {
+	caches.Lock()
+	defer caches.Unlock()
+
+	if el, ok := caches.entries[cacheKey(cache, key)]; ok {
+		cacheRemove(el)
+	}
+}
+
+// cacheFlush removes all entries of a cache, or of all caches if the name is empty.
+func cacheFlush(cache string) {
+	caches.Lock()
+	defer caches.Unlock()
+
+	for el := caches.order.Front(); el != nil; {
+		next := el.Next()
+		if cache == "" || el.Value.(*cacheEntry).cache == cache {
+			cacheRemove(el)
+		}
+		el = next
+	}
+}
+
+// GetCaches retrieves the usage and hit rates of the caches of disgm.
+//
+// The caches share one memory limit, set with the CacheMaxMemory option, and evict their least
+// recently used entries first. The stats are global to the disgm instance, not per guild, so
+// the endpoint requires an admin token, see the AdminTokens option.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the stats of each cache as a JSON array.
+//   - On failure, it returns an HTTP status 403 (Forbidden) without an admin token.
+// @Summary		Get Caches
+// @Description	Retrieve the entries, memory and hit rates of the caches.
+// @Tags			Admin
+// @Success		200	{array}		CacheStats
+// @Failure		403	{object}	error
+// @Router			/api/admin/caches [get]
+func GetCaches(c *fiber.Ctx, s *discordgo.Session) error {
+	if !isAdmin(c) {
+		return adminTokenRequired(c)
+	}
+
+	caches.Lock()
+	defer caches.Unlock()
+
+	stats := make([]CacheStats, 0, len(Caches))
+	for _, name := range Caches {
+		cs := *cacheStats(name)
+		cs.MaxEntries = caches.maxEntries[name]
+		stats = append(stats, cs)
+	}
+	return c.JSON(stats)
+}
+
+// FlushCache removes all entries of a cache, or of all caches.
+//
+// The caches are shared by all guilds, so the endpoint requires an admin token, see the AdminTokens option.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - name: The name of the cache to flush, e.g. users. Without a name, all caches are flushed.
+//
+// Returns:
+//   - On success, it returns an HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) without an admin token,
+//     or HTTP status 404 (Not Found) if the cache does not exist.
+// @Summary		Flush Cache
+// @Description	Remove all entries of a cache. The hit and miss counters are kept.
+// @Tags			Admin
+// @Param			name	path	string	true	"Cache name, all caches on /api/admin/caches"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		404	{object}	error
+// @Router			/api/admin/caches [delete]
+// @Router			/api/admin/caches/{name} [delete]
+func FlushCache(c *fiber.Ctx, s *discordgo.Session) error {
+	if !isAdmin(c) {
+		return adminTokenRequired(c)
+	}
+
+	name := c.Params("name")
+	if name != "" && !slices.Contains(Caches, name) {
+		return c.Status(fiber.StatusNotFound).SendString("Cache not found")
+	}
+
+	cacheFlush(name)
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,277 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Fields of a channel tracked by the channel history.
+const (
+	ChannelFieldName      = "name"
+	ChannelFieldTopic     = "topic"
+	ChannelFieldOverwrite = "permission_overwrite"
+)
+
+const (
+	maxChannelHistory = 100              // Entries kept per channel.
+	auditLogDelay     = 2 * time.Second  // Time to wait for the audit log entry of a change.
+	auditLogWindow    = 15 * time.Second // Maximum time between a change and its audit log entry.
+)
+
+// ChannelChange is a change of a single field of a channel. Before is null for added and After
+// for removed permission overwrites.
+type ChannelChange struct {
+	Field       string      `json:"field"`                  // Changed field: name, topic or permission_overwrite
+	OverwriteID string      `json:"overwrite_id,omitempty"` // ID of the role or member of a changed permission overwrite
+	Before      interface{} `json:"before"`                 // Value before the change
+	After       interface{} `json:"after"`                  // Value after the change
+}
+
+// ChannelHistoryEntry is a recorded update of a channel.
+type ChannelHistoryEntry struct {
+	ID        string          `json:"id"`                // Sortable ID of the entry within its channel
+	ChannelID string          `json:"channel_id"`        // ID of the updated channel
+	Time      time.Time       `json:"time"`              // When the update was received
+	UserID    string          `json:"user_id,omitempty"` // User who made the change, from the audit log if readable
+	Reason    string          `json:"reason,omitempty"`  // Audit log reason of the change
+	Changes   []ChannelChange `json:"changes"`           // Changed fields
+}
+
+// channelRecord is the last known state of the tracked fields of a channel.
+type channelRecord struct {
+	name       string
+	topic      string
+	overwrites map[string]OverwriteShape
+}
+
+// The channel history: the last known state of every channel, and the updates of name, topic
+// and permission overwrites recorded in the key-value store, keyed by "<guild>/<channel>/<id>".
+var channelHistory = struct {
+	sync.Mutex
+	storage *Storage
+	last    map[string]channelRecord
+}{last: make(map[string]channelRecord)}
+
+// newChannelRecord captures the tracked fields of a channel.
+func newChannelRecord(ch *discordgo.Channel) channelRecord {
+	rec := channelRecord{name: ch.Name, topic: ch.Topic, overwrites: make(map[string]OverwriteShape)}
+	for _, o := range ch.PermissionOverwrites {
+		rec.overwrites[o.ID] = OverwriteShape{ChannelID: ch.ID, ID: o.ID, Type: int(o.Type), Allow: o.Allow, Deny: o.Deny}
+	}
+	return rec
+}
+
+// diffChannelRecords lists the changed fields between two states of a channel.
+func diffChannelRecords(before, after channelRecord) []ChannelChange {
+	var changes []ChannelChange
+	if before.name != after.name {
+		changes = append(changes, ChannelChange{Field: ChannelFieldName, Before: before.name, After: after.name})
+	}
+	if before.topic != after.topic {
+		changes = append(changes, ChannelChange{Field: ChannelFieldTopic, Before: before.topic, After: after.topic})
+	}
+
+	overwrites := diffShapes(before.overwrites, after.overwrites)
+	for _, o := range overwrites.Added {
+		changes = append(changes, ChannelChange{Field: ChannelFieldOverwrite, OverwriteID: o.ID, After: o})
+	}
+	for _, o := range overwrites.Removed {
+		changes = append(changes, ChannelChange{Field: ChannelFieldOverwrite, OverwriteID: o.ID, Before: o})
+	}
+	for _, o := range overwrites.Changed {
+		changes = append(changes, ChannelChange{Field: ChannelFieldOverwrite, OverwriteID: o.After.ID, Before: o.Before, After: o.After})
+	}
+	return changes
+}
+
+// attributeChannelChange looks up the user and reason of a channel update in the guild audit log.
+// The bot needs the View Audit Log permission; without it the entry stays unattributed.
+func attributeChannelChange(s *discordgo.Session, guildID string, entry *ChannelHistoryEntry) {
+	auditLog, err := s.GuildAuditLog(guildID, "", "", 0, 25)
+	if err != nil {
+		return
+	}
+
+	for _, e := range auditLog.AuditLogEntries {
+		if e.TargetID != entry.ChannelID || e.ActionType == nil {
+			continue
+		}
+		switch *e.ActionType {
+		case discordgo.AuditLogActionChannelUpdate,
+			discordgo.AuditLogActionChannelOverwriteCreate,
+			discordgo.AuditLogActionChannelOverwriteUpdate,
+			discordgo.AuditLogActionChannelOverwriteDelete:
+		default:
+			continue
+		}
+
+		created, err := discordgo.SnowflakeTimestamp(e.ID)
+		if err != nil || entry.Time.Sub(created).Abs() > auditLogWindow {
+			continue
+		}
+		// Entries are newest first, so this is the latest matching change.
+		entry.UserID = e.UserID
+		entry.Reason = e.Reason
+		return
+	}
+}
+
+// persistChannelHistory stores an entry, dropping the oldest entries of the channel beyond maxChannelHistory.
+func persistChannelHistory(storage *Storage, guildID string, entry *ChannelHistoryEntry) {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Failed to record channel history: %v", err)
+		return
+	}
+
+	prefix := guildID + "/" + entry.ChannelID + "/"
+	err = storage.Update(func(tx *StorageTx) error {
+		if err := tx.Set(prefix+entry.ID, value); err != nil {
+			return err
+		}
+		keys, err := tx.List(prefix)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys[:max(0, len(keys)-maxChannelHistory)] {
+			if err := tx.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to record channel history: %v", err)
+	}
+}
+
+// registerChannelHistoryHandlers tracks the channels of the guilds and records their updates.
+func registerChannelHistoryHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
+		channelHistory.Lock()
+		defer channelHistory.Unlock()
+		for _, ch := range g.Channels {
+			channelHistory.last[ch.ID] = newChannelRecord(ch)
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.ChannelCreate) {
+		channelHistory.Lock()
+		defer channelHistory.Unlock()
+		channelHistory.last[c.ID] = newChannelRecord(c.Channel)
+	})
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.ChannelDelete) {
+		channelHistory.Lock()
+		defer channelHistory.Unlock()
+		delete(channelHistory.last, c.ID)
+	})
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.ChannelUpdate) {
+		after := newChannelRecord(c.Channel)
+
+		channelHistory.Lock()
+		before, ok := channelHistory.last[c.ID]
+		channelHistory.last[c.ID] = after
+		storage := channelHistory.storage
+		channelHistory.Unlock()
+
+		if !ok || storage == nil || c.GuildID == "" {
+			return // Nothing to compare against before the channel was seen.
+		}
+
+		changes := diffChannelRecords(before, after)
+		if len(changes) == 0 {
+			return
+		}
+
+		now := time.Now()
+		entry := &ChannelHistoryEntry{
+			ID:        fmt.Sprintf("%020d", now.UnixNano()),
+			ChannelID: c.ID,
+			Time:      now,
+			Changes:   changes,
+		}
+
+		// The audit log entry may be written after the gateway event.
+		go func() {
+			time.Sleep(auditLogDelay)
+			attributeChannelChange(s, c.GuildID, entry)
+			persistChannelHistory(storage, c.GuildID, entry)
+		}()
+	})
+}
+
+// GetChannelHistory retrieves the recorded name, topic and permission changes of a channel.
+//
+// This function answers who renamed or re-permissioned a channel and when. Changes are recorded
+// from the channel updates disgm receives and attributed to a user and reason from the guild
+// audit log when the bot can read it.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - limit: The number of entries to return (default 50, max 100) unless changed with the Limits option.
+//
+// Returns:
+//   - On success, it returns the history entries as a JSON array, newest first.
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is outside the
+//     token's scope, or HTTP status 500 (Internal Server Error) if the history cannot be read.
+// @Summary		Get Channel History
+// @Description	Retrieve the recorded name, topic and permission changes of a channel.
+// @Tags			Channels
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			limit		query		int		false	"Number of entries"	minimum(1)	maximum(100)	default(50)
+// @Success		200			{array}		ChannelHistoryEntry
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/history [get]
+func GetChannelHistory(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	channelID := c.Params("channelid")
+	limit := queryLimit(c, LimitChannelHistory)
+
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
+	channelHistory.Lock()
+	storage := channelHistory.storage
+	channelHistory.Unlock()
+
+	entries := []ChannelHistoryEntry{}
+	if storage == nil {
+		return c.JSON(entries)
+	}
+
+	err := storage.View(func(tx *StorageTx) error {
+		keys, err := tx.List(guildID + "/" + channelID + "/")
+		if err != nil {
+			return err
+		}
+		slices.Reverse(keys)
+
+		for _, key := range keys[:min(limit, len(keys))] {
+			value, ok, err := tx.Get(key)
+			if err != nil {
+				return err
+			}
+			var entry ChannelHistoryEntry
+			if ok && json.Unmarshal(value, &entry) == nil {
+				entries = append(entries, entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read channel history: " + err.Error())
+	}
+
+	return c.JSON(entries)
+}
@@ -1,6 +1,9 @@
 package disgm
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rif223/disgm/models"
@@ -37,6 +40,11 @@ func GetGuildChannels(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild channels: " + err.Error())
 	}
 
+	// Hides the channels outside the token scope.
+	channels = slices.DeleteFunc(channels, func(ch *discordgo.Channel) bool {
+		return !channelAllowed(c, ch.ID, false)
+	})
+
 	return c.JSON(channels)
 }
 
@@ -53,23 +61,29 @@ func GetGuildChannels(c *fiber.Ctx, s *discordgo.Session) error {
 //   - channelid: The ID of the channel to retrieve.
 //
 // Returns:
-//   - On success, it returns the channel details as JSON.
+//   - On success, it returns the channel details as JSON, with an ETag header for If-Match on updates.
 //   - On failure, it returns an HTTP status 500 (Internal Server Error) with an error message.
 // @Summary		Get Guild Channel
 // @Description	Retrieve a specific channel from the guild by ID.
 // @Tags			Channels
 // @Param			channelid	path		string	true	"Channel ID"
 // @Success		200			{object}	models.Channel
+// @Failure		403			{object}	error
 // @Failure		500			{object}	error
 // @Router			/api/guild/channels/{channelid} [get]
 func GetGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
 	channel, err := s.Channel(channelID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve channel: " + err.Error())
 	}
 
+	c.Set(fiber.HeaderETag, channelVersion(channel))
 	return c.JSON(channel)
 }
 
@@ -97,6 +111,7 @@ func GetGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 // @Description	Create a new channel in the guild.
 // @Tags			Channels
 // @Success		201	{object}	models.Channel
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels [post]
 func CreateGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
@@ -107,7 +122,12 @@ func CreateGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
 
-	channel, err := s.GuildChannelCreateComplex(guildID, channelData)
+	// Restricted tokens may only create channels inside a writable category.
+	if !unrestricted(c) && !channelAllowed(c, channelData.ParentID, true) {
+		return channelForbidden(c)
+	}
+
+	channel, err := s.GuildChannelCreateComplex(guildID, channelData, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create channel: " + err.Error())
 	}
@@ -128,32 +148,81 @@ func CreateGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 //   - channelid: The ID of the channel to update.
 //
 // Request Body:
-//   - The request body should contain the new channel settings in JSON format.
+//   - The request body should contain the new channel settings in JSON format. Fields such as
+//     topic or parent_id are cleared with null in a merge patch, or by listing them in the
+//     X-Update-Mask header.
+//
+// Request Headers:
+//   - If-Match: Optional ETag of the channel as read by the client. The update is refused if the
+//     channel was changed since, so concurrent edits do not overwrite each other.
 //
 // Returns:
-//   - On success, it returns the updated channel as JSON.
+//   - On success, it returns the updated channel as JSON with its new ETag.
 //   - On failure, it returns an HTTP status 400 (Bad Request) if the request body is invalid,
+//     HTTP status 412 (Precondition Failed) if the channel does not match If-Match,
 //     or an HTTP status 500 (Internal Server Error) if the update fails.
 // @Summary		Update Guild Channel
 // @Description	Update a specific channel in the guild.
 // @Tags			Channels
-// @Param			channelid	path		string	true	"Channel ID"
-// @Success		200			{object}	models.Channel
-// @Failure		500			{object}	error
+// @Accept			json,application/merge-patch+json
+// @Param			channelid		path		string	true	"Channel ID"
+// @Param			override		query		bool	false	"Allow changing a protected resource"
+// @Param			X-Update-Mask	header		string	false	"Fields to update, comma-separated; missing ones are cleared"
+// @Param			If-Match		header		string	false	"ETag of the channel as read"
+// @Success		200				{object}	models.Channel
+// @Failure		400				{object}	error
+// @Failure		403				{object}	error
+// @Failure		412				{object}	error
+// @Failure		500				{object}	error
 // @Router			/api/guild/channels/{channelid} [patch]
 func UpdateGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	if channelProtected(c, s, channelID) {
+		return protectedForbidden(c)
+	}
+
 	var options *discordgo.ChannelEdit
 	if err := c.BodyParser(&options); err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
+	fields, err := patchFields(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
 
-	channel, err := s.ChannelEdit(channelID, options)
+	before, beforeErr := cachedChannel(s, channelID)
+	if c.Get(fiber.HeaderIfMatch) != "" {
+		if beforeErr != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve channel: " + beforeErr.Error())
+		}
+		if current := channelVersion(before); !ifMatch(c, current) {
+			return preconditionFailed(c, current)
+		}
+	}
+
+	var channel *discordgo.Channel
+	if fields != nil {
+		channel = new(discordgo.Channel)
+		err = patchResource(s, discordgo.EndpointChannel(channelID), discordgo.EndpointChannel(channelID), fields, channel, auditOptions(c)...)
+	} else {
+		channel, err = s.ChannelEdit(channelID, options, auditOptions(c)...)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update channel positions: " + err.Error())
 	}
 
+	if beforeErr == nil {
+		recordChange(c, ChangeChannelEdit, channelID, func(options ...discordgo.RequestOption) error {
+			return restoreChannel(s, before, options...)
+		})
+	}
+
+	c.Set(fiber.HeaderETag, channelVersion(channel))
 	return c.JSON(channel)
 }
 
@@ -176,13 +245,32 @@ func UpdateGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 // @Description	Delete a specific channel in the guild.
 // @Tags			Channels
 // @Param			channelid	path	string	true	"Channel ID"
+// @Param			override	query		bool	false	"Allow changing a protected resource"
 // @Success		204
+// @Success		202	{object}	Approval
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid} [delete]
 func DeleteGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 
-	channel, err := s.ChannelDelete(channelID)
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	if channelProtected(c, s, channelID) {
+		return protectedForbidden(c)
+	}
+
+	if approvals.required(ActionChannelDelete) {
+		channelID := strings.Clone(channelID) // Params point into the request buffer, which is reused.
+		return requestApproval(c, ActionChannelDelete, []string{channelID}, func(options ...discordgo.RequestOption) error {
+			_, err := s.ChannelDelete(channelID, options...)
+			return err
+		})
+	}
+
+	channel, err := s.ChannelDelete(channelID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete channel: " + err.Error())
 	}
@@ -215,23 +303,43 @@ func DeleteGuildChannel(c *fiber.Ctx, s *discordgo.Session) error {
 // @Tags			Channels
 // @Param			channelid	path	string	true	"Channel ID"
 // @Param			overwriteid	path	string	true	"Overwrite ID"
+// @Param			override	query		bool	false	"Allow changing a protected resource"
 // @Success		204
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid}/permissions/{overwriteid} [put]
 func EditChannelPermissions(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 	overwriteID := c.Params("overwriteid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	if channelProtected(c, s, channelID) {
+		return protectedForbidden(c)
+	}
+
 	var perm discordgo.PermissionOverwrite
 	if err := c.BodyParser(&perm); err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
 
-	err := s.ChannelPermissionSet(channelID, overwriteID, perm.Type, perm.Allow, perm.Deny)
+	before, beforeErr := cachedChannel(s, channelID)
+
+	err := s.ChannelPermissionSet(channelID, overwriteID, perm.Type, perm.Allow, perm.Deny, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to edit channel permissions: " + err.Error())
 	}
 
+	if beforeErr == nil {
+		channelID, overwriteID := strings.Clone(channelID), strings.Clone(overwriteID) // Params point into the request buffer, which is reused.
+		overwrite := findOverwrite(before, overwriteID)
+		recordChange(c, ChangeOverwriteEdit, channelID, func(options ...discordgo.RequestOption) error {
+			return restoreOverwrite(s, channelID, overwriteID, overwrite, options...)
+		})
+	}
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -256,17 +364,93 @@ func EditChannelPermissions(c *fiber.Ctx, s *discordgo.Session) error {
 // @Tags			Channels
 // @Param			channelid	path	string	true	"Channel ID"
 // @Param			overwriteid	path	string	true	"Overwrite ID"
+// @Param			override	query		bool	false	"Allow changing a protected resource"
 // @Success		204
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid}/permissions/{overwriteid} [delete]
 func DeleteChannelPermissions(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 	overwriteID := c.Params("overwriteid")
 
-	err := s.ChannelPermissionDelete(channelID, overwriteID)
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	if channelProtected(c, s, channelID) {
+		return protectedForbidden(c)
+	}
+
+	before, beforeErr := cachedChannel(s, channelID)
+
+	err := s.ChannelPermissionDelete(channelID, overwriteID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete channel permissions: " + err.Error())
 	}
 
+	if beforeErr == nil {
+		if overwrite := findOverwrite(before, overwriteID); overwrite != nil {
+			channelID, overwriteID := strings.Clone(channelID), strings.Clone(overwriteID) // Params point into the request buffer, which is reused.
+			recordChange(c, ChangeOverwriteDelete, channelID, func(options ...discordgo.RequestOption) error {
+				return restoreOverwrite(s, channelID, overwriteID, overwrite, options...)
+			})
+		}
+	}
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// CreateChannelThread creates a thread in a channel without a starter message.
+//
+// This function parses the thread name, auto-archive duration and type from the request body
+// into a `discordgo.ThreadStart` struct. The type is a public thread (11) by default, or a
+// private thread (12), which only invited members and moderators can see.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the created thread channel as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token's scope,
+//     or HTTP status 500 (Internal Server Error) if the thread cannot be created.
+// @Summary		Create Channel Thread
+// @Description	Create a public or private thread in a channel without a message.
+// @Tags			Channels
+// @Param			channelid	path		string	true	"Channel ID"
+// @Success		201			{object}	models.Channel
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/threads [post]
+func CreateChannelThread(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	var thread discordgo.ThreadStart
+	if err := c.BodyParser(&thread); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if thread.Name == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name is required")
+	}
+
+	switch thread.Type {
+	case 0:
+		thread.Type = discordgo.ChannelTypeGuildPublicThread
+	case discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread, discordgo.ChannelTypeGuildNewsThread:
+	default:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: type must be a thread type (10, 11 or 12)")
+	}
+
+	channel, err := s.ThreadStartComplex(channelID, &thread, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create thread: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(channel)
+}
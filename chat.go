@@ -0,0 +1,187 @@
+package disgm
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/contrib/websocket"
+)
+
+const (
+	chatWindow     = 10 * time.Second // Window the chat rate limit applies to.
+	maxChatContent = 2000             // Maximum length of a message sent with the chat op.
+)
+
+// ChatMessage is the payload of the chat op, sent by WebSocket clients to post into a channel.
+//
+// Example:
+//
+//	{"op": "chat", "data": {"channel_id": "123", "name": "Visitor", "content": "Hello!"}}
+type ChatMessage struct {
+	ChannelID string `json:"channel_id"`     // ID of the channel the message is posted to
+	Content   string `json:"content"`        // Content of the message
+	Name      string `json:"name,omitempty"` // Display name of the sender, prefixed to the content
+}
+
+// ChatError is sent to a WebSocket client as CHAT_ERROR when its chat op is rejected.
+type ChatError struct {
+	ChannelID string `json:"channel_id"`
+	Error     string `json:"error"`
+}
+
+// The state of the WebSocket chat op: whether it is enabled, the rate limit per connection,
+// the send times of each connection and the nonces of messages awaiting their gateway echo.
+var chat = struct {
+	sync.Mutex
+	enabled bool
+	limit   int
+	sent    map[*websocket.Conn][]time.Time
+	nonces  map[string]*websocket.Conn
+}{
+	sent:   make(map[*websocket.Conn][]time.Time),
+	nonces: make(map[string]*websocket.Conn),
+}
+
+// allowChat reports whether a connection is within a rate limit and records the send.
+// The caller must hold the chat lock.
+func allowChat(conn *websocket.Conn, limit int) bool {
+	now := time.Now()
+
+	recent := chat.sent[conn][:0]
+	for _, t := range chat.sent[conn] {
+		if now.Sub(t) < chatWindow {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= limit {
+		chat.sent[conn] = recent
+		return false
+	}
+	chat.sent[conn] = append(recent, now)
+	return true
+}
+
+// chatSender returns the connection that sent a message with the chat op, found by the nonce
+// of its MESSAGE_CREATE event, so the message is not echoed back to it.
+func chatSender(data map[string]interface{}) *websocket.Conn {
+	nonce, ok := data["nonce"].(string)
+	if !ok {
+		return nil
+	}
+
+	chat.Lock()
+	defer chat.Unlock()
+
+	conn := chat.nonces[nonce]
+	delete(chat.nonces, nonce)
+	return conn
+}
+
+// forgetChatClient drops the chat state of a disconnected client.
+func forgetChatClient(conn *websocket.Conn) {
+	chat.Lock()
+	defer chat.Unlock()
+
+	delete(chat.sent, conn)
+	for nonce, c := range chat.nonces {
+		if c == conn {
+			delete(chat.nonces, nonce)
+		}
+	}
+}
+
+// handleChat posts the message of a chat op into a channel of the client's guild.
+//
+// The message is sent without mentions and with a nonce, which identifies its MESSAGE_CREATE
+// event so it is delivered to every other client of the guild but not echoed to the sender.
+// The sender receives CHAT_SENT with the created message, or CHAT_ERROR if it was rejected.
+//
+// Parameters:
+//   - conn: *websocket.Conn – The connection of the client, holding its token scope.
+//   - guildID: string – The ID of the guild the client is connected to.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//   - raw: json.RawMessage – The ChatMessage payload of the op.
+func handleChat(conn *websocket.Conn, guildID string, s *discordgo.Session, raw json.RawMessage) {
+	var msg ChatMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		writeEvent(conn, "CHAT_ERROR", ChatError{Error: "invalid chat payload: " + err.Error()})
+		return
+	}
+
+	reject := func(reason string) {
+		writeEvent(conn, "CHAT_ERROR", ChatError{ChannelID: msg.ChannelID, Error: reason})
+	}
+
+	content := strings.TrimSpace(msg.Content)
+	if name := strings.TrimSpace(msg.Name); name != "" && content != "" {
+		content = "**" + name + "**: " + content
+	}
+
+	switch {
+	case content == "":
+		reject("message is empty")
+		return
+	case len(content) > maxChatContent:
+		reject("message is too long")
+		return
+	}
+
+	channel, err := cachedChannel(s, msg.ChannelID)
+	if err != nil || channel.GuildID != guildID {
+		reject("channel not found in guild")
+		return
+	}
+	if !scopeAllows(conn.Locals("Scope"), msg.ChannelID, true) {
+		reject("channel is not allowed for this token")
+		return
+	}
+
+	nonce, err := randomToken(8)
+	if err != nil {
+		reject("failed to send message: " + err.Error())
+		return
+	}
+
+	chat.Lock()
+	if !chat.enabled {
+		chat.Unlock()
+		reject("chat is disabled")
+		return
+	}
+	limit := chat.limit
+	if tenant := requestTenant(conn.Locals("Tenant")); tenant != nil && tenant.Limits().ChatMessages > 0 {
+		limit = tenant.Limits().ChatMessages
+	}
+	if !allowChat(conn, limit) {
+		chat.Unlock()
+		reject("rate limited")
+		return
+	}
+	chat.nonces[nonce] = conn
+	chat.Unlock()
+
+	// discordgo does not expose message nonces, so the message is posted directly.
+	endpoint := discordgo.EndpointChannelMessages(msg.ChannelID)
+	body, err := s.RequestWithBucketID("POST", endpoint, map[string]interface{}{
+		"content":          content,
+		"nonce":            nonce,
+		"allowed_mentions": discordgo.MessageAllowedMentions{}, // Chat messages never ping.
+	}, endpoint)
+
+	var created discordgo.Message
+	if err == nil {
+		err = json.Unmarshal(body, &created)
+	}
+	if err != nil {
+		chat.Lock()
+		delete(chat.nonces, nonce)
+		chat.Unlock()
+		reject("failed to send message: " + err.Error())
+		return
+	}
+
+	writeEvent(conn, "CHAT_SENT", created)
+}
@@ -0,0 +1,175 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+)
+
+// clientRelayWindow is the window the rate limit of the relay op applies to.
+const clientRelayWindow = 10 * time.Second
+
+// ClientRelay is the payload of the relay op, which sends an application message to the other
+// WebSocket clients of the guild, such as presence in a dashboard. disgm only forwards the data.
+// The op is disabled unless the WebSocketRelay option is set.
+//
+// Example:
+//
+//	{"op": "relay", "data": {"type": "editing", "data": {"user": "X", "channel_id": "123"}}}
+type ClientRelay struct {
+	Type string          `json:"type"`         // Application-defined type of the message, e.g. "editing"
+	Data json.RawMessage `json:"data"`         // Any JSON value, up to the size limit of the MaxClientRelaySize option
+	To   string          `json:"to,omitempty"` // Connection ID of a single client to send the message to, all others if unset
+}
+
+// ClientRelayMessage is the payload of the CLIENT_RELAY event, a message relayed from another
+// client of the guild.
+type ClientRelayMessage struct {
+	From     string          `json:"from"`                // Connection ID of the sender
+	FromName string          `json:"from_name,omitempty"` // Name the sender identified with
+	Type     string          `json:"type"`                // Type of the message
+	Data     json.RawMessage `json:"data"`                // Data of the message
+}
+
+// RelaySent is the payload of the RELAY_SENT event, the reply to the relay op.
+type RelaySent struct {
+	Recipients int `json:"recipients"` // Clients the message was sent to
+}
+
+// The state of the relay op: whether it is enabled, its limits and the send times of each
+// connection.
+var clientRelays = struct {
+	sync.Mutex
+	enabled bool
+	limit   int
+	maxSize int
+	sent    map[*websocket.Conn][]time.Time
+}{sent: make(map[*websocket.Conn][]time.Time)}
+
+// allowClientRelay reports whether a connection is within the rate limit and records the send.
+// The caller must hold the lock of clientRelays.
+func allowClientRelay(conn *websocket.Conn) bool {
+	now := time.Now()
+
+	recent := clientRelays.sent[conn][:0]
+	for _, t := range clientRelays.sent[conn] {
+		if now.Sub(t) < clientRelayWindow {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= clientRelays.limit {
+		clientRelays.sent[conn] = recent
+		return false
+	}
+	clientRelays.sent[conn] = append(recent, now)
+	return true
+}
+
+// forgetClientRelays drops the relay state of a disconnected client.
+func forgetClientRelays(conn *websocket.Conn) {
+	clientRelays.Lock()
+	defer clientRelays.Unlock()
+	delete(clientRelays.sent, conn)
+}
+
+// handleClientRelay sends the message of a relay op to the other clients of the guild, or to the
+// client it is addressed to, as CLIENT_RELAY. Messages are not recorded in the event buffer, and
+// clients that subscribed to events receive messages to all clients only if they subscribed to
+// CLIENT_RELAY. The sender receives RELAY_SENT with the number of recipients, or RELAY_ERROR if
+// it was rejected.
+//
+// Parameters:
+//   - conn: *websocket.Conn – The connection of the client sending the message.
+//   - guildID: string – The ID of the guild the client is connected to.
+//   - raw: json.RawMessage – The ClientRelay payload of the op.
+func handleClientRelay(conn *websocket.Conn, guildID string, raw json.RawMessage) {
+	var msg ClientRelay
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		writeEvent(conn, "RELAY_ERROR", errorPayload{Error: "invalid relay payload: " + err.Error()})
+		return
+	}
+
+	clientRelays.Lock()
+	enabled, maxSize := clientRelays.enabled, clientRelays.maxSize
+	clientRelays.Unlock()
+
+	switch {
+	case !enabled:
+		writeEvent(conn, "RELAY_ERROR", errorPayload{Error: "relay is disabled"})
+		return
+	case msg.Type == "" || len(msg.Type) > maxClientLabel:
+		writeEvent(conn, "RELAY_ERROR", errorPayload{Error: fmt.Sprintf("type must have 1-%d characters", maxClientLabel)})
+		return
+	case len(msg.Data) > maxSize:
+		writeEvent(conn, "RELAY_ERROR", errorPayload{Error: fmt.Sprintf("data must be at most %d bytes", maxSize)})
+		return
+	}
+
+	clientRelays.Lock()
+	allowed := allowClientRelay(conn)
+	clientRelays.Unlock()
+	if !allowed {
+		writeEvent(conn, "RELAY_ERROR", errorPayload{Error: "rate limited"})
+		return
+	}
+
+	if len(msg.Data) == 0 {
+		msg.Data = json.RawMessage("null")
+	}
+	clientsMu.Lock()
+	relayed := ClientRelayMessage{From: clientInfo[conn].ID, FromName: clientInfo[conn].Name, Type: msg.Type, Data: msg.Data}
+	clientsMu.Unlock()
+
+	data, err := marshalEvent(relayed)
+	if err != nil {
+		writeEvent(conn, "RELAY_ERROR", errorPayload{Error: "failed to relay message: " + err.Error()})
+		return
+	}
+
+	clientsMu.Lock()
+	var recipients []*websocket.Conn
+	if msg.To != "" {
+		if target, ok := guildClient(guildID, msg.To); ok && target != conn {
+			recipients = append(recipients, target)
+		}
+	} else {
+		for client, gid := range clients {
+			if gid == guildID && client != conn && clientInfo[client].subscription.wants("CLIENT_RELAY", nil) {
+				recipients = append(recipients, client)
+			}
+		}
+	}
+	// The recipients are written to after the lock is released, see broadcastEvent.
+	type write struct {
+		conn  *websocket.Conn
+		info  *WSClient
+		batch []batchedEvent // Full batch of a batching client, nil if the message is written directly
+	}
+	var writes []write
+	for _, client := range recipients {
+		info := clientInfo[client]
+		if info.batch == nil {
+			writes = append(writes, write{conn: client, info: info})
+		} else if full := info.batch.add(client, "CLIENT_RELAY", data, ""); full != nil {
+			writes = append(writes, write{client, info, full})
+		}
+	}
+	clientsMu.Unlock()
+
+	for _, w := range writes {
+		if w.batch != nil {
+			writeBatch(w.conn, w.info, w.batch)
+		} else {
+			writeEncoded(w.conn, w.info, "CLIENT_RELAY", data, false)
+		}
+	}
+
+	if msg.To != "" && len(recipients) == 0 {
+		writeEvent(conn, "RELAY_ERROR", errorPayload{Error: fmt.Sprintf("client %s is not connected", msg.To)})
+		return
+	}
+	writeEvent(conn, "RELAY_SENT", RelaySent{Recipients: len(recipients)})
+}
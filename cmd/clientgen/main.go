@@ -0,0 +1,71 @@
+// Command clientgen generates TypeScript and Python clients of the disgm API.
+//
+// The clients are generated from the OpenAPI document compiled into disgm, so regenerate the
+// document with swag init first. Each API route becomes a method named after its summary, and
+// each definition a type. Run it from the module root, or with go generate:
+//
+//	go run ./cmd/clientgen                          # write clients/disgm.ts and clients/disgm_client.py
+//	go run ./cmd/clientgen -out ../dashboard/src/api -lang ts
+//	DISGM_CLIENT_DIR=../dashboard/src/api go generate
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/swaggo/swag"
+
+	_ "github.com/rif223/disgm/docs"
+)
+
+// generators are the client generators by language, with the name of the file they write.
+var generators = map[string]struct {
+	file     string
+	generate func(*api) string
+}{
+	"ts": {"disgm.ts", typescript},
+	"py": {"disgm_client.py", python},
+}
+
+func main() {
+	out := flag.String("out", "", "directory the clients are written to, defaults to clients")
+	langs := flag.String("lang", "ts,py", "comma-separated languages of the clients, ts and py")
+	flag.Parse()
+
+	// Empty when go generate expands an unset DISGM_CLIENT_DIR.
+	if *out == "" {
+		*out = "clients"
+	}
+
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read the OpenAPI document:", err)
+		os.Exit(2)
+	}
+	a, err := parseAPI(doc)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to parse the OpenAPI document:", err)
+		os.Exit(2)
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create the output directory:", err)
+		os.Exit(2)
+	}
+	for _, lang := range strings.Split(*langs, ",") {
+		g, ok := generators[strings.TrimSpace(lang)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown language %q, use ts or py\n", lang)
+			os.Exit(2)
+		}
+		path := filepath.Join(*out, g.file)
+		if err := os.WriteFile(path, []byte(g.generate(a)), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to write client:", err)
+			os.Exit(2)
+		}
+		fmt.Printf("Generated %d methods and %d types to %s\n", len(a.endpoints), len(a.types), path)
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// schema is a Swagger 2.0 schema, as generated by swag.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Items                *schema            `json:"items"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"` // true or a schema
+	AllOf                []*schema          `json:"allOf"`
+	Required             []string           `json:"required"`
+}
+
+// values returns the schema of the values of a map, or nil if the schema is not a map.
+func (s *schema) values() (*schema, bool) {
+	if len(s.AdditionalProperties) == 0 || string(s.AdditionalProperties) == "false" {
+		return nil, false
+	}
+	var values schema
+	if json.Unmarshal(s.AdditionalProperties, &values) != nil {
+		return nil, true // Any value.
+	}
+	return &values, true
+}
+
+// parameter is a parameter of an operation.
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path, query, header, body or formData
+	Type        string  `json:"type"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+	Items       *schema `json:"items"`
+}
+
+// schema returns the schema of a parameter, which is inline for all but body parameters.
+func (p parameter) schema() *schema {
+	if p.Schema != nil {
+		return p.Schema
+	}
+	return &schema{Type: p.Type, Items: p.Items}
+}
+
+// operation is a route of the document.
+type operation struct {
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Produces    []string `json:"produces"`
+	Parameters  []parameter
+	Responses   map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"responses"`
+}
+
+// document is the part of the OpenAPI document the clients are generated from.
+type document struct {
+	Info struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths       map[string]map[string]*operation `json:"paths"`
+	Definitions map[string]*schema               `json:"definitions"`
+}
+
+// result is how the body of a successful response is read.
+type result int
+
+const (
+	resultNone   result = iota // No body, e.g. 204 No Content
+	resultJSON                 // A JSON value of the schema of the response
+	resultText                 // Text, e.g. NDJSON or a protobuf schema
+	resultBinary               // Binary data, e.g. an image
+)
+
+// endpoint is an API route, a method of the clients.
+type endpoint struct {
+	words       []string // Words of the method name, from the summary
+	method      string
+	path        string
+	summary     string
+	description string
+	pathParams  []parameter // In the order of the path
+	query       []parameter
+	header      []parameter
+	form        []parameter
+	body        *parameter
+	result      result
+	schema      *schema // Schema of the JSON response
+}
+
+// typeDef is a definition of the document, a type of the clients.
+type typeDef struct {
+	name   string
+	schema *schema
+}
+
+// api is the API described by the OpenAPI document.
+type api struct {
+	title     string
+	version   string
+	endpoints []*endpoint
+	types     []typeDef
+	typeNames map[string]string // Names of the types by definition, e.g. ShapeChangeRoleShape for disgm.ShapeChange-disgm_RoleShape
+}
+
+// methodOrder is the order of the methods of a path in the clients.
+var methodOrder = []string{"get", "post", "put", "patch", "delete"}
+
+// parseAPI parses the OpenAPI document into the endpoints and types of the clients. Routes outside
+// of /api, such as the WebSocket endpoint, are left out.
+func parseAPI(doc string) (*api, error) {
+	var d document
+	if err := json.Unmarshal([]byte(doc), &d); err != nil {
+		return nil, err
+	}
+	a := &api{title: d.Info.Title, version: d.Info.Version, typeNames: make(map[string]string)}
+
+	// Definitions are named without their package, e.g. Role for disgm.Role, unless two packages
+	// define the name, e.g. ModelsRole for models.Role.
+	definitions := make([]string, 0, len(d.Definitions))
+	packages := make(map[string]int)
+	for name := range d.Definitions {
+		definitions = append(definitions, name)
+		packages[typeName(name)]++
+	}
+	slices.Sort(definitions)
+	for _, name := range definitions {
+		pkg, _, _ := strings.Cut(name, ".")
+		a.typeNames[name] = typeName(name)
+		if packages[typeName(name)] > 1 && pkg != "disgm" {
+			a.typeNames[name] = pascal(pkg) + typeName(name)
+		}
+		a.types = append(a.types, typeDef{name: a.typeNames[name], schema: d.Definitions[name]})
+	}
+
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		if strings.HasPrefix(path, "/api/") {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		for _, method := range methodOrder {
+			op, ok := d.Paths[path][method]
+			if !ok {
+				continue
+			}
+			e, err := parseEndpoint(strings.ToUpper(method), path, op)
+			if err != nil {
+				return nil, err
+			}
+			a.endpoints = append(a.endpoints, e)
+		}
+	}
+
+	// Routes sharing a summary are told apart by their path parameters, e.g. flushCacheByName.
+	names := make(map[string][]*endpoint)
+	for _, e := range a.endpoints {
+		names[strings.Join(e.words, " ")] = append(names[strings.Join(e.words, " ")], e)
+	}
+	for _, shared := range names {
+		if len(shared) < 2 {
+			continue
+		}
+		for _, e := range shared {
+			if len(e.pathParams) > 0 {
+				e.words = append(e.words, "by")
+				for _, p := range e.pathParams {
+					e.words = append(e.words, words(p.Name)...)
+				}
+			}
+		}
+	}
+	seen := make(map[string]bool)
+	for _, e := range a.endpoints {
+		name := strings.Join(e.words, " ")
+		if seen[name] {
+			return nil, fmt.Errorf("%s %s: summary %q is used by another route with the same path parameters", e.method, e.path, e.summary)
+		}
+		seen[name] = true
+	}
+	return a, nil
+}
+
+// parseEndpoint reads the parameters and response of a route.
+func parseEndpoint(method, path string, op *operation) (*endpoint, error) {
+	if op.Summary == "" {
+		return nil, fmt.Errorf("%s %s has no summary to name its method", method, path)
+	}
+	e := &endpoint{words: words(op.Summary), method: method, path: path, summary: op.Summary, description: op.Description}
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			e.pathParams = append(e.pathParams, p)
+		case "query":
+			e.query = append(e.query, p)
+		case "header":
+			e.header = append(e.header, p)
+		case "formData":
+			e.form = append(e.form, p)
+		case "body":
+			e.body = &p
+		}
+	}
+	// Writes without a documented body still take one, as not every handler documents its body.
+	if e.body == nil && len(e.form) == 0 && method != "GET" && method != "DELETE" {
+		e.body = &parameter{Name: "body", In: "body"}
+	}
+	// Path parameters follow the order of the path, not of the annotations.
+	slices.SortFunc(e.pathParams, func(a, b parameter) int {
+		return strings.Index(path, "{"+a.Name+"}") - strings.Index(path, "{"+b.Name+"}")
+	})
+
+	statuses := make([]string, 0, len(op.Responses))
+	for status := range op.Responses {
+		if strings.HasPrefix(status, "2") {
+			statuses = append(statuses, status)
+		}
+	}
+	slices.Sort(statuses)
+	if len(statuses) == 0 || op.Responses[statuses[0]].Schema == nil || statuses[0] == "204" {
+		return e, nil
+	}
+	e.schema = op.Responses[statuses[0]].Schema
+	switch {
+	case e.schema.Type == "file":
+		e.result = resultBinary
+	case len(op.Produces) > 0 && !slices.Contains(op.Produces, "application/json"):
+		e.result = resultText
+		if strings.HasPrefix(op.Produces[0], "image/") {
+			e.result = resultBinary
+		}
+	default:
+		e.result = resultJSON
+	}
+	return e, nil
+}
+
+// typeName returns the name of a definition without its package, e.g. ShapeChangeRoleShape for
+// disgm.ShapeChange-disgm_RoleShape.
+func typeName(definition string) string {
+	_, name, _ := strings.Cut(definition, ".")
+	var b strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if _, arg, ok := strings.Cut(part, "_"); ok {
+			part = arg
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// words splits a summary or parameter name into lowercase words, e.g. "Get Admin Guilds" into
+// get, admin and guilds.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// pascal joins words in PascalCase, e.g. GetAdminGuilds.
+func pascal(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// camel joins words in camelCase, e.g. getAdminGuilds.
+func camel(ws []string) string {
+	name := pascal(strings.Join(ws, " "))
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// snake joins words in snake_case, e.g. get_admin_guilds.
+func snake(ws []string) string {
+	return strings.Join(ws, "_")
+}
+
+// definition returns the definition a reference points to, e.g. disgm.Role for
+// #/definitions/disgm.Role.
+func definition(ref string) string {
+	return strings.TrimPrefix(ref, "#/definitions/")
+}
+
+// sortedProperties returns the property names of an object schema in order.
+func sortedProperties(s *schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// identifier reports whether a name can be used unquoted as a property or parameter.
+func identifier(name string) bool {
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// oneLine joins the lines of a description, for doc comments.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
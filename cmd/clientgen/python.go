@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// pyRuntime is the part of the Python client shared by all methods.
+const pyRuntime = `class DisgmError(Exception):
+    """Raised for responses with an error status, with the body disgm sent."""
+
+    def __init__(self, status: int, body: str):
+        super().__init__(f"disgm: {status} {body}")
+        self.status = status
+        self.body = body
+
+
+def _query_value(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def _multipart(form: Dict[str, Any]) -> Tuple[bytes, str]:
+    boundary = uuid.uuid4().hex
+    parts = []
+    for key, value in form.items():
+        if value is None:
+            continue
+        if isinstance(value, bytes):
+            header = f'Content-Disposition: form-data; name="{key}"; filename="{key}"\r\nContent-Type: application/octet-stream'
+        else:
+            header, value = f'Content-Disposition: form-data; name="{key}"', str(value).encode()
+        parts.append(f"--{boundary}\r\n{header}\r\n\r\n".encode() + value + b"\r\n")
+    return b"".join(parts) + f"--{boundary}--\r\n".encode(), f"multipart/form-data; boundary={boundary}"
+
+
+class DisgmClient:
+    """Calls the disgm API of a guild with a token."""
+
+    def __init__(self, base_url: str, token: str, timeout: float = 30):
+        self.base_url = base_url.rstrip("/")
+        self.token = token
+        self.timeout = timeout
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        query: Optional[Dict[str, Any]] = None,
+        headers: Optional[Dict[str, Optional[str]]] = None,
+        body: Any = None,
+        form: Optional[Dict[str, Any]] = None,
+    ) -> bytes:
+        url = self.base_url + path
+        params = {key: _query_value(value) for key, value in (query or {}).items() if value is not None}
+        if params:
+            url += "?" + urllib.parse.urlencode(params)
+        request_headers = {"Authorization": "Bearer " + self.token}
+        request_headers.update({key: value for key, value in (headers or {}).items() if value is not None})
+        data = None
+        if form is not None:
+            data, request_headers["Content-Type"] = _multipart(form)
+        elif body is not None:
+            data = json.dumps(body).encode()
+            request_headers["Content-Type"] = "application/json"
+        request = urllib.request.Request(url, data=data, method=method, headers=request_headers)
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout) as response:
+                return response.read()
+        except urllib.error.HTTPError as err:
+            raise DisgmError(err.code, err.read().decode("utf-8", "replace")) from None
+`
+
+// pyKeywords are the reserved words that cannot name a parameter.
+var pyKeywords = []string{"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield"}
+
+// python generates the Python client, a TypedDict per type and a DisgmClient class with a method
+// per route, using only the standard library.
+func python(a *api) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Code generated by go run ./cmd/clientgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "\"\"\"Client of the %s %s.\"\"\"\n\n", a.title, a.version)
+	b.WriteString("from __future__ import annotations\n\n")
+	b.WriteString("import json\nimport urllib.error\nimport urllib.parse\nimport urllib.request\nimport uuid\n")
+	b.WriteString("from typing import Any, Dict, List, Optional, Tuple, TypedDict\n\n")
+
+	// The functional syntax allows property names that are not identifiers; the types are quoted
+	// as they may refer to types defined later.
+	for _, t := range a.types {
+		if t.schema.Type != "object" || len(t.schema.Properties) == 0 {
+			fmt.Fprintf(&b, "\n%s = %s\n", t.name, pyType(a, t.schema))
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s = TypedDict(\n    %q,\n    {\n", t.name, t.name)
+		for _, name := range sortedProperties(t.schema) {
+			property := t.schema.Properties[name]
+			comment := ""
+			if property.Description != "" {
+				comment = "  # " + oneLine(property.Description)
+			}
+			fmt.Fprintf(&b, "        %q: %q,%s\n", name, pyType(a, property), comment)
+		}
+		b.WriteString("    },\n    total=False,\n)\n")
+	}
+
+	b.WriteString("\n\n" + pyRuntime)
+	for _, e := range a.endpoints {
+		b.WriteString("\n")
+		pyMethod(&b, a, e)
+	}
+	return b.String()
+}
+
+// pyMethod writes the method of a route. Path parameters come first, then the body or form
+// fields, then the query and header parameters as keyword arguments.
+func pyMethod(b *strings.Builder, a *api, e *endpoint) {
+	params := []string{"self"}
+	for _, p := range e.pathParams {
+		params = append(params, pyParam(p.Name)+": "+pyType(a, p.schema()))
+	}
+	if e.body != nil {
+		param := "body: " + pyType(a, e.body.schema())
+		if !e.body.Required {
+			param = "body: Optional[" + pyType(a, e.body.schema()) + "] = None"
+		}
+		params = append(params, param)
+	}
+	keywords := slices.Concat(e.form, e.query, e.header)
+	// Required keyword arguments first, for readability.
+	slices.SortStableFunc(keywords, func(a, b parameter) int {
+		switch {
+		case a.Required == b.Required:
+			return 0
+		case a.Required:
+			return -1
+		}
+		return 1
+	})
+	if len(keywords) > 0 {
+		params = append(params, "*")
+	}
+	var form, query, header []string
+	for _, p := range keywords {
+		param := pyParam(p.Name) + ": " + pyType(a, p.schema())
+		if !p.Required {
+			param = pyParam(p.Name) + ": Optional[" + pyType(a, p.schema()) + "] = None"
+		}
+		params = append(params, param)
+		value := fmt.Sprintf("%q: %s", p.Name, pyParam(p.Name))
+		switch p.In {
+		case "formData":
+			form = append(form, value)
+		case "query":
+			query = append(query, value)
+		default:
+			header = append(header, value)
+		}
+	}
+
+	path := fmt.Sprintf("%q", e.path)
+	if len(e.pathParams) > 0 {
+		path = e.path
+		for _, p := range e.pathParams {
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", "{urllib.parse.quote(str("+pyParam(p.Name)+"), safe='')}")
+		}
+		path = `f"` + path + `"`
+	}
+	request := []string{fmt.Sprintf("%q", e.method), path}
+	if len(query) > 0 {
+		request = append(request, "query={"+strings.Join(query, ", ")+"}")
+	}
+	if len(header) > 0 {
+		request = append(request, "headers={"+strings.Join(header, ", ")+"}")
+	}
+	if e.body != nil {
+		request = append(request, "body=body")
+	}
+	if len(form) > 0 {
+		request = append(request, "form={"+strings.Join(form, ", ")+"}")
+	}
+	call := "self._request(" + strings.Join(request, ", ") + ")"
+
+	var returns, read string
+	switch e.result {
+	case resultNone:
+		returns, read = "None", call
+	case resultJSON:
+		returns, read = pyType(a, e.schema), "return json.loads("+call+")"
+	case resultText:
+		returns, read = "str", "return "+call+".decode()"
+	case resultBinary:
+		returns, read = "bytes", "return "+call
+	}
+
+	doc := e.summary + "."
+	if e.description != "" {
+		doc += " " + oneLine(e.description)
+	}
+	fmt.Fprintf(b, "    def %s(%s) -> %s:\n", snake(e.words), strings.Join(params, ", "), returns)
+	fmt.Fprintf(b, "        \"\"\"%s\"\"\"\n", strings.ReplaceAll(doc, `"""`, `'''`))
+	fmt.Fprintf(b, "        %s\n", read)
+}
+
+// pyType returns the Python type of a schema. Inline objects are dictionaries.
+func pyType(a *api, s *schema) string {
+	switch {
+	case s == nil:
+		return "Any"
+	case s.Ref != "":
+		return a.typeNames[definition(s.Ref)]
+	case len(s.AllOf) > 0:
+		return pyType(a, s.AllOf[0]) // TypedDicts cannot be intersected; the first is the base.
+	}
+
+	switch s.Type {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "file":
+		return "bytes"
+	case "array":
+		return "List[" + pyType(a, s.Items) + "]"
+	case "object":
+		if values, ok := s.values(); ok {
+			return "Dict[str, " + pyType(a, values) + "]"
+		}
+		return "Dict[str, Any]"
+	}
+	return "Any"
+}
+
+// pyParam returns the name of a parameter, e.g. if_match for If-Match.
+func pyParam(name string) string {
+	param := snake(words(name))
+	if slices.Contains(pyKeywords, param) {
+		param += "_"
+	}
+	return param
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tsRuntime is the part of the TypeScript client shared by all methods.
+const tsRuntime = `/** DisgmError is thrown for responses with an error status, with the body disgm sent. */
+export class DisgmError extends Error {
+  readonly status: number;
+  readonly body: string;
+
+  constructor(status: number, body: string) {
+    super(` + "`disgm: ${status} ${body}`" + `);
+    this.status = status;
+    this.body = body;
+  }
+}
+
+interface RequestOptions {
+  query?: Record<string, string | number | boolean | undefined>;
+  headers?: Record<string, string | undefined>;
+  body?: unknown;
+  form?: Record<string, string | Blob | undefined>;
+}
+
+/** DisgmClient calls the disgm API of a guild with a token. */
+export class DisgmClient {
+  private readonly baseUrl: string;
+  private readonly token: string;
+  private readonly fetchFn: typeof fetch;
+
+  constructor(baseUrl: string, token: string, fetchFn: typeof fetch = globalThis.fetch.bind(globalThis)) {
+    this.baseUrl = baseUrl;
+    this.token = token;
+    this.fetchFn = fetchFn;
+  }
+
+  private async request(method: string, path: string, options: RequestOptions = {}): Promise<Response> {
+    const url = new URL(path, this.baseUrl);
+    for (const [key, value] of Object.entries(options.query ?? {})) {
+      if (value !== undefined) url.searchParams.set(key, String(value));
+    }
+    const headers: Record<string, string> = { Authorization: ` + "`Bearer ${this.token}`" + ` };
+    for (const [key, value] of Object.entries(options.headers ?? {})) {
+      if (value !== undefined) headers[key] = value;
+    }
+    let body: BodyInit | undefined;
+    if (options.form) {
+      const form = new FormData();
+      for (const [key, value] of Object.entries(options.form)) {
+        if (value !== undefined) form.append(key, value);
+      }
+      body = form;
+    } else if (options.body !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(options.body);
+    }
+    const response = await this.fetchFn(url, { method, headers, body });
+    if (!response.ok) throw new DisgmError(response.status, await response.text());
+    return response;
+  }
+`
+
+// tsKeywords are the reserved words that cannot name a parameter.
+var tsKeywords = []string{"break", "case", "catch", "class", "const", "continue", "default", "delete", "do", "else", "enum", "export", "extends", "false", "finally", "for", "function", "if", "import", "in", "instanceof", "new", "null", "return", "super", "switch", "this", "throw", "true", "try", "typeof", "var", "void", "while", "with"}
+
+// typescript generates the TypeScript client, an interface per type and a DisgmClient class with
+// a method per route, using fetch.
+func typescript(a *api) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by go run ./cmd/clientgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "// Client of the %s %s.\n\n", a.title, a.version)
+
+	for _, t := range a.types {
+		fmt.Fprintf(&b, "export type %s = %s;\n\n", t.name, tsType(a, t.schema, ""))
+	}
+
+	b.WriteString(tsRuntime)
+	for _, e := range a.endpoints {
+		b.WriteString("\n")
+		tsMethod(&b, a, e)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// tsMethod writes the method of a route. Path parameters come first, then the body or form, then
+// an object with the query and header parameters.
+func tsMethod(b *strings.Builder, a *api, e *endpoint) {
+	var params []string
+	for _, p := range e.pathParams {
+		params = append(params, tsParam(p.Name)+": "+tsType(a, p.schema(), ""))
+	}
+	if e.body != nil {
+		params = append(params, "body"+optional(e.body.Required)+": "+tsType(a, e.body.schema(), ""))
+	}
+	if len(e.form) > 0 {
+		var fields []string
+		for _, p := range e.form {
+			fields = append(fields, tsProperty(p.Name)+optional(p.Required)+": "+tsType(a, p.schema(), ""))
+		}
+		params = append(params, "form: { "+strings.Join(fields, "; ")+" }")
+	}
+	var options, query, header []string
+	for _, p := range slices.Concat(e.query, e.header) {
+		options = append(options, tsProperty(p.Name)+optional(p.Required)+": "+tsType(a, p.schema(), ""))
+		value := tsProperty(p.Name) + ": options" + tsAccess(p.Name)
+		if p.In == "query" {
+			query = append(query, value)
+		} else {
+			header = append(header, value)
+		}
+	}
+	if len(options) > 0 {
+		required := slices.ContainsFunc(slices.Concat(e.query, e.header), func(p parameter) bool { return p.Required })
+		param := "options: { " + strings.Join(options, "; ") + " }"
+		if !required {
+			param += " = {}"
+		}
+		params = append(params, param)
+	}
+
+	var returns, read string
+	switch e.result {
+	case resultNone:
+		returns = "void"
+	case resultJSON:
+		returns, read = tsType(a, e.schema, ""), "response.json()"
+	case resultText:
+		returns, read = "string", "response.text()"
+	case resultBinary:
+		returns, read = "Blob", "response.blob()"
+	}
+
+	path := e.path
+	for _, p := range e.pathParams {
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", "${encodeURIComponent("+tsParam(p.Name)+")}")
+	}
+	var request []string
+	if len(query) > 0 {
+		request = append(request, "query: { "+strings.Join(query, ", ")+" }")
+	}
+	if len(header) > 0 {
+		request = append(request, "headers: { "+strings.Join(header, ", ")+" }")
+	}
+	if e.body != nil {
+		request = append(request, "body")
+	}
+	if len(e.form) > 0 {
+		request = append(request, "form")
+	}
+	call := fmt.Sprintf("this.request(%q, `%s`", e.method, path)
+	if len(request) > 0 {
+		call += ", { " + strings.Join(request, ", ") + " }"
+	}
+	call += ")"
+
+	doc := e.summary + "."
+	if e.description != "" {
+		doc += " " + oneLine(e.description)
+	}
+	fmt.Fprintf(b, "  /** %s */\n", doc)
+	fmt.Fprintf(b, "  async %s(%s): Promise<%s> {\n", camel(e.words), strings.Join(params, ", "), returns)
+	if e.result == resultNone {
+		fmt.Fprintf(b, "    await %s;\n", call)
+	} else {
+		fmt.Fprintf(b, "    const response = await %s;\n", call)
+		fmt.Fprintf(b, "    return %s;\n", read)
+	}
+	b.WriteString("  }\n")
+}
+
+// tsType returns the TypeScript type of a schema, indented for nested objects.
+func tsType(a *api, s *schema, indent string) string {
+	switch {
+	case s == nil:
+		return "unknown"
+	case s.Ref != "":
+		return a.typeNames[definition(s.Ref)]
+	case len(s.AllOf) > 0:
+		types := make([]string, len(s.AllOf))
+		for i, part := range s.AllOf {
+			types[i] = tsType(a, part, indent)
+		}
+		return strings.Join(types, " & ")
+	}
+
+	switch s.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "file":
+		return "Blob"
+	case "array":
+		item := tsType(a, s.Items, indent)
+		if strings.ContainsAny(item, " |&") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	case "object":
+		if values, ok := s.values(); ok {
+			return "Record<string, " + tsType(a, values, indent) + ">"
+		}
+		if len(s.Properties) == 0 {
+			return "Record<string, unknown>"
+		}
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, name := range sortedProperties(s) {
+			property := s.Properties[name]
+			if property.Description != "" {
+				fmt.Fprintf(&b, "%s  /** %s */\n", indent, oneLine(property.Description))
+			}
+			fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, tsProperty(name), optional(slices.Contains(s.Required, name)), tsType(a, property, indent+"  "))
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	}
+	return "unknown"
+}
+
+// optional returns the marker of optional properties and parameters.
+func optional(required bool) string {
+	if required {
+		return ""
+	}
+	return "?"
+}
+
+// tsProperty returns a property name, quoted unless it is an identifier.
+func tsProperty(name string) string {
+	if identifier(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// tsAccess returns the access of a property, e.g. .limit or ["If-Match"].
+func tsAccess(name string) string {
+	if identifier(name) {
+		return "." + name
+	}
+	return fmt.Sprintf("[%q]", name)
+}
+
+// tsParam returns the name of a parameter, e.g. channelId for channel_id.
+func tsParam(name string) string {
+	param := camel(words(name))
+	if slices.Contains(tsKeywords, param) {
+		param += "_"
+	}
+	return param
+}
@@ -0,0 +1,121 @@
+package disgm
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CoalescedHeader marks the responses shared from an identical GET request that was in flight.
+const CoalescedHeader = "X-Disgm-Coalesced"
+
+// coalescedResponse is the response of a GET request, shared with the identical requests that
+// arrived while it was in flight.
+type coalescedResponse struct {
+	done    chan struct{} // Closed once the response is complete
+	status  int
+	body    []byte
+	headers [][2]string // Headers set by the handler
+	err     error
+	stream  bool // Whether the body was streamed and cannot be shared
+}
+
+// The GET requests in flight, keyed by coalescingKey.
+var inflight = struct {
+	sync.Mutex
+	requests map[string]*coalescedResponse
+}{requests: make(map[string]*coalescedResponse)}
+
+// coalescingKey returns the key of a GET request. Requests share a response only if they have
+// the same token, and so the same guild and scope, URL and content negotiation headers.
+func coalescingKey(c *fiber.Ctx) string {
+	token, _ := c.Locals("Token").(string)
+	return strings.Join([]string{
+		token,
+		c.OriginalURL(),
+		c.Get(fiber.HeaderAccept),
+		c.Get(fiber.HeaderAcceptEncoding),
+		c.Get(fiber.HeaderIfNoneMatch),
+	}, "\x00")
+}
+
+// responseHeaders returns the headers of a response, except those written per connection.
+func responseHeaders(c *fiber.Ctx) [][2]string {
+	var headers [][2]string
+	c.Response().Header.VisitAll(func(key, value []byte) {
+		switch string(key) {
+		case fiber.HeaderContentLength, fiber.HeaderSetCookie, fiber.HeaderDate, fiber.HeaderServer:
+			return
+		}
+		headers = append(headers, [2]string{string(key), string(value)})
+	})
+	return headers
+}
+
+// CoalescingMiddleware lets concurrent identical GET requests share one response.
+//
+// When several requests for the same API resource arrive at once, e.g. the widgets of a
+// dashboard loading the channels of a guild, only the first is handled and calls Discord; the
+// others wait for it and receive a copy of its response with the CoalescedHeader set. Requests
+// are only coalesced while one is in flight, so no response is served stale. Streamed responses
+// are not shared; the waiting requests are then handled on their own.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context holding the token under the key "Token".
+//
+// Returns:
+//   - The shared response, or the result of the next handler for the first request.
+func CoalescingMiddleware(c *fiber.Ctx) error {
+	if c.Method() != fiber.MethodGet || !strings.HasPrefix(c.Path(), "/api/") {
+		return c.Next()
+	}
+	key := coalescingKey(c)
+
+	inflight.Lock()
+	if r, ok := inflight.requests[key]; ok {
+		inflight.Unlock()
+		<-r.done
+		if r.stream {
+			return c.Next()
+		}
+		for _, h := range r.headers {
+			c.Set(h[0], h[1])
+		}
+		c.Set(CoalescedHeader, "true")
+		c.Status(r.status)
+		c.Response().SetBody(r.body)
+		return r.err
+	}
+	r := &coalescedResponse{done: make(chan struct{})}
+	inflight.requests[key] = r
+	inflight.Unlock()
+
+	// Headers set by earlier middleware, e.g. the quota of the tenant, belong to each request.
+	before := make(map[string]string)
+	for _, h := range responseHeaders(c) {
+		before[h[0]] = h[1]
+	}
+
+	defer func() {
+		inflight.Lock()
+		delete(inflight.requests, key)
+		inflight.Unlock()
+		close(r.done)
+	}()
+
+	r.err = c.Next()
+
+	r.status = c.Response().StatusCode()
+	r.stream = c.Response().IsBodyStream()
+	if !r.stream {
+		r.body = bytes.Clone(c.Response().Body())
+	}
+	for _, h := range responseHeaders(c) {
+		if value, ok := before[h[0]]; !ok || value != h[1] {
+			r.headers = append(r.headers, h)
+		}
+	}
+	return r.err
+}
@@ -0,0 +1,85 @@
+package disgm
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// channelVersion returns the ETag of the editable settings of a channel. Positions are left out,
+// as reordering other channels shifts them, and so are fields like the last message ID, which
+// change without an edit.
+func channelVersion(ch *discordgo.Channel) string {
+	// The state keeps empty lists where REST responses may omit them.
+	overwrites := ch.PermissionOverwrites
+	if overwrites == nil {
+		overwrites = []*discordgo.PermissionOverwrite{}
+	}
+	tags := ch.AvailableTags
+	if tags == nil {
+		tags = []discordgo.ForumTag{}
+	}
+
+	version := struct {
+		Name                 string                           `json:"name"`
+		Topic                string                           `json:"topic"`
+		NSFW                 bool                             `json:"nsfw"`
+		Bitrate              int                              `json:"bitrate"`
+		UserLimit            int                              `json:"user_limit"`
+		ParentID             string                           `json:"parent_id"`
+		RateLimitPerUser     int                              `json:"rate_limit_per_user"`
+		PermissionOverwrites []*discordgo.PermissionOverwrite `json:"permission_overwrites"`
+		Flags                discordgo.ChannelFlags           `json:"flags"`
+		ThreadMetadata       *discordgo.ThreadMetadata        `json:"thread_metadata"`
+		AvailableTags        []discordgo.ForumTag             `json:"available_tags"`
+	}{ch.Name, ch.Topic, ch.NSFW, ch.Bitrate, ch.UserLimit, ch.ParentID, ch.RateLimitPerUser, overwrites, ch.Flags, ch.ThreadMetadata, tags}
+	return etag(version)
+}
+
+// roleVersion returns the ETag of the editable settings of a role, without its position.
+func roleVersion(r *discordgo.Role) string {
+	version := struct {
+		Name         string `json:"name"`
+		Color        int    `json:"color"`
+		Hoist        bool   `json:"hoist"`
+		Permissions  int64  `json:"permissions"`
+		Mentionable  bool   `json:"mentionable"`
+		Icon         string `json:"icon"`
+		UnicodeEmoji string `json:"unicode_emoji"`
+	}{r.Name, r.Color, r.Hoist, r.Permissions, r.Mentionable, r.Icon, r.UnicodeEmoji}
+	return etag(version)
+}
+
+// etag returns a strong ETag of a value, the hash of its JSON encoding.
+func etag(v interface{}) string {
+	data, _ := json.Marshal(v)
+	sum := sha1.Sum(data)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// ifMatch reports whether the If-Match header of a request matches the current ETag of a
+// resource. Requests without the header always match.
+func ifMatch(c *fiber.Ctx, current string) bool {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// preconditionFailed responds with HTTP status 412 (Precondition Failed) for a resource that
+// changed since the client read it, with its current ETag.
+func preconditionFailed(c *fiber.Ctx, current string) error {
+	c.Set(fiber.HeaderETag, current)
+	return c.Status(fiber.StatusPreconditionFailed).SendString("Precondition failed: the resource was changed since it was read")
+}
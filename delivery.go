@@ -0,0 +1,193 @@
+package disgm
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kinds of the sinks events are delivered to.
+const (
+	SinkWebSocket = "websocket" // A connected WebSocket client, named after the name it identified with.
+	SinkWebhook   = "webhook"   // A webhook URL, named after its host, e.g. of event replays and policy alerts.
+)
+
+const (
+	deliveryWindow       = 24 * time.Hour // Time the delivery report covers.
+	maxDeliveryErrorSize = 200            // Length errors are truncated to in the report.
+)
+
+// deliveryKey identifies a sink of a guild.
+type deliveryKey struct {
+	guildID string
+	kind    string
+	name    string
+}
+
+// sinkDeliveries counts the deliveries of a sink, in total since disgm started for the metrics and
+// by hour for the report.
+type sinkDeliveries struct {
+	delivered   uint64
+	failed      uint64
+	hours       map[int64]*[2]int // Delivered and failed by Unix hour
+	lastError   string
+	lastFailure time.Time
+}
+
+// The deliveries of events to the sinks of the guilds.
+var deliveries = struct {
+	sync.Mutex
+	sinks map[deliveryKey]*sinkDeliveries
+}{sinks: make(map[deliveryKey]*sinkDeliveries)}
+
+// SinkDelivery summarizes the deliveries of events to a sink of the guild.
+type SinkDelivery struct {
+	Kind        string     `json:"kind"`                   // websocket or webhook
+	Name        string     `json:"name"`                   // Client name of WebSocket clients, "unnamed" if they set none; host of webhooks
+	Delivered   int        `json:"delivered"`              // Events delivered in the window
+	Failed      int        `json:"failed"`                 // Events that failed to be delivered in the window
+	SuccessRate float64    `json:"success_rate"`           // Share of delivered events
+	LastError   string     `json:"last_error,omitempty"`   // Error of the latest failure
+	LastFailure *time.Time `json:"last_failure,omitempty"` // When the latest failure happened
+}
+
+// DeliveryReport summarizes the event deliveries of the guild over the last 24 hours.
+type DeliveryReport struct {
+	Since     time.Time      `json:"since"`     // Start of the window
+	Delivered int            `json:"delivered"` // Events delivered to all sinks
+	Failed    int            `json:"failed"`    // Events that failed to be delivered to all sinks
+	Sinks     []SinkDelivery `json:"sinks"`     // Sinks with deliveries in the window, most failures first
+}
+
+// webhookSinkName names a webhook sink after the host of its URL, leaving out the path, which
+// holds the token of Discord webhooks.
+func webhookSinkName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "invalid"
+	}
+	return u.Host
+}
+
+// clientSinkName names the sink of a WebSocket client after the name it identified with, so its
+// deliveries add up across reconnects.
+func clientSinkName(info *WSClient) string {
+	if info == nil || info.Name == "" {
+		return "unnamed"
+	}
+	return info.Name
+}
+
+// recordDelivery counts the delivery of an event to a sink, failed if err is not nil.
+func recordDelivery(guildID, kind, name string, err error) {
+	deliveries.Lock()
+	defer deliveries.Unlock()
+
+	key := deliveryKey{guildID, kind, name}
+	d, ok := deliveries.sinks[key]
+	if !ok {
+		d = &sinkDeliveries{hours: make(map[int64]*[2]int)}
+		deliveries.sinks[key] = d
+	}
+
+	hour := time.Now().Unix() / 3600
+	counts, ok := d.hours[hour]
+	if !ok {
+		counts = &[2]int{}
+		d.hours[hour] = counts
+		for h := range d.hours {
+			if h <= hour-int64(deliveryWindow/time.Hour) {
+				delete(d.hours, h)
+			}
+		}
+	}
+
+	if err == nil {
+		d.delivered++
+		counts[0]++
+		return
+	}
+	d.failed++
+	counts[1]++
+	d.lastError = err.Error()
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		d.lastError = urlErr.Op + ": " + urlErr.Err.Error() // Leaves out the URL, which may hold a token.
+	}
+	if len(d.lastError) > maxDeliveryErrorSize {
+		d.lastError = d.lastError[:maxDeliveryErrorSize]
+	}
+	d.lastFailure = time.Now().UTC()
+}
+
+// deliveryReport summarizes the deliveries of a guild in the window.
+func deliveryReport(guildID string) DeliveryReport {
+	now := time.Now()
+	oldest := now.Unix()/3600 - int64(deliveryWindow/time.Hour)
+	report := DeliveryReport{Since: now.Add(-deliveryWindow).UTC(), Sinks: []SinkDelivery{}}
+
+	deliveries.Lock()
+	for key, d := range deliveries.sinks {
+		if key.guildID != guildID {
+			continue
+		}
+		sink := SinkDelivery{Kind: key.kind, Name: key.name}
+		for h, counts := range d.hours {
+			if h > oldest {
+				sink.Delivered += counts[0]
+				sink.Failed += counts[1]
+			}
+		}
+		if sink.Delivered+sink.Failed == 0 {
+			continue
+		}
+		if sink.Failed > 0 && now.Sub(d.lastFailure) < deliveryWindow {
+			lastFailure := d.lastFailure
+			sink.LastError, sink.LastFailure = d.lastError, &lastFailure
+		}
+		sink.SuccessRate = 1 - rate(sink.Failed, sink.Delivered+sink.Failed)
+		report.Delivered += sink.Delivered
+		report.Failed += sink.Failed
+		report.Sinks = append(report.Sinks, sink)
+	}
+	deliveries.Unlock()
+
+	slices.SortFunc(report.Sinks, func(a, b SinkDelivery) int {
+		if a.Failed != b.Failed {
+			return b.Failed - a.Failed
+		}
+		if a.Kind != b.Kind {
+			return strings.Compare(a.Kind, b.Kind)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return report
+}
+
+// GetDeliveryReport summarizes the delivery of events to the sinks of the guild.
+//
+// Sinks are the connected WebSocket clients, grouped by the name they identified with, and the
+// webhooks events are posted to, such as event replays and policy alerts, grouped by host. The
+// report covers the last 24 hours by the hour, so a failure drops out of it within an hour of
+// leaving the window.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the report as JSON, listing the sinks with the most failures first.
+// @Summary		Get Delivery Report
+// @Description	Summarize the event deliveries and failures per WebSocket client and webhook sink over the last 24 hours.
+// @Tags			Events
+// @Success		200	{object}	DeliveryReport
+// @Router			/api/guild/delivery-report [get]
+func GetDeliveryReport(c *fiber.Ctx, s *discordgo.Session) error {
+	return c.JSON(deliveryReport(c.Locals("ID").(string)))
+}
@@ -0,0 +1,370 @@
+package disgm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Events a digest can summarize.
+const (
+	DigestJoins      = "joins"      // Members who joined the guild.
+	DigestLeaves     = "leaves"     // Members who left or were removed from the guild.
+	DigestMessages   = "messages"   // Messages sent by members, per channel.
+	DigestModeration = "moderation" // Bans, unbans, kicks and timeouts, read from the audit log.
+)
+
+// Periods of a digest.
+const (
+	DigestDaily  = "daily"  // Posted every day at midnight UTC.
+	DigestWeekly = "weekly" // Posted every Sunday at midnight UTC.
+)
+
+const (
+	digestFlushInterval = time.Minute // Interval in which changed counts are persisted.
+	digestTopChannels   = 5           // Channels listed with their message counts.
+)
+
+// digestEvents are the events a digest summarizes, in the order of the embed.
+var digestEvents = []string{DigestJoins, DigestLeaves, DigestMessages, DigestModeration}
+
+// digestSchedules maps the periods of digests to the schedules of their tasks.
+var digestSchedules = map[string]string{DigestDaily: "@daily", DigestWeekly: "@weekly"}
+
+// errNoDigest is returned by digest tasks of guilds without a digest.
+var errNoDigest = errors.New("the guild has no digest configured")
+
+// DigestSettings configure the summary of a guild's activity posted to a channel.
+type DigestSettings struct {
+	ChannelID string   `json:"channel_id"`       // Channel the summary is posted to
+	Period    string   `json:"period"`           // daily or weekly
+	Events    []string `json:"events,omitempty"` // Summarized events: joins, leaves, messages and moderation, all by default
+}
+
+// validate checks a digest and fills in its default events.
+func (d *DigestSettings) validate() error {
+	if d.ChannelID == "" {
+		return errors.New("digest needs a channel_id")
+	}
+	if digestSchedules[d.Period] == "" {
+		return errors.New("digest period must be daily or weekly")
+	}
+	if len(d.Events) == 0 {
+		d.Events = slices.Clone(digestEvents)
+	}
+	for _, event := range d.Events {
+		if !slices.Contains(digestEvents, event) {
+			return fmt.Errorf("digest has unknown event %q, use %s", event, strings.Join(digestEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// digestCounts are the counted events of a guild since its last digest.
+type digestCounts struct {
+	Since    time.Time      `json:"since"`
+	Joins    int            `json:"joins"`
+	Leaves   int            `json:"leaves"`
+	Messages map[string]int `json:"messages"` // Keyed by channel ID
+	Bans     int            `json:"bans"`
+	Unbans   int            `json:"unbans"`
+	Kicks    int            `json:"kicks"`
+	Timeouts int            `json:"timeouts"`
+}
+
+// digestGuild is the digest of a guild and its counts.
+type digestGuild struct {
+	settings DigestSettings
+	counts   digestCounts
+	dirty    bool // Whether the counts changed since they were persisted
+}
+
+// The digests of the guilds, keyed by guild ID, with their counts persisted in the key-value
+// store. Only the events of configured digests are counted.
+var digests = struct {
+	sync.Mutex
+	storage *Storage
+	guilds  map[string]*digestGuild
+}{guilds: make(map[string]*digestGuild)}
+
+// digestTaskID returns the ID of the scheduled task posting the digest of a guild.
+func digestTaskID(guildID string) string {
+	return "digest-" + guildID
+}
+
+// loadDigests restores the counts of the configured digests and persists changed counts
+// periodically. A guild has counts while it has a digest.
+func loadDigests(storage *Storage) {
+	digests.Lock()
+	defer digests.Unlock()
+
+	digests.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load digests: %v", err)
+		return
+	}
+	for _, guildID := range keys {
+		var guildSettings GuildSettings
+		if err := readSettings(guildID, &guildSettings); err != nil || guildSettings.Digest == nil {
+			continue
+		}
+		data, ok, err := storage.Get(guildID)
+		if err != nil || !ok {
+			continue
+		}
+		g := &digestGuild{settings: *guildSettings.Digest}
+		if err := json.Unmarshal(data, &g.counts); err != nil {
+			log.Printf("Failed to load digest of guild %s: %v", guildID, err)
+			continue
+		}
+		digests.guilds[guildID] = g
+	}
+
+	go func() {
+		for range time.Tick(digestFlushInterval) {
+			flushDigests()
+		}
+	}()
+}
+
+// flushDigests persists the changed counts.
+func flushDigests() {
+	digests.Lock()
+	defer digests.Unlock()
+	for guildID, g := range digests.guilds {
+		if g.dirty {
+			persistDigest(guildID, g)
+		}
+	}
+}
+
+// persistDigest saves the counts of a guild. The caller must hold the digests lock.
+func persistDigest(guildID string, g *digestGuild) {
+	data, err := json.Marshal(g.counts)
+	if err == nil {
+		err = digests.storage.Set(guildID, data)
+	}
+	if err != nil {
+		log.Printf("Failed to persist digest of guild %s: %v", guildID, err)
+		return
+	}
+	g.dirty = false
+}
+
+// configureDigest applies the digest settings of a guild: it starts counting and schedules the
+// task posting the digest, or stops both if the digest is nil. Counts are kept when a digest
+// changes.
+func configureDigest(s *discordgo.Session, guildID string, d *DigestSettings) error {
+	digests.Lock()
+	g, ok := digests.guilds[guildID]
+	switch {
+	case d == nil && ok:
+		delete(digests.guilds, guildID)
+		if err := digests.storage.Delete(guildID); err != nil {
+			log.Printf("Failed to delete digest of guild %s: %v", guildID, err)
+		}
+	case d != nil && ok:
+		g.settings = *d
+	case d != nil:
+		g = &digestGuild{settings: *d, counts: digestCounts{Since: time.Now().UTC(), Messages: make(map[string]int)}}
+		digests.guilds[guildID] = g
+		persistDigest(guildID, g)
+	}
+	digests.Unlock()
+
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	id := digestTaskID(guildID)
+	current, ok := tasks.byID[id]
+	if ok && current.timer != nil {
+		current.timer.Stop()
+	}
+	if d == nil {
+		if !ok {
+			return nil
+		}
+		delete(tasks.byID, id)
+		return tasks.storage.Delete(guildID + "/" + id)
+	}
+
+	t := &Task{ID: id, GuildID: guildID, Name: "Digest", Schedule: digestSchedules[d.Period], Action: TaskDigest}
+	if ok {
+		t.Paused, t.LastRun, t.LastError = current.Paused, current.LastRun, current.LastError
+	}
+	if err := t.prepare(); err != nil {
+		return err
+	}
+	tasks.byID[id] = t
+	scheduleTask(s, t)
+	persistTask(t)
+	return nil
+}
+
+// countDigestEvent updates the counts of a guild if its digest summarizes the event.
+func countDigestEvent(guildID, event string, update func(counts *digestCounts)) {
+	digests.Lock()
+	defer digests.Unlock()
+
+	g, ok := digests.guilds[guildID]
+	if !ok || !slices.Contains(g.settings.Events, event) {
+		return
+	}
+	update(&g.counts)
+	g.dirty = true
+}
+
+// registerDigestHandlers counts the events of the configured digests. Moderation actions are
+// read from the audit log entries Discord sends, which need the GUILD_MODERATION intent and the
+// View Audit Log permission.
+func registerDigestHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+		countDigestEvent(m.GuildID, DigestJoins, func(counts *digestCounts) { counts.Joins++ })
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+		countDigestEvent(m.GuildID, DigestLeaves, func(counts *digestCounts) { counts.Leaves++ })
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if m.GuildID == "" || m.Author == nil || m.Author.Bot || m.WebhookID != "" {
+			return
+		}
+		countDigestEvent(m.GuildID, DigestMessages, func(counts *digestCounts) {
+			if counts.Messages == nil {
+				counts.Messages = make(map[string]int)
+			}
+			counts.Messages[m.ChannelID]++
+		})
+	})
+
+	// discordgo drops the guild ID of audit log entries, so they are read from the raw event.
+	s.AddHandler(func(s *discordgo.Session, e *discordgo.Event) {
+		if e.Type != "GUILD_AUDIT_LOG_ENTRY_CREATE" {
+			return
+		}
+		var entry struct {
+			discordgo.AuditLogEntry
+			GuildID string `json:"guild_id"`
+		}
+		if err := json.Unmarshal(e.RawData, &entry); err != nil || entry.ActionType == nil {
+			return
+		}
+		countDigestEvent(entry.GuildID, DigestModeration, func(counts *digestCounts) {
+			switch *entry.ActionType {
+			case discordgo.AuditLogActionMemberBanAdd:
+				counts.Bans++
+			case discordgo.AuditLogActionMemberBanRemove:
+				counts.Unbans++
+			case discordgo.AuditLogActionMemberKick:
+				counts.Kicks++
+			case discordgo.AuditLogActionMemberUpdate:
+				for _, change := range entry.Changes {
+					if change.Key != nil && *change.Key == discordgo.AuditLogChangeKeyCommunicationDisabledUntil && change.NewValue != nil {
+						counts.Timeouts++
+					}
+				}
+			}
+		})
+	})
+}
+
+// digestEmbed renders the counts of a digest as an embed.
+func digestEmbed(d DigestSettings, counts digestCounts, now time.Time) *discordgo.MessageEmbed {
+	title := "Daily digest"
+	if d.Period == DigestWeekly {
+		title = "Weekly digest"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: fmt.Sprintf("Activity since <t:%d:f>", counts.Since.Unix()),
+		Timestamp:   now.Format(time.RFC3339),
+	}
+
+	for _, event := range digestEvents {
+		if !slices.Contains(d.Events, event) {
+			continue
+		}
+		switch event {
+		case DigestJoins:
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Joins", Value: strconv.Itoa(counts.Joins), Inline: true})
+		case DigestLeaves:
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Leaves", Value: strconv.Itoa(counts.Leaves), Inline: true})
+		case DigestMessages:
+			channels := make([]string, 0, len(counts.Messages))
+			total := 0
+			for channelID, n := range counts.Messages {
+				channels = append(channels, channelID)
+				total += n
+			}
+			slices.SortFunc(channels, func(a, b string) int {
+				if n := counts.Messages[b] - counts.Messages[a]; n != 0 {
+					return n
+				}
+				return strings.Compare(a, b)
+			})
+			value := strconv.Itoa(total)
+			for _, channelID := range channels[:min(len(channels), digestTopChannels)] {
+				value += fmt.Sprintf("\n<#%s>: %d", channelID, counts.Messages[channelID])
+			}
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Messages", Value: value})
+		case DigestModeration:
+			value := fmt.Sprintf("Bans: %d\nUnbans: %d\nKicks: %d\nTimeouts: %d", counts.Bans, counts.Unbans, counts.Kicks, counts.Timeouts)
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Moderation", Value: value})
+		}
+	}
+	return embed
+}
+
+// postDigest posts the digest of a guild to its channel and starts counting anew. The counts are
+// kept if the digest cannot be posted, so the next digest covers the missed period.
+func postDigest(s *discordgo.Session, guildID string) error {
+	digests.Lock()
+	g, ok := digests.guilds[guildID]
+	var d DigestSettings
+	var counts digestCounts
+	if ok {
+		d, counts = g.settings, g.counts
+		counts.Messages = maps.Clone(g.counts.Messages)
+	}
+	digests.Unlock()
+
+	if !ok {
+		return errNoDigest
+	}
+
+	now := time.Now().UTC()
+	if _, err := s.ChannelMessageSendEmbed(d.ChannelID, digestEmbed(d, counts, now)); err != nil {
+		return err
+	}
+
+	digests.Lock()
+	defer digests.Unlock()
+	if g, ok := digests.guilds[guildID]; ok {
+		// Events counted while the digest was posted are kept for the next one.
+		next := digestCounts{Since: now, Messages: make(map[string]int)}
+		next.Joins = g.counts.Joins - counts.Joins
+		next.Leaves = g.counts.Leaves - counts.Leaves
+		next.Bans = g.counts.Bans - counts.Bans
+		next.Unbans = g.counts.Unbans - counts.Unbans
+		next.Kicks = g.counts.Kicks - counts.Kicks
+		next.Timeouts = g.counts.Timeouts - counts.Timeouts
+		for channelID, n := range g.counts.Messages {
+			if n -= counts.Messages[channelID]; n > 0 {
+				next.Messages[channelID] = n
+			}
+		}
+		g.counts = next
+		persistDigest(guildID, g)
+	}
+	return nil
+}
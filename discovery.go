@@ -0,0 +1,266 @@
+package disgm
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxDiscoveryKeywords   = 10 // Maximum number of discovery keywords of a guild.
+	maxDiscoveryKeyword    = 30 // Maximum length of a discovery keyword.
+	maxDiscoveryCategories = 5  // Maximum number of secondary discovery categories of a guild.
+)
+
+// GuildDiscovery is the discovery listing of a guild: its description and discovery metadata.
+type GuildDiscovery struct {
+	Description                 *string  `json:"description"`                   // Description of the guild, shown in its listing
+	PrimaryCategoryID           int      `json:"primary_category_id"`           // ID of the main discovery category
+	CategoryIDs                 []int    `json:"category_ids"`                  // IDs of up to 5 secondary discovery categories
+	Keywords                    []string `json:"keywords"`                      // Up to 10 search keywords, each up to 30 characters
+	EmojiDiscoverabilityEnabled bool     `json:"emoji_discoverability_enabled"` // Whether the guild's emojis can be discovered by users
+	Eligible                    bool     `json:"eligible"`                      // Whether the guild can be listed, which requires the COMMUNITY feature
+	Discoverable                bool     `json:"discoverable"`                  // Whether the guild is listed, with the DISCOVERABLE feature
+}
+
+// GuildDiscoveryParams are the changes to the discovery listing of a guild. Unset fields are kept.
+type GuildDiscoveryParams struct {
+	Description                 *string   `json:"description,omitempty"`                   // Description of the guild
+	PrimaryCategoryID           *int      `json:"primary_category_id,omitempty"`           // ID of the main discovery category
+	CategoryIDs                 *[]int    `json:"category_ids,omitempty"`                  // IDs of the secondary discovery categories, replacing the current ones
+	Keywords                    *[]string `json:"keywords,omitempty"`                      // Search keywords, replacing the current ones
+	EmojiDiscoverabilityEnabled *bool     `json:"emoji_discoverability_enabled,omitempty"` // Whether the guild's emojis can be discovered by users
+}
+
+// discoveryMetadata is the discovery metadata object of the Discord API.
+type discoveryMetadata struct {
+	PrimaryCategoryID           int      `json:"primary_category_id"`
+	Keywords                    []string `json:"keywords"`
+	EmojiDiscoverabilityEnabled bool     `json:"emoji_discoverability_enabled"`
+	CategoryIDs                 []int    `json:"category_ids"`
+}
+
+// validate checks the changes against the limits of discovery listings.
+func (p *GuildDiscoveryParams) validate() string {
+	if p.Keywords != nil {
+		if len(*p.Keywords) > maxDiscoveryKeywords {
+			return "at most 10 keywords are allowed"
+		}
+		for _, k := range *p.Keywords {
+			if k == "" || len(k) > maxDiscoveryKeyword {
+				return "keywords must be 1 to 30 characters long"
+			}
+		}
+	}
+	if p.CategoryIDs != nil && len(*p.CategoryIDs) > maxDiscoveryCategories {
+		return "at most 5 category_ids are allowed"
+	}
+	return ""
+}
+
+// guildDiscovery reads the discovery listing of a guild.
+func guildDiscovery(s *discordgo.Session, guild *discordgo.Guild) (*GuildDiscovery, error) {
+	discovery := &GuildDiscovery{
+		Eligible:     slices.Contains(guild.Features, discordgo.GuildFeatureCommunity),
+		Discoverable: slices.Contains(guild.Features, discordgo.GuildFeatureDiscoverable),
+		CategoryIDs:  []int{},
+		Keywords:     []string{},
+	}
+	if guild.Description != "" {
+		discovery.Description = &guild.Description
+	}
+	if !discovery.Eligible {
+		return discovery, nil // Discord has no discovery metadata for guilds that cannot be listed.
+	}
+
+	endpoint := discordgo.EndpointGuild(guild.ID) + "/discovery-metadata"
+	body, err := s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var metadata discoveryMetadata
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return nil, err
+	}
+
+	discovery.PrimaryCategoryID = metadata.PrimaryCategoryID
+	discovery.EmojiDiscoverabilityEnabled = metadata.EmojiDiscoverabilityEnabled
+	if metadata.Keywords != nil {
+		discovery.Keywords = metadata.Keywords
+	}
+	if metadata.CategoryIDs != nil {
+		discovery.CategoryIDs = metadata.CategoryIDs
+	}
+	return discovery, nil
+}
+
+// GetGuildDiscovery retrieves the discovery listing of the guild.
+//
+// The listing combines the description of the guild with its discovery metadata: categories,
+// keywords and emoji discoverability. Guilds without the COMMUNITY feature cannot be listed and
+// are returned with eligible set to false and empty metadata.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the discovery listing as JSON, with an ETag header for If-Match on updates.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) with an error message.
+// @Summary		Get Guild Discovery
+// @Description	Retrieve the discovery description, categories and keywords of the guild.
+// @Tags			Guild
+// @Success		200	{object}	GuildDiscovery
+// @Failure		500	{object}	error
+// @Router			/api/guild/discovery [get]
+func GetGuildDiscovery(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild: " + err.Error())
+	}
+
+	discovery, err := guildDiscovery(s, guild)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve discovery metadata: " + err.Error())
+	}
+
+	c.Set(fiber.HeaderETag, etag(discovery))
+	return c.JSON(discovery)
+}
+
+// UpdateGuildDiscovery updates the discovery listing of the guild.
+//
+// The description is set on the guild and the other fields on its discovery metadata; the
+// secondary categories are added and removed one by one to match category_ids. Only guilds
+// with the COMMUNITY feature can be listed. Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A GuildDiscoveryParams object; unset fields are kept.
+//
+// Request Headers:
+//   - If-Match: Optional ETag of the listing as read by the client. The update is refused if the
+//     listing was changed since.
+//
+// Returns:
+//   - On success, it returns the updated discovery listing as JSON with its new ETag.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid or exceeds the limits,
+//     HTTP status 403 (Forbidden) for restricted tokens,
+//     HTTP status 409 (Conflict) if the guild is not eligible for discovery,
+//     HTTP status 412 (Precondition Failed) if the listing does not match If-Match,
+//     or HTTP status 500 (Internal Server Error) if the listing cannot be updated.
+// @Summary		Update Guild Discovery
+// @Description	Update the discovery description, categories and keywords of a community guild.
+// @Tags			Guild
+// @Param			body		body		GuildDiscoveryParams	true	"Discovery changes"
+// @Param			If-Match	header		string					false	"ETag of the listing as read"
+// @Success		200			{object}	GuildDiscovery
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		409			{object}	error
+// @Failure		412			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/discovery [patch]
+func UpdateGuildDiscovery(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var params GuildDiscoveryParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if msg := params.validate(); msg != "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + msg)
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild: " + err.Error())
+	}
+	if !slices.Contains(guild.Features, discordgo.GuildFeatureCommunity) {
+		return c.Status(fiber.StatusConflict).SendString("Guild is not eligible for discovery: the COMMUNITY feature is required")
+	}
+	if c.Get(fiber.HeaderIfMatch) != "" {
+		current, err := guildDiscovery(s, guild)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve discovery metadata: " + err.Error())
+		}
+		if version := etag(current); !ifMatch(c, version) {
+			return preconditionFailed(c, version)
+		}
+	}
+
+	if params.Description != nil {
+		endpoint := discordgo.EndpointGuild(guildID)
+		body, err := s.RequestWithBucketID("PATCH", endpoint, map[string]string{"description": *params.Description}, endpoint, auditOptions(c)...)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to update guild description: " + err.Error())
+		}
+		var updated discordgo.Guild // The guild may be the state's, which must not be written to.
+		if err := json.Unmarshal(body, &updated); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to update guild description: " + err.Error())
+		}
+		guild = &updated
+	}
+
+	endpoint := discordgo.EndpointGuild(guildID) + "/discovery-metadata"
+	if params.PrimaryCategoryID != nil || params.Keywords != nil || params.EmojiDiscoverabilityEnabled != nil {
+		metadata := map[string]interface{}{}
+		if params.PrimaryCategoryID != nil {
+			metadata["primary_category_id"] = *params.PrimaryCategoryID
+		}
+		if params.Keywords != nil {
+			metadata["keywords"] = *params.Keywords
+		}
+		if params.EmojiDiscoverabilityEnabled != nil {
+			metadata["emoji_discoverability_enabled"] = *params.EmojiDiscoverabilityEnabled
+		}
+		if _, err := s.RequestWithBucketID("PATCH", endpoint, metadata, endpoint, auditOptions(c)...); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to update discovery metadata: " + err.Error())
+		}
+	}
+
+	if params.CategoryIDs != nil {
+		current, err := guildDiscovery(s, guild)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve discovery metadata: " + err.Error())
+		}
+
+		categories := discordgo.EndpointGuild(guildID) + "/discovery-categories"
+		for _, id := range current.CategoryIDs {
+			if !slices.Contains(*params.CategoryIDs, id) {
+				_, err = s.RequestWithBucketID("DELETE", categories+"/"+strconv.Itoa(id), nil, categories, auditOptions(c)...)
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).SendString("Failed to remove discovery category: " + err.Error())
+			}
+		}
+		for _, id := range *params.CategoryIDs {
+			if !slices.Contains(current.CategoryIDs, id) {
+				_, err = s.RequestWithBucketID("PUT", categories+"/"+strconv.Itoa(id), nil, categories, auditOptions(c)...)
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).SendString("Failed to add discovery category: " + err.Error())
+			}
+		}
+	}
+
+	discovery, err := guildDiscovery(s, guild)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve discovery metadata: " + err.Error())
+	}
+
+	c.Set(fiber.HeaderETag, etag(discovery))
+	return c.JSON(discovery)
+}
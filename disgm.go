@@ -2,33 +2,109 @@ package disgm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/gofiber/swagger"
 	"github.com/rif223/disgm/store"
 
 	_ "github.com/rif223/disgm/docs"
 )
 
+// Generates the TypeScript and Python clients from the OpenAPI document into $DISGM_CLIENT_DIR,
+// or clients if unset. Run swag init first, so the clients include the latest route changes.
+//go:generate go run ./cmd/clientgen -out=$DISGM_CLIENT_DIR
+
 // Options contains the configuration for the disgm package.
 type Options struct {
-	DisableStartupMessage bool
-	DisableLogger         bool
-	TokenStore            store.TokenStore // A map of valid tokens for authentication.
+	DisableStartupMessage   bool
+	DisableLogger           bool
+	TokenStore              store.TokenStore      // A map of valid tokens for authentication.
+	ScopeStore              store.ScopeStore      // Optional channel allowlists for restricted tokens.
+	ActingUserStore         store.ActingUserStore // Optional dashboard users the tokens act for, recorded in audit-log reasons and the API audit log.
+	CookieAuth              bool                  // Accepts the token from the disgm_token cookie and enables CSRF protection.
+	AllowOrigins            string                // Comma-separated list of origins allowed by CORS, defaults to "*".
+	ApprovalActions         []string              // Actions, e.g. ActionChannelDelete, that need approval with an unrelated credential, such as tokens issued for two people with POST /api/guild/tokens.
+	ApprovalTTL             time.Duration         // Time a pending approval stays valid, defaults to 15 minutes.
+	Protection              Protection            // Channels and roles that cannot be modified or deleted without override.
+	UndoRetention           time.Duration         // Time a recorded change can be undone, defaults to 1 hour.
+	AllowCrossGuildRelays   bool                  // Allows message relays into channels of other guilds, once the target guild confirms them.
+	WebSocketChat           bool                  // Lets WebSocket clients send messages with the chat op.
+	ChatRateLimit           int                   // Chat messages a WebSocket client may send per 10 seconds, defaults to 5.
+	WebSocketRelay          bool                  // Lets WebSocket clients of a guild message each other with the relay op.
+	ClientRelayRateLimit    int                   // Relay messages a WebSocket client may send per 10 seconds, defaults to 20.
+	MaxClientRelaySize      int                   // Largest data of a relay message in bytes, defaults to 4096.
+	KVStore                 store.KVStore         // Backend of the plugin and embedder storage, defaults to an in-memory store.
+	UserCacheTTL            time.Duration         // Time a cached user object stays valid, defaults to 10 minutes.
+	UserCacheSize           int                   // Maximum number of cached user objects, defaults to 10000.
+	CacheMaxMemory          int64                 // Estimated memory shared by all caches, in bytes, defaults to 64 MiB.
+	EventBufferSize         int                   // Events recorded per guild for export and replay, defaults to 10000.
+	EventRetention          time.Duration         // Time recorded events are kept, defaults to 24 hours.
+	MessageLog              bool                  // Records guild messages for the message search endpoint.
+	MessageLogRetention     time.Duration         // Time logged messages are kept, defaults to 30 days.
+	MessageIndex            MessageIndex          // Stores and searches the message log, defaults to an index in the KVStore.
+	DisabledModules         []string              // API modules disabled for all guilds, which guilds cannot enable, e.g. ModuleWebhooks.
+	MirrorURL               string                // Base URL incoming API requests are copied to asynchronously, e.g. a staging instance.
+	FaultInjection          []FaultRule           // Errors, delays and rate limits injected into API requests, for testing only.
+	JSONEncoder             utils.JSONMarshal     // Encodes API responses and WebSocket events, e.g. sonic.Marshal, defaults to encoding/json.
+	JSONDecoder             utils.JSONUnmarshal   // Decodes API request bodies, e.g. sonic.Unmarshal, defaults to encoding/json.
+	Prefork                 bool                  // Not supported yet and rejected by New: every process would run its own gateway session, schedulers and state.
+	Concurrency             int                   // Maximum number of concurrent connections, defaults to Fiber's 256 * 1024.
+	ReadBufferSize          int                   // Per-connection buffer for reading requests, limits the header size, defaults to 4096.
+	WriteBufferSize         int                   // Per-connection buffer for writing responses, defaults to 4096.
+	ReadTimeout             time.Duration         // Time allowed to read a request, unlimited by default.
+	WriteTimeout            time.Duration         // Time allowed to write a response, unlimited by default.
+	IdleTimeout             time.Duration         // Time keep-alive connections wait for the next request, defaults to ReadTimeout.
+	StrictIntents           bool                  // Fails New if the session lacks gateway intents needed by enabled features, instead of logging them.
+	UserInstallGuild        string                // Guild whose WebSocket clients receive interactions of user-installed commands outside of the bot's guilds, e.g. in DMs.
+	TokenRevalidateInterval time.Duration         // Interval in which the tokens of WebSocket connections are checked against the token store, defaults to 1 minute.
+	WebSocketOrigins        []string              // Origins browsers may open WebSocket connections from, e.g. "https://*.example.com", defaults to AllowOrigins with CookieAuth and to all otherwise.
+	ErrorBudget             ErrorBudget           // Window and thresholds of the error rates reported by the status endpoint and readiness probe.
+	TenantResolver          store.TenantResolver  // Resolves the tenant of each token, whose plan sets rate limits, quotas and the included modules.
+	AdminTokens             []string              // Tokens for the cross-guild admin routes below /api/admin, e.g. for support staff.
+	DisableCoalescing       bool                  // Handles every GET request on its own instead of sharing the response of an identical request in flight.
+	PrefetchOnConnect       bool                  // Sends WebSocket clients a READY snapshot of the guild, its channels, roles and recent messages when they connect.
+	PrefetchMessages        int                   // Recent messages per channel in the READY snapshot, up to 100, defaults to 20.
+	ProxyHeader             string                // Header the client IP is read from behind a proxy, e.g. "CF-Connecting-IP" or "X-Real-IP", defaults to "X-Forwarded-For".
+	TrustedProxies          []string              // IPs and CIDR ranges of the proxies whose ProxyHeader is trusted; without any, the header of every request is trusted.
+	EventThrottles          map[string]int        // Events of a type forwarded per second and guild, e.g. {"TYPING_START": 10}; the rest are dropped and counted in the status.
+	Projections             bool                  // Keeps read models of the guilds in memory from gateway events: the member directory and channel tree.
+	RequireEncryption       bool                  // Refuses WebSocket clients and webhook replays without a public key their events are sealed for, for TLS ending on untrusted infrastructure.
+	PublicURL               string                // Base URL clients reach the server at, e.g. "https://bot.example.com/disgm", used by the swagger doc; defaults to the host of each request.
+	Limits                  map[string]ListLimit  // Default and maximum sizes of the list endpoints by resource, e.g. {LimitMembers: {Default: 100, Max: 500}}; unset ones keep their built-in sizes.
 }
 
 // defaultOptions defines the default configuration for the disgm package.
 var defaultOptions = Options{
-	DisableStartupMessage: false,
-	DisableLogger:         false,
+	DisableStartupMessage:   false,
+	DisableLogger:           false,
+	AllowOrigins:            "*",
+	ApprovalTTL:             15 * time.Minute,
+	UndoRetention:           time.Hour,
+	ChatRateLimit:           5,
+	ClientRelayRateLimit:    20,
+	MaxClientRelaySize:      4096,
+	UserCacheTTL:            10 * time.Minute,
+	UserCacheSize:           10000,
+	CacheMaxMemory:          64 << 20,
+	EventBufferSize:         10000,
+	EventRetention:          24 * time.Hour,
+	MessageLogRetention:     30 * 24 * time.Hour,
+	TokenRevalidateInterval: time.Minute,
+	PrefetchMessages:        20,
+	ProxyHeader:             fiber.HeaderXForwardedFor,
+	ErrorBudget:             ErrorBudget{Window: 5 * time.Minute, MinRequests: 20},
 }
 
 // Disgm is the main structure for the package, containing the Discord session and the Fiber server.
@@ -36,6 +112,8 @@ type Disgm struct {
 	opt   *Options           // Options for the application.
 	s     *discordgo.Session // The DiscordGo session for interacting with the Discord API.
 	fiber *fiber.App         // The Fiber application for the web server.
+
+	plugins map[string]Plugin // Registered plugins by name.
 }
 
 // New creates a new instance of Disgm with the specified DiscordGo session and options.
@@ -51,10 +129,10 @@ type Disgm struct {
 // @title			Discord Guild Management API
 // @version		1.0
 // @description	API for managing Discord guilds using DiscordGo and Fiber.
-// @host			localhost:90
 func New(s *discordgo.Session, options ...Options) (d *Disgm, err error) {
 
-	opt := &defaultOptions
+	defaults := defaultOptions // Copied, so the options of a failed or earlier New do not leak into the next.
+	opt := &defaults
 
 	if len(options) > 0 {
 		o := options[0] // Gets the custom options.
@@ -68,12 +146,312 @@ func New(s *discordgo.Session, options ...Options) (d *Disgm, err error) {
 		if o.DisableLogger {
 			opt.DisableLogger = o.DisableLogger
 		}
+		if o.ScopeStore != nil {
+			opt.ScopeStore = o.ScopeStore // Sets the token scopes if specified.
+		}
+		if o.ActingUserStore != nil {
+			opt.ActingUserStore = o.ActingUserStore // Sets the acting users if specified.
+		}
+		if o.CookieAuth {
+			opt.CookieAuth = o.CookieAuth
+		}
+		if o.AllowOrigins != "" {
+			opt.AllowOrigins = o.AllowOrigins
+		}
+		if len(o.ApprovalActions) > 0 {
+			opt.ApprovalActions = o.ApprovalActions
+		}
+		if o.ApprovalTTL > 0 {
+			opt.ApprovalTTL = o.ApprovalTTL
+		}
+		opt.Protection = o.Protection
+		if o.UndoRetention > 0 {
+			opt.UndoRetention = o.UndoRetention
+		}
+		if o.AllowCrossGuildRelays {
+			opt.AllowCrossGuildRelays = o.AllowCrossGuildRelays
+		}
+		if o.WebSocketChat {
+			opt.WebSocketChat = o.WebSocketChat
+		}
+		if o.ChatRateLimit > 0 {
+			opt.ChatRateLimit = o.ChatRateLimit
+		}
+		if o.WebSocketRelay {
+			opt.WebSocketRelay = o.WebSocketRelay
+		}
+		if o.ClientRelayRateLimit > 0 {
+			opt.ClientRelayRateLimit = o.ClientRelayRateLimit
+		}
+		if o.MaxClientRelaySize > 0 {
+			opt.MaxClientRelaySize = o.MaxClientRelaySize
+		}
+		if o.KVStore != nil {
+			opt.KVStore = o.KVStore
+		}
+		if o.UserCacheTTL > 0 {
+			opt.UserCacheTTL = o.UserCacheTTL
+		}
+		if o.UserCacheSize > 0 {
+			opt.UserCacheSize = o.UserCacheSize
+		}
+		if o.CacheMaxMemory > 0 {
+			opt.CacheMaxMemory = o.CacheMaxMemory
+		}
+		if o.EventBufferSize > 0 {
+			opt.EventBufferSize = o.EventBufferSize
+		}
+		if o.EventRetention > 0 {
+			opt.EventRetention = o.EventRetention
+		}
+		if o.MessageLog {
+			opt.MessageLog = o.MessageLog
+		}
+		if o.MessageLogRetention > 0 {
+			opt.MessageLogRetention = o.MessageLogRetention
+		}
+		if o.MessageIndex != nil {
+			opt.MessageIndex = o.MessageIndex
+		}
+		if len(o.DisabledModules) > 0 {
+			opt.DisabledModules = o.DisabledModules
+		}
+		if o.MirrorURL != "" {
+			opt.MirrorURL = o.MirrorURL
+		}
+		if len(o.FaultInjection) > 0 {
+			opt.FaultInjection = o.FaultInjection
+		}
+		if o.JSONEncoder != nil {
+			opt.JSONEncoder = o.JSONEncoder
+		}
+		if o.JSONDecoder != nil {
+			opt.JSONDecoder = o.JSONDecoder
+		}
+		if o.Prefork {
+			opt.Prefork = o.Prefork
+		}
+		if o.Concurrency > 0 {
+			opt.Concurrency = o.Concurrency
+		}
+		if o.ReadBufferSize > 0 {
+			opt.ReadBufferSize = o.ReadBufferSize
+		}
+		if o.WriteBufferSize > 0 {
+			opt.WriteBufferSize = o.WriteBufferSize
+		}
+		if o.ReadTimeout > 0 {
+			opt.ReadTimeout = o.ReadTimeout
+		}
+		if o.WriteTimeout > 0 {
+			opt.WriteTimeout = o.WriteTimeout
+		}
+		if o.IdleTimeout > 0 {
+			opt.IdleTimeout = o.IdleTimeout
+		}
+		if o.StrictIntents {
+			opt.StrictIntents = o.StrictIntents
+		}
+		if o.UserInstallGuild != "" {
+			opt.UserInstallGuild = o.UserInstallGuild
+		}
+		if o.TokenRevalidateInterval > 0 {
+			opt.TokenRevalidateInterval = o.TokenRevalidateInterval
+		}
+		if len(o.WebSocketOrigins) > 0 {
+			opt.WebSocketOrigins = o.WebSocketOrigins
+		}
+		if o.ErrorBudget.Window > 0 {
+			opt.ErrorBudget.Window = o.ErrorBudget.Window
+		}
+		if o.ErrorBudget.MaxErrorRate > 0 {
+			opt.ErrorBudget.MaxErrorRate = o.ErrorBudget.MaxErrorRate
+		}
+		if o.ErrorBudget.MinRequests > 0 {
+			opt.ErrorBudget.MinRequests = o.ErrorBudget.MinRequests
+		}
+		if o.ErrorBudget.MaxDiscordErrors > 0 {
+			opt.ErrorBudget.MaxDiscordErrors = o.ErrorBudget.MaxDiscordErrors
+		}
+		if o.TenantResolver != nil {
+			opt.TenantResolver = o.TenantResolver
+		}
+		if len(o.AdminTokens) > 0 {
+			opt.AdminTokens = o.AdminTokens
+		}
+		if o.DisableCoalescing {
+			opt.DisableCoalescing = o.DisableCoalescing
+		}
+		if o.PrefetchOnConnect {
+			opt.PrefetchOnConnect = o.PrefetchOnConnect
+		}
+		if o.PrefetchMessages > 0 {
+			opt.PrefetchMessages = o.PrefetchMessages
+		}
+		if o.ProxyHeader != "" {
+			opt.ProxyHeader = o.ProxyHeader
+		}
+		if len(o.TrustedProxies) > 0 {
+			opt.TrustedProxies = o.TrustedProxies
+		}
+		if len(o.EventThrottles) > 0 {
+			opt.EventThrottles = o.EventThrottles
+		}
+		if o.Projections {
+			opt.Projections = o.Projections
+		}
+		if o.RequireEncryption {
+			opt.RequireEncryption = o.RequireEncryption
+		}
+		if o.PublicURL != "" {
+			opt.PublicURL = o.PublicURL
+		}
+		if len(o.Limits) > 0 {
+			opt.Limits = o.Limits
+		}
+	}
+
+	if opt.KVStore == nil {
+		opt.KVStore = store.NewMemoryKVStore() // Keeps plugin data in memory by default.
+	}
+
+	// Credentialed CORS requests are rejected by browsers for wildcard origins.
+	if opt.CookieAuth && opt.AllowOrigins == "*" {
+		return nil, errors.New("cookie auth requires explicit AllowOrigins")
+	}
+
+	// Browsers send the auth cookie with WebSocket connections from any website, so cookie auth
+	// limits them to the CORS origins unless WebSocket origins are given.
+	if opt.CookieAuth && len(opt.WebSocketOrigins) == 0 {
+		for _, origin := range strings.Split(opt.AllowOrigins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				opt.WebSocketOrigins = append(opt.WebSocketOrigins, origin)
+			}
+		}
+	}
+
+	// Checks the options for values disgm cannot work with.
+	if err := checkOptions(opt); err != nil {
+		return nil, err
+	}
+
+	// Checks the gateway intents of the session, which must be set before disgm is created.
+	if err := validateIntents(s, opt); err != nil {
+		return nil, err
+	}
+
+	// Configures the two-person approval policy.
+	approvals.mu.Lock()
+	approvals.actions = opt.ApprovalActions
+	approvals.ttl = opt.ApprovalTTL
+	approvals.mu.Unlock()
+
+	protection = opt.Protection // Configures the protected channels and roles.
+
+	// Configures the retention window of the undo journal.
+	journal.mu.Lock()
+	journal.retention = opt.UndoRetention
+	journal.mu.Unlock()
+
+	// Configures the message relays.
+	relays.Lock()
+	relays.crossGuild = opt.AllowCrossGuildRelays
+	relays.Unlock()
+	registerRelayHandlers(s)
+	registerWebhookPoolHandlers(s)
+	registerGiveawayHandlers(s)
+
+	// Configures the shared cache and the user cache.
+	caches.Lock()
+	caches.maxBytes = opt.CacheMaxMemory
+	caches.maxEntries[CacheUsers] = opt.UserCacheSize
+	caches.Unlock()
+	userCache.Lock()
+	userCache.ttl = opt.UserCacheTTL
+	userCache.Unlock()
+	registerUserCacheHandlers(s)
+
+	// Configures the routing of interactions outside of the bot's guilds.
+	interactionRouting.Lock()
+	interactionRouting.guildID = opt.UserInstallGuild
+	interactionRouting.Unlock()
+
+	// Configures the error budget and counts the errors of the Discord API.
+	errorBudget.Lock()
+	errorBudget.config = opt.ErrorBudget
+	errorBudget.Unlock()
+	recordDiscordErrors(s)
+
+	// Configures the admin tokens.
+	admin.Lock()
+	admin.tokens = opt.AdminTokens
+	admin.store = opt.TokenStore
+	admin.Unlock()
+
+	// Configures the READY snapshot of new WebSocket connections.
+	prefetch.Lock()
+	prefetch.enabled = opt.PrefetchOnConnect
+	prefetch.messages = opt.PrefetchMessages
+	prefetch.Unlock()
+	registerPrefetchHandlers(s)
+
+	// Configures the throttles of the forwarded events.
+	eventThrottles.Lock()
+	eventThrottles.limits = opt.EventThrottles
+	eventThrottles.Unlock()
+
+	// Configures the read models of the projection endpoints.
+	projections.Lock()
+	projections.enabled = opt.Projections
+	projections.Unlock()
+	registerProjectionHandlers(s)
+
+	// Configures the encryption of the events sent to clients and webhooks.
+	encryption.Lock()
+	encryption.required = opt.RequireEncryption
+	encryption.Unlock()
+
+	// Configures the WebSocket chat op.
+	chat.Lock()
+	chat.enabled = opt.WebSocketChat
+	chat.limit = opt.ChatRateLimit
+	chat.Unlock()
+
+	// Configures the WebSocket relay op.
+	clientRelays.Lock()
+	clientRelays.enabled = opt.WebSocketRelay
+	clientRelays.limit = opt.ClientRelayRateLimit
+	clientRelays.maxSize = opt.MaxClientRelaySize
+	clientRelays.Unlock()
+
+	configureListLimits(opt.Limits) // Configures the sizes of the list endpoints.
+
+	// Configures the codec of the WebSocket events, shared with the API responses.
+	eventEncoder.Lock()
+	eventEncoder.marshal = json.Marshal
+	if opt.JSONEncoder != nil {
+		eventEncoder.marshal = opt.JSONEncoder
 	}
+	eventEncoder.Unlock()
 
 	app := fiber.New(fiber.Config{
 		AppName:               "Disgm",
 		DisableStartupMessage: opt.DisableStartupMessage,
-		ProxyHeader:           "X-Forwarded-For", // Sets the proxy header for IP forwarding.
+		ProxyHeader:           opt.ProxyHeader, // Sets the proxy header for IP forwarding.
+		// Reads the header only from trusted proxies, so clients cannot spoof their IP, and only
+		// accepts valid IPs from it, taking the first of a X-Forwarded-For list.
+		EnableTrustedProxyCheck: len(opt.TrustedProxies) > 0,
+		TrustedProxies:          opt.TrustedProxies,
+		EnableIPValidation:      true,
+		JSONEncoder:             opt.JSONEncoder,
+		JSONDecoder:             opt.JSONDecoder,
+		Prefork:                 opt.Prefork,
+		Concurrency:             opt.Concurrency,
+		ReadBufferSize:          opt.ReadBufferSize,
+		WriteBufferSize:         opt.WriteBufferSize,
+		ReadTimeout:             opt.ReadTimeout,
+		WriteTimeout:            opt.WriteTimeout,
+		IdleTimeout:             opt.IdleTimeout,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			fmt.Printf("Error: %v\n", err)
 			return c.Status(fiber.StatusInternalServerError).SendString("Internal Server Error") // Returns an error status.
@@ -84,23 +462,137 @@ func New(s *discordgo.Session, options ...Options) (d *Disgm, err error) {
 		opt:   opt, // Sets the default options.
 		s:     s,   // Sets the DiscordGo session.
 		fiber: app, // Sets the Fiber application.
+
+		plugins: make(map[string]Plugin),
 	}
 
+	// Configures the storage of the guild snapshots, which backup tasks write to.
+	snapshots.Lock()
+	snapshots.storage = d.Storage("snapshots")
+	snapshots.Unlock()
+
+	loadTasks(s, d.Storage("tasks"))         // Reschedules the persisted tasks.
+	loadGuildTokens(d.Storage("tokens"))     // Restores the tokens issued for the guilds.
+	loadHooks(d.Storage("hooks"))            // Restores the inbound webhook integrations.
+	loadFeeds(s, d.Storage("feeds"))         // Restarts the pollers of the feed subscriptions.
+	loadGiveaways(s, d.Storage("giveaways")) // Re-arms the timers of the running giveaways.
+
+	loadRelays(d.Storage("relays"), d.Storage("relay-messages")) // Restores the message relays.
+
+	// Configures the persistent event buffer.
+	eventBuffer.Lock()
+	eventBuffer.size = opt.EventBufferSize
+	eventBuffer.retention = opt.EventRetention
+	eventBuffer.Unlock()
+	loadEventBuffer(d.Storage("events"))
+
+	// Configures the message log of the search endpoint.
+	messageLog.Lock()
+	messageLog.enabled = opt.MessageLog
+	messageLog.retention = opt.MessageLogRetention
+	messageLog.index = opt.MessageIndex
+	if messageLog.index == nil {
+		messageLog.index = &kvMessageIndex{storage: d.Storage("messages")}
+	}
+	messageLog.Unlock()
+	registerMessageLogHandlers(s)
+
+	// Configures the channel history.
+	channelHistory.Lock()
+	channelHistory.storage = d.Storage("channel-history")
+	channelHistory.Unlock()
+	registerChannelHistoryHandlers(s)
+
+	// Configures the storage of the permission overwrite templates.
+	overwriteTemplates.Lock()
+	overwriteTemplates.storage = d.Storage("overwrite-templates")
+	overwriteTemplates.Unlock()
+
+	// Configures the guild settings policies.
+	policies.Lock()
+	policies.storage = d.Storage("policies")
+	policies.Unlock()
+	registerPolicyHandlers(s)
+
+	loadAPIAudit(d.Storage("api-audit")) // Continues the API audit log.
+
+	// Configures the storage of the guild and token settings.
+	settings.Lock()
+	settings.storage = d.Storage("settings")
+	settings.Unlock()
+
+	// Configures the activity digests, which read their settings.
+	loadDigests(d.Storage("digests"))
+	registerDigestHandlers(s)
+
+	// Configures the API module toggles.
+	modules.Lock()
+	modules.disabled = opt.DisabledModules
+	modules.storage = d.Storage("modules")
+	modules.Unlock()
+
 	// Configures CORS and logger middleware.
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Accept-Language, Content-Length",
+		AllowOrigins:     opt.AllowOrigins,
+		AllowHeaders:     "Origin, Content-Type, Accept, Accept-Language, Content-Length, " + CSRFHeaderName + ", " + AuditReasonHeader + ", " + UpdateMaskHeader + ", If-Match",
+		ExposeHeaders:    ChangeIDHeader + ", ETag, Link, Retry-After, " + QuotaLimitHeader + ", " + QuotaRemainingHeader,
+		AllowCredentials: opt.CookieAuth, // Allows the browser to send the auth and CSRF cookies.
 	}))
 
 	if !opt.DisableLogger {
-		app.Use(logger.New()) // Adds the logger.
+		app.Use(logger.New(logger.Config{
+			// Records the acting user of the token alongside each request.
+			Format: "${time} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${locals:ActingUser} | ${error}\n",
+		})) // Adds the logger.
+	}
+
+	// Counts the API requests by route and status for the status endpoint.
+	app.Use(statusMiddleware)
+
+	// Mirrors the API requests, including their credentials, before they are authenticated.
+	if opt.MirrorURL != "" {
+		app.Use(newMirror(opt.MirrorURL))
 	}
 
+	// Registers the health probes before the token validation, so orchestrators need no token.
+	HealthRouter(app.Group("/api"), s)
+
 	// Middleware for token validation.
 	app.Use(func(c *fiber.Ctx) error {
+		if strings.HasPrefix(c.Path(), "/hooks/") {
+			return c.Next() // Inbound webhooks are authenticated by their signature.
+		}
 		return TokenMiddleware(d, c)
 	})
 
+	// Middleware for the rate limits and quotas of the tenants.
+	if opt.TenantResolver != nil {
+		app.Use(TenantMiddleware)
+	}
+
+	// Middleware for the API modules disabled per guild or plan.
+	app.Use(ModuleMiddleware)
+
+	// Middleware recording the changes made through the API with their credential and acting user.
+	app.Use(APIAuditMiddleware)
+
+	// Middleware injecting the configured faults into authenticated requests.
+	if len(opt.FaultInjection) > 0 {
+		log.Printf("Fault injection is enabled for %d rules", len(opt.FaultInjection))
+		app.Use(newFaultInjector(opt.FaultInjection))
+	}
+
+	// Middleware for CSRF protection of cookie authenticated requests.
+	if opt.CookieAuth {
+		app.Use(CSRFMiddleware)
+	}
+
+	// Middleware sharing the responses of concurrent identical GET requests.
+	if !opt.DisableCoalescing {
+		app.Use(CoalescingMiddleware)
+	}
+
+	app.Get("/swagger/doc.json", swaggerDoc(opt.PublicURL)) // Served with the host of the server, before the UI.
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
 	return
@@ -113,21 +605,71 @@ func (d *Disgm) RegisterApiRouter() {
 	})
 }
 
+// RegisterHookReceiver registers the inbound webhook receiver at /hooks/:integration.
+//
+// External services such as GitHub, Stripe or status pages post signed events to this route,
+// which are turned into Discord messages according to the integrations registered by each guild.
+func (d *Disgm) RegisterHookReceiver() {
+	d.fiber.Post("/hooks/:integration", func(c *fiber.Ctx) error {
+		return ReceiveHook(c, d.s)
+	})
+}
+
 // @Summary		Register WebSocket
 // @Description	Sets up the WebSocket connection to handle Discord events and messages.
 // @Tags			WebSocket
 // @Produce		json
+// @Param			v				query	int		false	"Protocol version, see /api/ws/protocol"
+// @Param			client_name		query	string	false	"Name of the client, shown in logs and the clients endpoint"
+// @Param			client_version	query	string	false	"Version of the client"
+// @Param			client_purpose	query	string	false	"What the client uses the connection for"
+// @Param			encoding		query	string	false	"Encoding of the events, json (default) or proto"	Enums(json, proto)
+// @Failure		403	{object}	error
 // @Router			/ws [get]
 func (d *Disgm) RegisterWebSocket() {
 	registerDiscordHandlers(d.s) // Registers the Discord handlers for events.
+	go d.revalidateTokens()      // Closes the connections of revoked tokens.
 
-	// Sets the WebSocket connection.
-	d.fiber.Get("/ws", websocket.New(func(c *websocket.Conn) {
+	// Sets the WebSocket connection, refusing upgrades from browsers on origins not allowed.
+	d.fiber.Get("/ws", WebSocketOriginMiddleware(d.opt.WebSocketOrigins), websocket.New(func(c *websocket.Conn) {
 		ID := c.Locals("ID").(string) // Retrieves the ID from the local context.
-		WebSocket(c, ID)              // Handles the WebSocket connection.
+		WebSocket(c, ID, d.s)         // Handles the WebSocket connection.
 	}))
 }
 
+// forwardedEvents lists the Discord gateway events forwarded to WebSocket clients.
+var forwardedEvents = []string{
+	"GUILD_UPDATE",
+	"VOICE_STATE_UPDATE",
+	"GUILD_MEMBER_ADD",
+	"GUILD_MEMBER_UPDATE",
+	"GUILD_MEMBER_REMOVE",
+	"GUILD_BAN_ADD",
+	"GUILD_BAN_REMOVE",
+	"CHANNEL_CREATE",
+	"CHANNEL_UPDATE",
+	"CHANNEL_DELETE",
+	"THREAD_CREATE",
+	"THREAD_UPDATE",
+	"THREAD_DELETE",
+	"THREAD_MEMBERS_UPDATE",
+	"THREAD_LIST_SYNC",
+	"GUILD_ROLE_CREATE",
+	"GUILD_ROLE_UPDATE",
+	"GUILD_ROLE_DELETE",
+	"MESSAGE_CREATE",
+	"MESSAGE_UPDATE",
+	"MESSAGE_DELETE",
+	"MESSAGE_REACTION_ADD",
+	"MESSAGE_REACTION_REMOVE",
+	"MESSAGE_REACTION_REMOVE_ALL",
+	"MESSAGE_POLL_VOTE_ADD",
+	"MESSAGE_POLL_VOTE_REMOVE",
+	"INTERACTION_CREATE",
+	"PRESENCE_UPDATE",
+	"TYPING_START",
+}
+
 // registerDiscordHandlers registers handlers for Discord events.
 //
 // This function adds an event handler that responds to various Discord events
@@ -137,32 +679,8 @@ func (d *Disgm) RegisterWebSocket() {
 //   - s: *discordgo.Session – The DiscordGo session for interacting with the Discord API.
 func registerDiscordHandlers(s *discordgo.Session) {
 	s.AddHandler(func(s *discordgo.Session, e *discordgo.Event) {
-		// List of relevant events to handle.
-		events := []string{
-			"GUILD_UPDATE",
-			"VOICE_STATE_UPDATE",
-			"GUILD_MEMBER_ADD",
-			"GUILD_MEMBER_UPDATE",
-			"GUILD_MEMBER_REMOVE",
-			"GUILD_BAN_ADD",
-			"GUILD_BAN_REMOVE",
-			"CHANNEL_CREATE",
-			"CHANNEL_UPDATE",
-			"CHANNEL_DELETE",
-			"GUILD_ROLE_CREATE",
-			"GUILD_ROLE_UPDATE",
-			"GUILD_ROLE_DELETE",
-			"MESSAGE_CREATE",
-			"MESSAGE_UPDATE",
-			"MESSAGE_DELETE",
-			"MESSAGE_REACTION_ADD",
-			"MESSAGE_REACTION_REMOVE",
-			"MESSAGE_REACTION_REMOVE_ALL",
-			"INTERACTION_CREATE",
-		}
-
 		// Checks if the event is in the list of processed events.
-		if slices.Contains(events, e.Type) {
+		if slices.Contains(forwardedEvents, e.Type) {
 			var data map[string]interface{}
 
 			err := json.Unmarshal(e.RawData, &data) // Converts the raw event data into a map.
@@ -171,10 +689,22 @@ func registerDiscordHandlers(s *discordgo.Session) {
 				return
 			}
 
-			if guildID, ok := data["guild_id"].(string); ok {
-				EventCall(guildID, e.Type, data) // Calls the EventCall function with the relevant data.
-			} else {
-				fmt.Println("guild_id not found") // Logs if guild_id is not found.
+			guildID, ok := data["guild_id"].(string)
+			if e.Type == interactionEvent {
+				// Interactions of user-installed commands may come from outside the bot's guilds.
+				guildID = interactionGuild(data)
+				ok = guildID != ""
+			}
+			// Events without a guild, e.g. direct messages, have no clients and are dropped.
+			if ok {
+				event := queuedEvent{guildID: guildID, name: e.Type, data: data, received: time.Now()}
+				if e.Type == "MESSAGE_CREATE" {
+					event.except = chatSender(data) // Skips the client that sent the message with the chat op.
+				}
+				if e.Type == "THREAD_MEMBERS_UPDATE" {
+					addThreadParent(s, data) // Lets subscriptions to the parent channel match the event.
+				}
+				queueEvent(event) // Queues the event for the WebSocket clients, interactions ahead of the others.
 			}
 		}
 	})
@@ -189,9 +719,10 @@ func registerDiscordHandlers(s *discordgo.Session) {
 //   - port (string): The port on which the server should listen. Defaults to ":90" if left empty.
 //
 // Return:
-//   - error: Returns an error if the server fails to start.
+//   - error: Returns an error if the self-check fails or the server fails to start.
 //
 // Functionality:
+//   - Runs SelfCheck and refuses to start if a route conflicts or lacks a swagger annotation.
 //   - Starts the server in a separate goroutine using Fiber (`app.Listen(port)`) to avoid blocking execution
 //     and logs any errors encountered during startup.
 //   - On success, logs a message indicating the actual port the server is listening on.
@@ -200,6 +731,10 @@ func (d *Disgm) Listen(port ...string) (err error) {
 		port = append(port, ":90")
 	}
 
+	if err := d.SelfCheck(); err != nil {
+		return err
+	}
+
 	// Starts the Fiber server in a separate goroutine
 	go func() {
 		if err = d.fiber.Listen(port[0]); err != nil {
@@ -209,3 +744,31 @@ func (d *Disgm) Listen(port ...string) (err error) {
 	log.Printf("Server started at port: %v", strings.Split(port[0], ":")[1]) // Logs startup message
 	return err
 }
+
+// Listener starts the Fiber server on an existing network listener.
+//
+// This method is like Listen, for servers whose listener is created by the caller, e.g. on a
+// random port in tests and benchmarks, or handed over by a process manager.
+//
+// Parameters:
+//   - ln (net.Listener): The listener the server accepts connections on.
+//
+// Return:
+//   - error: Returns an error if the self-check fails.
+//
+// Functionality:
+//   - Runs SelfCheck and refuses to start if a route conflicts or lacks a swagger annotation.
+//   - Serves in a separate goroutine until the listener is closed, logging any errors encountered.
+func (d *Disgm) Listener(ln net.Listener) error {
+	if err := d.SelfCheck(); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := d.fiber.Listener(ln); err != nil {
+			log.Printf("Failed to start Fiber server: %v", err) // Logs any startup errors
+		}
+	}()
+	log.Printf("Server started at address: %v", ln.Addr()) // Logs startup message
+	return nil
+}
@@ -0,0 +1,205 @@
+package disgmtest
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm"
+	"github.com/valyala/fasthttp"
+)
+
+// allocBudgets are the maximum allocations per operation of the hot paths, checked by
+// TestAllocBudgets. The fan-out budgets allow one allocation per client for the reads of the
+// test clients, which run in the same process.
+var allocBudgets = []struct {
+	name   string
+	budget float64
+	op     func(tb testing.TB) func()
+}{
+	{"EventFanout/1", 20, func(tb testing.TB) func() { return eventFanoutOp(tb, 1) }},
+	{"EventFanout/50", 80, func(tb testing.TB) func() { return eventFanoutOp(tb, 50) }},
+	{"EventFanout/500", 600, func(tb testing.TB) func() { return eventFanoutOp(tb, 500) }},
+	{"TokenMiddleware", 5, tokenMiddlewareOp},
+	{"ModuleMiddleware", 0, moduleMiddlewareOp},
+}
+
+// benchEvent is the payload of the benchmarked event, shaped like a small MESSAGE_CREATE.
+var benchEvent = &discordgo.Message{
+	ID:        "1234567890123456789",
+	ChannelID: "1234567890123456790",
+	GuildID:   "1234567890123456791",
+	Content:   "Hello, this is a message of average length for a busy guild!",
+	Author:    &discordgo.User{ID: "1234567890123456792", Username: "alice"},
+}
+
+// startDisgm starts disgm with the WebSocket on a random port, against a backend with one guild,
+// and stops both when the test ends. It returns the address of the server and the guild ID.
+func startDisgm(tb testing.TB) (addr, guildID string) {
+	backend := New()
+	tb.Cleanup(backend.Close)
+	guild := backend.AddGuild(&discordgo.Guild{Name: "Benchmark Guild"})
+
+	s, err := backend.Session()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	d, err := disgm.New(s, disgm.Options{DisableStartupMessage: true, DisableLogger: true, TokenStore: TokenStore{guild.ID: BotToken}})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	d.RegisterWebSocket()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { ln.Close() })
+	if err := d.Listener(ln); err != nil {
+		tb.Fatal(err)
+	}
+
+	return ln.Addr().String(), guild.ID
+}
+
+// eventFanoutOp connects the given number of WebSocket clients to a guild and returns an operation
+// sending an event to them with disgm.EventCall, including the writes to the connections.
+func eventFanoutOp(tb testing.TB, clients int) func() {
+	addr, guildID := startDisgm(tb)
+
+	header := http.Header{"Authorization": {"Bearer " + BotToken}}
+	for i := 0; i < clients; i++ {
+		conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws", header)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		tb.Cleanup(func() { conn.Close() })
+
+		// Waits for the HELLO event, so the client is registered before the operation runs
+		for j := 0; j < 2; j++ {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				tb.Fatal(err)
+			}
+		}
+		go func() {
+			for {
+				_, r, err := conn.NextReader()
+				if err != nil {
+					return
+				}
+				io.Copy(io.Discard, r)
+			}
+		}()
+	}
+
+	return func() {
+		if err := disgm.EventCall(guildID, "MESSAGE_CREATE", benchEvent); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+// middlewareOp returns an operation sending a request through the given middleware to a handler
+// that does nothing.
+func middlewareOp(tb testing.TB, middleware fiber.Handler) func() {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(middleware)
+	app.Get("/api/guild", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	handler := app.Handler()
+
+	var ctx fasthttp.RequestCtx
+	return func() {
+		ctx.Request.Reset()
+		ctx.Response.Reset()
+		ctx.Request.SetRequestURI("/api/guild")
+		ctx.Request.Header.SetMethod(fiber.MethodGet)
+		ctx.Request.Header.Set(fiber.HeaderAuthorization, "Bearer "+BotToken)
+		handler(&ctx)
+		if ctx.Response.StatusCode() != fiber.StatusNoContent {
+			tb.Fatalf("unexpected status %d", ctx.Response.StatusCode())
+		}
+	}
+}
+
+// tokenMiddlewareOp returns an operation authenticating a request with a valid token.
+func tokenMiddlewareOp(tb testing.TB) func() {
+	backend := New()
+	tb.Cleanup(backend.Close)
+	guild := backend.AddGuild(&discordgo.Guild{Name: "Benchmark Guild"})
+
+	s, err := backend.Session()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	d, err := disgm.New(s, disgm.Options{DisableStartupMessage: true, DisableLogger: true, TokenStore: TokenStore{guild.ID: BotToken}})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return middlewareOp(tb, func(c *fiber.Ctx) error {
+		return disgm.TokenMiddleware(d, c)
+	})
+}
+
+// moduleMiddlewareOp returns an operation checking the modules of a guild without overrides.
+func moduleMiddlewareOp(tb testing.TB) func() {
+	return middlewareOp(tb, func(c *fiber.Ctx) error {
+		c.Locals("ID", "1234567890123456791")
+		return disgm.ModuleMiddleware(c)
+	})
+}
+
+// benchmarkOp runs an operation b.N times.
+func benchmarkOp(b *testing.B, op func()) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		op()
+	}
+}
+
+// BenchmarkEventFanout measures sending an event to 1, 50 and 500 WebSocket clients of a guild.
+// The clients run in the same process, so their reads are counted in the allocations too, about
+// one per client and event.
+func BenchmarkEventFanout(b *testing.B) {
+	for _, clients := range []int{1, 50, 500} {
+		b.Run(fmt.Sprint(clients), func(b *testing.B) {
+			benchmarkOp(b, eventFanoutOp(b, clients))
+		})
+	}
+}
+
+// BenchmarkTokenMiddleware measures the authentication of a request with a valid token.
+func BenchmarkTokenMiddleware(b *testing.B) {
+	benchmarkOp(b, tokenMiddlewareOp(b))
+}
+
+// BenchmarkModuleMiddleware measures the module check of a request of a guild without overrides.
+func BenchmarkModuleMiddleware(b *testing.B) {
+	benchmarkOp(b, moduleMiddlewareOp(b))
+}
+
+// TestAllocBudgets fails when a hot path allocates more per operation than its budget, so a
+// change that makes every event or request allocate more is caught by go test.
+func TestAllocBudgets(t *testing.T) {
+	for _, tt := range allocBudgets {
+		t.Run(tt.name, func(t *testing.T) {
+			if testing.Short() && tt.name == "EventFanout/500" {
+				t.Skip("skipping 500 WebSocket clients in short mode")
+			}
+			op := tt.op(t)
+			op() // Warms up the pools and caches.
+
+			if allocs := testing.AllocsPerRun(100, op); allocs > tt.budget {
+				t.Errorf("%.0f allocs/op, budget %.0f", allocs, tt.budget)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package disgmtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm"
+)
+
+// ContractResponse is the recorded response of a route: its status and the shape of its body.
+//
+// The shape keeps the structure of a JSON body with the values replaced by their types, and
+// snowflake keys by "<id>", so it only changes with the API and not with the data. Text bodies
+// are kept up to the first colon, which holds the error message without its details, and
+// OpenMetrics bodies keep their metric descriptions without the samples.
+type ContractResponse struct {
+	Status int         `json:"status"`
+	Shape  interface{} `json:"shape"`
+}
+
+// Contract maps the routes of the API, as "METHOD /api/path", to their recorded responses.
+type Contract map[string]ContractResponse
+
+// contractParams are the values of the route parameters not backed by seeded resources.
+var contractParams = map[string]string{
+	"module":           disgm.ModuleMessages,
+	"name":             "contract",
+	"integration":      "contract",
+	"token":            "contract",
+	"interactiontoken": "contract",
+	"emojiid":          "%F0%9F%91%8D",
+}
+
+// contractMethods orders the requests, so reads see the seeded data before it is changed.
+var contractMethods = []string{
+	fiber.MethodGet, fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete,
+}
+
+// RecordContract calls every route of the API against a seeded backend and records the responses.
+//
+// Mutating routes are called with an empty JSON object, after all reads. Parameters naming a
+// seeded channel, message, member or role are filled with it; other resources are unknown.
+func RecordContract() (Contract, error) {
+	b := New()
+	defer b.Close()
+
+	guild := b.AddGuild(&discordgo.Guild{Name: "Contract Guild"})
+	category := b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "text", Type: discordgo.ChannelTypeGuildCategory})
+	channel := b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "general", Type: discordgo.ChannelTypeGuildText, ParentID: category.ID})
+	role := b.AddRole(guild.ID, &discordgo.Role{Name: "moderators"})
+	member := b.AddMember(&discordgo.Member{GuildID: guild.ID, User: &discordgo.User{Username: "alice"}, Roles: []string{role.ID}})
+	message := b.AddMessage(&discordgo.Message{ChannelID: channel.ID, Author: member.User, Content: "Hello!"})
+
+	s, err := b.Session()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := disgm.New(s, disgm.Options{DisableStartupMessage: true, TokenStore: TokenStore{guild.ID: BotToken}}); err != nil {
+		return nil, err
+	}
+	if err := s.Open(); err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	params := map[string]string{
+		"channelid":   channel.ID,
+		"messageid":   message.ID,
+		"memberid":    member.User.ID,
+		"userid":      member.User.ID,
+		"roleid":      role.ID,
+		"overwriteid": role.ID,
+	}
+
+	// The routes are served without the token middleware, as the unrestricted token of the guild,
+	// or as an admin token for the admin routes
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(func(c *fiber.Ctx) error {
+		if strings.HasPrefix(c.Path(), "/api/admin/") {
+			c.Locals("Token", BotToken)
+			c.Locals("Admin", true)
+			return c.Next()
+		}
+		c.Locals("ID", guild.ID)
+		c.Locals("Token", BotToken)
+		return c.Next()
+	})
+	app.Route("/api", func(r fiber.Router) {
+		disgm.HealthRouter(r, s)
+		disgm.Router(r, s)
+	})
+
+	routes := slices.DeleteFunc(app.GetRoutes(true), func(r fiber.Route) bool {
+		return !slices.Contains(contractMethods, r.Method) || !strings.HasPrefix(r.Path, "/api/")
+	})
+	sort.SliceStable(routes, func(i, j int) bool {
+		mi, mj := slices.Index(contractMethods, routes[i].Method), slices.Index(contractMethods, routes[j].Method)
+		if mi != mj {
+			return mi < mj
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	contract := make(Contract, len(routes))
+	for _, route := range routes {
+		path := route.Path
+		for _, name := range route.Params {
+			value, ok := params[name]
+			if !ok {
+				if value, ok = contractParams[name]; !ok {
+					value = "1"
+				}
+			}
+			path = strings.Replace(path, ":"+name, value, 1)
+		}
+
+		req := httptest.NewRequest(route.Method, path, nil)
+		if route.Method != fiber.MethodGet && route.Method != fiber.MethodDelete {
+			req = httptest.NewRequest(route.Method, path, strings.NewReader("{}"))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		}
+		res, err := app.Test(req, -1)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+
+		contract[route.Method+" "+route.Path] = ContractResponse{Status: res.StatusCode, Shape: bodyShape(res.Header.Get(fiber.HeaderContentType), body)}
+	}
+	return contract, nil
+}
+
+// bodyShape returns the shape of a response body.
+func bodyShape(contentType string, body []byte) interface{} {
+	if strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		var v interface{}
+		if json.Unmarshal(body, &v) == nil {
+			return jsonShape(v)
+		}
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	if strings.HasPrefix(contentType, "application/openmetrics-text") {
+		var lines []string
+		for _, line := range strings.Split(string(body), "\n") {
+			if strings.HasPrefix(line, "#") {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	text, _, _ := strings.Cut(string(body), ":")
+	return strings.TrimSpace(text)
+}
+
+// jsonShape replaces the values of a decoded JSON value by their types. Arrays are represented by
+// their first element, and keys that are snowflakes by "<id>".
+func jsonShape(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		shape := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			if k != "" && strings.Trim(k, "0123456789") == "" {
+				k = "<id>"
+			}
+			shape[k] = jsonShape(e)
+		}
+		return shape
+	case []interface{}:
+		if len(v) == 0 {
+			return []interface{}{}
+		}
+		return []interface{}{jsonShape(v[0])}
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+// Diff lists the differences of the contract from a golden contract, sorted by route.
+// Routes added or removed and responses with another status or shape are reported.
+func (c Contract) Diff(golden Contract) []string {
+	var diffs []string
+	for route, want := range golden {
+		got, ok := c[route]
+		switch {
+		case !ok:
+			diffs = append(diffs, route+": removed")
+		case got.Status != want.Status:
+			diffs = append(diffs, fmt.Sprintf("%s: status %d, want %d", route, got.Status, want.Status))
+		default:
+			gotShape, _ := json.Marshal(got.Shape)
+			wantShape, _ := json.Marshal(want.Shape)
+			if string(gotShape) != string(wantShape) {
+				diffs = append(diffs, fmt.Sprintf("%s: shape %s, want %s", route, gotShape, wantShape))
+			}
+		}
+	}
+	for route := range c {
+		if _, ok := golden[route]; !ok {
+			diffs = append(diffs, route+": added")
+		}
+	}
+	slices.Sort(diffs)
+	return diffs
+}
@@ -0,0 +1,53 @@
+package disgmtest
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+// update records the golden contract instead of checking it, after an intended API change:
+//
+//	go test ./disgmtest -run TestContract -update
+var update = flag.Bool("update", false, "record the golden contract in testdata instead of checking it")
+
+// goldenContract is the path of the golden contract, relative to the package.
+const goldenContract = "testdata/contract.json"
+
+// TestContract checks the status and response shape of every route against the golden contract.
+func TestContract(t *testing.T) {
+	contract, err := RecordContract()
+	if err != nil {
+		t.Fatalf("Failed to record contract: %v", err)
+	}
+
+	if *update {
+		data, err := json.MarshalIndent(contract, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenContract, append(data, '\n'), 0o644); err != nil {
+			t.Fatalf("Failed to write golden contract: %v", err)
+		}
+		t.Logf("Recorded %d routes to %s", len(contract), goldenContract)
+		return
+	}
+
+	data, err := os.ReadFile(goldenContract)
+	if err != nil {
+		t.Fatalf("Failed to read golden contract: %v", err)
+	}
+	var want Contract
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("Failed to read golden contract: %v", err)
+	}
+
+	diffs := contract.Diff(want)
+	for _, diff := range diffs {
+		t.Error(diff)
+	}
+	if len(diffs) > 0 {
+		t.Logf("%d routes differ from the contract, run with -update if the change is intended", len(diffs))
+	}
+}
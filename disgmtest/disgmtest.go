@@ -0,0 +1,310 @@
+// Package disgmtest provides an in-memory fake of the Discord API for developing against disgm
+// offline.
+//
+// A Backend serves the REST endpoints and the gateway disgm uses through a regular
+// discordgo.Session, so the disgm HTTP and WebSocket surface works unchanged on top of it:
+//
+//	b := disgmtest.New()
+//	defer b.Close()
+//
+//	guild := b.AddGuild(&discordgo.Guild{Name: "Test Guild"})
+//	b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "general"})
+//
+//	s, _ := b.Session()
+//	d, _ := disgm.New(s, disgm.Options{TokenStore: disgmtest.TokenStore{guild.ID: "dev"}})
+//	d.RegisterApiRouter()
+//	d.RegisterWebSocket()
+//	d.Listen()
+//	s.Open()
+//
+// The guilds, channels, roles, members and messages are seeded with the Add methods. Changes
+// made through the API are dispatched as gateway events, and Emit and Play send scripted events.
+package disgmtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// BotToken is the token of the sessions created by the backend. It is not checked.
+const BotToken = "disgmtest"
+
+// discordEpoch is the first second of 2015, the epoch of Discord snowflakes.
+const discordEpoch = 1420070400000
+
+// Backend is an in-memory fake of the Discord API.
+type Backend struct {
+	mu       sync.Mutex
+	server   *httptest.Server
+	bot      *discordgo.User
+	lastID   uint64
+	guilds   []*discordgo.Guild              // Guilds in the order they were added
+	channels map[string]*discordgo.Channel   // Channels and threads by ID
+	roles    map[string][]*discordgo.Role    // Roles by guild ID
+	members  map[string][]*discordgo.Member  // Members by guild ID
+	messages map[string][]*discordgo.Message // Messages by channel ID, oldest first
+	conns    map[*gatewayConn]struct{}       // Identified gateway connections
+	users    map[string]*discordgo.User      // Users by ID, from the seeded members
+}
+
+// Step is a scripted gateway event, sent by Play after the delay.
+type Step struct {
+	Delay time.Duration // Time to wait before sending the event
+	Event string        // Gateway event name, e.g. MESSAGE_CREATE
+	Data  interface{}   // Event payload, marshalled to JSON
+}
+
+// TokenStore is a fixed in-memory token store, mapping guild IDs to their tokens.
+type TokenStore map[string]string
+
+// Store replaces the tokens of the store.
+func (t TokenStore) Store(tokens map[string]string) error {
+	clear(t)
+	for k, v := range tokens {
+		t[k] = v
+	}
+	return nil
+}
+
+// Load returns the tokens of the store.
+func (t TokenStore) Load() (map[string]string, error) {
+	return t, nil
+}
+
+// New starts a backend with a bot user and no guilds.
+func New() *Backend {
+	b := &Backend{
+		channels: make(map[string]*discordgo.Channel),
+		roles:    make(map[string][]*discordgo.Role),
+		members:  make(map[string][]*discordgo.Member),
+		messages: make(map[string][]*discordgo.Message),
+		conns:    make(map[*gatewayConn]struct{}),
+		users:    make(map[string]*discordgo.User),
+	}
+	b.bot = &discordgo.User{ID: b.newID(), Username: "disgmtest", Discriminator: "0", Bot: true}
+	b.users[b.bot.ID] = b.bot
+	b.server = httptest.NewServer(b.routes())
+	return b
+}
+
+// Close disconnects the gateway connections and stops the backend.
+func (b *Backend) Close() {
+	b.mu.Lock()
+	for conn := range b.conns {
+		conn.ws.Close()
+	}
+	b.mu.Unlock()
+	b.server.Close()
+}
+
+// URL returns the base URL of the backend server.
+func (b *Backend) URL() string {
+	return b.server.URL
+}
+
+// Bot returns the user of the bot.
+func (b *Backend) Bot() *discordgo.User {
+	return b.bot
+}
+
+// Session creates a discordgo session whose requests are served by the backend.
+// The session connects to the fake gateway when opened.
+func (b *Backend) Session() (*discordgo.Session, error) {
+	s, err := discordgo.New("Bot " + BotToken)
+	if err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(b.server.URL)
+	if err != nil {
+		return nil, err
+	}
+	s.Client = &http.Client{Transport: rewriteTransport{target}, Timeout: 20 * time.Second}
+	s.Identify.Intents = discordgo.IntentsAll // The backend sends every event, privileged or not.
+	return s, nil
+}
+
+// rewriteTransport sends all requests to the backend server, whatever their host.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newID returns a new snowflake for the current time, greater than any returned before.
+func (b *Backend) newID() string {
+	id := uint64(time.Now().UnixMilli()-discordEpoch) << 22
+	if id <= b.lastID {
+		id = b.lastID + 1
+	}
+	b.lastID = id
+	return strconv.FormatUint(id, 10)
+}
+
+// AddGuild seeds a guild, assigning an ID if it has none, and dispatches GUILD_CREATE.
+// The bot is added as a member and an @everyone role is created.
+func (b *Backend) AddGuild(g *discordgo.Guild) *discordgo.Guild {
+	b.mu.Lock()
+	if g.ID == "" {
+		g.ID = b.newID()
+	}
+	if g.OwnerID == "" {
+		g.OwnerID = b.bot.ID
+	}
+	b.guilds = append(b.guilds, g)
+	b.roles[g.ID] = append(b.roles[g.ID], &discordgo.Role{ID: g.ID, Name: "@everyone", Permissions: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages})
+	b.members[g.ID] = append(b.members[g.ID], &discordgo.Member{GuildID: g.ID, User: b.bot, JoinedAt: time.Now()})
+	data, _ := json.Marshal(b.guildCreate(g))
+	b.mu.Unlock()
+
+	b.dispatch("GUILD_CREATE", json.RawMessage(data))
+	return g
+}
+
+// AddChannel seeds a channel of a seeded guild, assigning an ID if it has none, and
+// dispatches CHANNEL_CREATE, or THREAD_CREATE for threads.
+func (b *Backend) AddChannel(ch *discordgo.Channel) *discordgo.Channel {
+	b.mu.Lock()
+	if ch.ID == "" {
+		ch.ID = b.newID()
+	}
+	b.channels[ch.ID] = ch
+	data, _ := json.Marshal(ch)
+	b.mu.Unlock()
+
+	if ch.IsThread() {
+		b.dispatch("THREAD_CREATE", json.RawMessage(data))
+	} else {
+		b.dispatch("CHANNEL_CREATE", json.RawMessage(data))
+	}
+	return ch
+}
+
+// AddRole seeds a role of a seeded guild, assigning an ID if it has none, and dispatches
+// GUILD_ROLE_CREATE.
+func (b *Backend) AddRole(guildID string, r *discordgo.Role) *discordgo.Role {
+	b.mu.Lock()
+	if r.ID == "" {
+		r.ID = b.newID()
+	}
+	b.roles[guildID] = append(b.roles[guildID], r)
+	data, _ := json.Marshal(discordgo.GuildRole{GuildID: guildID, Role: r})
+	b.mu.Unlock()
+
+	b.dispatch("GUILD_ROLE_CREATE", json.RawMessage(data))
+	return r
+}
+
+// AddMember seeds a member of a seeded guild, assigning a user ID if it has none, and
+// dispatches GUILD_MEMBER_ADD.
+func (b *Backend) AddMember(m *discordgo.Member) *discordgo.Member {
+	b.mu.Lock()
+	if m.User == nil {
+		m.User = &discordgo.User{}
+	}
+	if m.User.ID == "" {
+		m.User.ID = b.newID()
+	}
+	if m.JoinedAt.IsZero() {
+		m.JoinedAt = time.Now()
+	}
+	b.members[m.GuildID] = append(b.members[m.GuildID], m)
+	b.users[m.User.ID] = m.User
+	data, _ := json.Marshal(m)
+	b.mu.Unlock()
+
+	b.dispatch("GUILD_MEMBER_ADD", json.RawMessage(data))
+	return m
+}
+
+// AddMessage seeds a message in a seeded channel, as if the author posted it, and dispatches
+// MESSAGE_CREATE. The ID, timestamp and guild ID are filled in if missing, and the bot is the
+// author if none is set.
+func (b *Backend) AddMessage(m *discordgo.Message) *discordgo.Message {
+	b.mu.Lock()
+	b.addMessage(m)
+	data, _ := json.Marshal(m)
+	b.mu.Unlock()
+
+	b.dispatch("MESSAGE_CREATE", json.RawMessage(data))
+	return m
+}
+
+// addMessage stores a message, filling in its missing fields. The caller must hold b.mu.
+func (b *Backend) addMessage(m *discordgo.Message) {
+	if m.ID == "" {
+		m.ID = b.newID()
+	}
+	if m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+	if m.Author == nil {
+		m.Author = b.bot
+	}
+	if ch, ok := b.channels[m.ChannelID]; ok && m.GuildID == "" {
+		m.GuildID = ch.GuildID
+	}
+	b.messages[m.ChannelID] = append(b.messages[m.ChannelID], m)
+}
+
+// Emit sends a gateway event to the connected sessions. The backend state is not changed,
+// so the event can describe anything, including changes the REST endpoints do not see.
+func (b *Backend) Emit(event string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	b.dispatch(event, json.RawMessage(raw))
+	return nil
+}
+
+// Play sends the scripted events in order, waiting for the delay of each step first.
+// It returns at the first event that cannot be marshalled.
+func (b *Backend) Play(steps ...Step) error {
+	for _, step := range steps {
+		time.Sleep(step.Delay)
+		if err := b.Emit(step.Event, step.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// guildCreate builds the GUILD_CREATE payload of a guild with its channels, roles and members.
+// The caller must hold b.mu.
+func (b *Backend) guildCreate(g *discordgo.Guild) *discordgo.Guild {
+	full := *g
+	full.Channels, full.Threads = nil, nil
+	for _, ch := range b.guildChannels(g.ID) {
+		if ch.IsThread() {
+			full.Threads = append(full.Threads, ch)
+		} else {
+			full.Channels = append(full.Channels, ch)
+		}
+	}
+	full.Roles = b.roles[g.ID]
+	full.Members = b.members[g.ID]
+	full.MemberCount = len(full.Members)
+	return &full
+}
+
+// gatewayConn is a gateway connection with its own sequence and serialized writes.
+type gatewayConn struct {
+	mu  sync.Mutex
+	ws  *websocket.Conn
+	seq int64
+}
@@ -0,0 +1,134 @@
+package disgmtest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// heartbeatInterval is the heartbeat interval in milliseconds sent in the HELLO payload.
+const heartbeatInterval = 41250
+
+// upgrader accepts the gateway connections of the sessions.
+var upgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+
+// gatewayPayload is a message of the gateway protocol.
+type gatewayPayload struct {
+	Op   int             `json:"op"`
+	Seq  int64           `json:"s,omitempty"`
+	Type string          `json:"t,omitempty"`
+	Data json.RawMessage `json:"d"`
+}
+
+// write sends a payload to the connection, numbering dispatches with the next sequence.
+func (c *gatewayConn) write(p gatewayPayload) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.writeLocked(p)
+}
+
+// writeLocked is write for callers holding c.mu.
+func (c *gatewayConn) writeLocked(p gatewayPayload) error {
+	if p.Op == 0 {
+		c.seq++
+		p.Seq = c.seq
+	}
+	return c.ws.WriteJSON(p)
+}
+
+// serveGateway runs the gateway protocol for a session: HELLO, then READY and a GUILD_CREATE
+// per guild on identify, heartbeat acknowledgements, and the dispatched events until the
+// session disconnects.
+func (b *Backend) serveGateway(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := &gatewayConn{ws: ws}
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, conn)
+		b.mu.Unlock()
+		ws.Close()
+	}()
+
+	hello, _ := json.Marshal(map[string]int{"heartbeat_interval": heartbeatInterval})
+	if conn.write(gatewayPayload{Op: 10, Data: hello}) != nil {
+		return
+	}
+
+	for {
+		var p gatewayPayload
+		if ws.ReadJSON(&p) != nil {
+			return
+		}
+
+		switch p.Op {
+		case 1: // Heartbeat
+			err = conn.write(gatewayPayload{Op: 11, Data: json.RawMessage("null")})
+		case 2: // Identify
+			err = b.identify(conn)
+		case 6: // Resume
+			b.mu.Lock()
+			b.conns[conn] = struct{}{}
+			b.mu.Unlock()
+			err = conn.write(gatewayPayload{Op: 0, Type: "RESUMED", Data: json.RawMessage("{}")})
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// identify sends READY and the guilds to a connection and subscribes it to the dispatched events.
+// Events dispatched meanwhile wait for the guilds, so the session sees them in order.
+func (b *Backend) identify(conn *gatewayConn) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	b.mu.Lock()
+	sessionID := b.newID()
+	unavailable := make([]map[string]interface{}, 0, len(b.guilds))
+	guilds := make([]json.RawMessage, 0, len(b.guilds))
+	for _, g := range b.guilds {
+		unavailable = append(unavailable, map[string]interface{}{"id": g.ID, "unavailable": true})
+		data, _ := json.Marshal(b.guildCreate(g))
+		guilds = append(guilds, data)
+	}
+	ready, _ := json.Marshal(map[string]interface{}{
+		"v":          10,
+		"session_id": sessionID,
+		"user":       b.bot,
+		"guilds":     unavailable,
+	})
+	b.conns[conn] = struct{}{}
+	b.mu.Unlock()
+
+	if err := conn.writeLocked(gatewayPayload{Op: 0, Type: "READY", Data: ready}); err != nil {
+		return err
+	}
+	for _, g := range guilds {
+		if err := conn.writeLocked(gatewayPayload{Op: 0, Type: "GUILD_CREATE", Data: g}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dispatch sends an event to all identified connections. Events are dropped for connections
+// that fail, which then close.
+func (b *Backend) dispatch(event string, data json.RawMessage) {
+	b.mu.Lock()
+	conns := make([]*gatewayConn, 0, len(b.conns))
+	for conn := range b.conns {
+		conns = append(conns, conn)
+	}
+	b.mu.Unlock()
+
+	for _, conn := range conns {
+		if conn.write(gatewayPayload{Op: 0, Type: event, Data: data}) != nil {
+			conn.ws.Close()
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package disgmtest
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+// TestMain hides the logs of disgm, which the tests and benchmarks start many times, unless the
+// tests run verbosely.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if !testing.Verbose() {
+		log.SetOutput(io.Discard)
+	}
+	os.Exit(m.Run())
+}
@@ -0,0 +1,536 @@
+package disgmtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord JSON error codes returned by the backend.
+const (
+	codeUnknownChannel = 10003
+	codeUnknownGuild   = 10004
+	codeUnknownMember  = 10007
+	codeUnknownMessage = 10008
+	codeUnknownUser    = 10013
+	codeInvalidBody    = 50035
+)
+
+// routes registers the REST endpoints and the gateway.
+func (b *Backend) routes() http.Handler {
+	api := "/api/v" + discordgo.APIVersion
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /gateway/", b.serveGateway)
+	mux.HandleFunc("GET "+api+"/gateway", b.getGateway)
+	mux.HandleFunc("GET "+api+"/gateway/bot", b.getGateway)
+
+	mux.HandleFunc("GET "+api+"/users/{userID}", b.getUser)
+
+	mux.HandleFunc("GET "+api+"/guilds/{guildID}", b.getGuild)
+	mux.HandleFunc("GET "+api+"/guilds/{guildID}/channels", b.getGuildChannels)
+	mux.HandleFunc("POST "+api+"/guilds/{guildID}/channels", b.createGuildChannel)
+	mux.HandleFunc("GET "+api+"/guilds/{guildID}/roles", b.getGuildRoles)
+	mux.HandleFunc("GET "+api+"/guilds/{guildID}/members", b.getGuildMembers)
+	mux.HandleFunc("GET "+api+"/guilds/{guildID}/members/{userID}", b.getGuildMember)
+	mux.HandleFunc("GET "+api+"/guilds/{guildID}/regions", b.getGuildRegions)
+
+	mux.HandleFunc("GET "+api+"/channels/{channelID}", b.getChannel)
+	mux.HandleFunc("PATCH "+api+"/channels/{channelID}", b.editChannel)
+	mux.HandleFunc("DELETE "+api+"/channels/{channelID}", b.deleteChannel)
+	mux.HandleFunc("GET "+api+"/channels/{channelID}/messages", b.getMessages)
+	mux.HandleFunc("POST "+api+"/channels/{channelID}/messages", b.createMessage)
+	mux.HandleFunc("GET "+api+"/channels/{channelID}/messages/{messageID}", b.getMessage)
+	mux.HandleFunc("PATCH "+api+"/channels/{channelID}/messages/{messageID}", b.editMessage)
+	mux.HandleFunc("DELETE "+api+"/channels/{channelID}/messages/{messageID}", b.deleteMessage)
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, 0, "404: Not Found (not implemented by disgmtest)")
+	})
+	return mux
+}
+
+// writeJSON writes a value as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a Discord JSON error.
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	writeJSON(w, status, map[string]interface{}{"code": code, "message": message})
+}
+
+// snowflakeLess reports whether snowflake a is lower than b.
+func snowflakeLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// guild returns a seeded guild by ID. The caller must hold b.mu.
+func (b *Backend) guild(id string) *discordgo.Guild {
+	i := slices.IndexFunc(b.guilds, func(g *discordgo.Guild) bool { return g.ID == id })
+	if i < 0 {
+		return nil
+	}
+	return b.guilds[i]
+}
+
+// guildChannels returns the channels of a guild sorted by position. The caller must hold b.mu.
+func (b *Backend) guildChannels(guildID string) []*discordgo.Channel {
+	channels := []*discordgo.Channel{}
+	for _, ch := range b.channels {
+		if ch.GuildID == guildID {
+			channels = append(channels, ch)
+		}
+	}
+	slices.SortFunc(channels, func(a, b *discordgo.Channel) int {
+		if a.Position != b.Position {
+			return a.Position - b.Position
+		}
+		if snowflakeLess(a.ID, b.ID) {
+			return -1
+		}
+		return 1
+	})
+	return channels
+}
+
+// messageIndex returns the index of a message in its channel, or -1. The caller must hold b.mu.
+func (b *Backend) messageIndex(channelID, messageID string) int {
+	return slices.IndexFunc(b.messages[channelID], func(m *discordgo.Message) bool { return m.ID == messageID })
+}
+
+func (b *Backend) getGateway(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"url":    "ws" + strings.TrimPrefix(b.server.URL, "http") + "/gateway",
+		"shards": 1,
+		"session_start_limit": map[string]int{
+			"total": 1000, "remaining": 1000, "reset_after": 0, "max_concurrency": 1,
+		},
+	})
+}
+
+func (b *Backend) getUser(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("userID")
+	if id == "@me" {
+		id = b.bot.ID
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	user, ok := b.users[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownUser, "Unknown User")
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (b *Backend) getGuild(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	g := b.guild(r.PathValue("guildID"))
+	if g == nil {
+		writeError(w, http.StatusNotFound, codeUnknownGuild, "Unknown Guild")
+		return
+	}
+	full := *g
+	full.Roles = b.roles[g.ID]
+	writeJSON(w, http.StatusOK, &full)
+}
+
+// voiceRegions are the voice regions returned for every guild.
+var voiceRegions = []map[string]interface{}{
+	{"id": "rotterdam", "name": "Rotterdam", "optimal": true, "deprecated": false, "custom": false},
+	{"id": "us-east", "name": "US East", "optimal": false, "deprecated": false, "custom": false},
+}
+
+func (b *Backend) getGuildRegions(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.guild(r.PathValue("guildID")) == nil {
+		writeError(w, http.StatusNotFound, codeUnknownGuild, "Unknown Guild")
+		return
+	}
+	writeJSON(w, http.StatusOK, voiceRegions)
+}
+
+func (b *Backend) getGuildChannels(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	guildID := r.PathValue("guildID")
+	if b.guild(guildID) == nil {
+		writeError(w, http.StatusNotFound, codeUnknownGuild, "Unknown Guild")
+		return
+	}
+	channels := slices.DeleteFunc(b.guildChannels(guildID), (*discordgo.Channel).IsThread)
+	writeJSON(w, http.StatusOK, channels)
+}
+
+func (b *Backend) createGuildChannel(w http.ResponseWriter, r *http.Request) {
+	var data discordgo.GuildChannelCreateData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil || data.Name == "" {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, "Invalid Form Body")
+		return
+	}
+
+	b.mu.Lock()
+	guildID := r.PathValue("guildID")
+	if b.guild(guildID) == nil {
+		b.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownGuild, "Unknown Guild")
+		return
+	}
+	b.mu.Unlock()
+
+	ch := b.AddChannel(&discordgo.Channel{
+		GuildID:              guildID,
+		Name:                 data.Name,
+		Type:                 data.Type,
+		Topic:                data.Topic,
+		Bitrate:              data.Bitrate,
+		UserLimit:            data.UserLimit,
+		RateLimitPerUser:     data.RateLimitPerUser,
+		Position:             data.Position,
+		PermissionOverwrites: data.PermissionOverwrites,
+		ParentID:             data.ParentID,
+		NSFW:                 data.NSFW,
+	})
+	writeJSON(w, http.StatusCreated, ch)
+}
+
+func (b *Backend) getGuildRoles(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	guildID := r.PathValue("guildID")
+	if b.guild(guildID) == nil {
+		writeError(w, http.StatusNotFound, codeUnknownGuild, "Unknown Guild")
+		return
+	}
+	writeJSON(w, http.StatusOK, b.roles[guildID])
+}
+
+func (b *Backend) getGuildMembers(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	guildID := r.PathValue("guildID")
+	if b.guild(guildID) == nil {
+		writeError(w, http.StatusNotFound, codeUnknownGuild, "Unknown Guild")
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 1
+	}
+	after := r.URL.Query().Get("after")
+
+	members := slices.Clone(b.members[guildID])
+	slices.SortFunc(members, func(a, b *discordgo.Member) int {
+		if snowflakeLess(a.User.ID, b.User.ID) {
+			return -1
+		}
+		return 1
+	})
+	page := []*discordgo.Member{}
+	for _, m := range members {
+		if len(page) == min(max(limit, 1), 1000) {
+			break
+		}
+		if after == "" || snowflakeLess(after, m.User.ID) {
+			page = append(page, m)
+		}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (b *Backend) getGuildMember(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i := slices.IndexFunc(b.members[r.PathValue("guildID")], func(m *discordgo.Member) bool {
+		return m.User.ID == r.PathValue("userID")
+	})
+	if i < 0 {
+		writeError(w, http.StatusNotFound, codeUnknownMember, "Unknown Member")
+		return
+	}
+	writeJSON(w, http.StatusOK, b.members[r.PathValue("guildID")][i])
+}
+
+func (b *Backend) getChannel(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch, ok := b.channels[r.PathValue("channelID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	writeJSON(w, http.StatusOK, ch)
+}
+
+func (b *Backend) editChannel(w http.ResponseWriter, r *http.Request) {
+	var edit struct {
+		Name                 *string                           `json:"name"`
+		Topic                *string                           `json:"topic"`
+		NSFW                 *bool                             `json:"nsfw"`
+		Position             *int                              `json:"position"`
+		ParentID             *string                           `json:"parent_id"`
+		RateLimitPerUser     *int                              `json:"rate_limit_per_user"`
+		PermissionOverwrites *[]*discordgo.PermissionOverwrite `json:"permission_overwrites"`
+		Archived             *bool                             `json:"archived"`
+		Locked               *bool                             `json:"locked"`
+	}
+	var fields map[string]json.RawMessage // Tells null fields, which are cleared, from missing ones
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &edit)
+	}
+	if err == nil {
+		err = json.Unmarshal(body, &fields)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, "Invalid Form Body")
+		return
+	}
+
+	b.mu.Lock()
+	ch, ok := b.channels[r.PathValue("channelID")]
+	if !ok {
+		b.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	if edit.Name != nil {
+		ch.Name = *edit.Name
+	}
+	if edit.Topic != nil || string(fields["topic"]) == "null" {
+		ch.Topic = deref(edit.Topic)
+	}
+	if edit.NSFW != nil {
+		ch.NSFW = *edit.NSFW
+	}
+	if edit.Position != nil {
+		ch.Position = *edit.Position
+	}
+	if edit.ParentID != nil || string(fields["parent_id"]) == "null" {
+		ch.ParentID = deref(edit.ParentID)
+	}
+	if edit.RateLimitPerUser != nil {
+		ch.RateLimitPerUser = *edit.RateLimitPerUser
+	}
+	if edit.PermissionOverwrites != nil {
+		ch.PermissionOverwrites = *edit.PermissionOverwrites
+	}
+	if ch.ThreadMetadata != nil && edit.Archived != nil {
+		ch.ThreadMetadata.Archived = *edit.Archived
+	}
+	if ch.ThreadMetadata != nil && edit.Locked != nil {
+		ch.ThreadMetadata.Locked = *edit.Locked
+	}
+	data, _ := json.Marshal(ch)
+	b.mu.Unlock()
+
+	if ch.IsThread() {
+		b.dispatch("THREAD_UPDATE", data)
+	} else {
+		b.dispatch("CHANNEL_UPDATE", data)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// deref returns the string a pointer points to, or an empty string for nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func (b *Backend) deleteChannel(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	ch, ok := b.channels[r.PathValue("channelID")]
+	if !ok {
+		b.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	delete(b.channels, ch.ID)
+	delete(b.messages, ch.ID)
+	data, _ := json.Marshal(ch)
+	b.mu.Unlock()
+
+	if ch.IsThread() {
+		b.dispatch("THREAD_DELETE", data)
+	} else {
+		b.dispatch("CHANNEL_DELETE", data)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (b *Backend) getMessages(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	channelID := r.PathValue("channelID")
+	if _, ok := b.channels[channelID]; !ok {
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+
+	q := r.URL.Query()
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil {
+		limit = 50
+	}
+	limit = min(max(limit, 1), 100)
+	before, after := q.Get("before"), q.Get("after")
+
+	// Messages are returned newest first, except the oldest after the after message
+	messages := b.messages[channelID]
+	page := []*discordgo.Message{}
+	if after != "" {
+		for _, m := range messages {
+			if len(page) < limit && snowflakeLess(after, m.ID) {
+				page = append(page, m)
+			}
+		}
+		slices.Reverse(page)
+	} else {
+		for i := len(messages) - 1; i >= 0 && len(page) < limit; i-- {
+			if before == "" || snowflakeLess(messages[i].ID, before) {
+				page = append(page, messages[i])
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// messageBody is the part of a created or edited message the backend keeps.
+type messageBody struct {
+	Content    *string                           `json:"content"`
+	Embeds     *[]*discordgo.MessageEmbed        `json:"embeds"`
+	Components json.RawMessage                   `json:"components"`
+	Reference  *discordgo.MessageReference       `json:"message_reference"`
+	Mentions   *discordgo.MessageAllowedMentions `json:"allowed_mentions"`
+}
+
+// readMessageBody decodes the body of a message request, sent as JSON or as the payload_json
+// field of a multipart form when it has files.
+func readMessageBody(r *http.Request) (body messageBody, err error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		if err = r.ParseMultipartForm(32 << 20); err != nil {
+			return body, err
+		}
+		err = json.Unmarshal([]byte(r.FormValue("payload_json")), &body)
+		return body, err
+	}
+	err = json.NewDecoder(r.Body).Decode(&body)
+	return body, err
+}
+
+func (b *Backend) createMessage(w http.ResponseWriter, r *http.Request) {
+	body, err := readMessageBody(r)
+	if err != nil || (body.Content == nil && body.Embeds == nil) {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, "Cannot send an empty message")
+		return
+	}
+
+	b.mu.Lock()
+	channelID := r.PathValue("channelID")
+	if _, ok := b.channels[channelID]; !ok {
+		b.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownChannel, "Unknown Channel")
+		return
+	}
+	m := &discordgo.Message{ChannelID: channelID, Type: discordgo.MessageTypeDefault}
+	if body.Content != nil {
+		m.Content = *body.Content
+	}
+	if body.Embeds != nil {
+		m.Embeds = *body.Embeds
+	}
+	if body.Reference != nil {
+		m.Type = discordgo.MessageTypeReply
+		m.MessageReference = body.Reference
+	}
+	b.addMessage(m)
+	data, _ := json.Marshal(m)
+	b.mu.Unlock()
+
+	b.dispatch("MESSAGE_CREATE", data)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (b *Backend) getMessage(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	channelID := r.PathValue("channelID")
+	i := b.messageIndex(channelID, r.PathValue("messageID"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	writeJSON(w, http.StatusOK, b.messages[channelID][i])
+}
+
+func (b *Backend) editMessage(w http.ResponseWriter, r *http.Request) {
+	body, err := readMessageBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, "Invalid Form Body")
+		return
+	}
+
+	b.mu.Lock()
+	channelID := r.PathValue("channelID")
+	i := b.messageIndex(channelID, r.PathValue("messageID"))
+	if i < 0 {
+		b.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	m := b.messages[channelID][i]
+	if body.Content != nil {
+		m.Content = *body.Content
+	}
+	if body.Embeds != nil {
+		m.Embeds = *body.Embeds
+	}
+	now := time.Now()
+	m.EditedTimestamp = &now
+	data, _ := json.Marshal(m)
+	b.mu.Unlock()
+
+	b.dispatch("MESSAGE_UPDATE", data)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (b *Backend) deleteMessage(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	channelID := r.PathValue("channelID")
+	i := b.messageIndex(channelID, r.PathValue("messageID"))
+	if i < 0 {
+		b.mu.Unlock()
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	m := b.messages[channelID][i]
+	b.messages[channelID] = slices.Delete(b.messages[channelID], i, i+1)
+	data, _ := json.Marshal(map[string]string{"id": m.ID, "channel_id": m.ChannelID, "guild_id": m.GuildID})
+	b.mu.Unlock()
+
+	b.dispatch("MESSAGE_DELETE", data)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,243 @@
+package disgm
+
+import (
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	eventQueueSize         = 4096                    // Gateway events waiting to be forwarded to WebSocket clients.
+	interactionQueueSize   = 256                     // Interactions waiting to be forwarded, ahead of the other events.
+	interactionSamples     = 256                     // Recent interactions kept per guild for the latency percentiles.
+	interactionSlowLatency = 1500 * time.Millisecond // Latency logged as slow, half of the interaction response deadline.
+	interactionEvent       = "INTERACTION_CREATE"
+)
+
+// queuedEvent is a gateway event waiting to be forwarded to the WebSocket clients of a guild.
+type queuedEvent struct {
+	guildID  string
+	name     string
+	data     map[string]interface{}
+	except   *websocket.Conn // Client that is not sent the event, e.g. the sender of a chat message
+	received time.Time
+}
+
+// The queues of the gateway events forwarded to WebSocket clients. Events are sent in the order
+// they were received by a single dispatcher, except for interactions, which have their own queue
+// that is always drained first: Discord expects a response within 3 seconds, which a flood of
+// MESSAGE_CREATE events in the other queue would easily exceed.
+var eventQueues = struct {
+	once         sync.Once
+	events       chan queuedEvent
+	interactions chan queuedEvent
+}{
+	events:       make(chan queuedEvent, eventQueueSize),
+	interactions: make(chan queuedEvent, interactionQueueSize),
+}
+
+// The routing of the interactions of user-installed commands used outside of the guilds of the
+// bot, such as in DMs, which carry no guild the bot is installed in. Set from the UserInstallGuild
+// option.
+var interactionRouting = struct {
+	sync.Mutex
+	guildID string
+}{}
+
+// interactionGuild returns the guild whose WebSocket clients receive an interaction: the guild it
+// was created in if the app is installed there, else the guild configured for user-installed
+// commands, or an empty string if there is none.
+func interactionGuild(data map[string]interface{}) string {
+	guildID, _ := data["guild_id"].(string)
+	// Owner "0" is the guild the app is installed to, "1" the installing user.
+	owners, ok := data["authorizing_integration_owners"].(map[string]interface{})
+	if guildID != "" && (!ok || owners["0"] != nil) {
+		return guildID
+	}
+
+	interactionRouting.Lock()
+	defer interactionRouting.Unlock()
+	return interactionRouting.guildID
+}
+
+// queueEvent queues a gateway event for the WebSocket clients, blocking while its queue is full.
+// Events exceeding the throttle of their type in their guild are dropped instead.
+func queueEvent(e queuedEvent) {
+	eventQueues.once.Do(func() { go dispatchEvents() })
+
+	if e.name == interactionEvent {
+		eventQueues.interactions <- e
+		return
+	}
+	if throttled(e.guildID, e.name) {
+		return
+	}
+	eventQueues.events <- e
+}
+
+// dispatchEvents forwards the queued events to the WebSocket clients, interactions first.
+func dispatchEvents() {
+	for {
+		select {
+		case e := <-eventQueues.interactions:
+			dispatchInteraction(e)
+			continue
+		default:
+		}
+
+		select {
+		case e := <-eventQueues.interactions:
+			dispatchInteraction(e)
+		case e := <-eventQueues.events:
+			if err := eventCallExcept(e.guildID, e.name, e.data, e.except); err != nil {
+				log.Printf("error: %v", err)
+			}
+		}
+	}
+}
+
+// dispatchInteraction forwards an interaction to the WebSocket clients of its guild and records
+// its latency. Interactions skip the channel filters of the subscriptions, as the client handling
+// the commands of a guild may only follow some of its channels.
+func dispatchInteraction(e queuedEvent) {
+	dispatched := time.Now()
+	if err := broadcastEvent(e.guildID, e.name, e.data, e.except, true); err != nil {
+		log.Printf("error: %v", err)
+	}
+
+	sample := interactionSample{queue: dispatched.Sub(e.received), total: time.Since(e.received)}
+	// The interaction ID carries the time Discord created it, which includes the gateway delay.
+	if id, ok := e.data["id"].(string); ok {
+		if created, err := discordgo.SnowflakeTimestamp(id); err == nil && created.Before(e.received) {
+			sample.total = time.Since(created)
+		}
+	}
+	recordInteractionLatency(e.guildID, sample)
+
+	if sample.total > interactionSlowLatency {
+		log.Printf("Slow interaction in guild %s: forwarded after %v, %v of it queued", e.guildID, sample.total, sample.queue)
+	}
+}
+
+// interactionSample is the latency of a forwarded interaction.
+type interactionSample struct {
+	queue time.Duration // Time waiting in the interaction queue
+	total time.Duration // Time from the creation of the interaction until it was sent to the clients
+}
+
+// InteractionLatency reports the latency of the interactions forwarded to the WebSocket clients of
+// a guild, from their creation by Discord until they were sent. The percentiles cover the recent
+// interactions.
+type InteractionLatency struct {
+	Count      int     `json:"count"`        // Interactions forwarded since disgm started
+	LastMs     float64 `json:"last_ms"`      // Latency of the last interaction
+	P50Ms      float64 `json:"p50_ms"`       // Median latency
+	P99Ms      float64 `json:"p99_ms"`       // 99th percentile latency
+	MaxMs      float64 `json:"max_ms"`       // Highest latency
+	QueueP99Ms float64 `json:"queue_p99_ms"` // 99th percentile time waiting in the queue
+	Slow       int     `json:"slow"`         // Interactions slower than 1.5 seconds since disgm started
+}
+
+// interactionLatency holds the recent latency samples of a guild in a ring.
+type interactionLatency struct {
+	count   int
+	slow    int
+	max     time.Duration
+	samples []interactionSample
+}
+
+// The interaction latencies by guild ID.
+var interactionLatencies = struct {
+	sync.Mutex
+	guilds map[string]*interactionLatency
+}{
+	guilds: make(map[string]*interactionLatency),
+}
+
+// recordInteractionLatency adds a latency sample of a guild.
+func recordInteractionLatency(guildID string, sample interactionSample) {
+	interactionLatencies.Lock()
+	defer interactionLatencies.Unlock()
+
+	l, ok := interactionLatencies.guilds[guildID]
+	if !ok {
+		l = &interactionLatency{samples: make([]interactionSample, 0, interactionSamples)}
+		interactionLatencies.guilds[guildID] = l
+	}
+	if len(l.samples) < interactionSamples {
+		l.samples = append(l.samples, sample)
+	} else {
+		l.samples[l.count%interactionSamples] = sample
+	}
+	l.count++
+	l.max = max(l.max, sample.total)
+	if sample.total > interactionSlowLatency {
+		l.slow++
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// percentile returns the p-th percentile of sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+// GetInteractionLatency retrieves the latency of the interactions forwarded to WebSocket clients.
+//
+// Interactions are sent to the clients ahead of all other events, as they must be answered within
+// 3 seconds. The latency is measured from the creation of the interaction, taken from its ID, until
+// it was written to the clients of the guild, so it includes the gateway delay and any clock skew.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the latency stats of the guild as JSON, with zero values before the first interaction.
+// @Summary		Get Interaction Latency
+// @Description	Retrieve the latency of the interactions forwarded to the WebSocket clients of the guild.
+// @Tags			Events
+// @Success		200	{object}	InteractionLatency
+// @Router			/api/guild/ws/latency [get]
+func GetInteractionLatency(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	interactionLatencies.Lock()
+	var stats InteractionLatency
+	if l, ok := interactionLatencies.guilds[guildID]; ok {
+		totals := make([]time.Duration, 0, len(l.samples))
+		queues := make([]time.Duration, 0, len(l.samples))
+		for _, sample := range l.samples {
+			totals = append(totals, sample.total)
+			queues = append(queues, sample.queue)
+		}
+		last := l.samples[(l.count-1)%interactionSamples]
+		slices.Sort(totals)
+		slices.Sort(queues)
+
+		stats = InteractionLatency{
+			Count:      l.count,
+			LastMs:     milliseconds(last.total),
+			P50Ms:      milliseconds(percentile(totals, 50)),
+			P99Ms:      milliseconds(percentile(totals, 99)),
+			MaxMs:      milliseconds(l.max),
+			QueueP99Ms: milliseconds(percentile(queues, 99)),
+			Slow:       l.slow,
+		}
+	}
+	interactionLatencies.Unlock()
+
+	return c.JSON(stats)
+}
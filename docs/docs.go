@@ -15,156 +15,212 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/guild": {
+        "/api/admin/caches": {
             "get": {
-                "description": "Retrieve the guild information.",
+                "description": "Retrieve the entries, memory and hit rates of the caches.",
                 "tags": [
-                    "Guild"
+                    "Admin"
                 ],
-                "summary": "Get Guild",
+                "summary": "Get Caches",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/disgm.Guild"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.CacheStats"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {}
                     }
                 }
-            }
-        },
-        "/api/guild/bans": {
-            "get": {
-                "description": "Retrieve all banned users from the guild.",
+            },
+            "delete": {
+                "description": "Remove all entries of a cache. The hit and miss counters are kept.",
                 "tags": [
-                    "Bans"
+                    "Admin"
                 ],
-                "summary": "Get Guild Bans",
+                "summary": "Flush Cache",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.GuildBan"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {}
                     }
                 }
             }
         },
-        "/api/guild/bans/{userid}": {
-            "get": {
-                "description": "Retrieve a specific banned user by user ID.",
+        "/api/admin/caches/{name}": {
+            "delete": {
+                "description": "Remove all entries of a cache. The hit and miss counters are kept.",
                 "tags": [
-                    "Bans"
+                    "Admin"
                 ],
-                "summary": "Get Guild Ban",
+                "summary": "Flush Cache",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
+                        "description": "Cache name, all caches on /api/admin/caches",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
                 ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/admin/guilds": {
+            "get": {
+                "description": "List all guilds of the bot with member counts and WebSocket connection stats.",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get Admin Guilds",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GuildBan"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.AdminGuild"
+                            }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "put": {
-                "description": "Ban a user from the guild.",
+            }
+        },
+        "/api/admin/guilds/{id}/impersonate": {
+            "post": {
+                "description": "Mint a short-lived, optionally scoped token for a guild for a support session.",
                 "tags": [
-                    "Bans"
+                    "Admin"
                 ],
-                "summary": "Add Guild Ban",
+                "summary": "Impersonate Guild",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
+                        "description": "Guild ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Impersonation parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ImpersonationParams"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Impersonation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove a ban for a user in the guild.",
-                "tags": [
-                    "Bans"
+            }
+        },
+        "/api/admin/metrics": {
+            "get": {
+                "description": "Expose the WebSocket clients and subscriptions per guild and the event delivery success rates per sink as OpenMetrics.",
+                "produces": [
+                    "text/plain"
                 ],
-                "summary": "Remove Guild Ban",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
-                    }
+                "tags": [
+                    "Admin"
                 ],
+                "summary": "Get Admin Metrics",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {}
                     }
                 }
             }
         },
-        "/api/guild/bulk-ban": {
-            "post": {
-                "description": "Ban multiple users in the guild at once.",
+        "/api/admin/status": {
+            "get": {
+                "description": "Retrieve the recent error rates per route and Discord error code, the throttled events, and whether disgm is ready.",
                 "tags": [
-                    "Bans"
+                    "Admin"
                 ],
-                "summary": "Bulk Ban Members",
+                "summary": "Get Status",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Status"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {}
                     }
                 }
             }
         },
-        "/api/guild/channels": {
+        "/api/commands": {
             "get": {
-                "description": "Retrieve all channels from the guild.",
+                "description": "Retrieve all global application commands, including their integration types and contexts.",
                 "tags": [
-                    "Channels"
+                    "Commands"
                 ],
-                "summary": "Get Guild Channels",
+                "summary": "Get Global Application Commands",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -173,7 +229,7 @@ const docTemplate = `{
                             "items": {
                                 "type": "array",
                                 "items": {
-                                    "$ref": "#/definitions/models.Channel"
+                                    "$ref": "#/definitions/models.ApplicationCommand"
                                 }
                             }
                         }
@@ -185,18 +241,37 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new channel in the guild.",
+                "description": "Create a new global application command, e.g. for user-installed apps.",
                 "tags": [
-                    "Channels"
+                    "Commands"
+                ],
+                "summary": "Create Global Application Command",
+                "parameters": [
+                    {
+                        "description": "Application command",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationCommand"
+                        }
+                    }
                 ],
-                "summary": "Create Guild Channel",
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Channel"
+                            "$ref": "#/definitions/models.ApplicationCommand"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -204,18 +279,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/channels/{channelid}": {
+        "/api/commands/{cmdid}": {
             "get": {
-                "description": "Retrieve a specific channel from the guild by ID.",
+                "description": "Retrieve a specific global application command by ID.",
                 "tags": [
-                    "Channels"
+                    "Commands"
                 ],
-                "summary": "Get Guild Channel",
+                "summary": "Get Global Application Command",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
+                        "description": "Command ID",
+                        "name": "cmdid",
                         "in": "path",
                         "required": true
                     }
@@ -224,7 +299,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Channel"
+                            "$ref": "#/definitions/models.ApplicationCommand"
                         }
                     },
                     "500": {
@@ -234,16 +309,16 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a specific channel in the guild.",
+                "description": "Delete a global application command by ID.",
                 "tags": [
-                    "Channels"
+                    "Commands"
                 ],
-                "summary": "Delete Guild Channel",
+                "summary": "Delete Global Application Command",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
+                        "description": "Command ID",
+                        "name": "cmdid",
                         "in": "path",
                         "required": true
                     }
@@ -252,32 +327,32 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "patch": {
-                "description": "Update a specific channel in the guild.",
+            }
+        },
+        "/api/csrf": {
+            "get": {
+                "description": "Issue a CSRF token for cookie authenticated clients.",
                 "tags": [
-                    "Channels"
-                ],
-                "summary": "Update Guild Channel",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Auth"
                 ],
+                "summary": "Get CSRF Token",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Channel"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -287,60 +362,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/channels/{channelid}/messages": {
+        "/api/guild": {
             "get": {
-                "description": "Retrieve all messages from a specific channel.",
+                "description": "Retrieve the guild information.",
                 "tags": [
-                    "Messages"
+                    "Guild"
                 ],
-                "summary": "Get Channel Messages",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
+                "summary": "Get Guild",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Guild"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
                     }
+                }
+            }
+        },
+        "/api/guild/approvals": {
+            "get": {
+                "description": "Retrieve all destructive actions waiting for a second approval.",
+                "tags": [
+                    "Approvals"
                 ],
+                "summary": "Get Pending Approvals",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/disgm.Message"
+                                "$ref": "#/definitions/disgm.Approval"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {}
                     }
                 }
-            },
+            }
+        },
+        "/api/guild/approvals/{approvalid}/approve": {
             "post": {
-                "description": "Send a new message to a specific channel.",
+                "description": "Approve and execute a pending destructive action.",
                 "tags": [
-                    "Messages"
+                    "Approvals"
                 ],
-                "summary": "Send Channel Message",
+                "summary": "Approve Action",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
+                        "description": "Approval ID",
+                        "name": "approvalid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Message"
+                            "$ref": "#/definitions/disgm.Approval"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -348,25 +441,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/channels/{channelid}/messages/{messageid}": {
-            "get": {
-                "description": "Retrieve a specific message by ID from a channel.",
+        "/api/guild/approvals/{approvalid}/reject": {
+            "post": {
+                "description": "Reject a pending destructive action.",
                 "tags": [
-                    "Messages"
+                    "Approvals"
                 ],
-                "summary": "Get Channel Message",
+                "summary": "Reject Action",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
+                        "description": "Approval ID",
+                        "name": "approvalid",
                         "in": "path",
                         "required": true
                     }
@@ -375,76 +461,127 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Message"
+                            "$ref": "#/definitions/disgm.Approval"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {}
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a specific message in a channel by ID.",
+            }
+        },
+        "/api/guild/audit": {
+            "get": {
+                "description": "Retrieve the latest changes made to the guild through the API with their credential and acting user, newest first.",
                 "tags": [
-                    "Messages"
+                    "Guild"
                 ],
-                "summary": "Delete Channel Message",
+                "summary": "Get API Audit Log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
+                        "description": "Acting user ID",
+                        "name": "acting_user",
+                        "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
-                        "in": "path",
-                        "required": true
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Number of entries",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.APIAuditEntry"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "patch": {
-                "description": "Edit a specific message in a channel by ID.",
+            }
+        },
+        "/api/guild/audit-logs": {
+            "get": {
+                "description": "Retrieve a page of the audit log of the guild, newest first.",
                 "tags": [
-                    "Messages"
+                    "Guild"
                 ],
-                "summary": "Edit Channel Message",
+                "summary": "Get Guild Audit Log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Action type",
+                        "name": "action_type",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Entries per page",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
-                        "in": "path",
-                        "required": true
+                        "description": "Page cursor",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Message"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/disgm.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/disgm.AuditLogEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -452,32 +589,60 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/channels/{channelid}/messages/{messageid}/reactions": {
-            "delete": {
-                "description": "Remove all reactions from a specific message in a channel.",
+        "/api/guild/automod/rules": {
+            "get": {
+                "description": "Retrieve the AutoMod rules of the guild.",
                 "tags": [
-                    "Reactions"
+                    "AutoMod"
                 ],
-                "summary": "Delete All Message Reactions",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
+                "summary": "Get AutoMod Rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AutoModRule"
+                            }
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a keyword, spam or mention AutoMod rule in the guild.",
+                "tags": [
+                    "AutoMod"
+                ],
+                "summary": "Create AutoMod Rule",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
-                        "in": "path",
-                        "required": true
+                        "description": "Rule",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AutoModRule"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AutoModRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -486,32 +651,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid}": {
+        "/api/guild/automod/rules/{ruleid}": {
             "get": {
-                "description": "Retrieve all reactions from a specific message in a channel.",
+                "description": "Retrieve an AutoMod rule of the guild by ID.",
                 "tags": [
-                    "Reactions"
+                    "AutoMod"
                 ],
-                "summary": "Get Message Reactions",
+                "summary": "Get AutoMod Rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Emoji ID",
-                        "name": "emojiid",
+                        "description": "Rule ID",
+                        "name": "ruleid",
                         "in": "path",
                         "required": true
                     }
@@ -520,13 +671,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/models.User"
-                                }
-                            }
+                            "$ref": "#/definitions/models.AutoModRule"
                         }
                     },
                     "500": {
@@ -535,38 +680,28 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Add a reaction to a specific message in a channel.",
+            "delete": {
+                "description": "Delete an AutoMod rule from the guild.",
                 "tags": [
-                    "Reactions"
+                    "AutoMod"
                 ],
-                "summary": "Create Message Reaction",
+                "summary": "Delete AutoMod Rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Emoji ID",
-                        "name": "emojiid",
+                        "description": "Rule ID",
+                        "name": "ruleid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -574,38 +709,44 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Remove a specific emoji reaction from a message in a channel.",
+            "patch": {
+                "description": "Edit the fields of an AutoMod rule.",
                 "tags": [
-                    "Reactions"
+                    "AutoMod"
                 ],
-                "summary": "Delete Message Reaction Emoji",
+                "summary": "Update AutoMod Rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
+                        "description": "Rule ID",
+                        "name": "ruleid",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Emoji ID",
-                        "name": "emojiid",
-                        "in": "path",
-                        "required": true
+                        "description": "Changed fields",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AutoModRule"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AutoModRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -614,46 +755,67 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid}/{userid}": {
-            "delete": {
-                "description": "Delete a user's reaction from a specific message in a channel.",
+        "/api/guild/bans": {
+            "get": {
+                "description": "Retrieve a page of banned users from the guild, ordered by user ID.",
                 "tags": [
-                    "Reactions"
+                    "Bans"
                 ],
-                "summary": "Delete Message Reaction",
+                "summary": "Get Guild Bans",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
-                        "in": "path",
-                        "required": true
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Bans per page",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Message ID",
-                        "name": "messageid",
-                        "in": "path",
-                        "required": true
+                        "description": "Page cursor",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Emoji ID",
-                        "name": "emojiid",
-                        "in": "path",
-                        "required": true
+                        "description": "Bans of lower user IDs",
+                        "name": "before",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userid",
-                        "in": "path",
-                        "required": true
+                        "description": "Bans of higher user IDs",
+                        "name": "after",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/disgm.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.GuildBan"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -662,25 +824,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/channels/{channelid}/permissions/{overwriteid}": {
-            "put": {
-                "description": "Edit permissions for a specific channel in the guild.",
+        "/api/guild/bans/{userid}": {
+            "get": {
+                "description": "Retrieve a specific banned user by user ID.",
                 "tags": [
-                    "Channels"
+                    "Bans"
                 ],
-                "summary": "Edit Channel Permissions",
+                "summary": "Get Guild Ban",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
+                        "description": "User ID",
+                        "name": "userid",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Overwrite ID",
-                        "name": "overwriteid",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GuildBan"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Ban a user from the guild.",
+                "tags": [
+                    "Bans"
+                ],
+                "summary": "Add Guild Ban",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
                         "in": "path",
                         "required": true
                     }
@@ -696,28 +879,45 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a specific permission overwrite for a channel.",
+                "description": "Remove a ban for a user in the guild.",
                 "tags": [
-                    "Channels"
+                    "Bans"
                 ],
-                "summary": "Delete Channel Permissions",
+                "summary": "Remove Guild Ban",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Channel ID",
-                        "name": "channelid",
+                        "description": "User ID",
+                        "name": "userid",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
-                    {
-                        "type": "string",
-                        "description": "Overwrite ID",
-                        "name": "overwriteid",
-                        "in": "path",
-                        "required": true
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
                     }
+                }
+            }
+        },
+        "/api/guild/bulk-ban": {
+            "post": {
+                "description": "Ban multiple users in the guild at once.",
+                "tags": [
+                    "Bans"
                 ],
+                "summary": "Bulk Ban Members",
                 "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Approval"
+                        }
+                    },
                     "204": {
                         "description": "No Content"
                     },
@@ -728,13 +928,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/commands": {
+        "/api/guild/channels": {
             "get": {
-                "description": "Retrieve all guild application commands.",
+                "description": "Retrieve all channels from the guild.",
                 "tags": [
-                    "Commands"
+                    "Channels"
                 ],
-                "summary": "Get Guild Application Commands",
+                "summary": "Get Guild Channels",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -743,7 +943,7 @@ const docTemplate = `{
                             "items": {
                                 "type": "array",
                                 "items": {
-                                    "$ref": "#/definitions/models.ApplicationCommand"
+                                    "$ref": "#/definitions/models.Channel"
                                 }
                             }
                         }
@@ -755,18 +955,22 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new guild application command.",
+                "description": "Create a new channel in the guild.",
                 "tags": [
-                    "Commands"
+                    "Channels"
                 ],
-                "summary": "Create Guild Application Command",
+                "summary": "Create Guild Channel",
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.ApplicationCommand"
+                            "$ref": "#/definitions/models.Channel"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -774,18 +978,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/commands/{cmdid}": {
+        "/api/guild/channels/{channelid}": {
             "get": {
-                "description": "Retrieve a specific guild application command by ID.",
+                "description": "Retrieve a specific channel from the guild by ID.",
                 "tags": [
-                    "Commands"
+                    "Channels"
                 ],
-                "summary": "Get Guild Application Command",
+                "summary": "Get Guild Channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Command ID",
-                        "name": "cmdid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
                     }
@@ -794,9 +998,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ApplicationCommand"
+                            "$ref": "#/definitions/models.Channel"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -804,57 +1012,101 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a guild application command by ID.",
+                "description": "Delete a specific channel in the guild.",
                 "tags": [
-                    "Commands"
+                    "Channels"
                 ],
-                "summary": "Delete Guild Application Command",
+                "summary": "Delete Guild Channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Command ID",
-                        "name": "cmdid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Approval"
+                        }
+                    },
                     "204": {
                         "description": "No Content"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            }
-        },
-        "/api/guild/interactions/{interactionid}/{interactiontoken}/callback": {
-            "post": {
-                "description": "Handle interaction callback for a specific interaction.",
+            },
+            "patch": {
+                "description": "Update a specific channel in the guild.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
                 "tags": [
-                    "Interactions"
+                    "Channels"
                 ],
-                "summary": "Create Interaction Callback",
+                "summary": "Update Guild Channel",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Interaction ID",
-                        "name": "interactionid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Interaction Token",
-                        "name": "interactiontoken",
-                        "in": "path",
-                        "required": true
+                        "description": "Fields to update, comma-separated; missing ones are cleared",
+                        "name": "X-Update-Mask",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the channel as read",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Channel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -863,23 +1115,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/members": {
+        "/api/guild/channels/{channelid}/history": {
             "get": {
-                "description": "Retrieve all members of the guild.",
+                "description": "Retrieve the recorded name, topic and permission changes of a channel.",
                 "tags": [
-                    "Members"
+                    "Channels"
+                ],
+                "summary": "Get Channel History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of entries",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get Guild Members",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/disgm.Member"
+                                "$ref": "#/definitions/disgm.ChannelHistoryEntry"
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -887,90 +1161,158 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/members/{memberid}": {
-            "get": {
-                "description": "Retrieve a specific member from the guild by ID.",
+        "/api/guild/channels/{channelid}/impersonated-messages": {
+            "post": {
+                "description": "Send a message with any display name and avatar through webhooks disgm manages in the channel.",
                 "tags": [
-                    "Members"
+                    "Webhooks"
                 ],
-                "summary": "Get Guild Member",
+                "summary": "Send Impersonated Message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Member ID",
-                        "name": "memberid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Thread to post in",
+                        "name": "thread_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Message",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ImpersonatedMessage"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Member"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "patch": {
-                "description": "Update a specific member in the guild.",
+            }
+        },
+        "/api/guild/channels/{channelid}/messages": {
+            "get": {
+                "description": "Retrieve a page of messages from a specific channel, newest first.",
                 "tags": [
-                    "Members"
+                    "Messages"
                 ],
-                "summary": "Update Guild Member",
+                "summary": "Get Channel Messages",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Member ID",
-                        "name": "memberid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Messages per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Check the permissions of the bot first",
+                        "name": "check_permissions",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Member"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/disgm.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/disgm.Message"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            }
-        },
-        "/api/guild/members/{memberid}/roles": {
-            "get": {
-                "description": "Retrieve all roles assigned to a specific member in the guild.",
+            },
+            "post": {
+                "description": "Send a new message to a specific channel.",
                 "tags": [
-                    "Roles"
+                    "Messages"
                 ],
-                "summary": "Get Member Roles",
+                "summary": "Send Channel Message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Member ID",
-                        "name": "memberid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Role"
-                            }
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -978,32 +1320,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/members/{memberid}/roles/{roleid}": {
-            "put": {
-                "description": "Add a role to a specific member in the guild.",
+        "/api/guild/channels/{channelid}/messages/{messageid}": {
+            "get": {
+                "description": "Retrieve a specific message by ID from a channel.",
                 "tags": [
-                    "Roles"
+                    "Messages"
                 ],
-                "summary": "Add Member Role",
+                "summary": "Get Channel Message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Member ID",
-                        "name": "memberid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Role ID",
-                        "name": "roleid",
+                        "description": "Message ID",
+                        "name": "messageid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -1012,23 +1361,23 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Remove a role from a specific member in the guild.",
+                "description": "Delete a specific message in a channel by ID.",
                 "tags": [
-                    "Roles"
+                    "Messages"
                 ],
-                "summary": "Remove Member Role",
+                "summary": "Delete Channel Message",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Member ID",
-                        "name": "memberid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Role ID",
-                        "name": "roleid",
+                        "description": "Message ID",
+                        "name": "messageid",
                         "in": "path",
                         "required": true
                     }
@@ -1037,29 +1386,9 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {}
-                    }
-                }
-            }
-        },
-        "/api/guild/roles": {
-            "get": {
-                "description": "Retrieve all roles of a specific guild using the guild ID.",
-                "tags": [
-                    "Roles"
-                ],
-                "summary": "Get all roles in a guild",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/disgm.Role"
-                            }
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -1067,66 +1396,84 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new role in a guild using the provided role parameters.",
+            "patch": {
+                "description": "Edit a specific message in a channel by ID.",
                 "tags": [
-                    "Roles"
+                    "Messages"
                 ],
-                "summary": "Create a new role in a guild",
+                "summary": "Edit Channel Message",
                 "parameters": [
                     {
-                        "description": "Role parameters",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RoleParams"
-                        }
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Role"
+                            "$ref": "#/definitions/models.Message"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
                     }
                 }
-            },
-            "patch": {
-                "description": "Reorder the roles in a guild based on the provided positions.",
+            }
+        },
+        "/api/guild/channels/{channelid}/messages/{messageid}/poll": {
+            "get": {
+                "description": "Aggregate the votes of a message poll into counts and percentages.",
                 "tags": [
-                    "Roles"
+                    "Polls"
                 ],
-                "summary": "Update role positions in a guild",
+                "summary": "Get Poll Results",
                 "parameters": [
                     {
-                        "description": "New role positions",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Role"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Role"
-                            }
+                            "$ref": "#/definitions/disgm.PollResults"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -1134,29 +1481,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/guild/roles/{roleid}": {
-            "get": {
-                "description": "Retrieve a specific role from a guild by its role ID.",
+        "/api/guild/channels/{channelid}/messages/{messageid}/poll/announcement": {
+            "post": {
+                "description": "Post the results of a poll to a channel when it expires.",
                 "tags": [
-                    "Roles"
+                    "Polls"
                 ],
-                "summary": "Get a specific role in a guild",
+                "summary": "Schedule Poll Announcement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the role to retrieve",
-                        "name": "roleid",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Target channel",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.PollAnnouncement"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Role"
+                            "$ref": "#/definitions/disgm.PollAnnouncement"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -1164,16 +1538,23 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a specific role from a guild using its role ID.",
+                "description": "Cancel the scheduled results announcement of a poll.",
                 "tags": [
-                    "Roles"
+                    "Polls"
                 ],
-                "summary": "Delete a role from a guild",
+                "summary": "Cancel Poll Announcement",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the role to delete",
-                        "name": "roleid",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
                         "in": "path",
                         "required": true
                     }
@@ -1182,43 +1563,51 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {}
                     }
                 }
-            },
-            "patch": {
-                "description": "Update a specific role in a guild using the provided role data.",
+            }
+        },
+        "/api/guild/channels/{channelid}/messages/{messageid}/polls/expire": {
+            "post": {
+                "description": "End a message poll of the bot immediately.",
                 "tags": [
-                    "Roles"
+                    "Polls"
                 ],
-                "summary": "Update a specific role in a guild",
+                "summary": "Expire Poll",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID of the role to update",
-                        "name": "roleid",
+                        "description": "Channel ID",
+                        "name": "channelid",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated role parameters",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RoleParams"
-                        }
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Role"
+                            "$ref": "#/definitions/disgm.PollResults"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -1226,20 +1615,81 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/user": {
+        "/api/guild/channels/{channelid}/messages/{messageid}/polls/{answerid}/voters": {
             "get": {
-                "description": "Retrieve the bot's user information.",
+                "description": "Retrieve the users who voted for an answer of a message poll.",
                 "tags": [
-                    "User"
+                    "Polls"
+                ],
+                "summary": "Get Poll Voters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Answer ID",
+                        "name": "answerid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 25,
+                        "description": "Users per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get Bot User",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/disgm.User"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/disgm.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.User"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -1247,18 +1697,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/guilds/{guildid}/members/{memberid}": {
+        "/api/guild/channels/{channelid}/messages/{messageid}/reactions": {
             "delete": {
-                "description": "Remove a member from the specified guild.",
+                "description": "Remove all reactions from a specific message in a channel.",
                 "tags": [
-                    "Members"
+                    "Reactions"
                 ],
-                "summary": "Kick Member",
+                "summary": "Delete All Message Reactions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Member ID",
-                        "name": "memberid",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
                         "in": "path",
                         "required": true
                     }
@@ -1267,6 +1724,10 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {}
@@ -1274,274 +1735,7635 @@ const docTemplate = `{
                 }
             }
         },
-        "/ws": {
+        "/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid}": {
             "get": {
-                "description": "Sets up the WebSocket connection to handle Discord events and messages.",
-                "produces": [
-                    "application/json"
+                "description": "Retrieve all reactions from a specific message in a channel.",
+                "tags": [
+                    "Reactions"
+                ],
+                "summary": "Get Message Reactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Emoji ID",
+                        "name": "emojiid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Users per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Users of higher user IDs",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "normal",
+                            "burst"
+                        ],
+                        "type": "string",
+                        "description": "Reaction type, normal by default",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/disgm.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.User"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Add a reaction to a specific message in a channel.",
+                "tags": [
+                    "Reactions"
+                ],
+                "summary": "Create Message Reaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Emoji ID",
+                        "name": "emojiid",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a specific emoji reaction from a message in a channel.",
                 "tags": [
-                    "WebSocket"
+                    "Reactions"
                 ],
-                "summary": "Register WebSocket",
-                "responses": {}
+                "summary": "Delete Message Reaction Emoji",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Emoji ID",
+                        "name": "emojiid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
             }
-        }
-    },
-    "definitions": {
-        "disgm.Guild": {
-            "type": "object",
-            "properties": {
-                "afk_channel_id": {
-                    "description": "Optional ID of AFK channel",
-                    "type": "string"
-                },
-                "afk_timeout": {
-                    "description": "AFK timeout in seconds",
-                    "type": "integer"
+        },
+        "/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid}/{userid}": {
+            "delete": {
+                "description": "Delete a user's reaction from a specific message in a channel.",
+                "tags": [
+                    "Reactions"
+                ],
+                "summary": "Delete Message Reaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Emoji ID",
+                        "name": "emojiid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/messages/{messageid}/threads": {
+            "post": {
+                "description": "Start a thread from an existing message.",
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Start Message Thread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Channel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/permissions/{overwriteid}": {
+            "put": {
+                "description": "Edit permissions for a specific channel in the guild.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Edit Channel Permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Overwrite ID",
+                        "name": "overwriteid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific permission overwrite for a channel.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Delete Channel Permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Overwrite ID",
+                        "name": "overwriteid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/threads": {
+            "post": {
+                "description": "Create a public or private thread in a channel without a message.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Create Channel Thread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Channel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/threads/archived/private": {
+            "get": {
+                "description": "Retrieve a page of the archived private threads of a channel.",
+                "tags": [
+                    "Threads"
+                ],
+                "summary": "Get Private Archived Threads",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Threads archived before this RFC3339 time",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum number of threads",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ThreadsList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/threads/archived/public": {
+            "get": {
+                "description": "Retrieve a page of the archived public threads of a channel.",
+                "tags": [
+                    "Threads"
+                ],
+                "summary": "Get Public Archived Threads",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Threads archived before this RFC3339 time",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum number of threads",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ThreadsList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/transcript": {
+            "get": {
+                "description": "Render the message history of a channel as a JSON or self-contained HTML transcript.",
+                "produces": [
+                    "application/json",
+                    "text/html"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Get Channel Transcript",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Transcript format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Maximum number of messages",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Transcript"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/webhooks": {
+            "get": {
+                "description": "Retrieve the webhooks of a channel.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get Channel Webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Webhook"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a webhook in a channel.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create Channel Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/channels/{channelid}/webhooks/{webhookid}": {
+            "delete": {
+                "description": "Delete a webhook of a channel.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete Channel Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Edit the name or avatar of a webhook, or move it to another channel.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Update Channel Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channelid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/commands": {
+            "get": {
+                "description": "Retrieve all guild application commands.",
+                "tags": [
+                    "Commands"
+                ],
+                "summary": "Get Guild Application Commands",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.ApplicationCommand"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new guild application command.",
+                "tags": [
+                    "Commands"
+                ],
+                "summary": "Create Guild Application Command",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationCommand"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/commands/{cmdid}": {
+            "get": {
+                "description": "Retrieve a specific guild application command by ID.",
+                "tags": [
+                    "Commands"
+                ],
+                "summary": "Get Guild Application Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Command ID",
+                        "name": "cmdid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationCommand"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a guild application command by ID.",
+                "tags": [
+                    "Commands"
+                ],
+                "summary": "Delete Guild Application Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Command ID",
+                        "name": "cmdid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a guild application command by ID, keeping its permissions.",
+                "tags": [
+                    "Commands"
+                ],
+                "summary": "Update Guild Application Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Command ID",
+                        "name": "cmdid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changed command fields",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationCommand"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationCommand"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/delivery-report": {
+            "get": {
+                "description": "Summarize the event deliveries and failures per WebSocket client and webhook sink over the last 24 hours.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get Delivery Report",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.DeliveryReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/guild/diff": {
+            "get": {
+                "description": "Compare the current guild structure against a stored snapshot.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Diff",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Snapshot ID",
+                        "name": "since",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildDiff"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/discovery": {
+            "get": {
+                "description": "Retrieve the discovery description, categories and keywords of the guild.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Discovery",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildDiscovery"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update the discovery description, categories and keywords of a community guild.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Update Guild Discovery",
+                "parameters": [
+                    {
+                        "description": "Discovery changes",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildDiscoveryParams"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the listing as read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildDiscovery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/emojis": {
+            "get": {
+                "description": "Retrieve all custom emojis of the guild.",
+                "tags": [
+                    "Emojis"
+                ],
+                "summary": "Get Guild Emojis",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Emoji"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a custom emoji in the guild from a base64 image.",
+                "tags": [
+                    "Emojis"
+                ],
+                "summary": "Create Guild Emoji",
+                "parameters": [
+                    {
+                        "description": "Emoji parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmojiParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Emoji"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/emojis/{emojiid}": {
+            "get": {
+                "description": "Retrieve a specific custom emoji of the guild by its ID.",
+                "tags": [
+                    "Emojis"
+                ],
+                "summary": "Get Guild Emoji",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Emoji ID",
+                        "name": "emojiid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Emoji"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a custom emoji from the guild.",
+                "tags": [
+                    "Emojis"
+                ],
+                "summary": "Delete Guild Emoji",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Emoji ID",
+                        "name": "emojiid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Edit the name or allowed roles of a custom emoji.",
+                "tags": [
+                    "Emojis"
+                ],
+                "summary": "Update Guild Emoji",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Emoji ID",
+                        "name": "emojiid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Emoji parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EmojiParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Emoji"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/events/export": {
+            "get": {
+                "description": "Stream the recorded events of the guild as NDJSON.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Export Guild Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated event names",
+                        "name": "types",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.RecordedEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/events/replay": {
+            "post": {
+                "description": "Replay recorded events to a single WebSocket client or webhook.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Replay Guild Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated event names",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Replay target",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ReplayTarget"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ReplayResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/feeds": {
+            "get": {
+                "description": "Retrieve the RSS and Atom feed subscriptions of the guild.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Get Guild Feeds",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Feed"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a channel to an RSS or Atom feed.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Create Guild Feed",
+                "parameters": [
+                    {
+                        "description": "Feed settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Feed"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/feeds/{feedid}": {
+            "get": {
+                "description": "Retrieve a feed subscription and its polling status.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Get Guild Feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed ID",
+                        "name": "feedid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Feed"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a feed subscription.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Delete Guild Feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed ID",
+                        "name": "feedid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the settings of a feed subscription.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Update Guild Feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed ID",
+                        "name": "feedid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feed settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Feed"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/giveaways": {
+            "get": {
+                "description": "Retrieve the running and ended giveaways of the guild.",
+                "tags": [
+                    "Giveaways"
+                ],
+                "summary": "Get Guild Giveaways",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Giveaway"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Start a reaction or button giveaway in a channel.",
+                "tags": [
+                    "Giveaways"
+                ],
+                "summary": "Create Guild Giveaway",
+                "parameters": [
+                    {
+                        "description": "Giveaway settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Giveaway"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Giveaway"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/giveaways/{giveawayid}": {
+            "get": {
+                "description": "Retrieve a giveaway of the guild.",
+                "tags": [
+                    "Giveaways"
+                ],
+                "summary": "Get Guild Giveaway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Giveaway ID",
+                        "name": "giveawayid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Giveaway"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel a running giveaway or forget an ended one.",
+                "tags": [
+                    "Giveaways"
+                ],
+                "summary": "Delete Guild Giveaway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Giveaway ID",
+                        "name": "giveawayid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/giveaways/{giveawayid}/entrants": {
+            "get": {
+                "description": "Retrieve the eligible entrants of a giveaway.",
+                "tags": [
+                    "Giveaways"
+                ],
+                "summary": "Get Giveaway Entrants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Giveaway ID",
+                        "name": "giveawayid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "users"
+                        ],
+                        "type": "string",
+                        "description": "Return user objects",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/giveaways/{giveawayid}/reroll": {
+            "post": {
+                "description": "Draw new winners for an ended giveaway.",
+                "tags": [
+                    "Giveaways"
+                ],
+                "summary": "Reroll Giveaway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Giveaway ID",
+                        "name": "giveawayid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of winners to replace",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Giveaway"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/hooks": {
+            "get": {
+                "description": "Retrieve the inbound webhook integrations of the guild.",
+                "tags": [
+                    "Hooks"
+                ],
+                "summary": "Get Guild Hooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Hook"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/guild/hooks/{integration}": {
+            "put": {
+                "description": "Register or replace an inbound webhook integration.",
+                "tags": [
+                    "Hooks"
+                ],
+                "summary": "Put Guild Hook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Integration name",
+                        "name": "integration",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Integration settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Hook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove an inbound webhook integration.",
+                "tags": [
+                    "Hooks"
+                ],
+                "summary": "Delete Guild Hook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Integration name",
+                        "name": "integration",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/interactions/{apptoken}/followups": {
+            "post": {
+                "description": "Send a followup message for an interaction after its initial callback.",
+                "tags": [
+                    "Interactions"
+                ],
+                "summary": "Create Followup Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Interaction Token",
+                        "name": "apptoken",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/interactions/{apptoken}/followups/{messageid}": {
+            "delete": {
+                "description": "Delete a followup message of an interaction.",
+                "tags": [
+                    "Interactions"
+                ],
+                "summary": "Delete Followup Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Interaction Token",
+                        "name": "apptoken",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Edit a followup message of an interaction.",
+                "tags": [
+                    "Interactions"
+                ],
+                "summary": "Update Followup Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Interaction Token",
+                        "name": "apptoken",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "messageid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/interactions/{interactionid}/{interactiontoken}/autocomplete": {
+            "post": {
+                "description": "Answer an autocomplete interaction with up to 25 choices.",
+                "tags": [
+                    "Interactions"
+                ],
+                "summary": "Create Autocomplete Response",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Interaction ID",
+                        "name": "interactionid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interaction Token",
+                        "name": "interactiontoken",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Choices",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.AutocompleteParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/interactions/{interactionid}/{interactiontoken}/callback": {
+            "post": {
+                "description": "Handle interaction callback for a specific interaction. Modals (type 9) are validated before they are sent.",
+                "tags": [
+                    "Interactions"
+                ],
+                "summary": "Create Interaction Callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Interaction ID",
+                        "name": "interactionid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interaction Token",
+                        "name": "interactiontoken",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/invites": {
+            "get": {
+                "description": "Retrieve the invites of all channels of the guild.",
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Get Guild Invites",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Invite"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/invites/{code}": {
+            "delete": {
+                "description": "Revoke an invite of the guild by its code.",
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Delete Guild Invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Invite"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/jobs": {
+            "get": {
+                "description": "Retrieve the bulk jobs of the guild with their progress and ETA.",
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Get Guild Jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Job"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Start a bulk ban, role assignment or purge at the pace of the rate limits, reporting its plan and ETA.",
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Create Guild Job",
+                "parameters": [
+                    {
+                        "description": "Job",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.JobParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Job"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/jobs/import": {
+            "post": {
+                "description": "Start a job importing a zip archive or a list of URLs as guild emojis or stickers, with per-file results.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Import Emojis or Stickers",
+                "parameters": [
+                    {
+                        "description": "URLs to import",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ImportParams"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Zip archive of the files",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "emoji_import or sticker_import, with file",
+                        "name": "kind",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to only plan the import, with file",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Job"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/jobs/{jobid}": {
+            "get": {
+                "description": "Retrieve a bulk job of the guild with its progress and ETA.",
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Get Guild Job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel a running bulk job of the guild, or remove a finished one.",
+                "tags": [
+                    "Jobs"
+                ],
+                "summary": "Cancel Guild Job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "jobid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/members": {
+            "get": {
+                "description": "Retrieve a page of members of the guild, ordered by user ID.",
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get Guild Members",
+                "parameters": [
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Members per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/disgm.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/disgm.Member"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/members/{memberid}": {
+            "get": {
+                "description": "Retrieve a specific member from the guild by ID.",
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get Guild Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Member"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a member from the specified guild.",
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Kick Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a specific member in the guild, or the nickname of the bot for @me.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Update Guild Member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID, or @me to change the nickname of the bot",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to update, comma-separated; missing ones are cleared",
+                        "name": "X-Update-Mask",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Member"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/members/{memberid}/images": {
+            "get": {
+                "description": "Retrieve the CDN URLs of the guild and user avatar and banner of a member.",
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get Member Images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image size, a power of two from 16 to 4096",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.MemberImages"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Set the guild avatar and banner of the bot.",
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Update Member Images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID, @me or the bot's ID",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Guild images",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.MemberImagesParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.MemberImages"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/members/{memberid}/roles": {
+            "get": {
+                "description": "Retrieve all roles assigned to a specific member in the guild.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get Member Roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/members/{memberid}/roles/{roleid}": {
+            "put": {
+                "description": "Add a role to a specific member in the guild.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Add Member Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "roleid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a role from a specific member in the guild.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Remove Member Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Member ID",
+                        "name": "memberid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "roleid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/modules": {
+            "get": {
+                "description": "Retrieve the API modules and whether they are enabled for the guild.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Modules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.ModuleState"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/modules/{module}": {
+            "put": {
+                "description": "Enable or disable an API module for the guild.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Set Guild Module",
+                "parameters": [
+                    {
+                        "enum": [
+                            "messages",
+                            "moderation",
+                            "webhooks",
+                            "analytics"
+                        ],
+                        "type": "string",
+                        "description": "Module",
+                        "name": "module",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Module state",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ModuleState"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ModuleState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/overwrite-templates": {
+            "get": {
+                "description": "Retrieve the permission overwrite templates of the guild.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Get Overwrite Templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.OverwriteTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/overwrite-templates/{name}": {
+            "get": {
+                "description": "Retrieve a permission overwrite template of the guild by name.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Get Overwrite Template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.OverwriteTemplate"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Create or replace a named permission overwrite template.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Put Overwrite Template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.OverwriteTemplate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.OverwriteTemplate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a permission overwrite template of the guild.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Delete Overwrite Template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/overwrite-templates/{name}/apply": {
+            "post": {
+                "description": "Set the overwrites of a template on channels, rolling back on failure.",
+                "tags": [
+                    "Channels"
+                ],
+                "summary": "Apply Overwrite Template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Channels",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ApplyTemplateRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ApplyTemplateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/policy": {
+            "get": {
+                "description": "Retrieve the desired security settings of the guild.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildPolicy"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Set the desired security settings of the guild, checked on every change.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Put Guild Policy",
+                "parameters": [
+                    {
+                        "description": "Policy",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildPolicy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the settings policy of the guild.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Delete Guild Policy",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/policy/violations": {
+            "get": {
+                "description": "Check the guild settings and roles against its policy.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Policy Violations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.PolicyReport"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/polls/announcements": {
+            "get": {
+                "description": "Retrieve the scheduled poll result announcements of the guild.",
+                "tags": [
+                    "Polls"
+                ],
+                "summary": "Get Poll Announcements",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.PollAnnouncement"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/guild/projections/channels": {
+            "get": {
+                "description": "Retrieve the channels of the guild by category with overwrite summaries, kept in memory from gateway events.",
+                "tags": [
+                    "Projections"
+                ],
+                "summary": "Get Channel Tree",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.ChannelTree"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/projections/members": {
+            "get": {
+                "description": "Retrieve the members of the guild with their roles resolved, kept in memory from gateway events.",
+                "tags": [
+                    "Projections"
+                ],
+                "summary": "Get Member Directory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID to filter by",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.MemberDirectory"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/regions": {
+            "get": {
+                "description": "Retrieve the voice regions available to the guild, e.g. for the RTC region of voice channels.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Regions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.VoiceRegion"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/relays": {
+            "get": {
+                "description": "Retrieve the message relays of the guild and the relays of other guilds into it.",
+                "tags": [
+                    "Relays"
+                ],
+                "summary": "Get Guild Relays",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Relay"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Mirror messages from a source channel into a target channel.",
+                "tags": [
+                    "Relays"
+                ],
+                "summary": "Create Guild Relay",
+                "parameters": [
+                    {
+                        "description": "Relay settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Relay"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Relay"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/relays/{relayid}": {
+            "delete": {
+                "description": "Remove a message relay.",
+                "tags": [
+                    "Relays"
+                ],
+                "summary": "Delete Guild Relay",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Relay ID",
+                        "name": "relayid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/relays/{relayid}/confirm": {
+            "post": {
+                "description": "Confirm a pending relay of another guild into a channel of the guild.",
+                "tags": [
+                    "Relays"
+                ],
+                "summary": "Confirm Guild Relay",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Relay ID",
+                        "name": "relayid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Relay"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/roles": {
+            "get": {
+                "description": "Retrieve all roles of a specific guild using the guild ID.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get all roles in a guild",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Role"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new role in a guild using the provided role parameters.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Create a new role in a guild",
+                "parameters": [
+                    {
+                        "description": "Role parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoleParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Reorder the roles in a guild based on the provided positions.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Update role positions in a guild",
+                "parameters": [
+                    {
+                        "description": "New role positions",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/roles/{roleid}": {
+            "get": {
+                "description": "Retrieve a specific role from a guild by its role ID.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Get a specific role in a guild",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the role to retrieve",
+                        "name": "roleid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a specific role from a guild using its role ID.",
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Delete a role from a guild",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the role to delete",
+                        "name": "roleid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Approval"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a specific role in a guild using the provided role data.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Update a specific role in a guild",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the role to update",
+                        "name": "roleid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated role parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoleParams"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fields to update, comma-separated; missing ones are cleared",
+                        "name": "X-Update-Mask",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the role as read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/roles/{roleid}/icon": {
+            "put": {
+                "description": "Set the icon of a role to an image or a unicode emoji.",
+                "consumes": [
+                    "application/json",
+                    "image/png",
+                    "image/jpeg",
+                    "image/gif"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Update Role Icon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "roleid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Icon image or emoji",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.RoleIconParams"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/scheduled-events/{eventid}/users": {
+            "get": {
+                "description": "Retrieve a page of the users interested in a scheduled event.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Scheduled Event Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheduled event ID",
+                        "name": "eventid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of users",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the guild member of each user",
+                        "name": "with_member",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users with a lower ID",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users with a higher ID",
+                        "name": "after",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduledEventUser"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/search/messages": {
+            "get": {
+                "description": "Full-text search over the logged message history of the guild.",
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Search Guild Messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Words the messages must contain",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author user ID",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated content kinds: link, embed, file, image, video, sound",
+                        "name": "has",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages older than this message ID",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 25,
+                        "description": "Number of messages",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.MessageSearchResult"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/settings": {
+            "get": {
+                "description": "Retrieve the settings of the guild, e.g. the audit-reason template.",
+                "tags": [
+                    "Settings"
+                ],
+                "summary": "Get Guild Settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildSettings"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the settings of the guild, e.g. the audit-reason template and the activity digest.",
+                "tags": [
+                    "Settings"
+                ],
+                "summary": "Set Guild Settings",
+                "parameters": [
+                    {
+                        "description": "Settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/settings/token": {
+            "get": {
+                "description": "Retrieve the settings of the token of the request, e.g. its audit-reason template.",
+                "tags": [
+                    "Settings"
+                ],
+                "summary": "Get Token Settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.TokenSettings"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the settings of the token of the request, e.g. its audit-reason template.",
+                "tags": [
+                    "Settings"
+                ],
+                "summary": "Set Token Settings",
+                "parameters": [
+                    {
+                        "description": "Settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.TokenSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.TokenSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/snapshot": {
+            "get": {
+                "description": "Retrieve the guild, channels, roles, emojis and optionally member counts in one response, from the state.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated parts: guild, channels, roles, emojis, members",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildSnapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/snapshots": {
+            "post": {
+                "description": "Store a snapshot of the guild channels, roles and overwrites.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Create Guild Snapshot",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Snapshot"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/stickers": {
+            "get": {
+                "description": "Retrieve all stickers of the guild.",
+                "tags": [
+                    "Stickers"
+                ],
+                "summary": "Get Guild Stickers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Sticker"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Upload a sticker to the guild.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "Stickers"
+                ],
+                "summary": "Create Guild Sticker",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Sticker file (PNG, APNG, GIF or Lottie JSON, max 512 KB)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the sticker",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of the sticker",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Autocomplete and suggestion tags",
+                        "name": "tags",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sticker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/stickers/{stickerid}": {
+            "get": {
+                "description": "Retrieve a specific sticker of the guild by its ID.",
+                "tags": [
+                    "Stickers"
+                ],
+                "summary": "Get Guild Sticker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sticker ID",
+                        "name": "stickerid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sticker"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a sticker from the guild.",
+                "tags": [
+                    "Stickers"
+                ],
+                "summary": "Delete Guild Sticker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sticker ID",
+                        "name": "stickerid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Edit the name, description or tags of a guild sticker.",
+                "tags": [
+                    "Stickers"
+                ],
+                "summary": "Update Guild Sticker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sticker ID",
+                        "name": "stickerid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sticker parameters",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StickerParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sticker"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/tasks": {
+            "get": {
+                "description": "Retrieve the scheduled tasks of the guild.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Get Guild Tasks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Task"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Schedule a built-in action: send_message, purge, sync_commands, backup or digest.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Create Guild Task",
+                "parameters": [
+                    {
+                        "description": "Task",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Task"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/tasks/{taskid}": {
+            "get": {
+                "description": "Retrieve a scheduled task and the result of its last run.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Get Guild Task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "taskid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Task"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a scheduled task.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Delete Guild Task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "taskid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the schedule, action, parameters or paused state of a task.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Update Guild Task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "taskid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Task"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/tasks/{taskid}/run": {
+            "post": {
+                "description": "Run a task immediately and return the result.",
+                "tags": [
+                    "Tasks"
+                ],
+                "summary": "Run Guild Task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "taskid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Task"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/threads/active": {
+            "get": {
+                "description": "Retrieve all active threads of the guild with the bot's thread members.",
+                "tags": [
+                    "Threads"
+                ],
+                "summary": "Get Active Threads",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ThreadsList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/tokens": {
+            "get": {
+                "description": "Retrieve the tokens issued for the guild, without the tokens themselves.",
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Get Guild Tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.GuildToken"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    }
+                }
+            },
+            "post": {
+                "description": "Issue an additional token for the guild, optionally restricted to channels.",
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Issue Guild Token",
+                "parameters": [
+                    {
+                        "description": "Token name, scope and acting user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.GuildToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/tokens/{tokenid}": {
+            "delete": {
+                "description": "Revoke a token issued for the guild.",
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Revoke Guild Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token ID",
+                        "name": "tokenid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/undo": {
+            "get": {
+                "description": "Retrieve the reversible changes that can still be undone.",
+                "tags": [
+                    "Undo"
+                ],
+                "summary": "Get Recent Changes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Change"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/guild/undo/{changeid}": {
+            "post": {
+                "description": "Revert a recent role, channel or permission overwrite change.",
+                "tags": [
+                    "Undo"
+                ],
+                "summary": "Undo Change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Change ID",
+                        "name": "changeid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Allow changing a protected resource",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/users/resolve": {
+            "post": {
+                "description": "Resolve up to 1000 user IDs to user objects, using the user cache.",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Resolve Guild Users",
+                "parameters": [
+                    {
+                        "description": "User IDs, e.g. {\\",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/disgm.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/webhooks": {
+            "get": {
+                "description": "Retrieve the webhooks of all channels of the guild.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get Guild Webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.Webhook"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/webhooks/{webhookid}/{token}/execute": {
+            "post": {
+                "description": "Send a message through a webhook of the guild.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Execute Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for the message and return it",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Thread to post in",
+                        "name": "thread_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/welcome-screen": {
+            "get": {
+                "description": "Retrieve the description and channels of the guild's welcome screen.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Welcome Screen",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.WelcomeScreen"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update the description and channels of the guild's welcome screen.",
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Update Welcome Screen",
+                "parameters": [
+                    {
+                        "description": "Welcome screen changes",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/disgm.WelcomeScreenParams"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the welcome screen as read",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.WelcomeScreen"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {}
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/widget.png": {
+            "get": {
+                "description": "Retrieve the widget image of the guild, cached for 5 minutes.",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "Guild"
+                ],
+                "summary": "Get Guild Widget Image",
+                "parameters": [
+                    {
+                        "enum": [
+                            "shield",
+                            "banner1",
+                            "banner2",
+                            "banner3",
+                            "banner4"
+                        ],
+                        "type": "string",
+                        "description": "Image style",
+                        "name": "style",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/ws/clients": {
+            "get": {
+                "description": "Retrieve the WebSocket clients connected for the guild with their labels and message counters.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get WebSocket Clients",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/disgm.WSClient"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/guild/ws/clients/{clientid}": {
+            "delete": {
+                "description": "Disconnect a WebSocket client of the guild, e.g. one that falls behind.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Kick WebSocket Client",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client ID",
+                        "name": "clientid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/guild/ws/latency": {
+            "get": {
+                "description": "Retrieve the latency of the interactions forwarded to the WebSocket clients of the guild.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get Interaction Latency",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.InteractionLatency"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/health": {
+            "get": {
+                "description": "Retrieve the state of the gateway connection and the gateway intents the session lacks.",
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Get Health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/health/ready": {
+            "get": {
+                "description": "Report whether the gateway is ready and the error budget is kept, for readiness probes.",
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Get Readiness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/invites/{code}": {
+            "get": {
+                "description": "Retrieve an invite of the guild by its code, optionally with the member counts of the guild.",
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Get Invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Whether to include the approximate member counts",
+                        "name": "with_counts",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Invite"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    }
+                }
+            },
+            "delete": {
+                "description": "Revoke an invite of the guild by its code.",
+                "tags": [
+                    "Invites"
+                ],
+                "summary": "Delete Guild Invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.Invite"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/schema/events": {
+            "get": {
+                "description": "Retrieve the JSON Schema of every WebSocket event envelope and payload.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get Event Schema",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/schema/events.proto": {
+            "get": {
+                "description": "Retrieve the .proto definitions of the WebSocket events for the proto encoding.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get Event Protobuf Definitions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user": {
+            "get": {
+                "description": "Retrieve the bot's user information.",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get Bot User",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.User"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/api/ws/protocol": {
+            "get": {
+                "description": "Retrieve the versions, frame formats, ops and close codes of the WebSocket protocol.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Get WebSocket Protocol",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/disgm.WSProtocol"
+                        }
+                    }
+                }
+            }
+        },
+        "/hooks/{integration}": {
+            "post": {
+                "description": "Receive a signed webhook from an external service and post it to Discord.",
+                "tags": [
+                    "Hooks"
+                ],
+                "summary": "Receive Hook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Integration name",
+                        "name": "integration",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {}
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {}
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {}
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {}
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "Sets up the WebSocket connection to handle Discord events and messages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "WebSocket"
+                ],
+                "summary": "Register WebSocket",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Protocol version, see /api/ws/protocol",
+                        "name": "v",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the client, shown in logs and the clients endpoint",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version of the client",
+                        "name": "client_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "What the client uses the connection for",
+                        "name": "client_purpose",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "proto"
+                        ],
+                        "type": "string",
+                        "description": "Encoding of the events, json (default) or proto",
+                        "name": "encoding",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {}
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "disgm.APIAuditEntry": {
+            "type": "object",
+            "properties": {
+                "acting_user": {
+                    "description": "Dashboard user the token acts for, if any",
+                    "type": "string"
+                },
+                "credential": {
+                    "description": "Credential of the request: guild, token:\u003cid\u003e for issued tokens, or impersonation:\u003chash\u003e",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Sortable ID of the entry within its guild",
+                    "type": "string"
+                },
+                "method": {
+                    "description": "HTTP method of the request",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path of the request",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Audit-log reason sent with the request, if any",
+                    "type": "string"
+                },
+                "route": {
+                    "description": "Route of the request, e.g. /api/guild/roles/:roleid",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "HTTP status of the response",
+                    "type": "integer"
+                },
+                "time": {
+                    "description": "When the request was answered",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.AdminGuild": {
+            "type": "object",
+            "properties": {
+                "clients": {
+                    "description": "Connected WebSocket clients",
+                    "type": "integer"
+                },
+                "events_out": {
+                    "description": "Events sent to the connected clients",
+                    "type": "integer"
+                },
+                "has_token": {
+                    "description": "Whether the token store has a token for the guild",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "member_count": {
+                    "type": "integer"
+                },
+                "messages_in": {
+                    "description": "Messages received from the connected clients",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slow_clients": {
+                    "description": "Connected WebSocket clients whose writes are slow",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.ApplyTemplateRequest": {
+            "type": "object",
+            "properties": {
+                "channel_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "disgm.ApplyTemplateResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "disgm.Approval": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action that will be executed, e.g. \"channel_delete\"",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "When the approval was requested",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Discord error if the approved action failed",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "When the approval can no longer be approved",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the approval",
+                    "type": "string"
+                },
+                "requested_by": {
+                    "description": "Acting user that requested the action, for display only",
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "description": "Acting user that approved or rejected the action, for display only",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Current state of the approval",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "IDs of the channels, roles or users affected",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "disgm.AuditLogChange": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "description": "Name of the changed field",
+                    "type": "string"
+                },
+                "new_value": {
+                    "description": "New value of the field, if any",
+                    "type": "object"
+                },
+                "old_value": {
+                    "description": "Old value of the field, if any",
+                    "type": "object"
+                }
+            }
+        },
+        "disgm.AuditLogEntry": {
+            "type": "object",
+            "properties": {
+                "action_type": {
+                    "description": "Type of the action, see the Discord audit log events",
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Changes made to the target",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.AuditLogChange"
+                    }
+                },
+                "id": {
+                    "description": "ID of the entry",
+                    "type": "string"
+                },
+                "options": {
+                    "description": "Additional info for some action types",
+                    "type": "object"
+                },
+                "reason": {
+                    "description": "Reason for the change, 1 to 512 characters",
+                    "type": "string"
+                },
+                "target_id": {
+                    "description": "ID of the affected entity, e.g. a user, role or channel",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "ID of the user or app that made the change",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.AutocompleteParams": {
+            "type": "object",
+            "properties": {
+                "choices": {
+                    "description": "Up to 25 choices, an empty list shows none",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ApplicationCommandOptionChoice"
+                    }
+                }
+            }
+        },
+        "disgm.BatchOptions": {
+            "type": "object",
+            "properties": {
+                "coalesce": {
+                    "description": "Whether presence and typing updates are coalesced per user",
+                    "type": "boolean"
+                },
+                "interval_ms": {
+                    "description": "Time events are collected before they are sent, 10 to 5000",
+                    "type": "integer"
+                },
+                "max_events": {
+                    "description": "Events that fill a batch, up to 1000, defaults to 100",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.CacheStats": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "description": "Estimated memory used by the entries",
+                    "type": "integer"
+                },
+                "entries": {
+                    "description": "Number of cached entries",
+                    "type": "integer"
+                },
+                "evictions": {
+                    "description": "Entries evicted for the memory or entry limit",
+                    "type": "integer"
+                },
+                "hits": {
+                    "description": "Lookups served from the cache",
+                    "type": "integer"
+                },
+                "max_entries": {
+                    "description": "Maximum number of entries of the cache, if limited",
+                    "type": "integer"
+                },
+                "misses": {
+                    "description": "Lookups of missing or expired entries",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name of the cache",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Change": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "When the change was made",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the change",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind of change, e.g. \"role_edit\"",
+                    "type": "string"
+                },
+                "target_id": {
+                    "description": "ID of the changed channel or role, or the guild for role positions",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.ChannelChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "Value after the change"
+                },
+                "before": {
+                    "description": "Value before the change"
+                },
+                "field": {
+                    "description": "Changed field: name, topic or permission_overwrite",
+                    "type": "string"
+                },
+                "overwrite_id": {
+                    "description": "ID of the role or member of a changed permission overwrite",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.ChannelHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Changed fields",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ChannelChange"
+                    }
+                },
+                "channel_id": {
+                    "description": "ID of the updated channel",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Sortable ID of the entry within its channel",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Audit log reason of the change",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "When the update was received",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "User who made the change, from the audit log if readable",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.ChannelNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "Channels of a category, in display order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ChannelNode"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "overwrites": {
+                    "$ref": "#/definitions/disgm.OverwriteSummary"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.ChannelShape": {
+            "type": "object",
+            "properties": {
+                "bitrate": {
+                    "description": "Bitrate of a voice channel",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Snowflake ID of the channel",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the channel",
+                    "type": "string"
+                },
+                "nsfw": {
+                    "description": "Whether the channel is NSFW",
+                    "type": "boolean"
+                },
+                "parent_id": {
+                    "description": "ID of the parent category",
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Sorting position of the channel",
+                    "type": "integer"
+                },
+                "rate_limit_per_user": {
+                    "description": "Slowmode in seconds",
+                    "type": "integer"
+                },
+                "topic": {
+                    "description": "Topic of the channel",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of the channel",
+                    "type": "integer"
+                },
+                "user_limit": {
+                    "description": "User limit of a voice channel",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.ChannelTree": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "Categories and the channels outside of them, in display order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ChannelNode"
+                    }
+                },
+                "updated_at": {
+                    "description": "When the last gateway event changed the tree",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.DeliveryReport": {
+            "type": "object",
+            "properties": {
+                "delivered": {
+                    "description": "Events delivered to all sinks",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Events that failed to be delivered to all sinks",
+                    "type": "integer"
+                },
+                "since": {
+                    "description": "Start of the window",
+                    "type": "string"
+                },
+                "sinks": {
+                    "description": "Sinks with deliveries in the window, most failures first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.SinkDelivery"
+                    }
+                }
+            }
+        },
+        "disgm.DigestSettings": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "Channel the summary is posted to",
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Summarized events: joins, leaves, messages and moderation, all by default",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "period": {
+                    "description": "daily or weekly",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.DirectoryMember": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "description": "Guild avatar, or user avatar",
+                    "type": "string"
+                },
+                "bot": {
+                    "description": "Whether the member is a bot",
+                    "type": "boolean"
+                },
+                "color": {
+                    "description": "Color of the highest colored role, 0 for none",
+                    "type": "integer"
+                },
+                "display_name": {
+                    "description": "Nickname, global name or username",
+                    "type": "string"
+                },
+                "joined_at": {
+                    "description": "When the member joined the guild",
+                    "type": "string"
+                },
+                "pending": {
+                    "description": "Whether the member has not passed membership screening",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "description": "Roles of the member, highest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.DirectoryRole"
+                    }
+                },
+                "timed_out_until": {
+                    "description": "When the timeout of the member ends",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.DirectoryRole": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.DiscordErrorStatus": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Discord JSON error code, 0 for errors without one, e.g. rate limits",
+                    "type": "integer"
+                },
+                "count": {
+                    "description": "Errors in the window",
+                    "type": "integer"
+                },
+                "http_status": {
+                    "description": "HTTP status of the latest error",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Message of the latest error",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Emoji": {
+            "type": "object",
+            "properties": {
+                "animated": {
+                    "description": "Whether the emoji is animated",
+                    "type": "boolean"
+                },
+                "available": {
+                    "description": "Whether the emoji can be used, false if lost due to boost removal",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "Define fields for Emoji structure based on your needs",
+                    "type": "string"
+                },
+                "managed": {
+                    "description": "Whether the emoji is managed by an application",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Name of the emoji",
+                    "type": "string"
+                },
+                "require_colons": {
+                    "description": "Whether the emoji requires colons",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "description": "IDs of the roles allowed to use the emoji",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "description": "Optional user object that created the emoji",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.Feed": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "ID of the channel new items are posted to",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the feed subscription",
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "Polling interval in minutes",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Result of the latest polls",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.FeedStatus"
+                        }
+                    ]
+                },
+                "template": {
+                    "description": "Go text/template for the message, executed with a FeedItem",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL of the RSS or Atom document",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.FeedStatus": {
+            "type": "object",
+            "properties": {
+                "last_error": {
+                    "description": "Error of the latest poll, if it failed",
+                    "type": "string"
+                },
+                "last_poll": {
+                    "description": "When the feed was last fetched",
+                    "type": "string"
+                },
+                "last_post": {
+                    "description": "When an item was last posted",
+                    "type": "string"
+                },
+                "posted": {
+                    "description": "Number of items posted since the feed was created",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.Giveaway": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "Channel of the giveaway message",
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "Duration in seconds, used on creation",
+                    "type": "integer"
+                },
+                "emoji": {
+                    "description": "Entry emoji of reaction giveaways, defaults to 🎉",
+                    "type": "string"
+                },
+                "ended": {
+                    "description": "Whether the winners were drawn",
+                    "type": "boolean"
+                },
+                "ends_at": {
+                    "description": "When the winners are drawn",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the giveaway",
+                    "type": "string"
+                },
+                "message_id": {
+                    "description": "ID of the giveaway message",
+                    "type": "string"
+                },
+                "mode": {
+                    "description": "GiveawayReaction (default) or GiveawayButton",
+                    "type": "string"
+                },
+                "past_winners": {
+                    "description": "User IDs of winners replaced by rerolls",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prize": {
+                    "description": "Prize shown in the giveaway message",
+                    "type": "string"
+                },
+                "requirements": {
+                    "description": "Conditions entrants must meet",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.GiveawayRequirements"
+                        }
+                    ]
+                },
+                "winner_count": {
+                    "description": "Number of winners, defaults to 1",
+                    "type": "integer"
+                },
+                "winners": {
+                    "description": "User IDs of the current winners",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "disgm.GiveawayRequirements": {
+            "type": "object",
+            "properties": {
+                "min_account_age_days": {
+                    "description": "Minimum age of the Discord account",
+                    "type": "integer"
+                },
+                "min_member_days": {
+                    "description": "Minimum time since joining the guild",
+                    "type": "integer"
+                },
+                "role_ids": {
+                    "description": "The user needs one of these roles, if set",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "disgm.Guild": {
+            "type": "object",
+            "properties": {
+                "afk_channel_id": {
+                    "description": "Optional ID of AFK channel",
+                    "type": "string"
+                },
+                "afk_timeout": {
+                    "description": "AFK timeout in seconds",
+                    "type": "integer"
+                },
+                "application_id": {
+                    "description": "Optional application ID if bot-created",
+                    "type": "string"
+                },
+                "approximate_member_count": {
+                    "description": "Optional approximate number of members",
+                    "type": "integer"
+                },
+                "approximate_presence_count": {
+                    "description": "Optional approximate non-offline members",
+                    "type": "integer"
+                },
+                "banner": {
+                    "description": "Optional banner hash",
+                    "type": "string"
+                },
+                "default_message_notifications": {
+                    "description": "Default message notifications level",
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "Optional description of the guild",
+                    "type": "string"
+                },
+                "discovery_splash": {
+                    "description": "Optional discovery splash hash for discoverable guilds",
+                    "type": "string"
+                },
+                "emojis": {
+                    "description": "Custom guild emojis",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Emoji"
+                    }
+                },
+                "explicit_content_filter": {
+                    "description": "Explicit content filter level",
+                    "type": "integer"
+                },
+                "features": {
+                    "description": "Enabled guild features",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "icon": {
+                    "description": "Optional icon hash",
+                    "type": "string"
+                },
+                "icon_hash": {
+                    "description": "Optional icon hash returned in the template object",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Snowflake ID of the guild",
+                    "type": "string"
+                },
+                "max_members": {
+                    "description": "Maximum number of members for the guild",
+                    "type": "integer"
+                },
+                "max_presences": {
+                    "description": "Optional maximum presences for the guild",
+                    "type": "integer"
+                },
+                "max_stage_video_channel_users": {
+                    "description": "Maximum users in a stage video channel",
+                    "type": "integer"
+                },
+                "max_video_channel_users": {
+                    "description": "Maximum users in a video channel",
+                    "type": "integer"
+                },
+                "mfa_level": {
+                    "description": "Required MFA level for the guild",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name of the guild (2-100 characters)",
+                    "type": "string"
+                },
+                "nsfw_level": {
+                    "description": "NSFW level of the guild",
+                    "type": "integer"
+                },
+                "owner": {
+                    "description": "Optional flag indicating if the user is the owner of the guild",
+                    "type": "boolean"
+                },
+                "owner_id": {
+                    "description": "ID of the owner",
+                    "type": "string"
+                },
+                "permissions": {
+                    "description": "Optional total permissions for the user in the guild",
+                    "type": "string"
+                },
+                "preferred_locale": {
+                    "description": "Preferred locale of the community guild",
+                    "type": "string"
+                },
+                "premium_progress_bar_enabled": {
+                    "description": "Flag for boost progress bar enabled",
+                    "type": "boolean"
+                },
+                "premium_subscription_count": {
+                    "description": "Optional number of boosts",
+                    "type": "integer"
+                },
+                "premium_tier": {
+                    "description": "Premium tier (Server Boost level)",
+                    "type": "integer"
+                },
+                "public_updates_channel_id": {
+                    "description": "Optional public updates channel ID",
+                    "type": "string"
+                },
+                "region": {
+                    "description": "Optional voice region ID for the guild (deprecated)",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Roles in the guild",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Role"
+                    }
+                },
+                "rules_channel_id": {
+                    "description": "Optional channel ID for community rules",
+                    "type": "string"
+                },
+                "safety_alerts_channel_id": {
+                    "description": "Optional channel ID for safety alerts",
+                    "type": "string"
+                },
+                "splash": {
+                    "description": "Optional splash hash",
+                    "type": "string"
+                },
+                "stickers": {
+                    "description": "Custom guild stickers",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Sticker"
+                    }
+                },
+                "system_channel_flags": {
+                    "description": "System channel flags",
+                    "type": "integer"
+                },
+                "system_channel_id": {
+                    "description": "Optional system channel ID for notices",
+                    "type": "string"
+                },
+                "vanity_url_code": {
+                    "description": "Optional vanity URL code for the guild",
+                    "type": "string"
+                },
+                "verification_level": {
+                    "description": "Verification level required for the guild",
+                    "type": "integer"
+                },
+                "welcome_screen": {
+                    "description": "Optional welcome screen object",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WelcomeScreen"
+                        }
+                    ]
+                },
+                "widget_channel_id": {
+                    "description": "Optional channel ID for widget invite",
+                    "type": "string"
+                },
+                "widget_enabled": {
+                    "description": "Optional flag indicating if the server widget is enabled",
+                    "type": "boolean"
+                }
+            }
+        },
+        "disgm.GuildDiff": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "Channel differences",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.ShapeDiff-disgm_ChannelShape"
+                        }
+                    ]
+                },
+                "overwrites": {
+                    "description": "Permission overwrite differences",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.ShapeDiff-disgm_OverwriteShape"
+                        }
+                    ]
+                },
+                "roles": {
+                    "description": "Role differences",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.ShapeDiff-disgm_RoleShape"
+                        }
+                    ]
+                },
+                "since": {
+                    "description": "ID of the snapshot compared against",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.GuildDiscovery": {
+            "type": "object",
+            "properties": {
+                "category_ids": {
+                    "description": "IDs of up to 5 secondary discovery categories",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "description": "Description of the guild, shown in its listing",
+                    "type": "string"
+                },
+                "discoverable": {
+                    "description": "Whether the guild is listed, with the DISCOVERABLE feature",
+                    "type": "boolean"
+                },
+                "eligible": {
+                    "description": "Whether the guild can be listed, which requires the COMMUNITY feature",
+                    "type": "boolean"
+                },
+                "emoji_discoverability_enabled": {
+                    "description": "Whether the guild's emojis can be discovered by users",
+                    "type": "boolean"
+                },
+                "keywords": {
+                    "description": "Up to 10 search keywords, each up to 30 characters",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "primary_category_id": {
+                    "description": "ID of the main discovery category",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.GuildDiscoveryParams": {
+            "type": "object",
+            "properties": {
+                "category_ids": {
+                    "description": "IDs of the secondary discovery categories, replacing the current ones",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "description": "Description of the guild",
+                    "type": "string"
+                },
+                "emoji_discoverability_enabled": {
+                    "description": "Whether the guild's emojis can be discovered by users",
+                    "type": "boolean"
+                },
+                "keywords": {
+                    "description": "Search keywords, replacing the current ones",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "primary_category_id": {
+                    "description": "ID of the main discovery category",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.GuildPolicy": {
+            "type": "object",
+            "properties": {
+                "alert_webhook_url": {
+                    "description": "URL drift alerts are posted to, e.g. a Discord webhook",
+                    "type": "string"
+                },
+                "explicit_content_filter": {
+                    "description": "Minimum explicit content filter level (0-2)",
+                    "type": "integer"
+                },
+                "mfa_level": {
+                    "description": "Required 2FA level for moderation (0 or 1)",
+                    "type": "integer"
+                },
+                "required_roles": {
+                    "description": "IDs or names of roles that must exist",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "verification_level": {
+                    "description": "Minimum verification level (0-4)",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.GuildSettings": {
+            "type": "object",
+            "properties": {
+                "audit_reason_template": {
+                    "description": "Template of the audit-log reasons with the placeholders acting_user, reason, guild_id and route, empty for the default",
+                    "type": "string"
+                },
+                "digest": {
+                    "description": "Summary of the guild's activity posted daily or weekly, none if unset",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.DigestSettings"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.GuildSnapshot": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "Channels the token may read",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "emojis": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "guild": {
+                    "description": "The guild, without its members, channels, roles and emojis",
+                    "type": "object"
+                },
+                "members": {
+                    "$ref": "#/definitions/disgm.MemberSummary"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "disgm.GuildToken": {
+            "type": "object",
+            "properties": {
+                "acting_user": {
+                    "description": "Dashboard user the token acts for, recorded in audit-log reasons and the API audit log",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "When the token was issued",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the token, used to revoke it",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Who or what the token is issued for",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Channels the token is restricted to, unrestricted if unset",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.Scope"
+                        }
+                    ]
+                },
+                "token": {
+                    "description": "The token itself, only returned when it is issued",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Health": {
+            "type": "object",
+            "properties": {
+                "gateway": {
+                    "description": "Whether the gateway connection is ready",
+                    "type": "boolean"
+                },
+                "intents": {
+                    "description": "Gateway intents the session does not request",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.IntentWarning"
+                    }
+                },
+                "status": {
+                    "description": "\"ok\", or \"degraded\" if the gateway is down or required intents are missing",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Hook": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "ID of the channel the messages are posted to",
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Events to post, empty posts every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Name of the integration, used in /hooks/{name}",
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Signature scheme, one of \"github\", \"stripe\" or \"generic\"",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Shared secret for the HMAC signature, never returned",
+                    "type": "string"
+                },
+                "template": {
+                    "description": "Go text/template for the message content",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.ImpersonatedMessage": {
+            "type": "object",
+            "properties": {
+                "allowed_mentions": {
+                    "description": "Mentions that notify, none by default",
+                    "type": "object"
+                },
+                "avatar_url": {
+                    "description": "URL of the avatar",
+                    "type": "string"
+                },
+                "content": {
+                    "description": "Text of the message",
+                    "type": "string"
+                },
+                "embeds": {
+                    "description": "Up to 10 embeds",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "flags": {
+                    "description": "Message flags, e.g. 4096 to suppress notifications",
+                    "type": "integer"
+                },
+                "tts": {
+                    "description": "Whether the message is read aloud",
+                    "type": "boolean"
+                },
+                "username": {
+                    "description": "Display name, 1-80 characters",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Impersonation": {
+            "type": "object",
+            "properties": {
+                "acting_user": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "guild_id": {
+                    "type": "string"
+                },
+                "scope": {
+                    "$ref": "#/definitions/store.Scope"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.ImpersonationParams": {
+            "type": "object",
+            "properties": {
+                "acting_user": {
+                    "description": "Support agent using the token, recorded in audit-log reasons and the API audit log",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Channels the token is restricted to, unrestricted if unset",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/store.Scope"
+                        }
+                    ]
+                },
+                "ttl_seconds": {
+                    "description": "Lifetime of the token, up to 3600, defaults to 900",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.ImportItem": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of a sticker, empty or 2-100 characters",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the emoji or sticker, defaults to the file name of the URL",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Autocomplete tags of a sticker, default to its name",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL of the image or sticker file",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.ImportParams": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Whether to only plan the import and report its ETA",
+                    "type": "boolean"
+                },
+                "items": {
+                    "description": "Files to import, up to 250",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ImportItem"
+                    }
+                },
+                "kind": {
+                    "description": "emoji_import or sticker_import",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.IntentWarning": {
+            "type": "object",
+            "properties": {
+                "intent": {
+                    "description": "Name of the intent, e.g. MESSAGE_CONTENT",
+                    "type": "string"
+                },
+                "needed_for": {
+                    "description": "The features or events that need the intent",
+                    "type": "string"
+                },
+                "required": {
+                    "description": "Whether an enabled feature needs the intent, rather than only forwarded events",
+                    "type": "boolean"
+                }
+            }
+        },
+        "disgm.InteractionLatency": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Interactions forwarded since disgm started",
+                    "type": "integer"
+                },
+                "last_ms": {
+                    "description": "Latency of the last interaction",
+                    "type": "number"
+                },
+                "max_ms": {
+                    "description": "Highest latency",
+                    "type": "number"
+                },
+                "p50_ms": {
+                    "description": "Median latency",
+                    "type": "number"
+                },
+                "p99_ms": {
+                    "description": "99th percentile latency",
+                    "type": "number"
+                },
+                "queue_p99_ms": {
+                    "description": "99th percentile time waiting in the queue",
+                    "type": "number"
+                },
+                "slow": {
+                    "description": "Interactions slower than 1.5 seconds since disgm started",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.Invite": {
+            "type": "object",
+            "properties": {
+                "approximate_member_count": {
+                    "description": "Members of the guild, with with_counts",
+                    "type": "integer"
+                },
+                "approximate_presence_count": {
+                    "description": "Online members of the guild, with with_counts",
+                    "type": "integer"
+                },
+                "channel": {
+                    "description": "Partial channel the invite leads to",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Channel"
+                        }
+                    ]
+                },
+                "code": {
+                    "description": "Code of the invite, as in discord.gg/\u003ccode\u003e",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "ISO8601 timestamp of the creation of the invite",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "ISO8601 timestamp the invite expires at, null if never",
+                    "type": "string"
+                },
+                "inviter": {
+                    "description": "User who created the invite, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "max_age": {
+                    "description": "Duration in seconds the invite is valid, 0 for never expiring",
+                    "type": "integer"
+                },
+                "max_uses": {
+                    "description": "Maximum number of uses, 0 for unlimited",
+                    "type": "integer"
+                },
+                "temporary": {
+                    "description": "Whether the invite grants temporary membership",
+                    "type": "boolean"
+                },
+                "uses": {
+                    "description": "Number of times the invite was used",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.Job": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "description": "Calls to Discord, fewer than items for bulk deletes",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "When the job was created",
+                    "type": "string"
+                },
+                "done": {
+                    "description": "Calls made",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "The first failed items",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.JobError"
+                    }
+                },
+                "estimated_seconds": {
+                    "description": "Time the calls left take",
+                    "type": "number"
+                },
+                "eta": {
+                    "description": "When the job is expected to finish",
+                    "type": "string"
+                },
+                "failed": {
+                    "description": "Items whose call failed",
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "description": "When the job finished or was cancelled",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Users, messages or imported files of the job",
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "plan": {
+                    "description": "Plan made when the job was created",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.JobPlan"
+                        }
+                    ]
+                },
+                "requested_by": {
+                    "description": "Acting user that created the job",
+                    "type": "string"
+                },
+                "results": {
+                    "description": "Outcome of every item, for imports",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.JobResult"
+                    }
+                },
+                "retries": {
+                    "description": "Calls retried after a rate limit or server error",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "One of planned, running, done and cancelled",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.JobError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "item": {
+                    "description": "User or message ID",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.JobParams": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "Channel of purge",
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "Whether to only plan the job and report its ETA",
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "One of bulk_ban, role_assign and purge",
+                    "type": "string"
+                },
+                "message_ids": {
+                    "description": "Messages of purge",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role_id": {
+                    "description": "Role of role_assign",
+                    "type": "string"
+                },
+                "user_ids": {
+                    "description": "Users of bulk_ban and role_assign",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "disgm.JobPlan": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "description": "Calls to Discord",
+                    "type": "integer"
+                },
+                "estimated_seconds": {
+                    "description": "Time all calls take",
+                    "type": "number"
+                },
+                "routes": {
+                    "description": "Calls per rate-limited route",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.PlannedRoute"
+                    }
+                }
+            }
+        },
+        "disgm.JobResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Why the item was skipped or failed",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID of the created emoji or sticker",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the emoji or sticker, de-duplicated against the guild",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "URL or file name the item was imported from",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "One of pending, created, skipped and failed",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.LoggedMessage": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "description": "ID of the author",
+                    "type": "string"
+                },
+                "author_name": {
+                    "description": "Username of the author when the message was sent",
+                    "type": "string"
+                },
+                "channel_id": {
+                    "description": "ID of the channel the message was sent in",
+                    "type": "string"
+                },
+                "content": {
+                    "description": "Latest content of the message",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "When the message was deleted, deleted messages stay searchable",
+                    "type": "string"
+                },
+                "edited_at": {
+                    "description": "When the message was last edited",
+                    "type": "string"
+                },
+                "has": {
+                    "description": "Kinds of content in the message, e.g. \"link\" or \"image\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "description": "Snowflake ID of the message",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "When the message was sent",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Member": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "description": "The member's guild avatar hash",
+                    "type": "string"
+                },
+                "avatar_decoration_data": {
+                    "description": "Data for the member's guild avatar decoration",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AvatarDecorationData"
+                        }
+                    ]
+                },
+                "communication_disabled_until": {
+                    "description": "When the user's timeout will expire",
+                    "type": "string"
+                },
+                "deaf": {
+                    "description": "Whether the user is deafened in voice channels",
+                    "type": "boolean"
+                },
+                "flags": {
+                    "description": "Guild member flags represented as a bit set, defaults to 0",
+                    "type": "integer"
+                },
+                "joined_at": {
+                    "description": "When the user joined the guild",
+                    "type": "string"
+                },
+                "mute": {
+                    "description": "Whether the user is muted in voice channels",
+                    "type": "boolean"
+                },
+                "nick": {
+                    "description": "This user's guild nickname",
+                    "type": "string"
+                },
+                "pending": {
+                    "description": "Whether the user has not yet passed the guild's Membership Screening requirements",
+                    "type": "boolean"
+                },
+                "permissions": {
+                    "description": "Total permissions of the member in the channel, including overwrites",
+                    "type": "string"
+                },
+                "premium_since": {
+                    "description": "When the user started boosting the guild",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Array of role object IDs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "description": "The user this guild member represents",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.MemberDirectory": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "description": "Whether all members were received, which needs the guild members intent",
+                    "type": "boolean"
+                },
+                "members": {
+                    "description": "Members, by display name",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.DirectoryMember"
+                    }
+                },
+                "updated_at": {
+                    "description": "When the last gateway event changed the directory",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.MemberImages": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "description": "Guild avatar of the member, null if not set",
+                    "type": "string"
+                },
+                "banner_url": {
+                    "description": "Guild banner of the member, null if not set",
+                    "type": "string"
+                },
+                "display_avatar_url": {
+                    "description": "Guild avatar, or else the avatar of the user",
+                    "type": "string"
+                },
+                "display_banner_url": {
+                    "description": "Guild banner, or else the banner of the user",
+                    "type": "string"
+                },
+                "user_avatar_url": {
+                    "description": "Avatar of the user, or the default avatar if not set",
+                    "type": "string"
+                },
+                "user_banner_url": {
+                    "description": "Banner of the user, null if not set",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.MemberImagesParams": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "description": "Guild avatar, up to 10 MiB",
+                    "type": "string"
+                },
+                "banner": {
+                    "description": "Guild banner, up to 10 MiB",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.MemberSummary": {
+            "type": "object",
+            "properties": {
+                "bots": {
+                    "description": "Bots among the cached members",
+                    "type": "integer"
+                },
+                "by_role": {
+                    "description": "Cached members by role ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "cached": {
+                    "description": "Members kept in the state, which the other counts are based on",
+                    "type": "integer"
+                },
+                "online": {
+                    "description": "Members with a presence other than offline",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Members of the guild",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.Message": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "description": "Sent with Rich Presence-related chat embeds"
+                },
+                "application": {
+                    "description": "Sent with Rich Presence-related chat embeds"
+                },
+                "application_id": {
+                    "description": "ID of the application if the message is an Interaction or application-owned webhook",
+                    "type": "string"
+                },
+                "attachments": {
+                    "description": "Any attached files",
+                    "type": "array",
+                    "items": {}
+                },
+                "author": {
+                    "description": "The author of this message (not guaranteed to be a valid user)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                },
+                "call": {
+                    "description": "The call associated with the message"
+                },
+                "channel_id": {
+                    "description": "ID of the channel the message was sent in",
+                    "type": "string"
+                },
+                "components": {
+                    "description": "Sent if the message contains components like buttons, action rows, etc.",
+                    "type": "array",
+                    "items": {}
+                },
+                "content": {
+                    "description": "Contents of the message",
+                    "type": "string"
+                },
+                "edited_timestamp": {
+                    "description": "When this message was edited (or null if never)",
+                    "type": "string"
+                },
+                "embeds": {
+                    "description": "Any embedded content",
+                    "type": "array",
+                    "items": {}
+                },
+                "flags": {
+                    "description": "Message flags combined as a bitfield",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID of the message",
+                    "type": "string"
+                },
+                "interaction": {
+                    "description": "Deprecated in favor of interaction_metadata"
+                },
+                "interaction_metadata": {
+                    "description": "Sent if the message is sent as a result of an interaction"
+                },
+                "mention_channels": {
+                    "description": "Channels specifically mentioned in this message",
+                    "type": "array",
+                    "items": {}
+                },
+                "mention_everyone": {
+                    "description": "Whether this message mentions everyone",
+                    "type": "boolean"
+                },
+                "mention_roles": {
+                    "description": "Roles specifically mentioned in this message",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mentions": {
+                    "description": "Users specifically mentioned in the message",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                },
+                "message_reference": {
+                    "description": "Data showing the source of a crosspost, channel follow add, pin, or reply message"
+                },
+                "message_snapshots": {
+                    "description": "The message associated with the message_reference",
+                    "type": "array",
+                    "items": {}
+                },
+                "nonce": {
+                    "description": "Used for validating a message was sent"
+                },
+                "pinned": {
+                    "description": "Whether this message is pinned",
+                    "type": "boolean"
+                },
+                "poll": {
+                    "description": "A poll!"
+                },
+                "position": {
+                    "description": "Approximate position of the message in a thread",
+                    "type": "integer"
+                },
+                "reactions": {
+                    "description": "Reactions to the message",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Reaction"
+                    }
+                },
+                "referenced_message": {
+                    "description": "The message associated with the message_reference",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    ]
+                },
+                "resolved": {
+                    "description": "Data for users, members, channels, and roles in the message's auto-populated select menus"
+                },
+                "role_subscription_data": {
+                    "description": "Data of the role subscription purchase or renewal"
+                },
+                "sticker_items": {
+                    "description": "Sent if the message contains stickers",
+                    "type": "array",
+                    "items": {}
+                },
+                "stickers": {
+                    "description": "Deprecated the stickers sent with the message",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Sticker"
+                    }
+                },
+                "thread": {
+                    "description": "The thread that was started from this message",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Channel"
+                        }
+                    ]
+                },
+                "timestamp": {
+                    "description": "When this message was sent",
+                    "type": "string"
+                },
+                "tts": {
+                    "description": "Whether this was a TTS message",
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "Type of message",
+                    "type": "integer"
+                },
+                "webhook_id": {
+                    "description": "If the message is generated by a webhook",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.MessageSearchResult": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "description": "Matching messages of the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.LoggedMessage"
+                    }
+                },
+                "total": {
+                    "description": "Number of matching messages across all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.ModuleState": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "module": {
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.OverwriteShape": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "description": "Allowed permissions bit set",
+                    "type": "integer"
+                },
+                "channel_id": {
+                    "description": "ID of the channel the overwrite belongs to",
+                    "type": "string"
+                },
+                "deny": {
+                    "description": "Denied permissions bit set",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID of the role or member",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of overwrite (0 = role, 1 = member)",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.OverwriteSummary": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "description": "Member overwrites",
+                    "type": "integer"
+                },
+                "private": {
+                    "description": "Whether @everyone is denied viewing the channel",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "description": "Role overwrites",
+                    "type": "integer"
+                },
+                "synced": {
+                    "description": "Whether the overwrites match those of the category",
+                    "type": "boolean"
+                }
+            }
+        },
+        "disgm.OverwriteTemplate": {
+            "type": "object",
+            "properties": {
+                "exclusive": {
+                    "description": "Whether applying removes the other overwrites of the channels",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Name of the template, lowercase letters, digits, dashes and underscores",
+                    "type": "string"
+                },
+                "overwrites": {
+                    "description": "Overwrites set on the channels, \"@everyone\" as ID for the everyone role",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "disgm.Page": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Items of the page"
+                },
+                "paging": {
+                    "description": "Cursors of the adjacent pages",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.Paging"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.Paging": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "Cursor of the next page, older items for newest-first lists",
+                    "type": "string"
+                },
+                "prev_cursor": {
+                    "description": "Cursor of the previous page",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total number of items, where Discord reports it",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.PlannedRoute": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "description": "Calls of the job to the route",
+                    "type": "integer"
+                },
+                "estimated_seconds": {
+                    "description": "Time the calls take when paced by the limit",
+                    "type": "number"
+                },
+                "limit": {
+                    "description": "Requests per window of the route",
+                    "type": "integer"
+                },
+                "observed": {
+                    "description": "Whether Discord reported the limit, else it is assumed",
+                    "type": "boolean"
+                },
+                "route": {
+                    "description": "Method and route, e.g. PUT /guilds/1234/bans/:id",
+                    "type": "string"
+                },
+                "window_seconds": {
+                    "description": "Length of a window of the route",
+                    "type": "number"
+                }
+            }
+        },
+        "disgm.PolicyReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "description": "When the guild was checked",
+                    "type": "string"
+                },
+                "violations": {
+                    "description": "Settings that do not match the policy",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.PolicyViolation"
+                    }
+                }
+            }
+        },
+        "disgm.PolicyViolation": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "description": "Current value, null for missing roles"
+                },
+                "expected": {
+                    "description": "Value required by the policy"
+                },
+                "setting": {
+                    "description": "Checked setting, e.g. \"verification_level\"",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.PollAnnouncement": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "Channel of the poll message",
+                    "type": "string"
+                },
+                "message_id": {
+                    "description": "ID of the poll message",
+                    "type": "string"
+                },
+                "post_at": {
+                    "description": "Expiry of the poll",
+                    "type": "string"
+                },
+                "target_channel_id": {
+                    "description": "Channel the results are posted to",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.PollAnswerResults": {
+            "type": "object",
+            "properties": {
+                "answer_id": {
+                    "type": "integer"
+                },
+                "emoji": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "description": "Share of all votes, rounded to one decimal",
+                    "type": "number"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.PollResults": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.PollAnswerResults"
+                    }
+                },
+                "channel_id": {
+                    "type": "string"
+                },
+                "expiry": {
+                    "description": "When the poll closes",
+                    "type": "string"
+                },
+                "finalized": {
+                    "description": "Whether Discord has finished counting the votes",
+                    "type": "boolean"
+                },
+                "message_id": {
+                    "type": "string"
+                },
+                "question": {
+                    "type": "string"
+                },
+                "total_votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.RecordedEvent": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "Data of the event as sent to the clients",
+                    "type": "object"
+                },
+                "id": {
+                    "description": "Sortable ID of the event within its guild",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the event, e.g. MESSAGE_CREATE",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "When the event was sent to the clients",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Relay": {
+            "type": "object",
+            "properties": {
+                "author_ids": {
+                    "description": "Only relays messages of these users, if set",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "contains": {
+                    "description": "Only relays messages containing one of these terms, if set",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "guild_id": {
+                    "description": "ID of the guild that created the relay",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the relay",
+                    "type": "string"
+                },
+                "include_bots": {
+                    "description": "Whether messages of bots are relayed",
+                    "type": "boolean"
+                },
+                "pending": {
+                    "description": "Whether the relay waits for the target guild to confirm it",
+                    "type": "boolean"
+                },
+                "source_channel_id": {
+                    "description": "ID of the channel messages are read from",
+                    "type": "string"
+                },
+                "target_channel_id": {
+                    "description": "ID of the channel messages are mirrored to",
+                    "type": "string"
+                },
+                "target_guild_id": {
+                    "description": "ID of the guild of the target channel",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.ReplayResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error that stopped the replay early, if any",
+                    "type": "string"
+                },
+                "replayed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.ReplayTarget": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "description": "Connection ID from the HELLO event of a WebSocket client",
+                    "type": "string"
                 },
-                "application_id": {
-                    "description": "Optional application ID if bot-created",
+                "public_key": {
+                    "description": "Base64 X25519 public key the webhook deliveries are sealed for",
                     "type": "string"
                 },
-                "approximate_member_count": {
-                    "description": "Optional approximate number of members",
+                "webhook_url": {
+                    "description": "URL each event is posted to as JSON",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Role": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "Color of the role",
                     "type": "integer"
                 },
-                "approximate_presence_count": {
-                    "description": "Optional approximate non-offline members",
-                    "type": "integer"
+                "hoist": {
+                    "description": "Whether the role is hoisted in the user list",
+                    "type": "boolean"
                 },
-                "banner": {
-                    "description": "Optional banner hash",
+                "id": {
+                    "description": "Define fields for Role structure based on your needs",
                     "type": "string"
                 },
-                "default_message_notifications": {
-                    "description": "Default message notifications level",
-                    "type": "integer"
+                "managed": {
+                    "description": "Whether the role is managed by an application",
+                    "type": "boolean"
                 },
-                "description": {
-                    "description": "Optional description of the guild",
+                "mentionable": {
+                    "description": "Whether the role is mentionable",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Name of the role",
                     "type": "string"
                 },
-                "discovery_splash": {
-                    "description": "Optional discovery splash hash for discoverable guilds",
+                "permissions": {
+                    "description": "Permissions for the role",
                     "type": "string"
                 },
-                "emojis": {
-                    "description": "Custom guild emojis",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Emoji"
-                    }
+                "position": {
+                    "description": "Position of the role",
+                    "type": "integer"
+                }
+            }
+        },
+        "disgm.RoleIconParams": {
+            "type": "object",
+            "properties": {
+                "image": {
+                    "description": "Icon image as base64 or data URI, up to 256 KiB",
+                    "type": "string"
                 },
-                "explicit_content_filter": {
-                    "description": "Explicit content filter level",
+                "unicode_emoji": {
+                    "description": "Standard emoji shown as the icon, e.g. 🔥",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.RoleShape": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "Color of the role",
                     "type": "integer"
                 },
-                "features": {
-                    "description": "Enabled guild features",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "hoist": {
+                    "description": "Whether the role is hoisted",
+                    "type": "boolean"
                 },
-                "icon": {
-                    "description": "Optional icon hash",
+                "id": {
+                    "description": "Snowflake ID of the role",
                     "type": "string"
                 },
-                "icon_hash": {
-                    "description": "Optional icon hash returned in the template object",
-                    "type": "string"
+                "mentionable": {
+                    "description": "Whether the role is mentionable",
+                    "type": "boolean"
                 },
-                "id": {
-                    "description": "Snowflake ID of the guild",
+                "name": {
+                    "description": "Name of the role",
                     "type": "string"
                 },
-                "max_members": {
-                    "description": "Maximum number of members for the guild",
+                "permissions": {
+                    "description": "Permissions of the role",
                     "type": "integer"
                 },
-                "max_presences": {
-                    "description": "Optional maximum presences for the guild",
+                "position": {
+                    "description": "Position of the role",
                     "type": "integer"
-                },
-                "max_stage_video_channel_users": {
-                    "description": "Maximum users in a stage video channel",
+                }
+            }
+        },
+        "disgm.RouteStatus": {
+            "type": "object",
+            "properties": {
+                "client_errors": {
+                    "description": "Requests answered with a 4xx status",
                     "type": "integer"
                 },
-                "max_video_channel_users": {
-                    "description": "Maximum users in a video channel",
-                    "type": "integer"
+                "error_rate": {
+                    "description": "Share of requests answered with a 5xx status",
+                    "type": "number"
                 },
-                "mfa_level": {
-                    "description": "Required MFA level for the guild",
+                "errors": {
+                    "description": "Requests answered with a 5xx status",
                     "type": "integer"
                 },
-                "name": {
-                    "description": "Name of the guild (2-100 characters)",
+                "method": {
                     "type": "string"
                 },
-                "nsfw_level": {
-                    "description": "NSFW level of the guild",
+                "requests": {
+                    "description": "Requests in the window",
                     "type": "integer"
                 },
-                "owner": {
-                    "description": "Optional flag indicating if the user is the owner of the guild",
-                    "type": "boolean"
-                },
-                "owner_id": {
-                    "description": "ID of the owner",
+                "route": {
+                    "description": "Route pattern, e.g. /api/guild/channels/:channelid",
                     "type": "string"
+                }
+            }
+        },
+        "disgm.ShapeChange-disgm_ChannelShape": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "Current value",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.ChannelShape"
+                        }
+                    ]
                 },
-                "permissions": {
-                    "description": "Optional total permissions for the user in the guild",
-                    "type": "string"
+                "before": {
+                    "description": "Value in the snapshot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.ChannelShape"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.ShapeChange-disgm_OverwriteShape": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "Current value",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.OverwriteShape"
+                        }
+                    ]
                 },
-                "preferred_locale": {
-                    "description": "Preferred locale of the community guild",
-                    "type": "string"
+                "before": {
+                    "description": "Value in the snapshot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.OverwriteShape"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.ShapeChange-disgm_RoleShape": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "Current value",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.RoleShape"
+                        }
+                    ]
                 },
-                "premium_progress_bar_enabled": {
-                    "description": "Flag for boost progress bar enabled",
-                    "type": "boolean"
+                "before": {
+                    "description": "Value in the snapshot",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.RoleShape"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.ShapeDiff-disgm_ChannelShape": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ChannelShape"
+                    }
                 },
-                "premium_subscription_count": {
-                    "description": "Optional number of boosts",
-                    "type": "integer"
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ShapeChange-disgm_ChannelShape"
+                    }
                 },
-                "premium_tier": {
-                    "description": "Premium tier (Server Boost level)",
-                    "type": "integer"
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ChannelShape"
+                    }
+                }
+            }
+        },
+        "disgm.ShapeDiff-disgm_OverwriteShape": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.OverwriteShape"
+                    }
                 },
-                "public_updates_channel_id": {
-                    "description": "Optional public updates channel ID",
-                    "type": "string"
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ShapeChange-disgm_OverwriteShape"
+                    }
                 },
-                "region": {
-                    "description": "Optional voice region ID for the guild (deprecated)",
-                    "type": "string"
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.OverwriteShape"
+                    }
+                }
+            }
+        },
+        "disgm.ShapeDiff-disgm_RoleShape": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.RoleShape"
+                    }
                 },
-                "roles": {
-                    "description": "Roles in the guild",
+                "changed": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Role"
+                        "$ref": "#/definitions/disgm.ShapeChange-disgm_RoleShape"
                     }
                 },
-                "rules_channel_id": {
-                    "description": "Optional channel ID for community rules",
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.RoleShape"
+                    }
+                }
+            }
+        },
+        "disgm.SinkDelivery": {
+            "type": "object",
+            "properties": {
+                "delivered": {
+                    "description": "Events delivered in the window",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Events that failed to be delivered in the window",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "websocket or webhook",
                     "type": "string"
                 },
-                "safety_alerts_channel_id": {
-                    "description": "Optional channel ID for safety alerts",
+                "last_error": {
+                    "description": "Error of the latest failure",
                     "type": "string"
                 },
-                "splash": {
-                    "description": "Optional splash hash",
+                "last_failure": {
+                    "description": "When the latest failure happened",
                     "type": "string"
                 },
-                "stickers": {
-                    "description": "Custom guild stickers",
+                "name": {
+                    "description": "Client name of WebSocket clients, \"unnamed\" if they set none; host of webhooks",
+                    "type": "string"
+                },
+                "success_rate": {
+                    "description": "Share of delivered events",
+                    "type": "number"
+                }
+            }
+        },
+        "disgm.Snapshot": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "When the snapshot was taken",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique ID of the snapshot",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Status": {
+            "type": "object",
+            "properties": {
+                "discord_errors": {
+                    "description": "Discord API errors in the window, most frequent first",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Sticker"
+                        "$ref": "#/definitions/disgm.DiscordErrorStatus"
                     }
                 },
-                "system_channel_flags": {
-                    "description": "System channel flags",
+                "error_rate": {
+                    "description": "Share of API requests answered with a 5xx status",
+                    "type": "number"
+                },
+                "errors": {
+                    "description": "API requests answered with a 5xx status",
                     "type": "integer"
                 },
-                "system_channel_id": {
-                    "description": "Optional system channel ID for notices",
-                    "type": "string"
+                "gateway": {
+                    "description": "Whether the gateway connection is ready",
+                    "type": "boolean"
                 },
-                "vanity_url_code": {
-                    "description": "Optional vanity URL code for the guild",
-                    "type": "string"
+                "ready": {
+                    "description": "Whether the gateway is ready and the error budget is kept",
+                    "type": "boolean"
                 },
-                "verification_level": {
-                    "description": "Verification level required for the guild",
+                "reasons": {
+                    "description": "Why disgm is not ready",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requests": {
+                    "description": "API requests in the window",
                     "type": "integer"
                 },
-                "welcome_screen": {
-                    "description": "Optional welcome screen object",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.WelcomeScreen"
-                        }
-                    ]
+                "routes": {
+                    "description": "Routes with requests in the window, most errors first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.RouteStatus"
+                    }
                 },
-                "widget_channel_id": {
-                    "description": "Optional channel ID for widget invite",
-                    "type": "string"
+                "throttled": {
+                    "description": "Events dropped by the EventThrottles option since disgm started, most dropped first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.ThrottledEvents"
+                    }
                 },
-                "widget_enabled": {
-                    "description": "Optional flag indicating if the server widget is enabled",
-                    "type": "boolean"
+                "window_seconds": {
+                    "description": "Time the counts cover",
+                    "type": "integer"
                 }
             }
         },
-        "disgm.Member": {
+        "disgm.Sticker": {
             "type": "object",
             "properties": {
-                "avatar": {
-                    "description": "The member's guild avatar hash",
-                    "type": "string"
-                },
-                "avatar_decoration_data": {
-                    "description": "Data for the member's guild avatar decoration",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.AvatarDecorationData"
-                        }
-                    ]
+                "available": {
+                    "description": "Whether the sticker can be used, false if lost due to boost removal",
+                    "type": "boolean"
                 },
-                "communication_disabled_until": {
-                    "description": "When the user's timeout will expire",
+                "description": {
+                    "description": "Optional description of the sticker",
                     "type": "string"
                 },
-                "deaf": {
-                    "description": "Whether the user is deafened in voice channels",
-                    "type": "boolean"
-                },
-                "flags": {
-                    "description": "Guild member flags represented as a bit set, defaults to 0",
+                "format_type": {
+                    "description": "Format type of the sticker",
                     "type": "integer"
                 },
-                "joined_at": {
-                    "description": "When the user joined the guild",
+                "guild_id": {
+                    "description": "ID of the guild the sticker belongs to",
                     "type": "string"
                 },
-                "mute": {
-                    "description": "Whether the user is muted in voice channels",
-                    "type": "boolean"
-                },
-                "nick": {
-                    "description": "This user's guild nickname",
+                "id": {
+                    "description": "Define fields for Sticker structure based on your needs",
                     "type": "string"
                 },
-                "pending": {
-                    "description": "Whether the user has not yet passed the guild's Membership Screening requirements",
-                    "type": "boolean"
+                "name": {
+                    "description": "Name of the sticker",
+                    "type": "string"
                 },
-                "permissions": {
-                    "description": "Total permissions of the member in the channel, including overwrites",
+                "pack_id": {
+                    "description": "ID of the sticker pack",
                     "type": "string"
                 },
-                "premium_since": {
-                    "description": "When the user started boosting the guild",
+                "tags": {
+                    "description": "Autocomplete and suggestion tags of the sticker",
                     "type": "string"
                 },
-                "roles": {
-                    "description": "Array of role object IDs",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "type": {
+                    "description": "Type of the sticker (1 = standard, 2 = guild)",
+                    "type": "integer"
                 },
                 "user": {
-                    "description": "The user this guild member represents",
+                    "description": "Optional user object that uploaded the sticker",
                     "allOf": [
                         {
                             "$ref": "#/definitions/models.User"
@@ -1550,209 +9372,199 @@ const docTemplate = `{
                 }
             }
         },
-        "disgm.Message": {
+        "disgm.Task": {
             "type": "object",
             "properties": {
-                "activity": {
-                    "description": "Sent with Rich Presence-related chat embeds"
+                "action": {
+                    "description": "One of the built-in actions, e.g. TaskSendMessage",
+                    "type": "string"
                 },
-                "application": {
-                    "description": "Sent with Rich Presence-related chat embeds"
+                "guild_id": {
+                    "description": "Guild the task belongs to",
+                    "type": "string"
                 },
-                "application_id": {
-                    "description": "ID of the application if the message is an Interaction or application-owned webhook",
+                "id": {
+                    "description": "Unique ID of the task",
                     "type": "string"
                 },
-                "attachments": {
-                    "description": "Any attached files",
-                    "type": "array",
-                    "items": {}
+                "last_error": {
+                    "description": "Error of the last run, if it failed",
+                    "type": "string"
                 },
-                "author": {
-                    "description": "The author of this message (not guaranteed to be a valid user)",
+                "last_run": {
+                    "description": "When the task last ran",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the task",
+                    "type": "string"
+                },
+                "next_run": {
+                    "description": "When the task runs next",
+                    "type": "string"
+                },
+                "params": {
+                    "description": "Parameters of the action",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/disgm.TaskParams"
                         }
                     ]
                 },
-                "call": {
-                    "description": "The call associated with the message"
+                "paused": {
+                    "description": "Whether the task is skipped until resumed",
+                    "type": "boolean"
                 },
+                "schedule": {
+                    "description": "Cron expression in UTC, a macro such as \"@daily\", or \"@every 6h\"",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.TaskParams": {
+            "type": "object",
+            "properties": {
                 "channel_id": {
-                    "description": "ID of the channel the message was sent in",
+                    "description": "Channel of send_message and purge",
                     "type": "string"
                 },
-                "components": {
-                    "description": "Sent if the message contains components like buttons, action rows, etc.",
+                "commands": {
+                    "description": "Command definitions of sync_commands",
                     "type": "array",
-                    "items": {}
+                    "items": {
+                        "type": "object"
+                    }
                 },
-                "content": {
-                    "description": "Contents of the message",
+                "keep_pinned": {
+                    "description": "Whether purge keeps pinned messages",
+                    "type": "boolean"
+                },
+                "limit": {
+                    "description": "Messages inspected by purge, 1-100, defaults to 100",
+                    "type": "integer"
+                },
+                "template": {
+                    "description": "Go text/template of send_message, executed with .Now and .GuildID",
                     "type": "string"
+                }
+            }
+        },
+        "disgm.ThrottledEvents": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "Events dropped since disgm started",
+                    "type": "integer"
                 },
-                "edited_timestamp": {
-                    "description": "When this message was edited (or null if never)",
+                "event": {
+                    "description": "Event type, e.g. TYPING_START",
                     "type": "string"
                 },
-                "embeds": {
-                    "description": "Any embedded content",
-                    "type": "array",
-                    "items": {}
+                "guild_id": {
+                    "type": "string"
                 },
-                "flags": {
-                    "description": "Message flags combined as a bitfield",
+                "limit": {
+                    "description": "Events forwarded per second",
                     "type": "integer"
-                },
-                "id": {
-                    "description": "ID of the message",
+                }
+            }
+        },
+        "disgm.TokenSettings": {
+            "type": "object",
+            "properties": {
+                "audit_reason_template": {
+                    "description": "Template of the audit-log reasons of the token, empty to use the guild's",
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Transcript": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
                     "type": "string"
                 },
-                "interaction": {
-                    "description": "Deprecated in favor of interaction_metadata"
+                "channel_name": {
+                    "type": "string"
                 },
-                "interaction_metadata": {
-                    "description": "Sent if the message is sent as a result of an interaction"
+                "generated_at": {
+                    "type": "string"
                 },
-                "mention_channels": {
-                    "description": "Channels specifically mentioned in this message",
-                    "type": "array",
-                    "items": {}
+                "guild_id": {
+                    "type": "string"
                 },
-                "mention_everyone": {
-                    "description": "Whether this message mentions everyone",
-                    "type": "boolean"
+                "guild_name": {
+                    "type": "string"
                 },
-                "mention_roles": {
-                    "description": "Roles specifically mentioned in this message",
+                "messages": {
+                    "description": "Messages from oldest to newest",
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/disgm.TranscriptMessage"
                     }
                 },
-                "mentions": {
-                    "description": "Users specifically mentioned in the message",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.User"
+                "users": {
+                    "description": "Authors keyed by user ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/disgm.TranscriptUser"
                     }
-                },
-                "message_reference": {
-                    "description": "Data showing the source of a crosspost, channel follow add, pin, or reply message"
-                },
-                "message_snapshots": {
-                    "description": "The message associated with the message_reference",
-                    "type": "array",
-                    "items": {}
-                },
-                "nonce": {
-                    "description": "Used for validating a message was sent"
-                },
-                "pinned": {
-                    "description": "Whether this message is pinned",
-                    "type": "boolean"
-                },
-                "poll": {
-                    "description": "A poll!"
-                },
-                "position": {
-                    "description": "Approximate position of the message in a thread",
-                    "type": "integer"
-                },
-                "reactions": {
-                    "description": "Reactions to the message",
+                }
+            }
+        },
+        "disgm.TranscriptMessage": {
+            "type": "object",
+            "properties": {
+                "attachments": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Reaction"
+                        "type": "object"
                     }
                 },
-                "referenced_message": {
-                    "description": "The message associated with the message_reference",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Message"
-                        }
-                    ]
-                },
-                "resolved": {
-                    "description": "Data for users, members, channels, and roles in the message's auto-populated select menus"
-                },
-                "role_subscription_data": {
-                    "description": "Data of the role subscription purchase or renewal"
+                "author_id": {
+                    "type": "string"
                 },
-                "sticker_items": {
-                    "description": "Sent if the message contains stickers",
-                    "type": "array",
-                    "items": {}
+                "content": {
+                    "type": "string"
                 },
-                "stickers": {
-                    "description": "Deprecated the stickers sent with the message",
+                "edited": {
+                    "type": "string"
+                },
+                "embeds": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Sticker"
+                        "type": "object"
                     }
                 },
-                "thread": {
-                    "description": "The thread that was started from this message",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Channel"
-                        }
-                    ]
-                },
-                "timestamp": {
-                    "description": "When this message was sent",
+                "id": {
                     "type": "string"
                 },
-                "tts": {
-                    "description": "Whether this was a TTS message",
-                    "type": "boolean"
-                },
-                "type": {
-                    "description": "Type of message",
-                    "type": "integer"
+                "reference": {
+                    "type": "object"
                 },
-                "webhook_id": {
-                    "description": "If the message is generated by a webhook",
+                "timestamp": {
                     "type": "string"
                 }
             }
         },
-        "disgm.Role": {
+        "disgm.TranscriptUser": {
             "type": "object",
             "properties": {
-                "color": {
-                    "description": "Color of the role",
-                    "type": "integer"
-                },
-                "hoist": {
-                    "description": "Whether the role is hoisted in the user list",
-                    "type": "boolean"
-                },
-                "id": {
-                    "description": "Define fields for Role structure based on your needs",
+                "avatar_url": {
                     "type": "string"
                 },
-                "managed": {
-                    "description": "Whether the role is managed by an application",
-                    "type": "boolean"
-                },
-                "mentionable": {
-                    "description": "Whether the role is mentionable",
+                "bot": {
                     "type": "boolean"
                 },
-                "name": {
-                    "description": "Name of the role",
+                "display_name": {
+                    "description": "Guild nickname, global name or username",
                     "type": "string"
                 },
-                "permissions": {
-                    "description": "Permissions for the role",
+                "id": {
                     "type": "string"
                 },
-                "position": {
-                    "description": "Position of the role",
-                    "type": "integer"
+                "username": {
+                    "type": "string"
                 }
             }
         },
@@ -1833,6 +9645,318 @@ const docTemplate = `{
                 }
             }
         },
+        "disgm.VoiceRegion": {
+            "type": "object",
+            "properties": {
+                "custom": {
+                    "description": "Whether the region is custom, e.g. for events",
+                    "type": "boolean"
+                },
+                "deprecated": {
+                    "description": "Whether the region is deprecated and should be avoided",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "Unique ID of the region, used as rtc_region of voice channels",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the region",
+                    "type": "string"
+                },
+                "optimal": {
+                    "description": "Whether the region is the closest to the bot",
+                    "type": "boolean"
+                }
+            }
+        },
+        "disgm.WSClient": {
+            "type": "object",
+            "properties": {
+                "batch": {
+                    "description": "Batch options set with the batch op, nil if events are sent one by one",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/disgm.BatchOptions"
+                        }
+                    ]
+                },
+                "connected_at": {
+                    "description": "When the client connected",
+                    "type": "string"
+                },
+                "encoding": {
+                    "description": "Encoding of the events sent to the client, json or proto",
+                    "type": "string"
+                },
+                "encrypted": {
+                    "description": "Whether the frames sent to the client are sealed for its public key",
+                    "type": "boolean"
+                },
+                "events_out": {
+                    "description": "Events sent to the client",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID of the connection, sent to the client in the HELLO event",
+                    "type": "string"
+                },
+                "max_write_latency_ms": {
+                    "description": "Slowest write to the client",
+                    "type": "number"
+                },
+                "messages_in": {
+                    "description": "Messages received from the client",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name of the client, e.g. the service using the connection",
+                    "type": "string"
+                },
+                "purpose": {
+                    "description": "What the client uses the connection for",
+                    "type": "string"
+                },
+                "queue_depth": {
+                    "description": "Write diagnostics, to find clients that fall behind. Events are written outside the lock of\nthe client registry, so a client that reads slowly does not block the other guilds, but it\ndelays the clients written to after it for the same event, up to the write timeout.",
+                    "type": "integer"
+                },
+                "remote_addr": {
+                    "description": "Network address of the client",
+                    "type": "string"
+                },
+                "slow": {
+                    "description": "Whether the average write latency is above 250 milliseconds",
+                    "type": "boolean"
+                },
+                "slow_writes": {
+                    "description": "Writes slower than 250 milliseconds",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version of the client",
+                    "type": "string"
+                },
+                "write_latency_ms": {
+                    "description": "Moving average of the time a write to the client takes",
+                    "type": "number"
+                }
+            }
+        },
+        "disgm.WSProtocol": {
+            "type": "object",
+            "properties": {
+                "$defs": {
+                    "description": "JSON Schemas of the op payloads",
+                    "type": "object"
+                },
+                "close_codes": {
+                    "description": "Close codes disgm sends",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.WSProtocolCloseCode"
+                    }
+                },
+                "default_version": {
+                    "description": "Version of clients that do not ask for one",
+                    "type": "integer"
+                },
+                "encodings": {
+                    "description": "Encodings of the events",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.WSProtocolParam"
+                    }
+                },
+                "event_schema": {
+                    "description": "Path of the JSON Schema of the events",
+                    "type": "string"
+                },
+                "frames": {
+                    "description": "Formats of the frames",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.WSProtocolFrame"
+                    }
+                },
+                "heartbeat_interval_ms": {
+                    "description": "Interval in which clients should send the heartbeat op",
+                    "type": "integer"
+                },
+                "ops": {
+                    "description": "Ops clients may send",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.WSProtocolOp"
+                    }
+                },
+                "query": {
+                    "description": "Query parameters of the WebSocket endpoint",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/disgm.WSProtocolParam"
+                    }
+                },
+                "url": {
+                    "description": "Path of the WebSocket endpoint",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Latest protocol version",
+                    "type": "integer"
+                },
+                "versions": {
+                    "description": "Protocol versions disgm speaks",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "disgm.WSProtocolCloseCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.WSProtocolFrame": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "direction": {
+                    "description": "server or client",
+                    "type": "string"
+                },
+                "example": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.WSProtocolOp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "JSON Schema of the payload, none if the op takes no data",
+                    "type": "object"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "replies": {
+                    "description": "Events sent in reply",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "disgm.WSProtocolParam": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "disgm.Webhook": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "description": "ID of the application that created the webhook",
+                    "type": "string"
+                },
+                "avatar": {
+                    "description": "Default avatar hash of the webhook",
+                    "type": "string"
+                },
+                "channel_id": {
+                    "description": "ID of the channel the webhook posts to",
+                    "type": "string"
+                },
+                "guild_id": {
+                    "description": "ID of the guild the webhook belongs to",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Snowflake ID of the webhook",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Default name of the webhook",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Secure token of incoming webhooks",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of the webhook (1 = incoming, 2 = channel follower, 3 = application)",
+                    "type": "integer"
+                },
+                "user": {
+                    "description": "Optional user object that created the webhook",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "disgm.WelcomeScreen": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the guild shown on the welcome screen, max 140 characters",
+                    "type": "string"
+                },
+                "welcome_channels": {
+                    "description": "Up to 5 channels to show on the welcome screen",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WelcomeChannel"
+                    }
+                }
+            }
+        },
+        "disgm.WelcomeScreenParams": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the guild, max 140 characters",
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Whether the welcome screen is shown to new members",
+                    "type": "boolean"
+                },
+                "welcome_channels": {
+                    "description": "Up to 5 channels, replacing the current ones",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WelcomeChannel"
+                    }
+                }
+            }
+        },
         "models.ApplicationCommand": {
             "type": "object",
             "properties": {
@@ -1841,10 +9965,10 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "contexts": {
-                    "description": "Interaction context(s) where the command can be used (global commands)",
+                    "description": "Interaction contexts where the command can be used (0 = guild, 1 = bot DM, 2 = private channel), global commands only",
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "type": "integer"
                     }
                 },
                 "default_member_permissions": {
@@ -1883,10 +10007,10 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "integration_types": {
-                    "description": "Installation contexts for the command (global commands)",
+                    "description": "Installation contexts where the command is available (0 = guild install, 1 = user install), global commands only",
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "type": "integer"
                     }
                 },
                 "name": {
@@ -2009,6 +10133,143 @@ const docTemplate = `{
                 }
             }
         },
+        "models.AutoModAction": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "description": "Optional settings of the action",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AutoModActionMetadata"
+                        }
+                    ]
+                },
+                "type": {
+                    "description": "Type of action (1 = block message, 2 = send alert, 3 = timeout)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AutoModActionMetadata": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "description": "ID of the channel alerts are sent to",
+                    "type": "string"
+                },
+                "custom_message": {
+                    "description": "Message shown to the member whose message is blocked",
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "description": "Timeout duration in seconds, max 4 weeks",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AutoModRule": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "description": "Actions taken when the rule is triggered",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AutoModAction"
+                    }
+                },
+                "creator_id": {
+                    "description": "ID of the user who created the rule",
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Whether the rule is enabled",
+                    "type": "boolean"
+                },
+                "event_type": {
+                    "description": "Event the rule is checked on (1 = message send)",
+                    "type": "integer"
+                },
+                "exempt_channels": {
+                    "description": "IDs of the channels not affected by the rule, max 50",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exempt_roles": {
+                    "description": "IDs of the roles not affected by the rule, max 20",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "guild_id": {
+                    "description": "ID of the guild the rule belongs to",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Snowflake ID of the rule",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the rule",
+                    "type": "string"
+                },
+                "trigger_metadata": {
+                    "description": "Optional settings of the trigger",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AutoModTriggerMetadata"
+                        }
+                    ]
+                },
+                "trigger_type": {
+                    "description": "Type of trigger (1 = keyword, 3 = spam, 4 = keyword preset, 5 = mention spam)",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AutoModTriggerMetadata": {
+            "type": "object",
+            "properties": {
+                "allow_list": {
+                    "description": "Substrings exempt from the rule",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "keyword_filter": {
+                    "description": "Substrings matched in the content, for keyword rules",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mention_raid_protection_enabled": {
+                    "description": "Whether to detect mention raids",
+                    "type": "boolean"
+                },
+                "mention_total_limit": {
+                    "description": "Unique role and user mentions allowed per message",
+                    "type": "integer"
+                },
+                "presets": {
+                    "description": "Predefined word lists (1 = profanity, 2 = sexual content, 3 = slurs)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "regex_patterns": {
+                    "description": "Regular expressions matched in the content, for keyword rules",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "models.AvatarDecorationData": {
             "type": "object",
             "properties": {
@@ -2188,7 +10449,17 @@ const docTemplate = `{
             }
         },
         "models.CountDetails": {
-            "type": "object"
+            "type": "object",
+            "properties": {
+                "burst": {
+                    "description": "Count of super reactions",
+                    "type": "integer"
+                },
+                "normal": {
+                    "description": "Count of normal reactions",
+                    "type": "integer"
+                }
+            }
         },
         "models.DefaultReaction": {
             "type": "object",
@@ -2210,6 +10481,10 @@ const docTemplate = `{
                     "description": "Whether the emoji is animated",
                     "type": "boolean"
                 },
+                "available": {
+                    "description": "Whether the emoji can be used, false if lost due to boost removal",
+                    "type": "boolean"
+                },
                 "id": {
                     "description": "Define fields for Emoji structure based on your needs",
                     "type": "string"
@@ -2222,15 +10497,15 @@ const docTemplate = `{
                     "description": "Name of the emoji",
                     "type": "string"
                 },
-                "requires_colons": {
+                "require_colons": {
                     "description": "Whether the emoji requires colons",
                     "type": "boolean"
                 },
                 "roles": {
-                    "description": "Roles allowed to use the emoji",
+                    "description": "IDs of the roles allowed to use the emoji",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Role"
+                        "type": "string"
                     }
                 },
                 "user": {
@@ -2243,6 +10518,26 @@ const docTemplate = `{
                 }
             }
         },
+        "models.EmojiParams": {
+            "type": "object",
+            "properties": {
+                "image": {
+                    "description": "Base64 image or data URI of the emoji, max 256 KB, only on creation",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the emoji",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "IDs of the roles allowed to use the emoji, empty for everyone",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "models.GuildBan": {
             "type": "object",
             "properties": {
@@ -2639,9 +10934,38 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ScheduledEventUser": {
+            "type": "object",
+            "properties": {
+                "guild_scheduled_event_id": {
+                    "description": "ID of the scheduled event",
+                    "type": "string"
+                },
+                "member": {
+                    "description": "Optional guild member of the user, with with_member",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Member"
+                        }
+                    ]
+                },
+                "user": {
+                    "description": "The interested user",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
         "models.Sticker": {
             "type": "object",
             "properties": {
+                "available": {
+                    "description": "Whether the sticker can be used, false if lost due to boost removal",
+                    "type": "boolean"
+                },
                 "description": {
                     "description": "Optional description of the sticker",
                     "type": "string"
@@ -2650,6 +10974,10 @@ const docTemplate = `{
                     "description": "Format type of the sticker",
                     "type": "integer"
                 },
+                "guild_id": {
+                    "description": "ID of the guild the sticker belongs to",
+                    "type": "string"
+                },
                 "id": {
                     "description": "Define fields for Sticker structure based on your needs",
                     "type": "string"
@@ -2661,6 +10989,39 @@ const docTemplate = `{
                 "pack_id": {
                     "description": "ID of the sticker pack",
                     "type": "string"
+                },
+                "tags": {
+                    "description": "Autocomplete and suggestion tags of the sticker",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of the sticker (1 = standard, 2 = guild)",
+                    "type": "integer"
+                },
+                "user": {
+                    "description": "Optional user object that uploaded the sticker",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.StickerParams": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description of the sticker, empty or 2-100 characters",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name of the sticker, 2-30 characters",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Autocomplete and suggestion tags, max 200 characters",
+                    "type": "string"
                 }
             }
         },
@@ -2723,6 +11084,29 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ThreadsList": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "Whether more threads can be paged, for archived threads",
+                    "type": "boolean"
+                },
+                "members": {
+                    "description": "Thread members of the bot for the threads it joined",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ThreadMember"
+                    }
+                },
+                "threads": {
+                    "description": "The threads",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Channel"
+                    }
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -2800,6 +11184,68 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Webhook": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "description": "ID of the application that created the webhook",
+                    "type": "string"
+                },
+                "avatar": {
+                    "description": "Default avatar hash of the webhook",
+                    "type": "string"
+                },
+                "channel_id": {
+                    "description": "ID of the channel the webhook posts to",
+                    "type": "string"
+                },
+                "guild_id": {
+                    "description": "ID of the guild the webhook belongs to",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Snowflake ID of the webhook",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Default name of the webhook",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Secure token of incoming webhooks",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of the webhook (1 = incoming, 2 = channel follower, 3 = application)",
+                    "type": "integer"
+                },
+                "user": {
+                    "description": "Optional user object that created the webhook",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.WebhookParams": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "description": "Base64 image or data URI of the default avatar",
+                    "type": "string"
+                },
+                "channel_id": {
+                    "description": "ID of the channel to move the webhook to, only on edit",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Default name of the webhook, 1-80 characters",
+                    "type": "string"
+                }
+            }
+        },
         "models.WelcomeChannel": {
             "type": "object",
             "properties": {
@@ -2808,11 +11254,15 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "description": {
-                    "description": "Optional description for the channel",
+                    "description": "Description of the channel, max 50 characters",
                     "type": "string"
                 },
-                "emoji": {
-                    "description": "Optional emoji for the channel",
+                "emoji_id": {
+                    "description": "ID of the custom emoji of the channel, if any",
+                    "type": "string"
+                },
+                "emoji_name": {
+                    "description": "Name of the custom emoji, or the unicode emoji of the channel",
                     "type": "string"
                 }
             }
@@ -2821,17 +11271,36 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "description": {
-                    "description": "Define fields relevant to the welcome screen as needed",
+                    "description": "Description of the guild shown on the welcome screen, max 140 characters",
                     "type": "string"
                 },
                 "welcome_channels": {
-                    "description": "Channels to show on the welcome screen",
+                    "description": "Up to 5 channels to show on the welcome screen",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.WelcomeChannel"
                     }
                 }
             }
+        },
+        "store.Scope": {
+            "type": "object",
+            "properties": {
+                "read_channels": {
+                    "description": "Channel IDs the token may read",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "write_channels": {
+                    "description": "Channel IDs the token may read and modify",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
         }
     }
 }`
@@ -2839,7 +11308,7 @@ const docTemplate = `{
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
-	Host:             "localhost:90",
+	Host:             "",
 	BasePath:         "",
 	Schemes:          []string{},
 	Title:            "Discord Guild Management API",
@@ -0,0 +1,44 @@
+package disgm
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errInternalAddress is returned for URLs resolving to an address that is not publicly routable.
+var errInternalAddress = errors.New("address is not publicly routable")
+
+// publicDialControl refuses connections to private, loopback, link-local and other internal
+// addresses. It runs after DNS resolution, also for redirects, so a URL supplied through the API
+// cannot reach services on the host or in its network.
+func publicDialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	ip := addrPort.Addr().Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return errInternalAddress
+	}
+	return nil
+}
+
+// newPublicClient returns a client for URLs supplied through the API, such as feeds, import files
+// and replay webhooks. It connects directly, without the proxy of the environment, so every
+// address is checked by publicDialControl.
+func newPublicClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         (&net.Dialer{Timeout: 10 * time.Second, Control: publicDialControl}).DialContext,
+			ForceAttemptHTTP2:   true,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,220 @@
+package disgm
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+type Emoji = models.Emoji
+
+// maxEmojiSize is the maximum size of an emoji image accepted by Discord.
+const maxEmojiSize = 256 * 1024
+
+// errImageTooLarge is returned for images larger than Discord accepts.
+var errImageTooLarge = errors.New("image is too large")
+
+// imageDataURI turns a base64 image into the data URI expected by Discord, detecting its type.
+// Data URIs are passed through.
+func imageDataURI(image string, maxSize int) (string, error) {
+	if strings.HasPrefix(image, "data:") {
+		return image, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(image)
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxSize {
+		return "", errImageTooLarge
+	}
+	return "data:" + http.DetectContentType(data) + ";base64," + image, nil
+}
+
+// invalidImage responds to a request with an image that imageDataURI rejected.
+func invalidImage(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errImageTooLarge) {
+		return c.Status(fiber.StatusRequestEntityTooLarge).SendString("Invalid image: " + err.Error())
+	}
+	return c.Status(fiber.StatusBadRequest).SendString("Invalid image: " + err.Error())
+}
+
+// GetGuildEmojis retrieves all custom emojis of a guild.
+//
+// This function extracts the guild ID from the Fiber context's locals and uses
+// the DiscordGo session to fetch the custom emojis of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the emojis as a JSON array with HTTP status 200.
+//   - On failure, it returns an HTTP status 500 and an error message if the emojis cannot be retrieved.
+// @Summary		Get Guild Emojis
+// @Description	Retrieve all custom emojis of the guild.
+// @Tags			Emojis
+// @Success		200	{array}		Emoji
+// @Failure		500	{object}	error
+// @Router			/api/guild/emojis [get]
+func GetGuildEmojis(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	emojis, err := s.GuildEmojis(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve emojis: " + err.Error())
+	}
+
+	return c.JSON(emojis)
+}
+
+// GetGuildEmoji retrieves a specific custom emoji of a guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the emoji as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 500 and an error message if the emoji cannot be retrieved.
+// @Summary		Get Guild Emoji
+// @Description	Retrieve a specific custom emoji of the guild by its ID.
+// @Tags			Emojis
+// @Param			emojiid	path		string	true	"Emoji ID"
+// @Success		200		{object}	models.Emoji
+// @Failure		500		{object}	error
+// @Router			/api/guild/emojis/{emojiid} [get]
+func GetGuildEmoji(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	emojiID := c.Params("emojiid")
+
+	emoji, err := s.GuildEmoji(guildID, emojiID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve emoji: " + err.Error())
+	}
+
+	return c.JSON(emoji)
+}
+
+// CreateGuildEmoji creates a custom emoji in a guild.
+//
+// This function parses the request body for the emoji name, image and allowed roles. The image
+// is either a data URI or plain base64, whose type is detected from its content. Discord accepts
+// PNG, JPEG, GIF and WebP images up to 256 KB.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the created emoji as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body or image is invalid,
+//     HTTP status 413 (Request Entity Too Large) if the image is too large,
+//     or HTTP status 500 if the emoji cannot be created.
+// @Summary		Create Guild Emoji
+// @Description	Create a custom emoji in the guild from a base64 image.
+// @Tags			Emojis
+// @Param			body	body		models.EmojiParams	true	"Emoji parameters"
+// @Success		201		{object}	models.Emoji
+// @Failure		400		{object}	error
+// @Failure		413		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/emojis [post]
+func CreateGuildEmoji(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	var params discordgo.EmojiParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if params.Name == "" || params.Image == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name and image are required")
+	}
+
+	image, err := imageDataURI(params.Image, maxEmojiSize)
+	if err != nil {
+		return invalidImage(c, err)
+	}
+	params.Image = image
+
+	emoji, err := s.GuildEmojiCreate(guildID, &params, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create emoji: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(emoji)
+}
+
+// UpdateGuildEmoji edits the name or allowed roles of a custom emoji.
+//
+// The image of an emoji cannot be changed after creation.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the updated emoji as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     or HTTP status 500 if the emoji cannot be updated.
+// @Summary		Update Guild Emoji
+// @Description	Edit the name or allowed roles of a custom emoji.
+// @Tags			Emojis
+// @Param			emojiid	path		string				true	"Emoji ID"
+// @Param			body	body		models.EmojiParams	true	"Emoji parameters"
+// @Success		200		{object}	models.Emoji
+// @Failure		400		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/emojis/{emojiid} [patch]
+func UpdateGuildEmoji(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	emojiID := c.Params("emojiid")
+
+	var params discordgo.EmojiParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if params.Image != "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: the image of an emoji cannot be changed")
+	}
+
+	emoji, err := s.GuildEmojiEdit(guildID, emojiID, &params, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update emoji: " + err.Error())
+	}
+
+	return c.JSON(emoji)
+}
+
+// DeleteGuildEmoji deletes a custom emoji from a guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns an HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 500 and an error message if the emoji cannot be deleted.
+// @Summary		Delete Guild Emoji
+// @Description	Delete a custom emoji from the guild.
+// @Tags			Emojis
+// @Param			emojiid	path	string	true	"Emoji ID"
+// @Success		204
+// @Failure		500	{object}	error
+// @Router			/api/guild/emojis/{emojiid} [delete]
+func DeleteGuildEmoji(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	emojiID := c.Params("emojiid")
+
+	err := s.GuildEmojiDelete(guildID, emojiID, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete emoji: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,99 @@
+package disgm
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/contrib/websocket"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// EncryptionHeader names the scheme of webhook deliveries whose body is sealed for a public key.
+const EncryptionHeader = "X-Disgm-Encryption"
+
+// SealedBoxScheme is the encryption of sealed frames and deliveries: libsodium's crypto_box_seal,
+// an ephemeral X25519 key followed by the XSalsa20-Poly1305 box of the plaintext.
+const SealedBoxScheme = "sealed-box"
+
+// closeEncryptionRequired is the close code of clients connecting without a valid public key
+// while the RequireEncryption option is set.
+const closeEncryptionRequired = 4003
+
+// Encryption is the payload of the encrypt op, which makes disgm seal every frame sent to the
+// client from then on, for deployments where TLS ends on infrastructure that must not read the
+// events.
+//
+// Frames are sealed with libsodium's crypto_box_seal for the X25519 public key of the client and
+// sent as binary frames; the client opens them with crypto_box_seal_open and its key pair, and
+// reads the plaintext in its encoding. The ENCRYPTION_ENABLED reply is the first sealed frame.
+// Ops sent by the client are not encrypted. The key may also be registered when connecting,
+// with the public_key query parameter, which seals the HELLO event too.
+//
+// Example:
+//
+//	{"op": "encrypt", "data": {"public_key": "base64 of the 32-byte X25519 public key"}}
+type Encryption struct {
+	PublicKey string `json:"public_key"` // X25519 public key, standard or URL-safe base64
+}
+
+// The encryption settings, set from the options.
+var encryption = struct {
+	sync.Mutex
+	required bool
+}{}
+
+// encryptionRequired reports whether the RequireEncryption option is set.
+func encryptionRequired() bool {
+	encryption.Lock()
+	defer encryption.Unlock()
+	return encryption.required
+}
+
+// parsePublicKey decodes an X25519 public key in standard or URL-safe base64, with or without
+// padding.
+func parsePublicKey(s string) (*[32]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	raw, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil {
+		raw, err = base64.RawURLEncoding.DecodeString(s)
+	}
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("public_key must be a base64-encoded 32-byte X25519 public key")
+	}
+	key := new([32]byte)
+	copy(key[:], raw)
+	return key, nil
+}
+
+// seal encrypts data for a public key as a sealed box.
+func seal(key *[32]byte, data []byte) ([]byte, error) {
+	return box.SealAnonymous(nil, data, key, rand.Reader)
+}
+
+// handleEncrypt registers the public key of a client and confirms it with an ENCRYPTION_ENABLED
+// event sealed for the key, or reports an invalid key with ENCRYPTION_ERROR. A client may replace
+// its key, but not turn encryption off again.
+func handleEncrypt(conn *websocket.Conn, raw json.RawMessage) {
+	var req Encryption
+	if err := json.Unmarshal(raw, &req); err != nil {
+		writeEvent(conn, "ENCRYPTION_ERROR", errorPayload{Error: "invalid encryption payload: " + err.Error()})
+		return
+	}
+	key, err := parsePublicKey(req.PublicKey)
+	if err != nil {
+		writeEvent(conn, "ENCRYPTION_ERROR", errorPayload{Error: err.Error()})
+		return
+	}
+
+	clientsMu.Lock()
+	if info, ok := clientInfo[conn]; ok {
+		info.publicKey, info.Encrypted = key, true
+	}
+	clientsMu.Unlock()
+
+	writeEvent(conn, "ENCRYPTION_ENABLED", Encryption{PublicKey: base64.StdEncoding.EncodeToString(key[:])})
+}
@@ -0,0 +1,233 @@
+package disgm
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RecordedEvent is an event stored in the persistent event buffer.
+type RecordedEvent struct {
+	ID   string          `json:"id"`                        // Sortable ID of the event within its guild
+	Time time.Time       `json:"time"`                      // When the event was sent to the clients
+	Name string          `json:"name"`                      // Name of the event, e.g. MESSAGE_CREATE
+	Data json.RawMessage `json:"data" swaggertype:"object"` // Data of the event as sent to the clients
+}
+
+// The persistent event buffer: every event sent to WebSocket clients is recorded per guild in the
+// key-value store, keyed by "<guild>/<id>" so keys sort by time. Guilds keep at most size events
+// and none older than the retention.
+var eventBuffer = struct {
+	sync.Mutex
+	storage   *Storage
+	size      int
+	retention time.Duration
+	counts    map[string]int
+	seq       uint64
+}{counts: make(map[string]int)}
+
+// loadEventBuffer counts the events already in the storage, so pruning continues after a restart.
+func loadEventBuffer(storage *Storage) {
+	eventBuffer.Lock()
+	defer eventBuffer.Unlock()
+
+	eventBuffer.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load event buffer: %v", err)
+		return
+	}
+	for _, key := range keys {
+		guildID, _, _ := strings.Cut(key, "/")
+		eventBuffer.counts[guildID]++
+	}
+}
+
+// recordEvent appends an event to the buffer of a guild.
+func recordEvent(guildID, name string, data json.RawMessage) {
+	eventBuffer.Lock()
+	defer eventBuffer.Unlock()
+
+	if eventBuffer.storage == nil || eventBuffer.size <= 0 {
+		return
+	}
+
+	now := time.Now()
+	eventBuffer.seq++
+	event := RecordedEvent{
+		ID:   fmt.Sprintf("%020d-%06d", now.UnixNano(), eventBuffer.seq%1000000),
+		Time: now,
+		Name: name,
+		Data: data,
+	}
+
+	value, err := json.Marshal(event)
+	if err == nil {
+		err = eventBuffer.storage.Set(guildID+"/"+event.ID, value)
+	}
+	if err != nil {
+		log.Printf("Failed to record event: %v", err)
+		return
+	}
+
+	// Prunes in batches, so the keys are not listed on every event.
+	eventBuffer.counts[guildID]++
+	if eventBuffer.counts[guildID] > eventBuffer.size+eventBuffer.size/10 {
+		pruneEvents(guildID)
+	}
+}
+
+// pruneEvents drops the events of a guild beyond the size and retention of the buffer.
+// The caller must hold the eventBuffer lock.
+func pruneEvents(guildID string) {
+	cutoff := fmt.Sprintf("%020d", time.Now().Add(-eventBuffer.retention).UnixNano())
+
+	err := eventBuffer.storage.Update(func(tx *StorageTx) error {
+		keys, err := tx.List(guildID + "/")
+		if err != nil {
+			return err
+		}
+
+		drop := max(0, len(keys)-eventBuffer.size)
+		for drop < len(keys) && strings.TrimPrefix(keys[drop], guildID+"/") < cutoff {
+			drop++
+		}
+		for _, key := range keys[:drop] {
+			if err := tx.Delete(key); err != nil {
+				return err
+			}
+		}
+		eventBuffer.counts[guildID] = len(keys) - drop
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to prune event buffer: %v", err)
+	}
+}
+
+// bufferedEvents calls fn for the recorded events of a guild between from and to, oldest first,
+// optionally restricted to the given event names. A zero from or to leaves the range open.
+func bufferedEvents(guildID string, from, to time.Time, types []string, fn func(RecordedEvent) error) error {
+	eventBuffer.Lock()
+	storage := eventBuffer.storage
+	eventBuffer.Unlock()
+
+	if storage == nil {
+		return nil
+	}
+
+	keys, err := storage.List(guildID + "/")
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		value, ok, err := storage.Get(key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue // Pruned in the meantime.
+		}
+
+		var event RecordedEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			continue
+		}
+		if (!from.IsZero() && event.Time.Before(from)) || (len(types) > 0 && !slices.Contains(types, event.Name)) {
+			continue
+		}
+		if !to.IsZero() && event.Time.After(to) {
+			break
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseEventRange reads the from, to and types query parameters of the event buffer endpoints.
+func parseEventRange(c *fiber.Ctx) (from, to time.Time, types []string, err error) {
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			return from, to, nil, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			return from, to, nil, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	if v := c.Query("types"); v != "" {
+		types = strings.Split(strings.ToUpper(v), ",")
+	}
+	return from, to, types, nil
+}
+
+// ExportGuildEvents streams the recorded events of the guild as NDJSON.
+//
+// This function writes one RecordedEvent per line, oldest first, for offline analysis or to
+// backfill a data warehouse. The buffer holds the events sent to WebSocket clients, limited by
+// the EventBufferSize and EventRetention options.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - from: Only events at or after this RFC 3339 time.
+//   - to: Only events at or before this RFC 3339 time.
+//   - types: Comma-separated event names, e.g. MESSAGE_CREATE,MESSAGE_DELETE.
+//
+// Returns:
+//   - On success, it streams the events as application/x-ndjson.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the range is invalid,
+//     or HTTP status 403 (Forbidden) for tokens restricted to channels.
+// @Summary		Export Guild Events
+// @Description	Stream the recorded events of the guild as NDJSON.
+// @Tags			Events
+// @Produce		application/x-ndjson
+// @Param			from	query		string	false	"Start of the range (RFC 3339)"
+// @Param			to		query		string	false	"End of the range (RFC 3339)"
+// @Param			types	query		string	false	"Comma-separated event names"
+// @Success		200		{object}	RecordedEvent
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Router			/api/guild/events/export [get]
+func ExportGuildEvents(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c) // Recorded events span all channels.
+	}
+
+	from, to, types, err := parseEventRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid range: " + err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+		err := bufferedEvents(guildID, from, to, types, func(event RecordedEvent) error {
+			if err := enc.Encode(event); err != nil {
+				return err
+			}
+			return w.Flush()
+		})
+		if err != nil {
+			log.Printf("Failed to export events: %v", err)
+		}
+	})
+	return nil
+}
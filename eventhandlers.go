@@ -0,0 +1,74 @@
+package disgm
+
+import (
+	"log"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// eventHandler is a handler added with OnEvent.
+type eventHandler struct {
+	eventType string // Event name, or "*" for every event
+	handle    func(guildID string, data interface{})
+}
+
+// The handlers of the embedding process, called for every event sent to the WebSocket clients.
+var eventHandlers = struct {
+	sync.Mutex
+	handlers []*eventHandler
+}{}
+
+// OnEvent adds a handler for the events disgm sends to WebSocket clients, so the embedding process
+// can use the same pipeline instead of adding discordgo handlers of its own.
+//
+// The handler receives the events of eventType, e.g. "MESSAGE_CREATE" or "APPROVAL_CREATE", or of
+// every type for "*", after the EventThrottles option dropped the excess ones, with the same data
+// the clients receive: the gateway payload as a map for Discord events, and the disgm type, e.g.
+// Approval, for the events of disgm. Handlers are called in the order events are sent, before the
+// clients receive them, so they should return quickly; long work belongs in a goroutine. The data
+// is shared with the clients and must not be modified. Replayed events are not passed to handlers.
+// Gateway events enter the pipeline once RegisterWebSocket has been called.
+//
+// Parameters:
+//   - eventType: string – The event name, or "*" for every event.
+//   - handler: func(guildID string, data interface{}) – Called with the guild and data of each event.
+//
+// Returns:
+//   - remove: func() – Removes the handler.
+func (d *Disgm) OnEvent(eventType string, handler func(guildID string, data interface{})) (remove func()) {
+	h := &eventHandler{eventType: strings.ToUpper(eventType), handle: handler}
+
+	eventHandlers.Lock()
+	eventHandlers.handlers = append(eventHandlers.handlers, h)
+	eventHandlers.Unlock()
+
+	return func() {
+		eventHandlers.Lock()
+		defer eventHandlers.Unlock()
+		eventHandlers.handlers = slices.DeleteFunc(eventHandlers.handlers, func(other *eventHandler) bool { return other == h })
+	}
+}
+
+// handleEvent calls the handlers of an event. A panicking handler is logged and does not stop the
+// event from reaching the other handlers and the clients.
+func handleEvent(guildID, name string, data interface{}) {
+	eventHandlers.Lock()
+	handlers := slices.Clone(eventHandlers.handlers)
+	eventHandlers.Unlock()
+
+	for _, h := range handlers {
+		if h.eventType != "*" && h.eventType != name {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Event handler for %s panicked: %v\n%s", name, r, debug.Stack())
+				}
+			}()
+			h.handle(guildID, data)
+		}()
+	}
+}
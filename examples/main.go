@@ -64,6 +64,7 @@ func main() {
 
 	disgmInstance.RegisterApiRouter()
 	disgmInstance.RegisterWebSocket()
+	disgmInstance.RegisterHookReceiver()
 
 	disgmInstance.Listen()
 
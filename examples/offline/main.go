@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rif223/disgm"
+	"github.com/rif223/disgm/disgmtest"
+)
+
+// Runs disgm against the in-memory Discord backend, for developing frontends offline.
+// Use the token "dev" for the API and WebSocket.
+func main() {
+
+	backend := disgmtest.New()
+	defer backend.Close()
+
+	guild := backend.AddGuild(&discordgo.Guild{Name: "Offline Guild"})
+	general := backend.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "general", Type: discordgo.ChannelTypeGuildText})
+	alice := backend.AddMember(&discordgo.Member{GuildID: guild.ID, User: &discordgo.User{Username: "alice"}})
+
+	session, err := backend.Session()
+	if err != nil {
+		log.Fatal("Error creating Discord session,", err)
+	}
+
+	disgmInstance, err := disgm.New(session, disgm.Options{
+		DisableStartupMessage: true,
+		TokenStore:            disgmtest.TokenStore{guild.ID: "dev"},
+	})
+	if err != nil {
+		log.Fatal("Error initializing Disgm,", err)
+	}
+
+	disgmInstance.RegisterApiRouter()
+	disgmInstance.RegisterWebSocket()
+
+	disgmInstance.Listen()
+
+	err = session.Open()
+	if err != nil {
+		log.Fatalf("Error opening Discord session: %v", err)
+	}
+	defer session.Close()
+
+	// Alice says hello every ten seconds
+	go func() {
+		for range time.Tick(10 * time.Second) {
+			backend.AddMessage(&discordgo.Message{ChannelID: general.ID, Author: alice.User, Content: "Hello!"})
+		}
+	}()
+
+	fmt.Println("Offline bot running....")
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	<-c
+
+}
@@ -0,0 +1,76 @@
+package disgm
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FaultHeader marks the responses of requests a fault was injected into.
+const FaultHeader = "X-Disgm-Fault"
+
+// FaultRule injects a fault into a percentage of the requests to matching routes, so dashboard
+// developers can test their retry and error handling. Rules are meant for testing instances only.
+type FaultRule struct {
+	Method     string        // HTTP method of the matching requests, empty for all
+	Path       string        // Path pattern below /api, ":" segments match any value and a trailing "*" any rest, e.g. "/guild/channels/:/messages*"
+	Percent    float64       // Share of the matching requests the fault is injected into, 0-100
+	Delay      time.Duration // Delay added before the request is handled or failed
+	Status     int           // HTTP status returned instead of handling the request, 0 to only delay
+	RateLimit  bool          // Returns a Discord-style 429 rate limit response instead of Status
+	RetryAfter time.Duration // Retry-After of rate limit responses, defaults to 1 second
+}
+
+// newFaultInjector returns a middleware that applies the first matching rule to each API request.
+func newFaultInjector(rules []FaultRule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path, ok := strings.CutPrefix(c.Path(), "/api")
+		if !ok {
+			return c.Next()
+		}
+
+		for _, rule := range rules {
+			if (rule.Method != "" && !strings.EqualFold(rule.Method, c.Method())) || !matchRoute(rule.Path, path) {
+				continue
+			}
+			if rand.Float64()*100 >= rule.Percent {
+				return c.Next()
+			}
+			return injectFault(c, rule)
+		}
+		return c.Next()
+	}
+}
+
+// injectFault delays a request and fails it according to a rule.
+func injectFault(c *fiber.Ctx, rule FaultRule) error {
+	if rule.Delay > 0 {
+		time.Sleep(rule.Delay)
+	}
+
+	switch {
+	case rule.RateLimit:
+		retryAfter := rule.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+		c.Set(FaultHeader, "rate_limit")
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Round(time.Second).Seconds())))
+		c.Set("X-RateLimit-Remaining", "0")
+		c.Set("X-RateLimit-Reset-After", strconv.FormatFloat(retryAfter.Seconds(), 'f', 3, 64))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"message":     "You are being rate limited.",
+			"retry_after": retryAfter.Seconds(),
+			"global":      false,
+		})
+	case rule.Status != 0:
+		c.Set(FaultHeader, "error")
+		return c.Status(rule.Status).SendString("Injected fault")
+	}
+
+	c.Set(FaultHeader, "delay")
+	return c.Next()
+}
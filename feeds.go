@@ -0,0 +1,560 @@
+package disgm
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultFeedInterval = 15 // Default polling interval of a feed in minutes.
+	minFeedInterval     = 5  // Minimum polling interval of a feed in minutes.
+)
+
+// defaultFeedTemplate is used for feeds created without a template.
+const defaultFeedTemplate = "**{{.Title}}**\n{{.Link}}"
+
+// maxFeedSize is the maximum size of a feed document in bytes.
+const maxFeedSize = 5 << 20
+
+// feedClient fetches feeds with a timeout, so a slow server cannot stall a poller.
+var feedClient = newPublicClient(30 * time.Second)
+
+// Feed is an RSS or Atom feed whose new items are posted to a guild channel.
+type Feed struct {
+	ID        string     `json:"id"`                 // Unique ID of the feed subscription
+	URL       string     `json:"url"`                // URL of the RSS or Atom document
+	ChannelID string     `json:"channel_id"`         // ID of the channel new items are posted to
+	Interval  int        `json:"interval"`           // Polling interval in minutes
+	Template  string     `json:"template,omitempty"` // Go text/template for the message, executed with a FeedItem
+	Status    FeedStatus `json:"status"`             // Result of the latest polls
+
+	guildID string             // Guild that created the feed
+	tmpl    *template.Template // Parsed message template
+	seen    map[string]bool    // IDs of the items in the latest document
+	stop    chan struct{}      // Closed to stop the poller
+}
+
+// storedFeed is a feed as persisted in the key-value store, with the items it has seen, so a
+// restart neither reposts them nor misses the items published in the meantime.
+type storedFeed struct {
+	Feed
+	GuildID string   `json:"guild_id"`
+	Seen    []string `json:"seen,omitempty"`
+}
+
+// FeedStatus reports the state of a feed poller.
+type FeedStatus struct {
+	LastPoll  *time.Time `json:"last_poll,omitempty"`  // When the feed was last fetched
+	LastPost  *time.Time `json:"last_post,omitempty"`  // When an item was last posted
+	LastError string     `json:"last_error,omitempty"` // Error of the latest poll, if it failed
+	Posted    int        `json:"posted"`               // Number of items posted since the feed was created
+}
+
+// FeedItem is an entry of an RSS or Atom feed, passed to the message template.
+type FeedItem struct {
+	ID        string // GUID or ID of the item, falling back to its link
+	Title     string // Title of the item
+	Link      string // Link to the item
+	Published string // Publication date as given by the feed
+}
+
+// feedDocument decodes both RSS 2.0 and Atom documents.
+type feedDocument struct {
+	Channel struct {
+		Items []struct {
+			GUID    string `xml:"guid"`
+			Title   string `xml:"title"`
+			Link    string `xml:"link"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+	Entries []struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Updated string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// A registry of feed subscriptions keyed by ID, persisted in the storage under the same key.
+var feeds = struct {
+	sync.Mutex
+	storage *Storage
+	byID    map[string]*Feed
+}{byID: make(map[string]*Feed)}
+
+// persistFeed writes a feed to the storage. The caller must hold the feeds lock.
+func persistFeed(feed *Feed) error {
+	stored := storedFeed{Feed: *feed, GuildID: feed.guildID}
+	if feed.seen != nil {
+		stored.Seen = make([]string, 0, len(feed.seen))
+		for id := range feed.seen {
+			stored.Seen = append(stored.Seen, id)
+		}
+		slices.Sort(stored.Seen)
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return feeds.storage.Set(feed.ID, data)
+}
+
+// loadFeeds restores the feed subscriptions from the storage and starts their pollers.
+func loadFeeds(s *discordgo.Session, storage *Storage) {
+	feeds.Lock()
+	defer feeds.Unlock()
+
+	feeds.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load feeds: %v", err)
+		return
+	}
+	for _, key := range keys {
+		data, ok, err := storage.Get(key)
+		if err != nil || !ok {
+			continue
+		}
+
+		var stored storedFeed
+		if err := json.Unmarshal(data, &stored); err != nil {
+			log.Printf("Failed to load feed %s: %v", key, err)
+			continue
+		}
+		feed := stored.Feed
+		feed.guildID = stored.GuildID
+		if feed.tmpl, err = template.New("feed").Parse(feed.Template); err != nil {
+			log.Printf("Failed to load feed %s: %v", key, err)
+			continue
+		}
+		if stored.Seen != nil {
+			feed.seen = make(map[string]bool, len(stored.Seen))
+			for _, id := range stored.Seen {
+				feed.seen[id] = true
+			}
+		}
+		feed.stop = make(chan struct{})
+		if old, ok := feeds.byID[feed.ID]; ok {
+			close(old.stop) // Replaces the poller when the feeds are loaded again.
+		}
+		feeds.byID[feed.ID] = &feed
+
+		startFeed(s, &feed)
+	}
+}
+
+// fetchFeed downloads a feed and returns its items in document order.
+func fetchFeed(url string) ([]FeedItem, error) {
+	resp, err := feedClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var doc feedDocument
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxFeedSize)).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var items []FeedItem
+	for _, i := range doc.Channel.Items {
+		items = append(items, FeedItem{ID: i.GUID, Title: i.Title, Link: i.Link, Published: i.PubDate})
+	}
+	for _, e := range doc.Entries {
+		item := FeedItem{ID: e.ID, Title: e.Title, Published: e.Updated}
+		for _, l := range e.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				item.Link = l.Href
+				break
+			}
+		}
+		items = append(items, item)
+	}
+
+	for i := range items {
+		if items[i].ID == "" {
+			items[i].ID = items[i].Link
+		}
+	}
+	return items, nil
+}
+
+// pollFeed fetches a feed once and posts the items that were not in the previous document.
+//
+// The first poll only records the current items, so subscribing does not flood the channel.
+// Messages are sent without holding the feeds lock, so a slow channel does not block the API.
+func pollFeed(s *discordgo.Session, feed *Feed) {
+	items, err := fetchFeed(feed.URL)
+
+	feeds.Lock()
+	now := time.Now()
+	feed.Status.LastPoll = &now
+	if err != nil {
+		feed.Status.LastError = err.Error()
+		feeds.Unlock()
+		return
+	}
+	first, seen, tmpl, channelID := feed.seen == nil, feed.seen, feed.tmpl, feed.ChannelID
+	feeds.Unlock()
+
+	// Feeds list the newest items first, so new items are posted in reverse.
+	var posted int
+	var lastError string
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+		if first || seen[item.ID] {
+			continue
+		}
+
+		var content strings.Builder
+		if err := tmpl.Execute(&content, item); err != nil {
+			lastError = err.Error()
+			continue
+		}
+		_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+			Content:         content.String(),
+			AllowedMentions: &discordgo.MessageAllowedMentions{}, // Item titles never ping.
+		})
+		if err != nil {
+			lastError = err.Error()
+			continue
+		}
+		posted++
+	}
+
+	feeds.Lock()
+	defer feeds.Unlock()
+
+	feed.Status.LastError = lastError
+	feed.Status.Posted += posted
+	if posted > 0 {
+		feed.Status.LastPost = &now
+	}
+	feed.seen = make(map[string]bool, len(items))
+	for _, item := range items {
+		feed.seen[item.ID] = true
+	}
+
+	// Feeds removed or replaced during the poll are not written back.
+	if feeds.byID[feed.ID] == feed {
+		if err := persistFeed(feed); err != nil {
+			log.Printf("Failed to store feed %s: %v", feed.ID, err)
+		}
+	}
+}
+
+// startFeed polls a feed in the background until its stop channel is closed.
+func startFeed(s *discordgo.Session, feed *Feed) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(feed.Interval) * time.Minute)
+		defer ticker.Stop()
+
+		pollFeed(s, feed)
+		for {
+			select {
+			case <-ticker.C:
+				pollFeed(s, feed)
+			case <-feed.stop:
+				return
+			}
+		}
+	}()
+}
+
+// parseFeedSettings validates the settings of a feed and parses its template.
+func parseFeedSettings(c *fiber.Ctx, s *discordgo.Session, feed *Feed) error {
+	guildID := c.Locals("ID").(string)
+
+	if !strings.HasPrefix(feed.URL, "http://") && !strings.HasPrefix(feed.URL, "https://") {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	if feed.Interval == 0 {
+		feed.Interval = defaultFeedInterval
+	}
+	if feed.Interval < minFeedInterval {
+		return fmt.Errorf("interval must be at least %d minutes", minFeedInterval)
+	}
+
+	channel, err := cachedChannel(s, feed.ChannelID)
+	if err != nil || channel.GuildID != guildID {
+		return fmt.Errorf("channel not found in guild")
+	}
+
+	if feed.Template == "" {
+		feed.Template = defaultFeedTemplate
+	}
+	feed.tmpl, err = template.New("feed").Parse(feed.Template)
+	return err
+}
+
+// guildFeed returns the feed with the given ID if it belongs to the guild of the request.
+// The caller must hold the feeds lock.
+func guildFeed(c *fiber.Ctx) (*Feed, bool) {
+	feed, ok := feeds.byID[c.Params("feedid")]
+	if !ok || feed.guildID != c.Locals("ID").(string) {
+		return nil, false
+	}
+	return feed, true
+}
+
+// GetGuildFeeds retrieves the feed subscriptions of the guild with their status.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Context:
+//   - ID: The guild ID is stored in the Fiber context under the key "ID".
+//
+// Returns:
+//   - It returns the feeds as a JSON array.
+// @Summary		Get Guild Feeds
+// @Description	Retrieve the RSS and Atom feed subscriptions of the guild.
+// @Tags			Feeds
+// @Success		200	{array}	Feed
+// @Router			/api/guild/feeds [get]
+func GetGuildFeeds(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	feeds.Lock()
+	defer feeds.Unlock()
+
+	list := []*Feed{}
+	for _, feed := range feeds.byID {
+		if feed.guildID == guildID {
+			list = append(list, feed)
+		}
+	}
+	slices.SortFunc(list, func(a, b *Feed) int { return strings.Compare(a.URL, b.URL) })
+
+	return c.JSON(list)
+}
+
+// GetGuildFeed retrieves a feed subscription of the guild with its status.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - feedid: The ID of the feed.
+//
+// Returns:
+//   - On success, it returns the feed as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such feed.
+// @Summary		Get Guild Feed
+// @Description	Retrieve a feed subscription and its polling status.
+// @Tags			Feeds
+// @Param			feedid	path		string	true	"Feed ID"
+// @Success		200		{object}	Feed
+// @Failure		404		{object}	error
+// @Router			/api/guild/feeds/{feedid} [get]
+func GetGuildFeed(c *fiber.Ctx, s *discordgo.Session) error {
+	feeds.Lock()
+	defer feeds.Unlock()
+
+	feed, ok := guildFeed(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Feed not found")
+	}
+
+	return c.JSON(feed)
+}
+
+// CreateGuildFeed subscribes a channel to an RSS or Atom feed.
+//
+// The feed is polled at the given interval and every new item is posted to the channel, rendered
+// with the template. Items already in the feed when subscribing are not posted.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A JSON object with the fields "url", "channel_id", "interval" (minutes) and "template".
+//
+// Returns:
+//   - On success, it returns the feed as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the settings are invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token scope,
+//     or HTTP status 500 (Internal Server Error) if the feed cannot be stored.
+// @Summary		Create Guild Feed
+// @Description	Subscribe a channel to an RSS or Atom feed.
+// @Tags			Feeds
+// @Param			body	body		Feed	true	"Feed settings"
+// @Success		201		{object}	Feed
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/feeds [post]
+func CreateGuildFeed(c *fiber.Ctx, s *discordgo.Session) error {
+	var feed Feed
+	if err := c.BodyParser(&feed); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if err := parseFeedSettings(c, s, &feed); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid feed: " + err.Error())
+	}
+	if !channelAllowed(c, feed.ChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	id, err := randomToken(8)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create feed: " + err.Error())
+	}
+	feed.ID = id
+	feed.Status = FeedStatus{}
+	feed.guildID = c.Locals("ID").(string)
+	feed.stop = make(chan struct{})
+
+	feeds.Lock()
+	if err := persistFeed(&feed); err != nil {
+		feeds.Unlock()
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create feed: " + err.Error())
+	}
+	feeds.byID[feed.ID] = &feed
+	feeds.Unlock()
+
+	startFeed(s, &feed)
+
+	return c.Status(fiber.StatusCreated).JSON(&feed)
+}
+
+// UpdateGuildFeed changes the URL, channel, interval or template of a feed subscription.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - feedid: The ID of the feed.
+//
+// Request Body:
+//   - A JSON object with the fields to change.
+//
+// Returns:
+//   - On success, it returns the updated feed as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such feed,
+//     HTTP status 400 (Bad Request) if the settings are invalid, HTTP status 403 (Forbidden)
+//     if the channel is outside the token scope, or HTTP status 500 (Internal Server Error)
+//     if the feed cannot be stored.
+// @Summary		Update Guild Feed
+// @Description	Change the settings of a feed subscription.
+// @Tags			Feeds
+// @Param			feedid	path		string	true	"Feed ID"
+// @Param			body	body		Feed	true	"Feed settings"
+// @Success		200		{object}	Feed
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/feeds/{feedid} [patch]
+func UpdateGuildFeed(c *fiber.Ctx, s *discordgo.Session) error {
+	feeds.Lock()
+	current, ok := guildFeed(c)
+	var feed Feed
+	if ok {
+		feed = Feed{URL: current.URL, ChannelID: current.ChannelID, Interval: current.Interval, Template: current.Template}
+	}
+	feeds.Unlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Feed not found")
+	}
+
+	if err := c.BodyParser(&feed); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if err := parseFeedSettings(c, s, &feed); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid feed: " + err.Error())
+	}
+	if !channelAllowed(c, feed.ChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	feeds.Lock()
+	if feeds.byID[current.ID] != current {
+		feeds.Unlock()
+		return c.Status(fiber.StatusNotFound).SendString("Feed not found")
+	}
+	feed.ID = current.ID
+	feed.Status = current.Status
+	feed.guildID = current.guildID
+	feed.stop = make(chan struct{})
+	if feed.URL == current.URL {
+		feed.seen = current.seen // Keeps the seen items, so the update does not repost them.
+	}
+	if err := persistFeed(&feed); err != nil {
+		feeds.Unlock()
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update feed: " + err.Error())
+	}
+	close(current.stop)
+	feeds.byID[feed.ID] = &feed
+	feeds.Unlock()
+
+	startFeed(s, &feed)
+
+	return c.JSON(&feed)
+}
+
+// DeleteGuildFeed unsubscribes a channel from a feed.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - feedid: The ID of the feed.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such feed,
+//     or HTTP status 500 (Internal Server Error) if it cannot be removed from the storage.
+// @Summary		Delete Guild Feed
+// @Description	Remove a feed subscription.
+// @Tags			Feeds
+// @Param			feedid	path	string	true	"Feed ID"
+// @Success		204
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/feeds/{feedid} [delete]
+func DeleteGuildFeed(c *fiber.Ctx, s *discordgo.Session) error {
+	feeds.Lock()
+	defer feeds.Unlock()
+
+	feed, ok := guildFeed(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Feed not found")
+	}
+
+	if err := feeds.storage.Delete(feed.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete feed: " + err.Error())
+	}
+	close(feed.stop)
+	delete(feeds.byID, feed.ID)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
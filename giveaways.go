@@ -0,0 +1,729 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	GiveawayReaction = "reaction" // Users enter by reacting to the giveaway message.
+	GiveawayButton   = "button"   // Users enter with a button below the giveaway message.
+)
+
+// giveawayButtonPrefix prefixes the custom ID of giveaway entry buttons. Interactions with this
+// prefix are answered by disgm.
+const giveawayButtonPrefix = "disgm_giveaway:"
+
+// minGiveawayDuration is the shortest duration of a giveaway.
+const minGiveawayDuration = 10 * time.Second
+
+const (
+	giveawayRetryDelay    = 10 * time.Second // First delay before drawing again when the entries cannot be read.
+	maxGiveawayRetryDelay = 10 * time.Minute // Longest delay between attempts to draw the winners.
+)
+
+// Giveaway is a prize drawn among the users who entered before it ended.
+type Giveaway struct {
+	ID           string               `json:"id"`                     // Unique ID of the giveaway
+	ChannelID    string               `json:"channel_id"`             // Channel of the giveaway message
+	MessageID    string               `json:"message_id"`             // ID of the giveaway message
+	Prize        string               `json:"prize"`                  // Prize shown in the giveaway message
+	Mode         string               `json:"mode"`                   // GiveawayReaction (default) or GiveawayButton
+	Emoji        string               `json:"emoji,omitempty"`        // Entry emoji of reaction giveaways, defaults to 🎉
+	Duration     int                  `json:"duration"`               // Duration in seconds, used on creation
+	WinnerCount  int                  `json:"winner_count"`           // Number of winners, defaults to 1
+	Requirements GiveawayRequirements `json:"requirements"`           // Conditions entrants must meet
+	EndsAt       time.Time            `json:"ends_at"`                // When the winners are drawn
+	Ended        bool                 `json:"ended"`                  // Whether the winners were drawn
+	Winners      []string             `json:"winners,omitempty"`      // User IDs of the current winners
+	PastWinners  []string             `json:"past_winners,omitempty"` // User IDs of winners replaced by rerolls
+
+	guildID  string          // Guild that created the giveaway
+	entrants map[string]bool // Users who entered a button giveaway
+	timer    *time.Timer     // Timer drawing the winners
+	attempts int             // Failed attempts to read the entries when drawing
+}
+
+// storedGiveaway is a giveaway as persisted in the key-value store, with its entrants.
+type storedGiveaway struct {
+	Giveaway
+	GuildID  string   `json:"guild_id"`
+	Entrants []string `json:"entrants,omitempty"`
+}
+
+// GiveawayRequirements are the conditions a user must meet to enter a giveaway.
+type GiveawayRequirements struct {
+	RoleIDs           []string `json:"role_ids,omitempty"`             // The user needs one of these roles, if set
+	MinAccountAgeDays int      `json:"min_account_age_days,omitempty"` // Minimum age of the Discord account
+	MinMemberDays     int      `json:"min_member_days,omitempty"`      // Minimum time since joining the guild
+}
+
+// A registry of giveaways keyed by ID, persisted in the storage under the same key.
+var giveaways = struct {
+	sync.Mutex
+	storage *Storage
+	byID    map[string]*Giveaway
+}{byID: make(map[string]*Giveaway)}
+
+// persistGiveaway writes a giveaway to the storage. The caller must hold the giveaways lock.
+func persistGiveaway(g *Giveaway) error {
+	stored := storedGiveaway{Giveaway: *g, GuildID: g.guildID, Entrants: make([]string, 0, len(g.entrants))}
+	for id := range g.entrants {
+		stored.Entrants = append(stored.Entrants, id)
+	}
+	slices.Sort(stored.Entrants)
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return giveaways.storage.Set(g.ID, data)
+}
+
+// loadGiveaways restores the giveaways from the storage and re-arms the timers of the running ones.
+// Giveaways that ended while disgm was not running are drawn right away.
+func loadGiveaways(s *discordgo.Session, storage *Storage) {
+	giveaways.Lock()
+	defer giveaways.Unlock()
+
+	giveaways.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load giveaways: %v", err)
+		return
+	}
+	for _, key := range keys {
+		data, ok, err := storage.Get(key)
+		if err != nil || !ok {
+			continue
+		}
+
+		var stored storedGiveaway
+		if err := json.Unmarshal(data, &stored); err != nil {
+			log.Printf("Failed to load giveaway %s: %v", key, err)
+			continue
+		}
+		g := &stored.Giveaway
+		g.guildID = stored.GuildID
+		g.entrants = make(map[string]bool, len(stored.Entrants))
+		for _, id := range stored.Entrants {
+			g.entrants[id] = true
+		}
+		if old, ok := giveaways.byID[g.ID]; ok && old.timer != nil {
+			old.timer.Stop() // Replaces the timer when the giveaways are loaded again.
+		}
+		giveaways.byID[g.ID] = g
+
+		if !g.Ended {
+			g.timer = time.AfterFunc(max(0, time.Until(g.EndsAt)), func() { endGiveaway(s, g) })
+		}
+	}
+}
+
+// eligible reports whether a user meets the requirements of a giveaway, with the reason if not.
+func (g *Giveaway) eligible(s *discordgo.Session, userID string) (bool, string) {
+	req := g.Requirements
+
+	if req.MinAccountAgeDays > 0 {
+		created, err := discordgo.SnowflakeTimestamp(userID)
+		if err != nil || time.Since(created) < time.Duration(req.MinAccountAgeDays)*24*time.Hour {
+			return false, fmt.Sprintf("your account must be at least %d days old", req.MinAccountAgeDays)
+		}
+	}
+
+	if len(req.RoleIDs) == 0 && req.MinMemberDays == 0 {
+		return true, ""
+	}
+
+	member, err := s.State.Member(g.guildID, userID)
+	if err != nil {
+		if member, err = s.GuildMember(g.guildID, userID); err != nil {
+			return false, "you must be a member of the server"
+		}
+	}
+
+	if len(req.RoleIDs) > 0 && !slices.ContainsFunc(member.Roles, func(id string) bool { return slices.Contains(req.RoleIDs, id) }) {
+		return false, "you do not have a required role"
+	}
+	if req.MinMemberDays > 0 && time.Since(member.JoinedAt) < time.Duration(req.MinMemberDays)*24*time.Hour {
+		return false, fmt.Sprintf("you must have been a member for at least %d days", req.MinMemberDays)
+	}
+	return true, ""
+}
+
+// entries returns the users who entered a giveaway and meet its requirements.
+//
+// Entrants of reaction giveaways are read from the reactions of the message, entrants of button
+// giveaways are tracked by the button handler, which already checked their requirements.
+func (g *Giveaway) entries(s *discordgo.Session) ([]string, error) {
+	giveaways.Lock()
+	mode, channelID, messageID, emoji := g.Mode, g.ChannelID, g.MessageID, g.Emoji
+	var users []string
+	for id := range g.entrants {
+		users = append(users, id)
+	}
+	giveaways.Unlock()
+
+	if mode == GiveawayButton {
+		slices.Sort(users)
+		return users, nil
+	}
+
+	afterID := ""
+	for {
+		page, err := s.MessageReactions(channelID, messageID, emoji, 100, "", afterID)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page {
+			if u.Bot {
+				continue
+			}
+			if ok, _ := g.eligible(s, u.ID); ok {
+				users = append(users, u.ID)
+			}
+		}
+		if len(page) < 100 {
+			return users, nil
+		}
+		afterID = page[len(page)-1].ID
+	}
+}
+
+// draw picks up to count random winners among the entries, skipping the excluded users.
+func draw(entries, exclude []string, count int) []string {
+	pool := slices.DeleteFunc(slices.Clone(entries), func(id string) bool { return slices.Contains(exclude, id) })
+	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	return pool[:min(count, len(pool))]
+}
+
+// giveawayEmbed renders the message of a giveaway.
+func giveawayEmbed(g *Giveaway) *discordgo.MessageEmbed {
+	var desc strings.Builder
+	if g.Ended {
+		if len(g.Winners) == 0 {
+			desc.WriteString("No valid entries.\n")
+		} else {
+			desc.WriteString("Winners: " + mentionUsers(g.Winners) + "\n")
+		}
+		fmt.Fprintf(&desc, "Ended <t:%d:R>", g.EndsAt.Unix())
+	} else {
+		if g.Mode == GiveawayButton {
+			desc.WriteString("Press the button below to enter!\n")
+		} else {
+			fmt.Fprintf(&desc, "React with %s to enter!\n", g.Emoji)
+		}
+		fmt.Fprintf(&desc, "Winners: %d\nEnds <t:%d:R>", g.WinnerCount, g.EndsAt.Unix())
+	}
+
+	req := g.Requirements
+	if len(req.RoleIDs) > 0 {
+		roles := make([]string, len(req.RoleIDs))
+		for i, id := range req.RoleIDs {
+			roles[i] = "<@&" + id + ">"
+		}
+		desc.WriteString("\nRequired role: " + strings.Join(roles, " or "))
+	}
+	if req.MinAccountAgeDays > 0 {
+		fmt.Fprintf(&desc, "\nAccount age: %d days", req.MinAccountAgeDays)
+	}
+	if req.MinMemberDays > 0 {
+		fmt.Fprintf(&desc, "\nMember for: %d days", req.MinMemberDays)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "🎉 " + g.Prize,
+		Description: desc.String(),
+		Timestamp:   g.EndsAt.Format(time.RFC3339),
+	}
+}
+
+// giveawayComponents returns the entry button of an open button giveaway.
+func giveawayComponents(g *Giveaway) []discordgo.MessageComponent {
+	if g.Mode != GiveawayButton || g.Ended {
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "Enter", Emoji: &discordgo.ComponentEmoji{Name: "🎉"}, Style: discordgo.PrimaryButton, CustomID: giveawayButtonPrefix + g.ID},
+	}}}
+}
+
+// mentionUsers formats user IDs as mentions.
+func mentionUsers(ids []string) string {
+	mentions := make([]string, len(ids))
+	for i, id := range ids {
+		mentions[i] = "<@" + id + ">"
+	}
+	return strings.Join(mentions, ", ")
+}
+
+// announceWinners edits the giveaway message and congratulates the winners in a reply.
+func announceWinners(s *discordgo.Session, g *Giveaway, winners []string) {
+	giveaways.Lock()
+	embed, components := giveawayEmbed(g), giveawayComponents(g)
+	giveaways.Unlock()
+
+	s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         g.MessageID,
+		Channel:    g.ChannelID,
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+
+	content := "Nobody entered **" + g.Prize + "**, so there is no winner."
+	if len(winners) > 0 {
+		content = "Congratulations " + mentionUsers(winners) + "! You won **" + g.Prize + "**!"
+	}
+	s.ChannelMessageSendComplex(g.ChannelID, &discordgo.MessageSend{
+		Content:         content,
+		Reference:       &discordgo.MessageReference{MessageID: g.MessageID, ChannelID: g.ChannelID, GuildID: g.guildID},
+		AllowedMentions: &discordgo.MessageAllowedMentions{Users: winners},
+	})
+}
+
+// endGiveaway draws the winners of a giveaway when it expires.
+//
+// If the entries cannot be read, e.g. during an outage of Discord, the giveaway stays open and
+// the winners are drawn again later, with a delay doubling up to maxGiveawayRetryDelay.
+func endGiveaway(s *discordgo.Session, g *Giveaway) {
+	entries, err := g.entries(s)
+
+	giveaways.Lock()
+	if giveaways.byID[g.ID] != g || g.Ended {
+		giveaways.Unlock()
+		return // Cancelled in the meantime.
+	}
+	if err != nil {
+		delay := min(giveawayRetryDelay<<min(g.attempts, 16), maxGiveawayRetryDelay)
+		g.attempts++
+		g.timer = time.AfterFunc(delay, func() { endGiveaway(s, g) })
+		giveaways.Unlock()
+
+		log.Printf("Failed to read the entries of giveaway %s, retrying in %s: %v", g.ID, delay, err)
+		return
+	}
+	g.Ended = true
+	g.Winners = draw(entries, nil, g.WinnerCount)
+	winners := g.Winners
+	if err := persistGiveaway(g); err != nil {
+		log.Printf("Failed to store giveaway %s: %v", g.ID, err)
+	}
+	giveaways.Unlock()
+
+	EventCall(g.guildID, "GIVEAWAY_END", g)
+	announceWinners(s, g, winners)
+}
+
+// registerGiveawayHandlers registers the handler answering the entry buttons of giveaways.
+func registerGiveawayHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.Type != discordgo.InteractionMessageComponent || i.Member == nil {
+			return
+		}
+		id, ok := strings.CutPrefix(i.MessageComponentData().CustomID, giveawayButtonPrefix)
+		if !ok {
+			return
+		}
+
+		reply := func(content string) {
+			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{Content: content, Flags: discordgo.MessageFlagsEphemeral},
+			})
+		}
+
+		giveaways.Lock()
+		g, ok := giveaways.byID[id]
+		ended := ok && g.Ended
+		giveaways.Unlock()
+
+		if !ok || ended {
+			reply("This giveaway has ended.")
+			return
+		}
+
+		if eligible, reason := g.eligible(s, i.Member.User.ID); !eligible {
+			reply("You cannot enter this giveaway: " + reason + ".")
+			return
+		}
+
+		giveaways.Lock()
+		entered := g.entrants[i.Member.User.ID]
+		g.entrants[i.Member.User.ID] = true
+		if !entered && giveaways.byID[g.ID] == g {
+			if err := persistGiveaway(g); err != nil {
+				log.Printf("Failed to store giveaway %s: %v", g.ID, err)
+			}
+		}
+		giveaways.Unlock()
+
+		if entered {
+			reply("You have already entered this giveaway.")
+			return
+		}
+		reply("You have entered the giveaway for **" + g.Prize + "**. Good luck!")
+	})
+}
+
+// guildGiveaway returns the giveaway with the given ID if it belongs to the guild of the request.
+// The caller must hold the giveaways lock.
+func guildGiveaway(c *fiber.Ctx) (*Giveaway, bool) {
+	g, ok := giveaways.byID[c.Params("giveawayid")]
+	if !ok || g.guildID != c.Locals("ID").(string) {
+		return nil, false
+	}
+	return g, true
+}
+
+// GetGuildGiveaways retrieves the giveaways of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the running and ended giveaways as a JSON array, ordered by end time.
+// @Summary		Get Guild Giveaways
+// @Description	Retrieve the running and ended giveaways of the guild.
+// @Tags			Giveaways
+// @Success		200	{array}	Giveaway
+// @Router			/api/guild/giveaways [get]
+func GetGuildGiveaways(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	giveaways.Lock()
+	defer giveaways.Unlock()
+
+	list := []*Giveaway{}
+	for _, g := range giveaways.byID {
+		if g.guildID == guildID {
+			list = append(list, g)
+		}
+	}
+	slices.SortFunc(list, func(a, b *Giveaway) int { return a.EndsAt.Compare(b.EndsAt) })
+
+	return c.JSON(list)
+}
+
+// GetGuildGiveaway retrieves a giveaway of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - giveawayid: The ID of the giveaway.
+//
+// Returns:
+//   - On success, it returns the giveaway as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such giveaway.
+// @Summary		Get Guild Giveaway
+// @Description	Retrieve a giveaway of the guild.
+// @Tags			Giveaways
+// @Param			giveawayid	path		string	true	"Giveaway ID"
+// @Success		200			{object}	Giveaway
+// @Failure		404			{object}	error
+// @Router			/api/guild/giveaways/{giveawayid} [get]
+func GetGuildGiveaway(c *fiber.Ctx, s *discordgo.Session) error {
+	giveaways.Lock()
+	defer giveaways.Unlock()
+
+	g, ok := guildGiveaway(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Giveaway not found")
+	}
+
+	return c.JSON(g)
+}
+
+// CreateGuildGiveaway starts a giveaway in a channel.
+//
+// This function posts the giveaway message and draws the winners when the duration has passed.
+// Users enter by reacting with the emoji or by pressing the entry button, depending on the mode.
+// Requirements are checked when a button is pressed, and when the winners of reaction giveaways are drawn.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A JSON object with the fields "channel_id", "prize", "duration" (seconds), "winner_count",
+//     "mode", "emoji" and "requirements".
+//
+// Returns:
+//   - On success, it returns the giveaway as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the giveaway is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token scope,
+//     or HTTP status 500 if the giveaway message cannot be posted or the giveaway cannot be stored.
+// @Summary		Create Guild Giveaway
+// @Description	Start a reaction or button giveaway in a channel.
+// @Tags			Giveaways
+// @Param			body	body		Giveaway	true	"Giveaway settings"
+// @Success		201		{object}	Giveaway
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/giveaways [post]
+func CreateGuildGiveaway(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	var g Giveaway
+	if err := c.BodyParser(&g); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	if g.Mode == "" {
+		g.Mode = GiveawayReaction
+	}
+	if g.Mode != GiveawayReaction && g.Mode != GiveawayButton {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid giveaway: mode must be reaction or button")
+	}
+	if g.Mode == GiveawayReaction && g.Emoji == "" {
+		g.Emoji = "🎉"
+	}
+	if g.WinnerCount == 0 {
+		g.WinnerCount = 1
+	}
+	duration := time.Duration(g.Duration) * time.Second
+	switch {
+	case strings.TrimSpace(g.Prize) == "":
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid giveaway: prize is required")
+	case g.WinnerCount < 1:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid giveaway: winner_count must be positive")
+	case duration < minGiveawayDuration:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid giveaway: duration must be at least 10 seconds")
+	}
+
+	if !channelAllowed(c, g.ChannelID, true) {
+		return channelForbidden(c)
+	}
+	channel, err := cachedChannel(s, g.ChannelID)
+	if err != nil || channel.GuildID != guildID {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid giveaway: channel not found in guild")
+	}
+
+	g.ID, err = randomToken(8)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create giveaway: " + err.Error())
+	}
+	g.guildID = guildID
+	g.EndsAt = time.Now().Add(duration).Truncate(time.Second)
+	g.Ended = false
+	g.Winners = nil
+	g.PastWinners = nil
+	g.entrants = make(map[string]bool)
+
+	msg, err := s.ChannelMessageSendComplex(g.ChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{giveawayEmbed(&g)},
+		Components: giveawayComponents(&g),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create giveaway: " + err.Error())
+	}
+	g.MessageID = msg.ID
+
+	if g.Mode == GiveawayReaction {
+		if err := s.MessageReactionAdd(g.ChannelID, g.MessageID, g.Emoji); err != nil {
+			s.ChannelMessageDelete(g.ChannelID, g.MessageID)
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid giveaway: failed to add emoji: " + err.Error())
+		}
+	}
+
+	ga := &g
+	giveaways.Lock()
+	if err := persistGiveaway(ga); err != nil {
+		giveaways.Unlock()
+		s.ChannelMessageDelete(g.ChannelID, g.MessageID)
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create giveaway: " + err.Error())
+	}
+	giveaways.byID[ga.ID] = ga
+	ga.timer = time.AfterFunc(duration, func() { endGiveaway(s, ga) })
+	giveaways.Unlock()
+
+	return c.Status(fiber.StatusCreated).JSON(ga)
+}
+
+// GetGiveawayEntrants retrieves the users who entered a giveaway and meet its requirements.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - giveawayid: The ID of the giveaway.
+//   - expand: "users" returns user objects instead of IDs, resolved through the user cache.
+//
+// Returns:
+//   - On success, it returns the user IDs or users of the entrants as a JSON array.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such giveaway,
+//     or HTTP status 500 if the reactions cannot be retrieved.
+// @Summary		Get Giveaway Entrants
+// @Description	Retrieve the eligible entrants of a giveaway.
+// @Tags			Giveaways
+// @Param			giveawayid	path	string	true	"Giveaway ID"
+// @Param			expand		query	string	false	"Return user objects"	Enums(users)
+// @Success		200			{array}	string
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/giveaways/{giveawayid}/entrants [get]
+func GetGiveawayEntrants(c *fiber.Ctx, s *discordgo.Session) error {
+	giveaways.Lock()
+	g, ok := guildGiveaway(c)
+	giveaways.Unlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Giveaway not found")
+	}
+
+	entries, err := g.entries(s)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve entrants: " + err.Error())
+	}
+	if entries == nil {
+		entries = []string{}
+	}
+
+	if c.Query("expand") == "users" {
+		resolved := resolveUsers(s, g.guildID, entries)
+		users := make([]*discordgo.User, 0, len(entries))
+		for _, id := range entries {
+			if u, ok := resolved[id]; ok {
+				users = append(users, u)
+			}
+		}
+		return c.JSON(users)
+	}
+
+	return c.JSON(entries)
+}
+
+// RerollGiveaway draws new winners for an ended giveaway.
+//
+// Users who already won the giveaway are excluded. By default all winners are replaced; the count
+// query parameter replaces only that many of them, e.g. when a single winner did not claim the prize.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - giveawayid: The ID of the giveaway.
+//   - count: The number of winners to replace, defaults to all.
+//
+// Returns:
+//   - On success, it returns the updated giveaway as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such giveaway,
+//     HTTP status 409 (Conflict) if the giveaway has not ended, or HTTP status 500 otherwise.
+// @Summary		Reroll Giveaway
+// @Description	Draw new winners for an ended giveaway.
+// @Tags			Giveaways
+// @Param			giveawayid	path		string	true	"Giveaway ID"
+// @Param			count		query		int		false	"Number of winners to replace"
+// @Success		200			{object}	Giveaway
+// @Failure		404			{object}	error
+// @Failure		409			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/giveaways/{giveawayid}/reroll [post]
+func RerollGiveaway(c *fiber.Ctx, s *discordgo.Session) error {
+	giveaways.Lock()
+	g, ok := guildGiveaway(c)
+	ended := ok && g.Ended
+	giveaways.Unlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Giveaway not found")
+	}
+	if !ended {
+		return c.Status(fiber.StatusConflict).SendString("Giveaway has not ended")
+	}
+
+	entries, err := g.entries(s)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve entrants: " + err.Error())
+	}
+
+	giveaways.Lock()
+	count := max(1, min(c.QueryInt("count", g.WinnerCount), g.WinnerCount))
+
+	// Replaces the last winners with new ones, never drawing a previous winner again.
+	exclude := append(slices.Clone(g.Winners), g.PastWinners...)
+	replaced := g.Winners[max(0, len(g.Winners)-count):]
+	newWinners := draw(entries, exclude, count)
+
+	g.PastWinners = append(g.PastWinners, replaced...)
+	g.Winners = append(slices.Clone(g.Winners[:len(g.Winners)-len(replaced)]), newWinners...)
+	if err := persistGiveaway(g); err != nil {
+		log.Printf("Failed to store giveaway %s: %v", g.ID, err)
+	}
+	giveaways.Unlock()
+
+	EventCall(g.guildID, "GIVEAWAY_REROLL", g)
+	announceWinners(s, g, newWinners)
+
+	return c.JSON(g)
+}
+
+// DeleteGuildGiveaway cancels a running giveaway or forgets an ended one.
+//
+// The giveaway message is left in place; a running giveaway is marked as cancelled in it.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - giveawayid: The ID of the giveaway.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such giveaway,
+//     or HTTP status 500 (Internal Server Error) if it cannot be removed from the storage.
+// @Summary		Delete Guild Giveaway
+// @Description	Cancel a running giveaway or forget an ended one.
+// @Tags			Giveaways
+// @Param			giveawayid	path	string	true	"Giveaway ID"
+// @Success		204
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/giveaways/{giveawayid} [delete]
+func DeleteGuildGiveaway(c *fiber.Ctx, s *discordgo.Session) error {
+	giveaways.Lock()
+	g, ok := guildGiveaway(c)
+	if !ok {
+		giveaways.Unlock()
+		return c.Status(fiber.StatusNotFound).SendString("Giveaway not found")
+	}
+	if err := giveaways.storage.Delete(g.ID); err != nil {
+		giveaways.Unlock()
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete giveaway: " + err.Error())
+	}
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	delete(giveaways.byID, g.ID)
+	giveaways.Unlock()
+
+	if !g.Ended {
+		embed := giveawayEmbed(g)
+		embed.Description = "This giveaway was cancelled."
+		components := []discordgo.MessageComponent{}
+		s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         g.MessageID,
+			Channel:    g.ChannelID,
+			Embeds:     &[]*discordgo.MessageEmbed{embed},
+			Components: &components,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,6 +1,10 @@
 package disgm
 
 import (
+	"encoding/json"
+	"slices"
+	"strings"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rif223/disgm/models"
@@ -8,6 +12,11 @@ import (
 
 type Guild = models.Guild
 
+type VoiceRegion = models.VoiceRegion
+
+// banPager pages through the bans of a guild by user ID.
+var banPager = pager{resource: LimitBans, reversible: true, boundaries: true}
+
 // GetGuild retrieves the details of a Discord guild.
 //
 // This function fetches the guild information using the guild ID, which is extracted from
@@ -40,10 +49,172 @@ func GetGuild(c *fiber.Ctx, s *discordgo.Session) error {
 	return c.JSON(guild)
 }
 
+// snapshotParts are the parts of a guild snapshot, and those included by default.
+var (
+	snapshotParts        = []string{"guild", "channels", "roles", "emojis", "members"}
+	defaultSnapshotParts = []string{"guild", "channels", "roles", "emojis"}
+)
+
+// GuildSnapshot is a guild with its channels, roles and emojis, as kept in the state. Parts that
+// were not asked for are null.
+type GuildSnapshot struct {
+	Guild    *discordgo.Guild     `json:"guild" swaggertype:"object"`          // The guild, without its members, channels, roles and emojis
+	Channels []*discordgo.Channel `json:"channels" swaggertype:"array,object"` // Channels the token may read
+	Roles    []*discordgo.Role    `json:"roles" swaggertype:"array,object"`
+	Emojis   []*discordgo.Emoji   `json:"emojis" swaggertype:"array,object"`
+	Members  *MemberSummary       `json:"members"`
+}
+
+// MemberSummary counts the members of a guild.
+type MemberSummary struct {
+	Total  int            `json:"total"`   // Members of the guild
+	Online int            `json:"online"`  // Members with a presence other than offline
+	Cached int            `json:"cached"`  // Members kept in the state, which the other counts are based on
+	Bots   int            `json:"bots"`    // Bots among the cached members
+	ByRole map[string]int `json:"by_role"` // Cached members by role ID
+}
+
+// guildSnapshot copies the parts of a guild from the state, limited to the channels the scope
+// may read. It returns false if the guild is not in the state.
+func guildSnapshot(s *discordgo.Session, guildID string, scope interface{}, parts []string) (*GuildSnapshot, bool) {
+	s.State.RLock()
+	defer s.State.RUnlock()
+
+	g, err := s.State.Guild(guildID)
+	if err != nil {
+		return nil, false
+	}
+
+	snapshot := &GuildSnapshot{}
+	if slices.Contains(parts, "guild") {
+		guild := *g
+		guild.Members, guild.Presences, guild.VoiceStates, guild.Channels, guild.Roles, guild.Emojis = nil, nil, nil, nil, nil, nil
+		snapshot.Guild = &guild
+	}
+	if slices.Contains(parts, "channels") {
+		snapshot.Channels = make([]*discordgo.Channel, 0, len(g.Channels))
+		for _, ch := range g.Channels {
+			if scopeAllows(scope, ch.ID, false) {
+				snapshot.Channels = append(snapshot.Channels, ch)
+			}
+		}
+	}
+	if slices.Contains(parts, "roles") {
+		snapshot.Roles = append([]*discordgo.Role{}, g.Roles...)
+	}
+	if slices.Contains(parts, "emojis") {
+		snapshot.Emojis = append([]*discordgo.Emoji{}, g.Emojis...)
+	}
+	if slices.Contains(parts, "members") {
+		summary := &MemberSummary{Total: g.MemberCount, Cached: len(g.Members), ByRole: make(map[string]int)}
+		for _, p := range g.Presences {
+			if p.Status != discordgo.StatusOffline && p.Status != "" {
+				summary.Online++
+			}
+		}
+		for _, m := range g.Members {
+			if m.User != nil && m.User.Bot {
+				summary.Bots++
+			}
+			for _, roleID := range m.Roles {
+				summary.ByRole[roleID]++
+			}
+		}
+		snapshot.Members = summary
+	}
+	return snapshot, true
+}
+
+// GetGuildSnapshot retrieves the guild, its channels, roles and emojis in one response.
+//
+// The snapshot is assembled from the state kept from the gateway, so it costs no Discord API
+// calls and replaces the separate requests a dashboard makes when it loads. The parts are chosen
+// with the include query parameter; member counts are only included when asked for.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - include: Comma-separated parts of the snapshot, of guild, channels, roles, emojis and
+//     members, defaults to guild,channels,roles,emojis.
+//
+// Returns:
+//   - On success, it returns the snapshot as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if a part is unknown,
+//     or HTTP status 404 (Not Found) if the guild is not in the state yet.
+// @Summary		Get Guild Snapshot
+// @Description	Retrieve the guild, channels, roles, emojis and optionally member counts in one response, from the state.
+// @Tags			Guild
+// @Param			include	query		string	false	"Comma-separated parts: guild, channels, roles, emojis, members"
+// @Success		200		{object}	GuildSnapshot
+// @Failure		400		{object}	error
+// @Failure		404		{object}	error
+// @Router			/api/guild/snapshot [get]
+func GetGuildSnapshot(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	parts := defaultSnapshotParts
+	if include := c.Query("include"); include != "" {
+		parts = strings.Split(include, ",")
+		for i, part := range parts {
+			parts[i] = strings.TrimSpace(part)
+			if !slices.Contains(snapshotParts, parts[i]) {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid include: unknown part " + parts[i])
+			}
+		}
+	}
+
+	snapshot, ok := guildSnapshot(s, guildID, c.Locals("Scope"), parts)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Guild not found in the state")
+	}
+
+	return c.JSON(snapshot)
+}
+
+// GetGuildRegions retrieves the voice regions available to a guild.
+//
+// The regions are the values accepted as rtc_region when editing a voice channel, with the
+// optimal region for the bot flagged. discordgo has no guild regions endpoint, so this function
+// calls the Discord API directly.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the voice regions as a JSON array.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) with an error message.
+// @Summary		Get Guild Regions
+// @Description	Retrieve the voice regions available to the guild, e.g. for the RTC region of voice channels.
+// @Tags			Guild
+// @Success		200	{array}		VoiceRegion
+// @Failure		500	{object}	error
+// @Router			/api/guild/regions [get]
+func GetGuildRegions(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	endpoint := discordgo.EndpointGuild(guildID) + "/regions"
+	body, err := s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve voice regions: " + err.Error())
+	}
+
+	regions := []VoiceRegion{}
+	if err := json.Unmarshal(body, &regions); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve voice regions: " + err.Error())
+	}
+
+	return c.JSON(regions)
+}
+
 // GetGuildBans retrieves the list of bans for a Discord guild.
 //
-// This function fetches a list of banned members from a guild by using the guild ID,
-// which is stored in the request context. It returns up to 100 bans at a time.
+// This function fetches a page of banned members from a guild by using the guild ID,
+// which is stored in the request context. It returns up to 100 bans at a time by default,
+// ordered by user ID. The first page may start at a user ID with before or after, instead of
+// the cursor of a previous page, e.g. to resume a listing that was interrupted.
 //
 // Parameters:
 //   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
@@ -52,24 +223,48 @@ func GetGuild(c *fiber.Ctx, s *discordgo.Session) error {
 // Request Context:
 //   - ID: The ID of the guild is stored in the Fiber context under the key "ID".
 //
+// Request Parameters:
+//   - limit: Optional number of bans, 1 to 1000, defaults to 100; both can be changed with the Limits option.
+//   - cursor: Optional cursor of the page, from the paging of a previous page.
+//   - before: Optional user ID; the page holds the bans of lower user IDs.
+//   - after: Optional user ID; the page holds the bans of higher user IDs.
+//
 // Returns:
-//   - On success, it returns the list of bans as JSON.
-//   - On failure, it returns an HTTP status 500 (Internal Server Error) with an error message.
+//   - On success, it returns the page of bans as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the limit, cursor, before or
+//     after is invalid or more than one of cursor, before and after is set,
+//     or HTTP status 500 (Internal Server Error) with an error message.
 // @Summary		Get Guild Bans
-// @Description	Retrieve all banned users from the guild.
+// @Description	Retrieve a page of banned users from the guild, ordered by user ID.
 // @Tags			Bans
-// @Success		200	{array}		models.GuildBan
-// @Failure		500	{object}	error
+// @Param			limit	query		int		false	"Bans per page"	minimum(1)	maximum(1000)	default(100)
+// @Param			cursor	query		string	false	"Page cursor"
+// @Param			before	query		string	false	"Bans of lower user IDs"
+// @Param			after	query		string	false	"Bans of higher user IDs"
+// @Success		200		{object}	Page{data=[]models.GuildBan}
+// @Failure		400		{object}	error
+// @Failure		500		{object}	error
 // @Router			/api/guild/bans [get]
 func GetGuildBans(c *fiber.Ctx, s *discordgo.Session) error {
 	guildID := c.Locals("ID").(string)
 
-	bans, err := s.GuildBans(guildID, 100, "", "")
+	req, err := banPager.request(c)
+	if err != nil {
+		return invalidPage(c, err)
+	}
+
+	bans, err := s.GuildBans(guildID, req.limit, req.before, req.after)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild bans: " + err.Error())
 	}
 
-	return c.JSON(bans)
+	ids := make([]string, len(bans))
+	for i, ban := range bans {
+		cacheUsers(ban.User)
+		ids[i] = ban.User.ID
+	}
+
+	return banPager.send(c, req, bans, ids, nil)
 }
 
 // GetGuildBan retrieves information about a specific banned member in a guild.
@@ -106,6 +301,8 @@ func GetGuildBan(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild ban: " + err.Error())
 	}
 
+	cacheUsers(ban.User)
+
 	return c.JSON(ban)
 }
 
@@ -151,7 +348,7 @@ func AddGuildBan(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
 
-	err := s.GuildBanCreateWithReason(guildID, userID, banData.Reason, banData.DeleteMessageDays)
+	err := s.GuildBanCreateWithReason(guildID, userID, auditReason(c, banData.Reason), banData.DeleteMessageDays)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to add guild ban: " + err.Error())
 	}
@@ -188,7 +385,7 @@ func RemoveGuildBan(c *fiber.Ctx, s *discordgo.Session) error {
 	guildID := c.Locals("ID").(string)
 	userID := c.Params("userid")
 
-	err := s.GuildBanDelete(guildID, userID)
+	err := s.GuildBanDelete(guildID, userID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to remove guild ban: " + err.Error())
 	}
@@ -219,6 +416,7 @@ func RemoveGuildBan(c *fiber.Ctx, s *discordgo.Session) error {
 // @Description	Ban multiple users in the guild at once.
 // @Tags			Bans
 // @Success		204
+// @Success		202	{object}	Approval
 // @Failure		500	{object}	error
 // @Router			/api/guild/bulk-ban [post]
 func BulkBanMembers(c *fiber.Ctx, s *discordgo.Session) error {
@@ -229,12 +427,26 @@ func BulkBanMembers(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
 
-	for _, userID := range userIDs {
-		err := s.GuildBanCreate(guildID, userID, 0)
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).SendString("Failed to ban user: " + err.Error())
-		}
+	if approvals.required(ActionBulkBan) {
+		return requestApproval(c, ActionBulkBan, userIDs, func(options ...discordgo.RequestOption) error {
+			return bulkBan(s, guildID, userIDs, options...)
+		})
+	}
+
+	err := bulkBan(s, guildID, userIDs, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to ban user: " + err.Error())
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// bulkBan bans each user in turn, stopping at the first failure.
+func bulkBan(s *discordgo.Session, guildID string, userIDs []string, options ...discordgo.RequestOption) error {
+	for _, userID := range userIDs {
+		if err := s.GuildBanCreate(guildID, userID, 0, options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package disgm
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// intentGuildMessagePolls is the intent of the poll vote events, which discordgo does not define.
+const intentGuildMessagePolls discordgo.Intent = 1 << 24
+
+// intentNames are the names of the gateway intents as documented by Discord.
+var intentNames = map[discordgo.Intent]string{
+	discordgo.IntentGuilds:                "GUILDS",
+	discordgo.IntentGuildMembers:          "GUILD_MEMBERS",
+	discordgo.IntentGuildModeration:       "GUILD_MODERATION",
+	discordgo.IntentGuildVoiceStates:      "GUILD_VOICE_STATES",
+	discordgo.IntentGuildPresences:        "GUILD_PRESENCES",
+	discordgo.IntentGuildMessages:         "GUILD_MESSAGES",
+	discordgo.IntentGuildMessageReactions: "GUILD_MESSAGE_REACTIONS",
+	discordgo.IntentGuildMessageTyping:    "GUILD_MESSAGE_TYPING",
+	discordgo.IntentMessageContent:        "MESSAGE_CONTENT",
+	intentGuildMessagePolls:               "GUILD_MESSAGE_POLLS",
+}
+
+// eventIntents maps the forwarded events to the intent Discord sends them for.
+// Events missing from the map, like INTERACTION_CREATE, are sent without an intent.
+var eventIntents = map[string]discordgo.Intent{
+	"GUILD_UPDATE":                discordgo.IntentGuilds,
+	"CHANNEL_CREATE":              discordgo.IntentGuilds,
+	"CHANNEL_UPDATE":              discordgo.IntentGuilds,
+	"CHANNEL_DELETE":              discordgo.IntentGuilds,
+	"THREAD_CREATE":               discordgo.IntentGuilds,
+	"THREAD_UPDATE":               discordgo.IntentGuilds,
+	"THREAD_DELETE":               discordgo.IntentGuilds,
+	"THREAD_MEMBERS_UPDATE":       discordgo.IntentGuilds,
+	"THREAD_LIST_SYNC":            discordgo.IntentGuilds,
+	"GUILD_ROLE_CREATE":           discordgo.IntentGuilds,
+	"GUILD_ROLE_UPDATE":           discordgo.IntentGuilds,
+	"GUILD_ROLE_DELETE":           discordgo.IntentGuilds,
+	"VOICE_STATE_UPDATE":          discordgo.IntentGuildVoiceStates,
+	"GUILD_MEMBER_ADD":            discordgo.IntentGuildMembers,
+	"GUILD_MEMBER_UPDATE":         discordgo.IntentGuildMembers,
+	"GUILD_MEMBER_REMOVE":         discordgo.IntentGuildMembers,
+	"GUILD_BAN_ADD":               discordgo.IntentGuildModeration,
+	"GUILD_BAN_REMOVE":            discordgo.IntentGuildModeration,
+	"MESSAGE_CREATE":              discordgo.IntentGuildMessages,
+	"MESSAGE_UPDATE":              discordgo.IntentGuildMessages,
+	"MESSAGE_DELETE":              discordgo.IntentGuildMessages,
+	"MESSAGE_REACTION_ADD":        discordgo.IntentGuildMessageReactions,
+	"MESSAGE_REACTION_REMOVE":     discordgo.IntentGuildMessageReactions,
+	"MESSAGE_REACTION_REMOVE_ALL": discordgo.IntentGuildMessageReactions,
+	"MESSAGE_POLL_VOTE_ADD":       intentGuildMessagePolls,
+	"MESSAGE_POLL_VOTE_REMOVE":    intentGuildMessagePolls,
+	"PRESENCE_UPDATE":             discordgo.IntentGuildPresences,
+	"TYPING_START":                discordgo.IntentGuildMessageTyping,
+}
+
+// IntentWarning reports a gateway intent the session does not request, and what lacks it.
+type IntentWarning struct {
+	Intent   string `json:"intent"`     // Name of the intent, e.g. MESSAGE_CONTENT
+	Required bool   `json:"required"`   // Whether an enabled feature needs the intent, rather than only forwarded events
+	Needed   string `json:"needed_for"` // The features or events that need the intent
+}
+
+// The intent warnings found when disgm was created.
+var intentWarnings = struct {
+	sync.Mutex
+	warnings []IntentWarning
+}{}
+
+// checkIntents compares the intents of the session with those needed by the enabled features
+// and the forwarded events. Without them, Discord silently sends empty message contents or no
+// events at all.
+func checkIntents(s *discordgo.Session, opt *Options) []IntentWarning {
+	intents := s.Identify.Intents
+	var warnings []IntentWarning
+
+	// Intents needed by features, which do not work without them.
+	required := []struct {
+		intent  discordgo.Intent
+		enabled bool
+		needed  string
+	}{
+		{discordgo.IntentGuilds, true, "the guild state"},
+		{discordgo.IntentGuildMessages, opt.MessageLog, "the message log"},
+		{discordgo.IntentMessageContent, opt.MessageLog, "the message log"},
+		{discordgo.IntentMessageContent, !slices.Contains(opt.DisabledModules, ModuleMessages), "message contents of the messages module"},
+		{discordgo.IntentGuildMembers, opt.Projections, "the member directory"},
+	}
+	for _, r := range required {
+		if !r.enabled || intents&r.intent != 0 {
+			continue
+		}
+		i := slices.IndexFunc(warnings, func(w IntentWarning) bool { return w.Intent == intentNames[r.intent] })
+		if i < 0 {
+			warnings = append(warnings, IntentWarning{Intent: intentNames[r.intent], Required: true, Needed: r.needed})
+		} else if !strings.Contains(warnings[i].Needed, r.needed) {
+			warnings[i].Needed += ", " + r.needed
+		}
+	}
+
+	// Intents of the forwarded events, which clients may not need.
+	events := make(map[discordgo.Intent][]string)
+	for _, name := range forwardedEvents {
+		if intent, ok := eventIntents[name]; ok && intents&intent == 0 {
+			events[intent] = append(events[intent], name)
+		}
+	}
+	for intent, names := range events {
+		needed := strings.Join(names, ", ") + " events"
+		i := slices.IndexFunc(warnings, func(w IntentWarning) bool { return w.Intent == intentNames[intent] })
+		if i < 0 {
+			warnings = append(warnings, IntentWarning{Intent: intentNames[intent], Needed: needed})
+		} else {
+			warnings[i].Needed += ", " + needed
+		}
+	}
+
+	slices.SortFunc(warnings, func(a, b IntentWarning) int { return strings.Compare(a.Intent, b.Intent) })
+	return warnings
+}
+
+// validateIntents checks the intents of the session when disgm is created. Missing intents are
+// logged, or fail creation with the StrictIntents option if an enabled feature needs them.
+func validateIntents(s *discordgo.Session, opt *Options) error {
+	warnings := checkIntents(s, opt)
+
+	intentWarnings.Lock()
+	intentWarnings.warnings = warnings
+	intentWarnings.Unlock()
+
+	var missing []string
+	for _, w := range warnings {
+		log.Printf("Missing gateway intent %s, needed for %s", w.Intent, w.Needed)
+		if w.Required {
+			missing = append(missing, w.Intent)
+		}
+	}
+	if opt.StrictIntents && len(missing) > 0 {
+		return fmt.Errorf("missing required gateway intents: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Health reports the state of disgm.
+type Health struct {
+	Status  string          `json:"status"`  // "ok", or "degraded" if the gateway is down or required intents are missing
+	Gateway bool            `json:"gateway"` // Whether the gateway connection is ready
+	Intents []IntentWarning `json:"intents"` // Gateway intents the session does not request
+}
+
+// GetHealth retrieves the state of the gateway connection and the missing gateway intents.
+//
+// The intents are checked when disgm is created, against the features enabled in its options
+// and the events forwarded to WebSocket clients. Privileged intents must also be enabled for the
+// application in the developer portal, which cannot be checked here. The endpoint is served
+// without a token, see HealthRouter.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the health as JSON, with HTTP status 200 even if degraded.
+//
+// @Summary		Get Health
+// @Description	Retrieve the state of the gateway connection and the gateway intents the session lacks.
+// @Tags			Health
+// @Success		200	{object}	Health
+// @Router			/api/health [get]
+func GetHealth(c *fiber.Ctx, s *discordgo.Session) error {
+	intentWarnings.Lock()
+	health := Health{Status: "ok", Gateway: s.DataReady, Intents: slices.Clone(intentWarnings.warnings)}
+	intentWarnings.Unlock()
+
+	if health.Intents == nil {
+		health.Intents = []IntentWarning{}
+	}
+	if !health.Gateway || slices.ContainsFunc(health.Intents, func(w IntentWarning) bool { return w.Required }) {
+		health.Status = "degraded"
+	}
+
+	return c.JSON(health)
+}
@@ -0,0 +1,382 @@
+package disgm
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Providers supported by the inbound webhook receiver.
+const (
+	HookProviderGitHub  = "github"  // Verifies X-Hub-Signature-256 and reads the event from X-GitHub-Event.
+	HookProviderStripe  = "stripe"  // Verifies Stripe-Signature and reads the event from the "type" field.
+	HookProviderGeneric = "generic" // Verifies X-Signature and reads the event from the "event" field.
+)
+
+// stripeTolerance is the maximum age of a Stripe signature timestamp.
+const stripeTolerance = 5 * time.Minute
+
+// defaultHookTemplate is used for integrations registered without a template.
+const defaultHookTemplate = "**{{.Event}}** received from {{.Integration}}"
+
+// Hook maps events of an external service to messages in a guild channel.
+type Hook struct {
+	Name      string   `json:"name"`               // Name of the integration, used in /hooks/{name}
+	Provider  string   `json:"provider"`           // Signature scheme, one of "github", "stripe" or "generic"
+	ChannelID string   `json:"channel_id"`         // ID of the channel the messages are posted to
+	Secret    string   `json:"secret,omitempty"`   // Shared secret for the HMAC signature, never returned
+	Template  string   `json:"template,omitempty"` // Go text/template for the message content
+	Events    []string `json:"events,omitempty"`   // Events to post, empty posts every event
+
+	guildID string             // Guild that registered the integration
+	tmpl    *template.Template // Parsed message template
+}
+
+// storedHook is an integration as persisted in the key-value store, with its secret.
+type storedHook struct {
+	Hook
+	GuildID string `json:"guild_id"`
+}
+
+// HookData is the data passed to the message template of an integration.
+type HookData struct {
+	Integration string                 // Name of the integration
+	Event       string                 // Name of the external event
+	Payload     map[string]interface{} // Decoded JSON body of the request
+}
+
+// A registry of inbound webhook integrations keyed by name, persisted in the storage under the same key.
+var hooks = struct {
+	sync.RWMutex
+	storage *Storage
+	byName  map[string]*Hook
+}{byName: make(map[string]*Hook)}
+
+// loadHooks restores the registered integrations from the storage.
+func loadHooks(storage *Storage) {
+	hooks.Lock()
+	defer hooks.Unlock()
+
+	hooks.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load hooks: %v", err)
+		return
+	}
+	for _, key := range keys {
+		data, ok, err := storage.Get(key)
+		if err != nil || !ok {
+			continue
+		}
+
+		var stored storedHook
+		if err := json.Unmarshal(data, &stored); err != nil {
+			log.Printf("Failed to load hook %s: %v", key, err)
+			continue
+		}
+		hook := stored.Hook
+		hook.guildID = stored.GuildID
+		if hook.tmpl, err = template.New(hook.Name).Parse(hook.Template); err != nil {
+			log.Printf("Failed to load hook %s: %v", key, err)
+			continue
+		}
+		hooks.byName[hook.Name] = &hook
+	}
+}
+
+// verifyHookSignature checks the HMAC signature of an inbound request against the integration secret.
+func verifyHookSignature(c *fiber.Ctx, hook *Hook, body []byte) bool {
+	sign := func(data []byte) []byte {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(data)
+		return mac.Sum(nil)
+	}
+	equal := func(signature string, data []byte) bool {
+		sig, err := hex.DecodeString(signature)
+		return err == nil && hmac.Equal(sig, sign(data))
+	}
+
+	switch hook.Provider {
+	case HookProviderGitHub:
+		return equal(strings.TrimPrefix(c.Get("X-Hub-Signature-256"), "sha256="), body)
+
+	case HookProviderStripe:
+		var timestamp string
+		var signatures []string
+		for _, part := range strings.Split(c.Get("Stripe-Signature"), ",") {
+			key, value, _ := strings.Cut(part, "=")
+			switch key {
+			case "t":
+				timestamp = value
+			case "v1":
+				signatures = append(signatures, value)
+			}
+		}
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || time.Since(time.Unix(unix, 0)).Abs() > stripeTolerance {
+			return false
+		}
+		signed := append([]byte(timestamp+"."), body...)
+		return slices.ContainsFunc(signatures, func(sig string) bool { return equal(sig, signed) })
+
+	default:
+		return equal(c.Get("X-Signature"), body)
+	}
+}
+
+// hookEvent returns the name of the external event carried by an inbound request.
+func hookEvent(c *fiber.Ctx, hook *Hook, payload map[string]interface{}) string {
+	switch hook.Provider {
+	case HookProviderGitHub:
+		return c.Get("X-GitHub-Event")
+	case HookProviderStripe:
+		event, _ := payload["type"].(string)
+		return event
+	default:
+		event, _ := payload["event"].(string)
+		return event
+	}
+}
+
+// ReceiveHook handles an inbound webhook from an external service.
+//
+// This function verifies the HMAC signature of the request with the integration secret, renders
+// the integration template with the decoded payload and posts the result to the configured channel.
+// The route is not protected by the token middleware, the signature authenticates the sender.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - integration: The name of the registered integration.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content), also for events that are filtered out.
+//   - On failure, it returns an HTTP status 404 (Not Found) for unknown integrations,
+//     HTTP status 401 (Unauthorized) for invalid signatures, HTTP status 400 (Bad Request) for
+//     invalid payloads, or HTTP status 500 (Internal Server Error) if the message cannot be sent.
+// @Summary		Receive Hook
+// @Description	Receive a signed webhook from an external service and post it to Discord.
+// @Tags			Hooks
+// @Param			integration	path	string	true	"Integration name"
+// @Success		204
+// @Failure		400	{object}	error
+// @Failure		401	{object}	error
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/hooks/{integration} [post]
+func ReceiveHook(c *fiber.Ctx, s *discordgo.Session) error {
+	hooks.RLock()
+	hook, ok := hooks.byName[c.Params("integration")]
+	hooks.RUnlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Integration not found")
+	}
+
+	body := c.Body()
+	if !verifyHookSignature(c, hook, body) {
+		return c.Status(fiber.StatusUnauthorized).SendString("Invalid signature")
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	event := hookEvent(c, hook, payload)
+	if len(hook.Events) > 0 && !slices.Contains(hook.Events, event) {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	var content strings.Builder
+	if err := hook.tmpl.Execute(&content, HookData{Integration: hook.Name, Event: event, Payload: payload}); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Failed to render template: " + err.Error())
+	}
+
+	msg := content.String()
+	if runes := []rune(msg); len(runes) > 2000 {
+		msg = string(runes[:2000]) // Discord rejects longer message content.
+	}
+
+	_, err := s.ChannelMessageSendComplex(hook.ChannelID, &discordgo.MessageSend{
+		Content:         msg,
+		AllowedMentions: &discordgo.MessageAllowedMentions{}, // Payloads of external services never ping.
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to send message: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetGuildHooks retrieves the inbound webhook integrations registered by the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Context:
+//   - ID: The guild ID is stored in the Fiber context under the key "ID".
+//
+// Returns:
+//   - It returns the integrations as a JSON array without their secrets.
+// @Summary		Get Guild Hooks
+// @Description	Retrieve the inbound webhook integrations of the guild.
+// @Tags			Hooks
+// @Success		200	{array}	Hook
+// @Router			/api/guild/hooks [get]
+func GetGuildHooks(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	hooks.RLock()
+	list := []Hook{}
+	for _, hook := range hooks.byName {
+		if hook.guildID == guildID {
+			h := *hook
+			h.Secret = ""
+			list = append(list, h)
+		}
+	}
+	hooks.RUnlock()
+
+	slices.SortFunc(list, func(a, b Hook) int { return strings.Compare(a.Name, b.Name) })
+
+	return c.JSON(list)
+}
+
+// PutGuildHook registers or replaces an inbound webhook integration.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - integration: The name of the integration, used in the receiver URL /hooks/{integration}.
+//
+// Request Body:
+//   - A JSON object with the fields "provider", "channel_id", "secret", "template" and "events".
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body or template is invalid,
+//     HTTP status 403 (Forbidden) if the channel is not writable, HTTP status 409 (Conflict)
+//     if another guild already uses the name, or HTTP status 500 (Internal Server Error) if it
+//     cannot be stored.
+// @Summary		Put Guild Hook
+// @Description	Register or replace an inbound webhook integration.
+// @Tags			Hooks
+// @Param			integration	path	string	true	"Integration name"
+// @Param			body		body	Hook	true	"Integration settings"
+// @Success		204
+// @Failure		400	{object}	error
+// @Failure		403	{object}	error
+// @Failure		409	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/hooks/{integration} [put]
+func PutGuildHook(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	name := c.Params("integration")
+
+	var hook Hook
+	if err := c.BodyParser(&hook); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	if hook.Provider == "" {
+		hook.Provider = HookProviderGeneric
+	}
+	if !slices.Contains([]string{HookProviderGitHub, HookProviderStripe, HookProviderGeneric}, hook.Provider) {
+		return c.Status(fiber.StatusBadRequest).SendString("Unknown provider: " + hook.Provider)
+	}
+	if hook.Secret == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("A secret is required")
+	}
+
+	channel, err := cachedChannel(s, hook.ChannelID)
+	if err != nil || channel.GuildID != guildID {
+		return c.Status(fiber.StatusBadRequest).SendString("Channel not found in guild")
+	}
+	if !channelAllowed(c, hook.ChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	if hook.Template == "" {
+		hook.Template = defaultHookTemplate
+	}
+	if hook.tmpl, err = template.New(name).Parse(hook.Template); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid template: " + err.Error())
+	}
+
+	hook.Name = name
+	hook.guildID = guildID
+
+	data, err := json.Marshal(storedHook{Hook: hook, GuildID: guildID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store integration: " + err.Error())
+	}
+
+	hooks.Lock()
+	defer hooks.Unlock()
+	if existing, ok := hooks.byName[name]; ok && existing.guildID != guildID {
+		return c.Status(fiber.StatusConflict).SendString("Integration name is already taken")
+	}
+	if err := hooks.storage.Set(name, data); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store integration: " + err.Error())
+	}
+	hooks.byName[name] = &hook
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteGuildHook removes an inbound webhook integration of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - integration: The name of the integration to remove.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such integration,
+//     or HTTP status 500 (Internal Server Error) if it cannot be removed from the storage.
+// @Summary		Delete Guild Hook
+// @Description	Remove an inbound webhook integration.
+// @Tags			Hooks
+// @Param			integration	path	string	true	"Integration name"
+// @Success		204
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/hooks/{integration} [delete]
+func DeleteGuildHook(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	name := c.Params("integration")
+
+	hooks.Lock()
+	defer hooks.Unlock()
+	if hook, ok := hooks.byName[name]; !ok || hook.guildID != guildID {
+		return c.Status(fiber.StatusNotFound).SendString("Integration not found")
+	}
+	if err := hooks.storage.Delete(name); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to remove integration: " + err.Error())
+	}
+	delete(hooks.byName, name)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
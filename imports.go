@@ -0,0 +1,417 @@
+package disgm
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kinds of import jobs.
+const (
+	JobEmojiImport   = "emoji_import"   // Creates guild emojis from PNG, JPEG, GIF or WebP images.
+	JobStickerImport = "sticker_import" // Creates guild stickers from PNG, APNG, GIF or Lottie JSON files.
+)
+
+// States of the items of an import job.
+const (
+	ImportPending = "pending" // Not imported yet
+	ImportCreated = "created" // Created in the guild
+	ImportSkipped = "skipped" // Not imported, e.g. for lack of a free slot
+	ImportFailed  = "failed"  // Download or creation failed
+)
+
+const (
+	maxImportItems       = 250 // Files of an import, the most emojis of one kind a guild can have.
+	maxEmojiNameLength   = 32  // Longest emoji name Discord accepts.
+	maxStickerNameLength = 30  // Longest sticker name Discord accepts.
+)
+
+// importClient downloads the files of imports with a timeout, so a slow server cannot stall a job.
+// Only publicly routable addresses are reached, see publicDialControl.
+var importClient = newPublicClient(30 * time.Second)
+
+// The emoji and sticker slots of a guild by premium tier. Static and animated emojis have separate
+// slots.
+var (
+	emojiSlots   = [...]int{50, 100, 150, 250}
+	stickerSlots = [...]int{5, 15, 30, 60}
+)
+
+// The file extensions each kind of import accepts.
+var importExtensions = map[string][]string{
+	JobEmojiImport:   {".png", ".jpg", ".jpeg", ".gif", ".webp"},
+	JobStickerImport: {".png", ".apng", ".gif", ".json"},
+}
+
+// ImportItem is a file of an import, downloaded from its URL when the job reaches it.
+type ImportItem struct {
+	URL         string `json:"url"`                   // URL of the image or sticker file
+	Name        string `json:"name,omitempty"`        // Name of the emoji or sticker, defaults to the file name of the URL
+	Tags        string `json:"tags,omitempty"`        // Autocomplete tags of a sticker, default to its name
+	Description string `json:"description,omitempty"` // Description of a sticker, empty or 2-100 characters
+}
+
+// ImportParams are the parameters of an import job from a list of URLs.
+type ImportParams struct {
+	Kind   string       `json:"kind"`              // emoji_import or sticker_import
+	Items  []ImportItem `json:"items"`             // Files to import, up to 250
+	DryRun bool         `json:"dry_run,omitempty"` // Whether to only plan the import and report its ETA
+}
+
+// importFile is a file of an import with its contents, if it was uploaded in a zip.
+type importFile struct {
+	ImportItem
+	source string // URL or path in the zip
+	data   []byte // Contents of an uploaded file, read up to one byte past the size limit; nil for URLs
+}
+
+// importFileSize returns the size limit of the files of an import kind.
+func importFileSize(kind string) int {
+	if kind == JobStickerImport {
+		return maxStickerSize
+	}
+	return maxEmojiSize
+}
+
+// readImportZip returns the files of an uploaded zip archive with an extension of the import kind,
+// skipping folders and the metadata of macOS.
+func readImportZip(r io.ReaderAt, size int64, kind string) ([]importFile, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []importFile
+	for _, f := range archive.File {
+		base := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(base, ".") ||
+			!slices.Contains(importExtensions[kind], strings.ToLower(path.Ext(base))) {
+			continue
+		}
+		if len(files) == maxImportItems {
+			return nil, fmt.Errorf("the archive has more than %d files", maxImportItems)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, int64(importFileSize(kind))+1)) // Zip bombs stop at the limit.
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name, err)
+		}
+		files = append(files, importFile{source: f.Name, data: data})
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("the archive has no %s files", strings.Join(importExtensions[kind], ", "))
+	}
+	return files, nil
+}
+
+// importName turns the name of a file into a valid emoji or sticker name. Emoji names keep
+// letters, digits and underscores.
+func importName(kind, name string) string {
+	maxLength := maxStickerNameLength
+	if kind == JobEmojiImport {
+		maxLength = maxEmojiNameLength
+		name = strings.Map(func(r rune) rune {
+			if r < utf8.RuneSelf && (r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return r
+			}
+			return '_'
+		}, name)
+	}
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxLength {
+		name = string(runes[:maxLength])
+	}
+	for utf8.RuneCountInString(name) < 2 {
+		name += "_"
+	}
+	return name
+}
+
+// uniqueImportName appends the lowest free number to a name the guild or the import already uses,
+// e.g. wave_2, shortening the name to keep it valid.
+func uniqueImportName(kind, name string, taken map[string]bool) string {
+	maxLength := maxStickerNameLength
+	if kind == JobEmojiImport {
+		maxLength = maxEmojiNameLength
+	}
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		suffix := "_" + strconv.Itoa(i)
+		runes := []rune(name)
+		candidate = string(runes[:min(len(runes), maxLength-len(suffix))]) + suffix
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// planImport names the files of an import and returns the calls creating them, and the results of
+// the files, which the calls update. Files are skipped if their type is not supported, they are too
+// large, or the guild has no free slot left for them.
+func planImport(s *discordgo.Session, guildID, kind string, files []importFile) ([]jobCall, []JobResult, error) {
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		if guild, err = s.Guild(guildID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tier := min(max(int(guild.PremiumTier), 0), len(emojiSlots)-1)
+	taken := make(map[string]bool)
+	var freeStatic, freeAnimated, freeStickers int
+	if kind == JobEmojiImport {
+		freeStatic, freeAnimated = emojiSlots[tier], emojiSlots[tier]
+		for _, emoji := range guild.Emojis {
+			taken[emoji.Name] = true
+			if emoji.Animated {
+				freeAnimated--
+			} else {
+				freeStatic--
+			}
+		}
+	} else {
+		freeStickers = stickerSlots[tier] - len(guild.Stickers)
+		for _, sticker := range guild.Stickers {
+			taken[sticker.Name] = true
+		}
+	}
+
+	var calls []jobCall
+	results := make([]JobResult, len(files))
+	route := bucketRoute(http.MethodPost, discordgo.EndpointGuildEmojis(guildID))
+	if kind == JobStickerImport {
+		route = bucketRoute(http.MethodPost, discordgo.EndpointGuildStickers(guildID))
+	}
+	for i, f := range files {
+		i, f := i, f
+		filename := path.Base(f.source)
+		if f.data == nil {
+			u, _ := url.Parse(f.URL)
+			filename = path.Base(u.Path)
+		}
+		ext := strings.ToLower(path.Ext(filename))
+		if f.Name == "" {
+			f.Name = strings.TrimSuffix(filename, path.Ext(filename))
+		}
+		f.Name = uniqueImportName(kind, importName(kind, f.Name), taken)
+		results[i] = JobResult{Source: f.source, Name: f.Name, Status: ImportPending}
+
+		free := &freeStickers
+		if kind == JobEmojiImport {
+			free = &freeStatic
+			if ext == ".gif" {
+				free = &freeAnimated // Only GIFs are planned as animated; animated WebP images take a static slot.
+			}
+		}
+		switch {
+		case ext != "" && !slices.Contains(importExtensions[kind], ext):
+			results[i].Status, results[i].Error = ImportSkipped, "unsupported file type "+ext
+		case len(f.data) > importFileSize(kind):
+			results[i].Status, results[i].Error = ImportSkipped, errImageTooLarge.Error()
+		case *free <= 0:
+			results[i].Status, results[i].Error = ImportSkipped, "no free slot left in the guild"
+		}
+		if results[i].Status == ImportSkipped {
+			delete(taken, f.Name)
+			continue
+		}
+		*free--
+
+		calls = append(calls, jobCall{
+			route: route,
+			items: []string{f.source},
+			do: func(options ...discordgo.RequestOption) error {
+				id, err := importFileTo(s, guildID, kind, f, ext, options)
+				jobs.Lock()
+				defer jobs.Unlock()
+				if err != nil {
+					results[i].Status, results[i].Error = ImportFailed, err.Error()
+				} else {
+					results[i].Status, results[i].ID, results[i].Error = ImportCreated, id, ""
+				}
+				return err
+			},
+		})
+	}
+	return calls, results, nil
+}
+
+// fetchImportFile downloads a file of an import, up to the size limit.
+func fetchImportFile(rawURL string, maxSize int) ([]byte, error) {
+	resp, err := importClient.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("download failed: " + resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSize {
+		return nil, errImageTooLarge
+	}
+	return data, nil
+}
+
+// importFileTo creates the emoji or sticker of a file, downloading it first if it has a URL, and
+// returns its ID.
+func importFileTo(s *discordgo.Session, guildID, kind string, f importFile, ext string, options []discordgo.RequestOption) (string, error) {
+	data := f.data
+	if data == nil {
+		var err error
+		if data, err = fetchImportFile(f.URL, importFileSize(kind)); err != nil {
+			return "", err
+		}
+	}
+	contentType := http.DetectContentType(data)
+
+	if kind == JobEmojiImport {
+		emoji, err := s.GuildEmojiCreate(guildID, &discordgo.EmojiParams{
+			Name:  f.Name,
+			Image: "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data),
+		}, options...)
+		if err != nil {
+			return "", err
+		}
+		return emoji.ID, nil
+	}
+
+	if ext == ".json" {
+		contentType = fiber.MIMEApplicationJSON // Lottie stickers are JSON, which is detected as text.
+	}
+	tags := f.Tags
+	if tags == "" {
+		tags = f.Name
+	}
+	fields := map[string]string{"name": f.Name, "description": f.Description, "tags": tags}
+	sticker, err := createSticker(s, guildID, fields, f.Name+ext, contentType, bytes.NewReader(data), options...)
+	if err != nil {
+		return "", err
+	}
+	return sticker.ID, nil
+}
+
+// CreateGuildImport starts a job importing a zip archive or a list of URLs as guild emojis or
+// stickers.
+//
+// The files are named after their file name, or the name given with their URL, made valid for
+// Discord and numbered where the guild or the import already uses the name, e.g. wave_2. Files of
+// an unsupported type, files larger than Discord accepts, and files the guild has no free slot
+// for, counted by its boost tier, are skipped. The job runs like the other bulk jobs, paced by
+// the rate limits and listed with them, and reports the outcome and ID of every file in its
+// results. URLs are downloaded when the job reaches them. With dry_run, the import is only planned.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - An ImportParams object with the URLs, or a multipart form with the fields:
+//   - file: A zip archive of the files, up to 250.
+//   - kind: emoji_import or sticker_import.
+//   - dry_run: Optional, true to only plan the import.
+//
+// Returns:
+//   - On success, it returns the job as JSON with HTTP status 202 (Accepted), or HTTP status 200
+//     with the planned job for a dry run.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body or archive is invalid,
+//     HTTP status 409 (Conflict) if another job of the guild is running,
+//     or HTTP status 500 (Internal Server Error) if the job cannot be created.
+//
+// @Summary		Import Emojis or Stickers
+// @Description	Start a job importing a zip archive or a list of URLs as guild emojis or stickers, with per-file results.
+// @Tags			Jobs
+// @Accept			json
+// @Accept			multipart/form-data
+// @Param			body	body		ImportParams	false	"URLs to import"
+// @Param			file	formData	file			false	"Zip archive of the files"
+// @Param			kind	formData	string			false	"emoji_import or sticker_import, with file"
+// @Param			dry_run	formData	bool			false	"Whether to only plan the import, with file"
+// @Success		200		{object}	Job
+// @Success		202		{object}	Job
+// @Failure		400		{object}	error
+// @Failure		409		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/jobs/import [post]
+func CreateGuildImport(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	user, _ := c.Locals("ActingUser").(string)
+
+	var params ImportParams
+	var files []importFile
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		params.Kind, params.DryRun = c.FormValue("kind"), c.FormValue("dry_run") == "true"
+		if importExtensions[params.Kind] == nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: kind must be emoji_import or sticker_import")
+		}
+		header, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+		}
+		file, err := header.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+		}
+		defer file.Close()
+		if files, err = readImportZip(file, header.Size, params.Kind); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid archive: " + err.Error())
+		}
+	} else {
+		if err := c.BodyParser(&params); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+		}
+		if importExtensions[params.Kind] == nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: kind must be emoji_import or sticker_import")
+		}
+		if len(params.Items) == 0 || len(params.Items) > maxImportItems {
+			return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("Invalid request body: items must have 1-%d files", maxImportItems))
+		}
+		for _, item := range params.Items {
+			u, err := url.Parse(item.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("Invalid request body: %q is not an absolute http or https URL", item.URL))
+			}
+			files = append(files, importFile{ImportItem: item, source: item.URL})
+		}
+	}
+
+	calls, results, err := planImport(s, guildID, params.Kind, files)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create job: " + err.Error())
+	}
+
+	j, err := launchJob(guildID, &Job{Kind: params.Kind, Items: len(files), Results: results, RequestedBy: user}, calls, params.DryRun, auditOptions(c))
+	switch {
+	case errors.Is(err, errJobRunning):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create job: " + err.Error())
+	case params.DryRun:
+		return c.JSON(j)
+	}
+
+	jobs.Lock()
+	defer jobs.Unlock()
+	return c.Status(fiber.StatusAccepted).JSON(*j)
+}
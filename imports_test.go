@@ -0,0 +1,28 @@
+package disgm
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestFetchImportFileInternalAddress checks that import files are not downloaded from the host or
+// its network.
+func TestFetchImportFileInternalAddress(t *testing.T) {
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer local.Close()
+
+	for _, url := range []string{
+		local.URL + "/emoji.png",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]:1/emoji.png",
+		"http://10.0.0.1/emoji.png",
+	} {
+		if _, err := fetchImportFile(url, maxEmojiSize); !errors.Is(err, errInternalAddress) {
+			t.Errorf("fetchImportFile(%q): error %v, want %v", url, err, errInternalAddress)
+		}
+	}
+}
@@ -1,10 +1,25 @@
 package disgm
 
 import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+const (
+	maxAutocompleteChoices = 25  // Choices Discord shows in reply to an autocomplete interaction.
+	maxChoiceLength        = 100 // Length of the name and string value of a choice.
 )
 
+// AutocompleteParams are the choices answering an autocomplete interaction.
+type AutocompleteParams struct {
+	Choices []*models.ApplicationCommandOptionChoice `json:"choices"` // Up to 25 choices, an empty list shows none
+}
+
 // CreateInteractionCallback handles the creation of a response to a Discord interaction.
 //
 // This function receives an interaction ID and interaction token from the request parameters,
@@ -21,25 +36,50 @@ import (
 //
 // Request Body:
 //   - The body should contain a valid `discordgo.InteractionResponse` object in JSON format.
+//     Modals (type 9) take a ModalParams object as data and are checked against the limits of
+//     Discord before they are sent.
 //
 // Returns:
 //   - On success, it returns HTTP status 204 (No Content).
-//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body is invalid,
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body or modal is invalid,
 //     or HTTP status 500 (Internal Server Error) if there is a problem sending the response.
 // @Summary		Create Interaction Callback
-// @Description	Handle interaction callback for a specific interaction.
+// @Description	Handle interaction callback for a specific interaction. Modals (type 9) are validated before they are sent.
 // @Tags			Interactions
 // @Param			interactionid		path	string	true	"Interaction ID"
 // @Param			interactiontoken	path	string	true	"Interaction Token"
 // @Success		204
+// @Failure		400	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/interactions/{interactionid}/{interactiontoken}/callback [post]
 func CreateInteractionCallback(c *fiber.Ctx, s *discordgo.Session) error {
 	interactionID := c.Params("interactionid")
 	interactionToken := c.Params("interactiontoken")
 
+	var kind struct {
+		Type discordgo.InteractionResponseType `json:"type"`
+	}
+	if err := c.BodyParser(&kind); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
 	var resp *discordgo.InteractionResponse
-	if err := c.BodyParser(&resp); err != nil {
+	if kind.Type == discordgo.InteractionResponseModal {
+		// discordgo cannot decode components, so modals are decoded into their own types.
+		var modal struct {
+			Data *ModalParams `json:"data"`
+		}
+		if err := c.BodyParser(&modal); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+		}
+		if modal.Data == nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid modal: data is required")
+		}
+		if err := modal.Data.validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid modal: " + err.Error())
+		}
+		resp = modal.Data.response()
+	} else if err := c.BodyParser(&resp); err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
 
@@ -51,6 +91,88 @@ func CreateInteractionCallback(c *fiber.Ctx, s *discordgo.Session) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// CreateAutocompleteResponse answers an autocomplete interaction with a list of choices.
+//
+// The choices are checked against the limits of Discord before the callback is sent, so clients
+// get a precise error instead of a rejected callback, and need not build the callback payload.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - interactionid: The ID of the interaction.
+//   - interactiontoken: The token of the interaction.
+//
+// Request Body:
+//   - An AutocompleteParams object with up to 25 choices, each with a name of 1-100 characters
+//     and a string of up to 100 characters or a number as value.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body or a choice is invalid,
+//     or HTTP status 500 (Internal Server Error) if there is a problem sending the response.
+// @Summary		Create Autocomplete Response
+// @Description	Answer an autocomplete interaction with up to 25 choices.
+// @Tags			Interactions
+// @Param			interactionid		path	string				true	"Interaction ID"
+// @Param			interactiontoken	path	string				true	"Interaction Token"
+// @Param			body				body	AutocompleteParams	true	"Choices"
+// @Success		204
+// @Failure		400	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/interactions/{interactionid}/{interactiontoken}/autocomplete [post]
+func CreateAutocompleteResponse(c *fiber.Ctx, s *discordgo.Session) error {
+	interactionID := c.Params("interactionid")
+	interactionToken := c.Params("interactiontoken")
+
+	var params AutocompleteParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if len(params.Choices) > maxAutocompleteChoices {
+		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("Invalid request body: %d choices exceed the limit of %d", len(params.Choices), maxAutocompleteChoices))
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(params.Choices))
+	for i, choice := range params.Choices {
+		if err := validateChoice(choice); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("Invalid request body: choice %d %v", i, err))
+		}
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{Name: choice.Name, Value: choice.Value}
+	}
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	}
+	if err := NewInteractionRespond(s, interactionID, interactionToken, resp); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to send autocomplete response: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// validateChoice checks a choice of an autocomplete response against the limits of Discord.
+func validateChoice(choice *models.ApplicationCommandOptionChoice) error {
+	if choice == nil {
+		return errors.New("is null")
+	}
+	if n := utf8.RuneCountInString(choice.Name); n < 1 || n > maxChoiceLength {
+		return fmt.Errorf("name must have 1-%d characters", maxChoiceLength)
+	}
+	switch value := choice.Value.(type) {
+	case string:
+		if utf8.RuneCountInString(value) > maxChoiceLength {
+			return fmt.Errorf("value must have at most %d characters", maxChoiceLength)
+		}
+	case float64:
+	default:
+		return errors.New("value must be a string or a number")
+	}
+	return nil
+}
+
 // NewInteractionRespond sends a response to a Discord interaction.
 //
 // This function sends an interaction response to the Discord API using the interaction ID and token.
@@ -82,3 +204,148 @@ func NewInteractionRespond(s *discordgo.Session, id string, token string, resp *
 	_, err := s.RequestWithBucketID("POST", endpoint, *resp, endpoint, options...)
 	return err
 }
+
+// CreateFollowupMessage sends a followup message for a Discord interaction.
+//
+// Followups can be sent for 15 minutes after the interaction, once the initial callback was sent,
+// so web-driven interaction flows can keep answering after the first response.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - apptoken: The token of the interaction.
+//
+// Request Body:
+//   - The body should contain a valid `discordgo.WebhookParams` object in JSON format.
+//
+// Returns:
+//   - On success, it returns the sent message as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body is invalid,
+//     or HTTP status 500 (Internal Server Error) if the message cannot be sent.
+// @Summary		Create Followup Message
+// @Description	Send a followup message for an interaction after its initial callback.
+// @Tags			Interactions
+// @Param			apptoken	path		string					true	"Interaction Token"
+// @Success		201			{object}	models.Message
+// @Failure		400			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/interactions/{apptoken}/followups [post]
+func CreateFollowupMessage(c *fiber.Ctx, s *discordgo.Session) error {
+	interactionToken := c.Params("apptoken")
+
+	var params discordgo.WebhookParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	user, _ := s.User("@me") // Retrieves the bot's application user
+	msg, err := s.FollowupMessageCreate(&discordgo.Interaction{AppID: user.ID, Token: interactionToken}, true, &params)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to send followup message: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(msg)
+}
+
+// UpdateFollowupMessage edits a followup message of a Discord interaction.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - apptoken: The token of the interaction.
+//   - messageid: The ID of the followup message, or @original for the initial response.
+//
+// Request Body:
+//   - The body should contain a valid `discordgo.WebhookEdit` object in JSON format.
+//
+// Returns:
+//   - On success, it returns the updated message as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token's scope,
+//     HTTP status 404 (Not Found) if the message does not exist,
+//     or HTTP status 500 (Internal Server Error) if the message cannot be edited.
+// @Summary		Update Followup Message
+// @Description	Edit a followup message of an interaction.
+// @Tags			Interactions
+// @Param			apptoken	path		string					true	"Interaction Token"
+// @Param			messageid	path		string					true	"Message ID"
+// @Success		200			{object}	models.Message
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/interactions/{apptoken}/followups/{messageid} [patch]
+func UpdateFollowupMessage(c *fiber.Ctx, s *discordgo.Session) error {
+	interactionToken := c.Params("apptoken")
+	messageID := c.Params("messageid")
+
+	var params discordgo.WebhookEdit
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	user, _ := s.User("@me") // Retrieves the bot's application user
+	existing, err := s.WebhookMessage(user.ID, interactionToken, messageID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Followup message not found")
+	}
+	if !channelAllowed(c, existing.ChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	msg, err := s.FollowupMessageEdit(&discordgo.Interaction{AppID: user.ID, Token: interactionToken}, messageID, &params)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to edit followup message: " + err.Error())
+	}
+
+	return c.JSON(msg)
+}
+
+// DeleteFollowupMessage deletes a followup message of a Discord interaction.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - apptoken: The token of the interaction.
+//   - messageid: The ID of the followup message, or @original for the initial response.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is outside the token's scope,
+//     HTTP status 404 (Not Found) if the message does not exist,
+//     or HTTP status 500 (Internal Server Error) if the message cannot be deleted.
+// @Summary		Delete Followup Message
+// @Description	Delete a followup message of an interaction.
+// @Tags			Interactions
+// @Param			apptoken	path	string	true	"Interaction Token"
+// @Param			messageid	path	string	true	"Message ID"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/interactions/{apptoken}/followups/{messageid} [delete]
+func DeleteFollowupMessage(c *fiber.Ctx, s *discordgo.Session) error {
+	interactionToken := c.Params("apptoken")
+	messageID := c.Params("messageid")
+
+	user, _ := s.User("@me") // Retrieves the bot's application user
+	existing, err := s.WebhookMessage(user.ID, interactionToken, messageID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Followup message not found")
+	}
+	if !channelAllowed(c, existing.ChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	if err := s.FollowupMessageDelete(&discordgo.Interaction{AppID: user.ID, Token: interactionToken}, messageID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete followup message: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,133 @@
+package disgm
+
+import (
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+type Invite = models.Invite
+
+// GetGuildInvites retrieves the invites of all channels of the guild.
+//
+// Invites to channels outside the token scope are hidden.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the invites as a JSON array with HTTP status 200.
+//   - On failure, it returns an HTTP status 500 and an error message if the invites cannot be retrieved.
+// @Summary		Get Guild Invites
+// @Description	Retrieve the invites of all channels of the guild.
+// @Tags			Invites
+// @Success		200	{array}		Invite
+// @Failure		500	{object}	error
+// @Router			/api/guild/invites [get]
+func GetGuildInvites(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	invites, err := s.GuildInvites(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve invites: " + err.Error())
+	}
+
+	// Hides the invites to channels outside the token scope.
+	invites = slices.DeleteFunc(invites, func(inv *discordgo.Invite) bool {
+		return inv.Channel == nil || !channelAllowed(c, inv.Channel.ID, false)
+	})
+
+	return c.JSON(invites)
+}
+
+// GetInvite retrieves an invite of the guild by its code.
+//
+// Only invites to the guild of the token are found, and for restricted tokens only those to
+// channels in its scope. Discord returns the inviter and expiry of an invite by its code, but not
+// its uses, which are listed with the guild invites.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - code: The code of the invite.
+//   - with_counts: Optional, true to include the approximate member and online counts of the guild.
+//
+// Returns:
+//   - On success, it returns the invite as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is not allowed for the token,
+//     or HTTP status 404 (Not Found) if the guild has no such invite.
+// @Summary		Get Invite
+// @Description	Retrieve an invite of the guild by its code, optionally with the member counts of the guild.
+// @Tags			Invites
+// @Param			code		path		string	true	"Invite code"
+// @Param			with_counts	query		bool	false	"Whether to include the approximate member counts"
+// @Success		200			{object}	Invite
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Router			/api/invites/{code} [get]
+func GetInvite(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	code := c.Params("code")
+
+	invite, err := s.InviteComplex(code, "", c.QueryBool("with_counts"), true)
+	if err != nil || invite.Guild == nil || invite.Guild.ID != guildID || invite.Channel == nil {
+		return c.Status(fiber.StatusNotFound).SendString("Invite not found in this guild")
+	}
+	if !channelAllowed(c, invite.Channel.ID, false) {
+		return channelForbidden(c)
+	}
+
+	return c.JSON(invite)
+}
+
+// DeleteGuildInvite revokes an invite of the guild.
+//
+// The invite is looked up first, so only invites to the guild of the token can be revoked, and
+// for restricted tokens only those to channels with write access.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - code: The code of the invite.
+//
+// Returns:
+//   - On success, it returns the revoked invite as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is not allowed for the token,
+//     HTTP status 404 (Not Found) if the guild has no such invite,
+//     or HTTP status 500 and an error message if the invite cannot be revoked.
+// @Summary		Delete Guild Invite
+// @Description	Revoke an invite of the guild by its code.
+// @Tags			Invites
+// @Param			code	path		string	true	"Invite code"
+// @Success		200		{object}	Invite
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/invites/{code} [delete]
+// @Router			/api/invites/{code} [delete]
+func DeleteGuildInvite(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	code := c.Params("code")
+
+	invite, err := s.Invite(code)
+	if err != nil || invite.Guild == nil || invite.Guild.ID != guildID || invite.Channel == nil {
+		return c.Status(fiber.StatusNotFound).SendString("Invite not found in this guild")
+	}
+	if !channelAllowed(c, invite.Channel.ID, true) {
+		return channelForbidden(c)
+	}
+
+	revoked, err := s.InviteDelete(code, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete invite: " + err.Error())
+	}
+
+	return c.JSON(revoked)
+}
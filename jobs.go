@@ -0,0 +1,558 @@
+package disgm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kinds of bulk jobs.
+const (
+	JobBulkBan    = "bulk_ban"    // Bans the users of user_ids.
+	JobRoleAssign = "role_assign" // Adds the role of role_id to the members of user_ids.
+	JobPurge      = "purge"       // Deletes the messages of message_ids in channel_id, in bulk where Discord allows it.
+)
+
+// States of a job.
+const (
+	JobPlanned   = "planned"   // Planned by a dry run, never runs
+	JobRunning   = "running"   // Calling Discord
+	JobDone      = "done"      // All calls made, some may have failed
+	JobCancelled = "cancelled" // Cancelled before all calls were made
+)
+
+const (
+	maxJobItems    = 10000            // Users or messages of a job.
+	maxJobErrors   = 50               // Failed items whose errors are kept in a job.
+	maxJobRetries  = 5                // Retries of a call that was rate limited or failed with a server error.
+	maxJobBackoff  = 30 * time.Second // Longest wait between the retries of a call.
+	maxBulkDelete  = 100              // Messages Discord deletes in one bulk delete call.
+	jobRetention   = time.Hour        // Time finished jobs are kept.
+	purgeAgeMargin = time.Hour        // Margin to the age limit of bulk deletes, for jobs waiting on their bucket.
+)
+
+// JobParams are the parameters of a bulk job.
+type JobParams struct {
+	Kind       string   `json:"kind"`                  // One of bulk_ban, role_assign and purge
+	UserIDs    []string `json:"user_ids,omitempty"`    // Users of bulk_ban and role_assign
+	RoleID     string   `json:"role_id,omitempty"`     // Role of role_assign
+	ChannelID  string   `json:"channel_id,omitempty"`  // Channel of purge
+	MessageIDs []string `json:"message_ids,omitempty"` // Messages of purge
+	DryRun     bool     `json:"dry_run,omitempty"`     // Whether to only plan the job and report its ETA
+}
+
+// PlannedRoute is the share of a job of one rate-limited route of the Discord API.
+type PlannedRoute struct {
+	Route            string  `json:"route"`             // Method and route, e.g. PUT /guilds/1234/bans/:id
+	Calls            int     `json:"calls"`             // Calls of the job to the route
+	Limit            int     `json:"limit"`             // Requests per window of the route
+	WindowSeconds    float64 `json:"window_seconds"`    // Length of a window of the route
+	Observed         bool    `json:"observed"`          // Whether Discord reported the limit, else it is assumed
+	EstimatedSeconds float64 `json:"estimated_seconds"` // Time the calls take when paced by the limit
+}
+
+// JobPlan schedules the calls of a job against the rate limits of their routes.
+type JobPlan struct {
+	Calls            int            `json:"calls"`             // Calls to Discord
+	Routes           []PlannedRoute `json:"routes"`            // Calls per rate-limited route
+	EstimatedSeconds float64        `json:"estimated_seconds"` // Time all calls take
+}
+
+// JobError is an item of a job that failed.
+type JobError struct {
+	Item  string `json:"item"` // User or message ID
+	Error string `json:"error"`
+}
+
+// JobResult is the outcome of an item of an import job.
+type JobResult struct {
+	Source string `json:"source"`          // URL or file name the item was imported from
+	Name   string `json:"name"`            // Name of the emoji or sticker, de-duplicated against the guild
+	Status string `json:"status"`          // One of pending, created, skipped and failed
+	ID     string `json:"id,omitempty"`    // ID of the created emoji or sticker
+	Error  string `json:"error,omitempty"` // Why the item was skipped or failed
+}
+
+// Job is a bulk operation on a guild, running in the background at the pace of the rate limits of
+// Discord.
+type Job struct {
+	ID               string      `json:"id"`
+	Kind             string      `json:"kind"`
+	Status           string      `json:"status"`                // One of planned, running, done and cancelled
+	Items            int         `json:"items"`                 // Users, messages or imported files of the job
+	Calls            int         `json:"calls"`                 // Calls to Discord, fewer than items for bulk deletes
+	Done             int         `json:"done"`                  // Calls made
+	Failed           int         `json:"failed"`                // Items whose call failed
+	Retries          int         `json:"retries"`               // Calls retried after a rate limit or server error
+	Errors           []JobError  `json:"errors"`                // The first failed items
+	Results          []JobResult `json:"results,omitempty"`     // Outcome of every item, for imports
+	Plan             JobPlan     `json:"plan"`                  // Plan made when the job was created
+	EstimatedSeconds float64     `json:"estimated_seconds"`     // Time the calls left take
+	ETA              time.Time   `json:"eta"`                   // When the job is expected to finish
+	RequestedBy      string      `json:"requested_by"`          // Acting user that created the job
+	CreatedAt        time.Time   `json:"created_at"`            // When the job was created
+	FinishedAt       *time.Time  `json:"finished_at,omitempty"` // When the job finished or was cancelled
+
+	cancel context.CancelFunc
+}
+
+// jobCall is a call of a job to Discord, on one or more items.
+type jobCall struct {
+	route string
+	items []string
+	do    func(options ...discordgo.RequestOption) error
+}
+
+// The bulk jobs, keyed by guild ID. A guild runs one job at a time, as the jobs would share the
+// rate limits their plans are based on.
+var jobs = struct {
+	sync.Mutex
+	byGuild map[string][]*Job
+}{byGuild: make(map[string][]*Job)}
+
+// uniqueIDs returns IDs sorted and without duplicates.
+func uniqueIDs(ids []string) []string {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// planJobCalls validates the parameters of a job and returns its calls.
+func planJobCalls(s *discordgo.Session, guildID string, params JobParams) ([]jobCall, int, error) {
+	var calls []jobCall
+	switch params.Kind {
+	case JobBulkBan, JobRoleAssign:
+		if len(params.UserIDs) == 0 || len(params.UserIDs) > maxJobItems {
+			return nil, 0, fmt.Errorf("user_ids must have 1-%d users", maxJobItems)
+		}
+		if params.Kind == JobRoleAssign && params.RoleID == "" {
+			return nil, 0, errors.New("role_id is required")
+		}
+		for _, userID := range uniqueIDs(params.UserIDs) {
+			userID := userID
+			call := jobCall{items: []string{userID}}
+			if params.Kind == JobBulkBan {
+				call.route = bucketRoute(http.MethodPut, discordgo.EndpointGuildBan(guildID, userID))
+				call.do = func(options ...discordgo.RequestOption) error {
+					return s.GuildBanCreate(guildID, userID, 0, options...)
+				}
+			} else {
+				call.route = bucketRoute(http.MethodPut, discordgo.EndpointGuildMemberRole(guildID, userID, params.RoleID))
+				call.do = func(options ...discordgo.RequestOption) error {
+					return s.GuildMemberRoleAdd(guildID, userID, params.RoleID, options...)
+				}
+			}
+			calls = append(calls, call)
+		}
+		return calls, len(calls), nil
+
+	case JobPurge:
+		if params.ChannelID == "" {
+			return nil, 0, errors.New("channel_id is required")
+		}
+		if len(params.MessageIDs) == 0 || len(params.MessageIDs) > maxJobItems {
+			return nil, 0, fmt.Errorf("message_ids must have 1-%d messages", maxJobItems)
+		}
+		// Discord only bulk deletes messages younger than two weeks; older ones are deleted one by one.
+		messageIDs := uniqueIDs(params.MessageIDs)
+		var recent, old []string
+		for _, id := range messageIDs {
+			created, err := discordgo.SnowflakeTimestamp(id)
+			if err != nil {
+				return nil, 0, fmt.Errorf("message_ids: %q is not a message ID", id)
+			}
+			if time.Since(created) < maxPurgeAge-purgeAgeMargin {
+				recent = append(recent, id)
+			} else {
+				old = append(old, id)
+			}
+		}
+		channelID := params.ChannelID
+		for start := 0; start < len(recent); start += maxBulkDelete {
+			chunk := recent[start:min(start+maxBulkDelete, len(recent))]
+			if len(chunk) == 1 {
+				old = append(old, chunk[0]) // Bulk deletes need at least two messages.
+				continue
+			}
+			calls = append(calls, jobCall{
+				route: bucketRoute(http.MethodPost, discordgo.EndpointChannelMessagesBulkDelete(channelID)),
+				items: chunk,
+				do: func(options ...discordgo.RequestOption) error {
+					return s.ChannelMessagesBulkDelete(channelID, chunk, options...)
+				},
+			})
+		}
+		for _, id := range old {
+			id := id
+			calls = append(calls, jobCall{
+				route: bucketRoute(http.MethodDelete, discordgo.EndpointChannelMessage(channelID, id)),
+				items: []string{id},
+				do: func(options ...discordgo.RequestOption) error {
+					return s.ChannelMessageDelete(channelID, id, options...)
+				},
+			})
+		}
+		return calls, len(messageIDs), nil
+	}
+	return nil, 0, fmt.Errorf("unknown kind %q, use one of %s, %s and %s", params.Kind, JobBulkBan, JobRoleAssign, JobPurge)
+}
+
+// planJob schedules calls against the rate limits of their routes, in the order they are made.
+func planJob(calls []jobCall) JobPlan {
+	plan := JobPlan{Calls: len(calls), Routes: []PlannedRoute{}}
+	for _, call := range calls {
+		i := slices.IndexFunc(plan.Routes, func(r PlannedRoute) bool { return r.Route == call.route })
+		if i < 0 {
+			state, observed := bucket(call.route)
+			plan.Routes = append(plan.Routes, PlannedRoute{
+				Route:         call.route,
+				Limit:         state.limit,
+				WindowSeconds: state.window.Seconds(),
+				Observed:      observed,
+			})
+			i = len(plan.Routes) - 1
+		}
+		plan.Routes[i].Calls++
+	}
+	for i, r := range plan.Routes {
+		estimate := estimateCalls(r.Route, r.Calls).Seconds()
+		plan.Routes[i].EstimatedSeconds = math.Round(estimate*10) / 10
+		plan.EstimatedSeconds += estimate
+	}
+	plan.EstimatedSeconds = math.Round(plan.EstimatedSeconds*10) / 10
+	return plan
+}
+
+// retryable reports whether a failed call is worth retrying: it was rate limited, e.g. by a
+// limit shared with other clients of the bot, or Discord failed with a server error.
+func retryable(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Response != nil &&
+		(restErr.Response.StatusCode == http.StatusTooManyRequests || restErr.Response.StatusCode >= 500)
+}
+
+// sleepContext waits for a duration, returning false if the context is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// runJob makes the calls of a job, waiting for their bucket instead of running into rate limits,
+// and retrying rate-limited calls with exponential backoff.
+func runJob(ctx context.Context, j *Job, calls []jobCall, options []discordgo.RequestOption) {
+	for i, call := range calls {
+		if !sleepContext(ctx, bucketWait(call.route)) {
+			break
+		}
+
+		err := call.do(options...)
+		for attempt := 0; err != nil && retryable(err) && attempt < maxJobRetries; attempt++ {
+			jobs.Lock()
+			j.Retries++
+			jobs.Unlock()
+			if !sleepContext(ctx, min(time.Second<<attempt, maxJobBackoff)) {
+				break
+			}
+			err = call.do(options...)
+		}
+
+		remaining := planJob(calls[i+1:])
+		jobs.Lock()
+		j.Done++
+		if err != nil {
+			j.Failed += len(call.items)
+			for _, item := range call.items {
+				if len(j.Errors) < maxJobErrors {
+					j.Errors = append(j.Errors, JobError{Item: item, Error: err.Error()})
+				}
+			}
+		}
+		j.EstimatedSeconds = remaining.EstimatedSeconds
+		j.ETA = time.Now().Add(time.Duration(remaining.EstimatedSeconds * float64(time.Second)))
+		jobs.Unlock()
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	now := time.Now()
+	jobs.Lock()
+	j.Status = JobDone
+	if j.Done < j.Calls {
+		j.Status = JobCancelled
+	}
+	j.FinishedAt = &now
+	j.cancel()
+	jobs.Unlock()
+}
+
+// startJob plans a job and runs it in the background, unless it is a dry run or another job of
+// the guild is running.
+func startJob(s *discordgo.Session, guildID, user string, params JobParams, options []discordgo.RequestOption) (*Job, error) {
+	calls, items, err := planJobCalls(s, guildID, params)
+	if err != nil {
+		return nil, err
+	}
+	return launchJob(guildID, &Job{Kind: params.Kind, Items: items, RequestedBy: user}, calls, params.DryRun, options)
+}
+
+// launchJob plans the calls of a job, whose kind, items and requester are set, and runs it in the
+// background, unless it is a dry run or another job of the guild is running.
+func launchJob(guildID string, j *Job, calls []jobCall, dryRun bool, options []discordgo.RequestOption) (*Job, error) {
+	id, err := randomToken(8)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := planJob(calls)
+	now := time.Now()
+	j.ID = id
+	j.Status = JobRunning
+	j.Calls = len(calls)
+	j.Errors = []JobError{}
+	j.Plan = plan
+	j.EstimatedSeconds = plan.EstimatedSeconds
+	j.ETA = now.Add(time.Duration(plan.EstimatedSeconds * float64(time.Second)))
+	j.CreatedAt = now
+	if dryRun {
+		j.Status = JobPlanned
+		return j, nil
+	}
+
+	jobs.Lock()
+	defer jobs.Unlock()
+	guildJobs := jobs.byGuild[guildID]
+	guildJobs = slices.DeleteFunc(guildJobs, func(j *Job) bool {
+		return j.FinishedAt != nil && time.Since(*j.FinishedAt) > jobRetention
+	})
+	if slices.ContainsFunc(guildJobs, func(j *Job) bool { return j.Status == JobRunning }) {
+		jobs.byGuild[guildID] = guildJobs
+		return nil, errJobRunning
+	}
+	var ctx context.Context
+	ctx, j.cancel = context.WithCancel(context.Background())
+	jobs.byGuild[guildID] = append(guildJobs, j)
+	go runJob(ctx, j, calls, options)
+	return j, nil
+}
+
+// errJobRunning is returned for a job created while another job of the guild is running.
+var errJobRunning = errors.New("another job of the guild is running; wait for it or cancel it")
+
+// guildJob returns a job of the guild of a request by the jobid parameter. The caller must hold
+// the jobs lock.
+func guildJob(c *fiber.Ctx) (*Job, bool) {
+	guildID := c.Locals("ID").(string)
+	i := slices.IndexFunc(jobs.byGuild[guildID], func(j *Job) bool { return j.ID == c.Params("jobid") })
+	if i < 0 {
+		return nil, false
+	}
+	return jobs.byGuild[guildID][i], true
+}
+
+// GetGuildJobs retrieves the bulk jobs of the guild, running and finished in the last hour.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the jobs as a JSON array, oldest first.
+//
+// @Summary		Get Guild Jobs
+// @Description	Retrieve the bulk jobs of the guild with their progress and ETA.
+// @Tags			Jobs
+// @Success		200	{array}	Job
+// @Router			/api/guild/jobs [get]
+func GetGuildJobs(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	jobs.Lock()
+	defer jobs.Unlock()
+	guildJobs := make([]Job, 0, len(jobs.byGuild[guildID]))
+	for _, j := range jobs.byGuild[guildID] {
+		if j.FinishedAt == nil || time.Since(*j.FinishedAt) <= jobRetention {
+			guildJobs = append(guildJobs, *j)
+		}
+	}
+
+	return c.JSON(guildJobs)
+}
+
+// GetGuildJob retrieves a bulk job of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - jobid: The ID of the job.
+//
+// Returns:
+//   - On success, it returns the job as JSON with its progress and the ETA of the calls left.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the job does not exist.
+//
+// @Summary		Get Guild Job
+// @Description	Retrieve a bulk job of the guild with its progress and ETA.
+// @Tags			Jobs
+// @Param			jobid	path		string	true	"Job ID"
+// @Success		200		{object}	Job
+// @Failure		404		{object}	error
+// @Router			/api/guild/jobs/{jobid} [get]
+func GetGuildJob(c *fiber.Ctx, s *discordgo.Session) error {
+	jobs.Lock()
+	defer jobs.Unlock()
+	j, ok := guildJob(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Job not found")
+	}
+
+	return c.JSON(*j)
+}
+
+// CreateGuildJob starts a bulk job: banning users, assigning a role to members, or purging
+// messages.
+//
+// The calls of the job are planned against the rate limits Discord reported for their routes, or
+// assumed limits for routes not called yet, and the response reports the plan and ETA up front.
+// The job then runs in the background, waiting for the buckets of its routes instead of running
+// into rate limits, and retries rate-limited calls with exponential backoff. A guild runs one job
+// at a time. With dry_run, the job is only planned. Bans and role assignments require an
+// unrestricted token, purges write access to the channel. Bulk bans need a second token's
+// approval if the ApprovalActions option lists ActionBulkBan.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A JobParams object.
+//
+// Returns:
+//   - On success, it returns the job as JSON with HTTP status 202 (Accepted), or HTTP status 200
+//     with the planned job for a dry run.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) if the token or module does not allow the job,
+//     HTTP status 409 (Conflict) if another job of the guild is running,
+//     or HTTP status 500 (Internal Server Error) if the job cannot be created.
+//
+// @Summary		Create Guild Job
+// @Description	Start a bulk ban, role assignment or purge at the pace of the rate limits, reporting its plan and ETA.
+// @Tags			Jobs
+// @Param			body	body		JobParams	true	"Job"
+// @Success		200		{object}	Job
+// @Success		202		{object}	Job
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		409		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/jobs [post]
+func CreateGuildJob(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	user, _ := c.Locals("ActingUser").(string)
+
+	var params JobParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	switch params.Kind {
+	case JobBulkBan, JobRoleAssign:
+		if !unrestricted(c) {
+			return adminForbidden(c)
+		}
+	case JobPurge:
+		if !channelAllowed(c, params.ChannelID, true) {
+			return channelForbidden(c)
+		}
+	}
+	module := map[string]string{JobBulkBan: ModuleModeration, JobPurge: ModuleMessages}[params.Kind]
+	if module != "" {
+		if denied, err := moduleDenied(c, guildID, module); denied {
+			return err
+		}
+	}
+
+	// Validates the job before it waits for approval.
+	if _, _, err := planJobCalls(s, guildID, params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if params.Kind == JobBulkBan && !params.DryRun && approvals.required(ActionBulkBan) {
+		return requestApproval(c, ActionBulkBan, params.UserIDs, func(options ...discordgo.RequestOption) error {
+			_, err := startJob(s, guildID, user, params, options)
+			return err
+		})
+	}
+
+	j, err := startJob(s, guildID, user, params, auditOptions(c))
+	switch {
+	case errors.Is(err, errJobRunning):
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create job: " + err.Error())
+	case params.DryRun:
+		return c.JSON(j)
+	}
+
+	jobs.Lock()
+	defer jobs.Unlock()
+	return c.Status(fiber.StatusAccepted).JSON(*j)
+}
+
+// CancelGuildJob cancels a running bulk job, or removes a finished one.
+//
+// Calls already made are not undone; the job reports how many were made.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - jobid: The ID of the job.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the job does not exist.
+//
+// @Summary		Cancel Guild Job
+// @Description	Cancel a running bulk job of the guild, or remove a finished one.
+// @Tags			Jobs
+// @Param			jobid	path	string	true	"Job ID"
+// @Success		204
+// @Failure		404	{object}	error
+// @Router			/api/guild/jobs/{jobid} [delete]
+func CancelGuildJob(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	jobs.Lock()
+	defer jobs.Unlock()
+	j, ok := guildJob(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Job not found")
+	}
+	if j.FinishedAt == nil {
+		j.cancel() // runJob records the cancellation once the current call returns.
+	} else {
+		jobs.byGuild[guildID] = slices.DeleteFunc(jobs.byGuild[guildID], func(other *Job) bool { return other == j })
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,271 @@
+package disgm
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kinds of reversible changes recorded in the undo journal.
+const (
+	ChangeChannelEdit     = "channel_edit"
+	ChangeOverwriteEdit   = "overwrite_edit"
+	ChangeOverwriteDelete = "overwrite_delete"
+	ChangeRoleEdit        = "role_edit"
+	ChangeRolePositions   = "role_positions"
+)
+
+// ChangeIDHeader is the response header carrying the journal ID of a reversible change.
+const ChangeIDHeader = "X-Change-ID"
+
+// Change is a reversible mutation recorded in the undo journal.
+type Change struct {
+	ID        string    `json:"id"`         // Unique ID of the change
+	Kind      string    `json:"kind"`       // Kind of change, e.g. "role_edit"
+	TargetID  string    `json:"target_id"`  // ID of the changed channel or role, or the guild for role positions
+	CreatedAt time.Time `json:"created_at"` // When the change was made
+
+	guildID string                                         // Guild the change belongs to
+	roleIDs []string                                       // Roles reordered by a role positions change
+	revert  func(options ...discordgo.RequestOption) error // Restores the before-image
+}
+
+// changeJournal keeps recent reversible changes in memory.
+type changeJournal struct {
+	mu        sync.Mutex
+	retention time.Duration
+	changes   map[string]*Change
+}
+
+// The undo journal, configured by New from the options.
+var journal = &changeJournal{changes: make(map[string]*Change)}
+
+// prune removes changes older than the retention window. The caller must hold the lock.
+func (j *changeJournal) prune() {
+	cutoff := time.Now().Add(-j.retention)
+	for id, change := range j.changes {
+		if change.CreatedAt.Before(cutoff) {
+			delete(j.changes, id)
+		}
+	}
+}
+
+// recordChange stores a reversible change and exposes its ID in the X-Change-ID response header.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context of the request that made the change.
+//   - kind: string – The kind of change, e.g. ChangeRoleEdit.
+//   - targetID: string – The ID of the changed channel or role.
+//   - revert: func – Restores the state from before the change.
+//   - roleIDs: []string – The roles reordered by a ChangeRolePositions change.
+func recordChange(c *fiber.Ctx, kind, targetID string, revert func(options ...discordgo.RequestOption) error, roleIDs ...string) {
+	id, err := randomToken(8)
+	if err != nil {
+		return
+	}
+
+	journal.mu.Lock()
+	journal.prune()
+	journal.changes[id] = &Change{
+		ID:        id,
+		Kind:      kind,
+		TargetID:  strings.Clone(targetID), // Params point into the request buffer, which is reused.
+		CreatedAt: time.Now(),
+		guildID:   c.Locals("ID").(string),
+		roleIDs:   roleIDs,
+		revert:    revert,
+	}
+	journal.mu.Unlock()
+
+	c.Set(ChangeIDHeader, id)
+}
+
+// changeAllowed reports whether the token of the request may see or undo a change. Channel changes
+// follow the channel scope of the token, while role changes require an unrestricted token.
+func changeAllowed(c *fiber.Ctx, change *Change, write bool) bool {
+	switch change.Kind {
+	case ChangeRoleEdit, ChangeRolePositions:
+		return unrestricted(c)
+	default:
+		return channelAllowed(c, change.TargetID, write)
+	}
+}
+
+// changeProtected reports whether undoing a change would change a protected channel or role without override.
+func changeProtected(c *fiber.Ctx, s *discordgo.Session, change *Change) bool {
+	switch change.Kind {
+	case ChangeRoleEdit:
+		return roleProtected(c, s, change.guildID, change.TargetID)
+	case ChangeRolePositions:
+		return slices.ContainsFunc(change.roleIDs, func(roleID string) bool {
+			return roleProtected(c, s, change.guildID, roleID)
+		})
+	default:
+		return channelProtected(c, s, change.TargetID)
+	}
+}
+
+// restoreChannel reverts a channel to a before-image.
+//
+// The channel is patched with a raw payload, since ChannelEdit drops empty topics and parents.
+func restoreChannel(s *discordgo.Session, before *discordgo.Channel, options ...discordgo.RequestOption) error {
+	data := map[string]interface{}{
+		"name":                  before.Name,
+		"nsfw":                  before.NSFW,
+		"position":              before.Position,
+		"permission_overwrites": before.PermissionOverwrites,
+		"parent_id":             nil,
+	}
+	if before.ParentID != "" {
+		data["parent_id"] = before.ParentID
+	}
+
+	switch before.Type {
+	case discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice:
+		data["bitrate"] = before.Bitrate
+		data["user_limit"] = before.UserLimit
+	case discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews, discordgo.ChannelTypeGuildForum:
+		data["topic"] = before.Topic
+		data["rate_limit_per_user"] = before.RateLimitPerUser
+	}
+
+	endpoint := discordgo.EndpointChannel(before.ID)
+	_, err := s.RequestWithBucketID("PATCH", endpoint, data, endpoint, options...)
+	return err
+}
+
+// restoreOverwrite reverts a permission overwrite, removing it if it did not exist before.
+func restoreOverwrite(s *discordgo.Session, channelID, overwriteID string, before *discordgo.PermissionOverwrite, options ...discordgo.RequestOption) error {
+	if before == nil {
+		return s.ChannelPermissionDelete(channelID, overwriteID, options...)
+	}
+	return s.ChannelPermissionSet(channelID, overwriteID, before.Type, before.Allow, before.Deny, options...)
+}
+
+// findOverwrite returns the permission overwrite with the given ID, or nil if the channel has none.
+func findOverwrite(channel *discordgo.Channel, overwriteID string) *discordgo.PermissionOverwrite {
+	i := slices.IndexFunc(channel.PermissionOverwrites, func(o *discordgo.PermissionOverwrite) bool {
+		return o.ID == overwriteID
+	})
+	if i < 0 {
+		return nil
+	}
+	return channel.PermissionOverwrites[i]
+}
+
+// restoreRole reverts a role to a before-image.
+func restoreRole(s *discordgo.Session, guildID string, before *discordgo.Role, options ...discordgo.RequestOption) error {
+	_, err := s.GuildRoleEdit(guildID, before.ID, &discordgo.RoleParams{
+		Name:        before.Name,
+		Color:       &before.Color,
+		Hoist:       &before.Hoist,
+		Permissions: &before.Permissions,
+		Mentionable: &before.Mentionable,
+	}, options...)
+	return err
+}
+
+// GetRecentChanges retrieves the reversible changes of the guild within the retention window.
+//
+// Restricted tokens only see the changes of the channels they can read.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Context:
+//   - ID: The guild ID is stored in the Fiber context under the key "ID".
+//
+// Returns:
+//   - It returns the changes as a JSON array, newest first.
+// @Summary		Get Recent Changes
+// @Description	Retrieve the reversible changes that can still be undone.
+// @Tags			Undo
+// @Success		200	{array}	Change
+// @Router			/api/guild/undo [get]
+func GetRecentChanges(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	journal.mu.Lock()
+	journal.prune()
+	list := []*Change{}
+	for _, change := range journal.changes {
+		if change.guildID == guildID && changeAllowed(c, change, false) {
+			list = append(list, change)
+		}
+	}
+	journal.mu.Unlock()
+
+	slices.SortFunc(list, func(a, b *Change) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return c.JSON(list)
+}
+
+// UndoChange reverts a recorded change to its before-image.
+//
+// Restricted tokens can only undo changes of the channels they can write to, and changes of
+// protected channels and roles require the query flag override=true, as for the change itself.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - changeid: The ID of the change, as returned in the X-Change-ID header.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the change is unknown or outside the
+//     retention window, HTTP status 403 (Forbidden) if the token may not change its target or the
+//     target is protected, or HTTP status 500 (Internal Server Error) if the revert fails.
+// @Summary		Undo Change
+// @Description	Revert a recent role, channel or permission overwrite change.
+// @Tags			Undo
+// @Param			changeid	path	string	true	"Change ID"
+// @Param			override	query	bool	false	"Allow changing a protected resource"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/undo/{changeid} [post]
+func UndoChange(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	changeID := c.Params("changeid")
+
+	journal.mu.Lock()
+	journal.prune()
+	change, ok := journal.changes[changeID]
+	journal.mu.Unlock()
+
+	if !ok || change.guildID != guildID || !changeAllowed(c, change, false) {
+		return c.Status(fiber.StatusNotFound).SendString("Change not found or expired")
+	}
+
+	if !changeAllowed(c, change, true) {
+		return channelForbidden(c)
+	}
+	if changeProtected(c, s, change) {
+		return protectedForbidden(c)
+	}
+
+	// Removes the change first, so it can only be reverted once.
+	journal.mu.Lock()
+	if _, ok := journal.changes[change.ID]; !ok {
+		journal.mu.Unlock()
+		return c.Status(fiber.StatusNotFound).SendString("Change not found or expired")
+	}
+	delete(journal.changes, change.ID)
+	journal.mu.Unlock()
+
+	if err := change.revert(auditOptions(c)...); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to undo change: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
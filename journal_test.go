@@ -0,0 +1,103 @@
+package disgm_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rif223/disgm"
+	"github.com/rif223/disgm/disgmtest"
+)
+
+// recentChanges lists the changes the token can undo, by target ID.
+func (ts *testServer) recentChanges(t *testing.T, token string) map[string]string {
+	t.Helper()
+
+	status, body := ts.do(t, http.MethodGet, "/api/guild/undo", token, "")
+	if status != http.StatusOK {
+		t.Fatalf("listing changes: status %d: %s", status, body)
+	}
+	var changes []disgm.Change
+	if err := json.Unmarshal([]byte(body), &changes); err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]string, len(changes))
+	for _, change := range changes {
+		ids[change.TargetID] = change.ID
+	}
+	return ids
+}
+
+// TestUndoChangeAccess checks that changes outside the token scope are neither listed nor undone,
+// and that changes of protected channels are only undone with override.
+func TestUndoChangeAccess(t *testing.T) {
+	var open, hidden, readOnly, protected *discordgo.Channel
+	ts := startServer(t, func(b *disgmtest.Backend, guild *discordgo.Guild, opt *disgm.Options) {
+		open = b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "open", Type: discordgo.ChannelTypeGuildText})
+		hidden = b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "hidden", Type: discordgo.ChannelTypeGuildText})
+		readOnly = b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "read-only", Type: discordgo.ChannelTypeGuildText})
+		protected = b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "protected", Type: discordgo.ChannelTypeGuildText})
+		opt.Protection = disgm.Protection{ChannelIDs: []string{protected.ID}}
+	})
+
+	for _, path := range []string{"/api/guild/channels/" + open.ID, "/api/guild/channels/" + hidden.ID, "/api/guild/channels/" + readOnly.ID, "/api/guild/channels/" + protected.ID + "?override=true"} {
+		if status, body := ts.do(t, http.MethodPatch, path, disgmtest.BotToken, `{"name":"renamed"}`); status != http.StatusOK {
+			t.Fatalf("PATCH %s: status %d: %s", path, status, body)
+		}
+	}
+	changes := ts.recentChanges(t, disgmtest.BotToken)
+
+	status, body := ts.do(t, http.MethodPost, "/api/guild/tokens", disgmtest.BotToken,
+		`{"name":"scoped","scope":{"write_channels":["`+open.ID+`"],"read_channels":["`+readOnly.ID+`"]}}`)
+	if status != http.StatusCreated {
+		t.Fatalf("issuing scoped token: status %d: %s", status, body)
+	}
+	var scoped disgm.GuildToken
+	if err := json.Unmarshal([]byte(body), &scoped); err != nil {
+		t.Fatal(err)
+	}
+
+	listed := ts.recentChanges(t, scoped.Token)
+	if _, ok := listed[hidden.ID]; ok || len(listed) != 2 {
+		t.Errorf("scoped token lists changes of %v, want only %s and %s", listed, open.ID, readOnly.ID)
+	}
+
+	undo := func(token, id, query string) int {
+		status, _ := ts.do(t, http.MethodPost, "/api/guild/undo/"+id+query, token, "")
+		return status
+	}
+	denied := []struct {
+		name  string
+		token string
+		id    string
+		query string
+		want  int
+	}{
+		{"channel outside the scope", scoped.Token, changes[hidden.ID], "", http.StatusNotFound},
+		{"read-only channel", scoped.Token, changes[readOnly.ID], "", http.StatusForbidden},
+		{"protected channel", disgmtest.BotToken, changes[protected.ID], "", http.StatusForbidden},
+	}
+	for _, tt := range denied {
+		if status := undo(tt.token, tt.id, tt.query); status != tt.want {
+			t.Errorf("undoing a change of a %s: status %d, want %d", tt.name, status, tt.want)
+		}
+	}
+
+	// Denied changes are kept, so they can still be undone by a token allowed to.
+	allowed := []struct {
+		name  string
+		token string
+		id    string
+		query string
+	}{
+		{"channel in the scope", scoped.Token, changes[open.ID], ""},
+		{"channel outside the scope, with the guild token", disgmtest.BotToken, changes[hidden.ID], ""},
+		{"protected channel, with override", disgmtest.BotToken, changes[protected.ID], "?override=true"},
+	}
+	for _, tt := range allowed {
+		if status := undo(tt.token, tt.id, tt.query); status != http.StatusNoContent {
+			t.Errorf("undoing a change of a %s: status %d, want 204", tt.name, status)
+		}
+	}
+}
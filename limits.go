@@ -0,0 +1,178 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// List resources whose default and maximum sizes can be set with the Limits option.
+const (
+	LimitMembers             = "members"               // Members per page of GET /guild/members.
+	LimitBans                = "bans"                  // Bans per page of GET /guild/bans.
+	LimitMessages            = "messages"              // Messages per page of GET /guild/channels/:channelid/messages.
+	LimitReactions           = "reactions"             // Users per page of the reactions of a message.
+	LimitPollVoters          = "poll_voters"           // Users per page of the voters of a poll answer.
+	LimitAuditLog            = "audit_log"             // Entries per page of GET /guild/audit-logs.
+	LimitArchivedThreads     = "archived_threads"      // Threads per page of the archived threads of a channel.
+	LimitScheduledEventUsers = "scheduled_event_users" // Users per page of the subscribers of a scheduled event.
+	LimitChannelHistory      = "channel_history"       // Entries of GET /guild/channels/:channelid/history.
+	LimitMessageSearch       = "message_search"        // Messages per page of GET /guild/search/messages.
+	LimitTranscript          = "transcript"            // Messages of a channel transcript.
+	LimitAPIAudit            = "api_audit"             // Entries of GET /guild/audit.
+)
+
+// ListLimit sets the number of items a list returns if a request sets no limit, and the most a
+// request may ask for.
+type ListLimit struct {
+	Default int // Items returned if the request sets no limit, 0 for the built-in default
+	Max     int // Most items a request may ask for, 0 for the built-in maximum
+}
+
+// listResource describes a list with a configurable size.
+type listResource struct {
+	name    string
+	limit   ListLimit // Built-in sizes
+	ceiling int       // Most items Discord or disgm return per request, 0 if there is no such limit
+	paths   []string  // Endpoints listing the resource, as in the swagger doc
+}
+
+// listResources lists the resources whose sizes can be configured, with their built-in sizes.
+var listResources = []listResource{
+	{name: LimitMembers, limit: ListLimit{1000, 1000}, ceiling: 1000, paths: []string{"/api/guild/members"}},
+	{name: LimitBans, limit: ListLimit{100, 1000}, ceiling: 1000, paths: []string{"/api/guild/bans"}},
+	{name: LimitMessages, limit: ListLimit{100, 100}, ceiling: 100, paths: []string{"/api/guild/channels/{channelid}/messages"}},
+	{name: LimitReactions, limit: ListLimit{100, 100}, ceiling: 100, paths: []string{"/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid}"}},
+	{name: LimitPollVoters, limit: ListLimit{25, 100}, ceiling: 100, paths: []string{"/api/guild/channels/{channelid}/messages/{messageid}/polls/{answerid}/voters"}},
+	{name: LimitAuditLog, limit: ListLimit{50, 100}, ceiling: 100, paths: []string{"/api/guild/audit-logs"}},
+	{name: LimitArchivedThreads, limit: ListLimit{50, 100}, ceiling: 100, paths: []string{"/api/guild/channels/{channelid}/threads/archived/public", "/api/guild/channels/{channelid}/threads/archived/private"}},
+	{name: LimitScheduledEventUsers, limit: ListLimit{100, 100}, ceiling: 100, paths: []string{"/api/guild/scheduled-events/{eventid}/users"}},
+	{name: LimitChannelHistory, limit: ListLimit{50, maxChannelHistory}, ceiling: maxChannelHistory, paths: []string{"/api/guild/channels/{channelid}/history"}},
+	{name: LimitMessageSearch, limit: ListLimit{25, 100}, ceiling: 1000, paths: []string{"/api/guild/search/messages"}},
+	{name: LimitTranscript, limit: ListLimit{1000, 10000}, paths: []string{"/api/guild/channels/{channelid}/transcript"}},
+	{name: LimitAPIAudit, limit: ListLimit{100, 1000}, ceiling: maxAPIAuditEntries, paths: []string{"/api/guild/audit"}},
+}
+
+// The sizes of the lists, keyed by resource: the built-in sizes with those of the Limits option
+// applied.
+var listLimits = struct {
+	sync.Mutex
+	limits map[string]ListLimit
+}{limits: builtinListLimits()}
+
+// builtinListLimits returns the built-in sizes of the lists.
+func builtinListLimits() map[string]ListLimit {
+	limits := make(map[string]ListLimit, len(listResources))
+	for _, r := range listResources {
+		limits[r.name] = r.limit
+	}
+	return limits
+}
+
+// configureListLimits applies the Limits option over the built-in sizes. Unset fields keep their
+// built-in value; a maximum below the built-in default also lowers the default.
+func configureListLimits(configured map[string]ListLimit) {
+	listLimits.Lock()
+	defer listLimits.Unlock()
+
+	listLimits.limits = builtinListLimits()
+	for name, l := range configured {
+		limit, ok := listLimits.limits[name]
+		if !ok {
+			continue
+		}
+		if l.Max > 0 {
+			limit.Max = l.Max
+		}
+		if l.Default > 0 {
+			limit.Default = l.Default
+		}
+		limit.Default = min(limit.Default, limit.Max)
+		listLimits.limits[name] = limit
+	}
+}
+
+// listLimit returns the sizes of a list resource.
+func listLimit(name string) ListLimit {
+	listLimits.Lock()
+	defer listLimits.Unlock()
+	return listLimits.limits[name]
+}
+
+// queryLimit reads the limit query parameter of a list, clamped between 1 and the maximum of the
+// resource.
+func queryLimit(c *fiber.Ctx, resource string) int {
+	sizes := listLimit(resource)
+	return min(max(c.QueryInt("limit", sizes.Default), 1), sizes.Max)
+}
+
+// checkListLimits reports configured sizes that are unknown, negative, above what the endpoint
+// can return or with a default above the maximum.
+func checkListLimits(configured map[string]ListLimit) []string {
+	var problems []string
+	for _, r := range listResources {
+		l, ok := configured[r.name]
+		if !ok {
+			continue
+		}
+		if l.Default < 0 || l.Max < 0 {
+			problems = append(problems, fmt.Sprintf("Limits: %s must not be negative", r.name))
+			continue
+		}
+		if r.ceiling > 0 && l.Max > r.ceiling {
+			problems = append(problems, fmt.Sprintf("Limits: %s maximum %d exceeds the %d items returned per request", r.name, l.Max, r.ceiling))
+		}
+		maximum := l.Max
+		if maximum == 0 {
+			maximum = r.limit.Max
+		}
+		if l.Default > maximum {
+			problems = append(problems, fmt.Sprintf("Limits: %s default %d exceeds its maximum %d", r.name, l.Default, maximum))
+		}
+	}
+
+	names := make([]string, 0, len(configured))
+	for name := range configured {
+		if !slices.ContainsFunc(listResources, func(r listResource) bool { return r.name == name }) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		problems = append(problems, fmt.Sprintf("Limits: unknown resource %q", name))
+	}
+	return problems
+}
+
+// documentListLimits sets the default and maximum of the limit parameters in a swagger doc to
+// the configured sizes. The doc is returned unchanged if it cannot be parsed.
+func documentListLimits(doc string) string {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return doc
+	}
+	paths, _ := spec["paths"].(map[string]interface{})
+
+	for _, r := range listResources {
+		limit := listLimit(r.name)
+		for _, path := range r.paths {
+			item, _ := paths[path].(map[string]interface{})
+			op, _ := item["get"].(map[string]interface{})
+			params, _ := op["parameters"].([]interface{})
+			for _, p := range params {
+				if param, ok := p.(map[string]interface{}); ok && param["name"] == "limit" && param["in"] == "query" {
+					param["minimum"], param["maximum"], param["default"] = 1, limit.Max, limit.Default
+				}
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(spec, "", "    ")
+	if err != nil {
+		return doc
+	}
+	return string(data)
+}
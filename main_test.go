@@ -0,0 +1,110 @@
+package disgm_test
+
+import (
+	"flag"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rif223/disgm"
+	"github.com/rif223/disgm/disgmtest"
+)
+
+// TestMain hides the logs of disgm, which the tests start many times, unless the tests run verbosely.
+func TestMain(m *testing.M) {
+	flag.Parse()
+	if !testing.Verbose() {
+		log.SetOutput(io.Discard)
+	}
+	os.Exit(m.Run())
+}
+
+// testServer is disgm serving its API on a random port, against a backend with one guild.
+type testServer struct {
+	URL     string             // Base URL of the server
+	Backend *disgmtest.Backend // Backend of the session
+	Guild   *discordgo.Guild   // Seeded guild, whose token is disgmtest.BotToken
+	Disgm   *disgm.Disgm
+}
+
+// startServer starts disgm with the API routes and the WebSocket and stops it when the test ends. The seed function,
+// if set, adds resources to the backend and changes the options before disgm is created.
+func startServer(t *testing.T, seed func(b *disgmtest.Backend, guild *discordgo.Guild, opt *disgm.Options)) *testServer {
+	t.Helper()
+
+	b := disgmtest.New()
+	t.Cleanup(b.Close)
+	guild := b.AddGuild(&discordgo.Guild{Name: "Test Guild"})
+
+	opt := disgm.Options{
+		DisableStartupMessage: true,
+		DisableLogger:         true,
+		TokenStore:            disgmtest.TokenStore{guild.ID: disgmtest.BotToken},
+	}
+	if seed != nil {
+		seed(b, guild, &opt)
+	}
+
+	s, err := b.Session()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := disgm.New(s, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.RegisterApiRouter()
+	d.RegisterWebSocket()
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	if err := d.Listener(ln); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testServer{URL: "http://" + ln.Addr().String(), Backend: b, Guild: guild, Disgm: d}
+}
+
+// do sends a request with the token to the server and returns the status and body of the response.
+// Bodies are sent as JSON.
+func (ts *testServer) do(t *testing.T, method, path, token, body string) (int, string) {
+	t.Helper()
+
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, ts.URL+path, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, string(data)
+}
@@ -1,6 +1,11 @@
 package disgm
 
 import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rif223/disgm/models"
@@ -8,33 +13,59 @@ import (
 
 type Member = models.Member
 
-// GetGuildMembers retrieves a list of up to 1000 members from a specific Discord guild.
+// memberPager pages through the members of a guild by user ID. Discord only supports after.
+var memberPager = pager{resource: LimitMembers}
+
+// GetGuildMembers retrieves a page of up to 1000 members from a specific Discord guild.
 //
 // This function extracts the guild ID from the Fiber context and uses the DiscordGo session to
-// retrieve the guild members. It fetches up to 1000 members from the specified guild.
+// retrieve the guild members, ordered by user ID. Discord only pages forward through members,
+// so pages have no prev cursor; the total is the member count of the guild.
 //
 // Parameters:
 //   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
 //   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
 //
+// Request Parameters:
+//   - limit: Optional number of members, 1 to 1000, defaults to 1000; both can be changed with the Limits option.
+//   - cursor: Optional cursor of the page, from the paging of a previous page.
+//
 // Returns:
-//   - On success, it returns the list of guild members as JSON with HTTP status 200.
-//   - On failure, it returns an HTTP status 500 and an error message if the members cannot be retrieved.
+//   - On success, it returns the page of guild members as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the limit or cursor is invalid,
+//     or HTTP status 500 and an error message if the members cannot be retrieved.
 // @Summary		Get Guild Members
-// @Description	Retrieve all members of the guild.
+// @Description	Retrieve a page of members of the guild, ordered by user ID.
 // @Tags			Members
-// @Success		200	{array}		Member
-// @Failure		500	{object}	error
+// @Param			limit	query		int		false	"Members per page"	minimum(1)	maximum(1000)	default(1000)
+// @Param			cursor	query		string	false	"Page cursor"
+// @Success		200		{object}	Page{data=[]Member}
+// @Failure		400		{object}	error
+// @Failure		500		{object}	error
 // @Router			/api/guild/members [get]
 func GetGuildMembers(c *fiber.Ctx, s *discordgo.Session) error {
 	guildID := c.Locals("ID").(string)
 
-	members, err := s.GuildMembers(guildID, "", 1000)
+	req, err := memberPager.request(c)
+	if err != nil {
+		return invalidPage(c, err)
+	}
+
+	members, err := s.GuildMembers(guildID, req.after, req.limit)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild members: " + err.Error())
 	}
 
-	return c.JSON(members)
+	ids := make([]string, len(members))
+	for i, m := range members {
+		ids[i] = m.User.ID
+	}
+	var total *int
+	if guild, err := s.State.Guild(guildID); err == nil && guild.MemberCount > 0 {
+		count := guild.MemberCount
+		total = &count
+	}
+	return memberPager.send(c, req, members, ids, total)
 }
 
 // GetGuildMember retrieves a specific member from a Discord guild using their member ID.
@@ -72,7 +103,9 @@ func GetGuildMember(c *fiber.Ctx, s *discordgo.Session) error {
 //
 // This function extracts the guild ID and member ID from the Fiber context and request parameters.
 // It parses the request body into a `discordgo.GuildMemberParams` struct and uses it to update
-// the member's settings (e.g., nickname, roles, mute, etc.).
+// the member's settings (e.g., nickname, roles, mute, etc.). The nickname is reset with null in
+// a merge patch, or by listing nick in the X-Update-Mask header. For the member ID @me, it changes
+// the nickname of the bot, the only field of a BotNicknameParams body.
 //
 // Parameters:
 //   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
@@ -80,24 +113,43 @@ func GetGuildMember(c *fiber.Ctx, s *discordgo.Session) error {
 //
 // Returns:
 //   - On success, it returns the updated guild member as JSON with HTTP status 200.
-//   - On failure, it returns an HTTP status 500 and an error message if the member cannot be updated.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the request body is invalid,
+//     or HTTP status 500 and an error message if the member cannot be updated.
 // @Summary		Update Guild Member
-// @Description	Update a specific member in the guild.
+// @Description	Update a specific member in the guild, or the nickname of the bot for @me.
 // @Tags			Members
-// @Param			memberid	path		string	true	"Member ID"
-// @Success		200			{object}	models.Member
-// @Failure		500			{object}	error
+// @Accept			json,application/merge-patch+json
+// @Param			memberid		path		string	true	"Member ID, or @me to change the nickname of the bot"
+// @Param			X-Update-Mask	header		string	false	"Fields to update, comma-separated; missing ones are cleared"
+// @Success		200				{object}	models.Member
+// @Failure		400				{object}	error
+// @Failure		500				{object}	error
 // @Router			/api/guild/members/{memberid} [patch]
 func UpdateGuildMember(c *fiber.Ctx, s *discordgo.Session) error {
 	guildID := c.Locals("ID").(string)
 	memberID := c.Params("memberid")
 
+	if memberID == "@me" {
+		return updateBotNickname(c, s)
+	}
+
 	var memberEdit discordgo.GuildMemberParams
 	if err := c.BodyParser(&memberEdit); err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
+	fields, err := patchFields(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
 
-	member, err := s.GuildMemberEdit(guildID, memberID, &memberEdit)
+	var member *discordgo.Member
+	if fields != nil {
+		member = new(discordgo.Member)
+		endpoint := discordgo.EndpointGuildMember(guildID, memberID)
+		err = patchResource(s, endpoint, discordgo.EndpointGuildMember(guildID, ""), fields, member, auditOptions(c)...)
+	} else {
+		member, err = s.GuildMemberEdit(guildID, memberID, &memberEdit, auditOptions(c)...)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update guild member: " + err.Error())
 	}
@@ -105,6 +157,50 @@ func UpdateGuildMember(c *fiber.Ctx, s *discordgo.Session) error {
 	return c.JSON(member)
 }
 
+// maxNickname is the maximum length of a nickname accepted by Discord.
+const maxNickname = 32
+
+// BotNicknameParams sets the nickname of the bot in the guild.
+type BotNicknameParams struct {
+	Nick *string `json:"nick"` // New nickname, 1-32 characters, null or an empty string to reset it
+}
+
+// updateBotNickname changes the nickname of the bot in the guild, for PATCH
+// /api/guild/members/@me. Unlike other members, whose nickname needs the Manage Nicknames
+// permission, the bot's own needs the Change Nickname permission and its own endpoint. The nick
+// field is required; null or an empty string resets the nickname.
+func updateBotNickname(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	// Decoded as a map to tell a missing nick from a null one, which resets the nickname.
+	var params map[string]*string
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	nick, ok := params["nick"]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: nick is required, null resets the nickname")
+	}
+	if nick != nil && utf8.RuneCountInString(*nick) > maxNickname {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: nick must have at most " + strconv.Itoa(maxNickname) + " characters")
+	}
+	if nick != nil && *nick == "" {
+		nick = nil
+	}
+
+	endpoint := discordgo.EndpointGuildMember(guildID, "@me")
+	body, err := s.RequestWithBucketID("PATCH", endpoint, BotNicknameParams{Nick: nick}, endpoint, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update bot nickname: " + err.Error())
+	}
+	var member discordgo.Member
+	if err := json.Unmarshal(body, &member); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update bot nickname: invalid member object")
+	}
+
+	return c.JSON(member)
+}
+
 // GetMemberRoles retrieves the roles of a specific guild member.
 //
 // This function extracts the guild ID and member ID from the Fiber context and request parameters.
@@ -161,7 +257,7 @@ func AddMemberRole(c *fiber.Ctx, s *discordgo.Session) error {
 	memberID := c.Params("memberid")
 	roleID := c.Params("roleid")
 
-	err := s.GuildMemberRoleAdd(guildID, memberID, roleID)
+	err := s.GuildMemberRoleAdd(guildID, memberID, roleID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to add role to member: " + err.Error())
 	}
@@ -194,7 +290,7 @@ func RemoveMemberRole(c *fiber.Ctx, s *discordgo.Session) error {
 	memberID := c.Params("memberid")
 	roleID := c.Params("roleid")
 
-	err := s.GuildMemberRoleRemove(guildID, memberID, roleID)
+	err := s.GuildMemberRoleRemove(guildID, memberID, roleID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to remove role from member: " + err.Error())
 	}
@@ -220,15 +316,214 @@ func RemoveMemberRole(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			memberid	path	string	true	"Member ID"
 // @Success		204
 // @Failure		500	{object}	error
-// @Router			/guilds/{guildid}/members/{memberid} [delete]
+// @Router			/api/guild/members/{memberid} [delete]
 func KickMember(c *fiber.Ctx, s *discordgo.Session) error {
 	guildID := c.Locals("ID").(string)
 	memberID := c.Params("memberid")
 
-	err := s.GuildMemberDelete(guildID, memberID)
+	err := s.GuildMemberDelete(guildID, memberID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to kick member: " + err.Error())
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// maxMemberImageSize is the maximum size of a guild avatar or banner accepted by Discord.
+const maxMemberImageSize = 10 * 1024 * 1024
+
+// MemberImages are the CDN URLs of the avatar and banner of a member. The guild-specific images
+// replace the images of the user in the guild; the display URLs are the ones Discord shows.
+type MemberImages struct {
+	AvatarURL        *string `json:"avatar_url"`         // Guild avatar of the member, null if not set
+	BannerURL        *string `json:"banner_url"`         // Guild banner of the member, null if not set
+	UserAvatarURL    string  `json:"user_avatar_url"`    // Avatar of the user, or the default avatar if not set
+	UserBannerURL    *string `json:"user_banner_url"`    // Banner of the user, null if not set
+	DisplayAvatarURL string  `json:"display_avatar_url"` // Guild avatar, or else the avatar of the user
+	DisplayBannerURL *string `json:"display_banner_url"` // Guild banner, or else the banner of the user
+}
+
+// MemberImagesParams sets the guild avatar and banner of the bot. The images are base64 or data
+// URIs; an empty string removes the guild image and an unset field keeps it.
+type MemberImagesParams struct {
+	Avatar *string `json:"avatar,omitempty"` // Guild avatar, up to 10 MiB
+	Banner *string `json:"banner,omitempty"` // Guild banner, up to 10 MiB
+}
+
+// imageMember is the member object with the guild banner, which discordgo does not decode.
+type imageMember struct {
+	Avatar string          `json:"avatar"`
+	Banner string          `json:"banner"`
+	User   *discordgo.User `json:"user"`
+}
+
+// cdnImageURL returns the URL of an image on the Discord CDN, animated if its hash says so.
+func cdnImageURL(path, hash, size string) *string {
+	if hash == "" {
+		return nil
+	}
+	url := discordgo.EndpointCDN + path + "/" + hash + ".png"
+	if strings.HasPrefix(hash, "a_") {
+		url = discordgo.EndpointCDN + path + "/" + hash + ".gif"
+	}
+	if size != "" {
+		url += "?size=" + size
+	}
+	return &url
+}
+
+// memberImages returns the image URLs of a member in the given size, or the default size if empty.
+// The banner of the user is not part of member objects, so the user is fetched for it.
+func memberImages(s *discordgo.Session, guildID string, m *imageMember, size string) *MemberImages {
+	userID := m.User.ID
+	images := &MemberImages{
+		AvatarURL:     cdnImageURL("guilds/"+guildID+"/users/"+userID+"/avatars", m.Avatar, size),
+		BannerURL:     cdnImageURL("guilds/"+guildID+"/users/"+userID+"/banners", m.Banner, size),
+		UserAvatarURL: m.User.AvatarURL(size),
+	}
+	if user, err := s.User(userID); err == nil {
+		images.UserBannerURL = cdnImageURL("banners/"+userID, user.Banner, size)
+	}
+
+	images.DisplayAvatarURL = images.UserAvatarURL
+	if images.AvatarURL != nil {
+		images.DisplayAvatarURL = *images.AvatarURL
+	}
+	images.DisplayBannerURL = images.UserBannerURL
+	if images.BannerURL != nil {
+		images.DisplayBannerURL = images.BannerURL
+	}
+	return images
+}
+
+// imageSize reads the size query parameter of the image endpoints, a power of two from 16 to 4096.
+func imageSize(c *fiber.Ctx) (string, bool) {
+	size := c.Query("size")
+	if size == "" {
+		return "", true
+	}
+	n, err := strconv.Atoi(size)
+	return size, err == nil && n >= 16 && n <= 4096 && n&(n-1) == 0
+}
+
+// GetMemberImages retrieves the avatar and banner URLs of a member.
+//
+// Members can have a guild-specific avatar and banner, which Discord shows instead of the images
+// of their user in that guild. This function turns the image hashes into CDN URLs, animated
+// images as GIF, so clients do not have to build them.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - size: Optional size of the images in pixels, a power of two from 16 to 4096.
+//
+// Returns:
+//   - On success, it returns the image URLs as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the size is invalid,
+//     or HTTP status 500 (Internal Server Error) if the member cannot be retrieved.
+// @Summary		Get Member Images
+// @Description	Retrieve the CDN URLs of the guild and user avatar and banner of a member.
+// @Tags			Members
+// @Param			memberid	path		string	true	"Member ID"
+// @Param			size		query		int		false	"Image size, a power of two from 16 to 4096"
+// @Success		200			{object}	MemberImages
+// @Failure		400			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/members/{memberid}/images [get]
+func GetMemberImages(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	memberID := c.Params("memberid")
+
+	size, ok := imageSize(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid size: must be a power of two from 16 to 4096")
+	}
+
+	endpoint := discordgo.EndpointGuildMember(guildID, memberID)
+	body, err := s.RequestWithBucketID("GET", endpoint, nil, discordgo.EndpointGuildMember(guildID, ""))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve member: " + err.Error())
+	}
+	var member imageMember
+	if err := json.Unmarshal(body, &member); err != nil || member.User == nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve member: invalid member object")
+	}
+
+	return c.JSON(memberImages(s, guildID, &member, size))
+}
+
+// UpdateMemberImages sets the guild avatar and banner of the bot.
+//
+// Discord only lets the bot change its own guild images, so the member ID must be "@me" or the
+// ID of the bot. The images are given as base64 or data URIs and the guild image is removed for
+// an empty string.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A MemberImagesParams object with the avatar and/or banner.
+//
+// Returns:
+//   - On success, it returns the new image URLs of the bot as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body or an image is invalid,
+//     HTTP status 403 (Forbidden) for members other than the bot,
+//     HTTP status 413 (Request Entity Too Large) if an image is too large,
+//     or HTTP status 500 (Internal Server Error) if the images cannot be set.
+// @Summary		Update Member Images
+// @Description	Set the guild avatar and banner of the bot.
+// @Tags			Members
+// @Param			memberid	path		string				true	"Member ID, @me or the bot's ID"
+// @Param			body		body		MemberImagesParams	true	"Guild images"
+// @Success		200			{object}	MemberImages
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		413			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/members/{memberid}/images [patch]
+func UpdateMemberImages(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	memberID := c.Params("memberid")
+
+	if memberID != "@me" && (s.State.User == nil || memberID != s.State.User.ID) {
+		return c.Status(fiber.StatusForbidden).SendString("Forbidden: only the guild images of the bot can be set")
+	}
+
+	var params MemberImagesParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	data := map[string]interface{}{}
+	for field, image := range map[string]*string{"avatar": params.Avatar, "banner": params.Banner} {
+		switch {
+		case image == nil:
+		case *image == "":
+			data[field] = nil // Removes the guild image.
+		default:
+			uri, err := imageDataURI(*image, maxMemberImageSize)
+			if err != nil {
+				return invalidImage(c, err)
+			}
+			data[field] = uri
+		}
+	}
+	if len(data) == 0 {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: avatar or banner is required")
+	}
+
+	endpoint := discordgo.EndpointGuildMember(guildID, "@me")
+	body, err := s.RequestWithBucketID("PATCH", endpoint, data, endpoint, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update guild images: " + err.Error())
+	}
+	var member imageMember
+	if err := json.Unmarshal(body, &member); err != nil || member.User == nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update guild images: invalid member object")
+	}
+
+	return c.JSON(memberImages(s, guildID, &member, ""))
+}
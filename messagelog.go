@@ -0,0 +1,462 @@
+package disgm
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/store"
+)
+
+// Attachment and content kinds accepted by the has filter of the message search.
+const (
+	HasLink  = "link"
+	HasEmbed = "embed"
+	HasFile  = "file"
+	HasImage = "image"
+	HasVideo = "video"
+	HasSound = "sound"
+)
+
+const (
+	messageLogPrune = time.Hour // Interval between retention prunes of a guild's log.
+)
+
+// LoggedMessage is a message recorded by the message log.
+type LoggedMessage struct {
+	ID         string     `json:"id"`                   // Snowflake ID of the message
+	ChannelID  string     `json:"channel_id"`           // ID of the channel the message was sent in
+	AuthorID   string     `json:"author_id"`            // ID of the author
+	AuthorName string     `json:"author_name"`          // Username of the author when the message was sent
+	Content    string     `json:"content"`              // Latest content of the message
+	Has        []string   `json:"has,omitempty"`        // Kinds of content in the message, e.g. "link" or "image"
+	Timestamp  time.Time  `json:"timestamp"`            // When the message was sent
+	EditedAt   *time.Time `json:"edited_at,omitempty"`  // When the message was last edited
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"` // When the message was deleted, deleted messages stay searchable
+}
+
+// MessageSearchResult is a page of messages matching a search, newest first.
+type MessageSearchResult struct {
+	Total    int             `json:"total"`    // Number of matching messages across all pages
+	Messages []LoggedMessage `json:"messages"` // Matching messages of the page
+}
+
+// MessageQuery is a search over the message log of a guild.
+type MessageQuery struct {
+	Text       string   // Words the messages must contain, all of them, case-insensitively
+	AuthorID   string   // Only messages of this user, if set
+	ChannelIDs []string // Only messages in one of these channels, if set
+	Has        []string // Kinds of content the messages must all have, e.g. "link" or "image"
+	Before     string   // Only messages older than this message ID, if set
+	Limit      int      // Maximum number of messages returned
+}
+
+// MessageIndex stores the message log and searches it.
+//
+// The default index keeps the messages and an inverted index of their words in the key-value
+// store of the KVStore option, so it persists with it and is not rebuilt in memory on startup.
+// Embedders can plug in a dedicated full-text engine, such as SQLite FTS or Bleve, with the
+// MessageIndex option. Implementations must be safe for concurrent use.
+type MessageIndex interface {
+
+	// Put stores a message of a guild, replacing the previous version with the same ID.
+	Put(guildID string, m LoggedMessage) error
+
+	// Get retrieves a message of a guild and whether it exists.
+	Get(guildID, messageID string) (LoggedMessage, bool, error)
+
+	// Prune removes the messages of a guild sent before the given time.
+	Prune(guildID string, before time.Time) error
+
+	// Search returns the messages of a guild matching the query, newest first, with the total
+	// number of matches across all pages.
+	Search(guildID string, query MessageQuery) (MessageSearchResult, error)
+}
+
+// The message log: messages of every guild are recorded in the index while the log is enabled,
+// and messages older than the retention are pruned from it hourly.
+var messageLog = struct {
+	sync.Mutex
+	enabled   bool
+	retention time.Duration
+	index     MessageIndex
+	pruned    map[string]time.Time
+}{pruned: make(map[string]time.Time)}
+
+// searchTerms splits text into lowercase words, without duplicates.
+func searchTerms(text string) []string {
+	terms := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(terms)
+	return slices.Compact(terms)
+}
+
+// messageHas returns the kinds of content in a message.
+func messageHas(m *discordgo.Message) []string {
+	var has []string
+	if strings.Contains(m.Content, "http://") || strings.Contains(m.Content, "https://") {
+		has = append(has, HasLink)
+	}
+	if len(m.Embeds) > 0 {
+		has = append(has, HasEmbed)
+	}
+	if len(m.Attachments) > 0 {
+		has = append(has, HasFile)
+	}
+	for _, a := range m.Attachments {
+		kind, _, _ := strings.Cut(a.ContentType, "/")
+		switch kind {
+		case "image":
+			has = append(has, HasImage)
+		case "video":
+			has = append(has, HasVideo)
+		case "audio":
+			has = append(has, HasSound)
+		}
+	}
+	slices.Sort(has)
+	return slices.Compact(has)
+}
+
+// snowflakeLess orders snowflake IDs numerically.
+func snowflakeLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// kvMessageIndex is the default MessageIndex, kept in a storage namespace. Messages are keyed by
+// "<guild>/m/<id>", and every word of their content by "<guild>/t/<word>/<id>". IDs are padded
+// with zeros, so keys sort like the snowflakes and the newest messages come last.
+type kvMessageIndex struct {
+	storage *Storage
+}
+
+var _ MessageIndex = (*kvMessageIndex)(nil)
+
+// paddedID pads a snowflake ID to a fixed width, so IDs sort numerically as strings.
+func paddedID(id string) string {
+	if len(id) >= 20 {
+		return id
+	}
+	return strings.Repeat("0", 20-len(id)) + id
+}
+
+// getMessage reads a message in a transaction.
+func (idx *kvMessageIndex) getMessage(tx *StorageTx, guildID, padded string) (LoggedMessage, bool, error) {
+	var m LoggedMessage
+	value, ok, err := tx.Get(guildID + "/m/" + padded)
+	if err != nil || !ok {
+		return m, false, err
+	}
+	return m, true, json.Unmarshal(value, &m)
+}
+
+// deleteMessage removes a message and the entries of its words in a transaction.
+func (idx *kvMessageIndex) deleteMessage(tx *StorageTx, guildID string, m LoggedMessage) error {
+	padded := paddedID(m.ID)
+	for _, term := range searchTerms(m.Content) {
+		if err := tx.Delete(guildID + "/t/" + term + "/" + padded); err != nil {
+			return err
+		}
+	}
+	return tx.Delete(guildID + "/m/" + padded)
+}
+
+// Put stores a message and indexes its words, dropping the words of the previous version.
+func (idx *kvMessageIndex) Put(guildID string, m LoggedMessage) error {
+	value, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	padded := paddedID(m.ID)
+
+	return idx.storage.Update(func(tx *StorageTx) error {
+		old, ok, err := idx.getMessage(tx, guildID, padded)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := idx.deleteMessage(tx, guildID, old); err != nil {
+				return err
+			}
+		}
+		for _, term := range searchTerms(m.Content) {
+			if err := tx.Set(guildID+"/t/"+term+"/"+padded, []byte{}); err != nil {
+				return err
+			}
+		}
+		return tx.Set(guildID+"/m/"+padded, value)
+	})
+}
+
+// Get retrieves a message.
+func (idx *kvMessageIndex) Get(guildID, messageID string) (m LoggedMessage, ok bool, err error) {
+	err = idx.storage.View(func(tx *StorageTx) error {
+		m, ok, err = idx.getMessage(tx, guildID, paddedID(messageID))
+		return err
+	})
+	return
+}
+
+// Prune removes the oldest messages until one was sent after the cutoff.
+func (idx *kvMessageIndex) Prune(guildID string, before time.Time) error {
+	return idx.storage.Update(func(tx *StorageTx) error {
+		keys, err := tx.List(guildID + "/m/")
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			m, ok, err := idx.getMessage(tx, guildID, strings.TrimPrefix(key, guildID+"/m/"))
+			if err != nil {
+				return err
+			}
+			if ok && !m.Timestamp.Before(before) {
+				return nil // Newer IDs were sent later.
+			}
+			if ok {
+				if err := idx.deleteMessage(tx, guildID, m); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Search intersects the messages of every word of the query, or takes all messages without
+// words, and applies the other filters to them, newest first.
+func (idx *kvMessageIndex) Search(guildID string, query MessageQuery) (MessageSearchResult, error) {
+	result := MessageSearchResult{Messages: []LoggedMessage{}}
+
+	err := idx.storage.View(func(tx *StorageTx) error {
+		var candidates []string
+		if terms := searchTerms(query.Text); len(terms) > 0 {
+			for i, term := range terms {
+				prefix := guildID + "/t/" + term + "/"
+				keys, err := tx.List(prefix)
+				if err != nil {
+					return err
+				}
+				ids := make([]string, len(keys))
+				for j, key := range keys {
+					ids[j] = strings.TrimPrefix(key, prefix)
+				}
+				if i == 0 {
+					candidates = ids
+				} else {
+					candidates = slices.DeleteFunc(candidates, func(id string) bool {
+						_, found := slices.BinarySearch(ids, id)
+						return !found
+					})
+				}
+			}
+		} else {
+			keys, err := tx.List(guildID + "/m/")
+			if err != nil {
+				return err
+			}
+			for _, key := range keys {
+				candidates = append(candidates, strings.TrimPrefix(key, guildID+"/m/"))
+			}
+		}
+
+		before := ""
+		if query.Before != "" {
+			before = paddedID(query.Before)
+		}
+		for i := len(candidates) - 1; i >= 0; i-- {
+			if before != "" && candidates[i] >= before {
+				continue
+			}
+			m, ok, err := idx.getMessage(tx, guildID, candidates[i])
+			if err != nil {
+				return err
+			}
+			if !ok || !query.matches(m) {
+				continue
+			}
+			result.Total++
+			if len(result.Messages) < query.Limit {
+				result.Messages = append(result.Messages, m)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// matches reports whether a message passes the filters of a query other than its words.
+func (q MessageQuery) matches(m LoggedMessage) bool {
+	if q.AuthorID != "" && m.AuthorID != q.AuthorID {
+		return false
+	}
+	if len(q.ChannelIDs) > 0 && !slices.Contains(q.ChannelIDs, m.ChannelID) {
+		return false
+	}
+	for _, kind := range q.Has {
+		if !slices.Contains(m.Has, kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// logMessage records a sent or edited message. Partial updates without content, such as
+// embeds resolved after sending, keep the logged content.
+func logMessage(m *discordgo.Message, edited bool) {
+	messageLog.Lock()
+	defer messageLog.Unlock()
+
+	if !messageLog.enabled || messageLog.index == nil || m.GuildID == "" {
+		return
+	}
+
+	logged, ok, err := messageLog.index.Get(m.GuildID, m.ID)
+	switch {
+	case err != nil:
+		log.Printf("Failed to log message: %v", err)
+		return
+	case !ok && edited:
+		return // Sent before it could be logged.
+	case !ok:
+		logged = LoggedMessage{ID: m.ID, ChannelID: m.ChannelID, Timestamp: m.Timestamp}
+		if m.Author != nil {
+			logged.AuthorID = m.Author.ID
+			logged.AuthorName = m.Author.Username
+		}
+	case m.EditedTimestamp == nil:
+		return
+	default:
+		logged.EditedAt = m.EditedTimestamp
+	}
+	logged.Content = m.Content
+	logged.Has = messageHas(m)
+
+	if err := messageLog.index.Put(m.GuildID, logged); err != nil {
+		log.Printf("Failed to log message: %v", err)
+	}
+
+	// Prunes each guild hourly, starting with its first message after a restart.
+	if time.Since(messageLog.pruned[m.GuildID]) > messageLogPrune {
+		messageLog.pruned[m.GuildID] = time.Now()
+		if err := messageLog.index.Prune(m.GuildID, time.Now().Add(-messageLog.retention)); err != nil {
+			log.Printf("Failed to prune message log: %v", err)
+		}
+	}
+}
+
+// registerMessageLogHandlers records the messages of the guilds while the message log is enabled.
+func registerMessageLogHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		logMessage(m.Message, false)
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageUpdate) {
+		logMessage(m.Message, true)
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageDelete) {
+		messageLog.Lock()
+		defer messageLog.Unlock()
+
+		if !messageLog.enabled || messageLog.index == nil || m.GuildID == "" {
+			return
+		}
+		logged, ok, err := messageLog.index.Get(m.GuildID, m.ID)
+		if err == nil && ok && logged.DeletedAt == nil {
+			now := time.Now()
+			logged.DeletedAt = &now
+			err = messageLog.index.Put(m.GuildID, logged)
+		}
+		if err != nil {
+			log.Printf("Failed to log message deletion: %v", err)
+		}
+	})
+}
+
+// SearchGuildMessages searches the logged message history of the guild.
+//
+// Discord offers no search API to bots, so this function searches the messages recorded by the
+// message log, which is enabled with the MessageLog option. Messages are matched when they
+// contain every word of the query, case-insensitively. Deleted messages stay searchable and
+// carry their deletion time. Tokens restricted to channels only find messages in their channels.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - q: Words the messages must contain.
+//   - author: Only messages of this user ID.
+//   - channel: Only messages in this channel ID.
+//   - has: Comma-separated kinds of content the messages must have: link, embed, file, image, video or sound.
+//   - before: Only messages older than this message ID, to page through the results.
+//   - limit: The number of messages to return (default 25, max 100) unless changed with the Limits option.
+//
+// Returns:
+//   - On success, it returns the matching messages, newest first, with the total number of matches.
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is outside the
+//     token's scope, HTTP status 404 (Not Found) if the message log is disabled,
+//     or HTTP status 500 (Internal Server Error) if the index cannot be searched.
+// @Summary		Search Guild Messages
+// @Description	Full-text search over the logged message history of the guild.
+// @Tags			Messages
+// @Param			q		query		string	false	"Words the messages must contain"
+// @Param			author	query		string	false	"Author user ID"
+// @Param			channel	query		string	false	"Channel ID"
+// @Param			has		query		string	false	"Comma-separated content kinds: link, embed, file, image, video, sound"
+// @Param			before	query		string	false	"Only messages older than this message ID"
+// @Param			limit	query		int		false	"Number of messages"	minimum(1)	maximum(100)	default(25)
+// @Success		200		{object}	MessageSearchResult
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/search/messages [get]
+func SearchGuildMessages(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	channel := c.Query("channel")
+	limit := queryLimit(c, LimitMessageSearch)
+
+	var has []string
+	if v := c.Query("has"); v != "" {
+		has = strings.Split(strings.ToLower(v), ",")
+	}
+
+	if channel != "" && !channelAllowed(c, channel, false) {
+		return channelForbidden(c)
+	}
+
+	query := MessageQuery{
+		Text:     c.Query("q"),
+		AuthorID: c.Query("author"),
+		Has:      has,
+		Before:   c.Query("before"),
+		Limit:    limit,
+	}
+	if channel != "" {
+		query.ChannelIDs = []string{channel}
+	} else if scope, _ := c.Locals("Scope").(*store.Scope); scope.Restricted() {
+		query.ChannelIDs = append(slices.Clone(scope.ReadChannels), scope.WriteChannels...)
+	}
+
+	messageLog.Lock()
+	enabled, index := messageLog.enabled, messageLog.index
+	messageLog.Unlock()
+
+	if !enabled || index == nil {
+		return c.Status(fiber.StatusNotFound).SendString("Message log is not enabled")
+	}
+
+	result, err := index.Search(guildID, query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to search messages: " + err.Error())
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,221 @@
+package disgm
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rif223/disgm/store"
+)
+
+// newTestMessageIndex returns a message index in an empty in-memory store.
+func newTestMessageIndex() *kvMessageIndex {
+	return &kvMessageIndex{storage: &Storage{kv: store.NewMemoryKVStore(), prefix: "messages/"}}
+}
+
+// messageIDs returns the IDs of the messages of a search result, in order.
+func messageIDs(result MessageSearchResult) []string {
+	ids := []string{}
+	for _, m := range result.Messages {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Hello, World!", []string{"hello", "world"}},
+		{"deploy deploy DEPLOY", []string{"deploy"}},
+		{"v2.1 is out", []string{"1", "is", "out", "v2"}},
+		{"Grüße aus Köln", []string{"aus", "grüße", "köln"}},
+		{"https://example.com/a-b", []string{"a", "b", "com", "example", "https"}},
+		{"  ... --- !!!", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := searchTerms(tt.text)
+			if got == nil {
+				got = []string{}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("searchTerms(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageHas(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *discordgo.Message
+		want []string
+	}{
+		{"plain", &discordgo.Message{Content: "hello"}, nil},
+		{"link", &discordgo.Message{Content: "see https://example.com"}, []string{HasLink}},
+		{"embed", &discordgo.Message{Embeds: []*discordgo.MessageEmbed{{}}}, []string{HasEmbed}},
+		{"files", &discordgo.Message{Attachments: []*discordgo.MessageAttachment{
+			{ContentType: "image/png"}, {ContentType: "image/gif"}, {ContentType: "audio/ogg"}, {ContentType: "application/zip"},
+		}}, []string{HasFile, HasImage, HasSound}},
+		{"video", &discordgo.Message{Content: "http://x", Attachments: []*discordgo.MessageAttachment{
+			{ContentType: "video/mp4"},
+		}}, []string{HasFile, HasLink, HasVideo}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageHas(tt.m); !slices.Equal(got, tt.want) {
+				t.Errorf("messageHas() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageIndexSearch(t *testing.T) {
+	idx := newTestMessageIndex()
+	messages := []LoggedMessage{
+		{ID: "9", ChannelID: "c1", AuthorID: "a1", Content: "deploy started"},
+		{ID: "10", ChannelID: "c1", AuthorID: "a2", Content: "Deploy finished https://ci", Has: []string{HasLink}},
+		{ID: "11", ChannelID: "c2", AuthorID: "a1", Content: "screenshot of the deploy", Has: []string{HasFile, HasImage}},
+		{ID: "12", ChannelID: "c2", AuthorID: "a2", Content: "lunch?"},
+		{ID: "100", ChannelID: "c1", AuthorID: "a1", Content: "deploy failed", Has: []string{HasEmbed, HasLink}},
+	}
+	for _, m := range messages {
+		if err := idx.Put("g1", m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Another guild must not leak into the results.
+	if err := idx.Put("g2", LoggedMessage{ID: "13", Content: "deploy"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		query     MessageQuery
+		want      []string
+		wantTotal int
+	}{
+		{"all", MessageQuery{Limit: 10}, []string{"100", "12", "11", "10", "9"}, 5},
+		{"term", MessageQuery{Text: "DEPLOY", Limit: 10}, []string{"100", "11", "10", "9"}, 4},
+		{"all terms", MessageQuery{Text: "deploy finished", Limit: 10}, []string{"10"}, 1},
+		{"unknown term", MessageQuery{Text: "deploy nothing", Limit: 10}, []string{}, 0},
+		{"author", MessageQuery{Text: "deploy", AuthorID: "a1", Limit: 10}, []string{"100", "11", "9"}, 3},
+		{"channels", MessageQuery{ChannelIDs: []string{"c2"}, Limit: 10}, []string{"12", "11"}, 2},
+		{"has", MessageQuery{Has: []string{HasLink}, Limit: 10}, []string{"100", "10"}, 2},
+		{"has all", MessageQuery{Has: []string{HasLink, HasEmbed}, Limit: 10}, []string{"100"}, 1},
+		{"has image", MessageQuery{Text: "deploy", Has: []string{HasImage}, Limit: 10}, []string{"11"}, 1},
+		{"has none", MessageQuery{Has: []string{HasVideo}, Limit: 10}, []string{}, 0},
+		{"first page", MessageQuery{Text: "deploy", Limit: 2}, []string{"100", "11"}, 4},
+		{"next page", MessageQuery{Text: "deploy", Before: "11", Limit: 2}, []string{"10", "9"}, 2},
+		{"last page", MessageQuery{Text: "deploy", Before: "9", Limit: 2}, []string{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := idx.Search("g1", tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := messageIDs(result); !slices.Equal(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+			if result.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", result.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestMessageIndexPaginatesAll(t *testing.T) {
+	idx := newTestMessageIndex()
+	for i := 1; i <= 25; i++ {
+		if err := idx.Put("g1", LoggedMessage{ID: fmt.Sprint(1000 + i), Content: "page test"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var seen []string
+	before := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination does not end")
+		}
+		result, err := idx.Search("g1", MessageQuery{Text: "page", Before: before, Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Messages) == 0 {
+			break
+		}
+		seen = append(seen, messageIDs(result)...)
+		before = result.Messages[len(result.Messages)-1].ID
+	}
+
+	if len(seen) != 25 {
+		t.Fatalf("got %d messages across the pages, want 25", len(seen))
+	}
+	for i, id := range seen {
+		if want := fmt.Sprint(1025 - i); id != want {
+			t.Fatalf("message %d = %s, want %s", i, id, want)
+		}
+	}
+}
+
+func TestMessageIndexPutReplacesTerms(t *testing.T) {
+	idx := newTestMessageIndex()
+	if err := idx.Put("g1", LoggedMessage{ID: "1", Content: "old words"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Put("g1", LoggedMessage{ID: "1", Content: "new words"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for term, want := range map[string]int{"old": 0, "new": 1, "words": 1} {
+		result, err := idx.Search("g1", MessageQuery{Text: term, Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Total != want {
+			t.Errorf("%q: total = %d, want %d", term, result.Total, want)
+		}
+	}
+
+	m, ok, err := idx.Get("g1", "1")
+	if err != nil || !ok || m.Content != "new words" {
+		t.Errorf("Get = %+v, %v, %v", m, ok, err)
+	}
+}
+
+func TestMessageIndexPrune(t *testing.T) {
+	idx := newTestMessageIndex()
+	now := time.Now()
+	for i, age := range []time.Duration{48 * time.Hour, 36 * time.Hour, time.Hour} {
+		m := LoggedMessage{ID: fmt.Sprint(i + 1), Content: "kept or pruned", Timestamp: now.Add(-age)}
+		if err := idx.Put("g1", m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := idx.Prune("g1", now.Add(-24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := idx.Search("g1", MessageQuery{Text: "pruned", Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messageIDs(result); !slices.Equal(got, []string{"3"}) {
+		t.Errorf("messages after prune = %q, want [3]", got)
+	}
+	keys, err := idx.storage.List("g1/t/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 {
+		t.Errorf("got %d term entries after prune, want 3: %q", len(keys), keys)
+	}
+}
@@ -8,34 +8,70 @@ import (
 
 type Message = models.Message
 
-// GetChannelMessages retrieves up to 100 messages from a specific Discord channel.
+// messagePager pages through the messages of a channel, newest first.
+var messagePager = pager{resource: LimitMessages, descending: true, reversible: true}
+
+// GetChannelMessages retrieves a page of up to 100 messages from a specific Discord channel.
 //
 // This function extracts the channel ID from the Fiber context and request parameters.
-// It uses the DiscordGo session to retrieve the latest 100 messages from the specified channel.
+// It uses the DiscordGo session to retrieve the messages of the page, newest first, starting
+// with the latest messages of the channel.
+// With check_permissions, the VIEW_CHANNEL and READ_MESSAGE_HISTORY permissions of the bot are
+// checked against the state first, so a missing permission is reported as such.
 //
 // Parameters:
 //   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
 //   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
 //
+// Request Parameters:
+//   - limit: Optional number of messages, 1 to 100, defaults to 100; both can be changed with the Limits option.
+//   - cursor: Optional cursor of the page, from the paging of a previous page.
+//   - check_permissions: Optional flag to check the permissions of the bot before calling Discord.
+//
 // Returns:
-//   - On success, it returns the list of messages as JSON with HTTP status 200.
-//   - On failure, it returns an HTTP status 500 and an error message if the messages cannot be retrieved.
+//   - On success, it returns the page of messages as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the limit or cursor is invalid,
+//     HTTP status 403 (Forbidden) if the channel is not allowed for the token
+//     or the bot lacks a permission, or HTTP status 500 if the messages cannot be retrieved.
 // @Summary		Get Channel Messages
-// @Description	Retrieve all messages from a specific channel.
+// @Description	Retrieve a page of messages from a specific channel, newest first.
 // @Tags			Messages
-// @Param			channelid	path		string	true	"Channel ID"
-// @Success		200			{array}		Message
-// @Failure		500			{object}	error
+// @Param			channelid			path		string	true	"Channel ID"
+// @Param			limit				query		int		false	"Messages per page"	minimum(1)	maximum(100)	default(100)
+// @Param			cursor				query		string	false	"Page cursor"
+// @Param			check_permissions	query		bool	false	"Check the permissions of the bot first"
+// @Success		200					{object}	Page{data=[]Message}
+// @Failure		400					{object}	error
+// @Failure		403					{object}	error
+// @Failure		500					{object}	error
 // @Router			/api/guild/channels/{channelid}/messages [get]
 func GetChannelMessages(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 
-	messages, err := s.ChannelMessages(channelID, 100, "", "", "")
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+	if c.QueryBool("check_permissions") {
+		if missing := botMissingPermission(s, channelID, discordgo.PermissionViewChannel, discordgo.PermissionReadMessageHistory); missing != "" {
+			return permissionForbidden(c, missing)
+		}
+	}
+
+	req, err := messagePager.request(c)
+	if err != nil {
+		return invalidPage(c, err)
+	}
+
+	messages, err := s.ChannelMessages(channelID, req.limit, req.before, req.after, "")
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
 	}
 
-	return c.JSON(messages)
+	ids := make([]string, len(messages))
+	for i, m := range messages {
+		ids[i] = m.ID
+	}
+	return messagePager.send(c, req, messages, ids, nil)
 }
 
 // GetChannelMessage retrieves a specific message from a Discord channel by its ID.
@@ -56,12 +92,17 @@ func GetChannelMessages(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			channelid	path		string	true	"Channel ID"
 // @Param			messageid	path		string	true	"Message ID"
 // @Success		200			{object}	models.Message
+// @Failure		403			{object}	error
 // @Failure		500			{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid} [get]
 func GetChannelMessage(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 	messageID := c.Params("messageid")
 
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
 	message, err := s.ChannelMessage(channelID, messageID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve message: " + err.Error())
@@ -88,11 +129,16 @@ func GetChannelMessage(c *fiber.Ctx, s *discordgo.Session) error {
 // @Tags			Messages
 // @Param			channelid	path		string	true	"Channel ID"
 // @Success		201			{object}	models.Message
+// @Failure		403			{object}	error
 // @Failure		500			{object}	error
 // @Router			/api/guild/channels/{channelid}/messages [post]
 func SendChannelMessage(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
 	var message discordgo.MessageSend
 	if err := c.BodyParser(&message); err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
@@ -125,12 +171,17 @@ func SendChannelMessage(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			channelid	path		string	true	"Channel ID"
 // @Param			messageid	path		string	true	"Message ID"
 // @Success		200			{object}	models.Message
+// @Failure		403			{object}	error
 // @Failure		500			{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid} [patch]
 func EditChannelMessage(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 	messageID := c.Params("messageid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
 	var message discordgo.MessageEdit
 	if err := c.BodyParser(&message); err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
@@ -165,16 +216,69 @@ func EditChannelMessage(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			channelid	path	string	true	"Channel ID"
 // @Param			messageid	path	string	true	"Message ID"
 // @Success		204
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid} [delete]
 func DeleteChannelMessage(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 	messageID := c.Params("messageid")
 
-	err := s.ChannelMessageDelete(channelID, messageID)
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	err := s.ChannelMessageDelete(channelID, messageID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete message: " + err.Error())
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// StartMessageThread starts a thread from an existing message.
+//
+// This function extracts the channel ID and message ID from the request parameters and parses
+// the thread settings from the request body into a `discordgo.ThreadStart` struct. It uses the
+// DiscordGo session to start a thread on the message.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the created thread channel as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     or HTTP status 500 and an error message if the thread cannot be started.
+// @Summary		Start Message Thread
+// @Description	Start a thread from an existing message.
+// @Tags			Messages
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			messageid	path		string	true	"Message ID"
+// @Success		201			{object}	models.Channel
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/messages/{messageid}/threads [post]
+func StartMessageThread(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+	messageID := c.Params("messageid")
+
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	var thread discordgo.ThreadStart
+	if err := c.BodyParser(&thread); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if thread.Name == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name is required")
+	}
+
+	channel, err := s.MessageThreadStartComplex(channelID, messageID, &thread, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to start thread: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(channel)
+}
@@ -0,0 +1,150 @@
+package disgm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// openMetricsContentType is the content type of the OpenMetrics text format.
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// labelEscaper escapes label values for the OpenMetrics text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricsWriter writes metric families in the OpenMetrics text format.
+type metricsWriter struct {
+	strings.Builder
+}
+
+// family writes the metadata of a metric family.
+func (w *metricsWriter) family(name, kind, help string) {
+	fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n", name, kind, name, help)
+}
+
+// sample writes a sample with labels given as name and value pairs.
+func (w *metricsWriter) sample(name string, value interface{}, labels ...string) {
+	w.WriteString(name)
+	for i := 0; i+1 < len(labels); i += 2 {
+		sep := ","
+		if i == 0 {
+			sep = "{"
+		}
+		fmt.Fprintf(w, `%s%s="%s"`, sep, labels[i], labelEscaper.Replace(labels[i+1]))
+	}
+	if len(labels) > 0 {
+		w.WriteString("}")
+	}
+	fmt.Fprintf(w, " %v\n", value)
+}
+
+// GetAdminMetrics exposes the WebSocket topology and the event deliveries of all guilds as
+// OpenMetrics, for Prometheus and compatible scrapers.
+//
+// The metrics are the connected WebSocket clients per guild, the clients receiving each forwarded
+// event type per guild by their subscriptions, the events delivered to and failed for each sink
+// since disgm started, and the success rate of each sink over the last 24 hours. Sinks are named
+// as in the delivery report. Requires an admin token, see the AdminTokens option.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the metrics in the OpenMetrics text format.
+//   - On failure, it returns an HTTP status 403 (Forbidden) without an admin token.
+// @Summary		Get Admin Metrics
+// @Description	Expose the WebSocket clients and subscriptions per guild and the event delivery success rates per sink as OpenMetrics.
+// @Tags			Admin
+// @Produce		plain
+// @Success		200	{string}	string
+// @Failure		403	{object}	error
+// @Router			/api/admin/metrics [get]
+func GetAdminMetrics(c *fiber.Ctx, s *discordgo.Session) error {
+	if !isAdmin(c) {
+		return adminTokenRequired(c)
+	}
+
+	clientCounts := make(map[string]int)
+	subscriptionCounts := make(map[string]map[string]int) // By guild and event
+	clientsMu.Lock()
+	for conn, guildID := range clients {
+		clientCounts[guildID]++
+		if subscriptionCounts[guildID] == nil {
+			subscriptionCounts[guildID] = make(map[string]int)
+		}
+		sub := clientInfo[conn].subscription
+		for _, name := range forwardedEvents {
+			if sub == nil || sub.events.match(name) {
+				subscriptionCounts[guildID][name]++
+			}
+		}
+	}
+	clientsMu.Unlock()
+
+	type sinkMetrics struct {
+		key                           deliveryKey
+		delivered, failed             uint64
+		recentDelivered, recentFailed int
+	}
+	var sinks []sinkMetrics
+	oldest := time.Now().Unix()/3600 - int64(deliveryWindow/time.Hour)
+	deliveries.Lock()
+	for key, d := range deliveries.sinks {
+		m := sinkMetrics{key: key, delivered: d.delivered, failed: d.failed}
+		for h, counts := range d.hours {
+			if h > oldest {
+				m.recentDelivered += counts[0]
+				m.recentFailed += counts[1]
+			}
+		}
+		sinks = append(sinks, m)
+	}
+	deliveries.Unlock()
+	slices.SortFunc(sinks, func(a, b sinkMetrics) int {
+		return strings.Compare(a.key.guildID+" "+a.key.kind+" "+a.key.name, b.key.guildID+" "+b.key.kind+" "+b.key.name)
+	})
+
+	guildIDs := make([]string, 0, len(clientCounts))
+	for guildID := range clientCounts {
+		guildIDs = append(guildIDs, guildID)
+	}
+	slices.Sort(guildIDs)
+
+	var w metricsWriter
+	w.family("disgm_websocket_clients", "gauge", "Connected WebSocket clients.")
+	for _, guildID := range guildIDs {
+		w.sample("disgm_websocket_clients", clientCounts[guildID], "guild_id", guildID)
+	}
+
+	w.family("disgm_websocket_subscriptions", "gauge", "Connected WebSocket clients receiving an event type.")
+	for _, guildID := range guildIDs {
+		for _, name := range forwardedEvents {
+			if n := subscriptionCounts[guildID][name]; n > 0 {
+				w.sample("disgm_websocket_subscriptions", n, "guild_id", guildID, "event", name)
+			}
+		}
+	}
+
+	w.family("disgm_event_deliveries", "counter", "Events delivered to a sink, by result.")
+	for _, m := range sinks {
+		labels := []string{"guild_id", m.key.guildID, "kind", m.key.kind, "sink", m.key.name}
+		w.sample("disgm_event_deliveries_total", m.delivered, append(labels, "result", "delivered")...)
+		w.sample("disgm_event_deliveries_total", m.failed, append(labels, "result", "failed")...)
+	}
+
+	w.family("disgm_event_delivery_success_ratio", "gauge", "Share of the events delivered to a sink over the last 24 hours.")
+	for _, m := range sinks {
+		if total := m.recentDelivered + m.recentFailed; total > 0 {
+			w.sample("disgm_event_delivery_success_ratio", 1-rate(m.recentFailed, total), "guild_id", m.key.guildID, "kind", m.key.kind, "sink", m.key.name)
+		}
+	}
+	w.WriteString("# EOF\n")
+
+	c.Set(fiber.HeaderContentType, openMetricsContentType)
+	return c.SendString(w.String())
+}
@@ -10,24 +10,111 @@ func TokenMiddleware(disgm *Disgm, c *fiber.Ctx) error {
 	//c.Locals("ID", "561234976788447232")
 	//return c.Next()
 
-	token := c.Get("Authorization")
-	splToken := strings.Split(token, " ")
-	if splToken[0] == "Bearer" {
+	var token string
+	var fromCookie bool
 
-		if disgm.opt.TokenStore != nil {
-			tokens, err := disgm.opt.TokenStore.Load()
+	splToken := strings.Split(c.Get("Authorization"), " ")
+	if splToken[0] == "Bearer" && len(splToken) > 1 {
+		token = splToken[1]
+	} else if disgm.opt.CookieAuth {
+		token = c.Cookies(TokenCookieName) // Falls back to the auth cookie.
+		fromCookie = true
+	}
 
-			if err != nil {
-				return c.Status(fiber.StatusUnauthorized).SendString("Unauthorized")
-			}
+	// Admin tokens are not bound to a guild and only valid for the admin routes.
+	if token != "" && isAdminToken(token) {
+		if !adminPath(c) {
+			return c.Status(fiber.StatusForbidden).SendString("Forbidden: admin tokens are only valid for the admin routes")
+		}
+		c.Locals("Token", token)
+		c.Locals("Admin", true)
+		return c.Next()
+	}
+	if adminPath(c) && token != "" {
+		return adminTokenRequired(c)
+	}
+
+	// Impersonation tokens are minted by admin tokens for a guild and expire.
+	if imp, ok := impersonation(token); ok && token != "" {
+		c.Locals("ID", imp.GuildID)
+		c.Locals("Token", token)
+		c.Locals("Credential", "impersonation:"+tokenHash(token)[:16])
+		c.Locals("CookieAuth", fromCookie)
+		c.Locals("ActingUser", imp.ActingUser)
+		if imp.Scope.Restricted() {
+			c.Locals("Scope", imp.Scope)
+		}
+		if err := resolveTenant(disgm, c, token); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to resolve tenant: " + err.Error())
+		}
+		return c.Next()
+	}
+
+	// Issued tokens are additional tokens of a guild, each with its own scope.
+	if issued, ok := issuedToken(token); ok && token != "" {
+		c.Locals("ID", issued.guildID)
+		c.Locals("Token", token)
+		c.Locals("Credential", "token:"+issued.ID)
+		c.Locals("CookieAuth", fromCookie)
+		if issued.ActingUser != "" {
+			c.Locals("ActingUser", issued.ActingUser)
+		}
+		if issued.Scope.Restricted() {
+			c.Locals("Scope", issued.Scope)
+		}
+		if err := resolveTenant(disgm, c, token); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to resolve tenant: " + err.Error())
+		}
+		return c.Next()
+	}
+
+	if token != "" && disgm.opt.TokenStore != nil {
+		tokens, err := disgm.opt.TokenStore.Load()
 
-			for k, v := range tokens {
-				if v == splToken[1] {
-					c.Locals("ID", k)
-					return c.Next()
-				}
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).SendString("Unauthorized")
+		}
+
+		for k, v := range tokens {
+			if v == token {
+				c.Locals("ID", k)
+				c.Locals("Token", token)
+				c.Locals("Credential", "guild")
+				c.Locals("CookieAuth", fromCookie)
+				return scopeNext(disgm, c, token)
 			}
 		}
 	}
 	return c.Status(fiber.StatusUnauthorized).SendString("Unauthorized")
 }
+
+// scopeNext stores the scope of a restricted token, the user the token acts for and the tenant of
+// the token in the context and continues the chain.
+func scopeNext(disgm *Disgm, c *fiber.Ctx, token string) error {
+	if disgm.opt.ScopeStore != nil {
+		scopes, err := disgm.opt.ScopeStore.LoadScopes()
+
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).SendString("Unauthorized")
+		}
+
+		if scope, ok := scopes[token]; ok && scope.Restricted() {
+			c.Locals("Scope", &scope)
+		}
+	}
+	if disgm.opt.ActingUserStore != nil {
+		users, err := disgm.opt.ActingUserStore.LoadActingUsers()
+
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).SendString("Unauthorized")
+		}
+
+		if user := users[token]; user != "" {
+			c.Locals("ActingUser", user)
+		}
+	}
+	if err := resolveTenant(disgm, c, token); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to resolve tenant: " + err.Error())
+	}
+	return c.Next()
+}
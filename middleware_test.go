@@ -0,0 +1,106 @@
+package disgm_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/rif223/disgm"
+	"github.com/rif223/disgm/disgmtest"
+)
+
+const testAdminToken = "admin-token"
+
+// TestTokenMiddlewareAdminRoutes checks that only admin tokens reach the admin routes, whatever
+// the case of the path, as routes are matched case-insensitively.
+func TestTokenMiddlewareAdminRoutes(t *testing.T) {
+	ts := startServer(t, func(b *disgmtest.Backend, guild *discordgo.Guild, opt *disgm.Options) {
+		opt.AdminTokens = []string{testAdminToken}
+	})
+	impersonate := `{"acting_user":"support"}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		want   int
+	}{
+		{"guild token", http.MethodGet, "/api/admin/guilds", disgmtest.BotToken, "", http.StatusForbidden},
+		{"guild token, upper-case prefix", http.MethodGet, "/API/admin/guilds", disgmtest.BotToken, "", http.StatusForbidden},
+		{"guild token, upper-case admin", http.MethodGet, "/api/ADMIN/guilds", disgmtest.BotToken, "", http.StatusForbidden},
+		{"guild token, mixed case", http.MethodGet, "/Api/Admin/Metrics", disgmtest.BotToken, "", http.StatusForbidden},
+		{"guild token, impersonation", http.MethodPost, "/api/ADMIN/guilds/" + "111" + "/impersonate", disgmtest.BotToken, impersonate, http.StatusForbidden},
+		{"guild token, flushing the caches", http.MethodDelete, "/api/admin/caches", disgmtest.BotToken, "", http.StatusForbidden},
+		{"no token", http.MethodGet, "/API/ADMIN/guilds", "", "", http.StatusUnauthorized},
+		{"admin token", http.MethodGet, "/api/admin/guilds", testAdminToken, "", http.StatusOK},
+		{"admin token, mixed case", http.MethodGet, "/API/Admin/guilds", testAdminToken, "", http.StatusOK},
+		{"admin token, caches", http.MethodGet, "/api/admin/caches", testAdminToken, "", http.StatusOK},
+		{"guild token, status", http.MethodGet, "/api/admin/status", disgmtest.BotToken, "", http.StatusForbidden},
+		{"admin token, status", http.MethodGet, "/api/admin/status", testAdminToken, "", http.StatusOK},
+		{"admin token, guild route", http.MethodGet, "/api/guild", testAdminToken, "", http.StatusForbidden},
+		{"admin token, upper-case guild route", http.MethodGet, "/API/GUILD", testAdminToken, "", http.StatusForbidden},
+		{"guild token, guild route", http.MethodGet, "/api/guild", disgmtest.BotToken, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, body := ts.do(t, tt.method, tt.path, tt.token, tt.body); status != tt.want {
+				t.Errorf("%s %s: status %d, want %d: %s", tt.method, tt.path, status, tt.want, body)
+			}
+		})
+	}
+
+	path := "/api/admin/guilds/" + ts.Guild.ID + "/impersonate"
+	if status, body := ts.do(t, http.MethodPost, path, testAdminToken, impersonate); status != http.StatusCreated {
+		t.Errorf("admin token: impersonation status %d, want 201: %s", status, body)
+	}
+}
+
+// TestHealthProbesWithoutToken checks that the health probes are served without a token.
+func TestHealthProbesWithoutToken(t *testing.T) {
+	ts := startServer(t, nil)
+
+	for _, path := range []string{"/api/health", "/api/health/ready"} {
+		if status, body := ts.do(t, http.MethodGet, path, "", ""); status != http.StatusOK {
+			t.Errorf("GET %s without a token: status %d, want 200: %s", path, status, body)
+		}
+	}
+}
+
+// TestModuleMiddleware checks that disabled modules are rejected whatever the case of the path,
+// and that guilds cannot enable the modules disabled in the options.
+func TestModuleMiddleware(t *testing.T) {
+	var channel *discordgo.Channel
+	ts := startServer(t, func(b *disgmtest.Backend, guild *discordgo.Guild, opt *disgm.Options) {
+		channel = b.AddChannel(&discordgo.Channel{GuildID: guild.ID, Name: "general", Type: discordgo.ChannelTypeGuildText})
+		opt.DisabledModules = []string{disgm.ModuleAnalytics}
+	})
+	messages := "/guild/channels/" + channel.ID + "/messages"
+
+	for _, path := range []string{"/api/guild/ws/clients", "/api/Guild/ws/clients", "/API/GUILD/WS/CLIENTS", "/api/guild/ws/clients/"} {
+		if status, body := ts.do(t, http.MethodGet, path, disgmtest.BotToken, ""); status != http.StatusForbidden {
+			t.Errorf("GET %s: status %d, want 403: %s", path, status, body)
+		}
+	}
+
+	if status, body := ts.do(t, http.MethodPut, "/api/guild/modules/analytics", disgmtest.BotToken, `{"enabled":true}`); status != http.StatusForbidden {
+		t.Errorf("enabling an operator-disabled module: status %d, want 403: %s", status, body)
+	}
+	if status, body := ts.do(t, http.MethodGet, "/api/guild/ws/clients", disgmtest.BotToken, ""); status != http.StatusForbidden {
+		t.Errorf("GET /api/guild/ws/clients after enabling: status %d, want 403: %s", status, body)
+	}
+
+	// Modules enabled in the options can still be disabled per guild.
+	if status, body := ts.do(t, http.MethodGet, "/api"+messages, disgmtest.BotToken, ""); status != http.StatusOK {
+		t.Fatalf("GET %s: status %d, want 200: %s", messages, status, body)
+	}
+	if status, body := ts.do(t, http.MethodPut, "/api/guild/modules/messages", disgmtest.BotToken, `{"enabled":false}`); status != http.StatusOK {
+		t.Fatalf("disabling a module: status %d, want 200: %s", status, body)
+	}
+	for _, path := range []string{"/api" + messages, "/API/Guild/Channels/" + channel.ID + "/Messages"} {
+		if status, body := ts.do(t, http.MethodGet, path, disgmtest.BotToken, ""); status != http.StatusForbidden {
+			t.Errorf("GET %s: status %d, want 403: %s", path, status, body)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package disgm
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MirrorHeader marks the requests disgm mirrors to the MirrorURL.
+const MirrorHeader = "X-Disgm-Mirror"
+
+const (
+	mirrorQueueSize = 256 // Mirrored requests waiting to be sent, newer requests are dropped when full.
+	mirrorWorkers   = 4   // Goroutines sending mirrored requests.
+)
+
+// mirroredRequest is a copy of an incoming request, detached from the reused fasthttp buffers.
+type mirroredRequest struct {
+	method string
+	uri    string
+	header http.Header
+	body   []byte
+}
+
+// mirrorClient sends the mirrored requests. Responses are discarded.
+var mirrorClient = &http.Client{Timeout: 10 * time.Second}
+
+// newMirror starts the workers that replay the queued requests against the base URL and returns
+// a middleware that queues a copy of every API request without delaying it.
+func newMirror(baseURL string) fiber.Handler {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	queue := make(chan mirroredRequest, mirrorQueueSize)
+
+	for i := 0; i < mirrorWorkers; i++ {
+		go func() {
+			for m := range queue {
+				req, err := http.NewRequest(m.method, baseURL+m.uri, bytes.NewReader(m.body))
+				if err != nil {
+					continue
+				}
+				req.Header = m.header
+				resp, err := mirrorClient.Do(req)
+				if err != nil {
+					log.Printf("Failed to mirror request: %v", err)
+					continue
+				}
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !strings.HasPrefix(c.Path(), "/api/") {
+			return c.Next()
+		}
+
+		m := mirroredRequest{
+			method: c.Method(),
+			uri:    string(c.Request().RequestURI()),
+			header: make(http.Header),
+			body:   bytes.Clone(c.Body()),
+		}
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			m.header.Add(string(key), string(value))
+		})
+		m.header.Set(MirrorHeader, "true")
+		m.header.Set("X-Forwarded-For", c.IP())
+
+		select {
+		case queue <- m:
+		default:
+			// Drops the copy rather than slowing down production traffic.
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,141 @@
+package disgm
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Limits of modals, as enforced by Discord.
+const (
+	maxModalCustomID    = 100
+	maxModalTitle       = 45
+	maxModalRows        = 5
+	maxTextInputLabel   = 45
+	maxTextInputLength  = 4000
+	maxTextInputPreview = 100 // Length of the placeholder of a text input.
+)
+
+// ModalParams is a modal shown in reply to an interaction, the data of a callback of type 9.
+type ModalParams struct {
+	CustomID   string     `json:"custom_id"`  // ID of the modal, sent back with the submission, 1-100 characters
+	Title      string     `json:"title"`      // Title of the modal, 1-45 characters
+	Components []ModalRow `json:"components"` // 1-5 action rows with one text input each
+}
+
+// ModalRow is an action row of a modal.
+type ModalRow struct {
+	Type       int              `json:"type"`       // Always 1, an action row
+	Components []ModalTextInput `json:"components"` // Exactly one text input
+}
+
+// ModalTextInput is a text input of a modal.
+type ModalTextInput struct {
+	Type        int    `json:"type"`                  // Always 4, a text input
+	CustomID    string `json:"custom_id"`             // ID of the input, unique in the modal, 1-100 characters
+	Style       int    `json:"style"`                 // 1 for a single line, 2 for a paragraph
+	Label       string `json:"label"`                 // Label of the input, 1-45 characters
+	MinLength   *int   `json:"min_length,omitempty"`  // Minimum length of the input, 0-4000
+	MaxLength   *int   `json:"max_length,omitempty"`  // Maximum length of the input, 1-4000
+	Required    *bool  `json:"required,omitempty"`    // Whether the input must be filled, defaults to true
+	Value       string `json:"value,omitempty"`       // Prefilled value, up to 4000 characters
+	Placeholder string `json:"placeholder,omitempty"` // Placeholder shown while empty, up to 100 characters
+}
+
+// validate checks a modal against the limits of Discord, naming the first invalid field.
+func (m *ModalParams) validate() error {
+	if n := utf8.RuneCountInString(m.CustomID); n < 1 || n > maxModalCustomID {
+		return fmt.Errorf("custom_id must have 1-%d characters", maxModalCustomID)
+	}
+	if n := utf8.RuneCountInString(m.Title); n < 1 || n > maxModalTitle {
+		return fmt.Errorf("title must have 1-%d characters", maxModalTitle)
+	}
+	if len(m.Components) < 1 || len(m.Components) > maxModalRows {
+		return fmt.Errorf("components must have 1-%d action rows", maxModalRows)
+	}
+
+	ids := make(map[string]bool)
+	for i, row := range m.Components {
+		if row.Type != int(discordgo.ActionsRowComponent) {
+			return fmt.Errorf("components[%d] must be an action row (type 1)", i)
+		}
+		if len(row.Components) != 1 {
+			return fmt.Errorf("components[%d] must have exactly one text input", i)
+		}
+		if err := row.Components[0].validate(); err != nil {
+			return fmt.Errorf("components[%d].components[0]: %w", i, err)
+		}
+		id := row.Components[0].CustomID
+		if ids[id] {
+			return fmt.Errorf("components[%d].components[0]: custom_id %q is used twice", i, id)
+		}
+		ids[id] = true
+	}
+	return nil
+}
+
+// validate checks a text input against the limits of Discord.
+func (t *ModalTextInput) validate() error {
+	if t.Type != int(discordgo.TextInputComponent) {
+		return errors.New("must be a text input (type 4)")
+	}
+	if n := utf8.RuneCountInString(t.CustomID); n < 1 || n > maxModalCustomID {
+		return fmt.Errorf("custom_id must have 1-%d characters", maxModalCustomID)
+	}
+	if t.Style != int(discordgo.TextInputShort) && t.Style != int(discordgo.TextInputParagraph) {
+		return errors.New("style must be 1 (short) or 2 (paragraph)")
+	}
+	if n := utf8.RuneCountInString(t.Label); n < 1 || n > maxTextInputLabel {
+		return fmt.Errorf("label must have 1-%d characters", maxTextInputLabel)
+	}
+	if t.MinLength != nil && (*t.MinLength < 0 || *t.MinLength > maxTextInputLength) {
+		return fmt.Errorf("min_length must be 0-%d", maxTextInputLength)
+	}
+	if t.MaxLength != nil && (*t.MaxLength < 1 || *t.MaxLength > maxTextInputLength) {
+		return fmt.Errorf("max_length must be 1-%d", maxTextInputLength)
+	}
+	if t.MinLength != nil && t.MaxLength != nil && *t.MinLength > *t.MaxLength {
+		return errors.New("min_length must not exceed max_length")
+	}
+	maxValue := maxTextInputLength
+	if t.MaxLength != nil {
+		maxValue = *t.MaxLength
+	}
+	if utf8.RuneCountInString(t.Value) > maxValue {
+		return fmt.Errorf("value must have at most %d characters", maxValue)
+	}
+	if utf8.RuneCountInString(t.Placeholder) > maxTextInputPreview {
+		return fmt.Errorf("placeholder must have at most %d characters", maxTextInputPreview)
+	}
+	return nil
+}
+
+// response returns the interaction response showing the modal.
+func (m *ModalParams) response() *discordgo.InteractionResponse {
+	rows := make([]discordgo.MessageComponent, len(m.Components))
+	for i, row := range m.Components {
+		input := row.Components[0]
+		text := discordgo.TextInput{
+			CustomID:    input.CustomID,
+			Label:       input.Label,
+			Style:       discordgo.TextInputStyle(input.Style),
+			Placeholder: input.Placeholder,
+			Value:       input.Value,
+			Required:    input.Required == nil || *input.Required,
+		}
+		if input.MinLength != nil {
+			text.MinLength = *input.MinLength
+		}
+		if input.MaxLength != nil {
+			text.MaxLength = *input.MaxLength
+		}
+		rows[i] = discordgo.ActionsRow{Components: []discordgo.MessageComponent{text}}
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{CustomID: m.CustomID, Title: m.Title, Components: rows},
+	}
+}
@@ -17,8 +17,8 @@ type ApplicationCommand struct {
 	DMPermission             *bool                       `json:"dm_permission,omitempty"`              // Deprecated: Indicates if the command is available in DMs for global commands
 	DefaultPermission        *bool                       `json:"default_permission,omitempty"`         // Deprecated: Indicates if the command is enabled by default when the app is added to a guild
 	NSFW                     *bool                       `json:"nsfw,omitempty"`                       // Indicates whether the command is age-restricted, defaults to false
-	IntegrationTypes         []string                    `json:"integration_types,omitempty"`          // Installation contexts for the command (global commands)
-	Contexts                 *[]string                   `json:"contexts,omitempty"`                   // Interaction context(s) where the command can be used (global commands)
+	IntegrationTypes         *[]int                      `json:"integration_types,omitempty"`          // Installation contexts where the command is available (0 = guild install, 1 = user install), global commands only
+	Contexts                 *[]int                      `json:"contexts,omitempty"`                   // Interaction contexts where the command can be used (0 = guild, 1 = bot DM, 2 = private channel), global commands only
 	Version                  string                      `json:"version"`                              // Auto-incrementing version identifier updated during substantial record changes
 	Handler                  *string                     `json:"handler,omitempty"`                    // Determines whether the interaction is handled by the app's handler or by Discord
 }
@@ -212,27 +212,35 @@ type Role struct {
 // Emoji represents an emoji object in the guild.
 type Emoji struct {
 	// Define fields for Emoji structure based on your needs
-	ID             string `json:"id"`              // Snowflake ID of the emoji
-	Name           string `json:"name"`            // Name of the emoji
-	Roles          []Role `json:"roles"`           // Roles allowed to use the emoji
-	User           *User  `json:"user,omitempty"`  // Optional user object that created the emoji
-	RequiresColons bool   `json:"requires_colons"` // Whether the emoji requires colons
-	Managed        bool   `json:"managed"`         // Whether the emoji is managed by an application
-	Animated       bool   `json:"animated"`        // Whether the emoji is animated
+	ID             string   `json:"id"`             // Snowflake ID of the emoji
+	Name           string   `json:"name"`           // Name of the emoji
+	Roles          []string `json:"roles"`          // IDs of the roles allowed to use the emoji
+	User           *User    `json:"user,omitempty"` // Optional user object that created the emoji
+	RequiresColons bool     `json:"require_colons"` // Whether the emoji requires colons
+	Managed        bool     `json:"managed"`        // Whether the emoji is managed by an application
+	Animated       bool     `json:"animated"`       // Whether the emoji is animated
+	Available      bool     `json:"available"`      // Whether the emoji can be used, false if lost due to boost removal
+}
+
+// EmojiParams structure representing the parameters to create or edit a guild emoji.
+type EmojiParams struct {
+	Name  string   `json:"name,omitempty"`  // Name of the emoji
+	Image string   `json:"image,omitempty"` // Base64 image or data URI of the emoji, max 256 KB, only on creation
+	Roles []string `json:"roles,omitempty"` // IDs of the roles allowed to use the emoji, empty for everyone
 }
 
 // WelcomeScreen represents the welcome screen for community guilds.
 type WelcomeScreen struct {
-	// Define fields relevant to the welcome screen as needed
-	Description     *string          `json:"description,omitempty"` // Optional description for welcome screen
-	WelcomeChannels []WelcomeChannel `json:"welcome_channels"`      // Channels to show on the welcome screen
+	Description     *string          `json:"description"`      // Description of the guild shown on the welcome screen, max 140 characters
+	WelcomeChannels []WelcomeChannel `json:"welcome_channels"` // Up to 5 channels to show on the welcome screen
 }
 
 // WelcomeChannel represents a channel in the welcome screen.
 type WelcomeChannel struct {
-	ChannelID   string  `json:"channel_id"`            // ID of the channel
-	Description *string `json:"description,omitempty"` // Optional description for the channel
-	Emoji       *string `json:"emoji,omitempty"`       // Optional emoji for the channel
+	ChannelID   string  `json:"channel_id"`  // ID of the channel
+	Description string  `json:"description"` // Description of the channel, max 50 characters
+	EmojiID     *string `json:"emoji_id"`    // ID of the custom emoji of the channel, if any
+	EmojiName   *string `json:"emoji_name"`  // Name of the custom emoji, or the unicode emoji of the channel
 }
 
 // Sticker represents a sticker object in the guild.
@@ -243,6 +251,38 @@ type Sticker struct {
 	Name        string  `json:"name"`                  // Name of the sticker
 	FormatType  int     `json:"format_type"`           // Format type of the sticker
 	Description *string `json:"description,omitempty"` // Optional description of the sticker
+	Tags        string  `json:"tags"`                  // Autocomplete and suggestion tags of the sticker
+	Type        int     `json:"type"`                  // Type of the sticker (1 = standard, 2 = guild)
+	Available   bool    `json:"available"`             // Whether the sticker can be used, false if lost due to boost removal
+	GuildID     string  `json:"guild_id"`              // ID of the guild the sticker belongs to
+	User        *User   `json:"user,omitempty"`        // Optional user object that uploaded the sticker
+}
+
+// StickerParams structure representing the parameters to edit a guild sticker.
+type StickerParams struct {
+	Name        string  `json:"name,omitempty"`        // Name of the sticker, 2-30 characters
+	Description *string `json:"description,omitempty"` // Description of the sticker, empty or 2-100 characters
+	Tags        string  `json:"tags,omitempty"`        // Autocomplete and suggestion tags, max 200 characters
+}
+
+// Webhook represents a webhook of a channel.
+type Webhook struct {
+	ID            string `json:"id"`                       // Snowflake ID of the webhook
+	Type          int    `json:"type"`                     // Type of the webhook (1 = incoming, 2 = channel follower, 3 = application)
+	GuildID       string `json:"guild_id"`                 // ID of the guild the webhook belongs to
+	ChannelID     string `json:"channel_id"`               // ID of the channel the webhook posts to
+	User          *User  `json:"user,omitempty"`           // Optional user object that created the webhook
+	Name          string `json:"name"`                     // Default name of the webhook
+	Avatar        string `json:"avatar"`                   // Default avatar hash of the webhook
+	Token         string `json:"token,omitempty"`          // Secure token of incoming webhooks
+	ApplicationID string `json:"application_id,omitempty"` // ID of the application that created the webhook
+}
+
+// WebhookParams structure representing the parameters to create or edit a channel webhook.
+type WebhookParams struct {
+	Name      string `json:"name,omitempty"`       // Default name of the webhook, 1-80 characters
+	Avatar    string `json:"avatar,omitempty"`     // Base64 image or data URI of the default avatar
+	ChannelID string `json:"channel_id,omitempty"` // ID of the channel to move the webhook to, only on edit
 }
 
 type GuildBan struct {
@@ -319,7 +359,8 @@ type Reaction struct {
 
 // CountDetails structure representing details about reaction counts.
 type CountDetails struct {
-	// Add specific fields for count details if needed
+	Burst  int `json:"burst"`  // Count of super reactions
+	Normal int `json:"normal"` // Count of normal reactions
 }
 
 // PartialEmoji structure representing an emoji used in reactions.
@@ -339,3 +380,80 @@ type RoleParams struct {
     UnicodeEmoji  string `json:"unicode_emoji,omitempty"` // The role's unicode emoji as a standard emoji (if the guild has the ROLE_ICONS feature)
     Mentionable   bool   `json:"mentionable"`       // Whether the role should be mentionable
 }
+
+// AutoModRule structure representing an AutoMod rule of a guild.
+type AutoModRule struct {
+	ID              string                  `json:"id,omitempty"`               // Snowflake ID of the rule
+	GuildID         string                  `json:"guild_id,omitempty"`         // ID of the guild the rule belongs to
+	Name            string                  `json:"name,omitempty"`             // Name of the rule
+	CreatorID       string                  `json:"creator_id,omitempty"`       // ID of the user who created the rule
+	EventType       int                     `json:"event_type,omitempty"`       // Event the rule is checked on (1 = message send)
+	TriggerType     int                     `json:"trigger_type,omitempty"`     // Type of trigger (1 = keyword, 3 = spam, 4 = keyword preset, 5 = mention spam)
+	TriggerMetadata *AutoModTriggerMetadata `json:"trigger_metadata,omitempty"` // Optional settings of the trigger
+	Actions         []AutoModAction         `json:"actions,omitempty"`          // Actions taken when the rule is triggered
+	Enabled         *bool                   `json:"enabled,omitempty"`          // Whether the rule is enabled
+	ExemptRoles     *[]string               `json:"exempt_roles,omitempty"`     // IDs of the roles not affected by the rule, max 20
+	ExemptChannels  *[]string               `json:"exempt_channels,omitempty"`  // IDs of the channels not affected by the rule, max 50
+}
+
+// AutoModTriggerMetadata structure representing the settings of an AutoMod rule trigger.
+type AutoModTriggerMetadata struct {
+	KeywordFilter                []string `json:"keyword_filter,omitempty"`                  // Substrings matched in the content, for keyword rules
+	RegexPatterns                []string `json:"regex_patterns,omitempty"`                  // Regular expressions matched in the content, for keyword rules
+	Presets                      []int    `json:"presets,omitempty"`                         // Predefined word lists (1 = profanity, 2 = sexual content, 3 = slurs)
+	AllowList                    []string `json:"allow_list,omitempty"`                      // Substrings exempt from the rule
+	MentionTotalLimit            int      `json:"mention_total_limit,omitempty"`             // Unique role and user mentions allowed per message
+	MentionRaidProtectionEnabled bool     `json:"mention_raid_protection_enabled,omitempty"` // Whether to detect mention raids
+}
+
+// AutoModAction structure representing an action taken when an AutoMod rule is triggered.
+type AutoModAction struct {
+	Type     int                    `json:"type"`               // Type of action (1 = block message, 2 = send alert, 3 = timeout)
+	Metadata *AutoModActionMetadata `json:"metadata,omitempty"` // Optional settings of the action
+}
+
+// AutoModActionMetadata structure representing the settings of an AutoMod action.
+type AutoModActionMetadata struct {
+	ChannelID     string `json:"channel_id,omitempty"`       // ID of the channel alerts are sent to
+	Duration      int    `json:"duration_seconds,omitempty"` // Timeout duration in seconds, max 4 weeks
+	CustomMessage string `json:"custom_message,omitempty"`   // Message shown to the member whose message is blocked
+}
+
+// ThreadsList structure representing a list of threads with the thread members of the bot.
+type ThreadsList struct {
+	Threads []Channel      `json:"threads"`  // The threads
+	Members []ThreadMember `json:"members"`  // Thread members of the bot for the threads it joined
+	HasMore bool           `json:"has_more"` // Whether more threads can be paged, for archived threads
+}
+
+// ScheduledEventUser structure representing a user interested in a scheduled event.
+type ScheduledEventUser struct {
+	GuildScheduledEventID string  `json:"guild_scheduled_event_id"` // ID of the scheduled event
+	User                  *User   `json:"user"`                     // The interested user
+	Member                *Member `json:"member,omitempty"`         // Optional guild member of the user, with with_member
+}
+
+// VoiceRegion structure representing a voice region of a guild.
+type VoiceRegion struct {
+	ID         string `json:"id"`         // Unique ID of the region, used as rtc_region of voice channels
+	Name       string `json:"name"`       // Name of the region
+	Optimal    bool   `json:"optimal"`    // Whether the region is the closest to the bot
+	Deprecated bool   `json:"deprecated"` // Whether the region is deprecated and should be avoided
+	Custom     bool   `json:"custom"`     // Whether the region is custom, e.g. for events
+}
+
+// Invite structure representing an invite to a channel of a guild.
+type Invite struct {
+	Code      string   `json:"code"`       // Code of the invite, as in discord.gg/<code>
+	Channel   *Channel `json:"channel"`    // Partial channel the invite leads to
+	Inviter   *User    `json:"inviter"`    // User who created the invite, if any
+	Uses      int      `json:"uses"`       // Number of times the invite was used
+	MaxUses   int      `json:"max_uses"`   // Maximum number of uses, 0 for unlimited
+	MaxAge    int      `json:"max_age"`    // Duration in seconds the invite is valid, 0 for never expiring
+	Temporary bool     `json:"temporary"`  // Whether the invite grants temporary membership
+	CreatedAt string   `json:"created_at"` // ISO8601 timestamp of the creation of the invite
+	ExpiresAt *string  `json:"expires_at"` // ISO8601 timestamp the invite expires at, null if never
+
+	ApproximatePresenceCount int `json:"approximate_presence_count,omitempty"` // Online members of the guild, with with_counts
+	ApproximateMemberCount   int `json:"approximate_member_count,omitempty"`   // Members of the guild, with with_counts
+}
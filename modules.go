@@ -0,0 +1,294 @@
+package disgm
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// API modules that can be enabled or disabled per guild.
+const (
+	ModuleMessages   = "messages"   // Reading, sending and searching messages, transcripts and polls.
+	ModuleModeration = "moderation" // Bans, bulk bans, AutoMod rules and kicking or editing members.
+	ModuleWebhooks   = "webhooks"   // Channel webhooks, webhook execution, inbound hooks and relays.
+	ModuleAnalytics  = "analytics"  // Event export and replay, WebSocket clients and latency, delivery reports and channel history.
+)
+
+// Modules lists the API modules that can be toggled, in display order.
+var Modules = []string{ModuleMessages, ModuleModeration, ModuleWebhooks, ModuleAnalytics}
+
+// moduleRoute assigns the routes matching a pattern to a module, see matchRoute. An empty
+// method matches all methods.
+type moduleRoute struct {
+	module  string
+	method  string
+	pattern string
+}
+
+// moduleRoutes maps the API routes to their modules.
+var moduleRoutes = []moduleRoute{
+	{ModuleMessages, "", "/guild/channels/:/messages*"},
+	{ModuleMessages, "", "/guild/channels/:/transcript"},
+	{ModuleMessages, "", "/guild/search/messages"},
+	{ModuleMessages, "", "/guild/polls*"},
+	{ModuleModeration, "", "/guild/bans*"},
+	{ModuleModeration, "", "/guild/bulk-ban"},
+	{ModuleModeration, "", "/guild/automod*"},
+	{ModuleModeration, "", "/guild/audit-logs"},
+	{ModuleModeration, fiber.MethodPatch, "/guild/members/:"},
+	{ModuleModeration, fiber.MethodDelete, "/guild/members/:"},
+	{ModuleWebhooks, "", "/guild/channels/:/webhooks*"},
+	{ModuleWebhooks, "", "/guild/channels/:/impersonated-messages"},
+	{ModuleWebhooks, "", "/guild/webhooks*"},
+	{ModuleWebhooks, "", "/guild/hooks*"},
+	{ModuleWebhooks, "", "/guild/relays*"},
+	{ModuleAnalytics, "", "/guild/events*"},
+	{ModuleAnalytics, "", "/guild/ws/clients*"},
+	{ModuleAnalytics, "", "/guild/ws/latency"},
+	{ModuleAnalytics, "", "/guild/delivery-report"},
+	{ModuleAnalytics, "", "/guild/channels/:/history"},
+}
+
+// ModuleState reports whether a module is enabled for a guild.
+type ModuleState struct {
+	Module  string `json:"module"`
+	Enabled bool   `json:"enabled"`
+}
+
+// The module toggles: modules disabled for all guilds in the options, and per-guild overrides stored
+// in the key-value store keyed by guild ID.
+var modules = struct {
+	sync.Mutex
+	disabled []string
+	storage  *Storage
+}{}
+
+// matches reports whether a route pattern matches a request.
+func (r moduleRoute) matches(method, path string) bool {
+	return (r.method == "" || r.method == method) && matchRoute(r.pattern, path)
+}
+
+// matchRoute reports whether a path below /api matches a pattern, where ":" segments match any
+// value and a trailing "*" matches any rest. Segments are compared case-insensitively, as routes
+// are matched. It walks the segments in place, as it runs for every request.
+func matchRoute(pattern, path string) bool {
+	pattern, prefix := strings.CutSuffix(pattern, "*")
+	path = strings.TrimSuffix(path, "/")
+	for {
+		want, patternRest, patternMore := strings.Cut(pattern, "/")
+		got, pathRest, pathMore := strings.Cut(path, "/")
+		if want != ":" && !strings.EqualFold(want, got) {
+			return false
+		}
+		if !patternMore {
+			return !pathMore || prefix
+		}
+		if !pathMore {
+			return false
+		}
+		pattern, path = patternRest, pathRest
+	}
+}
+
+// routeModule returns the module of an API route, or an empty string for routes that are always enabled.
+func routeModule(method, path string) string {
+	for _, r := range moduleRoutes {
+		if r.matches(method, path) {
+			return r.module
+		}
+	}
+	return ""
+}
+
+// moduleOverrides reads the module overrides of a guild.
+func moduleOverrides(guildID string) (map[string]bool, error) {
+	modules.Lock()
+	storage := modules.storage
+	modules.Unlock()
+
+	overrides := make(map[string]bool)
+	if storage == nil {
+		return overrides, nil
+	}
+	value, ok, err := storage.Get(guildID)
+	if err != nil || !ok {
+		return overrides, err
+	}
+	return overrides, json.Unmarshal(value, &overrides)
+}
+
+// operatorDisabled reports whether a module is disabled for all guilds by the DisabledModules option.
+func operatorDisabled(module string) bool {
+	modules.Lock()
+	defer modules.Unlock()
+	return slices.Contains(modules.disabled, module)
+}
+
+// moduleEnabled reports whether a module is enabled for a guild. Modules disabled in the options
+// stay disabled whatever the overrides of the guild.
+func moduleEnabled(guildID, module string) (bool, error) {
+	if operatorDisabled(module) {
+		return false, nil
+	}
+
+	overrides, err := moduleOverrides(guildID)
+	if err != nil {
+		return false, err
+	}
+	if enabled, ok := overrides[module]; ok {
+		return enabled, nil
+	}
+	return true, nil
+}
+
+// ModuleMiddleware rejects requests to API modules that are disabled for the guild of the token
+// or not included in the plan of its tenant.
+func ModuleMiddleware(c *fiber.Ctx) error {
+	guildID, _ := c.Locals("ID").(string)
+	path := c.Path()
+	if guildID == "" || len(path) < len("/api") || !strings.EqualFold(path[:len("/api")], "/api") {
+		return c.Next()
+	}
+	path = path[len("/api"):]
+
+	module := routeModule(c.Method(), path)
+	if module == "" {
+		return c.Next()
+	}
+
+	if denied, err := moduleDenied(c, guildID, module); denied {
+		return err
+	}
+	return c.Next()
+}
+
+// moduleDenied checks that a module is included in the plan of the tenant of a request and
+// enabled for its guild, and responds with HTTP status 403 (Forbidden) if not. Handlers whose
+// module depends on the request body call it themselves, as the middleware only knows the route.
+func moduleDenied(c *fiber.Ctx, guildID, module string) (bool, error) {
+	tenant := requestTenant(c.Locals("Tenant"))
+	if !planIncludes(tenant, module) {
+		return true, c.Status(fiber.StatusForbidden).SendString("Forbidden: the " + module + " module is not included in the " + tenant.Plan() + " plan")
+	}
+
+	enabled, err := moduleEnabled(guildID, module)
+	if err != nil {
+		return true, c.Status(fiber.StatusInternalServerError).SendString("Failed to read modules: " + err.Error())
+	}
+	if !enabled {
+		return true, c.Status(fiber.StatusForbidden).SendString("Forbidden: the " + module + " module is disabled for this guild")
+	}
+	return false, nil
+}
+
+// GetGuildModules retrieves the API modules and whether they are enabled for the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the modules with their state as a JSON array.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) if the overrides cannot be read.
+// @Summary		Get Guild Modules
+// @Description	Retrieve the API modules and whether they are enabled for the guild.
+// @Tags			Guild
+// @Success		200	{array}		ModuleState
+// @Failure		500	{object}	error
+// @Router			/api/guild/modules [get]
+func GetGuildModules(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	states := make([]ModuleState, 0, len(Modules))
+	for _, module := range Modules {
+		enabled, err := moduleEnabled(guildID, module)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to read modules: " + err.Error())
+		}
+		enabled = enabled && planIncludes(requestTenant(c.Locals("Tenant")), module)
+		states = append(states, ModuleState{Module: module, Enabled: enabled})
+	}
+
+	return c.JSON(states)
+}
+
+// SetGuildModule enables or disables an API module for the guild.
+//
+// Modules disabled for all guilds by the DisabledModules option cannot be enabled per guild.
+// Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - enabled: Whether the module is enabled.
+//
+// Returns:
+//   - On success, it returns the new state of the module as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) for tokens restricted to channels or modules disabled in the options,
+//     HTTP status 404 (Not Found) if the module does not exist,
+//     or HTTP status 500 (Internal Server Error) if the setting cannot be stored.
+// @Summary		Set Guild Module
+// @Description	Enable or disable an API module for the guild.
+// @Tags			Guild
+// @Param			module	path		string		true	"Module"	Enums(messages, moderation, webhooks, analytics)
+// @Param			body	body		ModuleState	true	"Module state"
+// @Success		200		{object}	ModuleState
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/modules/{module} [put]
+func SetGuildModule(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	module := c.Params("module")
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+	if !slices.Contains(Modules, module) {
+		return c.Status(fiber.StatusNotFound).SendString("Module not found")
+	}
+	if operatorDisabled(module) {
+		return c.Status(fiber.StatusForbidden).SendString("Forbidden: the " + module + " module is disabled for all guilds")
+	}
+
+	var state ModuleState
+	if err := c.BodyParser(&state); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	state.Module = module
+
+	modules.Lock()
+	defer modules.Unlock()
+
+	err := modules.storage.Update(func(tx *StorageTx) error {
+		overrides := make(map[string]bool)
+		value, ok, err := tx.Get(guildID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := json.Unmarshal(value, &overrides); err != nil {
+				return err
+			}
+		}
+		overrides[module] = state.Enabled
+
+		if value, err = json.Marshal(overrides); err != nil {
+			return err
+		}
+		return tx.Set(guildID, value)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store module: " + err.Error())
+	}
+
+	return c.JSON(state)
+}
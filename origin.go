@@ -0,0 +1,60 @@
+package disgm
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// originAllowed reports whether an origin matches one of the allowed origins. An allowed origin
+// is "*" for any origin, a scheme and host like "https://app.example.com", or a scheme and host
+// with a leading wildcard label like "https://*.example.com", which matches every subdomain but
+// not example.com itself. Ports must match exactly.
+func originAllowed(origin string, allowed []string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+		scheme, host, ok := strings.Cut(pattern, "://*.")
+		if !ok {
+			continue
+		}
+		prefix := scheme + "://"
+		if strings.HasPrefix(origin, prefix) {
+			sub, found := strings.CutSuffix(origin[len(prefix):], "."+host)
+			if found && sub != "" && !strings.ContainsAny(sub, "/:") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WebSocketOriginMiddleware refuses WebSocket upgrades from browsers on origins that are not
+// allowed.
+//
+// CORS does not apply to WebSocket connections, so any website could otherwise connect a browser
+// to disgm with a leaked token, for example through the auth cookie. Requests without an Origin
+// header come from clients other than browsers and are always allowed, as are requests from the
+// origin disgm is served on.
+//
+// Parameters:
+//   - allowed: []string – The allowed origins, see Options.WebSocketOrigins. All origins are allowed
+//     if it is empty.
+//
+// Returns:
+//   - A handler that continues the chain for allowed origins, or responds with HTTP status 403
+//     (Forbidden) otherwise.
+func WebSocketOriginMiddleware(allowed []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		if len(allowed) == 0 || origin == "" || strings.EqualFold(origin, c.BaseURL()) || originAllowed(origin, allowed) {
+			return c.Next()
+		}
+		log.Printf("Refused WebSocket connection from origin %s", origin)
+		return c.Status(fiber.StatusForbidden).SendString("Origin not allowed")
+	}
+}
@@ -0,0 +1,370 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EveryoneOverwrite is the overwrite ID of templates standing for the @everyone role of the guild
+// the template is applied in.
+const EveryoneOverwrite = "@everyone"
+
+// maxTemplateChannels is the maximum number of channels a template is applied to per request.
+const maxTemplateChannels = 50
+
+// OverwriteTemplate is a named set of permission overwrites, e.g. "staff-only" or "read-only",
+// that can be applied to many channels at once.
+type OverwriteTemplate struct {
+	Name       string                           `json:"name"`                                  // Name of the template, lowercase letters, digits, dashes and underscores
+	Overwrites []*discordgo.PermissionOverwrite `json:"overwrites" swaggertype:"array,object"` // Overwrites set on the channels, "@everyone" as ID for the everyone role
+	Exclusive  bool                             `json:"exclusive"`                             // Whether applying removes the other overwrites of the channels
+}
+
+// ApplyTemplateRequest lists the channels to apply an overwrite template to.
+type ApplyTemplateRequest struct {
+	ChannelIDs []string `json:"channel_ids"`
+}
+
+// ApplyTemplateResult lists the channels an overwrite template was applied to.
+type ApplyTemplateResult struct {
+	Applied []string `json:"applied"`
+}
+
+// The overwrite templates of the guilds, stored in the key-value store keyed by "<guild>/<name>".
+var overwriteTemplates = struct {
+	sync.Mutex
+	storage *Storage
+}{}
+
+// templateStorage returns the storage of the overwrite templates.
+func templateStorage() *Storage {
+	overwriteTemplates.Lock()
+	defer overwriteTemplates.Unlock()
+	return overwriteTemplates.storage
+}
+
+// loadTemplate reads an overwrite template of a guild.
+func loadTemplate(guildID, name string) (*OverwriteTemplate, bool, error) {
+	value, ok, err := templateStorage().Get(guildID + "/" + name)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	var tmpl OverwriteTemplate
+	if err := json.Unmarshal(value, &tmpl); err != nil {
+		return nil, false, err
+	}
+	return &tmpl, true, nil
+}
+
+// validate checks the name and overwrites of a template.
+func (t *OverwriteTemplate) validate() error {
+	if !pluginNamePattern.MatchString(t.Name) {
+		return fmt.Errorf("invalid template name %q", t.Name)
+	}
+	seen := make(map[string]bool)
+	for _, o := range t.Overwrites {
+		if o == nil || o.ID == "" {
+			return fmt.Errorf("overwrites need an id")
+		}
+		if o.Type != discordgo.PermissionOverwriteTypeRole && o.Type != discordgo.PermissionOverwriteTypeMember {
+			return fmt.Errorf("invalid type of overwrite %s", o.ID)
+		}
+		if seen[o.ID] {
+			return fmt.Errorf("duplicate overwrite %s", o.ID)
+		}
+		seen[o.ID] = true
+	}
+	return nil
+}
+
+// overwritesFor returns the overwrites of a channel after applying the template.
+func (t *OverwriteTemplate) overwritesFor(guildID string, channel *discordgo.Channel) []*discordgo.PermissionOverwrite {
+	var result []*discordgo.PermissionOverwrite
+	ids := make(map[string]bool)
+	for _, o := range t.Overwrites {
+		o := *o
+		if o.ID == EveryoneOverwrite {
+			o.ID = guildID
+		}
+		ids[o.ID] = true
+		result = append(result, &o)
+	}
+
+	if !t.Exclusive {
+		for _, o := range channel.PermissionOverwrites {
+			if !ids[o.ID] {
+				result = append(result, o)
+			}
+		}
+	}
+	return result
+}
+
+// setChannelOverwrites replaces all permission overwrites of a channel in a single request.
+func setChannelOverwrites(s *discordgo.Session, channelID string, overwrites []*discordgo.PermissionOverwrite, options ...discordgo.RequestOption) error {
+	if overwrites == nil {
+		overwrites = []*discordgo.PermissionOverwrite{}
+	}
+	endpoint := discordgo.EndpointChannel(channelID)
+	_, err := s.RequestWithBucketID("PATCH", endpoint, map[string]interface{}{"permission_overwrites": overwrites}, endpoint, options...)
+	return err
+}
+
+// GetOverwriteTemplates retrieves the permission overwrite templates of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the templates as a JSON array, sorted by name.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) if the templates cannot be read.
+// @Summary		Get Overwrite Templates
+// @Description	Retrieve the permission overwrite templates of the guild.
+// @Tags			Channels
+// @Success		200	{array}		OverwriteTemplate
+// @Failure		500	{object}	error
+// @Router			/api/guild/overwrite-templates [get]
+func GetOverwriteTemplates(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	templates := []OverwriteTemplate{}
+	err := templateStorage().View(func(tx *StorageTx) error {
+		keys, err := tx.List(guildID + "/")
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			value, ok, err := tx.Get(key)
+			if err != nil {
+				return err
+			}
+			var tmpl OverwriteTemplate
+			if ok && json.Unmarshal(value, &tmpl) == nil {
+				templates = append(templates, tmpl)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read templates: " + err.Error())
+	}
+
+	return c.JSON(templates)
+}
+
+// GetOverwriteTemplate retrieves a permission overwrite template of the guild by name.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the template as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the template does not exist.
+// @Summary		Get Overwrite Template
+// @Description	Retrieve a permission overwrite template of the guild by name.
+// @Tags			Channels
+// @Param			name	path		string	true	"Template name"
+// @Success		200		{object}	OverwriteTemplate
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/overwrite-templates/{name} [get]
+func GetOverwriteTemplate(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	tmpl, ok, err := loadTemplate(guildID, c.Params("name"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read template: " + err.Error())
+	}
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Template not found")
+	}
+
+	return c.JSON(tmpl)
+}
+
+// PutOverwriteTemplate creates or replaces a permission overwrite template of the guild.
+//
+// The overwrites use role or member IDs of the guild, or "@everyone" for the everyone role.
+// Exclusive templates remove all other overwrites of the channels they are applied to.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the stored template as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the template is invalid,
+//     or HTTP status 500 (Internal Server Error) if it cannot be stored.
+// @Summary		Put Overwrite Template
+// @Description	Create or replace a named permission overwrite template.
+// @Tags			Channels
+// @Param			name	path		string				true	"Template name"
+// @Param			body	body		OverwriteTemplate	true	"Template"
+// @Success		200		{object}	OverwriteTemplate
+// @Failure		400		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/overwrite-templates/{name} [put]
+func PutOverwriteTemplate(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	var tmpl OverwriteTemplate
+	if err := c.BodyParser(&tmpl); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	tmpl.Name = c.Params("name")
+	if err := tmpl.validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid template: " + err.Error())
+	}
+
+	value, err := json.Marshal(tmpl)
+	if err == nil {
+		err = templateStorage().Set(guildID+"/"+tmpl.Name, value)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store template: " + err.Error())
+	}
+
+	return c.JSON(tmpl)
+}
+
+// DeleteOverwriteTemplate deletes a permission overwrite template of the guild.
+//
+// Channels the template was applied to keep their overwrites.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the template does not exist.
+// @Summary		Delete Overwrite Template
+// @Description	Delete a permission overwrite template of the guild.
+// @Tags			Channels
+// @Param			name	path	string	true	"Template name"
+// @Success		204
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/overwrite-templates/{name} [delete]
+func DeleteOverwriteTemplate(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	key := guildID + "/" + c.Params("name")
+
+	found := false
+	err := templateStorage().Update(func(tx *StorageTx) error {
+		var err error
+		if _, found, err = tx.Get(key); err != nil || !found {
+			return err
+		}
+		return tx.Delete(key)
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete template: " + err.Error())
+	}
+	if !found {
+		return c.Status(fiber.StatusNotFound).SendString("Template not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ApplyOverwriteTemplate sets the overwrites of a template on one or many channels.
+//
+// The overwrites of each channel are replaced in a single request. If a channel fails, the
+// channels already changed are restored to their previous overwrites, so the template is applied
+// to either all or none of the channels.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - channel_ids: The IDs of the channels, at most 50.
+//
+// Returns:
+//   - On success, it returns the IDs of the changed channels as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) if a channel is outside the token's scope or protected,
+//     HTTP status 404 (Not Found) if the template or a channel does not exist,
+//     or HTTP status 500 (Internal Server Error) after rolling back a failed apply.
+// @Summary		Apply Overwrite Template
+// @Description	Set the overwrites of a template on channels, rolling back on failure.
+// @Tags			Channels
+// @Param			name		path		string					true	"Template name"
+// @Param			body		body		ApplyTemplateRequest	true	"Channels"
+// @Param			override	query		bool					false	"Allow changing a protected resource"
+// @Success		200			{object}	ApplyTemplateResult
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/overwrite-templates/{name}/apply [post]
+func ApplyOverwriteTemplate(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	var req ApplyTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	slices.Sort(req.ChannelIDs)
+	req.ChannelIDs = slices.Compact(req.ChannelIDs)
+	if len(req.ChannelIDs) == 0 || len(req.ChannelIDs) > maxTemplateChannels {
+		return c.Status(fiber.StatusBadRequest).SendString(fmt.Sprintf("Invalid request body: between 1 and %d channel_ids are required", maxTemplateChannels))
+	}
+
+	tmpl, ok, err := loadTemplate(guildID, c.Params("name"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read template: " + err.Error())
+	}
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Template not found")
+	}
+
+	// Checks every channel and keeps its before-image before changing any of them.
+	before := make([]*discordgo.Channel, len(req.ChannelIDs))
+	for i, channelID := range req.ChannelIDs {
+		if !channelAllowed(c, channelID, true) {
+			return channelForbidden(c)
+		}
+		if channelProtected(c, s, channelID) {
+			return protectedForbidden(c)
+		}
+		channel, err := cachedChannel(s, channelID)
+		if err != nil || channel.GuildID != guildID {
+			return c.Status(fiber.StatusNotFound).SendString("Channel not found: " + channelID)
+		}
+		before[i] = channel
+	}
+
+	result := ApplyTemplateResult{Applied: []string{}}
+	for _, channel := range before {
+		err := setChannelOverwrites(s, channel.ID, tmpl.overwritesFor(guildID, channel), auditOptions(c)...)
+		if err == nil {
+			result.Applied = append(result.Applied, channel.ID)
+			continue
+		}
+
+		// Restores the changed channels, newest first.
+		var failed []string
+		for i := len(result.Applied) - 1; i >= 0; i-- {
+			if rerr := setChannelOverwrites(s, before[i].ID, before[i].PermissionOverwrites, auditOptions(c)...); rerr != nil {
+				failed = append(failed, before[i].ID)
+			}
+		}
+		msg := fmt.Sprintf("Failed to apply template to channel %s: %v (rolled back %d channels)", channel.ID, err, len(result.Applied)-len(failed))
+		if len(failed) > 0 {
+			msg += "; rollback failed for " + strings.Join(failed, ", ")
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString(msg)
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,193 @@
+package disgm
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Page is the envelope of the paginated list endpoints.
+//
+// The next and previous pages are requested by passing next_cursor or prev_cursor as the cursor
+// query parameter, keeping the other parameters. The same URLs are sent in an RFC 5988 Link
+// header with the rel values "next" and "prev". A cursor is null if there is no such page; the
+// next page may turn out to be empty if the last page was exactly full.
+type Page struct {
+	Data   interface{} `json:"data"`   // Items of the page
+	Paging Paging      `json:"paging"` // Cursors of the adjacent pages
+}
+
+// Paging holds the cursors of the pages next to the current one.
+type Paging struct {
+	NextCursor *string `json:"next_cursor"`     // Cursor of the next page, older items for newest-first lists
+	PrevCursor *string `json:"prev_cursor"`     // Cursor of the previous page
+	Total      *int    `json:"total,omitempty"` // Total number of items, where Discord reports it
+}
+
+// pager describes how a list endpoint pages through a Discord list ordered by snowflake IDs.
+type pager struct {
+	resource   string // List resource whose sizes set the default and maximum items per page, see ListLimit
+	descending bool   // Whether the list is newest first, so next pages go back in time with before
+	reversible bool   // Whether Discord can page in the other direction, required for prev cursors
+	boundaries bool   // Whether a page may also start at an ID given with the before or after parameter
+}
+
+// pageRequest is the page asked for by a request. At most one of before and after is set.
+type pageRequest struct {
+	limit  int
+	before string
+	after  string
+}
+
+// errInvalidCursor is returned for cursors that were not issued by disgm.
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor builds the opaque cursor of a page boundary.
+func encodeCursor(direction, id string) *string {
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(direction + ":" + id))
+	return &cursor
+}
+
+// errCursorAndBoundary is returned for requests that set more than one of cursor, before and after.
+var errCursorAndBoundary = errors.New("only one of cursor, before and after may be set")
+
+// request reads the limit and cursor query parameters of a list request, and the before and after
+// parameters of pagers with boundaries.
+func (p pager) request(c *fiber.Ctx) (pageRequest, error) {
+	sizes := listLimit(p.resource)
+	req := pageRequest{limit: sizes.Default}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > sizes.Max {
+			return req, errors.New("limit must be between 1 and " + strconv.Itoa(sizes.Max))
+		}
+		req.limit = limit
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(v)
+		if err != nil {
+			return req, errInvalidCursor
+		}
+		direction, id, _ := strings.Cut(string(raw), ":")
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return req, errInvalidCursor
+		}
+		switch {
+		case direction == "after":
+			req.after = id
+		case direction == "before" && p.reversible:
+			req.before = id
+		default:
+			return req, errInvalidCursor
+		}
+	}
+
+	if !p.boundaries {
+		return req, nil
+	}
+	for _, param := range []string{"before", "after"} {
+		id := c.Query(param)
+		if id == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return req, errors.New(param + " must be a snowflake ID")
+		}
+		if req.before != "" || req.after != "" {
+			return req, errCursorAndBoundary
+		}
+		if param == "before" && !p.reversible {
+			return req, errors.New("before is not supported, Discord only pages forward")
+		}
+		if param == "before" {
+			req.before = id
+		} else {
+			req.after = id
+		}
+	}
+	return req, nil
+}
+
+// backwards reports whether a request pages in the prev direction of the list.
+func (p pager) backwards(req pageRequest) bool {
+	if p.descending {
+		return req.after != ""
+	}
+	return req.before != ""
+}
+
+// send responds with a page of items and its Link header. The IDs are the snowflakes the list is
+// ordered by, in any order; the cursors point past the lowest and highest of them.
+func (p pager) send(c *fiber.Ctx, req pageRequest, data interface{}, ids []string, total *int) error {
+	paging := Paging{Total: total}
+
+	if len(ids) > 0 {
+		lowest, highest := ids[0], ids[0]
+		for _, id := range ids[1:] {
+			if snowflakeLess(id, lowest) {
+				lowest = id
+			}
+			if snowflakeLess(highest, id) {
+				highest = id
+			}
+		}
+
+		// A full page may be followed by more items; a page reached backwards always is.
+		full := len(ids) >= req.limit
+		hasNext := full || p.backwards(req)
+		hasPrev := p.reversible && (req.before != "" || req.after != "") && (full || !p.backwards(req))
+
+		if p.descending {
+			if hasNext {
+				paging.NextCursor = encodeCursor("before", lowest)
+			}
+			if hasPrev {
+				paging.PrevCursor = encodeCursor("after", highest)
+			}
+		} else {
+			if hasNext {
+				paging.NextCursor = encodeCursor("after", highest)
+			}
+			if hasPrev {
+				paging.PrevCursor = encodeCursor("before", lowest)
+			}
+		}
+	}
+
+	var links []string
+	if paging.NextCursor != nil {
+		links = append(links, "<"+pageURL(c, *paging.NextCursor)+`>; rel="next"`)
+	}
+	if paging.PrevCursor != nil {
+		links = append(links, "<"+pageURL(c, *paging.PrevCursor)+`>; rel="prev"`)
+	}
+	if len(links) > 0 {
+		c.Set(fiber.HeaderLink, strings.Join(links, ", "))
+	}
+
+	return c.JSON(Page{Data: data, Paging: paging})
+}
+
+// pageURL returns the URL of the request with its cursor replaced.
+func pageURL(c *fiber.Ctx, cursor string) string {
+	u, err := url.Parse(c.OriginalURL())
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("cursor", cursor)
+	q.Del("before")
+	q.Del("after")
+	u.RawQuery = q.Encode()
+	return c.BaseURL() + u.String()
+}
+
+// invalidPage responds with HTTP status 400 (Bad Request) for invalid pagination parameters.
+func invalidPage(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).SendString("Invalid pagination: " + err.Error())
+}
@@ -0,0 +1,77 @@
+package disgm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UpdateMaskHeader is the request header listing the fields a PATCH request updates, separated by
+// commas. Listed fields missing from the body are cleared, and fields not listed are ignored.
+const UpdateMaskHeader = "X-Update-Mask"
+
+// MergePatchContentType is the content type of JSON merge patches (RFC 7396), in which a null
+// field clears it.
+const MergePatchContentType = "application/merge-patch+json"
+
+// patchFields returns the fields a PATCH request sets, for requests with an update mask or a
+// merge patch body, or nil for plain JSON bodies.
+//
+// The PATCH endpoints decode plain bodies into the discordgo parameter structs, whose omitempty
+// fields drop zero values, so a topic, nickname or parent cannot be cleared with them. The fields
+// returned here are sent to Discord as they are, where null clears a field.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context of the PATCH request.
+//
+// Returns:
+//   - map[string]json.RawMessage: The fields to send, with null for cleared fields, or nil.
+//   - error: An error if the body is not a JSON object or the mask names nested fields.
+func patchFields(c *fiber.Ctx) (map[string]json.RawMessage, error) {
+	mask := c.Get(UpdateMaskHeader)
+	mergePatch := strings.HasPrefix(c.Get(fiber.HeaderContentType), MergePatchContentType)
+	if mask == "" && !mergePatch {
+		return nil, nil
+	}
+
+	body := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(c.Body())) > 0 {
+		if err := json.Unmarshal(c.Body(), &body); err != nil {
+			return nil, errors.New("body must be a JSON object")
+		}
+	}
+	if mask == "" {
+		return body, nil
+	}
+
+	fields := make(map[string]json.RawMessage)
+	for _, name := range strings.Split(mask, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if strings.Contains(name, ".") {
+			return nil, errors.New("nested fields cannot be masked: " + name)
+		}
+		if value, ok := body[name]; ok {
+			fields[name] = value
+		} else {
+			fields[name] = json.RawMessage("null")
+		}
+	}
+	return fields, nil
+}
+
+// patchResource sends the fields of a PATCH request to a Discord endpoint and decodes the updated
+// resource.
+func patchResource(s *discordgo.Session, endpoint, bucket string, fields map[string]json.RawMessage, v interface{}, options ...discordgo.RequestOption) error {
+	body, err := s.RequestWithBucketID("PATCH", endpoint, fields, bucket, options...)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
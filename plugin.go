@@ -0,0 +1,60 @@
+package disgm
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// pluginNamePattern restricts plugin names to URL-safe lowercase identifiers.
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Plugin packages a feature, such as tickets, levels or a starboard, that is mounted into disgm.
+//
+// The routes of a plugin are served under /api/plugins/<name> and pass through the same token
+// authentication, CSRF protection and logging as the built-in API, so handlers can read the guild
+// ID from c.Locals("ID") like any other route.
+type Plugin interface {
+	// Name returns the unique name of the plugin, used as its route prefix.
+	Name() string
+
+	// Routes registers the HTTP routes of the plugin on a router mounted at /api/plugins/<name>.
+	Routes(router fiber.Router, s *discordgo.Session)
+
+	// EventHandlers returns the discordgo event handlers of the plugin, as accepted by Session.AddHandler.
+	EventHandlers() []interface{}
+}
+
+// RegisterPlugin mounts a plugin under /api/plugins/<name> and adds its Discord event handlers.
+// Plugins implementing StoragePlugin receive their storage namespace first.
+//
+// Parameters:
+//   - p: Plugin – The plugin to register.
+//
+// Returns:
+//   - error: An error if the plugin name is invalid or already registered.
+func (d *Disgm) RegisterPlugin(p Plugin) error {
+	name := p.Name()
+	if !pluginNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid plugin name %q", name)
+	}
+	if _, ok := d.plugins[name]; ok {
+		return fmt.Errorf("plugin %q is already registered", name)
+	}
+	d.plugins[name] = p
+
+	if sp, ok := p.(StoragePlugin); ok {
+		sp.SetStorage(d.Storage("plugin/" + name)) // Gives the plugin its storage namespace.
+	}
+
+	d.fiber.Route("/api/plugins/"+name, func(r fiber.Router) {
+		p.Routes(r, d.s) // Registers the routes of the plugin.
+	})
+
+	for _, h := range p.EventHandlers() {
+		d.s.AddHandler(h) // Adds the event handlers of the plugin.
+	}
+	return nil
+}
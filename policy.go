@@ -0,0 +1,341 @@
+package disgm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Settings checked by a guild policy.
+const (
+	PolicyVerificationLevel     = "verification_level"
+	PolicyExplicitContentFilter = "explicit_content_filter"
+	PolicyMFALevel              = "mfa_level"
+	PolicyRequiredRole          = "required_role"
+)
+
+// alertClient posts policy alerts to webhooks.
+var alertClient = &http.Client{Timeout: 10 * time.Second}
+
+// GuildPolicy is the desired state of the security settings of a guild. Unset fields are not checked.
+type GuildPolicy struct {
+	VerificationLevel     *int     `json:"verification_level,omitempty"`      // Minimum verification level (0-4)
+	ExplicitContentFilter *int     `json:"explicit_content_filter,omitempty"` // Minimum explicit content filter level (0-2)
+	MFALevel              *int     `json:"mfa_level,omitempty"`               // Required 2FA level for moderation (0 or 1)
+	RequiredRoles         []string `json:"required_roles,omitempty"`          // IDs or names of roles that must exist
+	AlertWebhookURL       string   `json:"alert_webhook_url,omitempty"`       // URL drift alerts are posted to, e.g. a Discord webhook
+}
+
+// PolicyViolation is a setting of a guild that does not match its policy.
+type PolicyViolation struct {
+	Setting  string      `json:"setting"`  // Checked setting, e.g. "verification_level"
+	Expected interface{} `json:"expected"` // Value required by the policy
+	Actual   interface{} `json:"actual"`   // Current value, null for missing roles
+}
+
+// PolicyReport is the result of checking a guild against its policy.
+type PolicyReport struct {
+	CheckedAt  time.Time         `json:"checked_at"` // When the guild was checked
+	Violations []PolicyViolation `json:"violations"` // Settings that do not match the policy
+}
+
+// The guild policies, stored in the key-value store keyed by guild ID, with the violations
+// last alerted per guild so drift is only reported when it changes.
+var policies = struct {
+	sync.Mutex
+	storage   *Storage
+	lastAlert map[string]string
+}{lastAlert: make(map[string]string)}
+
+// loadPolicy reads the policy of a guild.
+func loadPolicy(guildID string) (*GuildPolicy, bool, error) {
+	policies.Lock()
+	storage := policies.storage
+	policies.Unlock()
+
+	value, ok, err := storage.Get(guildID)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	var policy GuildPolicy
+	if err := json.Unmarshal(value, &policy); err != nil {
+		return nil, false, err
+	}
+	return &policy, true, nil
+}
+
+// check compares a guild and its roles against the policy.
+func (p *GuildPolicy) check(guild *discordgo.Guild, roles []*discordgo.Role) []PolicyViolation {
+	violations := []PolicyViolation{}
+	if p.VerificationLevel != nil && int(guild.VerificationLevel) < *p.VerificationLevel {
+		violations = append(violations, PolicyViolation{Setting: PolicyVerificationLevel, Expected: *p.VerificationLevel, Actual: int(guild.VerificationLevel)})
+	}
+	if p.ExplicitContentFilter != nil && int(guild.ExplicitContentFilter) < *p.ExplicitContentFilter {
+		violations = append(violations, PolicyViolation{Setting: PolicyExplicitContentFilter, Expected: *p.ExplicitContentFilter, Actual: int(guild.ExplicitContentFilter)})
+	}
+	if p.MFALevel != nil && int(guild.MfaLevel) < *p.MFALevel {
+		violations = append(violations, PolicyViolation{Setting: PolicyMFALevel, Expected: *p.MFALevel, Actual: int(guild.MfaLevel)})
+	}
+	for _, required := range p.RequiredRoles {
+		exists := slices.ContainsFunc(roles, func(r *discordgo.Role) bool { return r.ID == required || r.Name == required })
+		if !exists {
+			violations = append(violations, PolicyViolation{Setting: PolicyRequiredRole, Expected: required})
+		}
+	}
+	return violations
+}
+
+// violationKey identifies a set of violations, to detect when the drift of a guild changes.
+func violationKey(violations []PolicyViolation) string {
+	parts := make([]string, len(violations))
+	for i, v := range violations {
+		parts[i] = fmt.Sprintf("%s=%v", v.Setting, v.Expected)
+	}
+	return strings.Join(parts, ";")
+}
+
+// postPolicyAlert posts a drift report to the alert webhook of a policy. Discord webhook URLs
+// receive a readable message, other URLs the report as JSON.
+func postPolicyAlert(url, guildID string, report PolicyReport) error {
+	var payload interface{} = report
+	if strings.Contains(url, "discord.com/api/webhooks/") || strings.Contains(url, "discordapp.com/api/webhooks/") {
+		lines := []string{fmt.Sprintf("Guild %s drifted from its policy:", guildID)}
+		for _, v := range report.Violations {
+			if v.Setting == PolicyRequiredRole {
+				lines = append(lines, fmt.Sprintf("- required role %v is missing", v.Expected))
+			} else {
+				lines = append(lines, fmt.Sprintf("- %s is %v, expected at least %v", v.Setting, v.Actual, v.Expected))
+			}
+		}
+		payload = map[string]interface{}{
+			"content":          strings.Join(lines, "\n"),
+			"allowed_mentions": map[string]interface{}{"parse": []string{}},
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := alertClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// checkPolicyDrift checks a guild against its policy after a change and alerts when the
+// violations differ from the last alert. Back in compliance, the next drift alerts again.
+func checkPolicyDrift(s *discordgo.Session, guildID string) {
+	policy, ok, err := loadPolicy(guildID)
+	if err != nil || !ok {
+		return
+	}
+
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		return
+	}
+	s.State.RLock()
+	report := PolicyReport{CheckedAt: time.Now(), Violations: policy.check(guild, guild.Roles)}
+	s.State.RUnlock()
+
+	key := violationKey(report.Violations)
+	policies.Lock()
+	changed := policies.lastAlert[guildID] != key
+	policies.lastAlert[guildID] = key
+	policies.Unlock()
+
+	if !changed || len(report.Violations) == 0 {
+		return
+	}
+
+	EventCall(guildID, "POLICY_VIOLATION", report)
+	if policy.AlertWebhookURL != "" {
+		err := postPolicyAlert(policy.AlertWebhookURL, guildID, report)
+		recordDelivery(guildID, SinkWebhook, webhookSinkName(policy.AlertWebhookURL), err)
+		if err != nil {
+			log.Printf("Failed to post policy alert: %v", err)
+		}
+	}
+}
+
+// registerPolicyHandlers checks the guild policies when the settings or roles of a guild change.
+func registerPolicyHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, g *discordgo.GuildUpdate) {
+		checkPolicyDrift(s, g.ID)
+	})
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.GuildRoleUpdate) {
+		checkPolicyDrift(s, r.GuildID)
+	})
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.GuildRoleDelete) {
+		checkPolicyDrift(s, r.GuildID)
+	})
+}
+
+// GetGuildPolicy retrieves the settings policy of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the policy as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no policy.
+// @Summary		Get Guild Policy
+// @Description	Retrieve the desired security settings of the guild.
+// @Tags			Guild
+// @Success		200	{object}	GuildPolicy
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/policy [get]
+func GetGuildPolicy(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	policy, ok, err := loadPolicy(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read policy: " + err.Error())
+	}
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Policy not found")
+	}
+
+	return c.JSON(policy)
+}
+
+// PutGuildPolicy sets the settings policy of the guild.
+//
+// The policy describes the minimum verification level, explicit content filter and 2FA
+// requirement of the guild and the roles that must exist. Whenever the guild or its roles
+// change, disgm checks the policy and emits a POLICY_VIOLATION event, also posted to the alert
+// webhook, when the violations change. Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the stored policy as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the policy is invalid,
+//     HTTP status 403 (Forbidden) for tokens restricted to channels,
+//     or HTTP status 500 (Internal Server Error) if it cannot be stored.
+// @Summary		Put Guild Policy
+// @Description	Set the desired security settings of the guild, checked on every change.
+// @Tags			Guild
+// @Param			body	body		GuildPolicy	true	"Policy"
+// @Success		200		{object}	GuildPolicy
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/policy [put]
+func PutGuildPolicy(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var policy GuildPolicy
+	if err := c.BodyParser(&policy); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if policy.AlertWebhookURL != "" && !strings.HasPrefix(policy.AlertWebhookURL, "https://") && !strings.HasPrefix(policy.AlertWebhookURL, "http://") {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid policy: alert_webhook_url must be an http or https URL")
+	}
+
+	value, err := json.Marshal(policy)
+	if err == nil {
+		policies.Lock()
+		err = policies.storage.Set(guildID, value)
+		delete(policies.lastAlert, guildID) // Alerts again for drift that already exists.
+		policies.Unlock()
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store policy: " + err.Error())
+	}
+
+	return c.JSON(policy)
+}
+
+// DeleteGuildPolicy removes the settings policy of the guild, which stops the drift alerts.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) for tokens restricted to channels.
+// @Summary		Delete Guild Policy
+// @Description	Remove the settings policy of the guild.
+// @Tags			Guild
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/policy [delete]
+func DeleteGuildPolicy(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	policies.Lock()
+	err := policies.storage.Delete(guildID)
+	delete(policies.lastAlert, guildID)
+	policies.Unlock()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete policy: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetGuildPolicyViolations checks the guild against its settings policy.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the violations of the policy as JSON, empty if the guild complies.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no policy,
+//     or HTTP status 500 (Internal Server Error) if the guild cannot be read.
+// @Summary		Get Guild Policy Violations
+// @Description	Check the guild settings and roles against its policy.
+// @Tags			Guild
+// @Success		200	{object}	PolicyReport
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/policy/violations [get]
+func GetGuildPolicyViolations(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	policy, ok, err := loadPolicy(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read policy: " + err.Error())
+	}
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Policy not found")
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild: " + err.Error())
+	}
+
+	return c.JSON(PolicyReport{CheckedAt: time.Now(), Violations: policy.check(guild, guild.Roles)})
+}
@@ -0,0 +1,556 @@
+package disgm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	pollFinalizeDelay   = 10 * time.Second // Time between checks for finalized poll results.
+	pollFinalizeRetries = 6                // Checks before the results are posted even if not finalized.
+)
+
+// PollResults are the aggregated votes of a message poll.
+type PollResults struct {
+	ChannelID  string              `json:"channel_id"`
+	MessageID  string              `json:"message_id"`
+	Question   string              `json:"question"`
+	Expiry     *time.Time          `json:"expiry,omitempty"` // When the poll closes
+	Finalized  bool                `json:"finalized"`        // Whether Discord has finished counting the votes
+	TotalVotes int                 `json:"total_votes"`
+	Answers    []PollAnswerResults `json:"answers"`
+}
+
+// PollAnswerResults are the votes of a single poll answer.
+type PollAnswerResults struct {
+	AnswerID   int     `json:"answer_id"`
+	Text       string  `json:"text"`
+	Emoji      string  `json:"emoji,omitempty"`
+	Votes      int     `json:"votes"`
+	Percentage float64 `json:"percentage"` // Share of all votes, rounded to one decimal
+}
+
+// PollAnnouncement posts the results of a poll to a channel once the poll expires.
+type PollAnnouncement struct {
+	ChannelID       string    `json:"channel_id"`        // Channel of the poll message
+	MessageID       string    `json:"message_id"`        // ID of the poll message
+	TargetChannelID string    `json:"target_channel_id"` // Channel the results are posted to
+	PostAt          time.Time `json:"post_at"`           // Expiry of the poll
+
+	guildID string      // Guild that scheduled the announcement
+	timer   *time.Timer // Timer posting the results
+}
+
+// pollMessage is the part of a message object describing its poll, which discordgo does not model.
+type pollMessage struct {
+	Poll *struct {
+		Question struct {
+			Text string `json:"text"`
+		} `json:"question"`
+		Answers []struct {
+			AnswerID  int `json:"answer_id"`
+			PollMedia struct {
+				Text  string `json:"text"`
+				Emoji *struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"emoji"`
+			} `json:"poll_media"`
+		} `json:"answers"`
+		Expiry  *time.Time `json:"expiry"`
+		Results *struct {
+			IsFinalized  bool `json:"is_finalized"`
+			AnswerCounts []struct {
+				ID    int `json:"id"`
+				Count int `json:"count"`
+			} `json:"answer_counts"`
+		} `json:"results"`
+	} `json:"poll"`
+}
+
+// errNoPoll is returned for messages without a poll.
+var errNoPoll = errors.New("message has no poll")
+
+// A registry of scheduled poll announcements keyed by message ID.
+var pollAnnouncements = struct {
+	sync.Mutex
+	byMessage map[string]*PollAnnouncement
+}{byMessage: make(map[string]*PollAnnouncement)}
+
+// pollVoterPager pages through the voters of a poll answer by user ID. Discord only supports after.
+var pollVoterPager = pager{resource: LimitPollVoters}
+
+// fetchPollResults retrieves a poll message and aggregates its votes into percentages.
+func fetchPollResults(s *discordgo.Session, channelID, messageID string) (*PollResults, error) {
+	body, err := s.RequestWithBucketID("GET", discordgo.EndpointChannelMessage(channelID, messageID), nil, discordgo.EndpointChannelMessage(channelID, ""))
+	if err != nil {
+		return nil, err
+	}
+	return decodePollResults(channelID, messageID, body)
+}
+
+// decodePollResults aggregates the votes of the poll of a message object into percentages.
+func decodePollResults(channelID, messageID string, body []byte) (*PollResults, error) {
+	var m pollMessage
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, err
+	}
+	if m.Poll == nil {
+		return nil, errNoPoll
+	}
+
+	results := &PollResults{
+		ChannelID: channelID,
+		MessageID: messageID,
+		Question:  m.Poll.Question.Text,
+		Expiry:    m.Poll.Expiry,
+		Answers:   []PollAnswerResults{},
+	}
+
+	counts := make(map[int]int)
+	if m.Poll.Results != nil {
+		results.Finalized = m.Poll.Results.IsFinalized
+		for _, ac := range m.Poll.Results.AnswerCounts {
+			counts[ac.ID] = ac.Count
+			results.TotalVotes += ac.Count
+		}
+	}
+
+	for _, a := range m.Poll.Answers {
+		answer := PollAnswerResults{AnswerID: a.AnswerID, Text: a.PollMedia.Text, Votes: counts[a.AnswerID]}
+		if e := a.PollMedia.Emoji; e != nil {
+			answer.Emoji = e.Name
+			if e.ID != "" {
+				answer.Emoji = "<:" + e.Name + ":" + e.ID + ">"
+			}
+		}
+		if results.TotalVotes > 0 {
+			answer.Percentage = math.Round(float64(answer.Votes)/float64(results.TotalVotes)*1000) / 10
+		}
+		results.Answers = append(results.Answers, answer)
+	}
+
+	return results, nil
+}
+
+// pollResultsEmbed renders poll results as an embed with a bar per answer, most votes first.
+func pollResultsEmbed(guildID string, results *PollResults) *discordgo.MessageEmbed {
+	answers := slices.Clone(results.Answers)
+	slices.SortStableFunc(answers, func(a, b PollAnswerResults) int { return b.Votes - a.Votes })
+
+	var desc strings.Builder
+	for _, a := range answers {
+		bar := strings.Repeat("█", int(a.Percentage/10)) + strings.Repeat("░", 10-int(a.Percentage/10))
+		label := a.Text
+		if a.Emoji != "" {
+			label = a.Emoji + " " + label
+		}
+		fmt.Fprintf(&desc, "**%s**\n%s %d votes (%.1f%%)\n", label, bar, a.Votes, a.Percentage)
+	}
+
+	footer := fmt.Sprintf("%d votes", results.TotalVotes)
+	if !results.Finalized {
+		footer += " · not finalized"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Poll results: " + results.Question,
+		URL:         fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, results.ChannelID, results.MessageID),
+		Description: desc.String(),
+		Footer:      &discordgo.MessageEmbedFooter{Text: footer},
+	}
+}
+
+// announcePollResults posts the results of an expired poll, waiting for Discord to finalize them.
+func announcePollResults(s *discordgo.Session, announcement *PollAnnouncement, attempt int) {
+	results, err := fetchPollResults(s, announcement.ChannelID, announcement.MessageID)
+	if err == nil && !results.Finalized && attempt < pollFinalizeRetries {
+		pollAnnouncements.Lock()
+		if pollAnnouncements.byMessage[announcement.MessageID] == announcement {
+			announcement.timer = time.AfterFunc(pollFinalizeDelay, func() {
+				announcePollResults(s, announcement, attempt+1)
+			})
+		}
+		pollAnnouncements.Unlock()
+		return
+	}
+
+	pollAnnouncements.Lock()
+	if pollAnnouncements.byMessage[announcement.MessageID] != announcement {
+		pollAnnouncements.Unlock()
+		return // Cancelled or replaced in the meantime.
+	}
+	delete(pollAnnouncements.byMessage, announcement.MessageID)
+	pollAnnouncements.Unlock()
+
+	if err != nil {
+		return
+	}
+	s.ChannelMessageSendEmbed(announcement.TargetChannelID, pollResultsEmbed(announcement.guildID, results))
+}
+
+// GetPollResults aggregates the votes of a message poll.
+//
+// This function retrieves the poll of a message and returns the votes of each answer with its share
+// of all votes. Live vote changes are pushed to WebSocket clients as MESSAGE_POLL_VOTE_ADD and
+// MESSAGE_POLL_VOTE_REMOVE events, which require the bot to request the guild message polls intent.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - channelid: The ID of the channel.
+//   - messageid: The ID of the poll message.
+//
+// Returns:
+//   - On success, it returns the aggregated results as JSON.
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is outside the token scope,
+//     HTTP status 404 (Not Found) if the message has no poll, or HTTP status 500 otherwise.
+// @Summary		Get Poll Results
+// @Description	Aggregate the votes of a message poll into counts and percentages.
+// @Tags			Polls
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			messageid	path		string	true	"Message ID"
+// @Success		200			{object}	PollResults
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/messages/{messageid}/poll [get]
+func GetPollResults(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+	messageID := c.Params("messageid")
+
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
+	results, err := fetchPollResults(s, channelID, messageID)
+	if errors.Is(err, errNoPoll) {
+		return c.Status(fiber.StatusNotFound).SendString("Poll not found")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve poll: " + err.Error())
+	}
+
+	return c.JSON(results)
+}
+
+// GetPollVoters retrieves the users who voted for an answer of a message poll.
+//
+// The voters are returned in pages ordered by user ID. Discord only pages forward through the
+// voters of an answer.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - channelid: The ID of the channel.
+//   - messageid: The ID of the poll message.
+//   - answerid: The ID of the answer.
+//   - limit: Optional number of users, 1 to 100, defaults to 25; both can be changed with the Limits option.
+//   - cursor: Optional cursor of the page, from the paging of a previous page.
+//
+// Returns:
+//   - On success, it returns the page of voters as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the answer ID, limit or cursor is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token scope,
+//     or HTTP status 500 (Internal Server Error) if the voters cannot be retrieved.
+// @Summary		Get Poll Voters
+// @Description	Retrieve the users who voted for an answer of a message poll.
+// @Tags			Polls
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			messageid	path		string	true	"Message ID"
+// @Param			answerid	path		int		true	"Answer ID"
+// @Param			limit		query		int		false	"Users per page"	minimum(1)	maximum(100)	default(25)
+// @Param			cursor		query		string	false	"Page cursor"
+// @Success		200			{object}	Page{data=UserArray}
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/messages/{messageid}/polls/{answerid}/voters [get]
+func GetPollVoters(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+	messageID := c.Params("messageid")
+
+	answerID, err := strconv.Atoi(c.Params("answerid"))
+	if err != nil || answerID < 1 {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid answer ID")
+	}
+
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
+	req, err := pollVoterPager.request(c)
+	if err != nil {
+		return invalidPage(c, err)
+	}
+
+	query := url.Values{"limit": {strconv.Itoa(req.limit)}}
+	if req.after != "" {
+		query.Set("after", req.after)
+	}
+
+	bucket := discordgo.EndpointChannel(channelID) + "/polls/" + messageID + "/answers/"
+	body, err := s.RequestWithBucketID("GET", bucket+strconv.Itoa(answerID)+"?"+query.Encode(), nil, bucket)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve poll voters: " + err.Error())
+	}
+	var voters struct {
+		Users []*discordgo.User `json:"users"`
+	}
+	if err := json.Unmarshal(body, &voters); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve poll voters: " + err.Error())
+	}
+
+	cacheUsers(voters.Users...)
+
+	if voters.Users == nil {
+		voters.Users = []*discordgo.User{}
+	}
+	ids := make([]string, len(voters.Users))
+	for i, u := range voters.Users {
+		ids[i] = u.ID
+	}
+	return pollVoterPager.send(c, req, voters.Users, ids, nil)
+}
+
+// ExpirePoll ends a message poll immediately.
+//
+// Only polls posted by the bot can be ended. A scheduled results announcement of the poll is
+// posted as soon as Discord has finalized the results, rather than at the original expiry.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - channelid: The ID of the channel.
+//   - messageid: The ID of the poll message.
+//
+// Returns:
+//   - On success, it returns the results of the ended poll as JSON.
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is outside the token scope,
+//     HTTP status 404 (Not Found) if the message has no poll,
+//     or HTTP status 500 (Internal Server Error) if the poll cannot be ended.
+// @Summary		Expire Poll
+// @Description	End a message poll of the bot immediately.
+// @Tags			Polls
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			messageid	path		string	true	"Message ID"
+// @Success		200			{object}	PollResults
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/messages/{messageid}/polls/expire [post]
+func ExpirePoll(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	channelID := c.Params("channelid")
+	messageID := c.Params("messageid")
+
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	if _, err := fetchPollResults(s, channelID, messageID); errors.Is(err, errNoPoll) {
+		return c.Status(fiber.StatusNotFound).SendString("Poll not found")
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve poll: " + err.Error())
+	}
+
+	endpoint := discordgo.EndpointChannel(channelID) + "/polls/" + messageID + "/expire"
+	body, err := s.RequestWithBucketID("POST", endpoint, nil, discordgo.EndpointChannel(channelID)+"/polls/", auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to expire poll: " + err.Error())
+	}
+	results, err := decodePollResults(channelID, messageID, body)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to expire poll: " + err.Error())
+	}
+
+	// Posts a scheduled announcement now instead of at the original expiry
+	pollAnnouncements.Lock()
+	if a, ok := pollAnnouncements.byMessage[messageID]; ok && a.guildID == guildID {
+		if a.timer != nil {
+			a.timer.Stop()
+		}
+		a.PostAt = time.Now()
+		a.timer = time.AfterFunc(pollFinalizeDelay, func() {
+			announcePollResults(s, a, 0)
+		})
+	}
+	pollAnnouncements.Unlock()
+
+	return c.JSON(results)
+}
+
+// GetPollAnnouncements retrieves the scheduled poll result announcements of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the announcements as a JSON array, ordered by posting time.
+// @Summary		Get Poll Announcements
+// @Description	Retrieve the scheduled poll result announcements of the guild.
+// @Tags			Polls
+// @Success		200	{array}	PollAnnouncement
+// @Router			/api/guild/polls/announcements [get]
+func GetPollAnnouncements(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	pollAnnouncements.Lock()
+	defer pollAnnouncements.Unlock()
+
+	list := []*PollAnnouncement{}
+	for _, a := range pollAnnouncements.byMessage {
+		if a.guildID == guildID {
+			list = append(list, a)
+		}
+	}
+	slices.SortFunc(list, func(a, b *PollAnnouncement) int { return a.PostAt.Compare(b.PostAt) })
+
+	return c.JSON(list)
+}
+
+// SchedulePollAnnouncement posts the results of a poll to a channel when the poll expires.
+//
+// Once the poll expires, the results are posted as an embed as soon as Discord has finalized the
+// vote counts. Scheduling an announcement for the same poll again replaces the previous one.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - channelid: The ID of the channel.
+//   - messageid: The ID of the poll message.
+//
+// Request Body:
+//   - A JSON object with the field "target_channel_id", defaulting to the channel of the poll.
+//
+// Returns:
+//   - On success, it returns the announcement as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the poll has no expiry or the target
+//     channel is not in the guild, HTTP status 403 (Forbidden) if a channel is outside the token scope,
+//     HTTP status 404 (Not Found) if the message has no poll, or HTTP status 500 otherwise.
+// @Summary		Schedule Poll Announcement
+// @Description	Post the results of a poll to a channel when it expires.
+// @Tags			Polls
+// @Param			channelid	path		string				true	"Channel ID"
+// @Param			messageid	path		string				true	"Message ID"
+// @Param			body		body		PollAnnouncement	false	"Target channel"
+// @Success		201			{object}	PollAnnouncement
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/messages/{messageid}/poll/announcement [post]
+func SchedulePollAnnouncement(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	channelID := c.Params("channelid")
+	messageID := c.Params("messageid")
+
+	var announcement PollAnnouncement
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&announcement); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+		}
+	}
+	if announcement.TargetChannelID == "" {
+		announcement.TargetChannelID = channelID
+	}
+
+	if !channelAllowed(c, channelID, false) || !channelAllowed(c, announcement.TargetChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	target, err := cachedChannel(s, announcement.TargetChannelID)
+	if err != nil || target.GuildID != guildID {
+		return c.Status(fiber.StatusBadRequest).SendString("Target channel not found in guild")
+	}
+
+	results, err := fetchPollResults(s, channelID, messageID)
+	if errors.Is(err, errNoPoll) {
+		return c.Status(fiber.StatusNotFound).SendString("Poll not found")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve poll: " + err.Error())
+	}
+	if results.Expiry == nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Poll has no expiry")
+	}
+
+	announcement.ChannelID = channelID
+	announcement.MessageID = messageID
+	announcement.PostAt = *results.Expiry
+	announcement.guildID = guildID
+
+	a := &announcement
+	pollAnnouncements.Lock()
+	if previous, ok := pollAnnouncements.byMessage[messageID]; ok && previous.timer != nil {
+		previous.timer.Stop()
+	}
+	pollAnnouncements.byMessage[messageID] = a
+	a.timer = time.AfterFunc(time.Until(a.PostAt), func() {
+		announcePollResults(s, a, 0)
+	})
+	pollAnnouncements.Unlock()
+
+	return c.Status(fiber.StatusCreated).JSON(a)
+}
+
+// CancelPollAnnouncement cancels the scheduled results announcement of a poll.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - channelid: The ID of the channel.
+//   - messageid: The ID of the poll message.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if no announcement is scheduled.
+// @Summary		Cancel Poll Announcement
+// @Description	Cancel the scheduled results announcement of a poll.
+// @Tags			Polls
+// @Param			channelid	path	string	true	"Channel ID"
+// @Param			messageid	path	string	true	"Message ID"
+// @Success		204
+// @Failure		404	{object}	error
+// @Router			/api/guild/channels/{channelid}/messages/{messageid}/poll/announcement [delete]
+func CancelPollAnnouncement(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	messageID := c.Params("messageid")
+
+	pollAnnouncements.Lock()
+	defer pollAnnouncements.Unlock()
+
+	a, ok := pollAnnouncements.byMessage[messageID]
+	if !ok || a.guildID != guildID {
+		return c.Status(fiber.StatusNotFound).SendString("Announcement not found")
+	}
+
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	delete(pollAnnouncements.byMessage, messageID)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,119 @@
+package disgm
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/contrib/websocket"
+)
+
+const (
+	maxPrefetchChannels = 10               // Most recently active channels whose messages are prefetched.
+	prefetchTTL         = 30 * time.Second // Time prefetched messages are shared with clients that connect later.
+)
+
+// ReadySnapshot is the payload of the READY event, sent after HELLO if the PrefetchOnConnect
+// option is set, so dashboards can render without calling the API first.
+type ReadySnapshot struct {
+	Guild    *discordgo.Guild                `json:"guild"`    // The guild, without its members, channels, roles and emojis
+	Channels []*discordgo.Channel            `json:"channels"` // Channels the token may read
+	Roles    []*discordgo.Role               `json:"roles"`
+	Messages map[string][]*discordgo.Message `json:"messages"` // Recent messages of the most recently active text channels, keyed by channel ID
+}
+
+// The prefetch settings, set from the options.
+var prefetch = struct {
+	sync.Mutex
+	enabled  bool
+	messages int
+}{}
+
+// registerPrefetchHandlers drops the prefetched messages of a channel once they change.
+func registerPrefetchHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		cacheDelete(CacheMessages, m.ChannelID)
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageUpdate) {
+		cacheDelete(CacheMessages, m.ChannelID)
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageDelete) {
+		cacheDelete(CacheMessages, m.ChannelID)
+	})
+}
+
+// recentMessages returns the recent messages of a channel from the cache, or fetches and caches
+// them, so only the first client connecting calls Discord.
+func recentMessages(s *discordgo.Session, channelID string, limit int) ([]*discordgo.Message, error) {
+	if v, ok := cacheGet(CacheMessages, channelID); ok {
+		return v.([]*discordgo.Message), nil
+	}
+	messages, err := s.ChannelMessages(channelID, limit, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	var size int64
+	for _, m := range messages {
+		size += int64(len(m.Content)) + 512 // Rough estimate of the author, embeds and other fields.
+	}
+	cacheSet(CacheMessages, channelID, messages, size, prefetchTTL)
+	return messages, nil
+}
+
+// readySnapshot builds the READY snapshot of a guild from the state, limited to the channels the
+// scope of the connection may read. It returns false if the guild is not in the state.
+func readySnapshot(s *discordgo.Session, guildID string, scope interface{}, messages int) (*ReadySnapshot, bool) {
+	guild, ok := guildSnapshot(s, guildID, scope, []string{"guild", "channels", "roles"})
+	if !ok {
+		return nil, false
+	}
+	snapshot := &ReadySnapshot{
+		Guild:    guild.Guild,
+		Channels: guild.Channels,
+		Roles:    guild.Roles,
+		Messages: make(map[string][]*discordgo.Message),
+	}
+
+	if messages <= 0 {
+		return snapshot, true
+	}
+
+	// Prefetches the messages of the text channels with the latest messages the bot can read.
+	var active []*discordgo.Channel
+	for _, ch := range snapshot.Channels {
+		if (ch.Type == discordgo.ChannelTypeGuildText || ch.Type == discordgo.ChannelTypeGuildNews) && ch.LastMessageID != "" &&
+			botMissingPermission(s, ch.ID, discordgo.PermissionViewChannel, discordgo.PermissionReadMessageHistory) == "" {
+			active = append(active, ch)
+		}
+	}
+	slices.SortFunc(active, func(a, b *discordgo.Channel) int {
+		switch {
+		case snowflakeLess(b.LastMessageID, a.LastMessageID):
+			return -1
+		case snowflakeLess(a.LastMessageID, b.LastMessageID):
+			return 1
+		}
+		return 0
+	})
+	for _, ch := range active[:min(len(active), maxPrefetchChannels)] {
+		if recent, err := recentMessages(s, ch.ID, messages); err == nil {
+			snapshot.Messages[ch.ID] = recent
+		}
+	}
+	return snapshot, true
+}
+
+// sendReady sends the READY snapshot to a new connection if the PrefetchOnConnect option is set.
+func sendReady(conn *websocket.Conn, guildID string, s *discordgo.Session) {
+	prefetch.Lock()
+	enabled, messages := prefetch.enabled, prefetch.messages
+	prefetch.Unlock()
+	if !enabled {
+		return
+	}
+
+	if snapshot, ok := readySnapshot(s, guildID, conn.Locals("Scope"), messages); ok {
+		writeEvent(conn, "READY", snapshot)
+	}
+}
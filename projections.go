@@ -0,0 +1,501 @@
+package disgm
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DirectoryRole is a role of a member in the member directory.
+type DirectoryRole struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Color    int    `json:"color"`
+	Position int    `json:"position"`
+}
+
+// DirectoryMember is a member of the member directory, with the roles resolved.
+type DirectoryMember struct {
+	UserID        string          `json:"user_id"`
+	Username      string          `json:"username"`
+	DisplayName   string          `json:"display_name"`              // Nickname, global name or username
+	AvatarURL     string          `json:"avatar_url"`                // Guild avatar, or user avatar
+	Bot           bool            `json:"bot"`                       // Whether the member is a bot
+	Roles         []DirectoryRole `json:"roles"`                     // Roles of the member, highest first
+	Color         int             `json:"color"`                     // Color of the highest colored role, 0 for none
+	JoinedAt      time.Time       `json:"joined_at"`                 // When the member joined the guild
+	Pending       bool            `json:"pending"`                   // Whether the member has not passed membership screening
+	TimedOutUntil *time.Time      `json:"timed_out_until,omitempty"` // When the timeout of the member ends
+}
+
+// MemberDirectory is the read model of the members of a guild.
+type MemberDirectory struct {
+	Members   []DirectoryMember `json:"members"`    // Members, by display name
+	Complete  bool              `json:"complete"`   // Whether all members were received, which needs the guild members intent
+	UpdatedAt time.Time         `json:"updated_at"` // When the last gateway event changed the directory
+}
+
+// OverwriteSummary summarizes the permission overwrites of a channel.
+type OverwriteSummary struct {
+	Roles   int  `json:"roles"`   // Role overwrites
+	Members int  `json:"members"` // Member overwrites
+	Private bool `json:"private"` // Whether @everyone is denied viewing the channel
+	Synced  bool `json:"synced"`  // Whether the overwrites match those of the category
+}
+
+// ChannelNode is a channel of the channel tree, with the channels of a category as its children.
+type ChannelNode struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Type       int              `json:"type"`
+	Position   int              `json:"position"`
+	Overwrites OverwriteSummary `json:"overwrites"`
+	Children   []ChannelNode    `json:"children,omitempty"` // Channels of a category, in display order
+}
+
+// ChannelTree is the read model of the channels of a guild.
+type ChannelTree struct {
+	Channels  []ChannelNode `json:"channels"`   // Categories and the channels outside of them, in display order
+	UpdatedAt time.Time     `json:"updated_at"` // When the last gateway event changed the tree
+}
+
+// guildProjection is the state of a guild the read models are built from. The read models are
+// built on the first read after a change and served from memory until the next change.
+type guildProjection struct {
+	roles       map[string]*discordgo.Role
+	channels    map[string]*discordgo.Channel
+	members     map[string]*discordgo.Member
+	memberCount int // Members of the guild as reported by Discord
+
+	directory *MemberDirectory // Nil after a change of the members or roles
+	tree      *ChannelTree     // Nil after a change of the channels
+	membersAt time.Time        // Last change of the members or roles
+	channelAt time.Time        // Last change of the channels
+}
+
+// The read models of the guilds, kept from gateway events while the Projections option is set,
+// keyed by guild ID.
+var projections = struct {
+	sync.Mutex
+	enabled bool
+	guilds  map[string]*guildProjection
+}{guilds: make(map[string]*guildProjection)}
+
+// projection returns the projection of a guild, creating it for the first event of the guild.
+// The caller must hold the projections lock.
+func projection(guildID string) *guildProjection {
+	p, ok := projections.guilds[guildID]
+	if !ok {
+		p = &guildProjection{
+			roles:    make(map[string]*discordgo.Role),
+			channels: make(map[string]*discordgo.Channel),
+			members:  make(map[string]*discordgo.Member),
+		}
+		projections.guilds[guildID] = p
+	}
+	return p
+}
+
+// membersChanged drops the member directory of a guild, to be rebuilt on the next read.
+func (p *guildProjection) membersChanged() {
+	p.directory, p.membersAt = nil, time.Now()
+}
+
+// channelsChanged drops the channel tree of a guild, to be rebuilt on the next read.
+func (p *guildProjection) channelsChanged() {
+	p.tree, p.channelAt = nil, time.Now()
+}
+
+// memberDirectory returns the member directory, building it if it changed since the last read.
+func (p *guildProjection) memberDirectory() *MemberDirectory {
+	if p.directory != nil {
+		return p.directory
+	}
+
+	members := make([]DirectoryMember, 0, len(p.members))
+	for _, m := range p.members {
+		if m.User == nil {
+			continue
+		}
+		entry := DirectoryMember{
+			UserID:        m.User.ID,
+			Username:      m.User.Username,
+			DisplayName:   m.DisplayName(),
+			AvatarURL:     m.AvatarURL(""),
+			Bot:           m.User.Bot,
+			Roles:         []DirectoryRole{},
+			JoinedAt:      m.JoinedAt,
+			Pending:       m.Pending,
+			TimedOutUntil: m.CommunicationDisabledUntil,
+		}
+		for _, id := range m.Roles {
+			if r, ok := p.roles[id]; ok {
+				entry.Roles = append(entry.Roles, DirectoryRole{ID: r.ID, Name: r.Name, Color: r.Color, Position: r.Position})
+			}
+		}
+		slices.SortFunc(entry.Roles, func(a, b DirectoryRole) int {
+			return cmp.Or(cmp.Compare(b.Position, a.Position), strings.Compare(a.ID, b.ID))
+		})
+		if i := slices.IndexFunc(entry.Roles, func(r DirectoryRole) bool { return r.Color != 0 }); i >= 0 {
+			entry.Color = entry.Roles[i].Color
+		}
+		if entry.DisplayName == "" {
+			entry.DisplayName = m.User.Username
+		}
+		members = append(members, entry)
+	}
+	slices.SortFunc(members, func(a, b DirectoryMember) int {
+		return cmp.Or(strings.Compare(strings.ToLower(a.DisplayName), strings.ToLower(b.DisplayName)), strings.Compare(a.UserID, b.UserID))
+	})
+
+	p.directory = &MemberDirectory{Members: members, Complete: len(p.members) >= p.memberCount, UpdatedAt: p.membersAt}
+	return p.directory
+}
+
+// channelTree returns the channel tree, building it if it changed since the last read.
+func (p *guildProjection) channelTree() *ChannelTree {
+	if p.tree != nil {
+		return p.tree
+	}
+
+	// Channels outside of a category come first, as in the Discord client; voice channels follow
+	// the text channels of their category.
+	order := func(a, b ChannelNode) int {
+		return cmp.Or(cmp.Compare(channelGroup(a.Type), channelGroup(b.Type)), cmp.Compare(a.Position, b.Position), strings.Compare(a.ID, b.ID))
+	}
+	children := make(map[string][]ChannelNode)
+	roots := []ChannelNode{}
+	for _, ch := range p.channels {
+		node := ChannelNode{
+			ID:         ch.ID,
+			Name:       ch.Name,
+			Type:       int(ch.Type),
+			Position:   ch.Position,
+			Overwrites: summarizeOverwrites(ch, p.channels[ch.ParentID]),
+		}
+		if _, ok := p.channels[ch.ParentID]; ok && ch.Type != discordgo.ChannelTypeGuildCategory {
+			children[ch.ParentID] = append(children[ch.ParentID], node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	for i, node := range roots {
+		if nodes, ok := children[node.ID]; ok {
+			slices.SortFunc(nodes, order)
+			roots[i].Children = nodes
+		}
+	}
+	slices.SortFunc(roots, order)
+
+	p.tree = &ChannelTree{Channels: roots, UpdatedAt: p.channelAt}
+	return p.tree
+}
+
+// channelGroup returns the group a channel type is sorted in: text channels, then voice
+// channels, then categories.
+func channelGroup(t int) int {
+	switch discordgo.ChannelType(t) {
+	case discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice:
+		return 1
+	case discordgo.ChannelTypeGuildCategory:
+		return 2
+	}
+	return 0
+}
+
+// summarizeOverwrites counts the permission overwrites of a channel and compares them with those
+// of its category, which is nil for channels outside of a category.
+func summarizeOverwrites(ch, category *discordgo.Channel) OverwriteSummary {
+	var summary OverwriteSummary
+	for _, o := range ch.PermissionOverwrites {
+		if o.Type == discordgo.PermissionOverwriteTypeMember {
+			summary.Members++
+			continue
+		}
+		summary.Roles++
+		if o.ID == ch.GuildID && o.Deny&discordgo.PermissionViewChannel != 0 {
+			summary.Private = true // The @everyone role has the ID of the guild.
+		}
+	}
+	if category != nil {
+		summary.Synced = sameOverwrites(ch.PermissionOverwrites, category.PermissionOverwrites)
+	}
+	return summary
+}
+
+// sameOverwrites reports whether two channels have the same permission overwrites, in any order.
+func sameOverwrites(a, b []*discordgo.PermissionOverwrite) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, o := range a {
+		if !slices.ContainsFunc(b, func(other *discordgo.PermissionOverwrite) bool { return *other == *o }) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyMember copies a member of an event. The projections keep copies, as the state of the
+// session updates the objects it holds in place.
+func copyMember(m *discordgo.Member) *discordgo.Member {
+	member := *m
+	return &member
+}
+
+// copyRole copies a role of an event.
+func copyRole(r *discordgo.Role) *discordgo.Role {
+	role := *r
+	return &role
+}
+
+// copyChannel copies a channel of an event.
+func copyChannel(ch *discordgo.Channel) *discordgo.Channel {
+	channel := *ch
+	return &channel
+}
+
+// loadGuildProjection replaces the projection of a guild with the state of a GUILD_CREATE event.
+func loadGuildProjection(g *discordgo.Guild) {
+	p := &guildProjection{
+		roles:       make(map[string]*discordgo.Role, len(g.Roles)),
+		channels:    make(map[string]*discordgo.Channel, len(g.Channels)),
+		members:     make(map[string]*discordgo.Member, len(g.Members)),
+		memberCount: g.MemberCount,
+	}
+	for _, r := range g.Roles {
+		p.roles[r.ID] = copyRole(r)
+	}
+	for _, ch := range g.Channels {
+		ch = copyChannel(ch)
+		ch.GuildID = g.ID // Discord leaves it out of the channels of the guild.
+		p.channels[ch.ID] = ch
+	}
+	for _, m := range g.Members {
+		if m.User != nil {
+			p.members[m.User.ID] = copyMember(m)
+		}
+	}
+	p.membersChanged()
+	p.channelsChanged()
+	projections.guilds[g.ID] = p
+}
+
+// registerProjectionHandlers keeps the projections of the guilds from gateway events while the
+// Projections option is set. The members of a guild are requested when it becomes available, if
+// the session has the guild members intent.
+func registerProjectionHandlers(s *discordgo.Session) {
+	// update applies an event to the projection of a guild while projections are enabled.
+	update := func(guildID string, apply func(p *guildProjection)) {
+		projections.Lock()
+		defer projections.Unlock()
+		if projections.enabled && guildID != "" {
+			apply(projection(guildID))
+		}
+	}
+
+	s.AddHandler(func(s *discordgo.Session, g *discordgo.GuildCreate) {
+		projections.Lock()
+		enabled := projections.enabled
+		if enabled {
+			loadGuildProjection(g.Guild)
+		}
+		projections.Unlock()
+
+		if enabled && s.Identify.Intents&discordgo.IntentGuildMembers != 0 && len(g.Members) < g.MemberCount {
+			_ = s.RequestGuildMembers(g.ID, "", 0, "", false) // Arrives as GUILD_MEMBERS_CHUNK events.
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, g *discordgo.GuildDelete) {
+		projections.Lock()
+		defer projections.Unlock()
+		delete(projections.guilds, g.ID)
+	})
+	s.AddHandler(func(s *discordgo.Session, chunk *discordgo.GuildMembersChunk) {
+		update(chunk.GuildID, func(p *guildProjection) {
+			for _, m := range chunk.Members {
+				if m.User != nil {
+					p.members[m.User.ID] = copyMember(m)
+				}
+			}
+			p.membersChanged()
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberAdd) {
+		update(m.GuildID, func(p *guildProjection) {
+			if m.User != nil {
+				p.members[m.User.ID] = copyMember(m.Member)
+				p.memberCount++
+				p.membersChanged()
+			}
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberUpdate) {
+		update(m.GuildID, func(p *guildProjection) {
+			if m.User != nil {
+				p.members[m.User.ID] = copyMember(m.Member)
+				p.membersChanged()
+			}
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.GuildMemberRemove) {
+		update(m.GuildID, func(p *guildProjection) {
+			if m.User != nil {
+				delete(p.members, m.User.ID)
+				p.memberCount--
+				p.membersChanged()
+			}
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.GuildRoleCreate) {
+		update(r.GuildID, func(p *guildProjection) {
+			p.roles[r.Role.ID] = copyRole(r.Role)
+			p.membersChanged()
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.GuildRoleUpdate) {
+		update(r.GuildID, func(p *guildProjection) {
+			p.roles[r.Role.ID] = copyRole(r.Role)
+			p.membersChanged()
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.GuildRoleDelete) {
+		update(r.GuildID, func(p *guildProjection) {
+			delete(p.roles, r.RoleID)
+			p.membersChanged()
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.ChannelCreate) {
+		update(c.GuildID, func(p *guildProjection) {
+			p.channels[c.ID] = copyChannel(c.Channel)
+			p.channelsChanged()
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.ChannelUpdate) {
+		update(c.GuildID, func(p *guildProjection) {
+			p.channels[c.ID] = copyChannel(c.Channel)
+			p.channelsChanged()
+		})
+	})
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.ChannelDelete) {
+		update(c.GuildID, func(p *guildProjection) {
+			delete(p.channels, c.ID)
+			p.channelsChanged()
+		})
+	})
+}
+
+// projectionUnavailable responds for guilds without a projection: HTTP status 404 (Not Found) if
+// projections are disabled, else HTTP status 503 (Service Unavailable) until the guild is received
+// from the gateway.
+func projectionUnavailable(c *fiber.Ctx) error {
+	if !projections.enabled {
+		return c.Status(fiber.StatusNotFound).SendString("Projections are not enabled")
+	}
+	return c.Status(fiber.StatusServiceUnavailable).SendString("Projection of the guild is not built yet")
+}
+
+// GetMemberDirectory retrieves the member directory of the guild from memory.
+//
+// The directory lists the members with their roles resolved, highest first, and is kept up to date
+// from gateway events if the Projections option is set, so it does not call Discord. It holds all
+// members with the guild members intent; without it, only the members seen in events are listed
+// and complete is false.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Query Parameters:
+//   - role: Lists only the members with the role of this ID.
+//
+// Returns:
+//   - On success, it returns the member directory as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if projections are disabled, or
+//     HTTP status 503 (Service Unavailable) if the guild has not been received yet.
+// @Summary		Get Member Directory
+// @Description	Retrieve the members of the guild with their roles resolved, kept in memory from gateway events.
+// @Tags			Projections
+// @Param			role	query		string	false	"Role ID to filter by"
+// @Success		200		{object}	MemberDirectory
+// @Failure		404		{object}	error
+// @Failure		503		{object}	error
+// @Router			/api/guild/projections/members [get]
+func GetMemberDirectory(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	projections.Lock()
+	p, ok := projections.guilds[guildID]
+	if !ok || !projections.enabled {
+		defer projections.Unlock()
+		return projectionUnavailable(c)
+	}
+	directory := p.memberDirectory()
+	projections.Unlock()
+
+	if roleID := c.Query("role"); roleID != "" {
+		filtered := *directory
+		filtered.Members = slices.DeleteFunc(slices.Clone(directory.Members), func(m DirectoryMember) bool {
+			return !slices.ContainsFunc(m.Roles, func(r DirectoryRole) bool { return r.ID == roleID })
+		})
+		return c.JSON(filtered)
+	}
+	return c.JSON(directory)
+}
+
+// GetChannelTree retrieves the channel tree of the guild from memory.
+//
+// The tree lists the categories with their channels in display order, each with a summary of its
+// permission overwrites, and is kept up to date from gateway events if the Projections option is
+// set, so it does not call Discord. Channels outside the token scope are left out, and categories
+// only kept for the channels in them.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the channel tree as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if projections are disabled, or
+//     HTTP status 503 (Service Unavailable) if the guild has not been received yet.
+// @Summary		Get Channel Tree
+// @Description	Retrieve the channels of the guild by category with overwrite summaries, kept in memory from gateway events.
+// @Tags			Projections
+// @Success		200	{object}	ChannelTree
+// @Failure		404	{object}	error
+// @Failure		503	{object}	error
+// @Router			/api/guild/projections/channels [get]
+func GetChannelTree(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	projections.Lock()
+	p, ok := projections.guilds[guildID]
+	if !ok || !projections.enabled {
+		defer projections.Unlock()
+		return projectionUnavailable(c)
+	}
+	tree := p.channelTree()
+	projections.Unlock()
+
+	if unrestricted(c) {
+		return c.JSON(tree)
+	}
+
+	// Hides the channels outside the token scope.
+	scoped := ChannelTree{Channels: []ChannelNode{}, UpdatedAt: tree.UpdatedAt}
+	for _, node := range tree.Channels {
+		children := slices.DeleteFunc(slices.Clone(node.Children), func(child ChannelNode) bool {
+			return !channelAllowed(c, child.ID, false)
+		})
+		if len(children) > 0 || channelAllowed(c, node.ID, false) {
+			node.Children = children
+			scoped.Channels = append(scoped.Channels, node)
+		}
+	}
+	return c.JSON(scoped)
+}
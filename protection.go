@@ -0,0 +1,77 @@
+package disgm
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Protection lists channels and roles that cannot be modified or deleted through the API.
+//
+// A request can still change a protected channel or role by setting the query flag override=true,
+// so dashboards have to opt in explicitly instead of nuking #rules by accident.
+type Protection struct {
+	ChannelIDs   []string // IDs of protected channels
+	RoleIDs      []string // IDs of protected roles
+	NamePatterns []string // Case-insensitive patterns matched against channel and role names, e.g. "rules" or "mod-*"
+}
+
+// The protection list, configured by New from the options.
+var protection Protection
+
+// protectedName reports whether a channel or role name matches one of the protected patterns.
+func protectedName(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range protection.NamePatterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// channelProtected reports whether a request would change a protected channel without override.
+func channelProtected(c *fiber.Ctx, s *discordgo.Session, channelID string) bool {
+	if c.QueryBool("override") {
+		return false
+	}
+	if slices.Contains(protection.ChannelIDs, channelID) {
+		return true
+	}
+	if len(protection.NamePatterns) == 0 {
+		return false
+	}
+
+	channel, err := cachedChannel(s, channelID)
+	if err != nil {
+		return false
+	}
+	return protectedName(channel.Name)
+}
+
+// roleProtected reports whether a request would change a protected role without override.
+func roleProtected(c *fiber.Ctx, s *discordgo.Session, guildID, roleID string) bool {
+	if c.QueryBool("override") {
+		return false
+	}
+	if slices.Contains(protection.RoleIDs, roleID) {
+		return true
+	}
+	if len(protection.NamePatterns) == 0 {
+		return false
+	}
+
+	role, err := cachedRole(s, guildID, roleID)
+	if err != nil {
+		return false
+	}
+	return protectedName(role.Name)
+}
+
+// protectedForbidden responds with HTTP status 403 (Forbidden) for protected channels and roles.
+func protectedForbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).SendString("Forbidden: resource is protected, set override=true to change it")
+}
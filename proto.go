@@ -0,0 +1,413 @@
+package disgm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Encodings of the events sent to a WebSocket client, chosen with the encoding query parameter.
+const (
+	EncodingJSON  = "json"  // Events are sent as JSON text messages (default).
+	EncodingProto = "proto" // Events are sent as protobuf binary messages, see /api/schema/events.proto.
+)
+
+// protoKind is how a Go value is represented in the protobuf encoding.
+type protoKind int
+
+const (
+	protoJSON    protoKind = iota // Values without a protobuf equivalent, sent as a JSON string
+	protoString                   // string
+	protoBool                     // bool
+	protoInt                      // int64
+	protoUint                     // uint64
+	protoDouble                   // double
+	protoBytes                    // bytes
+	protoTime                     // string in RFC 3339 format
+	protoMessage                  // nested message
+)
+
+// protoShape is the protobuf representation of a struct field.
+type protoShape struct {
+	kind     protoKind
+	repeated bool
+	isMap    bool
+	keyKind  protoKind
+	elem     reflect.Type // Type of the value, element or map value, without pointers
+}
+
+// protoField is a serialized field of a struct with its protobuf field number.
+type protoField struct {
+	name   string
+	number int
+	index  []int
+	shape  protoShape
+}
+
+// protoFieldCache caches the fields of the struct types, keyed by reflect.Type.
+var protoFieldCache sync.Map
+
+// derefType strips pointers from a type.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// kindOf returns the protobuf kind of a single, non-repeated value.
+func kindOf(t reflect.Type) protoKind {
+	t = derefType(t)
+	switch t {
+	case timeType:
+		return protoTime
+	case rawMessageType:
+		return protoJSON
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return protoString
+	case reflect.Bool:
+		return protoBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return protoInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return protoUint
+	case reflect.Float32, reflect.Float64:
+		return protoDouble
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return protoBytes
+		}
+	case reflect.Struct:
+		if t.Name() != "" {
+			return protoMessage
+		}
+	}
+	return protoJSON
+}
+
+// shapeOf returns the protobuf representation of a field type. Nested containers, which
+// protobuf cannot express, are sent as JSON strings.
+func shapeOf(t reflect.Type, asString bool) protoShape {
+	t = derefType(t)
+	if asString {
+		return protoShape{kind: protoString, elem: t}
+	}
+
+	switch {
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8, t.Kind() == reflect.Array:
+		elem := derefType(t.Elem())
+		return protoShape{kind: kindOf(elem), repeated: true, elem: elem}
+	case t.Kind() == reflect.Map:
+		key := kindOf(t.Key())
+		if key == protoString || key == protoInt || key == protoUint {
+			elem := derefType(t.Elem())
+			return protoShape{kind: kindOf(elem), isMap: true, keyKind: key, elem: elem}
+		}
+		return protoShape{kind: protoJSON, elem: t}
+	}
+	return protoShape{kind: kindOf(t), elem: t}
+}
+
+// protoFields returns the serialized fields of a struct in declaration order, flattening embedded
+// structs like encoding/json. Field numbers follow that order, starting at 1.
+func protoFields(t reflect.Type) []protoField {
+	if cached, ok := protoFieldCache.Load(t); ok {
+		return cached.([]protoField)
+	}
+
+	var fields []protoField
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			idx := append(slices.Clone(index), i)
+
+			if f.Anonymous && name == "" && derefType(f.Type).Kind() == reflect.Struct {
+				walk(derefType(f.Type), idx)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fields = append(fields, protoField{
+				name:   name,
+				number: len(fields) + 1,
+				index:  idx,
+				shape:  shapeOf(f.Type, strings.Contains(opts, "string")),
+			})
+		}
+	}
+	walk(t, nil)
+
+	protoFieldCache.Store(t, fields)
+	return fields
+}
+
+// protoMessageName returns the message name of a struct type, e.g. ModelsMessage for models.Message.
+func protoMessageName(t reflect.Type) string {
+	var name strings.Builder
+	for _, part := range strings.Split(schemaName(t), ".") {
+		for i, r := range part {
+			switch {
+			case i == 0:
+				name.WriteRune(unicode.ToUpper(r))
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				name.WriteRune(r)
+			}
+		}
+	}
+	return name.String()
+}
+
+// protoTypeName returns the protobuf type of a value of the given kind.
+func protoTypeName(kind protoKind, t reflect.Type) string {
+	switch kind {
+	case protoBool:
+		return "bool"
+	case protoInt:
+		return "int64"
+	case protoUint:
+		return "uint64"
+	case protoDouble:
+		return "double"
+	case protoBytes:
+		return "bytes"
+	case protoMessage:
+		return protoMessageName(t)
+	}
+	return "string"
+}
+
+// eventProto is the generated .proto file, built on first use.
+var eventProto = sync.OnceValue(func() string {
+	messages := make(map[string]reflect.Type)
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		name := protoMessageName(t)
+		if _, ok := messages[name]; ok {
+			return
+		}
+		messages[name] = t
+		for _, f := range protoFields(t) {
+			if f.shape.kind == protoMessage {
+				collect(f.shape.elem)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(eventPayloads))
+	for name, payload := range eventPayloads {
+		names = append(names, name)
+		collect(reflect.TypeOf(payload))
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	b.WriteString("// Code generated by disgm from its models. DO NOT EDIT.\n\n")
+	b.WriteString("syntax = \"proto3\";\n\npackage disgm.events;\n\n")
+	b.WriteString("// Event is the envelope of every event sent to WebSocket clients using the proto encoding.\n")
+	b.WriteString("// data holds the payload message of the event, whose type depends on name:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "//   %s: %s\n", name, protoMessageName(reflect.TypeOf(eventPayloads[name])))
+	}
+	b.WriteString("// Times are RFC 3339 strings; values without a protobuf equivalent are JSON strings.\n")
+	b.WriteString("// error is set instead of data if the payload could not be encoded, e.g. for events without a payload type.\n")
+	b.WriteString("message Event {\n  string name = 1;\n  bytes data = 2;\n  bool replay = 3;\n  string error = 4;\n}\n")
+	b.WriteString("\n// EventBatch holds the events sent in one frame to clients that enabled batching with the batch op.\n")
+	b.WriteString("message EventBatch {\n  repeated Event events = 1;\n}\n")
+
+	messageNames := make([]string, 0, len(messages))
+	for name := range messages {
+		messageNames = append(messageNames, name)
+	}
+	slices.Sort(messageNames)
+
+	for _, name := range messageNames {
+		t := messages[name]
+		fmt.Fprintf(&b, "\n// %s is generated from %s.\nmessage %s {\n", name, schemaName(t), name)
+		for _, f := range protoFields(t) {
+			typ := protoTypeName(f.shape.kind, f.shape.elem)
+			switch {
+			case f.shape.isMap:
+				typ = fmt.Sprintf("map<%s, %s>", protoTypeName(f.shape.keyKind, nil), typ)
+			case f.shape.repeated:
+				typ = "repeated " + typ
+			}
+			fmt.Fprintf(&b, "  %s %s = %d;\n", typ, f.name, f.number)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+})
+
+// appendTag appends the key of a protobuf field.
+func appendTag(b []byte, number int, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(number)<<3|uint64(wireType))
+}
+
+// appendLengthDelimited appends a field of wire type 2.
+func appendLengthDelimited(b []byte, number int, data []byte) []byte {
+	b = appendTag(b, number, 2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// appendProtoMessage appends the protobuf encoding of a struct value.
+func appendProtoMessage(b []byte, v reflect.Value) []byte {
+	for _, f := range protoFields(v.Type()) {
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil {
+			continue // Field of a nil embedded struct.
+		}
+		b = appendProtoField(b, f.number, fv, f.shape)
+	}
+	return b
+}
+
+// appendProtoField appends a field of any shape, omitting empty values like proto3.
+func appendProtoField(b []byte, number int, v reflect.Value, shape protoShape) []byte {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface && shape.kind != protoJSON {
+		if v.IsNil() {
+			return b
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case shape.isMap:
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := appendProtoValue(nil, 1, iter.Key(), shape.keyKind, true)
+			entry = appendProtoValue(entry, 2, iter.Value(), shape.kind, true)
+			b = appendLengthDelimited(b, number, entry)
+		}
+		return b
+	case shape.repeated:
+		for i := 0; i < v.Len(); i++ {
+			b = appendProtoValue(b, number, v.Index(i), shape.kind, true)
+		}
+		return b
+	}
+	return appendProtoValue(b, number, v, shape.kind, false)
+}
+
+// appendProtoValue appends a single value. Zero values are omitted unless force is set,
+// which keeps the positions of repeated elements and map values.
+func appendProtoValue(b []byte, number int, v reflect.Value, kind protoKind, force bool) []byte {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return b
+		}
+		v = v.Elem()
+	}
+	if !force && v.IsZero() {
+		return b
+	}
+
+	switch kind {
+	case protoString:
+		if v.Kind() == reflect.String {
+			return appendLengthDelimited(b, number, []byte(v.String()))
+		}
+		return appendLengthDelimited(b, number, []byte(fmt.Sprint(v.Interface())))
+	case protoTime:
+		return appendLengthDelimited(b, number, []byte(v.Interface().(time.Time).Format(time.RFC3339Nano)))
+	case protoBool:
+		b = appendTag(b, number, 0)
+		if v.Bool() {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case protoInt:
+		return binary.AppendUvarint(appendTag(b, number, 0), uint64(v.Int()))
+	case protoUint:
+		return binary.AppendUvarint(appendTag(b, number, 0), v.Uint())
+	case protoDouble:
+		return binary.LittleEndian.AppendUint64(appendTag(b, number, 1), math.Float64bits(v.Float()))
+	case protoBytes:
+		return appendLengthDelimited(b, number, v.Bytes())
+	case protoMessage:
+		return appendLengthDelimited(b, number, appendProtoMessage(nil, v))
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return b
+	}
+	return appendLengthDelimited(b, number, data)
+}
+
+// protoErrorsLogged holds the names of the events whose payload failed to encode, so each is logged once.
+var protoErrorsLogged sync.Map
+
+// appendProtoEvent appends an event envelope with its payload, decoded from JSON into the
+// payload type registered for the event. If the event has no payload type or its payload cannot
+// be decoded, data is left empty and the error field tells clients why.
+func appendProtoEvent(b []byte, name string, data json.RawMessage, replay bool) []byte {
+	b = appendLengthDelimited(b, 1, []byte(name))
+
+	var encodeErr error
+	if payload, ok := eventPayloads[name]; !ok {
+		encodeErr = fmt.Errorf("no protobuf payload type for event %s", name)
+	} else {
+		v := reflect.New(reflect.TypeOf(payload))
+		if encodeErr = json.Unmarshal(data, v.Interface()); encodeErr == nil {
+			b = appendLengthDelimited(b, 2, appendProtoMessage(nil, v.Elem()))
+		}
+	}
+	if encodeErr != nil {
+		b = appendLengthDelimited(b, 4, []byte(encodeErr.Error()))
+		if _, logged := protoErrorsLogged.LoadOrStore(name, true); !logged {
+			log.Printf("Failed to encode %s event as protobuf: %v", name, encodeErr)
+		}
+	}
+
+	if replay {
+		b = append(appendTag(b, 3, 0), 1)
+	}
+	return b
+}
+
+// GetEventProto returns the protobuf definitions of the events disgm emits.
+//
+// The definitions are generated from the same models as the JSON Schema and describe the binary
+// messages sent to WebSocket clients that connect with encoding=proto.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the .proto file as text.
+// @Summary		Get Event Protobuf Definitions
+// @Description	Retrieve the .proto definitions of the WebSocket events for the proto encoding.
+// @Tags			Events
+// @Produce		plain
+// @Success		200	{string}	string
+// @Router			/api/schema/events.proto [get]
+func GetEventProto(c *fiber.Ctx, s *discordgo.Session) error {
+	c.Set(fiber.HeaderContentType, "text/plain; charset=utf-8")
+	return c.SendString(eventProto())
+}
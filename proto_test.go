@@ -0,0 +1,323 @@
+package disgm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// compileEventProto compiles the generated .proto file with a real protobuf compiler.
+func compileEventProto(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"events.proto": eventProto()}),
+		},
+	}
+	files, err := compiler.Compile(context.Background(), "events.proto")
+	if err != nil {
+		t.Fatalf("events.proto does not compile: %v", err)
+	}
+	return files[0]
+}
+
+// decodeEvent decodes an event envelope with the compiled descriptors, failing on unknown fields.
+func decodeEvent(t *testing.T, file protoreflect.FileDescriptor, b []byte) *dynamicpb.Message {
+	t.Helper()
+
+	event := dynamicpb.NewMessage(file.Messages().ByName("Event"))
+	if err := proto.Unmarshal(b, event); err != nil {
+		t.Fatalf("Event does not decode: %v", err)
+	}
+	assertNoUnknown(t, "Event", event)
+	return event
+}
+
+// fillSample sets every field of a value to a non-zero sample, down to the given depth, so
+// every field of the generated messages is encoded. Interfaces are left empty.
+func fillSample(v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if depth > 0 && v.Type().Elem().Kind() != reflect.Interface {
+			v.Set(reflect.New(v.Type().Elem()))
+			fillSample(v.Elem(), depth)
+		}
+	case reflect.String:
+		v.SetString("sample")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(7)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Slice:
+		if v.Type() == rawMessageType {
+			v.SetBytes([]byte(`{"sample":true}`))
+			return
+		}
+		// Pointer elements need another level, as protobuf has no null elements.
+		elem := v.Type().Elem()
+		if elem.Kind() == reflect.Pointer && elem.Elem().Kind() == reflect.Interface {
+			return
+		}
+		if depth > 1 || depth > 0 && elem.Kind() != reflect.Pointer {
+			v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+			fillSample(v.Index(0), depth-1)
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillSample(v.Index(i), depth-1)
+		}
+	case reflect.Map:
+		if depth > 0 {
+			key, value := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			fillSample(key, depth-1)
+			fillSample(value, depth-1)
+			v.Set(reflect.MakeMap(v.Type()))
+			v.SetMapIndex(key, value)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			v.Set(reflect.ValueOf(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillSample(v.Field(i), depth-1)
+			}
+		}
+	}
+}
+
+// assertNoUnknown fails if a decoded message or one of its nested messages has fields the
+// descriptors do not declare, or fields whose wire type does not match their declaration.
+func assertNoUnknown(t *testing.T, path string, m protoreflect.Message) {
+	t.Helper()
+
+	if len(m.GetUnknown()) > 0 {
+		t.Errorf("%s: unknown fields in the encoding", path)
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+					assertNoUnknown(t, fmt.Sprintf("%s.%s[%v]", path, fd.Name(), k), v.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len(); i++ {
+					assertNoUnknown(t, fmt.Sprintf("%s.%s[%d]", path, fd.Name(), i), v.List().Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			assertNoUnknown(t, path+"."+string(fd.Name()), v.Message())
+		}
+		return true
+	})
+}
+
+// protoExpected returns the value a real decoder must return for a single Go value.
+func protoExpected(v reflect.Value, kind protoKind) interface{} {
+	switch kind {
+	case protoString:
+		if v.Kind() == reflect.String {
+			return v.String()
+		}
+		return fmt.Sprint(v.Interface())
+	case protoTime:
+		return v.Interface().(time.Time).Format(time.RFC3339Nano)
+	case protoBool:
+		return v.Bool()
+	case protoInt:
+		return v.Int()
+	case protoUint:
+		return v.Uint()
+	case protoDouble:
+		return v.Float()
+	case protoBytes:
+		return v.Bytes()
+	}
+	data, _ := json.Marshal(v.Interface())
+	return string(data)
+}
+
+// assertProtoValue compares a single decoded value with the Go value it was encoded from.
+func assertProtoValue(t *testing.T, path string, got protoreflect.Value, v reflect.Value, kind protoKind) {
+	t.Helper()
+
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if kind == protoMessage {
+		assertProtoMessage(t, path, got.Message(), v)
+		return
+	}
+
+	want := protoExpected(v, kind)
+	if b, ok := want.([]byte); ok {
+		if !bytes.Equal(got.Bytes(), b) {
+			t.Errorf("%s: got %q, want %q", path, got.Bytes(), b)
+		}
+		return
+	}
+	if got.Interface() != want {
+		t.Errorf("%s: got %v, want %v", path, got.Interface(), want)
+	}
+}
+
+// assertProtoMessage compares a decoded message field by field with the Go struct it was
+// encoded from, checking the names and numbers of the fields against the compiled descriptor.
+func assertProtoMessage(t *testing.T, path string, m protoreflect.Message, v reflect.Value) {
+	t.Helper()
+
+	desc := m.Descriptor()
+	if name := protoMessageName(v.Type()); string(desc.Name()) != name {
+		t.Fatalf("%s: decoded as %s, want %s", path, desc.Name(), name)
+	}
+
+	for _, f := range protoFields(v.Type()) {
+		fieldPath := path + "." + f.name
+		fd := desc.Fields().ByNumber(protoreflect.FieldNumber(f.number))
+		if fd == nil || string(fd.Name()) != f.name {
+			t.Errorf("%s: field %d is not declared with this name", fieldPath, f.number)
+			continue
+		}
+
+		fv, err := v.FieldByIndexErr(f.index)
+		for err == nil && (fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface) && !fv.IsNil() && f.shape.kind != protoJSON {
+			fv = fv.Elem()
+		}
+		if err != nil || !fv.IsValid() || fv.IsZero() {
+			if m.Has(fd) && !f.shape.repeated && !f.shape.isMap {
+				t.Errorf("%s: empty value was encoded", fieldPath)
+			}
+			continue
+		}
+		if !m.Has(fd) {
+			t.Errorf("%s: value %v was not encoded", fieldPath, fv.Interface())
+			continue
+		}
+
+		got := m.Get(fd)
+		switch {
+		case f.shape.isMap:
+			if got.Map().Len() != fv.Len() {
+				t.Errorf("%s: got %d entries, want %d", fieldPath, got.Map().Len(), fv.Len())
+			}
+			iter := fv.MapRange()
+			for iter.Next() {
+				key := protoreflect.ValueOf(protoExpected(iter.Key(), f.shape.keyKind)).MapKey()
+				if !got.Map().Has(key) {
+					t.Errorf("%s: missing key %v", fieldPath, key)
+					continue
+				}
+				assertProtoValue(t, fmt.Sprintf("%s[%v]", fieldPath, key), got.Map().Get(key), iter.Value(), f.shape.kind)
+			}
+		case f.shape.repeated:
+			if got.List().Len() != fv.Len() {
+				t.Errorf("%s: got %d elements, want %d", fieldPath, got.List().Len(), fv.Len())
+				continue
+			}
+			for i := 0; i < fv.Len(); i++ {
+				assertProtoValue(t, fmt.Sprintf("%s[%d]", fieldPath, i), got.List().Get(i), fv.Index(i), f.shape.kind)
+			}
+		default:
+			assertProtoValue(t, fieldPath, got, fv, f.shape.kind)
+		}
+	}
+}
+
+// TestProtoEventRoundTrip encodes a filled payload of every event and decodes it with a real
+// protobuf implementation, using the descriptors compiled from /api/schema/events.proto.
+func TestProtoEventRoundTrip(t *testing.T) {
+	file := compileEventProto(t)
+
+	names := make([]string, 0, len(eventPayloads))
+	for name := range eventPayloads {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			typ := reflect.TypeOf(eventPayloads[name])
+
+			sample := reflect.New(typ)
+			fillSample(sample.Elem(), 4)
+			data, err := json.Marshal(sample.Interface())
+			if err != nil {
+				t.Fatalf("sample does not marshal: %v", err)
+			}
+
+			// The payload is encoded as the API sends it, after a JSON round trip.
+			want := reflect.New(typ)
+			if err := json.Unmarshal(data, want.Interface()); err != nil {
+				t.Fatalf("sample does not unmarshal: %v", err)
+			}
+
+			event := decodeEvent(t, file, appendProtoEvent(nil, name, data, true))
+			fields := event.Descriptor().Fields()
+			if got := event.Get(fields.ByName("name")).String(); got != name {
+				t.Errorf("name: got %q", got)
+			}
+			if !event.Get(fields.ByName("replay")).Bool() {
+				t.Error("replay: not set")
+			}
+			if got := event.Get(fields.ByName("error")).String(); got != "" {
+				t.Fatalf("error: %s", got)
+			}
+
+			payload := dynamicpb.NewMessage(file.Messages().ByName(protoreflect.Name(protoMessageName(typ))))
+			if err := proto.Unmarshal(event.Get(fields.ByName("data")).Bytes(), payload); err != nil {
+				t.Fatalf("data does not decode: %v", err)
+			}
+			assertNoUnknown(t, name, payload)
+			assertProtoMessage(t, name, payload, want.Elem())
+		})
+	}
+}
+
+// TestProtoEventError checks that events whose payload cannot be encoded carry an error instead of data.
+func TestProtoEventError(t *testing.T) {
+	file := compileEventProto(t)
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"UNREGISTERED_EVENT", `{"id":"1"}`},
+		{"MESSAGE_CREATE", `{"id":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := decodeEvent(t, file, appendProtoEvent(nil, tt.name, json.RawMessage(tt.data), false))
+			fields := event.Descriptor().Fields()
+
+			if event.Has(fields.ByName("data")) {
+				t.Error("data: set for a payload that cannot be encoded")
+			}
+			if event.Get(fields.ByName("error")).String() == "" {
+				t.Error("error: not set")
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package disgm
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	wsProtocolVersion        = 2                // Latest version of the WebSocket protocol.
+	wsDefaultProtocolVersion = 1                // Version of clients that do not ask for one, kept for compatibility.
+	heartbeatInterval        = 30 * time.Second // Interval in which clients send the heartbeat op and disgm pings them.
+	heartbeatTimeout         = 2 * heartbeatInterval
+	closeUnsupportedVersion  = 4001 // Close code of clients asking for a protocol version disgm does not speak.
+)
+
+// wsProtocolVersions are the protocol versions disgm speaks. Version 1 sends a plain text
+// greeting before the HELLO event; from version 2 on, HELLO is the first frame.
+var wsProtocolVersions = []int{1, 2}
+
+// Hello is the payload of the HELLO event, the first event sent on a connection.
+type Hello struct {
+	ClientID            string   `json:"client_id"`             // ID of the connection, used to target it with event replays
+	ProtocolVersion     int      `json:"protocol_version"`      // Protocol version of the connection
+	HeartbeatIntervalMs int      `json:"heartbeat_interval_ms"` // Interval in which the client should send the heartbeat op
+	Ops                 []string `json:"ops"`                   // Ops the client may send
+	Encoding            string   `json:"encoding"`              // Encoding of the events sent to the client, json or proto
+	Encrypted           bool     `json:"encrypted"`             // Whether the frames are sealed for the public key of the client
+}
+
+// HeartbeatAck is the payload of the HEARTBEAT_ACK event, the reply to the heartbeat op.
+type HeartbeatAck struct {
+	Time time.Time `json:"time"` // Server time the heartbeat was received
+}
+
+// wsOp describes an op clients send over the WebSocket.
+type wsOp struct {
+	name        string
+	description string
+	payload     interface{} // Example of the payload type, nil if the op takes no data
+	replies     []string    // Events sent in reply
+}
+
+// wsOps lists the ops clients may send, in the order they are documented.
+var wsOps = []wsOp{
+	{"heartbeat", "Keeps the connection alive. Connections that send nothing and answer no ping for twice the heartbeat interval are closed.", nil, []string{"HEARTBEAT_ACK"}},
+	{"identify", "Labels the connection with the name, version and purpose of the client, as the client_* query parameters do.", ClientIdentity{}, nil},
+	{"subscribe", "Limits the events sent to the client to the given event and channel patterns. Empty lists match everything.", Subscription{}, []string{"SUBSCRIBED", "SUBSCRIBE_ERROR"}},
+	{"batch", "Collects events and sends them in one frame per interval. An interval of 0 turns batching off.", BatchOptions{}, []string{"BATCH_UPDATED", "BATCH_ERROR"}},
+	{"chat", "Sends a message to a channel of the guild as the bot, if the chat op is enabled.", ChatMessage{}, []string{"CHAT_SENT", "CHAT_ERROR"}},
+	{"relay", "Sends an application message, such as presence in a dashboard, to the other clients of the guild or the client with the given connection ID as CLIENT_RELAY, if the relay op is enabled.", ClientRelay{}, []string{"RELAY_SENT", "RELAY_ERROR"}},
+	{"encrypt", "Seals every frame sent to the client from then on for its X25519 public key, starting with the ENCRYPTION_ENABLED reply.", Encryption{}, []string{"ENCRYPTION_ENABLED", "ENCRYPTION_ERROR"}},
+}
+
+// wsOpNames returns the names of the ops clients may send.
+func wsOpNames() []string {
+	names := make([]string, len(wsOps))
+	for i, op := range wsOps {
+		names[i] = op.name
+	}
+	return names
+}
+
+// negotiateVersion returns the protocol version asked for with the v query parameter, the
+// default version if there is none, or false if disgm does not speak it.
+func negotiateVersion(v string) (int, bool) {
+	if v == "" {
+		return wsDefaultProtocolVersion, true
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil || !slices.Contains(wsProtocolVersions, version) {
+		return 0, false
+	}
+	return version, true
+}
+
+// keepAlive pings a connection every heartbeat interval until done is closed, so clients that
+// do not send the heartbeat op stay connected as long as their WebSocket stack answers pings.
+func keepAlive(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(clientWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// WSProtocol describes the disgm WebSocket protocol.
+type WSProtocol struct {
+	Version             int                    `json:"version"`                    // Latest protocol version
+	Versions            []int                  `json:"versions"`                   // Protocol versions disgm speaks
+	DefaultVersion      int                    `json:"default_version"`            // Version of clients that do not ask for one
+	URL                 string                 `json:"url"`                        // Path of the WebSocket endpoint
+	Query               []WSProtocolParam      `json:"query"`                      // Query parameters of the WebSocket endpoint
+	HeartbeatIntervalMs int                    `json:"heartbeat_interval_ms"`      // Interval in which clients should send the heartbeat op
+	Encodings           []WSProtocolParam      `json:"encodings"`                  // Encodings of the events
+	Frames              []WSProtocolFrame      `json:"frames"`                     // Formats of the frames
+	Ops                 []WSProtocolOp         `json:"ops"`                        // Ops clients may send
+	CloseCodes          []WSProtocolCloseCode  `json:"close_codes"`                // Close codes disgm sends
+	EventSchema         string                 `json:"event_schema"`               // Path of the JSON Schema of the events
+	Defs                map[string]interface{} `json:"$defs" swaggertype:"object"` // JSON Schemas of the op payloads
+}
+
+// WSProtocolParam describes a query parameter or encoding of the WebSocket protocol.
+type WSProtocolParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// WSProtocolFrame describes the format of a WebSocket frame.
+type WSProtocolFrame struct {
+	Name        string `json:"name"`
+	Direction   string `json:"direction"` // server or client
+	Description string `json:"description"`
+	Example     string `json:"example,omitempty"`
+}
+
+// WSProtocolOp describes an op clients may send.
+type WSProtocolOp struct {
+	Op          string                 `json:"op"`
+	Description string                 `json:"description"`
+	Data        map[string]interface{} `json:"data,omitempty" swaggertype:"object"` // JSON Schema of the payload, none if the op takes no data
+	Replies     []string               `json:"replies,omitempty"`                   // Events sent in reply
+}
+
+// WSProtocolCloseCode describes a close code disgm sends.
+type WSProtocolCloseCode struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+// wsProtocol is the description of the WebSocket protocol, built once.
+var wsProtocol = sync.OnceValue(func() WSProtocol {
+	g := &schemaGenerator{defs: make(map[string]interface{})}
+
+	ops := make([]WSProtocolOp, len(wsOps))
+	for i, op := range wsOps {
+		ops[i] = WSProtocolOp{Op: op.name, Description: op.description, Replies: op.replies}
+		if op.payload != nil {
+			ops[i].Data = g.schemaFor(reflect.TypeOf(op.payload))
+		}
+	}
+
+	return WSProtocol{
+		Version:        wsProtocolVersion,
+		Versions:       wsProtocolVersions,
+		DefaultVersion: wsDefaultProtocolVersion,
+		URL:            "/ws",
+		Query: []WSProtocolParam{
+			{"v", "Protocol version. Versions disgm does not speak are refused with close code 4001."},
+			{"encoding", "Encoding of the events, json (default) or proto."},
+			{"client_name", "Name of the client, shown in logs and the clients endpoint."},
+			{"client_version", "Version of the client."},
+			{"client_purpose", "What the client uses the connection for."},
+			{"public_key", "Base64 X25519 public key every frame is sealed for, HELLO included, as the encrypt op does. Required with the RequireEncryption option."},
+		},
+		HeartbeatIntervalMs: int(heartbeatInterval / time.Millisecond),
+		Encodings: []WSProtocolParam{
+			{EncodingJSON, "Events are JSON text frames."},
+			{EncodingProto, "Events are Protobuf binary frames, described by /api/schema/events.proto. Ops are JSON in every encoding."},
+		},
+		Frames: []WSProtocolFrame{
+			{"greeting", "server", "Plain text frame sent before HELLO in protocol version 1 only. Clients should ignore it.", "Welcome! You are connected."},
+			{"event", "server", "An event with its name and payload. Replayed events have replay set.", `{"name":"HELLO","data":{"client_id":"…","protocol_version":2,"heartbeat_interval_ms":30000,"ops":["heartbeat"],"encoding":"json"}}`},
+			{"batch", "server", "An array of events, sent instead of single events while batching is on.", `[{"name":"TYPING_START","data":{}},{"name":"MESSAGE_CREATE","data":{}}]`},
+			{"sealed", "server", "A binary frame holding any other server frame in the encoding of the client, sealed with libsodium's crypto_box_seal once the client registered a public key.", ""},
+			{"op", "client", "An op with its payload. Other messages are logged and ignored.", `{"op":"heartbeat"}`},
+		},
+		Ops: ops,
+		CloseCodes: []WSProtocolCloseCode{
+			{closeKicked, "The client was disconnected with the kick endpoint."},
+			{closeUnsupportedVersion, "The protocol version asked for is not supported."},
+			{closeTokenRevoked, "The token of the connection was revoked or expired."},
+			{closeEncryptionRequired, "The public key is invalid, or missing while encryption is required."},
+		},
+		EventSchema: "/api/schema/events",
+		Defs:        g.defs,
+	}
+})
+
+// GetWSProtocol returns the description of the WebSocket protocol.
+//
+// The description lists the protocol versions, connection parameters, frame formats, ops with
+// the JSON Schema of their payloads, and close codes, so clients can implement the protocol
+// without reading the disgm source. The events themselves are described by the event schema.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the protocol description as JSON.
+// @Summary		Get WebSocket Protocol
+// @Description	Retrieve the versions, frame formats, ops and close codes of the WebSocket protocol.
+// @Tags			WebSocket
+// @Produce		json
+// @Success		200	{object}	WSProtocol
+// @Router			/api/ws/protocol [get]
+func GetWSProtocol(c *fiber.Ctx, s *discordgo.Session) error {
+	return c.JSON(wsProtocol())
+}
@@ -0,0 +1,124 @@
+package disgm
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBucketLimit  = 5                      // Requests per window assumed for routes without an observed rate limit.
+	defaultBucketWindow = 5 * time.Second        // Window assumed for routes without an observed rate limit.
+	plannedCallLatency  = 150 * time.Millisecond // Time a call to Discord is assumed to take, besides waiting for its bucket.
+)
+
+// bucketState is the rate limit of a route of the Discord API, as last reported by Discord.
+type bucketState struct {
+	limit     int           // Requests per window
+	remaining int           // Requests left in the current window
+	resetAt   time.Time     // When the current window ends
+	window    time.Duration // Longest reset time seen, the length of a window
+}
+
+// The rate limits of the routes of the Discord API, keyed by bucketRoute, observed in the
+// responses of Discord.
+var rateLimits = struct {
+	sync.Mutex
+	buckets map[string]*bucketState
+}{buckets: make(map[string]*bucketState)}
+
+// majorParameters are the resources whose IDs are part of the rate limit of a route, as Discord
+// limits each guild, channel and webhook separately.
+var majorParameters = []string{"guilds", "channels", "webhooks"}
+
+// bucketRoute returns the route of a request to the Discord API that its rate limit applies to:
+// the path below the API version with the IDs other than those of the major parameters replaced,
+// e.g. PUT /guilds/1234/bans/:id.
+func bucketRoute(method, endpoint string) string {
+	if i := strings.Index(endpoint, "/api/v"); i >= 0 {
+		endpoint = endpoint[i+len("/api/v"):]
+		if j := strings.IndexByte(endpoint, '/'); j >= 0 {
+			endpoint = endpoint[j:]
+		}
+	}
+	endpoint, _, _ = strings.Cut(endpoint, "?")
+
+	segments := strings.Split(strings.TrimSuffix(endpoint, "/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if _, err := strconv.ParseUint(segments[i], 10, 64); err == nil && !slices.Contains(majorParameters, segments[i-1]) {
+			segments[i] = ":id"
+		}
+	}
+	return method + " " + strings.Join(segments, "/")
+}
+
+// observeRateLimit records the rate limit of a route from the headers of a response of Discord.
+func observeRateLimit(req *http.Request, resp *http.Response) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil || limit <= 0 {
+		return // Routes without a rate limit.
+	}
+	remaining, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	resetAfter, _ := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Reset-After"), 64)
+	reset := time.Duration(resetAfter * float64(time.Second))
+
+	route := bucketRoute(req.Method, req.URL.Path)
+	rateLimits.Lock()
+	defer rateLimits.Unlock()
+	b, ok := rateLimits.buckets[route]
+	if !ok {
+		b = &bucketState{}
+		rateLimits.buckets[route] = b
+	}
+	b.limit, b.remaining, b.resetAt = limit, remaining, time.Now().Add(reset)
+	if reset > b.window {
+		b.window = reset
+	}
+}
+
+// bucket returns the rate limit of a route, the default one if Discord has not reported it yet,
+// with the remaining requests refilled if its window has ended.
+func bucket(route string) (state bucketState, observed bool) {
+	rateLimits.Lock()
+	b, observed := rateLimits.buckets[route]
+	if observed {
+		state = *b
+	}
+	rateLimits.Unlock()
+
+	if state.limit <= 0 {
+		state.limit = defaultBucketLimit
+	}
+	if state.window <= 0 {
+		state.window = defaultBucketWindow
+	}
+	if now := time.Now(); !state.resetAt.After(now) {
+		state.remaining, state.resetAt = state.limit, now.Add(state.window)
+	}
+	return state, observed
+}
+
+// bucketWait returns the time until a route accepts requests again, zero if it has requests left.
+func bucketWait(route string) time.Duration {
+	if state, _ := bucket(route); state.remaining <= 0 {
+		return time.Until(state.resetAt)
+	}
+	return 0
+}
+
+// estimateCalls returns the time calls to a route take when paced by its rate limit: the calls
+// left in the current window go out at once, the rest one window after another.
+func estimateCalls(route string, calls int) time.Duration {
+	if calls <= 0 {
+		return 0
+	}
+	state, _ := bucket(route)
+	var wait time.Duration
+	if rest := calls - state.remaining; rest > 0 {
+		wait = time.Until(state.resetAt) + time.Duration((rest-1)/state.limit)*state.window
+	}
+	return wait + time.Duration(calls)*plannedCallLatency
+}
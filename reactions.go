@@ -1,6 +1,11 @@
 package disgm
 
 import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rif223/disgm/models"
@@ -8,25 +13,46 @@ import (
 
 type UserArray = []models.User
 
+// reactionPager pages through the users of a reaction by user ID. Discord only supports after.
+var reactionPager = pager{resource: LimitReactions, boundaries: true}
+
+// reactionTypes maps the reaction types of the type query parameter to Discord's.
+var reactionTypes = map[string]string{"normal": "0", "burst": "1"}
+
 // GetMessageReactions retrieves the users who reacted to a specific message with a given emoji.
 //
 // This function extracts the channel ID, message ID, and emoji ID from the Fiber context and request parameters.
-// It uses the DiscordGo session to retrieve the list of users who reacted with the specified emoji.
+// It uses the DiscordGo session to retrieve a page of the users who reacted with the specified
+// emoji, ordered by user ID. Discord only pages forward through reactions. Normal reactions and
+// super reactions (burst) are listed separately, normal ones by default.
 //
 // Parameters:
 //   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
 //   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
 //
+// Request Parameters:
+//   - limit: Optional number of users, 1 to 100, defaults to 100; both can be changed with the Limits option.
+//   - cursor: Optional cursor of the page, from the paging of a previous page.
+//   - after: Optional user ID; the page holds the users of higher user IDs.
+//   - type: Optional reaction type, normal (default) or burst for super reactions.
+//
 // Returns:
-//   - On success, it returns the list of users who reacted with the emoji as JSON with HTTP status 200.
-//   - On failure, it returns an HTTP status 500 and an error message if the reactions cannot be retrieved.
+//   - On success, it returns the page of users who reacted with the emoji as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the limit, cursor, after or type is invalid,
+//     or HTTP status 500 and an error message if the reactions cannot be retrieved.
 // @Summary		Get Message Reactions
 // @Description	Retrieve all reactions from a specific message in a channel.
 // @Tags			Reactions
 // @Param			channelid	path		string	true	"Channel ID"
 // @Param			messageid	path		string	true	"Message ID"
 // @Param			emojiid		path		string	true	"Emoji ID"
-// @Success		200			{array}		UserArray
+// @Param			limit		query		int		false	"Users per page"	minimum(1)	maximum(100)	default(100)
+// @Param			cursor		query		string	false	"Page cursor"
+// @Param			after		query		string	false	"Users of higher user IDs"
+// @Param			type		query		string	false	"Reaction type, normal by default"	Enums(normal, burst)
+// @Success		200			{object}	Page{data=UserArray}
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
 // @Failure		500			{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid} [get]
 func GetMessageReactions(c *fiber.Ctx, s *discordgo.Session) error {
@@ -34,12 +60,45 @@ func GetMessageReactions(c *fiber.Ctx, s *discordgo.Session) error {
 	messageID := c.Params("messageid")
 	emojiID := c.Params("emojiid")
 
-	users, err := s.MessageReactions(channelID, messageID, emojiID, 100, "", "")
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
+	req, err := reactionPager.request(c)
+	if err != nil {
+		return invalidPage(c, err)
+	}
+
+	reactionType, ok := reactionTypes[c.Query("type", "normal")]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid type: must be normal or burst")
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(req.limit))
+	query.Set("type", reactionType)
+	if req.after != "" {
+		query.Set("after", req.after)
+	}
+
+	// discordgo has no reaction type, so the request is sent as it would send it.
+	endpoint := discordgo.EndpointMessageReactions(channelID, messageID, strings.ReplaceAll(emojiID, "#", "%23"))
+	body, err := s.RequestWithBucketID("GET", endpoint+"?"+query.Encode(), nil, discordgo.EndpointMessageReaction(channelID, "", "", ""))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
 	}
+	var users []*discordgo.User
+	if err := json.Unmarshal(body, &users); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
+	}
+
+	cacheUsers(users...)
 
-	return c.JSON(users)
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return reactionPager.send(c, req, users, ids, nil)
 }
 
 // CreateMessageReaction adds a reaction to a specific message with a given emoji.
@@ -61,6 +120,7 @@ func GetMessageReactions(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			messageid	path	string	true	"Message ID"
 // @Param			emojiid		path	string	true	"Emoji ID"
 // @Success		201
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid} [put]
 func CreateMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
@@ -68,6 +128,10 @@ func CreateMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
 	messageID := c.Params("messageid")
 	emojiID := c.Params("emojiid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
 	err := s.MessageReactionAdd(channelID, messageID, emojiID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
@@ -96,6 +160,7 @@ func CreateMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			emojiid		path	string	true	"Emoji ID"
 // @Param			userid		path	string	true	"User ID"
 // @Success		204
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid}/{userid} [delete]
 func DeleteMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
@@ -104,6 +169,10 @@ func DeleteMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
 	emojiID := c.Params("emojiid")
 	userID := c.Params("userid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
 	err := s.MessageReactionRemove(channelID, messageID, emojiID, userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
@@ -130,12 +199,17 @@ func DeleteMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			channelid	path	string	true	"Channel ID"
 // @Param			messageid	path	string	true	"Message ID"
 // @Success		204
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid}/reactions [delete]
 func DeleteAllMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
 	channelID := c.Params("channelid")
 	messageID := c.Params("messageid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
 	err := s.MessageReactionsRemoveAll(channelID, messageID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
@@ -163,6 +237,7 @@ func DeleteAllMessageReaction(c *fiber.Ctx, s *discordgo.Session) error {
 // @Param			messageid	path	string	true	"Message ID"
 // @Param			emojiid		path	string	true	"Emoji ID"
 // @Success		204
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/channels/{channelid}/messages/{messageid}/reactions/{emojiid} [delete]
 func DeleteMessageReactionEmoji(c *fiber.Ctx, s *discordgo.Session) error {
@@ -170,6 +245,10 @@ func DeleteMessageReactionEmoji(c *fiber.Ctx, s *discordgo.Session) error {
 	messageID := c.Params("messageid")
 	emojiID := c.Params("emojiid")
 
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
 	err := s.MessageReactionsRemoveEmoji(channelID, messageID, emojiID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
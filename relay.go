@@ -0,0 +1,554 @@
+package disgm
+
+import (
+	"encoding/json"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxRelayedMessages is the number of relayed messages per relay tracked for edit and delete propagation.
+const maxRelayedMessages = 1000
+
+// relayWebhookName is the name of the webhooks created for relays, used to find orphaned ones.
+const relayWebhookName = "disgm relay"
+
+// Relay mirrors the messages of a source channel into a target channel through a webhook.
+//
+// Relays into another guild stay pending until that guild confirms them with its own token,
+// so no guild can post into the channels of another without its consent.
+type Relay struct {
+	ID              string   `json:"id"`                   // Unique ID of the relay
+	GuildID         string   `json:"guild_id"`             // ID of the guild that created the relay
+	SourceChannelID string   `json:"source_channel_id"`    // ID of the channel messages are read from
+	TargetChannelID string   `json:"target_channel_id"`    // ID of the channel messages are mirrored to
+	TargetGuildID   string   `json:"target_guild_id"`      // ID of the guild of the target channel
+	Pending         bool     `json:"pending"`              // Whether the relay waits for the target guild to confirm it
+	IncludeBots     bool     `json:"include_bots"`         // Whether messages of bots are relayed
+	AuthorIDs       []string `json:"author_ids,omitempty"` // Only relays messages of these users, if set
+	Contains        []string `json:"contains,omitempty"`   // Only relays messages containing one of these terms, if set
+
+	webhook   *discordgo.Webhook // Webhook in the target channel, unset while pending
+	mirrored  map[string]string  // Source message IDs mapped to relayed message IDs
+	mirrorLog []string           // Source message IDs in relay order, used to bound mirrored
+}
+
+// storedRelay is a relay as persisted in the key-value store, with the webhook it posts through.
+type storedRelay struct {
+	Relay
+	WebhookID    string `json:"webhook_id,omitempty"`
+	WebhookToken string `json:"webhook_token,omitempty"`
+}
+
+// A registry of message relays, with the option that allows relays across guilds. Relays are
+// persisted keyed by ID, the relayed message IDs keyed by "<relay>/<source message>".
+var relays = struct {
+	sync.Mutex
+	list       []*Relay
+	crossGuild bool
+	storage    *Storage
+	messages   *Storage
+	loaded     time.Time // When the relays were restored; older relay webhooks of no relay are removed
+	cleaned    bool      // Whether the orphaned relay webhooks were removed
+}{}
+
+// persistRelay writes a relay to the storage. The caller must hold the relays lock.
+func persistRelay(r *Relay) error {
+	stored := storedRelay{Relay: *r}
+	if r.webhook != nil {
+		stored.WebhookID, stored.WebhookToken = r.webhook.ID, r.webhook.Token
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return relays.storage.Set(r.ID, data)
+}
+
+// loadRelays restores the relays and the IDs of their relayed messages from the storage.
+func loadRelays(storage, messages *Storage) {
+	relays.Lock()
+	defer relays.Unlock()
+
+	relays.storage, relays.messages = storage, messages
+	relays.loaded = time.Now()
+	relays.list = nil
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load relays: %v", err)
+		return
+	}
+	for _, key := range keys {
+		data, ok, err := storage.Get(key)
+		if err != nil || !ok {
+			continue
+		}
+
+		var stored storedRelay
+		if err := json.Unmarshal(data, &stored); err != nil {
+			log.Printf("Failed to load relay %s: %v", key, err)
+			continue
+		}
+		r := stored.Relay
+		if stored.WebhookID != "" {
+			r.webhook = &discordgo.Webhook{ID: stored.WebhookID, Token: stored.WebhookToken, ChannelID: r.TargetChannelID}
+		}
+		r.mirrored = make(map[string]string)
+
+		err = messages.View(func(tx *StorageTx) error {
+			ids, err := tx.List(r.ID + "/")
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				value, ok, err := tx.Get(id)
+				if err != nil {
+					return err
+				}
+				if ok {
+					sourceID := strings.TrimPrefix(id, r.ID+"/")
+					r.mirrored[sourceID] = string(value)
+					r.mirrorLog = append(r.mirrorLog, sourceID)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Failed to load relayed messages of relay %s: %v", key, err)
+		}
+		sort.Slice(r.mirrorLog, func(i, j int) bool { return snowflakeLess(r.mirrorLog[i], r.mirrorLog[j]) })
+
+		relays.list = append(relays.list, &r)
+	}
+}
+
+// removeRelayWebhooks deletes the relay webhooks of the bot that belong to no relay, e.g. of relays
+// lost before they were persisted. Only webhooks created before the relays were restored are
+// removed, so relays being created meanwhile keep theirs.
+func removeRelayWebhooks(s *discordgo.Session, botID string, guilds []*discordgo.Guild) {
+	relays.Lock()
+	loaded := relays.loaded
+	known := make(map[string]bool, len(relays.list))
+	for _, r := range relays.list {
+		if r.webhook != nil {
+			known[r.webhook.ID] = true
+		}
+	}
+	relays.Unlock()
+
+	for _, g := range guilds {
+		webhooks, err := s.GuildWebhooks(g.ID)
+		if err != nil {
+			continue // The bot may lack the permission to manage webhooks.
+		}
+		for _, wh := range webhooks {
+			if wh.Name != relayWebhookName || wh.User == nil || wh.User.ID != botID || known[wh.ID] {
+				continue
+			}
+			if created, err := discordgo.SnowflakeTimestamp(wh.ID); err != nil || !created.Before(loaded) {
+				continue
+			}
+			if err := s.WebhookDelete(wh.ID); err != nil {
+				log.Printf("Failed to remove orphaned relay webhook %s: %v", wh.ID, err)
+			}
+		}
+	}
+}
+
+// matches reports whether a message passes the filters of the relay.
+func (r *Relay) matches(m *discordgo.Message) bool {
+	if m.Author == nil || (m.Author.Bot && !r.IncludeBots) {
+		return false
+	}
+	if len(r.AuthorIDs) > 0 && !slices.Contains(r.AuthorIDs, m.Author.ID) {
+		return false
+	}
+	if len(r.Contains) > 0 {
+		content := strings.ToLower(m.Content)
+		return slices.ContainsFunc(r.Contains, func(term string) bool {
+			return strings.Contains(content, strings.ToLower(term))
+		})
+	}
+	return true
+}
+
+// remember maps a source message to its relayed copy, forgetting the oldest entries.
+// The caller must hold the relays lock.
+func (r *Relay) remember(sourceID, relayedID string) {
+	r.mirrored[sourceID] = relayedID
+	r.mirrorLog = append(r.mirrorLog, sourceID)
+	if err := relays.messages.Set(r.ID+"/"+sourceID, []byte(relayedID)); err != nil {
+		log.Printf("Failed to store relayed message of relay %s: %v", r.ID, err)
+	}
+	if len(r.mirrorLog) > maxRelayedMessages {
+		forget(r, r.mirrorLog[0])
+		r.mirrorLog = r.mirrorLog[1:]
+	}
+}
+
+// forget drops a relayed message from the relay. The caller must hold the relays lock.
+func forget(r *Relay, sourceID string) {
+	delete(r.mirrored, sourceID)
+	if err := relays.messages.Delete(r.ID + "/" + sourceID); err != nil {
+		log.Printf("Failed to remove relayed message of relay %s: %v", r.ID, err)
+	}
+}
+
+// relayContent returns the content of a message with its attachment URLs appended.
+func relayContent(m *discordgo.Message) string {
+	content := m.Content
+	for _, a := range m.Attachments {
+		content += "\n" + a.URL
+	}
+	return content
+}
+
+// relaysFor returns the confirmed relays reading from a channel, skipping messages sent by relay webhooks.
+func relaysFor(channelID, webhookID string) []*Relay {
+	relays.Lock()
+	defer relays.Unlock()
+
+	var list []*Relay
+	for _, r := range relays.list {
+		if r.webhook == nil {
+			continue // Pending relays have no webhook yet.
+		}
+		if webhookID != "" && r.webhook.ID == webhookID {
+			return nil // Never relays a relayed message, avoiding loops.
+		}
+		if r.SourceChannelID == channelID {
+			list = append(list, r)
+		}
+	}
+	return list
+}
+
+// registerRelayHandlers registers the gateway handlers that mirror, edit and delete relayed messages.
+func registerRelayHandlers(s *discordgo.Session) {
+	// Removes the orphaned relay webhooks once, when the guilds of the bot are known.
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+		relays.Lock()
+		done := relays.cleaned || relays.storage == nil
+		relays.cleaned = true
+		relays.Unlock()
+
+		if !done {
+			go removeRelayWebhooks(s, r.User.ID, r.Guilds)
+		}
+	})
+
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		for _, r := range relaysFor(m.ChannelID, m.WebhookID) {
+			if !r.matches(m.Message) {
+				continue
+			}
+
+			msg, err := s.WebhookExecute(r.webhook.ID, r.webhook.Token, true, &discordgo.WebhookParams{
+				Content:         relayContent(m.Message),
+				Username:        m.Author.Username,
+				AvatarURL:       m.Author.AvatarURL(""),
+				Embeds:          m.Embeds,
+				AllowedMentions: &discordgo.MessageAllowedMentions{}, // Relayed messages never ping.
+			})
+			if err != nil {
+				continue
+			}
+
+			relays.Lock()
+			r.remember(m.ID, msg.ID)
+			relays.Unlock()
+		}
+	})
+
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageUpdate) {
+		for _, r := range relaysFor(m.ChannelID, m.WebhookID) {
+			relays.Lock()
+			relayedID, ok := r.mirrored[m.ID]
+			relays.Unlock()
+
+			if ok && m.Author != nil {
+				content := relayContent(m.Message)
+				s.WebhookMessageEdit(r.webhook.ID, r.webhook.Token, relayedID, &discordgo.WebhookEdit{
+					Content: &content,
+					Embeds:  &m.Embeds,
+				})
+			}
+		}
+	})
+
+	s.AddHandler(func(s *discordgo.Session, m *discordgo.MessageDelete) {
+		for _, r := range relaysFor(m.ChannelID, "") {
+			relays.Lock()
+			relayedID, ok := r.mirrored[m.ID]
+			if ok {
+				forget(r, m.ID)
+			}
+			relays.Unlock()
+
+			if ok {
+				s.WebhookMessageDelete(r.webhook.ID, r.webhook.Token, relayedID)
+			}
+		}
+	})
+}
+
+// GetGuildRelays retrieves the message relays created by the guild or relaying into it.
+//
+// Relays of other guilds into the guild are listed as well, so the guild can confirm or remove them.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Context:
+//   - ID: The guild ID is stored in the Fiber context under the key "ID".
+//
+// Returns:
+//   - It returns the relays as a JSON array.
+// @Summary		Get Guild Relays
+// @Description	Retrieve the message relays of the guild and the relays of other guilds into it.
+// @Tags			Relays
+// @Success		200	{array}	Relay
+// @Router			/api/guild/relays [get]
+func GetGuildRelays(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	relays.Lock()
+	list := []*Relay{}
+	for _, r := range relays.list {
+		if r.GuildID == guildID || r.TargetGuildID == guildID {
+			list = append(list, r)
+		}
+	}
+	relays.Unlock()
+
+	return c.JSON(list)
+}
+
+// CreateGuildRelay creates a relay that mirrors messages from one channel to another.
+//
+// This function creates a webhook in the target channel, so relayed messages keep the name and
+// avatar of their author. Edits and deletions of relayed messages are propagated as well.
+// The target channel must belong to the same guild unless cross-guild relays are enabled; relays
+// into another guild are created pending and only relay once that guild confirms them.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A JSON object with the fields "source_channel_id", "target_channel_id", "include_bots",
+//     "author_ids" and "contains".
+//
+// Returns:
+//   - On success, it returns the created relay as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body or channels are invalid,
+//     HTTP status 403 (Forbidden) if a channel is outside the token scope, or HTTP status 500
+//     (Internal Server Error) if the webhook cannot be created or the relay cannot be stored.
+// @Summary		Create Guild Relay
+// @Description	Mirror messages from a source channel into a target channel.
+// @Tags			Relays
+// @Param			body	body		Relay	true	"Relay settings"
+// @Success		201		{object}	Relay
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/relays [post]
+func CreateGuildRelay(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	var relay Relay
+	if err := c.BodyParser(&relay); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	source, err := cachedChannel(s, relay.SourceChannelID)
+	if err != nil || source.GuildID != guildID {
+		return c.Status(fiber.StatusBadRequest).SendString("Source channel not found in guild")
+	}
+	target, err := cachedChannel(s, relay.TargetChannelID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Target channel not found")
+	}
+
+	relays.Lock()
+	crossGuild := relays.crossGuild
+	relays.Unlock()
+
+	if target.GuildID != guildID && !crossGuild {
+		return c.Status(fiber.StatusBadRequest).SendString("Target channel must belong to the guild")
+	}
+	if !channelAllowed(c, relay.SourceChannelID, false) || !channelAllowed(c, relay.TargetChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	if relay.ID, err = randomToken(8); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create relay: " + err.Error())
+	}
+	relay.GuildID = guildID
+	relay.TargetGuildID = target.GuildID
+	relay.Pending = target.GuildID != guildID // Waits for the consent of the target guild.
+	relay.mirrored = make(map[string]string)
+
+	if !relay.Pending {
+		if relay.webhook, err = s.WebhookCreate(relay.TargetChannelID, relayWebhookName, "", auditOptions(c)...); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to create relay webhook: " + err.Error())
+		}
+	}
+
+	relays.Lock()
+	err = persistRelay(&relay)
+	if err == nil {
+		relays.list = append(relays.list, &relay)
+	}
+	relays.Unlock()
+
+	if err != nil {
+		if relay.webhook != nil {
+			s.WebhookDelete(relay.webhook.ID)
+		}
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create relay: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(&relay)
+}
+
+// ConfirmGuildRelay confirms a pending relay of another guild into a channel of the guild.
+//
+// This function must be called with the token of the target guild. It creates the webhook in
+// the target channel, after which messages are relayed.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - relayid: The ID of the pending relay.
+//
+// Returns:
+//   - On success, it returns the confirmed relay as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if cross-guild relays are disabled,
+//     HTTP status 403 (Forbidden) if the target channel is outside the token scope, HTTP status 404
+//     (Not Found) if no relay into the guild waits for confirmation, or HTTP status 500
+//     (Internal Server Error) if the webhook cannot be created or the relay cannot be stored.
+// @Summary		Confirm Guild Relay
+// @Description	Confirm a pending relay of another guild into a channel of the guild.
+// @Tags			Relays
+// @Param			relayid	path		string	true	"Relay ID"
+// @Success		200		{object}	Relay
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/relays/{relayid}/confirm [post]
+func ConfirmGuildRelay(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	relayID := c.Params("relayid")
+
+	pending := func(r *Relay) bool { return r.ID == relayID && r.TargetGuildID == guildID && r.Pending }
+
+	relays.Lock()
+	i := slices.IndexFunc(relays.list, pending)
+	var relay *Relay
+	if i >= 0 {
+		relay = relays.list[i]
+	}
+	crossGuild := relays.crossGuild
+	relays.Unlock()
+
+	if relay == nil {
+		return c.Status(fiber.StatusNotFound).SendString("Pending relay not found")
+	}
+	if !crossGuild {
+		return c.Status(fiber.StatusBadRequest).SendString("Cross-guild relays are disabled")
+	}
+	if !channelAllowed(c, relay.TargetChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	webhook, err := s.WebhookCreate(relay.TargetChannelID, relayWebhookName, "", auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create relay webhook: " + err.Error())
+	}
+
+	relays.Lock()
+	if !slices.ContainsFunc(relays.list, pending) {
+		relays.Unlock()
+		s.WebhookDelete(webhook.ID)
+		return c.Status(fiber.StatusNotFound).SendString("Pending relay not found")
+	}
+	relay.webhook, relay.Pending = webhook, false
+	if err = persistRelay(relay); err != nil {
+		relay.webhook, relay.Pending = nil, true
+	}
+	confirmed := *relay
+	relays.Unlock()
+
+	if err != nil {
+		s.WebhookDelete(webhook.ID)
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to confirm relay: " + err.Error())
+	}
+
+	return c.JSON(&confirmed)
+}
+
+// DeleteGuildRelay removes a message relay and its webhook.
+//
+// Both the guild that created the relay and the guild it relays into can remove it.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - relayid: The ID of the relay to remove.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such relay,
+//     or HTTP status 500 (Internal Server Error) if it cannot be removed from the storage.
+// @Summary		Delete Guild Relay
+// @Description	Remove a message relay.
+// @Tags			Relays
+// @Param			relayid	path	string	true	"Relay ID"
+// @Success		204
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/relays/{relayid} [delete]
+func DeleteGuildRelay(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	relayID := c.Params("relayid")
+
+	relays.Lock()
+	i := slices.IndexFunc(relays.list, func(r *Relay) bool {
+		return r.ID == relayID && (r.GuildID == guildID || r.TargetGuildID == guildID)
+	})
+	if i < 0 {
+		relays.Unlock()
+		return c.Status(fiber.StatusNotFound).SendString("Relay not found")
+	}
+	relay := relays.list[i]
+	if err := relays.storage.Delete(relay.ID); err != nil {
+		relays.Unlock()
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete relay: " + err.Error())
+	}
+	for sourceID := range relay.mirrored {
+		forget(relay, sourceID)
+	}
+	relays.list = slices.Delete(relays.list, i, i+1)
+	relays.Unlock()
+
+	if relay.webhook != nil {
+		s.WebhookDelete(relay.webhook.ID, auditOptions(c)...) // The relay is gone even if the webhook was removed manually.
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,288 @@
+package disgm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/store"
+)
+
+// ReplayHeader marks the requests of event replays sent to a webhook sink.
+const ReplayHeader = "X-Disgm-Replay"
+
+// replayClient posts replayed events to webhook sinks. Only publicly routable addresses are
+// reached, see publicDialControl.
+var replayClient = newPublicClient(10 * time.Second)
+
+// ReplayTarget is the sink a replay is sent to: a connected WebSocket client or a webhook URL.
+type ReplayTarget struct {
+	ClientID   string `json:"client_id,omitempty"`   // Connection ID from the HELLO event of a WebSocket client
+	WebhookURL string `json:"webhook_url,omitempty"` // URL each event is posted to as JSON
+	PublicKey  string `json:"public_key,omitempty"`  // Base64 X25519 public key the webhook deliveries are sealed for
+}
+
+// ReplayResult reports how many recorded events were replayed.
+type ReplayResult struct {
+	Replayed int    `json:"replayed"`
+	Error    string `json:"error,omitempty"` // Error that stopped the replay early, if any
+}
+
+// replayToClient sends a recorded event to a single WebSocket client, marked as a replay.
+func replayToClient(guildID, clientID string, event Event) error {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	clientsMu.Lock()
+	conn, ok := guildClient(guildID, clientID)
+	info := clientInfo[conn]
+	clientsMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("client %s disconnected", clientID)
+	}
+	return writeEncoded(conn, info, event.Name, dataBytes, event.Replay)
+}
+
+// replayToWebhook posts a recorded event to a webhook sink, marked as a replay. With a public key,
+// the JSON body is sealed for it and posted as binary data.
+func replayToWebhook(url string, publicKey *[32]byte, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	contentType := "application/json"
+	if publicKey != nil {
+		if body, err = seal(publicKey, body); err != nil {
+			return err
+		}
+		contentType = "application/octet-stream"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(ReplayHeader, "true")
+	if publicKey != nil {
+		req.Header.Set(EncryptionHeader, SealedBoxScheme)
+	}
+
+	resp, err := replayClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// GetWebSocketClients retrieves the WebSocket clients connected for the guild.
+//
+// Each client is listed with the name, version and purpose it identified itself with, its
+// message counters and its write latency, so noisy or slow consumers can be attributed to a service.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the clients as a JSON array, ordered by connection time.
+// @Summary		Get WebSocket Clients
+// @Description	Retrieve the WebSocket clients connected for the guild with their labels and message counters.
+// @Tags			Events
+// @Success		200	{array}	WSClient
+// @Router			/api/guild/ws/clients [get]
+func GetWebSocketClients(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	clientsMu.Lock()
+	list := []WSClient{}
+	for conn, info := range clientInfo {
+		if clients[conn] == guildID {
+			client := *info
+			if info.batch != nil {
+				client.QueueDepth = len(info.batch.events)
+			}
+			list = append(list, client)
+		}
+	}
+	clientsMu.Unlock()
+
+	slices.SortFunc(list, func(a, b WSClient) int { return a.ConnectedAt.Compare(b.ConnectedAt) })
+
+	return c.JSON(list)
+}
+
+// KickWebSocketClient disconnects a WebSocket client of the guild.
+//
+// This function is meant for clients that fall behind, which delay the events of the other
+// clients of their guild; see the slow and write_latency_ms fields of the clients endpoint. The
+// client is sent a close frame with code 4000 and the reason "kicked", if it still reads, and
+// disconnected.
+// Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns an HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 404 (Not Found) if the client is not connected.
+// @Summary		Kick WebSocket Client
+// @Description	Disconnect a WebSocket client of the guild, e.g. one that falls behind.
+// @Tags			Events
+// @Param			clientid	path	string	true	"Client ID"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		404	{object}	error
+// @Router			/api/guild/ws/clients/{clientid} [delete]
+func KickWebSocketClient(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	clientsMu.Lock()
+	conn, ok := guildClient(guildID, c.Params("clientid"))
+	var label string
+	if ok {
+		label = clientInfo[conn].label()
+	}
+	clientsMu.Unlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Client not found")
+	}
+	log.Printf("Kicking client %s [%s]", guildID, label)
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeKicked, "kicked"), time.Now().Add(time.Second))
+	conn.Close()
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ReplayGuildEvents replays a range of recorded events to a single sink.
+//
+// This function sends the recorded events of the guild, oldest first, in the same format as live
+// events but with "replay" set, to one WebSocket client or webhook URL. It is meant to debug consumer
+// logic against real historical traffic; the replayed events are not recorded again and no other
+// client receives them. Clients whose token is restricted to channels only receive the events of
+// those channels, as with live events. The replay stops at the first event the sink fails to receive.
+//
+// Webhook deliveries are sealed for public_key if set, which the RequireEncryption option makes
+// mandatory; they are then posted as application/octet-stream with the X-Disgm-Encryption header
+// set to sealed-box. Clients that registered a public key receive sealed frames as usual.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - from: Only events at or after this RFC 3339 time.
+//   - to: Only events at or before this RFC 3339 time.
+//   - types: Comma-separated event names, e.g. MESSAGE_CREATE,MESSAGE_DELETE.
+//
+// Request Body:
+//   - A JSON object with either "client_id" or "webhook_url", and optionally "public_key" for webhooks.
+//
+// Returns:
+//   - On success, it returns the number of replayed events as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the range or target is invalid,
+//     including an invalid or, while encryption is required, missing public key,
+//     HTTP status 403 (Forbidden) for tokens restricted to channels,
+//     or HTTP status 404 (Not Found) if the client is not connected.
+// @Summary		Replay Guild Events
+// @Description	Replay recorded events to a single WebSocket client or webhook.
+// @Tags			Events
+// @Param			from	query		string			false	"Start of the range (RFC 3339)"
+// @Param			to		query		string			false	"End of the range (RFC 3339)"
+// @Param			types	query		string			false	"Comma-separated event names"
+// @Param			body	body		ReplayTarget	true	"Replay target"
+// @Success		200		{object}	ReplayResult
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Router			/api/guild/events/replay [post]
+func ReplayGuildEvents(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	from, to, types, err := parseEventRange(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid range: " + err.Error())
+	}
+
+	var target ReplayTarget
+	if err := c.BodyParser(&target); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	var send func(Event) error
+	var scope *store.Scope // Scope of the token of the target client, whose events are left out
+	switch {
+	case target.ClientID != "" && target.WebhookURL == "":
+		clientsMu.Lock()
+		conn, ok := guildClient(guildID, target.ClientID)
+		if ok {
+			scope = clientInfo[conn].scope
+		}
+		clientsMu.Unlock()
+		if !ok {
+			return c.Status(fiber.StatusNotFound).SendString("Client not found")
+		}
+		send = func(e Event) error { return replayToClient(guildID, target.ClientID, e) }
+	case target.WebhookURL != "" && target.ClientID == "":
+		if !strings.HasPrefix(target.WebhookURL, "http://") && !strings.HasPrefix(target.WebhookURL, "https://") {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid target: webhook_url must be an http or https URL")
+		}
+		var publicKey *[32]byte
+		if target.PublicKey != "" || encryptionRequired() {
+			if publicKey, err = parsePublicKey(target.PublicKey); err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString("Invalid target: " + err.Error())
+			}
+		}
+		send = func(e Event) error {
+			err := replayToWebhook(target.WebhookURL, publicKey, e)
+			recordDelivery(guildID, SinkWebhook, webhookSinkName(target.WebhookURL), err)
+			return err
+		}
+	default:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid target: set either client_id or webhook_url")
+	}
+
+	var result ReplayResult
+	err = bufferedEvents(guildID, from, to, types, func(recorded RecordedEvent) error {
+		if scope != nil && !scopeAllowsEvent(scope, eventScopeChannelIDs(recorded.Name, recorded.Data)) {
+			return nil
+		}
+		if err := send(Event{Name: recorded.Name, Data: recorded.Data, Replay: true}); err != nil {
+			return err
+		}
+		result.Replayed++
+		return nil
+	})
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,27 @@
+package disgm
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestReplayToWebhookInternalAddress checks that replays are not posted to the host or its network.
+func TestReplayToWebhookInternalAddress(t *testing.T) {
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer local.Close()
+
+	event := Event{Name: "MESSAGE_CREATE", Data: map[string]string{"content": "Hello!"}}
+	for _, url := range []string{
+		local.URL + "/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://192.168.0.1/hook",
+	} {
+		if err := replayToWebhook(url, nil, event); !errors.Is(err, errInternalAddress) {
+			t.Errorf("replayToWebhook(%q): error %v, want %v", url, err, errInternalAddress)
+		}
+	}
+}
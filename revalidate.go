@@ -0,0 +1,71 @@
+package disgm
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+)
+
+// closeTokenRevoked is the close code of clients whose token was revoked or expired.
+const closeTokenRevoked = 4002
+
+// RevalidateTokens closes the WebSocket connections whose tokens are no longer valid.
+//
+// Tokens are checked when a connection is opened, so a token removed from the token store keeps
+// streaming events until its clients disconnect. This method is called periodically by
+// RegisterWebSocket, and should be called by applications right after they revoke a token so
+// its connections are closed at once. Connections whose token now belongs to another guild are
+// closed as well, and so are those of expired impersonation tokens and revoked issued tokens.
+//
+// Returns:
+//   - int: The number of connections closed.
+//   - error: An error if the token store cannot be loaded, in which case no connection is closed.
+func (d *Disgm) RevalidateTokens() (int, error) {
+	// Without a token store, clients can only connect with impersonation and issued tokens, which
+	// are still checked.
+	var tokens map[string]string
+	if d.opt.TokenStore != nil {
+		var err error
+		if tokens, err = d.opt.TokenStore.Load(); err != nil {
+			return 0, err
+		}
+	}
+
+	// The connections are closed after the lock is released, as the close frames may have to wait
+	// for a write in progress
+	var revoked []*websocket.Conn
+	clientsMu.Lock()
+	for conn, guildID := range clients {
+		info := clientInfo[conn]
+		if info.token == "" || tokens[guildID] == info.token {
+			continue
+		}
+		if imp, ok := impersonation(info.token); ok && imp.GuildID == guildID {
+			continue
+		}
+		if issued, ok := issuedToken(info.token); ok && issued.guildID == guildID {
+			continue
+		}
+		log.Printf("Closing client %s [%s]: token revoked", guildID, info.label())
+		revoked = append(revoked, conn)
+	}
+	clientsMu.Unlock()
+
+	for _, conn := range revoked {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeTokenRevoked, "token revoked"), time.Now().Add(time.Second))
+		conn.Close()
+	}
+	return len(revoked), nil
+}
+
+// revalidateTokens calls RevalidateTokens in the configured interval.
+func (d *Disgm) revalidateTokens() {
+	ticker := time.NewTicker(d.opt.TokenRevalidateInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := d.RevalidateTokens(); err != nil {
+			log.Printf("Failed to revalidate WebSocket tokens: %v", err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package disgm_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/rif223/disgm"
+	"github.com/rif223/disgm/disgmtest"
+)
+
+// dial connects a WebSocket client with the token and waits for its HELLO event.
+func (ts *testServer) dial(t *testing.T, token string) *websocket.Conn {
+	t.Helper()
+
+	header := http.Header{"Authorization": {"Bearer " + token}}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws?v=2", header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	var event disgm.Event
+	if err := conn.ReadJSON(&event); err != nil || event.Name != "HELLO" {
+		t.Fatalf("reading HELLO: event %q, error %v", event.Name, err)
+	}
+	return conn
+}
+
+// TestRevalidateTokensWithoutTokenStore checks that the connections of revoked issued tokens are
+// closed when disgm has no token store.
+func TestRevalidateTokensWithoutTokenStore(t *testing.T) {
+	ts := startServer(t, func(b *disgmtest.Backend, guild *discordgo.Guild, opt *disgm.Options) {
+		opt.TokenStore = nil
+		opt.AdminTokens = []string{testAdminToken}
+	})
+
+	status, body := ts.do(t, http.MethodPost, "/api/admin/guilds/"+ts.Guild.ID+"/impersonate", testAdminToken, `{"acting_user":"support"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("impersonating the guild: status %d: %s", status, body)
+	}
+	var imp disgm.Impersonation
+	if err := json.Unmarshal([]byte(body), &imp); err != nil {
+		t.Fatal(err)
+	}
+
+	status, body = ts.do(t, http.MethodPost, "/api/guild/tokens", imp.Token, `{"name":"dashboard"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("issuing token: status %d: %s", status, body)
+	}
+	var issued disgm.GuildToken
+	if err := json.Unmarshal([]byte(body), &issued); err != nil {
+		t.Fatal(err)
+	}
+
+	revoked := ts.dial(t, issued.Token)
+	ts.dial(t, imp.Token)
+
+	if status, body := ts.do(t, http.MethodDelete, "/api/guild/tokens/"+issued.ID, imp.Token, ""); status != http.StatusNoContent {
+		t.Fatalf("revoking token: status %d: %s", status, body)
+	}
+
+	closed, err := ts.Disgm.RevalidateTokens()
+	if err != nil || closed != 1 {
+		t.Fatalf("RevalidateTokens() = %d, %v, want 1 connection closed", closed, err)
+	}
+	for {
+		if _, _, err = revoked.ReadMessage(); err != nil {
+			break
+		}
+	}
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != 4002 {
+		t.Errorf("connection of the revoked token: error %v, want close code 4002", err)
+	}
+}
@@ -1,6 +1,11 @@
 package disgm
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rif223/disgm/models"
@@ -47,7 +52,8 @@ func GetGuildRoles(c *fiber.Ctx, s *discordgo.Session) error {
 //   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
 //
 // Returns:
-//   - On success, it returns the role object as JSON with HTTP status 200.
+//   - On success, it returns the role object as JSON with HTTP status 200 and an ETag header for
+//     If-Match on updates.
 //   - On failure, it returns an HTTP status 500 and an error message if the role cannot be retrieved.
 // @Summary		Get a specific role in a guild
 // @Description	Retrieve a specific role from a guild by its role ID.
@@ -65,6 +71,7 @@ func GetGuildRole(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve role: " + err.Error())
 	}
 
+	c.Set(fiber.HeaderETag, roleVersion(role))
 	return c.JSON(role)
 }
 
@@ -95,7 +102,7 @@ func CreateGuildRole(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
 
-	role, err := s.GuildRoleCreate(guildID, &roleData)
+	role, err := s.GuildRoleCreate(guildID, &roleData, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create role: " + err.Error())
 	}
@@ -119,7 +126,9 @@ func CreateGuildRole(c *fiber.Ctx, s *discordgo.Session) error {
 // @Description	Reorder the roles in a guild based on the provided positions.
 // @Tags			Roles
 // @Param			body	body		[]models.Role	true	"New role positions"
+// @Param			override	query		bool	false	"Allow changing a protected resource"
 // @Success		200		{array}		models.Role
+// @Failure		403		{object}	error
 // @Failure		500		{object}	error
 // @Router			/api/guild/roles [patch]
 func UpdateGuildRolePositions(c *fiber.Ctx, s *discordgo.Session) error {
@@ -130,11 +139,35 @@ func UpdateGuildRolePositions(c *fiber.Ctx, s *discordgo.Session) error {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
 
-	roles, err := s.GuildRoleReorder(guildID, positions)
+	for _, role := range positions {
+		if roleProtected(c, s, guildID, role.ID) {
+			return protectedForbidden(c)
+		}
+	}
+
+	var before []*discordgo.Role
+	for _, role := range positions {
+		if r, err := cachedRole(s, guildID, role.ID); err == nil {
+			before = append(before, &discordgo.Role{ID: r.ID, Position: r.Position})
+		}
+	}
+
+	roles, err := s.GuildRoleReorder(guildID, positions, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update role positions: " + err.Error())
 	}
 
+	if len(before) == len(positions) {
+		roleIDs := make([]string, len(before))
+		for i, role := range before {
+			roleIDs[i] = role.ID
+		}
+		recordChange(c, ChangeRolePositions, guildID, func(options ...discordgo.RequestOption) error {
+			_, err := s.GuildRoleReorder(guildID, before, options...)
+			return err
+		}, roleIDs...)
+	}
+
 	return c.JSON(roles)
 }
 
@@ -147,31 +180,75 @@ func UpdateGuildRolePositions(c *fiber.Ctx, s *discordgo.Session) error {
 //   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
 //   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
 //
+// Request Headers:
+//   - If-Match: Optional ETag of the role as read by the client. The update is refused if the role
+//     was changed since, so concurrent edits do not overwrite each other.
+//
 // Returns:
-//   - On success, it returns the updated role as JSON with HTTP status 200.
-//   - On failure, it returns an HTTP status 500 and an error message if the role cannot be updated.
+//   - On success, it returns the updated role as JSON with HTTP status 200 and its new ETag.
+//   - On failure, it returns an HTTP status 412 (Precondition Failed) if the role does not match If-Match,
+//     or HTTP status 500 and an error message if the role cannot be updated.
 // @Summary		Update a specific role in a guild
 // @Description	Update a specific role in a guild using the provided role data.
 // @Tags			Roles
+// @Accept			json,application/merge-patch+json
 // @Param			roleid	path		string				true	"ID of the role to update"
 // @Param			body	body		models.RoleParams	true	"Updated role parameters"
+// @Param			override	query		bool	false	"Allow changing a protected resource"
+// @Param			X-Update-Mask	header		string	false	"Fields to update, comma-separated; missing ones are cleared"
+// @Param			If-Match	header		string	false	"ETag of the role as read"
 // @Success		200		{object}	models.Role
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		412		{object}	error
 // @Failure		500		{object}	error
 // @Router			/api/guild/roles/{roleid} [patch]
 func UpdateGuildRole(c *fiber.Ctx, s *discordgo.Session) error {
 	guildID := c.Locals("ID").(string)
 	roleID := c.Params("roleid")
 
+	if roleProtected(c, s, guildID, roleID) {
+		return protectedForbidden(c)
+	}
+
 	var roleData *discordgo.RoleParams
 	if err := c.BodyParser(&roleData); err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
 	}
+	fields, err := patchFields(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
 
-	role, err := s.GuildRoleEdit(guildID, roleID, roleData)
+	before, beforeErr := cachedRole(s, guildID, roleID)
+	if c.Get(fiber.HeaderIfMatch) != "" {
+		if beforeErr != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve role: " + beforeErr.Error())
+		}
+		if current := roleVersion(before); !ifMatch(c, current) {
+			return preconditionFailed(c, current)
+		}
+	}
+
+	var role *discordgo.Role
+	if fields != nil {
+		role = new(discordgo.Role)
+		endpoint := discordgo.EndpointGuildRole(guildID, roleID)
+		err = patchResource(s, endpoint, discordgo.EndpointGuildRole(guildID, ""), fields, role, auditOptions(c)...)
+	} else {
+		role, err = s.GuildRoleEdit(guildID, roleID, roleData, auditOptions(c)...)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update role: " + err.Error())
 	}
 
+	if beforeErr == nil {
+		recordChange(c, ChangeRoleEdit, roleID, func(options ...discordgo.RequestOption) error {
+			return restoreRole(s, guildID, before, options...)
+		})
+	}
+
+	c.Set(fiber.HeaderETag, roleVersion(role))
 	return c.JSON(role)
 }
 
@@ -191,17 +268,129 @@ func UpdateGuildRole(c *fiber.Ctx, s *discordgo.Session) error {
 // @Description	Delete a specific role from a guild using its role ID.
 // @Tags			Roles
 // @Param			roleid	path	string	true	"ID of the role to delete"
+// @Param			override	query		bool	false	"Allow changing a protected resource"
 // @Success		204
+// @Success		202	{object}	Approval
+// @Failure		403	{object}	error
 // @Failure		500	{object}	error
 // @Router			/api/guild/roles/{roleid} [delete]
 func DeleteGuildRole(c *fiber.Ctx, s *discordgo.Session) error {
 	guildID := c.Locals("ID").(string)
 	roleID := c.Params("roleid")
 
-	err := s.GuildRoleDelete(guildID, roleID)
+	if roleProtected(c, s, guildID, roleID) {
+		return protectedForbidden(c)
+	}
+
+	if approvals.required(ActionRoleDelete) {
+		roleID := strings.Clone(roleID) // Params point into the request buffer, which is reused.
+		return requestApproval(c, ActionRoleDelete, []string{roleID}, func(options ...discordgo.RequestOption) error {
+			return s.GuildRoleDelete(guildID, roleID, options...)
+		})
+	}
+
+	err := s.GuildRoleDelete(guildID, roleID, auditOptions(c)...)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete role: " + err.Error())
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// maxRoleIconSize is the maximum size of a role icon accepted by Discord.
+const maxRoleIconSize = 256 * 1024
+
+// RoleIconParams sets the icon of a role, either an image or a unicode emoji.
+type RoleIconParams struct {
+	Image        string `json:"image,omitempty"`         // Icon image as base64 or data URI, up to 256 KiB
+	UnicodeEmoji string `json:"unicode_emoji,omitempty"` // Standard emoji shown as the icon, e.g. 🔥
+}
+
+// UpdateGuildRoleIcon sets the icon of a role to an image or a unicode emoji.
+//
+// The image can be sent as the raw request body with an image content type, or as base64 or
+// data URI in a JSON body; the emoji only in a JSON body. Setting one removes the other. Role
+// icons require the ROLE_ICONS feature, which guilds get with boost level 2.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - An image with a Content-Type of image/png, image/jpeg or image/gif, or
+//   - A RoleIconParams object with either image or unicode_emoji.
+//
+// Returns:
+//   - On success, it returns the updated role as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body or image is invalid,
+//     HTTP status 403 (Forbidden) if the role is protected,
+//     HTTP status 409 (Conflict) if the guild lacks the ROLE_ICONS feature,
+//     HTTP status 413 (Request Entity Too Large) if the image is larger than 256 KiB,
+//     or HTTP status 500 (Internal Server Error) if the icon cannot be set.
+// @Summary		Update Role Icon
+// @Description	Set the icon of a role to an image or a unicode emoji.
+// @Tags			Roles
+// @Accept			json,png,jpeg,gif
+// @Param			roleid		path		string			true	"Role ID"
+// @Param			body		body		RoleIconParams	true	"Icon image or emoji"
+// @Param			override	query		bool			false	"Allow changing a protected resource"
+// @Success		200			{object}	models.Role
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		409			{object}	error
+// @Failure		413			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/roles/{roleid}/icon [put]
+func UpdateGuildRoleIcon(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	roleID := c.Params("roleid")
+
+	if roleProtected(c, s, guildID, roleID) {
+		return protectedForbidden(c)
+	}
+
+	var params RoleIconParams
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), "image/") {
+		if len(c.Body()) > maxRoleIconSize {
+			return invalidImage(c, errImageTooLarge)
+		}
+		params.Image = base64.StdEncoding.EncodeToString(c.Body())
+	} else if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if (params.Image == "") == (params.UnicodeEmoji == "") {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: either image or unicode_emoji is required")
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild: " + err.Error())
+	}
+	if !slices.Contains(guild.Features, discordgo.GuildFeatureRoleIcons) {
+		return c.Status(fiber.StatusConflict).SendString("Role icons are not available: the ROLE_ICONS feature is required")
+	}
+
+	// The other icon is sent as null, which discordgo.RoleParams cannot express.
+	data := map[string]interface{}{"icon": nil, "unicode_emoji": nil}
+	if params.Image != "" {
+		image, err := imageDataURI(params.Image, maxRoleIconSize)
+		if err != nil {
+			return invalidImage(c, err)
+		}
+		data["icon"] = image
+	} else {
+		data["unicode_emoji"] = params.UnicodeEmoji
+	}
+
+	endpoint := discordgo.EndpointGuildRole(guildID, roleID)
+	body, err := s.RequestWithBucketID("PATCH", endpoint, data, discordgo.EndpointGuildRole(guildID, ""), auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update role icon: " + err.Error())
+	}
+	var role discordgo.Role
+	if err := json.Unmarshal(body, &role); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update role icon: " + err.Error())
+	}
+
+	return c.JSON(role)
+}
@@ -5,8 +5,50 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// HealthRouter registers the health probes, which are served without a token. New registers them
+// under /api before the token validation.
+func HealthRouter(router fiber.Router, s *discordgo.Session) {
+	router.Get("/health", func(c *fiber.Ctx) error {
+		return GetHealth(c, s)
+	})
+
+	router.Get("/health/ready", func(c *fiber.Ctx) error {
+		return GetReadiness(c, s)
+	})
+}
+
 func Router(router fiber.Router, s *discordgo.Session) {
 
+	router.Get("/csrf", GetCSRFToken)
+
+	router.Get("/admin/guilds", func(c *fiber.Ctx) error {
+		return GetAdminGuilds(c, s)
+	})
+
+	router.Post("/admin/guilds/:id/impersonate", func(c *fiber.Ctx) error {
+		return ImpersonateGuild(c, s)
+	})
+
+	router.Get("/admin/metrics", func(c *fiber.Ctx) error {
+		return GetAdminMetrics(c, s)
+	})
+
+	router.Get("/admin/status", func(c *fiber.Ctx) error {
+		return GetStatus(c, s)
+	})
+
+	router.Get("/admin/caches", func(c *fiber.Ctx) error {
+		return GetCaches(c, s)
+	})
+
+	router.Delete("/admin/caches", func(c *fiber.Ctx) error {
+		return FlushCache(c, s)
+	})
+
+	router.Delete("/admin/caches/:name", func(c *fiber.Ctx) error {
+		return FlushCache(c, s)
+	})
+
 	router.Get("/user", func(c *fiber.Ctx) error {
 		return GetBotUser(c, s)
 	})
@@ -15,10 +57,270 @@ func Router(router fiber.Router, s *discordgo.Session) {
 		return GetGuild(c, s)
 	})
 
+	router.Get("/guild/regions", func(c *fiber.Ctx) error {
+		return GetGuildRegions(c, s)
+	})
+
+	router.Get("/guild/invites", func(c *fiber.Ctx) error {
+		return GetGuildInvites(c, s)
+	})
+
+	router.Delete("/guild/invites/:code", func(c *fiber.Ctx) error {
+		return DeleteGuildInvite(c, s)
+	})
+
+	router.Get("/invites/:code", func(c *fiber.Ctx) error {
+		return GetInvite(c, s)
+	})
+
+	router.Delete("/invites/:code", func(c *fiber.Ctx) error {
+		return DeleteGuildInvite(c, s)
+	})
+
+	router.Get("/guild/audit-logs", func(c *fiber.Ctx) error {
+		return GetGuildAuditLog(c, s)
+	})
+
+	router.Get("/guild/audit", func(c *fiber.Ctx) error {
+		return GetAPIAudit(c, s)
+	})
+
+	router.Get("/guild/widget.png", func(c *fiber.Ctx) error {
+		return GetGuildWidgetImage(c, s)
+	})
+
+	router.Get("/guild/welcome-screen", func(c *fiber.Ctx) error {
+		return GetWelcomeScreen(c, s)
+	})
+
+	router.Patch("/guild/welcome-screen", func(c *fiber.Ctx) error {
+		return UpdateWelcomeScreen(c, s)
+	})
+
+	router.Get("/guild/discovery", func(c *fiber.Ctx) error {
+		return GetGuildDiscovery(c, s)
+	})
+
+	router.Patch("/guild/discovery", func(c *fiber.Ctx) error {
+		return UpdateGuildDiscovery(c, s)
+	})
+
+	router.Get("/guild/snapshot", func(c *fiber.Ctx) error {
+		return GetGuildSnapshot(c, s)
+	})
+
+	router.Post("/guild/snapshots", func(c *fiber.Ctx) error {
+		return CreateGuildSnapshot(c, s)
+	})
+
+	router.Get("/guild/diff", func(c *fiber.Ctx) error {
+		return GetGuildDiff(c, s)
+	})
+
+	router.Get("/guild/hooks", func(c *fiber.Ctx) error {
+		return GetGuildHooks(c, s)
+	})
+
+	router.Put("/guild/hooks/:integration", func(c *fiber.Ctx) error {
+		return PutGuildHook(c, s)
+	})
+
+	router.Delete("/guild/hooks/:integration", func(c *fiber.Ctx) error {
+		return DeleteGuildHook(c, s)
+	})
+
+	router.Get("/guild/relays", func(c *fiber.Ctx) error {
+		return GetGuildRelays(c, s)
+	})
+
+	router.Post("/guild/relays", func(c *fiber.Ctx) error {
+		return CreateGuildRelay(c, s)
+	})
+
+	router.Post("/guild/relays/:relayid/confirm", func(c *fiber.Ctx) error {
+		return ConfirmGuildRelay(c, s)
+	})
+
+	router.Delete("/guild/relays/:relayid", func(c *fiber.Ctx) error {
+		return DeleteGuildRelay(c, s)
+	})
+
+	router.Get("/guild/feeds", func(c *fiber.Ctx) error {
+		return GetGuildFeeds(c, s)
+	})
+
+	router.Get("/guild/feeds/:feedid", func(c *fiber.Ctx) error {
+		return GetGuildFeed(c, s)
+	})
+
+	router.Post("/guild/feeds", func(c *fiber.Ctx) error {
+		return CreateGuildFeed(c, s)
+	})
+
+	router.Patch("/guild/feeds/:feedid", func(c *fiber.Ctx) error {
+		return UpdateGuildFeed(c, s)
+	})
+
+	router.Delete("/guild/feeds/:feedid", func(c *fiber.Ctx) error {
+		return DeleteGuildFeed(c, s)
+	})
+
+	router.Get("/guild/giveaways", func(c *fiber.Ctx) error {
+		return GetGuildGiveaways(c, s)
+	})
+
+	router.Get("/guild/giveaways/:giveawayid", func(c *fiber.Ctx) error {
+		return GetGuildGiveaway(c, s)
+	})
+
+	router.Post("/guild/giveaways", func(c *fiber.Ctx) error {
+		return CreateGuildGiveaway(c, s)
+	})
+
+	router.Get("/guild/giveaways/:giveawayid/entrants", func(c *fiber.Ctx) error {
+		return GetGiveawayEntrants(c, s)
+	})
+
+	router.Post("/guild/giveaways/:giveawayid/reroll", func(c *fiber.Ctx) error {
+		return RerollGiveaway(c, s)
+	})
+
+	router.Delete("/guild/giveaways/:giveawayid", func(c *fiber.Ctx) error {
+		return DeleteGuildGiveaway(c, s)
+	})
+
+	router.Get("/guild/tasks", func(c *fiber.Ctx) error {
+		return GetGuildTasks(c, s)
+	})
+
+	router.Get("/guild/tasks/:taskid", func(c *fiber.Ctx) error {
+		return GetGuildTask(c, s)
+	})
+
+	router.Post("/guild/tasks", func(c *fiber.Ctx) error {
+		return CreateGuildTask(c, s)
+	})
+
+	router.Patch("/guild/tasks/:taskid", func(c *fiber.Ctx) error {
+		return UpdateGuildTask(c, s)
+	})
+
+	router.Post("/guild/tasks/:taskid/run", func(c *fiber.Ctx) error {
+		return RunGuildTask(c, s)
+	})
+
+	router.Delete("/guild/tasks/:taskid", func(c *fiber.Ctx) error {
+		return DeleteGuildTask(c, s)
+	})
+
+	router.Get("/guild/jobs", func(c *fiber.Ctx) error {
+		return GetGuildJobs(c, s)
+	})
+
+	router.Get("/guild/jobs/:jobid", func(c *fiber.Ctx) error {
+		return GetGuildJob(c, s)
+	})
+
+	router.Post("/guild/jobs", func(c *fiber.Ctx) error {
+		return CreateGuildJob(c, s)
+	})
+
+	router.Delete("/guild/jobs/:jobid", func(c *fiber.Ctx) error {
+		return CancelGuildJob(c, s)
+	})
+
+	router.Post("/guild/jobs/import", func(c *fiber.Ctx) error {
+		return CreateGuildImport(c, s)
+	})
+
+	router.Get("/guild/projections/members", func(c *fiber.Ctx) error {
+		return GetMemberDirectory(c, s)
+	})
+
+	router.Get("/guild/projections/channels", func(c *fiber.Ctx) error {
+		return GetChannelTree(c, s)
+	})
+
+	router.Post("/guild/users/resolve", func(c *fiber.Ctx) error {
+		return ResolveGuildUsers(c, s)
+	})
+
+	router.Get("/guild/events/export", func(c *fiber.Ctx) error {
+		return ExportGuildEvents(c, s)
+	})
+
+	router.Post("/guild/events/replay", func(c *fiber.Ctx) error {
+		return ReplayGuildEvents(c, s)
+	})
+
+	router.Get("/guild/ws/clients", func(c *fiber.Ctx) error {
+		return GetWebSocketClients(c, s)
+	})
+
+	router.Delete("/guild/ws/clients/:clientid", func(c *fiber.Ctx) error {
+		return KickWebSocketClient(c, s)
+	})
+
+	router.Get("/guild/ws/latency", func(c *fiber.Ctx) error {
+		return GetInteractionLatency(c, s)
+	})
+
+	router.Get("/guild/delivery-report", func(c *fiber.Ctx) error {
+		return GetDeliveryReport(c, s)
+	})
+
+	router.Get("/guild/search/messages", func(c *fiber.Ctx) error {
+		return SearchGuildMessages(c, s)
+	})
+
+	router.Get("/schema/events", func(c *fiber.Ctx) error {
+		return GetEventSchema(c, s)
+	})
+
+	router.Get("/schema/events.proto", func(c *fiber.Ctx) error {
+		return GetEventProto(c, s)
+	})
+
+	router.Get("/ws/protocol", func(c *fiber.Ctx) error {
+		return GetWSProtocol(c, s)
+	})
+
 	router.Post("/guild/interactions/:interactionid/:interactiontoken/callback", func(c *fiber.Ctx) error {
 		return CreateInteractionCallback(c, s)
 	})
 
+	router.Post("/guild/interactions/:interactionid/:interactiontoken/autocomplete", func(c *fiber.Ctx) error {
+		return CreateAutocompleteResponse(c, s)
+	})
+
+	router.Post("/guild/interactions/:apptoken/followups", func(c *fiber.Ctx) error {
+		return CreateFollowupMessage(c, s)
+	})
+
+	router.Patch("/guild/interactions/:apptoken/followups/:messageid", func(c *fiber.Ctx) error {
+		return UpdateFollowupMessage(c, s)
+	})
+
+	router.Delete("/guild/interactions/:apptoken/followups/:messageid", func(c *fiber.Ctx) error {
+		return DeleteFollowupMessage(c, s)
+	})
+
+	router.Get("/commands", func(c *fiber.Ctx) error {
+		return GetGlobalApplicationCommands(c, s)
+	})
+
+	router.Get("/commands/:cmdid", func(c *fiber.Ctx) error {
+		return GetGlobalApplicationCommand(c, s)
+	})
+
+	router.Post("/commands", func(c *fiber.Ctx) error {
+		return CreateGlobalApplicationCommand(c, s)
+	})
+
+	router.Delete("/commands/:cmdid", func(c *fiber.Ctx) error {
+		return DeleteGlobalApplicationCommand(c, s)
+	})
+
 	router.Get("/guild/commands", func(c *fiber.Ctx) error {
 		return GetGuildApplicationCommands(c, s)
 	})
@@ -31,10 +333,46 @@ func Router(router fiber.Router, s *discordgo.Session) {
 		return CreateGuildApplicationCommand(c, s)
 	})
 
+	router.Patch("/guild/commands/:cmdid", func(c *fiber.Ctx) error {
+		return UpdateGuildApplicationCommand(c, s)
+	})
+
 	router.Delete("/guild/commands/:cmdid", func(c *fiber.Ctx) error {
 		return DeleteGuildApplicationCommand(c, s)
 	})
 
+	router.Get("/guild/tokens", func(c *fiber.Ctx) error {
+		return GetGuildTokens(c, s)
+	})
+
+	router.Post("/guild/tokens", func(c *fiber.Ctx) error {
+		return IssueGuildToken(c, s)
+	})
+
+	router.Delete("/guild/tokens/:tokenid", func(c *fiber.Ctx) error {
+		return RevokeGuildToken(c, s)
+	})
+
+	router.Get("/guild/approvals", func(c *fiber.Ctx) error {
+		return GetPendingApprovals(c, s)
+	})
+
+	router.Post("/guild/approvals/:approvalid/approve", func(c *fiber.Ctx) error {
+		return ApproveAction(c, s)
+	})
+
+	router.Post("/guild/approvals/:approvalid/reject", func(c *fiber.Ctx) error {
+		return RejectAction(c, s)
+	})
+
+	router.Get("/guild/undo", func(c *fiber.Ctx) error {
+		return GetRecentChanges(c, s)
+	})
+
+	router.Post("/guild/undo/:changeid", func(c *fiber.Ctx) error {
+		return UndoChange(c, s)
+	})
+
 	router.Get("/guild/bans", func(c *fiber.Ctx) error {
 		return GetGuildBans(c, s)
 	})
@@ -83,10 +421,126 @@ func Router(router fiber.Router, s *discordgo.Session) {
 		return DeleteChannelPermissions(c, s)
 	})
 
+	router.Get("/guild/settings", func(c *fiber.Ctx) error {
+		return GetGuildSettings(c, s)
+	})
+
+	router.Put("/guild/settings", func(c *fiber.Ctx) error {
+		return SetGuildSettings(c, s)
+	})
+
+	router.Get("/guild/settings/token", func(c *fiber.Ctx) error {
+		return GetTokenSettings(c, s)
+	})
+
+	router.Put("/guild/settings/token", func(c *fiber.Ctx) error {
+		return SetTokenSettings(c, s)
+	})
+
+	router.Get("/guild/modules", func(c *fiber.Ctx) error {
+		return GetGuildModules(c, s)
+	})
+
+	router.Put("/guild/modules/:module", func(c *fiber.Ctx) error {
+		return SetGuildModule(c, s)
+	})
+
+	router.Get("/guild/scheduled-events/:eventid/users", func(c *fiber.Ctx) error {
+		return GetScheduledEventUsers(c, s)
+	})
+
+	router.Get("/guild/threads/active", func(c *fiber.Ctx) error {
+		return GetGuildActiveThreads(c, s)
+	})
+
+	router.Get("/guild/channels/:channelid/threads/archived/public", func(c *fiber.Ctx) error {
+		return GetPublicArchivedThreads(c, s)
+	})
+
+	router.Get("/guild/channels/:channelid/threads/archived/private", func(c *fiber.Ctx) error {
+		return GetPrivateArchivedThreads(c, s)
+	})
+
+	router.Post("/guild/channels/:channelid/threads", func(c *fiber.Ctx) error {
+		return CreateChannelThread(c, s)
+	})
+
+	router.Get("/guild/policy", func(c *fiber.Ctx) error {
+		return GetGuildPolicy(c, s)
+	})
+
+	router.Put("/guild/policy", func(c *fiber.Ctx) error {
+		return PutGuildPolicy(c, s)
+	})
+
+	router.Delete("/guild/policy", func(c *fiber.Ctx) error {
+		return DeleteGuildPolicy(c, s)
+	})
+
+	router.Get("/guild/policy/violations", func(c *fiber.Ctx) error {
+		return GetGuildPolicyViolations(c, s)
+	})
+
+	router.Get("/guild/overwrite-templates", func(c *fiber.Ctx) error {
+		return GetOverwriteTemplates(c, s)
+	})
+
+	router.Get("/guild/overwrite-templates/:name", func(c *fiber.Ctx) error {
+		return GetOverwriteTemplate(c, s)
+	})
+
+	router.Put("/guild/overwrite-templates/:name", func(c *fiber.Ctx) error {
+		return PutOverwriteTemplate(c, s)
+	})
+
+	router.Delete("/guild/overwrite-templates/:name", func(c *fiber.Ctx) error {
+		return DeleteOverwriteTemplate(c, s)
+	})
+
+	router.Post("/guild/overwrite-templates/:name/apply", func(c *fiber.Ctx) error {
+		return ApplyOverwriteTemplate(c, s)
+	})
+
+	router.Get("/guild/channels/:channelid/webhooks", func(c *fiber.Ctx) error {
+		return GetChannelWebhooks(c, s)
+	})
+
+	router.Post("/guild/channels/:channelid/webhooks", func(c *fiber.Ctx) error {
+		return CreateChannelWebhook(c, s)
+	})
+
+	router.Patch("/guild/channels/:channelid/webhooks/:webhookid", func(c *fiber.Ctx) error {
+		return UpdateChannelWebhook(c, s)
+	})
+
+	router.Delete("/guild/channels/:channelid/webhooks/:webhookid", func(c *fiber.Ctx) error {
+		return DeleteChannelWebhook(c, s)
+	})
+
+	router.Get("/guild/webhooks", func(c *fiber.Ctx) error {
+		return GetGuildWebhooks(c, s)
+	})
+
+	router.Post("/guild/webhooks/:webhookid/:token/execute", func(c *fiber.Ctx) error {
+		return ExecuteWebhook(c, s)
+	})
+
+	router.Post("/guild/channels/:channelid/impersonated-messages", func(c *fiber.Ctx) error {
+		return SendImpersonatedMessage(c, s)
+	})
+
 	router.Get("/guild/channels/:channelid/messages", func(c *fiber.Ctx) error {
 		return GetChannelMessages(c, s)
 	})
 
+	router.Get("/guild/channels/:channelid/transcript", func(c *fiber.Ctx) error {
+		return GetChannelTranscript(c, s)
+	})
+
+	router.Get("/guild/channels/:channelid/history", func(c *fiber.Ctx) error {
+		return GetChannelHistory(c, s)
+	})
+
 	router.Get("/guild/channels/:channelid/messages/:messageid", func(c *fiber.Ctx) error {
 		return GetChannelMessage(c, s)
 	})
@@ -115,11 +569,39 @@ func Router(router fiber.Router, s *discordgo.Session) {
 		return DeleteMessageReaction(c, s)
 	})
 
-	router.Get("/guild/channels/:channelid/messages/:messageid/reactions", func(c *fiber.Ctx) error {
+	router.Post("/guild/channels/:channelid/messages/:messageid/threads", func(c *fiber.Ctx) error {
+		return StartMessageThread(c, s)
+	})
+
+	router.Get("/guild/channels/:channelid/messages/:messageid/poll", func(c *fiber.Ctx) error {
+		return GetPollResults(c, s)
+	})
+
+	router.Get("/guild/channels/:channelid/messages/:messageid/polls/:answerid/voters", func(c *fiber.Ctx) error {
+		return GetPollVoters(c, s)
+	})
+
+	router.Post("/guild/channels/:channelid/messages/:messageid/polls/expire", func(c *fiber.Ctx) error {
+		return ExpirePoll(c, s)
+	})
+
+	router.Post("/guild/channels/:channelid/messages/:messageid/poll/announcement", func(c *fiber.Ctx) error {
+		return SchedulePollAnnouncement(c, s)
+	})
+
+	router.Delete("/guild/channels/:channelid/messages/:messageid/poll/announcement", func(c *fiber.Ctx) error {
+		return CancelPollAnnouncement(c, s)
+	})
+
+	router.Get("/guild/polls/announcements", func(c *fiber.Ctx) error {
+		return GetPollAnnouncements(c, s)
+	})
+
+	router.Delete("/guild/channels/:channelid/messages/:messageid/reactions", func(c *fiber.Ctx) error {
 		return DeleteAllMessageReaction(c, s)
 	})
 
-	router.Get("/guild/channels/:channelid/messages/:messageid/reactions/:emojiid", func(c *fiber.Ctx) error {
+	router.Delete("/guild/channels/:channelid/messages/:messageid/reactions/:emojiid", func(c *fiber.Ctx) error {
 		return DeleteMessageReactionEmoji(c, s)
 	})
 
@@ -139,6 +621,14 @@ func Router(router fiber.Router, s *discordgo.Session) {
 		return KickMember(c, s)
 	})
 
+	router.Get("/guild/members/:memberid/images", func(c *fiber.Ctx) error {
+		return GetMemberImages(c, s)
+	})
+
+	router.Patch("/guild/members/:memberid/images", func(c *fiber.Ctx) error {
+		return UpdateMemberImages(c, s)
+	})
+
 	router.Get("/guild/members/:memberid/roles", func(c *fiber.Ctx) error {
 		return GetMemberRoles(c, s)
 	})
@@ -163,7 +653,7 @@ func Router(router fiber.Router, s *discordgo.Session) {
 		return GetGuildRole(c, s)
 	})
 
-	router.Post("/guild/roles/:roleid", func(c *fiber.Ctx) error {
+	router.Post("/guild/roles", func(c *fiber.Ctx) error {
 		return CreateGuildRole(c, s)
 	})
 
@@ -174,4 +664,68 @@ func Router(router fiber.Router, s *discordgo.Session) {
 	router.Delete("/guild/roles/:roleid", func(c *fiber.Ctx) error {
 		return DeleteGuildRole(c, s)
 	})
+
+	router.Put("/guild/roles/:roleid/icon", func(c *fiber.Ctx) error {
+		return UpdateGuildRoleIcon(c, s)
+	})
+
+	router.Get("/guild/emojis", func(c *fiber.Ctx) error {
+		return GetGuildEmojis(c, s)
+	})
+
+	router.Post("/guild/emojis", func(c *fiber.Ctx) error {
+		return CreateGuildEmoji(c, s)
+	})
+
+	router.Get("/guild/emojis/:emojiid", func(c *fiber.Ctx) error {
+		return GetGuildEmoji(c, s)
+	})
+
+	router.Patch("/guild/emojis/:emojiid", func(c *fiber.Ctx) error {
+		return UpdateGuildEmoji(c, s)
+	})
+
+	router.Delete("/guild/emojis/:emojiid", func(c *fiber.Ctx) error {
+		return DeleteGuildEmoji(c, s)
+	})
+
+	router.Get("/guild/stickers", func(c *fiber.Ctx) error {
+		return GetGuildStickers(c, s)
+	})
+
+	router.Post("/guild/stickers", func(c *fiber.Ctx) error {
+		return CreateGuildSticker(c, s)
+	})
+
+	router.Get("/guild/stickers/:stickerid", func(c *fiber.Ctx) error {
+		return GetGuildSticker(c, s)
+	})
+
+	router.Patch("/guild/stickers/:stickerid", func(c *fiber.Ctx) error {
+		return UpdateGuildSticker(c, s)
+	})
+
+	router.Delete("/guild/stickers/:stickerid", func(c *fiber.Ctx) error {
+		return DeleteGuildSticker(c, s)
+	})
+
+	router.Get("/guild/automod/rules", func(c *fiber.Ctx) error {
+		return GetAutoModRules(c, s)
+	})
+
+	router.Post("/guild/automod/rules", func(c *fiber.Ctx) error {
+		return CreateAutoModRule(c, s)
+	})
+
+	router.Get("/guild/automod/rules/:ruleid", func(c *fiber.Ctx) error {
+		return GetAutoModRule(c, s)
+	})
+
+	router.Patch("/guild/automod/rules/:ruleid", func(c *fiber.Ctx) error {
+		return UpdateAutoModRule(c, s)
+	})
+
+	router.Delete("/guild/automod/rules/:ruleid", func(c *fiber.Ctx) error {
+		return DeleteAutoModRule(c, s)
+	})
 }
@@ -0,0 +1,57 @@
+package disgm
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+// ScheduledEventUser is a user interested in a scheduled event.
+type ScheduledEventUser = models.ScheduledEventUser
+
+// GetScheduledEventUsers retrieves the users interested in a scheduled event of the guild.
+//
+// This function returns the users who marked themselves as interested in the event, sorted by
+// user ID. Pages are read with the before and after parameters, set to the first or last user ID
+// of the previous page. With with_member, the guild member object of each user is included.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - eventid: The ID of the scheduled event.
+//
+// Request Query:
+//   - limit: The maximum number of users to return, 1 to 100 (default 100) unless changed with the Limits option.
+//   - with_member: Whether to include the guild member of each user.
+//   - before: Only return users with a lower ID.
+//   - after: Only return users with a higher ID.
+//
+// Returns:
+//   - On success, it returns the users of the event as a JSON array.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) if the users cannot be retrieved.
+// @Summary		Get Scheduled Event Users
+// @Description	Retrieve a page of the users interested in a scheduled event.
+// @Tags			Guild
+// @Param			eventid		path		string	true	"Scheduled event ID"
+// @Param			limit		query		int		false	"Maximum number of users"	minimum(1)	maximum(100)	default(100)
+// @Param			with_member	query		bool	false	"Include the guild member of each user"
+// @Param			before		query		string	false	"Only users with a lower ID"
+// @Param			after		query		string	false	"Only users with a higher ID"
+// @Success		200			{array}		models.ScheduledEventUser
+// @Failure		500			{object}	error
+// @Router			/api/guild/scheduled-events/{eventid}/users [get]
+func GetScheduledEventUsers(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	eventID := c.Params("eventid")
+
+	limit := queryLimit(c, LimitScheduledEventUsers)
+
+	users, err := s.GuildScheduledEventUsers(guildID, eventID, limit, c.QueryBool("with_member"), c.Query("before"), c.Query("after"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve scheduled event users: " + err.Error())
+	}
+
+	return c.JSON(users)
+}
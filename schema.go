@@ -0,0 +1,341 @@
+package disgm
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+// interactionPayload is an interaction with the fields of user-installed apps, which discordgo
+// does not decode.
+type interactionPayload struct {
+	discordgo.Interaction
+	Context                      *int              `json:"context,omitempty"`                        // Context the interaction was created in (0 = guild, 1 = bot DM, 2 = private channel)
+	AuthorizingIntegrationOwners map[string]string `json:"authorizing_integration_owners,omitempty"` // IDs of the guild (0) and user (1) the app was installed by
+}
+
+// UnmarshalJSON decodes the interaction with discordgo and the fields of user-installed apps, as
+// the decoder of the embedded interaction would otherwise skip them.
+func (p *interactionPayload) UnmarshalJSON(data []byte) error {
+	if err := p.Interaction.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var apps struct {
+		Context                      *int              `json:"context"`
+		AuthorizingIntegrationOwners map[string]string `json:"authorizing_integration_owners"`
+	}
+	if err := json.Unmarshal(data, &apps); err != nil {
+		return err
+	}
+	p.Context, p.AuthorizingIntegrationOwners = apps.Context, apps.AuthorizingIntegrationOwners
+	return nil
+}
+
+// The payloads of gateway events that are not a plain model.
+type (
+	guildMemberPayload struct {
+		GuildID string `json:"guild_id"`
+		models.Member
+	}
+	guildUserPayload struct {
+		GuildID string       `json:"guild_id"`
+		User    *models.User `json:"user"`
+	}
+	guildRolePayload struct {
+		GuildID string       `json:"guild_id"`
+		Role    *models.Role `json:"role"`
+	}
+	guildRoleDeletePayload struct {
+		GuildID string `json:"guild_id"`
+		RoleID  string `json:"role_id"`
+	}
+	messageDeletePayload struct {
+		ID        string `json:"id"`
+		ChannelID string `json:"channel_id"`
+		GuildID   string `json:"guild_id"`
+	}
+	reactionRemoveAllPayload struct {
+		ChannelID string `json:"channel_id"`
+		MessageID string `json:"message_id"`
+		GuildID   string `json:"guild_id"`
+	}
+	pollVotePayload struct {
+		UserID    string `json:"user_id"`
+		ChannelID string `json:"channel_id"`
+		MessageID string `json:"message_id"`
+		GuildID   string `json:"guild_id"`
+		AnswerID  int    `json:"answer_id"`
+	}
+	// presenceUpdatePayload decodes the presence updates disgm sends, which discordgo cannot read
+	// back: discordgo.Activity marshals created_at as a time, but unmarshals it from Unix milliseconds.
+	presenceUpdatePayload struct {
+		User         *discordgo.User        `json:"user"`
+		Status       discordgo.Status       `json:"status"`
+		Activities   []*presenceActivity    `json:"activities"`
+		Since        *int                   `json:"since"`
+		ClientStatus discordgo.ClientStatus `json:"client_status"`
+		GuildID      string                 `json:"guild_id"`
+	}
+	presenceActivity    discordgo.Activity
+	threadCreatePayload struct {
+		models.Channel
+		NewlyCreated bool `json:"newly_created,omitempty"` // Whether the thread was just created, rather than joined
+	}
+	threadDeletePayload struct {
+		ID       string `json:"id"`
+		GuildID  string `json:"guild_id"`
+		ParentID string `json:"parent_id"`
+		Type     int    `json:"type"`
+	}
+	threadMemberPayload struct {
+		models.ThreadMember
+		Member *models.Member `json:"member,omitempty"`
+	}
+	threadMembersUpdatePayload struct {
+		ID               string                `json:"id"`
+		GuildID          string                `json:"guild_id"`
+		ParentID         string                `json:"parent_id,omitempty"`          // Parent channel of the thread, added by disgm if the thread is in the state
+		MemberCount      int                   `json:"member_count"`                 // Approximate number of members, stops counting at 50
+		AddedMembers     []threadMemberPayload `json:"added_members,omitempty"`      // Members added to the thread
+		RemovedMemberIDs []string              `json:"removed_member_ids,omitempty"` // IDs of the users removed from the thread
+	}
+	threadListSyncPayload struct {
+		GuildID    string                `json:"guild_id"`
+		ChannelIDs []string              `json:"channel_ids,omitempty"` // Parent channels synced, all of the guild if missing
+		Threads    []models.Channel      `json:"threads"`               // Active threads of the channels the bot can view
+		Members    []models.ThreadMember `json:"members"`               // Thread members of the bot for the threads it joined
+	}
+	errorPayload struct {
+		Error string `json:"error"`
+	}
+)
+
+// eventPayloads maps every event disgm can emit over WebSocket to an example of its payload type.
+var eventPayloads = map[string]interface{}{
+	"GUILD_UPDATE":                models.Guild{},
+	"VOICE_STATE_UPDATE":          discordgo.VoiceState{},
+	"GUILD_MEMBER_ADD":            guildMemberPayload{},
+	"GUILD_MEMBER_UPDATE":         guildMemberPayload{},
+	"GUILD_MEMBER_REMOVE":         guildUserPayload{},
+	"GUILD_BAN_ADD":               guildUserPayload{},
+	"GUILD_BAN_REMOVE":            guildUserPayload{},
+	"CHANNEL_CREATE":              models.Channel{},
+	"CHANNEL_UPDATE":              models.Channel{},
+	"CHANNEL_DELETE":              models.Channel{},
+	"THREAD_CREATE":               threadCreatePayload{},
+	"THREAD_UPDATE":               models.Channel{},
+	"THREAD_DELETE":               threadDeletePayload{},
+	"THREAD_MEMBERS_UPDATE":       threadMembersUpdatePayload{},
+	"THREAD_LIST_SYNC":            threadListSyncPayload{},
+	"GUILD_ROLE_CREATE":           guildRolePayload{},
+	"GUILD_ROLE_UPDATE":           guildRolePayload{},
+	"GUILD_ROLE_DELETE":           guildRoleDeletePayload{},
+	"MESSAGE_CREATE":              models.Message{},
+	"MESSAGE_UPDATE":              models.Message{},
+	"MESSAGE_DELETE":              messageDeletePayload{},
+	"MESSAGE_REACTION_ADD":        discordgo.MessageReaction{},
+	"MESSAGE_REACTION_REMOVE":     discordgo.MessageReaction{},
+	"MESSAGE_REACTION_REMOVE_ALL": reactionRemoveAllPayload{},
+	"MESSAGE_POLL_VOTE_ADD":       pollVotePayload{},
+	"MESSAGE_POLL_VOTE_REMOVE":    pollVotePayload{},
+	"INTERACTION_CREATE":          interactionPayload{},
+	"APPROVAL_CREATE":             Approval{},
+	"APPROVAL_UPDATE":             Approval{},
+	"GIVEAWAY_END":                Giveaway{},
+	"GIVEAWAY_REROLL":             Giveaway{},
+	"TASK_RUN":                    Task{},
+	"POLICY_VIOLATION":            PolicyReport{},
+	"HELLO":                       Hello{},
+	"HEARTBEAT_ACK":               HeartbeatAck{},
+	"READY":                       ReadySnapshot{},
+	"CHAT_SENT":                   models.Message{},
+	"CHAT_ERROR":                  ChatError{},
+	"CLIENT_RELAY":                ClientRelayMessage{},
+	"RELAY_SENT":                  RelaySent{},
+	"RELAY_ERROR":                 errorPayload{},
+	"SUBSCRIBED":                  Subscription{},
+	"SUBSCRIBE_ERROR":             errorPayload{},
+	"BATCH_UPDATED":               BatchOptions{},
+	"BATCH_ERROR":                 errorPayload{},
+	"ENCRYPTION_ENABLED":          Encryption{},
+	"ENCRYPTION_ERROR":            errorPayload{},
+	"PRESENCE_UPDATE":             presenceUpdatePayload{},
+	"TYPING_START":                discordgo.TypingStart{},
+}
+
+// schemaGenerator builds JSON Schemas from Go types by reflection, the way encoding/json
+// serializes them. Named structs are emitted once under $defs and referenced, which also
+// terminates recursive types.
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor returns the schema of a type.
+func (g *schemaGenerator) schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // Reserves the name, so recursive references terminate.
+			g.defs[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
+	}
+	return map[string]interface{}{} // Interfaces accept any value.
+}
+
+// structSchema returns the object schema of a struct, flattening embedded structs like encoding/json.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	g.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the serialized fields of a struct to the properties of an object schema.
+func (g *schemaGenerator) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(ft, properties, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		if strings.Contains(opts, "string") {
+			properties[name] = map[string]interface{}{"type": "string"}
+		} else {
+			properties[name] = g.schemaFor(f.Type)
+		}
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName returns the $defs name of a named type, qualified by its package name.
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return pkg + "." + t.Name()
+}
+
+// eventSchema is the generated schema document, built on first use.
+var eventSchema = sync.OnceValue(func() map[string]interface{} {
+	g := &schemaGenerator{defs: make(map[string]interface{})}
+
+	names := make([]string, 0, len(eventPayloads))
+	for name := range eventPayloads {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	envelopes := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		g.defs[name] = g.schemaFor(reflect.TypeOf(eventPayloads[name]))
+		envelopes = append(envelopes, map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":   map[string]interface{}{"const": name},
+				"data":   map[string]interface{}{"$ref": "#/$defs/" + name},
+				"replay": map[string]interface{}{"type": "boolean"},
+			},
+			"required": []string{"name", "data"},
+		})
+	}
+
+	return map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "disgm WebSocket event",
+		"description": "Envelope of every event sent over the disgm WebSocket. The payload of each event is defined under $defs by its name.",
+		"oneOf":       envelopes,
+		"$defs":       g.defs,
+	}
+})
+
+// GetEventSchema returns the JSON Schema of the events disgm emits.
+//
+// The schema is generated from the models and describes the envelope of every event sent over
+// the WebSocket, with the payload of each event under $defs by its name, so consumers outside
+// Go can generate types and validate streams.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the JSON Schema document.
+//
+// @Summary		Get Event Schema
+// @Description	Retrieve the JSON Schema of every WebSocket event envelope and payload.
+// @Tags			Events
+// @Produce		json
+// @Success		200	{object}	map[string]interface{}
+// @Router			/api/schema/events [get]
+func GetEventSchema(c *fiber.Ctx, s *discordgo.Session) error {
+	return c.JSON(eventSchema())
+}
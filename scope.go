@@ -0,0 +1,127 @@
+package disgm
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/store"
+)
+
+// channelAllowed reports whether the token of the request may access a channel.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context holding the token scope under the key "Scope".
+//   - channelID: string – The ID of the channel being accessed.
+//   - write: bool – Whether the request modifies the channel or its messages.
+//
+// Returns:
+//   - bool: True if the token is unrestricted or the channel is in its allowlist.
+func channelAllowed(c *fiber.Ctx, channelID string, write bool) bool {
+	return scopeAllows(c.Locals("Scope"), channelID, write)
+}
+
+// scopeAllows reports whether a scope stored in the Locals of a request or WebSocket connection
+// grants access to a channel. A missing scope, or one listing no channels, means the token is
+// unrestricted.
+func scopeAllows(local interface{}, channelID string, write bool) bool {
+	scope, _ := local.(*store.Scope)
+	if !scope.Restricted() {
+		return true
+	}
+
+	if slices.Contains(scope.WriteChannels, channelID) {
+		return true
+	}
+	return !write && slices.Contains(scope.ReadChannels, channelID)
+}
+
+// eventScopeChannelIDs returns the channels an event belongs to for the token scopes of WebSocket
+// clients: the channel of channel events, and the channels matched by subscriptions for the others.
+// Recorded events, whose data is raw JSON, are decoded first.
+func eventScopeChannelIDs(name string, data interface{}) []string {
+	if raw, ok := data.(json.RawMessage); ok {
+		var decoded map[string]interface{}
+		if json.Unmarshal(raw, &decoded) != nil {
+			return nil
+		}
+		data = decoded
+	}
+
+	switch name {
+	case "CHANNEL_CREATE", "CHANNEL_UPDATE", "CHANNEL_DELETE":
+		if m, ok := data.(map[string]interface{}); ok {
+			if id, _ := m["id"].(string); id != "" {
+				return []string{id}
+			}
+		}
+		return nil
+	}
+	return eventChannelIDs(name, data)
+}
+
+// scopeAllowsEvent reports whether a scope grants read access to an event with the given channels.
+// Events without a channel, e.g. GUILD_MEMBER_ADD, are allowed, and thread events are allowed if the
+// thread or its parent channel is.
+func scopeAllowsEvent(scope *store.Scope, channelIDs []string) bool {
+	if len(channelIDs) == 0 {
+		return true
+	}
+	for _, id := range channelIDs {
+		if scopeAllows(scope, id, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// channelForbidden responds with HTTP status 403 (Forbidden) for channels outside the token scope.
+func channelForbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).SendString("Forbidden: channel is not allowed for this token")
+}
+
+// unrestricted reports whether the token of the request has no channel scope, which is required
+// for guild-wide administrative endpoints.
+func unrestricted(c *fiber.Ctx) bool {
+	scope, _ := c.Locals("Scope").(*store.Scope)
+	return !scope.Restricted()
+}
+
+// adminForbidden responds with HTTP status 403 (Forbidden) for restricted tokens on administrative endpoints.
+func adminForbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).SendString("Forbidden: this endpoint requires an unrestricted token")
+}
+
+// permissionNames are the names of the permissions checked before calling Discord.
+var permissionNames = map[int64]string{
+	discordgo.PermissionViewChannel:        "VIEW_CHANNEL",
+	discordgo.PermissionReadMessageHistory: "READ_MESSAGE_HISTORY",
+	discordgo.PermissionManageWebhooks:     "MANAGE_WEBHOOKS",
+	discordgo.PermissionSendMessages:       "SEND_MESSAGES",
+	discordgo.PermissionEmbedLinks:         "EMBED_LINKS",
+}
+
+// botMissingPermission returns the name of the first permission the bot lacks in a channel, or an
+// empty string if it has them all. The permissions are computed from the state, so nothing is
+// reported for channels or members the state does not hold; Discord checks those itself.
+func botMissingPermission(s *discordgo.Session, channelID string, permissions ...int64) string {
+	if s.State.User == nil {
+		return ""
+	}
+	granted, err := s.State.UserChannelPermissions(s.State.User.ID, channelID)
+	if err != nil {
+		return ""
+	}
+	for _, p := range permissions {
+		if granted&p == 0 {
+			return permissionNames[p]
+		}
+	}
+	return ""
+}
+
+// permissionForbidden responds with HTTP status 403 (Forbidden) for a permission the bot lacks.
+func permissionForbidden(c *fiber.Ctx, permission string) error {
+	return c.Status(fiber.StatusForbidden).SendString("Forbidden: the bot lacks the " + permission + " permission in this channel")
+}
@@ -0,0 +1,196 @@
+package disgm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/swaggo/swag"
+)
+
+// approvalActions lists the actions that can be placed under the two-person approval policy.
+var approvalActions = []string{ActionBulkBan, ActionChannelDelete, ActionRoleDelete}
+
+// checkOptions validates the options when disgm is created, so a misconfiguration fails New with
+// the option to fix instead of surfacing as odd behavior at runtime.
+func checkOptions(opt *Options) error {
+	var problems []string
+
+	for _, action := range opt.ApprovalActions {
+		if !slices.Contains(approvalActions, action) {
+			problems = append(problems, fmt.Sprintf("ApprovalActions: unknown action %q, use one of %s", action, strings.Join(approvalActions, ", ")))
+		}
+	}
+	for _, module := range opt.DisabledModules {
+		if !slices.Contains(Modules, module) {
+			problems = append(problems, fmt.Sprintf("DisabledModules: unknown module %q, use one of %s", module, strings.Join(Modules, ", ")))
+		}
+	}
+	if opt.MirrorURL != "" {
+		u, err := url.Parse(opt.MirrorURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("MirrorURL: %q is not an absolute http or https URL", opt.MirrorURL))
+		}
+	}
+	if opt.PublicURL != "" {
+		u, err := url.Parse(opt.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("PublicURL: %q is not an absolute http or https URL", opt.PublicURL))
+		}
+	}
+	for i, rule := range opt.FaultInjection {
+		if !strings.HasPrefix(rule.Path, "/") {
+			problems = append(problems, fmt.Sprintf("FaultInjection[%d]: path %q must start with /", i, rule.Path))
+		}
+		if rule.Percent < 0 || rule.Percent > 100 {
+			problems = append(problems, fmt.Sprintf("FaultInjection[%d]: percent %v must be between 0 and 100", i, rule.Percent))
+		}
+		if rule.Status != 0 && (rule.Status < 400 || rule.Status > 599) {
+			problems = append(problems, fmt.Sprintf("FaultInjection[%d]: status %d is not an error status", i, rule.Status))
+		}
+	}
+	for _, origin := range opt.WebSocketOrigins {
+		if origin != "*" && !strings.Contains(origin, "://") {
+			problems = append(problems, fmt.Sprintf("WebSocketOrigins: %q needs a scheme, e.g. https://%s", origin, origin))
+		}
+	}
+	if opt.ErrorBudget.MaxErrorRate > 1 {
+		problems = append(problems, fmt.Sprintf("ErrorBudget.MaxErrorRate: %v is a share between 0 and 1", opt.ErrorBudget.MaxErrorRate))
+	}
+	for _, proxy := range opt.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				problems = append(problems, fmt.Sprintf("TrustedProxies: %q is neither an IP nor a CIDR range", proxy))
+			}
+		}
+	}
+	if opt.Prefork {
+		problems = append(problems, "Prefork: not supported, every process would run its own gateway session, schedulers and state")
+	}
+	if opt.PrefetchMessages > 100 {
+		problems = append(problems, fmt.Sprintf("PrefetchMessages: %d exceeds the limit of 100 messages per request", opt.PrefetchMessages))
+	}
+	throttledNames := make([]string, 0, len(opt.EventThrottles))
+	for name := range opt.EventThrottles {
+		throttledNames = append(throttledNames, name)
+	}
+	slices.Sort(throttledNames)
+	for _, name := range throttledNames {
+		switch limit := opt.EventThrottles[name]; {
+		case name == interactionEvent:
+			problems = append(problems, fmt.Sprintf("EventThrottles: %s cannot be throttled, every interaction needs a response", name))
+		case !slices.Contains(forwardedEvents, name):
+			problems = append(problems, fmt.Sprintf("EventThrottles: %q is not a forwarded event", name))
+		case limit < 1:
+			problems = append(problems, fmt.Sprintf("EventThrottles: %s must allow at least 1 event per second", name))
+		}
+	}
+	problems = append(problems, checkListLimits(opt.Limits)...)
+	if slices.Contains(opt.AdminTokens, "") {
+		problems = append(problems, "AdminTokens: admin tokens must not be empty")
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid options:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// routeShape returns a route path with its parameter names left out, e.g. /api/guild/roles/: for
+// /api/guild/roles/:roleid, so routes that differ only in parameter names compare equal.
+func routeShape(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || (strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")) {
+			segments[i] = ":"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// checkRoutes reports routes registered twice for a method and routes a parametric route
+// registered before them shadows, as Fiber matches routes in registration order.
+func checkRoutes(routes []fiber.Route) []string {
+	var problems []string
+	for i, route := range routes {
+		if route.Method == fiber.MethodHead {
+			continue // Registered along with every GET route.
+		}
+		shape := routeShape(route.Path)
+		for _, earlier := range routes[:i] {
+			if earlier.Method != route.Method {
+				continue
+			}
+			switch earlierShape := routeShape(earlier.Path); {
+			case earlierShape == shape:
+				problems = append(problems, fmt.Sprintf("%s %s is registered twice, as %s and %s", route.Method, route.Path, earlier.Path, route.Path))
+			case matchRoute(earlierShape, shape):
+				problems = append(problems, fmt.Sprintf("%s %s is unreachable, %s is registered before it; register it first", route.Method, route.Path, earlier.Path))
+			default:
+				continue
+			}
+			break
+		}
+	}
+	return problems
+}
+
+// checkAnnotations reports API routes without a swagger annotation. Plugin routes are left out,
+// as plugins are not part of the generated documentation.
+func checkAnnotations(routes []fiber.Route) ([]string, error) {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return nil, err
+	}
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, err
+	}
+	documented := make(map[string]bool)
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			documented[strings.ToUpper(method)+" "+routeShape(path)] = true
+		}
+	}
+
+	var problems []string
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead || !strings.HasPrefix(route.Path, "/api/") || strings.HasPrefix(route.Path, "/api/plugins/") {
+			continue
+		}
+		if !documented[route.Method+" "+routeShape(route.Path)] {
+			problems = append(problems, fmt.Sprintf("%s %s has no swagger annotation; add one and run swag init", route.Method, route.Path))
+		}
+	}
+	return problems, nil
+}
+
+// SelfCheck checks the registered routes for conflicts and missing swagger annotations.
+//
+// Listen and Listener run it before serving, so a route registered twice or shadowed by an
+// earlier one fails startup instead of silently answering with the wrong handler.
+//
+// Returns:
+//   - error: An error listing every problem found, or nil.
+func (d *Disgm) SelfCheck() error {
+	routes := d.fiber.GetRoutes(true)
+
+	problems := checkRoutes(routes)
+	missing, err := checkAnnotations(routes)
+	if err != nil {
+		return fmt.Errorf("failed to read the swagger documentation: %w", err)
+	}
+	problems = append(problems, missing...)
+
+	if len(problems) > 0 {
+		return errors.New("self-check failed:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package disgm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxAuditReason is the length of the audit-log reasons Discord keeps.
+const maxAuditReason = 512
+
+// auditReasonPlaceholders are the placeholders of audit-reason templates.
+var auditReasonPlaceholders = []string{"acting_user", "reason", "guild_id", "route"}
+
+// placeholderPattern matches the placeholders of an audit-reason template, e.g. {{reason}}.
+var placeholderPattern = regexp.MustCompile(`{{\s*([a-z_]*)\s*}}`)
+
+// GuildSettings are the settings of a guild, shared by all of its tokens.
+type GuildSettings struct {
+	AuditReasonTemplate string          `json:"audit_reason_template"` // Template of the audit-log reasons with the placeholders acting_user, reason, guild_id and route, empty for the default
+	Digest              *DigestSettings `json:"digest,omitempty"`      // Summary of the guild's activity posted daily or weekly, none if unset
+}
+
+// TokenSettings are the settings of the token of a request, which take precedence over the
+// settings of its guild.
+type TokenSettings struct {
+	AuditReasonTemplate string `json:"audit_reason_template"` // Template of the audit-log reasons of the token, empty to use the guild's
+}
+
+// The settings, stored in the key-value store keyed by guild ID, and by guild ID and token hash
+// for the settings of tokens.
+var settings = struct {
+	sync.Mutex
+	storage *Storage
+}{}
+
+// tokenSettingsKey returns the key of the settings of a token, which stores a hash of the token
+// instead of the token itself.
+func tokenSettingsKey(guildID, token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return guildID + "/token/" + hex.EncodeToString(sum[:16])
+}
+
+// readSettings reads stored settings into v, leaving v unchanged if there are none.
+func readSettings(key string, v interface{}) error {
+	settings.Lock()
+	storage := settings.storage
+	settings.Unlock()
+
+	if storage == nil {
+		return nil
+	}
+	value, ok, err := storage.Get(key)
+	if err != nil || !ok {
+		return err
+	}
+	return json.Unmarshal(value, v)
+}
+
+// writeSettings stores settings, removing them if they equal the zero value.
+func writeSettings(key string, v interface{}, empty bool) error {
+	settings.Lock()
+	defer settings.Unlock()
+
+	if empty {
+		return settings.storage.Delete(key)
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return settings.storage.Set(key, value)
+}
+
+// validateAuditReasonTemplate checks that a template only uses known placeholders and fits the
+// length of audit-log reasons.
+func validateAuditReasonTemplate(template string) error {
+	if utf8.RuneCountInString(template) > maxAuditReason {
+		return fmt.Errorf("audit_reason_template must have at most %d characters", maxAuditReason)
+	}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(auditReasonPlaceholders, m[1]) {
+			return fmt.Errorf("audit_reason_template has unknown placeholder %s, use one of {{%s}}", m[0], strings.Join(auditReasonPlaceholders, "}}, {{"))
+		}
+	}
+	return nil
+}
+
+// auditReasonTemplate returns the audit-reason template of a request: the template of its token,
+// else the template of its guild, else an empty string.
+func auditReasonTemplate(c *fiber.Ctx) string {
+	guildID, _ := c.Locals("ID").(string)
+	token, _ := c.Locals("Token").(string)
+	if guildID == "" {
+		return ""
+	}
+
+	var tokenSettings TokenSettings
+	if token != "" && readSettings(tokenSettingsKey(guildID, token), &tokenSettings) == nil && tokenSettings.AuditReasonTemplate != "" {
+		return tokenSettings.AuditReasonTemplate
+	}
+	var guildSettings GuildSettings
+	if readSettings(guildID, &guildSettings) == nil {
+		return guildSettings.AuditReasonTemplate
+	}
+	return ""
+}
+
+// renderAuditReason fills the placeholders of a template. Separators left dangling by empty
+// placeholders, e.g. the colon of "{{acting_user}}: {{reason}}" without a reason, are trimmed.
+func renderAuditReason(template string, c *fiber.Ctx, user, reason string) string {
+	guildID, _ := c.Locals("ID").(string)
+	values := map[string]string{
+		"acting_user": user,
+		"reason":      reason,
+		"guild_id":    guildID,
+		"route":       c.Method() + " " + c.Route().Path,
+	}
+	rendered := placeholderPattern.ReplaceAllStringFunc(template, func(m string) string {
+		return values[placeholderPattern.FindStringSubmatch(m)[1]]
+	})
+	rendered = strings.Trim(rendered, " :-|,")
+
+	if utf8.RuneCountInString(rendered) > maxAuditReason {
+		rendered = string([]rune(rendered)[:maxAuditReason])
+	}
+	return rendered
+}
+
+// GetGuildSettings retrieves the settings of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the settings as JSON.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) if the settings cannot be read.
+// @Summary		Get Guild Settings
+// @Description	Retrieve the settings of the guild, e.g. the audit-reason template.
+// @Tags			Settings
+// @Success		200	{object}	GuildSettings
+// @Failure		500	{object}	error
+// @Router			/api/guild/settings [get]
+func GetGuildSettings(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	var guildSettings GuildSettings
+	if err := readSettings(guildID, &guildSettings); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read settings: " + err.Error())
+	}
+
+	return c.JSON(guildSettings)
+}
+
+// SetGuildSettings replaces the settings of the guild.
+//
+// The audit-reason template is applied to the audit-log reasons of all changes made through
+// disgm for the guild, unless the token has its own template. Its placeholders are
+// {{acting_user}}, {{reason}}, {{guild_id}} and {{route}}.
+//
+// The digest posts a summary embed of joins, leaves, messages per channel and moderation actions
+// to its channel every day or week at midnight UTC. It is posted by a scheduled task named Digest,
+// listed with the other tasks, where it can be paused or run early. Moderation actions are read
+// from the audit log, which needs the GUILD_MODERATION intent and the View Audit Log permission.
+// Removing the digest discards its counts. Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A GuildSettings object.
+//
+// Returns:
+//   - On success, it returns the new settings as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body, template or digest is invalid,
+//     HTTP status 403 (Forbidden) for tokens restricted to channels or if the bot cannot post the
+//     digest, or HTTP status 500 (Internal Server Error) if the settings cannot be stored.
+// @Summary		Set Guild Settings
+// @Description	Replace the settings of the guild, e.g. the audit-reason template and the activity digest.
+// @Tags			Settings
+// @Param			body	body		GuildSettings	true	"Settings"
+// @Success		200		{object}	GuildSettings
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/settings [put]
+func SetGuildSettings(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var guildSettings GuildSettings
+	if err := c.BodyParser(&guildSettings); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if err := validateAuditReasonTemplate(guildSettings.AuditReasonTemplate); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if d := guildSettings.Digest; d != nil {
+		if err := d.validate(); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+		}
+		channel, err := cachedChannel(s, d.ChannelID)
+		if err != nil || channel.GuildID != guildID {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: digest channel not found in guild")
+		}
+		if permission := botMissingPermission(s, d.ChannelID, discordgo.PermissionViewChannel, discordgo.PermissionSendMessages, discordgo.PermissionEmbedLinks); permission != "" {
+			return permissionForbidden(c, permission)
+		}
+	}
+
+	if err := writeSettings(guildID, guildSettings, guildSettings == GuildSettings{}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store settings: " + err.Error())
+	}
+	if err := configureDigest(s, guildID, guildSettings.Digest); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to schedule digest: " + err.Error())
+	}
+
+	return c.JSON(guildSettings)
+}
+
+// GetTokenSettings retrieves the settings of the token of the request.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the settings as JSON.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) if the settings cannot be read.
+// @Summary		Get Token Settings
+// @Description	Retrieve the settings of the token of the request, e.g. its audit-reason template.
+// @Tags			Settings
+// @Success		200	{object}	TokenSettings
+// @Failure		500	{object}	error
+// @Router			/api/guild/settings/token [get]
+func GetTokenSettings(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	token, _ := c.Locals("Token").(string)
+
+	var tokenSettings TokenSettings
+	if err := readSettings(tokenSettingsKey(guildID, token), &tokenSettings); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read settings: " + err.Error())
+	}
+
+	return c.JSON(tokenSettings)
+}
+
+// SetTokenSettings replaces the settings of the token of the request.
+//
+// The audit-reason template of a token takes precedence over the template of its guild, so each
+// dashboard sharing a guild can attribute its changes. It takes the same placeholders as the
+// template of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A TokenSettings object.
+//
+// Returns:
+//   - On success, it returns the new settings as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body or template is invalid,
+//     or HTTP status 500 (Internal Server Error) if the settings cannot be stored.
+// @Summary		Set Token Settings
+// @Description	Replace the settings of the token of the request, e.g. its audit-reason template.
+// @Tags			Settings
+// @Param			body	body		TokenSettings	true	"Settings"
+// @Success		200		{object}	TokenSettings
+// @Failure		400		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/settings/token [put]
+func SetTokenSettings(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	token, _ := c.Locals("Token").(string)
+
+	var tokenSettings TokenSettings
+	if err := c.BodyParser(&tokenSettings); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if err := validateAuditReasonTemplate(tokenSettings.AuditReasonTemplate); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	if err := writeSettings(tokenSettingsKey(guildID, token), tokenSettings, tokenSettings == TokenSettings{}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store settings: " + err.Error())
+	}
+
+	return c.JSON(tokenSettings)
+}
@@ -0,0 +1,337 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxSnapshots is the number of structure snapshots kept per guild.
+const maxSnapshots = 10
+
+// ChannelShape is the structural part of a channel compared by the diff endpoint.
+type ChannelShape struct {
+	ID               string `json:"id"`                  // Snowflake ID of the channel
+	Name             string `json:"name"`                // Name of the channel
+	Type             int    `json:"type"`                // Type of the channel
+	Topic            string `json:"topic"`               // Topic of the channel
+	Position         int    `json:"position"`            // Sorting position of the channel
+	ParentID         string `json:"parent_id"`           // ID of the parent category
+	NSFW             bool   `json:"nsfw"`                // Whether the channel is NSFW
+	Bitrate          int    `json:"bitrate"`             // Bitrate of a voice channel
+	UserLimit        int    `json:"user_limit"`          // User limit of a voice channel
+	RateLimitPerUser int    `json:"rate_limit_per_user"` // Slowmode in seconds
+}
+
+// RoleShape is the structural part of a role compared by the diff endpoint.
+type RoleShape struct {
+	ID          string `json:"id"`          // Snowflake ID of the role
+	Name        string `json:"name"`        // Name of the role
+	Color       int    `json:"color"`       // Color of the role
+	Hoist       bool   `json:"hoist"`       // Whether the role is hoisted
+	Position    int    `json:"position"`    // Position of the role
+	Permissions int64  `json:"permissions"` // Permissions of the role
+	Mentionable bool   `json:"mentionable"` // Whether the role is mentionable
+}
+
+// OverwriteShape is a permission overwrite of a channel compared by the diff endpoint.
+type OverwriteShape struct {
+	ChannelID string `json:"channel_id"` // ID of the channel the overwrite belongs to
+	ID        string `json:"id"`         // ID of the role or member
+	Type      int    `json:"type"`       // Type of overwrite (0 = role, 1 = member)
+	Allow     int64  `json:"allow"`      // Allowed permissions bit set
+	Deny      int64  `json:"deny"`       // Denied permissions bit set
+}
+
+// Snapshot is a stored copy of the guild structure.
+type Snapshot struct {
+	ID        string    `json:"id"`         // Unique ID of the snapshot
+	CreatedAt time.Time `json:"created_at"` // When the snapshot was taken
+
+	channels   map[string]ChannelShape
+	roles      map[string]RoleShape
+	overwrites map[string]OverwriteShape
+}
+
+// ShapeChange is a resource that exists in both states with different values.
+type ShapeChange[T any] struct {
+	Before T `json:"before"` // Value in the snapshot
+	After  T `json:"after"`  // Current value
+}
+
+// ShapeDiff lists the added, removed and changed resources of one kind.
+type ShapeDiff[T any] struct {
+	Added   []T              `json:"added"`
+	Removed []T              `json:"removed"`
+	Changed []ShapeChange[T] `json:"changed"`
+}
+
+// GuildDiff is the difference between a snapshot and the current guild structure.
+type GuildDiff struct {
+	Since      string                    `json:"since"`      // ID of the snapshot compared against
+	Channels   ShapeDiff[ChannelShape]   `json:"channels"`   // Channel differences
+	Roles      ShapeDiff[RoleShape]      `json:"roles"`      // Role differences
+	Overwrites ShapeDiff[OverwriteShape] `json:"overwrites"` // Permission overwrite differences
+}
+
+// storedSnapshot is a snapshot as persisted in the key-value store.
+type storedSnapshot struct {
+	Snapshot
+	Channels   map[string]ChannelShape   `json:"channels"`
+	Roles      map[string]RoleShape      `json:"roles"`
+	Overwrites map[string]OverwriteShape `json:"overwrites"`
+}
+
+// The storage of the recent snapshots, keyed by "<guild>/<created>-<id>" so keys sort by time and
+// the snapshots survive restarts.
+var snapshots = struct {
+	sync.Mutex
+	storage *Storage
+}{}
+
+// snapshotKey returns the storage key of a snapshot.
+func snapshotKey(guildID string, snap *Snapshot) string {
+	return fmt.Sprintf("%s/%020d-%s", guildID, snap.CreatedAt.UnixNano(), snap.ID)
+}
+
+// takeSnapshot reads the current structure of a guild from the Discord API.
+func takeSnapshot(s *discordgo.Session, guildID string) (*Snapshot, error) {
+	channels, err := s.GuildChannels(guildID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{
+		CreatedAt:  time.Now(),
+		channels:   make(map[string]ChannelShape, len(channels)),
+		roles:      make(map[string]RoleShape, len(roles)),
+		overwrites: make(map[string]OverwriteShape),
+	}
+
+	for _, ch := range channels {
+		snap.channels[ch.ID] = ChannelShape{
+			ID:               ch.ID,
+			Name:             ch.Name,
+			Type:             int(ch.Type),
+			Topic:            ch.Topic,
+			Position:         ch.Position,
+			ParentID:         ch.ParentID,
+			NSFW:             ch.NSFW,
+			Bitrate:          ch.Bitrate,
+			UserLimit:        ch.UserLimit,
+			RateLimitPerUser: ch.RateLimitPerUser,
+		}
+		for _, o := range ch.PermissionOverwrites {
+			snap.overwrites[ch.ID+":"+o.ID] = OverwriteShape{
+				ChannelID: ch.ID,
+				ID:        o.ID,
+				Type:      int(o.Type),
+				Allow:     o.Allow,
+				Deny:      o.Deny,
+			}
+		}
+	}
+
+	for _, r := range roles {
+		snap.roles[r.ID] = RoleShape{
+			ID:          r.ID,
+			Name:        r.Name,
+			Color:       r.Color,
+			Hoist:       r.Hoist,
+			Position:    r.Position,
+			Permissions: r.Permissions,
+			Mentionable: r.Mentionable,
+		}
+	}
+
+	return snap, nil
+}
+
+// diffShapes compares two sets of resources keyed by ID, sorted by key for stable output.
+func diffShapes[T comparable](before, after map[string]T) ShapeDiff[T] {
+	diff := ShapeDiff[T]{Added: []T{}, Removed: []T{}, Changed: []ShapeChange[T]{}}
+
+	keys := make([]string, 0, len(before)+len(after))
+	for k := range before {
+		keys = append(keys, k)
+	}
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		b, inBefore := before[k]
+		a, inAfter := after[k]
+		switch {
+		case !inBefore:
+			diff.Added = append(diff.Added, a)
+		case !inAfter:
+			diff.Removed = append(diff.Removed, b)
+		case a != b:
+			diff.Changed = append(diff.Changed, ShapeChange[T]{Before: b, After: a})
+		}
+	}
+	return diff
+}
+
+// CreateGuildSnapshot stores a snapshot of the current guild structure.
+//
+// This function records the channels, roles and permission overwrites of the guild, so later
+// changes can be reviewed with the diff endpoint. Only the most recent snapshots are kept.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Context:
+//   - ID: The guild ID is stored in the Fiber context under the key "ID".
+//
+// Returns:
+//   - On success, it returns the snapshot ID and creation time as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) if the guild cannot be read
+//     or the snapshot cannot be stored.
+// @Summary		Create Guild Snapshot
+// @Description	Store a snapshot of the guild channels, roles and overwrites.
+// @Tags			Guild
+// @Success		201	{object}	Snapshot
+// @Failure		500	{object}	error
+// @Router			/api/guild/snapshots [post]
+func CreateGuildSnapshot(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	snap, err := takeSnapshot(s, guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create snapshot: " + err.Error())
+	}
+	if snap.ID, err = randomToken(8); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create snapshot: " + err.Error())
+	}
+
+	if err := storeSnapshot(guildID, snap); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to store snapshot: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(snap)
+}
+
+// storeSnapshot persists a snapshot of a guild, dropping the oldest beyond maxSnapshots.
+func storeSnapshot(guildID string, snap *Snapshot) error {
+	value, err := json.Marshal(storedSnapshot{
+		Snapshot:   *snap,
+		Channels:   snap.channels,
+		Roles:      snap.roles,
+		Overwrites: snap.overwrites,
+	})
+	if err != nil {
+		return err
+	}
+
+	snapshots.Lock()
+	defer snapshots.Unlock()
+
+	return snapshots.storage.Update(func(tx *StorageTx) error {
+		if err := tx.Set(snapshotKey(guildID, snap), value); err != nil {
+			return err
+		}
+		keys, err := tx.List(guildID + "/")
+		if err != nil {
+			return err
+		}
+		for _, key := range keys[:max(0, len(keys)-maxSnapshots)] {
+			if err := tx.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// loadSnapshot reads a stored snapshot of a guild by ID.
+func loadSnapshot(guildID, id string) (*Snapshot, error) {
+	snapshots.Lock()
+	defer snapshots.Unlock()
+
+	var snap *Snapshot
+	err := snapshots.storage.View(func(tx *StorageTx) error {
+		keys, err := tx.List(guildID + "/")
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(keys, func(key string) bool { return id != "" && strings.HasSuffix(key, "-"+id) })
+		if i < 0 {
+			return nil
+		}
+		value, ok, err := tx.Get(keys[i])
+		if err != nil || !ok {
+			return err
+		}
+
+		var stored storedSnapshot
+		if err := json.Unmarshal(value, &stored); err != nil {
+			return err
+		}
+		snap = &stored.Snapshot
+		snap.channels, snap.roles, snap.overwrites = stored.Channels, stored.Roles, stored.Overwrites
+		return nil
+	})
+	return snap, err
+}
+
+// GetGuildDiff compares the current guild structure against a stored snapshot.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Query:
+//   - since: The ID of the snapshot to compare against.
+//
+// Returns:
+//   - On success, it returns the added, removed and changed channels, roles and overwrites as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the snapshot is unknown,
+//     or HTTP status 500 (Internal Server Error) if the snapshot or the guild cannot be read.
+// @Summary		Get Guild Diff
+// @Description	Compare the current guild structure against a stored snapshot.
+// @Tags			Guild
+// @Param			since	query		string	true	"Snapshot ID"
+// @Success		200		{object}	GuildDiff
+// @Failure		404		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/diff [get]
+func GetGuildDiff(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	since := c.Query("since")
+
+	before, err := loadSnapshot(guildID, since)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read snapshot: " + err.Error())
+	}
+	if before == nil {
+		return c.Status(fiber.StatusNotFound).SendString("Snapshot not found")
+	}
+
+	after, err := takeSnapshot(s, guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to read guild structure: " + err.Error())
+	}
+
+	return c.JSON(GuildDiff{
+		Since:      before.ID,
+		Channels:   diffShapes(before.channels, after.channels),
+		Roles:      diffShapes(before.roles, after.roles),
+		Overwrites: diffShapes(before.overwrites, after.overwrites),
+	})
+}
@@ -0,0 +1,48 @@
+package disgm
+
+import (
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// cachedChannel returns a copy of a channel from the state cache, falling back to the Discord API.
+//
+// The copy can be kept as a before-image, since the state cache updates its channels in place.
+func cachedChannel(s *discordgo.Session, channelID string) (*discordgo.Channel, error) {
+	channel, err := s.State.Channel(channelID)
+	if err != nil {
+		return s.Channel(channelID)
+	}
+
+	s.State.RLock()
+	defer s.State.RUnlock()
+
+	cp := *channel
+	cp.PermissionOverwrites = make([]*discordgo.PermissionOverwrite, len(channel.PermissionOverwrites))
+	for i, overwrite := range channel.PermissionOverwrites {
+		o := *overwrite
+		cp.PermissionOverwrites[i] = &o
+	}
+	return &cp, nil
+}
+
+// cachedRole returns a copy of a role from the state cache, falling back to the Discord API.
+func cachedRole(s *discordgo.Session, guildID, roleID string) (*discordgo.Role, error) {
+	role, err := s.State.Role(guildID, roleID)
+	if err == nil {
+		cp := *role
+		return &cp, nil
+	}
+
+	roles, err := s.GuildRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(roles, func(r *discordgo.Role) bool { return r.ID == roleID })
+	if i < 0 {
+		return nil, discordgo.ErrStateNotFound
+	}
+	return roles[i], nil
+}
@@ -0,0 +1,354 @@
+package disgm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrorBudget configures the error rates tracked for the status endpoint and the thresholds
+// above which the readiness probe reports disgm as not ready.
+type ErrorBudget struct {
+	Window           time.Duration // Time the error rates are computed over, defaults to 5 minutes
+	MaxErrorRate     float64       // Share of API requests failing with a server error, 0-1, above which disgm is not ready, 0 to never
+	MinRequests      int           // API requests in the window before the error rate is judged, defaults to 20
+	MaxDiscordErrors int           // Errors returned by the Discord API in the window above which disgm is not ready, 0 to never
+}
+
+// statusBucket counts the requests and errors of one minute.
+type statusBucket struct {
+	routes  map[string]*RouteStatus // Keyed by method and route
+	discord map[int]*DiscordErrorStatus
+}
+
+// The error budget and the counters of the last minutes, keyed by the Unix minute.
+var errorBudget = struct {
+	sync.Mutex
+	config  ErrorBudget
+	buckets map[int64]*statusBucket
+}{buckets: make(map[int64]*statusBucket)}
+
+// RouteStatus are the recent requests of an API route.
+type RouteStatus struct {
+	Method       string  `json:"method"`
+	Route        string  `json:"route"`         // Route pattern, e.g. /api/guild/channels/:channelid
+	Requests     int     `json:"requests"`      // Requests in the window
+	ClientErrors int     `json:"client_errors"` // Requests answered with a 4xx status
+	Errors       int     `json:"errors"`        // Requests answered with a 5xx status
+	ErrorRate    float64 `json:"error_rate"`    // Share of requests answered with a 5xx status
+}
+
+// DiscordErrorStatus are the recent errors of the Discord API with one error code.
+type DiscordErrorStatus struct {
+	Code       int    `json:"code"`        // Discord JSON error code, 0 for errors without one, e.g. rate limits
+	Message    string `json:"message"`     // Message of the latest error
+	HTTPStatus int    `json:"http_status"` // HTTP status of the latest error
+	Count      int    `json:"count"`       // Errors in the window
+}
+
+// Status reports the recent error rates of disgm and whether it is ready.
+type Status struct {
+	Ready         bool                 `json:"ready"`          // Whether the gateway is ready and the error budget is kept
+	Reasons       []string             `json:"reasons"`        // Why disgm is not ready
+	Gateway       bool                 `json:"gateway"`        // Whether the gateway connection is ready
+	WindowSeconds int                  `json:"window_seconds"` // Time the counts cover
+	Requests      int                  `json:"requests"`       // API requests in the window
+	Errors        int                  `json:"errors"`         // API requests answered with a 5xx status
+	ErrorRate     float64              `json:"error_rate"`     // Share of API requests answered with a 5xx status
+	Routes        []RouteStatus        `json:"routes"`         // Routes with requests in the window, most errors first
+	DiscordErrors []DiscordErrorStatus `json:"discord_errors"` // Discord API errors in the window, most frequent first
+	Throttled     []ThrottledEvents    `json:"throttled"`      // Events dropped by the EventThrottles option since disgm started, most dropped first
+}
+
+// currentBucket returns the bucket of the current minute, dropping buckets outside the window.
+// The caller must hold the lock of errorBudget.
+func currentBucket() *statusBucket {
+	minute := time.Now().Unix() / 60
+	b, ok := errorBudget.buckets[minute]
+	if !ok {
+		b = &statusBucket{routes: make(map[string]*RouteStatus), discord: make(map[int]*DiscordErrorStatus)}
+		errorBudget.buckets[minute] = b
+		oldest := minute - windowMinutes()
+		for m := range errorBudget.buckets {
+			if m <= oldest {
+				delete(errorBudget.buckets, m)
+			}
+		}
+	}
+	return b
+}
+
+// windowMinutes returns the number of minute buckets covering the window.
+// The caller must hold the lock of errorBudget.
+func windowMinutes() int64 {
+	return int64(math.Ceil(errorBudget.config.Window.Minutes()))
+}
+
+// recordRequest counts an API request by its route and status.
+func recordRequest(method, route string, status int) {
+	errorBudget.Lock()
+	defer errorBudget.Unlock()
+
+	b := currentBucket()
+	key := method + " " + route
+	r, ok := b.routes[key]
+	if !ok {
+		r = &RouteStatus{Method: method, Route: route}
+		b.routes[key] = r
+	}
+	r.Requests++
+	switch {
+	case status >= 500:
+		r.Errors++
+	case status >= 400:
+		r.ClientErrors++
+	}
+}
+
+// recordDiscordError counts an error response of the Discord API.
+func recordDiscordError(code int, message string, status int) {
+	errorBudget.Lock()
+	defer errorBudget.Unlock()
+
+	b := currentBucket()
+	e, ok := b.discord[code]
+	if !ok {
+		e = &DiscordErrorStatus{Code: code}
+		b.discord[code] = e
+	}
+	e.Message, e.HTTPStatus = message, status
+	e.Count++
+}
+
+// statusMiddleware counts the API requests by route and status. Requests that match no route
+// are counted under the route "*", so unknown paths do not grow the counters.
+func statusMiddleware(c *fiber.Ctx) error {
+	if !strings.HasPrefix(c.Path(), "/api/") {
+		return c.Next()
+	}
+
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		}
+	}
+	route := c.Route().Path
+	if !strings.HasPrefix(route, "/api/") {
+		route = "*"
+	}
+	recordRequest(c.Method(), route, status)
+	return err
+}
+
+// discordErrorRecorder wraps the HTTP transport of the session to count the error responses of
+// the Discord API by their JSON error code. discordgo retries rate limited requests, so 429
+// responses are counted even if the request eventually succeeds. It also records the rate limits
+// of the responses, which the job planner paces its calls by.
+type discordErrorRecorder struct {
+	next http.RoundTripper
+}
+
+// RoundTrip sends the request, records its rate limit and counts the response if it is an error.
+func (t discordErrorRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	observeRateLimit(req, resp)
+	if resp.StatusCode < 400 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var discordErr struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &discordErr)
+	if discordErr.Message == "" {
+		discordErr.Message = http.StatusText(resp.StatusCode)
+	}
+	recordDiscordError(discordErr.Code, discordErr.Message, resp.StatusCode)
+	return resp, nil
+}
+
+// recordDiscordErrors installs the discordErrorRecorder on the HTTP client of a session.
+func recordDiscordErrors(s *discordgo.Session) {
+	if s.Client == nil {
+		s.Client = &http.Client{}
+	}
+	next := s.Client.Transport
+	if _, ok := next.(discordErrorRecorder); ok {
+		return
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	s.Client.Transport = discordErrorRecorder{next: next}
+}
+
+// currentStatus sums the counters of the window and checks them against the error budget.
+func currentStatus(s *discordgo.Session) Status {
+	errorBudget.Lock()
+	config := errorBudget.config
+	oldest := time.Now().Unix()/60 - windowMinutes()
+	routes := make(map[string]*RouteStatus)
+	discord := make(map[int]*DiscordErrorStatus)
+	latest := make(map[int]int64)
+	for minute, b := range errorBudget.buckets {
+		if minute <= oldest {
+			continue
+		}
+		for key, r := range b.routes {
+			sum, ok := routes[key]
+			if !ok {
+				sum = &RouteStatus{Method: r.Method, Route: r.Route}
+				routes[key] = sum
+			}
+			sum.Requests += r.Requests
+			sum.ClientErrors += r.ClientErrors
+			sum.Errors += r.Errors
+		}
+		for code, e := range b.discord {
+			sum, ok := discord[code]
+			if !ok {
+				sum = &DiscordErrorStatus{Code: code}
+				discord[code] = sum
+			}
+			if minute >= latest[code] {
+				sum.Message, sum.HTTPStatus, latest[code] = e.Message, e.HTTPStatus, minute
+			}
+			sum.Count += e.Count
+		}
+	}
+	errorBudget.Unlock()
+
+	status := Status{
+		Gateway:       s.DataReady,
+		WindowSeconds: int(config.Window / time.Second),
+		Reasons:       []string{},
+		Routes:        make([]RouteStatus, 0, len(routes)),
+		DiscordErrors: make([]DiscordErrorStatus, 0, len(discord)),
+		Throttled:     throttledEvents(),
+	}
+	for _, r := range routes {
+		r.ErrorRate = rate(r.Errors, r.Requests)
+		status.Requests += r.Requests
+		status.Errors += r.Errors
+		status.Routes = append(status.Routes, *r)
+	}
+	slices.SortFunc(status.Routes, func(a, b RouteStatus) int {
+		if a.Errors != b.Errors {
+			return b.Errors - a.Errors
+		}
+		return strings.Compare(a.Route+" "+a.Method, b.Route+" "+b.Method)
+	})
+	discordErrors := 0
+	for _, e := range discord {
+		discordErrors += e.Count
+		status.DiscordErrors = append(status.DiscordErrors, *e)
+	}
+	slices.SortFunc(status.DiscordErrors, func(a, b DiscordErrorStatus) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return a.Code - b.Code
+	})
+	status.ErrorRate = rate(status.Errors, status.Requests)
+
+	if !status.Gateway {
+		status.Reasons = append(status.Reasons, "gateway is not ready")
+	}
+	if config.MaxErrorRate > 0 && status.Requests >= config.MinRequests && status.ErrorRate > config.MaxErrorRate {
+		status.Reasons = append(status.Reasons, "error rate "+strconv.FormatFloat(status.ErrorRate, 'f', -1, 64)+" exceeds "+strconv.FormatFloat(config.MaxErrorRate, 'f', -1, 64))
+	}
+	if config.MaxDiscordErrors > 0 && discordErrors > config.MaxDiscordErrors {
+		status.Reasons = append(status.Reasons, strconv.Itoa(discordErrors)+" Discord API errors exceed "+strconv.Itoa(config.MaxDiscordErrors))
+	}
+	status.Ready = len(status.Reasons) == 0
+	return status
+}
+
+// rate returns the share of errors in requests, rounded to three decimals.
+func rate(errors, requests int) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return math.Round(float64(errors)/float64(requests)*1000) / 1000
+}
+
+// GetStatus retrieves the recent error rates of the API routes and the Discord API.
+//
+// The requests of every API route are counted by status over the window of the ErrorBudget
+// option, together with the errors returned by the Discord API by their JSON error code and the
+// events dropped by the EventThrottles option. The status is not ready if the gateway is down or
+// the error budget is exceeded. The counters cover all guilds, so the endpoint requires an admin
+// token, see the AdminTokens option.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the status as JSON, with HTTP status 200 even if not ready.
+//   - On failure, it returns an HTTP status 403 (Forbidden) without an admin token.
+// @Summary		Get Status
+// @Description	Retrieve the recent error rates per route and Discord error code, the throttled events, and whether disgm is ready.
+// @Tags			Admin
+// @Success		200	{object}	Status
+// @Failure		403	{object}	error
+// @Router			/api/admin/status [get]
+func GetStatus(c *fiber.Ctx, s *discordgo.Session) error {
+	if !isAdmin(c) {
+		return adminTokenRequired(c)
+	}
+
+	return c.JSON(currentStatus(s))
+}
+
+// GetReadiness reports whether disgm is ready to serve requests, for readiness probes.
+//
+// disgm is ready if the gateway connection is ready and the error budget is kept. Probes only
+// need the status code; the body lists the reasons disgm is not ready. The endpoint is served
+// without a token, see HealthRouter.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - HTTP status 200 (OK) if ready, or HTTP status 503 (Service Unavailable) otherwise, with the
+//     reasons as a JSON array.
+// @Summary		Get Readiness
+// @Description	Report whether the gateway is ready and the error budget is kept, for readiness probes.
+// @Tags			Health
+// @Success		200	{array}		string
+// @Failure		503	{array}		string
+// @Router			/api/health/ready [get]
+func GetReadiness(c *fiber.Ctx, s *discordgo.Session) error {
+	status := currentStatus(s)
+	if !status.Ready {
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	return c.JSON(status.Reasons)
+}
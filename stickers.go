@@ -0,0 +1,262 @@
+package disgm
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+type Sticker = models.Sticker
+
+// maxStickerSize is the maximum size of a sticker file accepted by Discord.
+const maxStickerSize = 512 * 1024
+
+// quoteEscaper escapes the file name of a form part, like mime/multipart.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// GetGuildStickers retrieves all stickers of a guild.
+//
+// discordgo has no sticker endpoints, so this function calls the Discord API directly.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the stickers as a JSON array with HTTP status 200.
+//   - On failure, it returns an HTTP status 500 and an error message if the stickers cannot be retrieved.
+// @Summary		Get Guild Stickers
+// @Description	Retrieve all stickers of the guild.
+// @Tags			Stickers
+// @Success		200	{array}		Sticker
+// @Failure		500	{object}	error
+// @Router			/api/guild/stickers [get]
+func GetGuildStickers(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	endpoint := discordgo.EndpointGuildStickers(guildID)
+	body, err := s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve stickers: " + err.Error())
+	}
+
+	var stickers []*discordgo.Sticker
+	if err := json.Unmarshal(body, &stickers); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve stickers: " + err.Error())
+	}
+
+	return c.JSON(stickers)
+}
+
+// GetGuildSticker retrieves a specific sticker of a guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the sticker as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 500 and an error message if the sticker cannot be retrieved.
+// @Summary		Get Guild Sticker
+// @Description	Retrieve a specific sticker of the guild by its ID.
+// @Tags			Stickers
+// @Param			stickerid	path		string	true	"Sticker ID"
+// @Success		200			{object}	models.Sticker
+// @Failure		500			{object}	error
+// @Router			/api/guild/stickers/{stickerid} [get]
+func GetGuildSticker(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	stickerID := c.Params("stickerid")
+
+	body, err := s.RequestWithBucketID("GET", discordgo.EndpointGuildSticker(guildID, stickerID), nil, discordgo.EndpointGuildStickers(guildID))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve sticker: " + err.Error())
+	}
+
+	var sticker discordgo.Sticker
+	if err := json.Unmarshal(body, &sticker); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve sticker: " + err.Error())
+	}
+
+	return c.JSON(sticker)
+}
+
+// CreateGuildSticker uploads a sticker to a guild.
+//
+// This function reads a multipart form with the sticker file and its name, description and
+// tags, and forwards it to Discord. Discord accepts PNG, APNG, GIF and Lottie JSON files up
+// to 512 KB.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body (multipart/form-data):
+//   - file: The sticker file.
+//   - name: The name of the sticker, 2-30 characters.
+//   - description: The description of the sticker, empty or 2-100 characters.
+//   - tags: Autocomplete and suggestion tags, e.g. the name of a related emoji.
+//
+// Returns:
+//   - On success, it returns the created sticker as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the form is invalid,
+//     HTTP status 413 (Request Entity Too Large) if the file is too large,
+//     or HTTP status 500 if the sticker cannot be created.
+// @Summary		Create Guild Sticker
+// @Description	Upload a sticker to the guild.
+// @Tags			Stickers
+// @Accept			multipart/form-data
+// @Param			file		formData	file	true	"Sticker file (PNG, APNG, GIF or Lottie JSON, max 512 KB)"
+// @Param			name		formData	string	true	"Name of the sticker"
+// @Param			description	formData	string	false	"Description of the sticker"
+// @Param			tags		formData	string	true	"Autocomplete and suggestion tags"
+// @Success		201			{object}	models.Sticker
+// @Failure		400			{object}	error
+// @Failure		413			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/stickers [post]
+func CreateGuildSticker(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if header.Size > maxStickerSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).SendString("Invalid request body: file exceeds 512 KB")
+	}
+	if c.FormValue("name") == "" || c.FormValue("tags") == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name and tags are required")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	defer file.Close()
+
+	fields := map[string]string{"name": c.FormValue("name"), "description": c.FormValue("description"), "tags": c.FormValue("tags")}
+	sticker, err := createSticker(s, guildID, fields, header.Filename, header.Header.Get("Content-Type"), file, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create sticker: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(sticker)
+}
+
+// createSticker uploads a sticker file with the name, description and tags fields.
+func createSticker(s *discordgo.Session, guildID string, fields map[string]string, filename, contentType string, file io.Reader, options ...discordgo.RequestOption) (*discordgo.Sticker, error) {
+	// Discord expects the fields and the file as separate form parts, without payload_json.
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	for _, field := range []string{"name", "description", "tags"} {
+		form.WriteField(field, fields[field])
+	}
+
+	part := make(textproto.MIMEHeader)
+	part.Set("Content-Disposition", `form-data; name="file"; filename="`+quoteEscaper.Replace(filename)+`"`)
+	part.Set("Content-Type", contentType)
+	if part.Get("Content-Type") == "" {
+		part.Set("Content-Type", "application/octet-stream")
+	}
+	w, err := form.CreatePart(part)
+	if err == nil {
+		_, err = io.Copy(w, file)
+	}
+	if err == nil {
+		err = form.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := discordgo.EndpointGuildStickers(guildID)
+	body, err := s.RequestWithLockedBucket("POST", endpoint, form.FormDataContentType(), buf.Bytes(), s.Ratelimiter.LockBucket(endpoint), 0, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var sticker discordgo.Sticker
+	if err := json.Unmarshal(body, &sticker); err != nil {
+		return nil, err
+	}
+	return &sticker, nil
+}
+
+// UpdateGuildSticker edits the name, description or tags of a guild sticker.
+//
+// The file of a sticker cannot be changed after upload.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the updated sticker as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     or HTTP status 500 if the sticker cannot be updated.
+// @Summary		Update Guild Sticker
+// @Description	Edit the name, description or tags of a guild sticker.
+// @Tags			Stickers
+// @Param			stickerid	path		string					true	"Sticker ID"
+// @Param			body		body		models.StickerParams	true	"Sticker parameters"
+// @Success		200			{object}	models.Sticker
+// @Failure		400			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/stickers/{stickerid} [patch]
+func UpdateGuildSticker(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	stickerID := c.Params("stickerid")
+
+	var params models.StickerParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	body, err := s.RequestWithBucketID("PATCH", discordgo.EndpointGuildSticker(guildID, stickerID), params, discordgo.EndpointGuildStickers(guildID), auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update sticker: " + err.Error())
+	}
+
+	var sticker discordgo.Sticker
+	if err := json.Unmarshal(body, &sticker); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update sticker: " + err.Error())
+	}
+
+	return c.JSON(sticker)
+}
+
+// DeleteGuildSticker deletes a sticker from a guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns an HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 500 and an error message if the sticker cannot be deleted.
+// @Summary		Delete Guild Sticker
+// @Description	Delete a sticker from the guild.
+// @Tags			Stickers
+// @Param			stickerid	path	string	true	"Sticker ID"
+// @Success		204
+// @Failure		500	{object}	error
+// @Router			/api/guild/stickers/{stickerid} [delete]
+func DeleteGuildSticker(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	stickerID := c.Params("stickerid")
+
+	_, err := s.RequestWithBucketID("DELETE", discordgo.EndpointGuildSticker(guildID, stickerID), nil, discordgo.EndpointGuildStickers(guildID), auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete sticker: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,117 @@
+package disgm
+
+import (
+	"strings"
+
+	"github.com/rif223/disgm/store"
+)
+
+// Storage is a namespaced view of the key-value store configured in Options.KVStore.
+//
+// Plugins and embedders each get their own namespace, so keys never collide and nobody needs
+// their own database wiring. Single operations run in their own transaction; use Update or View
+// to combine several operations atomically.
+type Storage struct {
+	kv     store.KVStore
+	prefix string
+}
+
+// StorageTx is a transaction on a Storage namespace.
+type StorageTx struct {
+	tx     store.KVTx
+	prefix string
+}
+
+// StoragePlugin is implemented by plugins that persist data. RegisterPlugin passes them the
+// storage namespace "plugin/<name>" before mounting their routes.
+type StoragePlugin interface {
+	Plugin
+
+	// SetStorage receives the storage namespace of the plugin.
+	SetStorage(storage *Storage)
+}
+
+// Storage returns the storage namespace with the given name.
+//
+// Parameters:
+//   - namespace: string – The name of the namespace, e.g. the name of the embedding application.
+//
+// Returns:
+//   - *Storage: The storage namespace.
+func (d *Disgm) Storage(namespace string) *Storage {
+	return &Storage{kv: d.opt.KVStore, prefix: namespace + "/"}
+}
+
+// View runs fn in a read-only transaction on the namespace.
+func (st *Storage) View(fn func(tx *StorageTx) error) error {
+	return st.kv.View(func(tx store.KVTx) error {
+		return fn(&StorageTx{tx: tx, prefix: st.prefix})
+	})
+}
+
+// Update runs fn in a read-write transaction on the namespace. The changes are committed
+// if fn returns nil and discarded otherwise.
+func (st *Storage) Update(fn func(tx *StorageTx) error) error {
+	return st.kv.Update(func(tx store.KVTx) error {
+		return fn(&StorageTx{tx: tx, prefix: st.prefix})
+	})
+}
+
+// Get retrieves the value of a key and whether it exists.
+func (st *Storage) Get(key string) (value []byte, ok bool, err error) {
+	err = st.View(func(tx *StorageTx) error {
+		value, ok, err = tx.Get(key)
+		return err
+	})
+	return
+}
+
+// Set stores the value of a key.
+func (st *Storage) Set(key string, value []byte) error {
+	return st.Update(func(tx *StorageTx) error {
+		return tx.Set(key, value)
+	})
+}
+
+// Delete removes a key.
+func (st *Storage) Delete(key string) error {
+	return st.Update(func(tx *StorageTx) error {
+		return tx.Delete(key)
+	})
+}
+
+// List retrieves the keys starting with prefix in ascending order.
+func (st *Storage) List(prefix string) (keys []string, err error) {
+	err = st.View(func(tx *StorageTx) error {
+		keys, err = tx.List(prefix)
+		return err
+	})
+	return
+}
+
+// Get retrieves the value of a key and whether it exists.
+func (tx *StorageTx) Get(key string) ([]byte, bool, error) {
+	return tx.tx.Get(tx.prefix + key)
+}
+
+// Set stores the value of a key.
+func (tx *StorageTx) Set(key string, value []byte) error {
+	return tx.tx.Set(tx.prefix+key, value)
+}
+
+// Delete removes a key.
+func (tx *StorageTx) Delete(key string) error {
+	return tx.tx.Delete(tx.prefix + key)
+}
+
+// List retrieves the keys starting with prefix in ascending order, without the namespace.
+func (tx *StorageTx) List(prefix string) ([]string, error) {
+	keys, err := tx.tx.List(tx.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, tx.prefix)
+	}
+	return keys, nil
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrReadOnly is returned when a read-only transaction is used to modify data.
+var ErrReadOnly = errors.New("store: transaction is read-only")
+
+// KVStore defines an interface for a transactional key-value store.
+//
+// It backs the namespaced storage disgm gives to plugins and embedders. The implementing
+// types should provide the actual logic for persisting the data, whether it be in-memory,
+// in a file, or in a database.
+type KVStore interface {
+
+	// View runs fn in a read-only transaction.
+	//
+	// Parameters:
+	//   - fn: func(tx KVTx) error – The function reading the data.
+	//
+	// Returns:
+	//   - error: The error returned by fn, or an error of the store.
+	View(fn func(tx KVTx) error) error
+
+	// Update runs fn in a read-write transaction.
+	//
+	// The changes made by fn are committed atomically if it returns nil
+	// and discarded if it returns an error.
+	//
+	// Parameters:
+	//   - fn: func(tx KVTx) error – The function reading and modifying the data.
+	//
+	// Returns:
+	//   - error: The error returned by fn, or an error of the store.
+	Update(fn func(tx KVTx) error) error
+}
+
+// KVTx defines the operations available inside a KVStore transaction.
+type KVTx interface {
+
+	// Get retrieves the value of a key and whether it exists.
+	Get(key string) (value []byte, ok bool, err error)
+
+	// Set stores the value of a key.
+	Set(key string, value []byte) error
+
+	// Delete removes a key. Deleting a missing key is not an error.
+	Delete(key string) error
+
+	// List retrieves the keys starting with prefix in ascending order.
+	List(prefix string) (keys []string, err error)
+}
+
+// MemoryKVStore is a KVStore keeping its data in memory.
+//
+// It is the default store of disgm and loses its data when the process exits.
+type MemoryKVStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+var _ KVStore = (*MemoryKVStore)(nil)
+
+// NewMemoryKVStore creates an empty in-memory key-value store.
+func NewMemoryKVStore() *MemoryKVStore {
+	return &MemoryKVStore{data: make(map[string][]byte)}
+}
+
+// View runs fn in a read-only transaction.
+func (m *MemoryKVStore) View(fn func(tx KVTx) error) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return fn(&memoryTx{store: m, readOnly: true})
+}
+
+// Update runs fn in a read-write transaction and commits its changes if it succeeds.
+func (m *MemoryKVStore) Update(fn func(tx KVTx) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := &memoryTx{store: m, writes: make(map[string][]byte)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	for key, value := range tx.writes {
+		if value == nil {
+			delete(m.data, key)
+		} else {
+			m.data[key] = value
+		}
+	}
+	return nil
+}
+
+// memoryTx is a transaction of a MemoryKVStore. Writes are buffered until the transaction commits,
+// with deleted keys recorded as nil values.
+type memoryTx struct {
+	store    *MemoryKVStore
+	readOnly bool
+	writes   map[string][]byte
+}
+
+func (tx *memoryTx) Get(key string) ([]byte, bool, error) {
+	value, ok := tx.writes[key]
+	if !ok {
+		value, ok = tx.store.data[key]
+	}
+	if !ok || value == nil {
+		return nil, false, nil
+	}
+	return slices.Clone(value), true, nil
+}
+
+func (tx *memoryTx) Set(key string, value []byte) error {
+	if tx.readOnly {
+		return ErrReadOnly
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	tx.writes[key] = slices.Clone(value)
+	return nil
+}
+
+func (tx *memoryTx) Delete(key string) error {
+	if tx.readOnly {
+		return ErrReadOnly
+	}
+	tx.writes[key] = nil
+	return nil
+}
+
+func (tx *memoryTx) List(prefix string) ([]string, error) {
+	var keys []string
+	for key := range tx.store.data {
+		if _, written := tx.writes[key]; !written && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	for key, value := range tx.writes {
+		if value != nil && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
@@ -0,0 +1,48 @@
+package store
+
+// Scope restricts which channels a single token may access.
+//
+// Tokens without a scope, or with a scope listing no channels, are unrestricted.
+// Channels listed in WriteChannels are readable as well, so a read-only token
+// only needs ReadChannels.
+type Scope struct {
+	ReadChannels  []string `json:"read_channels,omitempty"`  // Channel IDs the token may read
+	WriteChannels []string `json:"write_channels,omitempty"` // Channel IDs the token may read and modify
+}
+
+// Restricted reports whether the scope lists any channels, and so restricts its token.
+func (s *Scope) Restricted() bool {
+	return s != nil && (len(s.ReadChannels) > 0 || len(s.WriteChannels) > 0)
+}
+
+// ScopeStore defines an interface for loading token scopes.
+//
+// The implementing types should provide the actual logic for loading the scopes,
+// whether it be in-memory, in a file, or in a database.
+type ScopeStore interface {
+
+	// LoadScopes retrieves the scopes of restricted tokens.
+	//
+	// Returns:
+	//   - scopes: map[string]Scope – A map where keys are tokens and
+	//     values are the scopes they are restricted to.
+	//   - error: An error, if any occurs during the loading process.
+	//     It should return nil if the loading is successful.
+	LoadScopes() (scopes map[string]Scope, err error)
+}
+
+// ActingUserStore defines an interface for loading the acting users of tokens.
+//
+// The acting user is the dashboard user a token acts for. It is appended to the Discord
+// audit-log reasons and recorded in the API audit log, and does not restrict the token.
+type ActingUserStore interface {
+
+	// LoadActingUsers retrieves the acting users of tokens.
+	//
+	// Returns:
+	//   - users: map[string]string – A map where keys are tokens and
+	//     values are the IDs of the users they act for.
+	//   - error: An error, if any occurs during the loading process.
+	//     It should return nil if the loading is successful.
+	LoadActingUsers() (users map[string]string, err error)
+}
@@ -0,0 +1,73 @@
+package store
+
+// Tenant describes the customer a token belongs to, for operators that sell disgm in plans.
+//
+// Tenants are resolved per token by a TenantResolver. Their limits are enforced by disgm and
+// their feature flags decide which API modules the plan includes.
+type Tenant interface {
+
+	// ID returns the ID of the tenant. Rate limits and quotas are counted per ID, so tokens of
+	// the same tenant share them.
+	ID() string
+
+	// Plan returns the name of the plan of the tenant.
+	Plan() string
+
+	// Limits returns the limits of the plan of the tenant.
+	Limits() TenantLimits
+
+	// Feature reports whether the plan of the tenant includes a feature, such as an API module.
+	//
+	// Returns:
+	//   - enabled: bool – Whether the feature is included.
+	//   - ok: bool – Whether the plan decides on the feature. If not, the settings of disgm apply.
+	Feature(name string) (enabled bool, ok bool)
+}
+
+// TenantLimits are the limits of the plan of a tenant. Zero values mean no limit.
+type TenantLimits struct {
+	RequestsPerMinute int // Requests the tokens of the tenant may send per minute
+	RequestsPerDay    int // Requests the tokens of the tenant may send per UTC day
+	ChatMessages      int // Chat messages a WebSocket client of the tenant may send per 10 seconds, instead of the ChatRateLimit option
+}
+
+// TenantResolver defines an interface for resolving the tenant of a token.
+//
+// The implementing types should provide the actual lookup, e.g. in the database of a billing
+// system. It is called for every request, so it should cache its results.
+type TenantResolver interface {
+
+	// ResolveTenant retrieves the tenant of a token.
+	//
+	// Parameters:
+	//   - token: string – The token of the request.
+	//   - guildID: string – The ID of the guild the token belongs to.
+	//
+	// Returns:
+	//   - Tenant: The tenant of the token, or nil for tokens without a tenant, which are not limited.
+	//   - error: An error, if any occurs during the lookup.
+	ResolveTenant(token, guildID string) (Tenant, error)
+}
+
+// StaticTenant is a Tenant with fixed values, for resolvers that read plans from configuration.
+type StaticTenant struct {
+	TenantID     string          // ID of the tenant
+	PlanName     string          // Name of the plan
+	TenantLimits TenantLimits    // Limits of the plan
+	Features     map[string]bool // Features the plan includes or excludes; features missing from the map are not decided
+}
+
+// ID returns the ID of the tenant.
+func (t *StaticTenant) ID() string { return t.TenantID }
+
+// Plan returns the name of the plan of the tenant.
+func (t *StaticTenant) Plan() string { return t.PlanName }
+
+// Limits returns the limits of the plan of the tenant.
+func (t *StaticTenant) Limits() TenantLimits { return t.TenantLimits }
+
+// Feature reports whether the plan of the tenant includes a feature.
+func (t *StaticTenant) Feature(name string) (bool, bool) {
+	enabled, ok := t.Features[name]
+	return enabled, ok
+}
@@ -0,0 +1,177 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/contrib/websocket"
+)
+
+// Subscription is the payload of the subscribe op, which limits the events a client receives.
+//
+// Both lists accept exact values and glob patterns with "*" and "?", e.g. "MESSAGE_*" or
+// "GUILD_ROLE_*". An empty list matches everything, and events without a channel pass the
+// channel filter. Thread events match the thread and its parent channel, so subscribing to a
+// channel includes the threads created in it. THREAD_LIST_SYNC matches the channels it syncs.
+// Subscribing again replaces the previous subscription.
+//
+// Example:
+//
+//	{"op": "subscribe", "data": {"events": ["MESSAGE_*"], "channels": ["123", "456"]}}
+type Subscription struct {
+	Events   []string `json:"events"`   // Event names or patterns
+	Channels []string `json:"channels"` // Channel IDs or patterns
+}
+
+// globMatcher matches strings against a set of glob patterns. Exact values and trailing-star
+// prefixes, the common cases, are checked without pattern matching.
+type globMatcher struct {
+	exact    map[string]bool
+	prefixes []string
+	patterns []string
+}
+
+// compileGlobs compiles patterns into a matcher. It returns nil, which matches everything,
+// for an empty list or one containing "*".
+func compileGlobs(patterns []string) (*globMatcher, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	m := &globMatcher{exact: make(map[string]bool)}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q", p)
+		}
+
+		switch body, trailing := strings.CutSuffix(p, "*"); {
+		case p == "*":
+			return nil, nil
+		case !strings.ContainsAny(p, "*?[\\"):
+			m.exact[p] = true
+		case trailing && !strings.ContainsAny(body, "*?[\\"):
+			m.prefixes = append(m.prefixes, body)
+		default:
+			m.patterns = append(m.patterns, p)
+		}
+	}
+	return m, nil
+}
+
+// match reports whether s matches one of the patterns.
+func (m *globMatcher) match(s string) bool {
+	if m == nil || m.exact[s] {
+		return true
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	for _, p := range m.patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// subscription is the compiled subscription of a client.
+type subscription struct {
+	events   *globMatcher
+	channels *globMatcher
+}
+
+// wants reports whether an event passes the subscription: its name matches, and it has no channels
+// or one of them matches. A nil subscription receives everything.
+func (sub *subscription) wants(name string, channelIDs []string) bool {
+	if sub == nil {
+		return true
+	}
+	if !sub.events.match(name) {
+		return false
+	}
+	if len(channelIDs) == 0 || sub.channels == nil {
+		return true
+	}
+	for _, id := range channelIDs {
+		if sub.channels.match(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventChannelIDs returns the channels of an event, if its data has any: the channel_id of most
+// events, the thread and its parent for thread events, and the synced channels for THREAD_LIST_SYNC.
+func eventChannelIDs(name string, data interface{}) []string {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var ids []string
+	switch name {
+	case "THREAD_CREATE", "THREAD_UPDATE", "THREAD_DELETE", "THREAD_MEMBERS_UPDATE":
+		for _, key := range []string{"id", "parent_id"} {
+			if id, _ := m[key].(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	case "THREAD_LIST_SYNC":
+		channels, _ := m["channel_ids"].([]interface{})
+		for _, v := range channels {
+			if id, _ := v.(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	default:
+		if id, _ := m["channel_id"].(string); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// addThreadParent adds the parent channel of the thread, which Discord leaves out, to the data of a
+// THREAD_MEMBERS_UPDATE event, if the thread is in the state.
+func addThreadParent(s *discordgo.Session, data map[string]interface{}) {
+	id, _ := data["id"].(string)
+	if thread, err := s.State.Channel(id); err == nil && thread.ParentID != "" {
+		data["parent_id"] = thread.ParentID
+	}
+}
+
+// handleSubscribe compiles and stores the subscription of a client and confirms it with a
+// SUBSCRIBED event, or reports invalid patterns with SUBSCRIBE_ERROR.
+func handleSubscribe(conn *websocket.Conn, raw json.RawMessage) {
+	var req Subscription
+	if err := json.Unmarshal(raw, &req); err != nil {
+		writeEvent(conn, "SUBSCRIBE_ERROR", map[string]string{"error": "invalid subscription: " + err.Error()})
+		return
+	}
+	for i, name := range req.Events {
+		req.Events[i] = strings.ToUpper(name)
+	}
+
+	events, err := compileGlobs(req.Events)
+	if err == nil {
+		var channels *globMatcher
+		if channels, err = compileGlobs(req.Channels); err == nil {
+			clientsMu.Lock()
+			if info, ok := clientInfo[conn]; ok {
+				info.subscription = &subscription{events: events, channels: channels}
+			}
+			clientsMu.Unlock()
+		}
+	}
+	if err != nil {
+		writeEvent(conn, "SUBSCRIBE_ERROR", map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeEvent(conn, "SUBSCRIBED", req)
+}
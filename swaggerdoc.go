@@ -0,0 +1,33 @@
+package disgm
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/docs"
+)
+
+// swaggerDoc serves the swagger doc with the host the API is reached at, so the "try it out"
+// button of the swagger UI calls this server rather than a host fixed when the doc was generated.
+//
+// The host, scheme and base path are taken from the PublicURL option if it is set, e.g. for a
+// server behind a proxy on another host or below a path, and otherwise from each request, which
+// reaches the server at the address it is bound to or the proxy forwarding to it. The limit
+// parameters of the list endpoints are documented with the sizes of the Limits option.
+func swaggerDoc(publicURL string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		spec := *docs.SwaggerInfo // Copied, so concurrent requests do not share the host.
+		if u, err := url.Parse(publicURL); publicURL != "" && err == nil {
+			spec.Host = u.Host
+			spec.Schemes = []string{u.Scheme}
+			spec.BasePath = strings.TrimSuffix(u.Path, "/")
+		} else {
+			spec.Host = c.Hostname()
+			spec.Schemes = []string{c.Protocol()}
+		}
+
+		c.Type("json")
+		return c.SendString(documentListLimits(spec.ReadDoc()))
+	}
+}
@@ -0,0 +1,545 @@
+package disgm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Built-in actions a scheduled task can run.
+const (
+	TaskSendMessage  = "send_message"  // Posts a rendered template to a channel.
+	TaskPurge        = "purge"         // Bulk deletes recent messages of a channel.
+	TaskSyncCommands = "sync_commands" // Overwrites the guild application commands with the task's definitions.
+	TaskBackup       = "backup"        // Stores a structure snapshot of the guild, see the snapshots endpoints.
+	TaskDigest       = "digest"        // Posts the digest configured in the guild settings.
+)
+
+// maxPurgeAge is the age limit of messages Discord allows to bulk delete.
+const maxPurgeAge = 14 * 24 * time.Hour
+
+// Task runs a built-in action on a schedule. Tasks are persisted in the key-value store,
+// so they are rescheduled when disgm restarts.
+type Task struct {
+	ID        string     `json:"id"`                   // Unique ID of the task
+	GuildID   string     `json:"guild_id"`             // Guild the task belongs to
+	Name      string     `json:"name"`                 // Name of the task
+	Schedule  string     `json:"schedule"`             // Cron expression in UTC, a macro such as "@daily", or "@every 6h"
+	Action    string     `json:"action"`               // One of the built-in actions, e.g. TaskSendMessage
+	Params    TaskParams `json:"params"`               // Parameters of the action
+	Paused    bool       `json:"paused"`               // Whether the task is skipped until resumed
+	NextRun   *time.Time `json:"next_run,omitempty"`   // When the task runs next
+	LastRun   *time.Time `json:"last_run,omitempty"`   // When the task last ran
+	LastError string     `json:"last_error,omitempty"` // Error of the last run, if it failed
+
+	schedule *cronSchedule // Parsed schedule
+	tmpl     *template.Template
+	timer    *time.Timer // Timer of the next run
+}
+
+// TaskParams are the parameters of the built-in task actions.
+type TaskParams struct {
+	ChannelID  string                          `json:"channel_id,omitempty"`                          // Channel of send_message and purge
+	Template   string                          `json:"template,omitempty"`                            // Go text/template of send_message, executed with .Now and .GuildID
+	Limit      int                             `json:"limit,omitempty"`                               // Messages inspected by purge, 1-100, defaults to 100
+	KeepPinned bool                            `json:"keep_pinned,omitempty"`                         // Whether purge keeps pinned messages
+	Commands   []*discordgo.ApplicationCommand `json:"commands,omitempty" swaggertype:"array,object"` // Command definitions of sync_commands
+}
+
+// A registry of scheduled tasks keyed by ID, with the storage they are persisted in.
+var tasks = struct {
+	sync.Mutex
+	storage *Storage
+	byID    map[string]*Task
+}{byID: make(map[string]*Task)}
+
+// prepare validates a task and parses its schedule and template.
+func (t *Task) prepare() error {
+	var err error
+	if t.schedule, err = parseSchedule(t.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	switch t.Action {
+	case TaskSendMessage:
+		if t.Params.ChannelID == "" || t.Params.Template == "" {
+			return fmt.Errorf("send_message needs channel_id and template")
+		}
+		if t.tmpl, err = template.New("task").Parse(t.Params.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	case TaskPurge:
+		if t.Params.ChannelID == "" {
+			return fmt.Errorf("purge needs channel_id")
+		}
+		if t.Params.Limit == 0 {
+			t.Params.Limit = 100
+		}
+		if t.Params.Limit < 1 || t.Params.Limit > 100 {
+			return fmt.Errorf("limit must be between 1 and 100")
+		}
+	case TaskSyncCommands:
+		if t.Params.Commands == nil {
+			return fmt.Errorf("sync_commands needs commands")
+		}
+	case TaskBackup, TaskDigest:
+	default:
+		return fmt.Errorf("unknown action %q", t.Action)
+	}
+	return nil
+}
+
+// persistTask saves a task in the storage. The caller must hold the tasks lock.
+func persistTask(t *Task) {
+	data, err := json.Marshal(t)
+	if err == nil {
+		err = tasks.storage.Set(t.GuildID+"/"+t.ID, data)
+	}
+	if err != nil {
+		log.Printf("Failed to persist task %s: %v", t.ID, err)
+	}
+}
+
+// scheduleTask arms the timer of the next run of a task. The caller must hold the tasks lock.
+func scheduleTask(s *discordgo.Session, t *Task) {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	next := t.schedule.next(time.Now())
+	if next.IsZero() {
+		t.NextRun = nil
+		return
+	}
+	t.NextRun = &next
+	t.timer = time.AfterFunc(time.Until(next), func() {
+		tasks.Lock()
+		current := tasks.byID[t.ID] == t
+		paused := t.Paused
+		if current {
+			scheduleTask(s, t)
+		}
+		tasks.Unlock()
+
+		if current && !paused {
+			runTask(s, t)
+		}
+	})
+}
+
+// runTask runs the action of a task and records the result.
+func runTask(s *discordgo.Session, t *Task) {
+	tasks.Lock()
+	action, params, guildID, tmpl := t.Action, t.Params, t.GuildID, t.tmpl
+	tasks.Unlock()
+
+	err := runTaskAction(s, guildID, action, params, tmpl)
+
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	now := time.Now()
+	t.LastRun = &now
+	t.LastError = ""
+	if err != nil {
+		t.LastError = err.Error()
+	}
+	if tasks.byID[t.ID] == t {
+		persistTask(t)
+	}
+	EventCall(guildID, "TASK_RUN", t)
+}
+
+// runTaskAction performs a built-in task action.
+func runTaskAction(s *discordgo.Session, guildID, action string, params TaskParams, tmpl *template.Template) error {
+	switch action {
+	case TaskSendMessage:
+		var content strings.Builder
+		if err := tmpl.Execute(&content, map[string]interface{}{"Now": time.Now().UTC(), "GuildID": guildID}); err != nil {
+			return err
+		}
+		_, err := s.ChannelMessageSend(params.ChannelID, content.String())
+		return err
+
+	case TaskPurge:
+		messages, err := s.ChannelMessages(params.ChannelID, params.Limit, "", "", "")
+		if err != nil {
+			return err
+		}
+		var ids []string
+		for _, m := range messages {
+			if (params.KeepPinned && m.Pinned) || time.Since(m.Timestamp) >= maxPurgeAge {
+				continue
+			}
+			ids = append(ids, m.ID)
+		}
+		switch len(ids) {
+		case 0:
+			return nil
+		case 1:
+			return s.ChannelMessageDelete(params.ChannelID, ids[0])
+		default:
+			return s.ChannelMessagesBulkDelete(params.ChannelID, ids)
+		}
+
+	case TaskSyncCommands:
+		user, err := s.User("@me")
+		if err != nil {
+			return err
+		}
+		_, err = s.ApplicationCommandBulkOverwrite(user.ID, guildID, params.Commands)
+		return err
+
+	case TaskBackup:
+		snap, err := takeSnapshot(s, guildID)
+		if err != nil {
+			return err
+		}
+		if snap.ID, err = randomToken(8); err != nil {
+			return err
+		}
+		return storeSnapshot(guildID, snap)
+
+	case TaskDigest:
+		return postDigest(s, guildID)
+	}
+	return fmt.Errorf("unknown action %q", action)
+}
+
+// loadTasks restores the persisted tasks and schedules them.
+func loadTasks(s *discordgo.Session, storage *Storage) {
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	tasks.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load tasks: %v", err)
+		return
+	}
+	for _, key := range keys {
+		data, ok, err := storage.Get(key)
+		if err != nil || !ok {
+			continue
+		}
+
+		var t Task
+		if err := json.Unmarshal(data, &t); err != nil {
+			log.Printf("Failed to load task %s: %v", key, err)
+			continue
+		}
+		if err := t.prepare(); err != nil {
+			log.Printf("Failed to load task %s: %v", key, err)
+			continue
+		}
+		tasks.byID[t.ID] = &t
+		scheduleTask(s, &t)
+	}
+}
+
+// taskChannelAllowed reports whether the token of the request may use the channel of a task,
+// and whether the channel is in the guild.
+func taskChannelAllowed(c *fiber.Ctx, s *discordgo.Session, t *Task) (bool, string) {
+	if t.Params.ChannelID == "" {
+		return true, ""
+	}
+	if !channelAllowed(c, t.Params.ChannelID, true) {
+		return false, ""
+	}
+	channel, err := cachedChannel(s, t.Params.ChannelID)
+	if err != nil || channel.GuildID != t.GuildID {
+		return false, "channel not found in guild"
+	}
+	return true, ""
+}
+
+// guildTask returns the task with the given ID if it belongs to the guild of the request.
+// The caller must hold the tasks lock.
+func guildTask(c *fiber.Ctx) (*Task, bool) {
+	t, ok := tasks.byID[c.Params("taskid")]
+	if !ok || t.GuildID != c.Locals("ID").(string) {
+		return nil, false
+	}
+	return t, true
+}
+
+// GetGuildTasks retrieves the scheduled tasks of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - It returns the tasks as a JSON array, ordered by name.
+// @Summary		Get Guild Tasks
+// @Description	Retrieve the scheduled tasks of the guild.
+// @Tags			Tasks
+// @Success		200	{array}	Task
+// @Router			/api/guild/tasks [get]
+func GetGuildTasks(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	list := []*Task{}
+	for _, t := range tasks.byID {
+		if t.GuildID == guildID {
+			list = append(list, t)
+		}
+	}
+	slices.SortFunc(list, func(a, b *Task) int { return strings.Compare(a.Name, b.Name) })
+
+	return c.JSON(list)
+}
+
+// GetGuildTask retrieves a scheduled task of the guild.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - taskid: The ID of the task.
+//
+// Returns:
+//   - On success, it returns the task as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such task.
+// @Summary		Get Guild Task
+// @Description	Retrieve a scheduled task and the result of its last run.
+// @Tags			Tasks
+// @Param			taskid	path		string	true	"Task ID"
+// @Success		200		{object}	Task
+// @Failure		404		{object}	error
+// @Router			/api/guild/tasks/{taskid} [get]
+func GetGuildTask(c *fiber.Ctx, s *discordgo.Session) error {
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	t, ok := guildTask(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Task not found")
+	}
+
+	return c.JSON(t)
+}
+
+// CreateGuildTask schedules a built-in action.
+//
+// This function validates the schedule and the parameters of the action, persists the task and
+// arms its first run. Schedules are five-field cron expressions evaluated in UTC, the macros
+// "@hourly", "@daily", "@weekly" and "@monthly", or intervals such as "@every 6h".
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A JSON object with the fields "name", "schedule", "action", "params" and "paused".
+//
+// Returns:
+//   - On success, it returns the task as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the task is invalid,
+//     or HTTP status 403 (Forbidden) if its channel is outside the token scope.
+// @Summary		Create Guild Task
+// @Description	Schedule a built-in action: send_message, purge, sync_commands, backup or digest.
+// @Tags			Tasks
+// @Param			body	body		Task	true	"Task"
+// @Success		201		{object}	Task
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Router			/api/guild/tasks [post]
+func CreateGuildTask(c *fiber.Ctx, s *discordgo.Session) error {
+	var t Task
+	if err := c.BodyParser(&t); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	t.GuildID = c.Locals("ID").(string)
+	t.NextRun, t.LastRun, t.LastError = nil, nil, ""
+
+	if err := t.prepare(); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid task: " + err.Error())
+	}
+	if ok, reason := taskChannelAllowed(c, s, &t); !ok {
+		if reason != "" {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid task: " + reason)
+		}
+		return channelForbidden(c)
+	}
+
+	id, err := randomToken(8)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create task: " + err.Error())
+	}
+	t.ID = id
+
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	tasks.byID[t.ID] = &t
+	scheduleTask(s, &t)
+	persistTask(&t)
+
+	return c.Status(fiber.StatusCreated).JSON(&t)
+}
+
+// UpdateGuildTask changes a scheduled task.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - taskid: The ID of the task.
+//
+// Request Body:
+//   - A JSON object with the fields to change; "params" replaces the parameters as a whole.
+//
+// Returns:
+//   - On success, it returns the updated task as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such task,
+//     HTTP status 400 (Bad Request) if the task is invalid, or HTTP status 403 (Forbidden)
+//     if its channel is outside the token scope.
+// @Summary		Update Guild Task
+// @Description	Change the schedule, action, parameters or paused state of a task.
+// @Tags			Tasks
+// @Param			taskid	path		string	true	"Task ID"
+// @Param			body	body		Task	true	"Task"
+// @Success		200		{object}	Task
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		404		{object}	error
+// @Router			/api/guild/tasks/{taskid} [patch]
+func UpdateGuildTask(c *fiber.Ctx, s *discordgo.Session) error {
+	tasks.Lock()
+	current, ok := guildTask(c)
+	var t Task
+	if ok {
+		t = Task{Name: current.Name, Schedule: current.Schedule, Action: current.Action, Paused: current.Paused}
+	}
+	tasks.Unlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Task not found")
+	}
+
+	var body struct {
+		Params *TaskParams `json:"params"`
+	}
+	if err := c.BodyParser(&t); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if err := c.BodyParser(&body); err == nil && body.Params == nil {
+		t.Params = current.Params // Keeps the parameters if the body does not replace them.
+	}
+
+	t.ID, t.GuildID = current.ID, current.GuildID
+	t.LastRun, t.LastError = current.LastRun, current.LastError
+
+	if err := t.prepare(); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid task: " + err.Error())
+	}
+	if ok, reason := taskChannelAllowed(c, s, &t); !ok {
+		if reason != "" {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid task: " + reason)
+		}
+		return channelForbidden(c)
+	}
+
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	if current.timer != nil {
+		current.timer.Stop()
+	}
+	tasks.byID[t.ID] = &t
+	scheduleTask(s, &t)
+	persistTask(&t)
+
+	return c.JSON(&t)
+}
+
+// RunGuildTask runs a task immediately, independent of its schedule.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - taskid: The ID of the task.
+//
+// Returns:
+//   - On success, it returns the task with the result of the run as JSON.
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such task.
+// @Summary		Run Guild Task
+// @Description	Run a task immediately and return the result.
+// @Tags			Tasks
+// @Param			taskid	path		string	true	"Task ID"
+// @Success		200		{object}	Task
+// @Failure		404		{object}	error
+// @Router			/api/guild/tasks/{taskid}/run [post]
+func RunGuildTask(c *fiber.Ctx, s *discordgo.Session) error {
+	tasks.Lock()
+	t, ok := guildTask(c)
+	tasks.Unlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Task not found")
+	}
+
+	runTask(s, t)
+
+	tasks.Lock()
+	defer tasks.Unlock()
+	return c.JSON(t)
+}
+
+// DeleteGuildTask removes a scheduled task.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - taskid: The ID of the task.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 404 (Not Found) if the guild has no such task,
+//     or HTTP status 500 if it cannot be removed from the storage.
+// @Summary		Delete Guild Task
+// @Description	Remove a scheduled task.
+// @Tags			Tasks
+// @Param			taskid	path	string	true	"Task ID"
+// @Success		204
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/tasks/{taskid} [delete]
+func DeleteGuildTask(c *fiber.Ctx, s *discordgo.Session) error {
+	tasks.Lock()
+	defer tasks.Unlock()
+
+	t, ok := guildTask(c)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).SendString("Task not found")
+	}
+
+	if err := tasks.storage.Delete(t.GuildID + "/" + t.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete task: " + err.Error())
+	}
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	delete(tasks.byID, t.ID)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
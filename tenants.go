@@ -0,0 +1,130 @@
+package disgm
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/store"
+)
+
+const (
+	QuotaLimitHeader     = "X-Quota-Limit"     // Requests the tenant of the token may send per UTC day.
+	QuotaRemainingHeader = "X-Quota-Remaining" // Requests left in the daily quota of the tenant.
+)
+
+// tenantWindow counts the requests of a tenant in a fixed window.
+type tenantWindow struct {
+	start int64 // Start of the window, in units of the window since the Unix epoch
+	count int
+}
+
+// The requests of each tenant in the current minute and UTC day, keyed by tenant ID.
+var tenantUsage = struct {
+	sync.Mutex
+	minutes map[string]*tenantWindow
+	days    map[string]*tenantWindow
+}{
+	minutes: make(map[string]*tenantWindow),
+	days:    make(map[string]*tenantWindow),
+}
+
+// resolveTenant stores the tenant of a token in the context, if the options have a resolver.
+func resolveTenant(disgm *Disgm, c *fiber.Ctx, token string) error {
+	if disgm.opt.TenantResolver == nil {
+		return nil
+	}
+	guildID, _ := c.Locals("ID").(string)
+	tenant, err := disgm.opt.TenantResolver.ResolveTenant(token, guildID)
+	if err != nil {
+		return err
+	}
+	if tenant != nil {
+		c.Locals("Tenant", tenant)
+	}
+	return nil
+}
+
+// requestTenant returns the tenant of a request or WebSocket connection, or nil.
+func requestTenant(local interface{}) store.Tenant {
+	tenant, _ := local.(store.Tenant)
+	return tenant
+}
+
+// usedRequests returns the requests of a tenant in the window starting at start.
+// The caller must hold the lock of tenantUsage.
+func usedRequests(windows map[string]*tenantWindow, id string, start int64) int {
+	if w, ok := windows[id]; ok && w.start == start {
+		return w.count
+	}
+	return 0
+}
+
+// countRequest counts a request of a tenant in the window starting at start, replacing a past window.
+// The caller must hold the lock of tenantUsage.
+func countRequest(windows map[string]*tenantWindow, id string, start int64) int {
+	w, ok := windows[id]
+	if !ok || w.start != start {
+		w = &tenantWindow{start: start}
+		windows[id] = w
+	}
+	w.count++
+	return w.count
+}
+
+// TenantMiddleware enforces the rate limit and daily quota of the tenant of the token.
+//
+// Requests of tokens without a tenant are not limited. The rate limit is counted per minute and
+// the quota per UTC day, both shared by all tokens of a tenant. Rejected requests do not count.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context holding the tenant under the key "Tenant".
+//
+// Returns:
+//   - The result of the next handler if the tenant is within its limits.
+//   - An HTTP status 429 (Too Many Requests) with a Retry-After header otherwise.
+func TenantMiddleware(c *fiber.Ctx) error {
+	tenant := requestTenant(c.Locals("Tenant"))
+	if tenant == nil {
+		return c.Next()
+	}
+	id, limits := tenant.ID(), tenant.Limits()
+	now := time.Now().Unix()
+	minute, day := now/60, now/86400
+
+	tenantUsage.Lock()
+	if limits.RequestsPerMinute > 0 && usedRequests(tenantUsage.minutes, id, minute) >= limits.RequestsPerMinute {
+		tenantUsage.Unlock()
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(60-now%60, 10))
+		return c.Status(fiber.StatusTooManyRequests).SendString("Rate limit of the " + tenant.Plan() + " plan exceeded")
+	}
+	if limits.RequestsPerDay > 0 && usedRequests(tenantUsage.days, id, day) >= limits.RequestsPerDay {
+		tenantUsage.Unlock()
+		c.Set(QuotaLimitHeader, strconv.Itoa(limits.RequestsPerDay))
+		c.Set(QuotaRemainingHeader, "0")
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(86400-now%86400, 10))
+		return c.Status(fiber.StatusTooManyRequests).SendString("Daily quota of the " + tenant.Plan() + " plan exceeded")
+	}
+
+	countRequest(tenantUsage.minutes, id, minute)
+	requests := countRequest(tenantUsage.days, id, day)
+	tenantUsage.Unlock()
+
+	if limits.RequestsPerDay > 0 {
+		c.Set(QuotaLimitHeader, strconv.Itoa(limits.RequestsPerDay))
+		c.Set(QuotaRemainingHeader, strconv.Itoa(limits.RequestsPerDay-requests))
+	}
+
+	return c.Next()
+}
+
+// planIncludes reports whether the plan of a tenant includes a feature. Features the plan does
+// not decide on, and requests without a tenant, are included.
+func planIncludes(tenant store.Tenant, feature string) bool {
+	if tenant == nil {
+		return true
+	}
+	enabled, ok := tenant.Feature(feature)
+	return !ok || enabled
+}
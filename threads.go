@@ -0,0 +1,160 @@
+package disgm
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+// ThreadsList is a list of threads with the thread members of the bot.
+type ThreadsList = models.ThreadsList
+
+// scopeThreads removes the threads outside the token's scope from a list, with their members.
+// Threads are allowed if their parent channel or the thread itself is in the scope.
+func scopeThreads(c *fiber.Ctx, list *discordgo.ThreadsList) {
+	list.Threads = slices.DeleteFunc(list.Threads, func(t *discordgo.Channel) bool {
+		return !channelAllowed(c, t.ParentID, false) && !channelAllowed(c, t.ID, false)
+	})
+	list.Members = slices.DeleteFunc(list.Members, func(m *discordgo.ThreadMember) bool {
+		return !slices.ContainsFunc(list.Threads, func(t *discordgo.Channel) bool { return t.ID == m.ID })
+	})
+}
+
+// GetGuildActiveThreads retrieves all active threads of the guild.
+//
+// This function mirrors Discord's list active guild threads endpoint: it returns the active
+// threads, public and private, with the thread member objects of the bot for the threads it
+// has joined. Tokens restricted to channels only see the threads of their channels.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the threads and members as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 500 and an error message if the threads cannot be retrieved.
+// @Summary		Get Active Threads
+// @Description	Retrieve all active threads of the guild with the bot's thread members.
+// @Tags			Threads
+// @Success		200	{object}	models.ThreadsList
+// @Failure		500	{object}	error
+// @Router			/api/guild/threads/active [get]
+func GetGuildActiveThreads(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	threads, err := s.GuildThreadsActive(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve active threads: " + err.Error())
+	}
+	scopeThreads(c, threads)
+
+	return c.JSON(threads)
+}
+
+// parseArchivedPage reads the before and limit query parameters of the archived thread endpoints.
+func parseArchivedPage(c *fiber.Ctx) (before *time.Time, limit int, err error) {
+	if v := c.Query("before"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid before: %w", err)
+		}
+		before = &t
+	}
+	return before, queryLimit(c, LimitArchivedThreads), nil
+}
+
+// listArchivedThreads serves a page of archived threads of a channel using the given lister.
+func listArchivedThreads(c *fiber.Ctx, list func(channelID string, before *time.Time, limit int) (*discordgo.ThreadsList, error)) error {
+	channelID := c.Params("channelid")
+
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
+	before, limit, err := parseArchivedPage(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	threads, err := list(channelID, before, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve archived threads: " + err.Error())
+	}
+
+	return c.JSON(threads)
+}
+
+// GetPublicArchivedThreads retrieves the archived public threads of a channel.
+//
+// This function returns the archived threads newest first, by archive time. Older threads are
+// paged with the before parameter, set to the archive timestamp of the last thread of the
+// previous page while has_more is true.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Query:
+//   - before: Only return threads archived before this RFC3339 time.
+//   - limit: The maximum number of threads to return, 1 to 100 (default 50) unless changed with the Limits option.
+//
+// Returns:
+//   - On success, it returns the threads, the bot's thread members and has_more as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if before is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token's scope,
+//     or HTTP status 500 (Internal Server Error) if the threads cannot be retrieved.
+// @Summary		Get Public Archived Threads
+// @Description	Retrieve a page of the archived public threads of a channel.
+// @Tags			Threads
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			before		query		string	false	"Threads archived before this RFC3339 time"
+// @Param			limit		query		int		false	"Maximum number of threads"	minimum(1)	maximum(100)	default(50)
+// @Success		200			{object}	models.ThreadsList
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/threads/archived/public [get]
+func GetPublicArchivedThreads(c *fiber.Ctx, s *discordgo.Session) error {
+	return listArchivedThreads(c, func(channelID string, before *time.Time, limit int) (*discordgo.ThreadsList, error) {
+		return s.ThreadsArchived(channelID, before, limit)
+	})
+}
+
+// GetPrivateArchivedThreads retrieves the archived private threads of a channel.
+//
+// This function pages like GetPublicArchivedThreads. Discord requires the bot to have the
+// Manage Threads permission in the channel to list private threads.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Query:
+//   - before: Only return threads archived before this RFC3339 time.
+//   - limit: The maximum number of threads to return, 1 to 100 (default 50) unless changed with the Limits option.
+//
+// Returns:
+//   - On success, it returns the threads, the bot's thread members and has_more as JSON.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if before is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token's scope,
+//     or HTTP status 500 (Internal Server Error) if the threads cannot be retrieved.
+// @Summary		Get Private Archived Threads
+// @Description	Retrieve a page of the archived private threads of a channel.
+// @Tags			Threads
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			before		query		string	false	"Threads archived before this RFC3339 time"
+// @Param			limit		query		int		false	"Maximum number of threads"	minimum(1)	maximum(100)	default(50)
+// @Success		200			{object}	models.ThreadsList
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/threads/archived/private [get]
+func GetPrivateArchivedThreads(c *fiber.Ctx, s *discordgo.Session) error {
+	return listArchivedThreads(c, func(channelID string, before *time.Time, limit int) (*discordgo.ThreadsList, error) {
+		return s.ThreadsPrivateArchived(channelID, before, limit)
+	})
+}
@@ -0,0 +1,81 @@
+package disgm
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// throttleWindow counts the events of one type in a guild during the current second.
+type throttleWindow struct {
+	second int64
+	events int
+}
+
+// The throttles of the forwarded events, set from the EventThrottles option, with the events of
+// the current second and the events dropped since disgm started, keyed by guild ID and event.
+var eventThrottles = struct {
+	sync.Mutex
+	limits  map[string]int
+	windows map[string]*throttleWindow
+	dropped map[string]int
+}{
+	windows: make(map[string]*throttleWindow),
+	dropped: make(map[string]int),
+}
+
+// ThrottledEvents are the events of one type dropped in a guild by the EventThrottles option.
+type ThrottledEvents struct {
+	GuildID string `json:"guild_id"`
+	Event   string `json:"event"`   // Event type, e.g. TYPING_START
+	Limit   int    `json:"limit"`   // Events forwarded per second
+	Dropped int    `json:"dropped"` // Events dropped since disgm started
+}
+
+// throttled reports whether an event exceeds the throttle of its type in its guild, counting it
+// as dropped if so. Events without a throttle are never dropped.
+func throttled(guildID, name string) bool {
+	eventThrottles.Lock()
+	defer eventThrottles.Unlock()
+
+	limit, ok := eventThrottles.limits[name]
+	if !ok {
+		return false
+	}
+	key := guildID + "/" + name
+	second := time.Now().Unix()
+	w, ok := eventThrottles.windows[key]
+	if !ok {
+		w = &throttleWindow{}
+		eventThrottles.windows[key] = w
+	}
+	if w.second != second {
+		w.second, w.events = second, 0
+	}
+	if w.events >= limit {
+		eventThrottles.dropped[key]++
+		return true
+	}
+	w.events++
+	return false
+}
+
+// throttledEvents returns the events dropped by the throttles, most dropped first.
+func throttledEvents() []ThrottledEvents {
+	eventThrottles.Lock()
+	defer eventThrottles.Unlock()
+
+	throttled := make([]ThrottledEvents, 0, len(eventThrottles.dropped))
+	for key, dropped := range eventThrottles.dropped {
+		guildID, name, _ := strings.Cut(key, "/")
+		throttled = append(throttled, ThrottledEvents{GuildID: guildID, Event: name, Limit: eventThrottles.limits[name], Dropped: dropped})
+	}
+	slices.SortFunc(throttled, func(a, b ThrottledEvents) int {
+		if a.Dropped != b.Dropped {
+			return b.Dropped - a.Dropped
+		}
+		return strings.Compare(a.GuildID+" "+a.Event, b.GuildID+" "+b.Event)
+	})
+	return throttled
+}
@@ -0,0 +1,289 @@
+package disgm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/store"
+)
+
+// GuildToken is an additional token of a guild, issued for a single person or service, e.g. a
+// support agent who may only use the support channels.
+//
+// The token store holds one token per guild, which cannot be restricted without restricting
+// everyone using it. Issued tokens act like the guild token within their scope and are persisted
+// in the key-value store, which keeps a hash of each token instead of the token itself.
+type GuildToken struct {
+	ID         string       `json:"id"`                    // Unique ID of the token, used to revoke it
+	Name       string       `json:"name"`                  // Who or what the token is issued for
+	Token      string       `json:"token,omitempty"`       // The token itself, only returned when it is issued
+	Scope      *store.Scope `json:"scope,omitempty"`       // Channels the token is restricted to, unrestricted if unset
+	ActingUser string       `json:"acting_user,omitempty"` // Dashboard user the token acts for, recorded in audit-log reasons and the API audit log
+	CreatedAt  time.Time    `json:"created_at"`            // When the token was issued
+
+	guildID string   // Guild the token belongs to
+	hash    string   // Hash of the token, which is looked up for each request
+	lineage []string // Credentials the token was issued from, oldest first, ending with its own
+}
+
+// storedGuildToken is an issued token as persisted in the key-value store.
+type storedGuildToken struct {
+	GuildToken
+	GuildID string   `json:"guild_id"`
+	Hash    string   `json:"hash"`
+	Lineage []string `json:"lineage"`
+}
+
+// The issued tokens keyed by hash, with the storage they are persisted in, keyed by guild ID and token ID.
+var guildTokens = struct {
+	sync.Mutex
+	storage *Storage
+	byHash  map[string]*GuildToken
+}{byHash: make(map[string]*GuildToken)}
+
+// tokenHash returns the hash of a token under which it is stored.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issuedToken returns the issued token with the given value.
+func issuedToken(token string) (*GuildToken, bool) {
+	guildTokens.Lock()
+	defer guildTokens.Unlock()
+
+	t, ok := guildTokens.byHash[tokenHash(token)]
+	return t, ok
+}
+
+// loadGuildTokens restores the issued tokens from the storage.
+func loadGuildTokens(storage *Storage) {
+	guildTokens.Lock()
+	defer guildTokens.Unlock()
+
+	guildTokens.storage = storage
+
+	keys, err := storage.List("")
+	if err != nil {
+		log.Printf("Failed to load guild tokens: %v", err)
+		return
+	}
+	for _, key := range keys {
+		data, ok, err := storage.Get(key)
+		if err != nil || !ok {
+			continue
+		}
+
+		var stored storedGuildToken
+		if err := json.Unmarshal(data, &stored); err != nil {
+			log.Printf("Failed to load guild token %s: %v", key, err)
+			continue
+		}
+		t := stored.GuildToken
+		t.guildID, t.hash, t.lineage = stored.GuildID, stored.Hash, stored.Lineage
+		if len(t.lineage) == 0 {
+			t.lineage = []string{"guild", "token:" + t.ID} // Issued before lineages were recorded.
+		}
+		guildTokens.byHash[t.hash] = &t
+	}
+}
+
+// credentialLineage returns the credentials a request's token was issued from, oldest first,
+// ending with its own credential. Issued tokens carry the lineage of the token that issued them, so
+// whoever holds a token is known to control every token issued from it.
+func credentialLineage(c *fiber.Ctx) []string {
+	credential, _ := c.Locals("Credential").(string)
+	id, ok := strings.CutPrefix(credential, "token:")
+	if !ok {
+		return []string{credential}
+	}
+	guildID, _ := c.Locals("ID").(string)
+
+	guildTokens.Lock()
+	defer guildTokens.Unlock()
+
+	for _, t := range guildTokens.byHash {
+		if t.guildID == guildID && t.ID == id {
+			return slices.Clone(t.lineage)
+		}
+	}
+	return []string{credential}
+}
+
+// guildTokenList returns the issued tokens of a guild, oldest first. The caller must hold the lock.
+func guildTokenList(guildID string) []*GuildToken {
+	list := []*GuildToken{}
+	for _, t := range guildTokens.byHash {
+		if t.guildID == guildID {
+			list = append(list, t)
+		}
+	}
+	slices.SortFunc(list, func(a, b *GuildToken) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return list
+}
+
+// GetGuildTokens retrieves the tokens issued for the guild, without the tokens themselves.
+//
+// Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the tokens as a JSON array, oldest first.
+//   - On failure, it returns an HTTP status 403 (Forbidden) for restricted tokens.
+// @Summary		Get Guild Tokens
+// @Description	Retrieve the tokens issued for the guild, without the tokens themselves.
+// @Tags			Tokens
+// @Success		200	{array}		GuildToken
+// @Failure		403	{object}	error
+// @Router			/api/guild/tokens [get]
+func GetGuildTokens(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	guildTokens.Lock()
+	defer guildTokens.Unlock()
+
+	return c.JSON(guildTokenList(guildID))
+}
+
+// IssueGuildToken issues an additional token for the guild, optionally restricted to channels.
+//
+// The token is only returned in the response; store it, as it cannot be retrieved later. The
+// token cannot approve actions requested by the token issuing it or the tokens that one was issued
+// from, nor the other way around. Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A GuildToken object with the name and optionally the scope and acting user of the token.
+//
+// Returns:
+//   - On success, it returns the issued token as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) for restricted tokens,
+//     or HTTP status 500 (Internal Server Error) if the token cannot be generated or stored.
+// @Summary		Issue Guild Token
+// @Description	Issue an additional token for the guild, optionally restricted to channels.
+// @Tags			Tokens
+// @Param			body	body		GuildToken	true	"Token name, scope and acting user"
+// @Success		201		{object}	GuildToken
+// @Failure		400		{object}	error
+// @Failure		403		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/tokens [post]
+func IssueGuildToken(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var params GuildToken
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if strings.TrimSpace(params.Name) == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name is required")
+	}
+
+	id, err := randomToken(8)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to issue token: " + err.Error())
+	}
+	token, err := randomToken(32)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to issue token: " + err.Error())
+	}
+
+	t := &GuildToken{
+		ID:         id,
+		Name:       params.Name,
+		Scope:      params.Scope,
+		ActingUser: params.ActingUser,
+		CreatedAt:  time.Now().UTC(),
+		guildID:    guildID,
+		hash:       tokenHash(token),
+		lineage:    append(credentialLineage(c), "token:"+id),
+	}
+	data, err := json.Marshal(storedGuildToken{GuildToken: *t, GuildID: t.guildID, Hash: t.hash, Lineage: t.lineage})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to issue token: " + err.Error())
+	}
+
+	guildTokens.Lock()
+	defer guildTokens.Unlock()
+
+	if err := guildTokens.storage.Set(guildID+"/"+id, data); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to issue token: " + err.Error())
+	}
+	guildTokens.byHash[t.hash] = t
+
+	issued := *t
+	issued.Token = token
+	return c.Status(fiber.StatusCreated).JSON(&issued)
+}
+
+// RevokeGuildToken revokes a token issued for the guild.
+//
+// WebSocket connections opened with the token are closed when the tokens are revalidated next.
+// Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - tokenid: The ID of the issued token.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) for restricted tokens,
+//     HTTP status 404 (Not Found) if the guild has no such token,
+//     or HTTP status 500 (Internal Server Error) if it cannot be removed from the storage.
+// @Summary		Revoke Guild Token
+// @Description	Revoke a token issued for the guild.
+// @Tags			Tokens
+// @Param			tokenid	path	string	true	"Token ID"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/tokens/{tokenid} [delete]
+func RevokeGuildToken(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	guildTokens.Lock()
+	defer guildTokens.Unlock()
+
+	for hash, t := range guildTokens.byHash {
+		if t.guildID != guildID || t.ID != c.Params("tokenid") {
+			continue
+		}
+		if err := guildTokens.storage.Delete(guildID + "/" + t.ID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to revoke token: " + err.Error())
+		}
+		delete(guildTokens.byHash, hash)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	return c.Status(fiber.StatusNotFound).SendString("Token not found")
+}
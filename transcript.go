@@ -0,0 +1,280 @@
+package disgm
+
+import (
+	"html/template"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// mentionPattern matches user, role and channel mentions in message content.
+var mentionPattern = regexp.MustCompile(`<(@!?|@&|#)(\d+)>`)
+
+// Transcript is the history of a channel with its authors resolved.
+type Transcript struct {
+	GuildID     string                    `json:"guild_id"`
+	GuildName   string                    `json:"guild_name"`
+	ChannelID   string                    `json:"channel_id"`
+	ChannelName string                    `json:"channel_name"`
+	GeneratedAt time.Time                 `json:"generated_at"`
+	Users       map[string]TranscriptUser `json:"users"`    // Authors keyed by user ID
+	Messages    []TranscriptMessage       `json:"messages"` // Messages from oldest to newest
+}
+
+// TranscriptUser is an author of a transcript message.
+type TranscriptUser struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"` // Guild nickname, global name or username
+	AvatarURL   string `json:"avatar_url"`
+	Bot         bool   `json:"bot"`
+}
+
+// TranscriptMessage is a message of a transcript with its mentions resolved to names.
+type TranscriptMessage struct {
+	ID          string                         `json:"id"`
+	AuthorID    string                         `json:"author_id"`
+	Content     string                         `json:"content"`
+	Timestamp   time.Time                      `json:"timestamp"`
+	Edited      *time.Time                     `json:"edited,omitempty"`
+	Embeds      []*discordgo.MessageEmbed      `json:"embeds,omitempty" swaggertype:"array,object"`
+	Attachments []*discordgo.MessageAttachment `json:"attachments,omitempty" swaggertype:"array,object"`
+	Reference   *discordgo.MessageReference    `json:"reference,omitempty" swaggertype:"object"`
+	Author      TranscriptUser                 `json:"-"` // Used by the HTML template
+}
+
+// transcriptTemplate renders a transcript as a self-contained HTML page.
+var transcriptTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"image": func(a *discordgo.MessageAttachment) bool { return strings.HasPrefix(a.ContentType, "image/") },
+	"time":  func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>#{{.ChannelName}} – {{.GuildName}}</title>
+<style>
+body{margin:0;padding:24px;background:#313338;color:#dbdee1;font:15px/1.4 "gg sans","Helvetica Neue",Helvetica,Arial,sans-serif}
+header{border-bottom:1px solid #3f4147;margin-bottom:16px;padding-bottom:12px}
+header h1{margin:0;font-size:20px;color:#f2f3f5}
+header p{margin:4px 0 0;color:#949ba4;font-size:13px}
+.msg{display:flex;gap:12px;padding:6px 0}
+.avatar{width:40px;height:40px;border-radius:50%;flex-shrink:0}
+.name{font-weight:600;color:#f2f3f5}
+.bot{background:#5865f2;color:#fff;border-radius:3px;font-size:10px;padding:1px 4px;margin-left:4px}
+.meta{color:#949ba4;font-size:12px;margin-left:6px}
+.content{white-space:pre-wrap;word-wrap:break-word}
+.embed{border-left:4px solid #1e1f22;background:#2b2d31;border-radius:4px;padding:8px 12px;margin-top:4px;max-width:520px}
+.embed .title{font-weight:600;color:#00a8fc}
+.field{margin-top:4px}.field b{display:block;color:#f2f3f5}
+.attachment{display:block;margin-top:4px;color:#00a8fc}
+.attachment img{max-width:400px;max-height:300px;border-radius:4px}
+</style>
+</head>
+<body>
+<header>
+<h1>#{{.ChannelName}}</h1>
+<p>{{.GuildName}} · {{len .Messages}} messages · generated {{time .GeneratedAt}}</p>
+</header>
+{{range .Messages}}<div class="msg" id="m{{.ID}}">
+<img class="avatar" src="{{.Author.AvatarURL}}" alt="">
+<div>
+<div><span class="name" title="{{.Author.Username}}">{{.Author.DisplayName}}</span>{{if .Author.Bot}}<span class="bot">BOT</span>{{end}}<span class="meta">{{time .Timestamp}}{{if .Edited}} (edited){{end}}</span></div>
+{{if .Reference}}<div class="meta">↪ reply to <a href="#m{{.Reference.MessageID}}">message</a></div>{{end}}
+{{if .Content}}<div class="content">{{.Content}}</div>{{end}}
+{{range .Embeds}}<div class="embed">
+{{if .Title}}<div class="title">{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</div>{{end}}
+{{if .Description}}<div class="content">{{.Description}}</div>{{end}}
+{{range .Fields}}<div class="field"><b>{{.Name}}</b><span class="content">{{.Value}}</span></div>{{end}}
+{{if .Image}}<img src="{{.Image.URL}}" alt="" style="max-width:400px">{{end}}
+</div>{{end}}
+{{range .Attachments}}<a class="attachment" href="{{.URL}}">{{if image .}}<img src="{{.URL}}" alt="{{.Filename}}">{{else}}📎 {{.Filename}} ({{.Size}} bytes){{end}}</a>{{end}}
+</div>
+</div>
+{{end}}</body>
+</html>
+`))
+
+// transcriptUser resolves the author of a message, preferring the guild nickname from the state.
+func transcriptUser(s *discordgo.Session, guildID string, u *discordgo.User) TranscriptUser {
+	user := TranscriptUser{
+		ID:          u.ID,
+		Username:    u.Username,
+		DisplayName: u.Username,
+		AvatarURL:   u.AvatarURL("64"),
+		Bot:         u.Bot,
+	}
+	if u.GlobalName != "" {
+		user.DisplayName = u.GlobalName
+	}
+	if member, err := s.State.Member(guildID, u.ID); err == nil && member.Nick != "" {
+		user.DisplayName = member.Nick
+	}
+	return user
+}
+
+// resolveMentions replaces user, role and channel mentions with their names.
+func resolveMentions(s *discordgo.Session, guildID string, m *discordgo.Message, users map[string]TranscriptUser) string {
+	return mentionPattern.ReplaceAllStringFunc(m.Content, func(mention string) string {
+		parts := mentionPattern.FindStringSubmatch(mention)
+		id := parts[2]
+
+		switch parts[1] {
+		case "@&":
+			if role, err := s.State.Role(guildID, id); err == nil {
+				return "@" + role.Name
+			}
+		case "#":
+			if channel, err := s.State.Channel(id); err == nil {
+				return "#" + channel.Name
+			}
+		default:
+			if user, ok := users[id]; ok {
+				return "@" + user.DisplayName
+			}
+			for _, u := range m.Mentions {
+				if u.ID == id {
+					return "@" + transcriptUser(s, guildID, u).DisplayName
+				}
+			}
+		}
+		return mention
+	})
+}
+
+// GetChannelTranscript renders the history of a channel as a transcript.
+//
+// This function pages through the message history of the channel, newest first, until the limit
+// is reached, and returns the messages from oldest to newest with their authors, embeds and
+// attachments. Mentions of users, roles and channels are replaced with their names. The HTML format
+// is a self-contained page, suitable for archiving a ticket channel before it is closed.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - channelid: The ID of the channel.
+//   - format: "json" (default) or "html".
+//   - limit: The maximum number of messages, defaults to 1000 and is capped at 10000 unless changed with the Limits option.
+//
+// Returns:
+//   - On success, it returns the transcript as JSON or HTML.
+//   - On failure, it returns an HTTP status 400 (Bad Request) for invalid parameters,
+//     HTTP status 403 (Forbidden) if the channel is outside the token scope,
+//     HTTP status 404 (Not Found) if the channel is not in the guild,
+//     or HTTP status 500 if the history cannot be retrieved.
+// @Summary		Get Channel Transcript
+// @Description	Render the message history of a channel as a JSON or self-contained HTML transcript.
+// @Tags			Messages
+// @Produce		json,html
+// @Param			channelid	path		string	true	"Channel ID"
+// @Param			format		query		string	false	"Transcript format"	Enums(json, html)
+// @Param			limit		query		int		false	"Maximum number of messages"	minimum(1)	maximum(10000)	default(1000)
+// @Success		200			{object}	Transcript
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/transcript [get]
+func GetChannelTranscript(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	channelID := c.Params("channelid")
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "html" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid format: must be json or html")
+	}
+
+	sizes := listLimit(LimitTranscript)
+	limit := sizes.Default
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid limit")
+		}
+		limit = min(n, sizes.Max)
+	}
+
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
+	channel, err := cachedChannel(s, channelID)
+	if err != nil || channel.GuildID != guildID {
+		return c.Status(fiber.StatusNotFound).SendString("Channel not found")
+	}
+
+	transcript := Transcript{
+		GuildID:     guildID,
+		ChannelID:   channelID,
+		ChannelName: channel.Name,
+		GeneratedAt: time.Now(),
+		Users:       make(map[string]TranscriptUser),
+		Messages:    []TranscriptMessage{},
+	}
+	if guild, err := s.State.Guild(guildID); err == nil {
+		transcript.GuildName = guild.Name
+	} else if guild, err := s.Guild(guildID); err == nil {
+		transcript.GuildName = guild.Name
+	}
+
+	// Pages backwards through the history, 100 messages at a time.
+	var history []*discordgo.Message
+	beforeID := ""
+	for len(history) < limit {
+		page, err := s.ChannelMessages(channelID, min(100, limit-len(history)), beforeID, "", "")
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve messages: " + err.Error())
+		}
+		history = append(history, page...)
+		if len(page) < 100 {
+			break
+		}
+		beforeID = page[len(page)-1].ID
+	}
+	slices.Reverse(history)
+
+	for _, m := range history {
+		if m.Author == nil {
+			continue
+		}
+		author, ok := transcript.Users[m.Author.ID]
+		if !ok {
+			author = transcriptUser(s, guildID, m.Author)
+			transcript.Users[author.ID] = author
+		}
+	}
+
+	for _, m := range history {
+		if m.Author == nil {
+			continue
+		}
+		transcript.Messages = append(transcript.Messages, TranscriptMessage{
+			ID:          m.ID,
+			AuthorID:    m.Author.ID,
+			Content:     resolveMentions(s, guildID, m, transcript.Users),
+			Timestamp:   m.Timestamp,
+			Edited:      m.EditedTimestamp,
+			Embeds:      m.Embeds,
+			Attachments: m.Attachments,
+			Reference:   m.MessageReference,
+			Author:      transcript.Users[m.Author.ID],
+		})
+	}
+
+	if format == "html" {
+		var page strings.Builder
+		if err := transcriptTemplate.Execute(&page, transcript); err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to render transcript: " + err.Error())
+		}
+		c.Type("html", "utf-8")
+		return c.SendString(page.String())
+	}
+
+	return c.JSON(transcript)
+}
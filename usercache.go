@@ -0,0 +1,187 @@
+package disgm
+
+import (
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	maxResolveUsers    = 1000            // Maximum number of user IDs resolved per request.
+	memberChunkTimeout = 5 * time.Second // Time to wait for a requested guild members chunk.
+	memberRequestBatch = 100             // Maximum number of user IDs per guild members request.
+)
+
+// A cache of user objects referenced by bans, reactions and other lists, kept in the shared
+// cache, with the pending guild member requests waiting for their chunks by nonce.
+var userCache = struct {
+	sync.Mutex
+	ttl     time.Duration
+	waiters map[string]chan struct{}
+}{
+	waiters: make(map[string]chan struct{}),
+}
+
+// userSize estimates the memory of a cached user object.
+func userSize(u *discordgo.User) int64 {
+	return int64(unsafe.Sizeof(*u) + uintptr(len(u.ID)+len(u.Email)+len(u.Username)+len(u.Avatar)+
+		len(u.Locale)+len(u.Discriminator)+len(u.GlobalName)+len(u.Token)+len(u.Banner)))
+}
+
+// cacheUsers adds users to the cache.
+func cacheUsers(users ...*discordgo.User) {
+	userCache.Lock()
+	ttl := userCache.ttl
+	userCache.Unlock()
+
+	for _, u := range users {
+		if u != nil && u.ID != "" {
+			cacheSet(CacheUsers, u.ID, u, userSize(u), ttl)
+		}
+	}
+}
+
+// cachedUser returns a user from the cache if it has not expired.
+func cachedUser(id string) (*discordgo.User, bool) {
+	u, ok := cacheGet(CacheUsers, id)
+	if !ok {
+		return nil, false
+	}
+	return u.(*discordgo.User), true
+}
+
+// registerUserCacheHandlers registers the handler that caches the members of requested chunks
+// and wakes up the resolver waiting for them.
+func registerUserCacheHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, chunk *discordgo.GuildMembersChunk) {
+		users := make([]*discordgo.User, 0, len(chunk.Members))
+		for _, m := range chunk.Members {
+			users = append(users, m.User)
+		}
+		cacheUsers(users...)
+
+		if chunk.Nonce == "" || chunk.ChunkIndex != chunk.ChunkCount-1 {
+			return
+		}
+		userCache.Lock()
+		if done, ok := userCache.waiters[chunk.Nonce]; ok {
+			close(done)
+			delete(userCache.waiters, chunk.Nonce)
+		}
+		userCache.Unlock()
+	})
+}
+
+// requestMembers asks the gateway for the members with the given IDs and waits for the chunks.
+// It requires the guild members intent; without it the request times out and nothing is cached.
+func requestMembers(s *discordgo.Session, guildID string, ids []string) {
+	nonce, err := randomToken(8)
+	if err != nil {
+		return
+	}
+
+	done := make(chan struct{})
+	userCache.Lock()
+	userCache.waiters[nonce] = done
+	userCache.Unlock()
+
+	defer func() {
+		userCache.Lock()
+		delete(userCache.waiters, nonce)
+		userCache.Unlock()
+	}()
+
+	if err := s.RequestGuildMembersList(guildID, ids, 0, nonce, false); err != nil {
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(memberChunkTimeout):
+	}
+}
+
+// resolveUsers returns the users with the given IDs, avoiding a REST call per user.
+//
+// Users are taken from the cache and the state first. The remaining IDs are requested from the
+// gateway as guild members in batches of 100, and only users that are not members of the guild,
+// such as banned users, are fetched one by one from the Discord API. IDs that cannot be resolved
+// are missing from the result.
+func resolveUsers(s *discordgo.Session, guildID string, ids []string) map[string]*discordgo.User {
+	resolved := make(map[string]*discordgo.User, len(ids))
+
+	lookup := func() []string {
+		var missing []string
+		for _, id := range ids {
+			if _, ok := resolved[id]; ok {
+				continue
+			}
+			if u, ok := cachedUser(id); ok {
+				resolved[id] = u
+			} else if m, err := s.State.Member(guildID, id); err == nil && m.User != nil {
+				resolved[id] = m.User
+				cacheUsers(m.User)
+			} else {
+				missing = append(missing, id)
+			}
+		}
+		return missing
+	}
+
+	missing := lookup()
+	for i := 0; i < len(missing); i += memberRequestBatch {
+		requestMembers(s, guildID, missing[i:min(i+memberRequestBatch, len(missing))])
+	}
+	if len(missing) > 0 {
+		missing = lookup()
+	}
+
+	for _, id := range missing {
+		if u, err := s.User(id); err == nil {
+			resolved[id] = u
+			cacheUsers(u)
+		}
+	}
+	return resolved
+}
+
+// ResolveGuildUsers resolves user IDs to user objects in one request.
+//
+// This function lets clients enrich lists that only carry user IDs, such as audit entries or
+// giveaway entrants, without one request per user. Users are served from a cache that is also
+// filled by the ban and reaction endpoints; see resolveUsers for the lookup order.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A JSON object with the field "ids", an array of up to 1000 user IDs.
+//
+// Returns:
+//   - On success, it returns a JSON object mapping the resolved IDs to users.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid or has too many IDs.
+// @Summary		Resolve Guild Users
+// @Description	Resolve up to 1000 user IDs to user objects, using the user cache.
+// @Tags			User
+// @Param			body	body		object	true	"User IDs, e.g. {\"ids\": [\"123\"]}"
+// @Success		200		{object}	map[string]User
+// @Failure		400		{object}	error
+// @Router			/api/guild/users/resolve [post]
+func ResolveGuildUsers(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	var body struct {
+		IDs []string `json:"ids"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if len(body.IDs) > maxResolveUsers {
+		return c.Status(fiber.StatusBadRequest).SendString("Too many user IDs: at most 1000 are allowed")
+	}
+
+	return c.JSON(resolveUsers(s, guildID, body.IDs))
+}
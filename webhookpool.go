@@ -0,0 +1,278 @@
+package disgm
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	poolWebhookName    = "disgm identity pool" // Name of the pooled webhooks, which marks them as managed by disgm.
+	maxPoolWebhooks    = 3                     // Pooled webhooks per channel, of the 15 Discord allows.
+	poolIdleTimeout    = 24 * time.Hour        // Time after the last message the pooled webhooks of a channel are deleted.
+	poolSweepInterval  = time.Hour             // Interval in which idle pools are cleaned up.
+	maxWebhookUsername = 80                    // Longest display name of a webhook message.
+)
+
+// ImpersonatedMessage is a message sent with a display name and avatar of its own through the
+// pooled webhooks of a channel.
+type ImpersonatedMessage struct {
+	Username        string                            `json:"username"`                                        // Display name, 1-80 characters
+	AvatarURL       string                            `json:"avatar_url,omitempty"`                            // URL of the avatar
+	Content         string                            `json:"content,omitempty"`                               // Text of the message
+	Embeds          []*discordgo.MessageEmbed         `json:"embeds,omitempty" swaggertype:"array,object"`     // Up to 10 embeds
+	AllowedMentions *discordgo.MessageAllowedMentions `json:"allowed_mentions,omitempty" swaggertype:"object"` // Mentions that notify, none by default
+	Flags           discordgo.MessageFlags            `json:"flags,omitempty" swaggertype:"integer"`           // Message flags, e.g. 4096 to suppress notifications
+	TTS             bool                              `json:"tts,omitempty"`                                   // Whether the message is read aloud
+}
+
+// channelPool holds the pooled webhooks of a channel. Its lock is held while webhooks are loaded
+// or created, so concurrent messages to a new channel create one webhook, not one each.
+type channelPool struct {
+	sync.Mutex
+	loaded   bool
+	webhooks []*discordgo.Webhook
+	next     int       // Webhook the round robin starts at
+	lastUsed time.Time // When the last message was sent, for the cleanup of idle pools
+}
+
+// The pooled webhooks of the channels, keyed by channel ID.
+var webhookPool = struct {
+	sync.Mutex
+	channels map[string]*channelPool
+	sweeper  sync.Once
+}{channels: make(map[string]*channelPool)}
+
+// errPoolWebhookGone is returned for a pooled webhook deleted outside of disgm.
+var errPoolWebhookGone = errors.New("pooled webhook was deleted")
+
+// channelWebhookPool returns the pool of a channel, creating an empty one.
+func channelWebhookPool(channelID string) *channelPool {
+	webhookPool.Lock()
+	defer webhookPool.Unlock()
+	p, ok := webhookPool.channels[channelID]
+	if !ok {
+		p = &channelPool{}
+		webhookPool.channels[channelID] = p
+	}
+	return p
+}
+
+// executeRoute returns the rate-limited route of executing a webhook.
+func executeRoute(w *discordgo.Webhook) string {
+	return bucketRoute(http.MethodPost, discordgo.EndpointWebhookToken(w.ID, w.Token))
+}
+
+// pooled reports whether a webhook is a pooled webhook created by the bot.
+func pooled(s *discordgo.Session, w *discordgo.Webhook) bool {
+	return w.Name == poolWebhookName && w.Token != "" && (s.State.User == nil || w.User == nil || w.User.ID == s.State.User.ID)
+}
+
+// acquire returns a webhook of the pool to send a message with: the next one with requests left
+// in its rate limit, else a new one while the pool is not full, else the one available first.
+// The pooled webhooks left from an earlier run are adopted on first use.
+func (p *channelPool) acquire(s *discordgo.Session, channelID string, options []discordgo.RequestOption) (*discordgo.Webhook, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.loaded {
+		webhooks, err := s.ChannelWebhooks(channelID)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range webhooks {
+			if pooled(s, w) && len(p.webhooks) < maxPoolWebhooks {
+				p.webhooks = append(p.webhooks, w)
+			}
+		}
+		p.loaded = true
+	}
+	p.lastUsed = time.Now()
+
+	var first *discordgo.Webhook
+	var firstWait time.Duration
+	for i := range p.webhooks {
+		w := p.webhooks[(p.next+i)%len(p.webhooks)]
+		wait := bucketWait(executeRoute(w))
+		if wait <= 0 {
+			p.next = (p.next + i + 1) % len(p.webhooks)
+			return w, nil
+		}
+		if first == nil || wait < firstWait {
+			first, firstWait = w, wait
+		}
+	}
+	if len(p.webhooks) < maxPoolWebhooks {
+		w, err := s.WebhookCreate(channelID, poolWebhookName, "", options...)
+		if err != nil {
+			return nil, err
+		}
+		p.webhooks = append(p.webhooks, w)
+		return w, nil
+	}
+	return first, nil // discordgo waits for its rate limit.
+}
+
+// drop removes a webhook deleted outside of disgm from the pool.
+func (p *channelPool) drop(webhookID string) {
+	p.Lock()
+	defer p.Unlock()
+	for i, w := range p.webhooks {
+		if w.ID == webhookID {
+			p.webhooks = append(p.webhooks[:i], p.webhooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// sendImpersonated sends a message through the pool of a channel. A pooled webhook deleted
+// outside of disgm is dropped and the message sent through another one.
+func sendImpersonated(s *discordgo.Session, channelID, threadID string, params *discordgo.WebhookParams, options []discordgo.RequestOption) (*discordgo.Message, error) {
+	webhookPool.sweeper.Do(func() { go sweepWebhookPools(s) })
+
+	p := channelWebhookPool(channelID)
+	for attempt := 0; ; attempt++ {
+		w, err := p.acquire(s, channelID, options)
+		if err != nil {
+			return nil, err
+		}
+
+		var msg *discordgo.Message
+		if threadID != "" {
+			msg, err = s.WebhookThreadExecute(w.ID, w.Token, true, threadID, params)
+		} else {
+			msg, err = s.WebhookExecute(w.ID, w.Token, true, params)
+		}
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeUnknownWebhook {
+			p.drop(w.ID)
+			if attempt == 0 {
+				continue
+			}
+			return nil, errPoolWebhookGone
+		}
+		return msg, err
+	}
+}
+
+// sweepWebhookPools deletes the pooled webhooks of channels without messages for the idle
+// timeout, so unused channels do not keep webhooks around.
+func sweepWebhookPools(s *discordgo.Session) {
+	for range time.Tick(poolSweepInterval) {
+		var idle []*channelPool
+		webhookPool.Lock()
+		for channelID, p := range webhookPool.channels {
+			p.Lock()
+			if time.Since(p.lastUsed) > poolIdleTimeout {
+				idle = append(idle, p)
+				delete(webhookPool.channels, channelID)
+			}
+			p.Unlock()
+		}
+		webhookPool.Unlock()
+
+		for _, p := range idle {
+			for _, w := range p.webhooks {
+				s.WebhookDelete(w.ID) // Already gone if it was deleted manually.
+			}
+		}
+	}
+}
+
+// registerWebhookPoolHandlers forgets the pools of deleted channels, whose webhooks Discord
+// deletes with them.
+func registerWebhookPoolHandlers(s *discordgo.Session) {
+	s.AddHandler(func(s *discordgo.Session, c *discordgo.ChannelDelete) {
+		webhookPool.Lock()
+		defer webhookPool.Unlock()
+		delete(webhookPool.channels, c.ID)
+	})
+}
+
+// SendImpersonatedMessage sends a message with any display name and avatar to a channel.
+//
+// The message is sent through webhooks disgm creates and manages in the channel, named "disgm
+// identity pool": up to three per channel, used in turn so messages are not held up by the rate
+// limit of a single webhook. Webhooks left from an earlier run are reused, webhooks deleted by
+// hand replaced, and the webhooks of a channel without messages for a day deleted. Mentions do
+// not notify anyone unless allowed_mentions allows them.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - thread_id: The ID of a thread of the channel to post in.
+//
+// Request Body:
+//   - An ImpersonatedMessage object with the username and the content or embeds.
+//
+// Returns:
+//   - On success, it returns the sent message as JSON with HTTP status 201 (Created).
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token's scope or the bot lacks
+//     the Manage Webhooks permission, or HTTP status 500 if the message cannot be sent.
+//
+// @Summary		Send Impersonated Message
+// @Description	Send a message with any display name and avatar through webhooks disgm manages in the channel.
+// @Tags			Webhooks
+// @Param			channelid	path		string				true	"Channel ID"
+// @Param			thread_id	query		string				false	"Thread to post in"
+// @Param			body		body		ImpersonatedMessage	true	"Message"
+// @Success		201			{object}	models.Message
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/impersonated-messages [post]
+func SendImpersonatedMessage(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	var message ImpersonatedMessage
+	if err := c.BodyParser(&message); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	name := strings.ToLower(message.Username)
+	switch {
+	case strings.TrimSpace(message.Username) == "" || utf8.RuneCountInString(message.Username) > maxWebhookUsername:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: username must have 1-80 characters")
+	case strings.Contains(name, "discord") || strings.Contains(name, "clyde"):
+		return c.Status(fiber.StatusBadRequest).SendString(`Invalid request body: Discord rejects usernames containing "discord" or "clyde"`)
+	case message.AvatarURL != "" && !strings.HasPrefix(message.AvatarURL, "https://") && !strings.HasPrefix(message.AvatarURL, "http://"):
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: avatar_url must be an http or https URL")
+	case message.Content == "" && len(message.Embeds) == 0:
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: content or embeds are required")
+	}
+	if permission := botMissingPermission(s, channelID, discordgo.PermissionManageWebhooks); permission != "" {
+		return permissionForbidden(c, permission)
+	}
+
+	params := &discordgo.WebhookParams{
+		Username:        message.Username,
+		AvatarURL:       message.AvatarURL,
+		Content:         message.Content,
+		Embeds:          message.Embeds,
+		AllowedMentions: message.AllowedMentions,
+		Flags:           message.Flags,
+		TTS:             message.TTS,
+	}
+	if params.AllowedMentions == nil {
+		params.AllowedMentions = &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{}}
+	}
+
+	msg, err := sendImpersonated(s, channelID, c.Query("thread_id"), params, auditOptions(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to send message: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(msg)
+}
@@ -0,0 +1,330 @@
+package disgm
+
+import (
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+type Webhook = models.Webhook
+
+// maxAvatarSize caps the webhook avatars forwarded to Discord.
+const maxAvatarSize = 8 * 1024 * 1024
+
+// channelWebhook retrieves a webhook and checks that it belongs to the channel.
+func channelWebhook(s *discordgo.Session, channelID, webhookID string) (*discordgo.Webhook, error) {
+	webhook, err := s.Webhook(webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.ChannelID != channelID {
+		return nil, discordgo.ErrStateNotFound
+	}
+	return webhook, nil
+}
+
+// GetChannelWebhooks retrieves the webhooks of a channel.
+//
+// This function extracts the channel ID from the request parameters and uses the DiscordGo
+// session to fetch the webhooks of the channel, including the tokens of incoming webhooks.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the webhooks as a JSON array with HTTP status 200.
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is outside the
+//     token's scope, or HTTP status 500 if the webhooks cannot be retrieved.
+// @Summary		Get Channel Webhooks
+// @Description	Retrieve the webhooks of a channel.
+// @Tags			Webhooks
+// @Param			channelid	path		string	true	"Channel ID"
+// @Success		200			{array}		Webhook
+// @Failure		403			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/webhooks [get]
+func GetChannelWebhooks(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+
+	if !channelAllowed(c, channelID, false) {
+		return channelForbidden(c)
+	}
+
+	webhooks, err := s.ChannelWebhooks(channelID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve webhooks: " + err.Error())
+	}
+
+	return c.JSON(webhooks)
+}
+
+// GetGuildWebhooks retrieves the webhooks of all channels of the guild.
+//
+// This function uses the DiscordGo session to fetch the webhooks of the guild in one request,
+// for audits and cleanups that would otherwise list every channel. Webhooks of channels outside
+// the token's scope are left out.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the webhooks as a JSON array with HTTP status 200.
+//   - On failure, it returns an HTTP status 500 if the webhooks cannot be retrieved, e.g. if the
+//     bot lacks the Manage Webhooks permission.
+// @Summary		Get Guild Webhooks
+// @Description	Retrieve the webhooks of all channels of the guild.
+// @Tags			Webhooks
+// @Success		200	{array}		Webhook
+// @Failure		500	{object}	error
+// @Router			/api/guild/webhooks [get]
+func GetGuildWebhooks(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	webhooks, err := s.GuildWebhooks(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve webhooks: " + err.Error())
+	}
+
+	// Hides the webhooks of channels outside the token scope.
+	webhooks = slices.DeleteFunc(webhooks, func(w *discordgo.Webhook) bool {
+		return !channelAllowed(c, w.ChannelID, false)
+	})
+
+	return c.JSON(webhooks)
+}
+
+// CreateChannelWebhook creates a webhook in a channel.
+//
+// The avatar is either a data URI or plain base64, whose type is detected from its content.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the created webhook as JSON with HTTP status 201.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token's scope,
+//     or HTTP status 500 if the webhook cannot be created.
+// @Summary		Create Channel Webhook
+// @Description	Create a webhook in a channel.
+// @Tags			Webhooks
+// @Param			channelid	path		string					true	"Channel ID"
+// @Param			body		body		models.WebhookParams	true	"Webhook parameters"
+// @Success		201			{object}	models.Webhook
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		413			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/webhooks [post]
+func CreateChannelWebhook(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	var params models.WebhookParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if params.Name == "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: name is required")
+	}
+	if params.Avatar != "" {
+		avatar, err := imageDataURI(params.Avatar, maxAvatarSize)
+		if err != nil {
+			return invalidImage(c, err)
+		}
+		params.Avatar = avatar
+	}
+
+	webhook, err := s.WebhookCreate(channelID, params.Name, params.Avatar, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to create webhook: " + err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(webhook)
+}
+
+// UpdateChannelWebhook edits the name or avatar of a channel webhook, or moves it to another channel.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the updated webhook as JSON with HTTP status 200.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) if a channel is outside the token's scope,
+//     HTTP status 404 (Not Found) if the webhook does not belong to the channel,
+//     or HTTP status 500 if the webhook cannot be updated.
+// @Summary		Update Channel Webhook
+// @Description	Edit the name or avatar of a webhook, or move it to another channel.
+// @Tags			Webhooks
+// @Param			channelid	path		string					true	"Channel ID"
+// @Param			webhookid	path		string					true	"Webhook ID"
+// @Param			body		body		models.WebhookParams	true	"Webhook parameters"
+// @Success		200			{object}	models.Webhook
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		404			{object}	error
+// @Failure		413			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/channels/{channelid}/webhooks/{webhookid} [patch]
+func UpdateChannelWebhook(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	channelID := c.Params("channelid")
+	webhookID := c.Params("webhookid")
+
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	var params models.WebhookParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	if params.ChannelID != "" && params.ChannelID != channelID {
+		if !channelAllowed(c, params.ChannelID, true) {
+			return channelForbidden(c)
+		}
+		target, err := cachedChannel(s, params.ChannelID)
+		if err != nil || target.GuildID != guildID {
+			return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: unknown channel_id")
+		}
+	}
+
+	if _, err := channelWebhook(s, channelID, webhookID); err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Webhook not found")
+	}
+	if params.Avatar != "" {
+		avatar, err := imageDataURI(params.Avatar, maxAvatarSize)
+		if err != nil {
+			return invalidImage(c, err)
+		}
+		params.Avatar = avatar
+	}
+
+	webhook, err := s.WebhookEdit(webhookID, params.Name, params.Avatar, params.ChannelID, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update webhook: " + err.Error())
+	}
+
+	return c.JSON(webhook)
+}
+
+// DeleteChannelWebhook deletes a webhook of a channel.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 403 (Forbidden) if the channel is outside the
+//     token's scope, HTTP status 404 (Not Found) if the webhook does not belong to the channel,
+//     or HTTP status 500 if the webhook cannot be deleted.
+// @Summary		Delete Channel Webhook
+// @Description	Delete a webhook of a channel.
+// @Tags			Webhooks
+// @Param			channelid	path	string	true	"Channel ID"
+// @Param			webhookid	path	string	true	"Webhook ID"
+// @Success		204
+// @Failure		403	{object}	error
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/channels/{channelid}/webhooks/{webhookid} [delete]
+func DeleteChannelWebhook(c *fiber.Ctx, s *discordgo.Session) error {
+	channelID := c.Params("channelid")
+	webhookID := c.Params("webhookid")
+
+	if !channelAllowed(c, channelID, true) {
+		return channelForbidden(c)
+	}
+
+	if _, err := channelWebhook(s, channelID, webhookID); err != nil {
+		return c.Status(fiber.StatusNotFound).SendString("Webhook not found")
+	}
+
+	if err := s.WebhookDelete(webhookID, auditOptions(c)...); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to delete webhook: " + err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ExecuteWebhook sends a message through a webhook of the guild.
+//
+// This function lets dashboards post with the name and avatar of a webhook without calling
+// Discord directly. The webhook must belong to the guild, and its channel must be within the
+// token's scope.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - wait: Whether to wait for the message to be sent and return it.
+//   - thread_id: The ID of a thread of the webhook's channel to post in.
+//
+// Returns:
+//   - On success, it returns the sent message as JSON if wait is set, or HTTP status 204 (No Content).
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid,
+//     HTTP status 403 (Forbidden) if the channel is outside the token's scope,
+//     HTTP status 404 (Not Found) if the webhook does not belong to the guild,
+//     or HTTP status 500 if the message cannot be sent.
+// @Summary		Execute Webhook
+// @Description	Send a message through a webhook of the guild.
+// @Tags			Webhooks
+// @Param			webhookid	path		string	true	"Webhook ID"
+// @Param			token		path		string	true	"Webhook token"
+// @Param			wait		query		bool	false	"Wait for the message and return it"
+// @Param			thread_id	query		string	false	"Thread to post in"
+// @Success		200			{object}	models.Message
+// @Success		204
+// @Failure		400	{object}	error
+// @Failure		403	{object}	error
+// @Failure		404	{object}	error
+// @Failure		500	{object}	error
+// @Router			/api/guild/webhooks/{webhookid}/{token}/execute [post]
+func ExecuteWebhook(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+	webhookID := c.Params("webhookid")
+	token := c.Params("token")
+	wait := c.QueryBool("wait")
+	threadID := c.Query("thread_id")
+
+	webhook, err := s.WebhookWithToken(webhookID, token)
+	if err != nil || webhook.GuildID != guildID {
+		return c.Status(fiber.StatusNotFound).SendString("Webhook not found")
+	}
+	if !channelAllowed(c, webhook.ChannelID, true) {
+		return channelForbidden(c)
+	}
+
+	var params discordgo.WebhookParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+
+	var msg *discordgo.Message
+	if threadID != "" {
+		msg, err = s.WebhookThreadExecute(webhookID, token, wait, threadID, &params)
+	} else {
+		msg, err = s.WebhookExecute(webhookID, token, wait, &params)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to execute webhook: " + err.Error())
+	}
+
+	if !wait {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	return c.JSON(msg)
+}
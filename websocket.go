@@ -2,51 +2,227 @@ package disgm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/rif223/disgm/store"
 )
 
 // Event struct defines the structure of an event that is sent to clients over WebSocket.
 // It contains the event name and the associated data.
 type Event struct {
-	Name string      `json:"name"`
-	Data interface{} `json:"data"`
+	Name   string      `json:"name"`
+	Data   interface{} `json:"data"`
+	Replay bool        `json:"replay,omitempty"` // Set on recorded events replayed to the client
 }
 
+// WSClient describes a connected WebSocket client.
+//
+// Clients identify themselves with the client_name, client_version and client_purpose query
+// parameters when connecting, or later with the identify op, so their traffic can be attributed.
+type WSClient struct {
+	ID          string        `json:"id"`                // ID of the connection, sent to the client in the HELLO event
+	Name        string        `json:"name,omitempty"`    // Name of the client, e.g. the service using the connection
+	Version     string        `json:"version,omitempty"` // Version of the client
+	Purpose     string        `json:"purpose,omitempty"` // What the client uses the connection for
+	Encoding    string        `json:"encoding"`          // Encoding of the events sent to the client, json or proto
+	ConnectedAt time.Time     `json:"connected_at"`      // When the client connected
+	RemoteAddr  string        `json:"remote_addr"`       // Network address of the client
+	MessagesIn  int           `json:"messages_in"`       // Messages received from the client
+	EventsOut   int           `json:"events_out"`        // Events sent to the client
+	Batch       *BatchOptions `json:"batch,omitempty"`   // Batch options set with the batch op, nil if events are sent one by one
+	Encrypted   bool          `json:"encrypted"`         // Whether the frames sent to the client are sealed for its public key
+
+	// Write diagnostics, to find clients that fall behind. Events are written outside the lock of
+	// the client registry, so a client that reads slowly does not block the other guilds, but it
+	// delays the clients written to after it for the same event, up to the write timeout.
+	QueueDepth        int     `json:"queue_depth"`          // Events waiting in the batch of the client
+	WriteLatencyMs    float64 `json:"write_latency_ms"`     // Moving average of the time a write to the client takes
+	MaxWriteLatencyMs float64 `json:"max_write_latency_ms"` // Slowest write to the client
+	SlowWrites        int     `json:"slow_writes"`          // Writes slower than 250 milliseconds
+	Slow              bool    `json:"slow"`                 // Whether the average write latency is above 250 milliseconds
+
+	token        string        // Token the client connected with, checked by RevalidateTokens
+	scope        *store.Scope  // Channels the token of the client is restricted to, nil if unrestricted
+	subscription *subscription // Events the client subscribed to, nil for all
+	batch        *eventBatch   // Events waiting to be sent in a batch, nil if batching is off
+	publicKey    *[32]byte     // Key the frames sent to the client are sealed for, nil if they are not encrypted
+	writeMu      *sync.Mutex   // Serializes the writes to the connection, taken without clientsMu
+}
+
+// ClientIdentity is the payload of the identify op, which labels the connection of a client.
+type ClientIdentity struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Purpose string `json:"purpose"`
+}
+
+// maxClientLabel is the maximum length of a client name, version or purpose.
+const maxClientLabel = 64
+
+// label identifies a client in logs by its name and version, falling back to the connection ID.
+func (c *WSClient) label() string {
+	switch {
+	case c.Name == "":
+		return c.ID
+	case c.Version == "":
+		return c.Name + " (" + c.ID + ")"
+	default:
+		return c.Name + "/" + c.Version + " (" + c.ID + ")"
+	}
+}
+
+// identify sets the labels of a client, truncated to maxClientLabel.
+func (c *WSClient) identify(identity ClientIdentity) {
+	truncate := func(s string) string { return s[:min(len(s), maxClientLabel)] }
+	c.Name = truncate(identity.Name)
+	c.Version = truncate(identity.Version)
+	c.Purpose = truncate(identity.Purpose)
+}
+
+// Op struct defines the structure of a message that clients send over WebSocket.
+// It contains the operation name and its payload.
+type Op struct {
+	Op   string          `json:"op"`
+	Data json.RawMessage `json:"data"`
+}
+
+const (
+	clientWriteTimeout = 10 * time.Second       // Time a write may block before the client is disconnected.
+	slowWriteLatency   = 250 * time.Millisecond // Write latency above which a client is considered slow.
+	closeKicked        = 4000                   // Close code of clients disconnected with the kick endpoint.
+)
+
 // A map to keep track of connected clients. The map key is the WebSocket connection,
 // and the value is the client's unique ID.
 var clients = make(map[*websocket.Conn]string)
 
+// A map of the connection details of each client, keyed like clients.
+var clientInfo = make(map[*websocket.Conn]*WSClient)
+
+// clientsMu guards the clients and clientInfo maps and the fields of the clients. It is never held
+// while writing to a connection, so a slow client cannot block the others; writes to a connection
+// are serialized by the writeMu of its client instead.
+var clientsMu sync.Mutex
+
+// eventEncoder encodes the data of the events sent to WebSocket clients, set from the JSONEncoder option.
+var eventEncoder = struct {
+	sync.Mutex
+	marshal utils.JSONMarshal
+}{marshal: json.Marshal}
+
+// marshalEvent encodes the data of an event with the configured encoder.
+func marshalEvent(data interface{}) ([]byte, error) {
+	eventEncoder.Lock()
+	marshal := eventEncoder.marshal
+	eventEncoder.Unlock()
+	return marshal(data)
+}
+
 // WebSocket function manages the lifecycle of a WebSocket connection.
-// It registers the client, sends a welcome message, and listens for incoming messages.
-func WebSocket(conn *websocket.Conn, id string) {
+// It negotiates the protocol version, registers the client, sends the HELLO event, and listens
+// for incoming messages.
+func WebSocket(conn *websocket.Conn, id string, s *discordgo.Session) {
 	defer func() {
 		conn.Close()
 	}()
 
+	// Refuse protocol versions disgm does not speak
+	version, ok := negotiateVersion(conn.Query("v"))
+	if !ok {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeUnsupportedVersion, "unsupported protocol version"), time.Now().Add(time.Second))
+		return
+	}
+
+	// Refuse clients without a public key if encryption is required
+	var publicKey *[32]byte
+	if key := conn.Query("public_key"); key != "" || encryptionRequired() {
+		var err error
+		if publicKey, err = parsePublicKey(key); err != nil {
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(closeEncryptionRequired, err.Error()), time.Now().Add(time.Second))
+			return
+		}
+	}
+
 	// Register the client with their unique ID
+	clientID, _ := randomToken(8)
+	info := &WSClient{ID: clientID, ConnectedAt: time.Now(), RemoteAddr: conn.RemoteAddr().String(), Encoding: EncodingJSON, writeMu: new(sync.Mutex)}
+	info.publicKey, info.Encrypted = publicKey, publicKey != nil
+	info.token, _ = conn.Locals("Token").(string)
+	info.scope, _ = conn.Locals("Scope").(*store.Scope)
+	if conn.Query("encoding") == EncodingProto {
+		info.Encoding = EncodingProto
+	}
+	info.identify(ClientIdentity{
+		Name:    conn.Query("client_name"),
+		Version: conn.Query("client_version"),
+		Purpose: conn.Query("client_purpose"),
+	})
+	clientsMu.Lock()
 	clients[conn] = id
-	log.Printf("Client connected: %s", id)
+	clientInfo[conn] = info
+	label := info.label()
+	clientsMu.Unlock()
+	log.Printf("Client connected: %s [%s]", id, label)
+
+	// Send a welcome message to clients of the first protocol version, which expect it
+	if version == 1 {
+		info.writeMu.Lock()
+		conn.WriteMessage(websocket.TextMessage, []byte("Welcome! You are connected."))
+		info.writeMu.Unlock()
+	}
 
-	// Send a welcome message to the client
-	conn.WriteMessage(websocket.TextMessage, []byte("Welcome! You are connected."))
+	// Tell the client its connection ID, used to target it with event replays, and the protocol
+	writeEvent(conn, "HELLO", Hello{
+		ClientID:            clientID,
+		ProtocolVersion:     version,
+		HeartbeatIntervalMs: int(heartbeatInterval / time.Millisecond),
+		Ops:                 wsOpNames(),
+		Encoding:            info.Encoding,
+		Encrypted:           info.Encrypted,
+	})
+	sendReady(conn, id, s) // Sends the READY snapshot if prefetching is on.
 
 	// Handle incoming messages from the client
-	handleMessages(conn, id)
+	handleMessages(conn, id, s)
 }
 
 // handleMessages continuously listens for messages from the connected client
-// and logs the received messages. It also handles client disconnections.
-func handleMessages(conn *websocket.Conn, id string) {
+// and dispatches the supported ops. It also handles client disconnections.
+func handleMessages(conn *websocket.Conn, id string, s *discordgo.Session) {
 	defer func() {
 		// Close the connection and remove the client from the map on disconnect
 		conn.Close()
+		clientsMu.Lock()
+		info := clientInfo[conn]
+		label := info.label()
+		if info.batch != nil && info.batch.timer != nil {
+			info.batch.timer.Stop()
+		}
 		delete(clients, conn)
-		log.Printf("Client disconnected: %s", id)
+		delete(clientInfo, conn)
+		clientsMu.Unlock()
+		forgetChatClient(conn)
+		forgetClientRelays(conn)
+		log.Printf("Client disconnected: %s [%s]", id, label)
 	}()
 
+	// Close connections that send nothing and answer no ping for the heartbeat timeout
+	done := make(chan struct{})
+	defer close(done)
+	go keepAlive(conn, done)
+	conn.SetReadDeadline(time.Now().Add(heartbeatTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(heartbeatTimeout))
+	})
+
 	// Loop to continuously read messages from the WebSocket connection
 	for {
 		_, msg, err := conn.ReadMessage() // Read the message from the client
@@ -55,34 +231,310 @@ func handleMessages(conn *websocket.Conn, id string) {
 			log.Printf("error: %v", err)
 			break
 		}
+
+		conn.SetReadDeadline(time.Now().Add(heartbeatTimeout))
+
+		clientsMu.Lock()
+		info := clientInfo[conn]
+		info.MessagesIn++
+		label := info.label()
+		clientsMu.Unlock()
+
+		// Dispatch the op, if the message is one
+		var op Op
+		if json.Unmarshal(msg, &op) == nil {
+			switch op.Op {
+			case "heartbeat":
+				writeEvent(conn, "HEARTBEAT_ACK", HeartbeatAck{Time: time.Now().UTC()})
+				continue
+			case "chat":
+				handleChat(conn, id, s, op.Data)
+				continue
+			case "relay":
+				handleClientRelay(conn, id, op.Data)
+				continue
+			case "subscribe":
+				handleSubscribe(conn, op.Data)
+				continue
+			case "batch":
+				handleBatch(conn, op.Data)
+				continue
+			case "encrypt":
+				handleEncrypt(conn, op.Data)
+				continue
+			case "identify":
+				var identity ClientIdentity
+				if json.Unmarshal(op.Data, &identity) == nil {
+					clientsMu.Lock()
+					info.identify(identity)
+					clientsMu.Unlock()
+					log.Printf("Client identified: %s [%s] is now [%s]", id, label, info.label())
+				}
+				continue
+			}
+		}
+
 		// Log the message along with the client ID
-		log.Printf("%s: %s", id, msg)
+		log.Printf("%s [%s]: %s", id, label, msg)
 	}
 }
 
-// EventCall is used to send an event to a specific client identified by the ID.
-// It marshals the event data to JSON and sends it via WebSocket to the client.
+// EventCall is used to send an event to the clients identified by the ID.
+// It marshals the event data to JSON and sends it via WebSocket to each client.
 func EventCall(id string, name string, data interface{}) error {
+	return eventCallExcept(id, name, data, nil)
+}
+
+// eventCallExcept sends an event to the clients identified by the ID, skipping the given connection.
+func eventCallExcept(id string, name string, data interface{}, except *websocket.Conn) error {
+	return broadcastEvent(id, name, data, except, false)
+}
+
+// broadcastEvent sends an event to the subscribed clients identified by the ID, skipping the given
+// connection. Priority events skip the channel filters of the subscriptions and are recorded in the
+// event buffer after they are sent rather than before. Clients whose token is restricted to channels
+// never receive the events of other channels, priority or not.
+func broadcastEvent(id string, name string, data interface{}, except *websocket.Conn, priority bool) error {
+	// Marshal the event data into JSON format
+	dataBytes, err := marshalEvent(data)
+	if err != nil {
+		// Return an error if JSON marshalling fails
+		return fmt.Errorf("error marshalling message: %v", err)
+	}
+
+	// Record the event in the persistent event buffer
+	if priority {
+		defer recordEvent(id, name, dataBytes)
+	} else {
+		recordEvent(id, name, dataBytes)
+	}
+
+	// Pass the event to the handlers of the embedding process
+	handleEvent(id, name, data)
+
+	var channelIDs []string
+	var user string
+	if !priority {
+		channelIDs = eventChannelIDs(name, data)
+		user = coalesceUser(name, data)
+	}
+
+	// The event is encoded once per encoding and the frames are shared by all clients
+	frames := eventFrames{name: name, data: dataBytes}
+	defer frames.release()
+
+	// The channels checked against the token scopes, found once the first restricted client is reached
+	var scopeChannels []string
+	scopeChecked := false
+
+	// The recipients are collected under the lock and written to after it is released, so a slow
+	// client cannot block the registry for the other clients and guilds
+	type recipient struct {
+		conn  *websocket.Conn
+		info  *WSClient
+		sink  string
+		batch []batchedEvent // Full batch of a batching client, nil if the event is written directly
+	}
+	var recipients []recipient
+
+	clientsMu.Lock()
 	// Iterate over all connected clients
 	for client, gid := range clients {
-		// Send the event to the client with the matching ID
-		if gid == id {
-			// Create an Event struct with the event name and data
-			event := Event{
-				Name: name,
-				Data: data,
+		// Send the event to every subscribed client with the matching ID
+		info := clientInfo[client]
+		if gid != id || client == except {
+			continue
+		}
+		if info.scope != nil {
+			if !scopeChecked {
+				scopeChannels, scopeChecked = eventScopeChannelIDs(name, data), true
 			}
-
-			// Marshal the event into JSON format
-			eventBytes, err := json.Marshal(event)
-			if err != nil {
-				// Return an error if JSON marshalling fails
-				return fmt.Errorf("error marshalling message: %v", err)
+			if !scopeAllowsEvent(info.scope, scopeChannels) {
+				continue
 			}
+		}
+		if !info.subscription.wants(name, channelIDs) {
+			continue
+		}
+		if info.batch != nil && !priority {
+			// Queue the event in the client's batch, which is sent in one frame once it is full
+			if full := info.batch.add(client, name, dataBytes, user); full != nil {
+				recipients = append(recipients, recipient{client, info, clientSinkName(info), full})
+			} else {
+				info.EventsOut++
+				recordDelivery(id, SinkWebSocket, clientSinkName(info), nil)
+			}
+			continue
+		}
+		recipients = append(recipients, recipient{conn: client, info: info, sink: clientSinkName(info)})
+	}
+	clientsMu.Unlock()
+
+	for _, r := range recipients {
+		var werr error
+		if r.batch != nil {
+			werr = writeBatch(r.conn, r.info, r.batch)
+		} else {
+			// Write the event to the client's WebSocket connection in its encoding
+			werr = frames.write(r.conn, r.info)
+		}
+		recordDelivery(id, SinkWebSocket, r.sink, werr)
+		if werr != nil {
+			err = werr
+		} else {
+			clientsMu.Lock()
+			r.info.EventsOut++
+			clientsMu.Unlock()
+		}
+	}
+	return err
+}
 
-			// Write the JSON-encoded event to the client's WebSocket connection
-			return client.WriteMessage(websocket.TextMessage, eventBytes)
+// writeEvent sends an event to a single connection.
+func writeEvent(conn *websocket.Conn, name string, data interface{}) error {
+	dataBytes, err := marshalEvent(data)
+	if err != nil {
+		return fmt.Errorf("error marshalling message: %v", err)
+	}
+
+	clientsMu.Lock()
+	info, ok := clientInfo[conn]
+	clientsMu.Unlock()
+	if !ok {
+		return errClientDisconnected
+	}
+	return writeEncoded(conn, info, name, dataBytes, false)
+}
+
+// errClientDisconnected is returned for writes to a client that is no longer registered.
+var errClientDisconnected = errors.New("client disconnected")
+
+// envelopeBuffers pools the buffers events are encoded into before they are written, so the
+// fan-out to many clients does not allocate an envelope per client.
+var envelopeBuffers = sync.Pool{New: func() interface{} {
+	b := make([]byte, 0, 4096)
+	return &b
+}}
+
+// writeEncoded writes an event with JSON-encoded data to a connection in the encoding of its client.
+// The caller must not hold clientsMu.
+func writeEncoded(conn *websocket.Conn, info *WSClient, name string, data json.RawMessage, replay bool) error {
+	frames := eventFrames{name: name, data: data, replay: replay}
+	defer frames.release()
+	return frames.write(conn, info)
+}
+
+// eventFrames holds the encodings of an event, each encoded on first use into a pooled buffer,
+// so an event broadcast to many clients is encoded once per encoding rather than per client.
+type eventFrames struct {
+	name   string
+	data   json.RawMessage
+	replay bool
+	json   *[]byte // JSON envelope, nil until a JSON client is written to
+	proto  *[]byte // Protobuf envelope, nil until a proto client is written to
+}
+
+// write writes the event to a connection in the encoding of its client.
+// The caller must not hold clientsMu.
+func (f *eventFrames) write(conn *websocket.Conn, info *WSClient) error {
+	if info.Encoding == EncodingProto {
+		if f.proto == nil {
+			f.proto = envelopeBuffers.Get().(*[]byte)
+			*f.proto = appendProtoEvent((*f.proto)[:0], f.name, f.data, f.replay)
 		}
+		return writeFrame(conn, info, websocket.BinaryMessage, *f.proto)
+	}
+
+	if f.json == nil {
+		f.json = envelopeBuffers.Get().(*[]byte)
+		*f.json = appendEventJSON((*f.json)[:0], f.name, f.data, f.replay)
 	}
+	return writeFrame(conn, info, websocket.TextMessage, *f.json)
+}
+
+// writeFrame writes a frame to a connection and records the write latency of its client. Frames
+// of clients that registered a public key are sealed for it and sent as binary frames.
+//
+// A write that blocks for longer than clientWriteTimeout fails and the connection is closed, so a
+// client that stopped reading cannot stall the events of the others. A client whose average write
+// latency rises above slowWriteLatency is logged once until it catches up again.
+// The caller must not hold clientsMu.
+func writeFrame(conn *websocket.Conn, info *WSClient, messageType int, data []byte) error {
+	clientsMu.Lock()
+	publicKey := info.publicKey
+	clientsMu.Unlock()
+	if publicKey != nil {
+		sealed, err := seal(publicKey, data)
+		if err != nil {
+			return err
+		}
+		messageType, data = websocket.BinaryMessage, sealed
+	}
+
+	info.writeMu.Lock()
+	start := time.Now()
+	conn.SetWriteDeadline(start.Add(clientWriteTimeout))
+	err := conn.WriteMessage(messageType, data)
+	latency := time.Since(start)
+	info.writeMu.Unlock()
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	if err != nil {
+		log.Printf("Closing client %s [%s]: write failed after %v: %v", clients[conn], info.label(), latency, err)
+		conn.Close()
+		return err
+	}
+
+	ms := milliseconds(latency)
+	info.WriteLatencyMs = info.WriteLatencyMs*0.8 + ms*0.2
+	info.MaxWriteLatencyMs = max(info.MaxWriteLatencyMs, ms)
+	if latency > slowWriteLatency {
+		info.SlowWrites++
+	}
+	slow := info.WriteLatencyMs > milliseconds(slowWriteLatency)
+	if slow && !info.Slow {
+		log.Printf("Slow client %s [%s]: writes take %.0fms on average", clients[conn], info.label(), info.WriteLatencyMs)
+	}
+	info.Slow = slow
 	return nil
 }
+
+// release returns the buffers of the frames to the pool. The frames must not be used afterwards.
+func (f *eventFrames) release() {
+	for _, buf := range []*[]byte{f.json, f.proto} {
+		if buf != nil {
+			envelopeBuffers.Put(buf)
+		}
+	}
+	f.json, f.proto = nil, nil
+}
+
+// appendEventJSON appends the JSON encoding of an Event with compact JSON data, as json.Marshal
+// would produce it. Event names are plain ASCII identifiers, so Go quoting is valid JSON for them.
+func appendEventJSON(b []byte, name string, data json.RawMessage, replay bool) []byte {
+	b = append(b, `{"name":`...)
+	b = strconv.AppendQuote(b, name)
+	b = append(b, `,"data":`...)
+	if len(data) == 0 {
+		b = append(b, "null"...)
+	} else {
+		b = append(b, data...)
+	}
+	if replay {
+		b = append(b, `,"replay":true`...)
+	}
+	return append(b, '}')
+}
+
+// guildClient returns the connection of a client of the guild by its connection ID.
+// The caller must hold clientsMu.
+func guildClient(guildID, clientID string) (*websocket.Conn, bool) {
+	for conn, info := range clientInfo {
+		if info.ID == clientID && clients[conn] == guildID {
+			return conn, true
+		}
+	}
+	return nil, false
+}
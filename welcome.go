@@ -0,0 +1,178 @@
+package disgm
+
+import (
+	"encoding/json"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rif223/disgm/models"
+)
+
+const (
+	maxWelcomeChannels           = 5   // Maximum number of channels on a welcome screen.
+	maxWelcomeDescription        = 140 // Maximum length of the description of a welcome screen.
+	maxWelcomeChannelDescription = 50  // Maximum length of the description of a welcome channel.
+)
+
+type WelcomeScreen = models.WelcomeScreen
+
+// WelcomeScreenParams are the changes to the welcome screen of a guild. Unset fields are kept.
+type WelcomeScreenParams struct {
+	Enabled         *bool                    `json:"enabled,omitempty"`          // Whether the welcome screen is shown to new members
+	Description     *string                  `json:"description,omitempty"`      // Description of the guild, max 140 characters
+	WelcomeChannels *[]models.WelcomeChannel `json:"welcome_channels,omitempty"` // Up to 5 channels, replacing the current ones
+}
+
+// validate checks the changes against the limits of welcome screens.
+func (p *WelcomeScreenParams) validate() string {
+	if p.Description != nil && utf8.RuneCountInString(*p.Description) > maxWelcomeDescription {
+		return "description must be at most 140 characters long"
+	}
+	if p.WelcomeChannels != nil {
+		if len(*p.WelcomeChannels) > maxWelcomeChannels {
+			return "at most 5 welcome_channels are allowed"
+		}
+		for _, ch := range *p.WelcomeChannels {
+			if ch.ChannelID == "" {
+				return "welcome_channels require a channel_id"
+			}
+			if ch.Description == "" || utf8.RuneCountInString(ch.Description) > maxWelcomeChannelDescription {
+				return "welcome channel descriptions must be 1 to 50 characters long"
+			}
+		}
+	}
+	return ""
+}
+
+// welcomeScreen reads the welcome screen of a guild.
+func welcomeScreen(s *discordgo.Session, guildID string) (*WelcomeScreen, error) {
+	endpoint := discordgo.EndpointGuild(guildID) + "/welcome-screen"
+	body, err := s.RequestWithBucketID("GET", endpoint, nil, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var screen WelcomeScreen
+	if err := json.Unmarshal(body, &screen); err != nil {
+		return nil, err
+	}
+	if screen.WelcomeChannels == nil {
+		screen.WelcomeChannels = []models.WelcomeChannel{}
+	}
+	return &screen, nil
+}
+
+// GetWelcomeScreen retrieves the welcome screen of the guild.
+//
+// The welcome screen is shown to new members of community guilds, with a description of the
+// guild and up to 5 channels to start in.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Returns:
+//   - On success, it returns the welcome screen as JSON, with an ETag header for If-Match on updates.
+//   - On failure, it returns an HTTP status 500 (Internal Server Error) with an error message.
+// @Summary		Get Welcome Screen
+// @Description	Retrieve the description and channels of the guild's welcome screen.
+// @Tags			Guild
+// @Success		200	{object}	WelcomeScreen
+// @Failure		500	{object}	error
+// @Router			/api/guild/welcome-screen [get]
+func GetWelcomeScreen(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	screen, err := welcomeScreen(s, guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve welcome screen: " + err.Error())
+	}
+
+	c.Set(fiber.HeaderETag, etag(screen))
+	return c.JSON(screen)
+}
+
+// UpdateWelcomeScreen updates the welcome screen of the guild.
+//
+// Only guilds with the COMMUNITY feature have a welcome screen. The channels given replace the
+// current ones. Requires an unrestricted token.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Body:
+//   - A WelcomeScreenParams object; unset fields are kept.
+//
+// Request Headers:
+//   - If-Match: Optional ETag of the welcome screen as read by the client. The update is refused if
+//     the welcome screen was changed since.
+//
+// Returns:
+//   - On success, it returns the updated welcome screen as JSON with its new ETag.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the body is invalid or exceeds the limits,
+//     HTTP status 403 (Forbidden) for restricted tokens,
+//     HTTP status 409 (Conflict) if the guild is not a community guild,
+//     HTTP status 412 (Precondition Failed) if the welcome screen does not match If-Match,
+//     or HTTP status 500 (Internal Server Error) if the welcome screen cannot be updated.
+// @Summary		Update Welcome Screen
+// @Description	Update the description and channels of the guild's welcome screen.
+// @Tags			Guild
+// @Param			body		body		WelcomeScreenParams	true	"Welcome screen changes"
+// @Param			If-Match	header		string				false	"ETag of the welcome screen as read"
+// @Success		200			{object}	WelcomeScreen
+// @Failure		400			{object}	error
+// @Failure		403			{object}	error
+// @Failure		409			{object}	error
+// @Failure		412			{object}	error
+// @Failure		500			{object}	error
+// @Router			/api/guild/welcome-screen [patch]
+func UpdateWelcomeScreen(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	if !unrestricted(c) {
+		return adminForbidden(c)
+	}
+
+	var params WelcomeScreenParams
+	if err := c.BodyParser(&params); err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + err.Error())
+	}
+	if msg := params.validate(); msg != "" {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid request body: " + msg)
+	}
+
+	guild, err := s.Guild(guildID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve guild: " + err.Error())
+	}
+	if !slices.Contains(guild.Features, discordgo.GuildFeatureCommunity) {
+		return c.Status(fiber.StatusConflict).SendString("Guild has no welcome screen: the COMMUNITY feature is required")
+	}
+	if c.Get(fiber.HeaderIfMatch) != "" {
+		current, err := welcomeScreen(s, guildID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve welcome screen: " + err.Error())
+		}
+		if version := etag(current); !ifMatch(c, version) {
+			return preconditionFailed(c, version)
+		}
+	}
+
+	endpoint := discordgo.EndpointGuild(guildID) + "/welcome-screen"
+	body, err := s.RequestWithBucketID("PATCH", endpoint, params, endpoint, auditOptions(c)...)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update welcome screen: " + err.Error())
+	}
+	var screen WelcomeScreen
+	if err := json.Unmarshal(body, &screen); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Failed to update welcome screen: " + err.Error())
+	}
+	if screen.WelcomeChannels == nil {
+		screen.WelcomeChannels = []models.WelcomeChannel{}
+	}
+
+	c.Set(fiber.HeaderETag, etag(&screen))
+	return c.JSON(screen)
+}
@@ -0,0 +1,90 @@
+package disgm
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gofiber/fiber/v2"
+)
+
+// widgetCacheTTL is the time a widget image is served from the cache, and the max-age clients
+// may cache it for. Discord itself refreshes the images every few minutes.
+const widgetCacheTTL = 5 * time.Minute
+
+// widgetStyles are the styles of the widget image, shield being the default.
+var widgetStyles = []string{"shield", "banner1", "banner2", "banner3", "banner4"}
+
+// widgetImage is a cached widget image.
+type widgetImage struct {
+	data    []byte
+	etag    string
+	fetched time.Time
+}
+
+// GetGuildWidgetImage retrieves the widget image of the guild as PNG.
+//
+// The image is proxied from Discord, so status pages can embed it through the API origin, and
+// cached for 5 minutes per guild and style. Responses carry an ETag and Cache-Control headers
+// and conditional requests are answered with HTTP status 304. The widget must be enabled in
+// the guild settings.
+//
+// Parameters:
+//   - c: *fiber.Ctx – The Fiber context used to handle HTTP requests and responses.
+//   - s: *discordgo.Session – The DiscordGo session used to interact with the Discord API.
+//
+// Request Parameters:
+//   - style: Optional style of the image, shield (default) or banner1 to banner4.
+//
+// Returns:
+//   - On success, it returns the PNG image with HTTP status 200, or 304 if it did not change.
+//   - On failure, it returns an HTTP status 400 (Bad Request) if the style is invalid,
+//     or HTTP status 500 (Internal Server Error) if the image cannot be retrieved.
+// @Summary		Get Guild Widget Image
+// @Description	Retrieve the widget image of the guild, cached for 5 minutes.
+// @Tags			Guild
+// @Produce		png
+// @Param			style	query		string	false	"Image style"	Enums(shield, banner1, banner2, banner3, banner4)
+// @Success		200		{file}		binary
+// @Success		304
+// @Failure		400		{object}	error
+// @Failure		500		{object}	error
+// @Router			/api/guild/widget.png [get]
+func GetGuildWidgetImage(c *fiber.Ctx, s *discordgo.Session) error {
+	guildID := c.Locals("ID").(string)
+
+	style := c.Query("style", "shield")
+	if !slices.Contains(widgetStyles, style) {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid style: must be shield, banner1, banner2, banner3 or banner4")
+	}
+
+	key := guildID + "/" + style
+	var image *widgetImage
+	if v, ok := cacheGet(CacheWidgets, key); ok {
+		image = v.(*widgetImage)
+	} else {
+		endpoint := discordgo.EndpointGuild(guildID) + "/widget.png"
+		data, err := s.RequestWithBucketID("GET", endpoint+"?style="+style, nil, endpoint)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).SendString("Failed to retrieve widget image: " + err.Error())
+		}
+		sum := sha1.Sum(data)
+		image = &widgetImage{data: data, etag: `"` + hex.EncodeToString(sum[:]) + `"`, fetched: time.Now()}
+		cacheSet(CacheWidgets, key, image, int64(len(data)), widgetCacheTTL)
+	}
+
+	age := time.Since(image.fetched)
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(int((widgetCacheTTL - age).Seconds())))
+	c.Set(fiber.HeaderETag, image.etag)
+	c.Set(fiber.HeaderLastModified, image.fetched.UTC().Format(http.TimeFormat))
+	if c.Get(fiber.HeaderIfNoneMatch) == image.etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Send(image.data)
+}